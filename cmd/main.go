@@ -18,7 +18,9 @@ import (
 	_ "github.com/cnxysoft/DDBOT-WSa/lsp/douyin"
 	_ "github.com/cnxysoft/DDBOT-WSa/lsp/douyu"
 	_ "github.com/cnxysoft/DDBOT-WSa/lsp/huya"
+	_ "github.com/cnxysoft/DDBOT-WSa/lsp/kick"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/permission"
+	_ "github.com/cnxysoft/DDBOT-WSa/lsp/twitch"
 	_ "github.com/cnxysoft/DDBOT-WSa/lsp/twitter"
 	_ "github.com/cnxysoft/DDBOT-WSa/lsp/weibo"
 	_ "github.com/cnxysoft/DDBOT-WSa/lsp/youtube"
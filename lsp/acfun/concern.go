@@ -9,7 +9,6 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
-	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/tidwall/buntdb"
 	"strconv"
 	"strings"
@@ -54,16 +53,16 @@ func (c *Concern) Stop() {
 }
 
 func (c *Concern) notifyGenerator() concern.NotifyGeneratorFunc {
-	return func(groupCode int64, ievent concern.Event) (result []concern.Notify) {
+	return func(target mmsg.Target, ievent concern.Event) (result []concern.Notify) {
 		log := ievent.Logger()
 		switch event := ievent.(type) {
 		case *LiveInfo:
-			notify := NewConcernLiveNotify(groupCode, event)
+			notify := NewConcernLiveNotify(target, event)
 			result = append(result, notify)
 			if event.Living() {
-				log.WithFields(localutils.GroupLogFields(groupCode)).Trace("living notify")
+				log.WithFields(mmsg.TargetLogFields(target)).Trace("living notify")
 			} else {
-				log.WithFields(localutils.GroupLogFields(groupCode)).Trace("noliving notify")
+				log.WithFields(mmsg.TargetLogFields(target)).Trace("noliving notify")
 			}
 		default:
 			log.Errorf("unknown concern_type %v", ievent.Type().String())
@@ -92,7 +91,7 @@ func (c *Concern) fresh() concern.FreshFunc {
 			err := func() error {
 				defer func() { logger.WithField("cost", time.Now().Sub(start)).Tracef("watchCore live fresh done") }()
 
-				_, ids, types, err := c.StateManager.ListConcernState(func(groupCode int64, id interface{}, p concern_type.Type) bool {
+				_, ids, types, err := c.StateManager.ListConcernState(func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
 					return p.ContainAny(Live)
 				})
 				if err != nil {
@@ -193,12 +192,12 @@ func (c *Concern) fresh() concern.FreshFunc {
 	}
 }
 
-func (c *Concern) Add(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+func (c *Concern) Add(ctx mmsg.IMsgCtx, target mmsg.Target, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	var err error
 	var uid = id.(int64)
-	log := logger.WithFields(localutils.GroupLogFields(groupCode)).WithField("id", id)
+	log := logger.WithFields(mmsg.TargetLogFields(target)).WithField("id", id)
 
-	err = c.StateManager.CheckGroupConcern(groupCode, id, ctype)
+	err = c.StateManager.CheckGroupConcern(target, id, ctype)
 	if err != nil {
 		return nil, err
 	}
@@ -209,7 +208,7 @@ func (c *Concern) Add(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctype c
 		log.Errorf("FindOrLoadUserInfo error %v", err)
 		return nil, fmt.Errorf("查询用户信息失败 %v - %v", id, err)
 	}
-	_, err = c.StateManager.AddGroupConcern(groupCode, id, ctype)
+	_, err = c.StateManager.AddGroupConcern(target, id, ctype)
 	if err != nil {
 		return nil, err
 	}
@@ -218,12 +217,11 @@ func (c *Concern) Add(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctype c
 		log.Errorf("SetUidFirstTimestampIfNotExist failed %v", err)
 	}
 	if ctype.ContainAny(Live) {
-		// 其他群关注了同一uid，并且推送过Living，那么给新watch的群也推一份
+		// 其他群关注了同一uid，并且推送过Living，那么给新watch的群或者私聊也推一份
 		if liveInfo != nil && liveInfo.Living() {
-			if ctx.GetTarget().TargetType().IsGroup() {
-				defer c.GroupWatchNotify(groupCode, uid)
-			}
-			if ctx.GetTarget().TargetType().IsPrivate() {
+			if mmsg.TargetEqual(ctx.GetTarget(), target) {
+				defer c.GroupWatchNotify(target, uid)
+			} else {
 				defer ctx.Send(mmsg.NewText("检测到该用户正在直播，但由于您目前处于私聊模式，" +
 					"因此不会在群内推送本次直播，将在该用户下次直播时推送"))
 			}
@@ -232,14 +230,14 @@ func (c *Concern) Add(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctype c
 	return concern.NewIdentity(userInfo.Uid, userInfo.GetName()), nil
 }
 
-func (c *Concern) Remove(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+func (c *Concern) Remove(ctx mmsg.IMsgCtx, target mmsg.Target, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	mid := id.(int64)
 	var identityInfo concern.IdentityInfo
 	var allCtype concern_type.Type
 	err := c.StateManager.RWCoverTx(func(tx *buntdb.Tx) error {
 		var err error
 		identityInfo, _ = c.Get(mid)
-		_, err = c.StateManager.RemoveGroupConcern(groupCode, mid, ctype)
+		_, err = c.StateManager.RemoveGroupConcern(target, mid, ctype)
 		if err != nil {
 			return err
 		}
@@ -276,17 +274,10 @@ func (c *Concern) GetStateManager() concern.IStateManager {
 
 func (c *Concern) FindUserInfo(uid int64, load bool) (*UserInfo, error) {
 	if load {
-		resp, err := LivePage(uid)
+		userInfo, err := loadUserInfo(uid)
 		if err != nil {
 			return nil, err
 		}
-		userInfo := &UserInfo{
-			Uid:      uid,
-			Name:     resp.GetLiveInfo().GetUser().GetName(),
-			Followed: int(resp.GetLiveInfo().GetUser().GetFanCountValue()),
-			UserImg:  resp.GetLiveInfo().GetUser().GetHeadUrl(),
-			LiveUrl:  LiveUrl(uid),
-		}
 		err = c.AddUserInfo(userInfo)
 		if err != nil {
 			return nil, err
@@ -295,6 +286,21 @@ func (c *Concern) FindUserInfo(uid int64, load bool) (*UserInfo, error) {
 	return c.StateManager.GetUserInfo(uid)
 }
 
+// loadUserInfo 从直播间页面获取用户信息，不会保存
+func loadUserInfo(uid int64) (*UserInfo, error) {
+	resp, err := LivePage(uid)
+	if err != nil {
+		return nil, err
+	}
+	return &UserInfo{
+		Uid:      uid,
+		Name:     resp.GetLiveInfo().GetUser().GetName(),
+		Followed: int(resp.GetLiveInfo().GetUser().GetFanCountValue()),
+		UserImg:  resp.GetLiveInfo().GetUser().GetHeadUrl(),
+		LiveUrl:  LiveUrl(uid),
+	}, nil
+}
+
 func (c *Concern) FindOrLoadUserInfo(uid int64) (*UserInfo, error) {
 	userInfo, _ := c.FindUserInfo(uid, false)
 	if userInfo == nil {
@@ -303,11 +309,11 @@ func (c *Concern) FindOrLoadUserInfo(uid int64) (*UserInfo, error) {
 	return userInfo, nil
 }
 
-func (c *Concern) GroupWatchNotify(groupCode, mid int64) {
+func (c *Concern) GroupWatchNotify(target mmsg.Target, mid int64) {
 	liveInfo, _ := c.GetLiveInfo(mid)
 	if liveInfo.Living() {
 		liveInfo.liveStatusChanged = true
-		c.notify <- NewConcernLiveNotify(groupCode, liveInfo)
+		c.notify <- NewConcernLiveNotify(target, liveInfo)
 	}
 }
 
@@ -4,9 +4,7 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
-	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/sirupsen/logrus"
-	"sync"
 )
 
 type UserInfo struct {
@@ -33,8 +31,7 @@ type LiveInfo struct {
 	StartTs  int64  `json:"start_ts"`
 	IsLiving bool   `json:"living"`
 
-	once              sync.Once
-	msgCache          *mmsg.MSG
+	msgCache          template.MsgCache
 	liveStatusChanged bool
 	liveTitleChanged  bool
 }
@@ -51,6 +48,19 @@ func (l *LiveInfo) LiveStatusChanged() bool {
 	return l.liveStatusChanged
 }
 
+func (l *LiveInfo) AnchorName() string {
+	return l.Name
+}
+
+func (l *LiveInfo) RoomTitle() string {
+	return l.Title
+}
+
+// Popularity acfun目前没有获取人气，总是返回0
+func (l *LiveInfo) Popularity() int64 {
+	return 0
+}
+
 func (l *LiveInfo) TitleChanged() bool {
 	return l.liveTitleChanged
 }
@@ -73,8 +83,8 @@ func (l *LiveInfo) Logger() *logrus.Entry {
 	})
 }
 
-func (l *LiveInfo) GetMSG() *mmsg.MSG {
-	l.once.Do(func() {
+func (l *LiveInfo) GetMSG(locale string) *mmsg.MSG {
+	return l.msgCache.Get(locale, func() *mmsg.MSG {
 		var data = map[string]interface{}{
 			"title":  l.Title,
 			"name":   l.Name,
@@ -82,39 +92,37 @@ func (l *LiveInfo) GetMSG() *mmsg.MSG {
 			"cover":  l.Cover,
 			"living": l.Living(),
 		}
-		var err error
-		l.msgCache, err = template.LoadAndExec("notify.group.acfun.live.tmpl", data)
+		msg, err := template.ExecNotify(locale, "notify.group.acfun.live.tmpl", data)
 		if err != nil {
 			logger.Errorf("acfun: LiveInfo LoadAndExec error %v", err)
 		}
-		return
+		return msg
 	})
-	return l.msgCache
 }
 
 type ConcernLiveNotify struct {
-	GroupCode int64
+	Target mmsg.Target
 	*LiveInfo
 }
 
-func (notify *ConcernLiveNotify) GetGroupCode() int64 {
-	return notify.GroupCode
+func (notify *ConcernLiveNotify) GetTarget() mmsg.Target {
+	return notify.Target
 }
 
 func (notify *ConcernLiveNotify) ToMessage() (m *mmsg.MSG) {
-	return notify.LiveInfo.GetMSG()
+	return notify.LiveInfo.GetMSG(template.NotifyLocale(notify.Target))
 }
 
 func (notify *ConcernLiveNotify) Logger() *logrus.Entry {
 	if notify == nil {
 		return logger
 	}
-	return notify.LiveInfo.Logger().WithFields(localutils.GroupLogFields(notify.GroupCode))
+	return notify.LiveInfo.Logger().WithFields(mmsg.TargetLogFields(notify.Target))
 }
 
-func NewConcernLiveNotify(groupCode int64, info *LiveInfo) *ConcernLiveNotify {
+func NewConcernLiveNotify(target mmsg.Target, info *LiveInfo) *ConcernLiveNotify {
 	return &ConcernLiveNotify{
-		GroupCode: groupCode,
-		LiveInfo:  info,
+		Target:   target,
+		LiveInfo: info,
 	}
 }
@@ -0,0 +1,48 @@
+package acfun
+
+import (
+	"errors"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+)
+
+// Preview 与刷新相同，从正在直播的列表中查找，并当作刚刚发生变化处理
+// 不在列表中时当作没有直播
+func (c *Concern) Preview(target mmsg.Target, id interface{}, ctype concern_type.Type, limit int) ([]concern.Notify, error) {
+	if ctype != Live {
+		return nil, errors.New("该类型不支持预览")
+	}
+	uid := id.(int64)
+	liveInfos, err := c.freshLiveInfo()
+	if err != nil {
+		return nil, err
+	}
+	var liveInfo *LiveInfo
+	for _, info := range liveInfos {
+		if info.Uid == uid {
+			liveInfo = info
+			break
+		}
+	}
+	if liveInfo == nil {
+		userInfo, err := c.GetUserInfo(uid)
+		if err != nil {
+			// 不能使用 FindOrLoadUserInfo，预览没有订阅的用户时不应该保存用户信息
+			userInfo, err = loadUserInfo(uid)
+			if err != nil {
+				return nil, err
+			}
+		}
+		liveInfo = &LiveInfo{UserInfo: *userInfo}
+		if oldInfo, _ := c.GetLiveInfo(uid); oldInfo != nil {
+			liveInfo.LiveId = oldInfo.LiveId
+			liveInfo.Title = oldInfo.Title
+			liveInfo.Cover = oldInfo.Cover
+			liveInfo.StartTs = oldInfo.StartTs
+		}
+	}
+	liveInfo.liveStatusChanged = true
+	return []concern.Notify{NewConcernLiveNotify(target, liveInfo)}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/tidwall/buntdb"
 )
 
@@ -12,8 +13,8 @@ type StateManager struct {
 	extraKey
 }
 
-func (s *StateManager) GetGroupConcernConfig(groupCode int64, id interface{}) (concernConfig concern.IConfig) {
-	return NewGroupConcernConfig(s.StateManager.GetGroupConcernConfig(groupCode, id))
+func (s *StateManager) GetGroupConcernConfig(target mmsg.Target, id interface{}) (concernConfig concern.IConfig) {
+	return NewGroupConcernConfig(s.StateManager.GetGroupConcernConfig(target, id))
 }
 
 func NewStateManager(notify chan<- concern.Notify) *StateManager {
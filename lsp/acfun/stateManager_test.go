@@ -4,6 +4,7 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/internal/test"
 	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/stretchr/testify/assert"
 	"github.com/tidwall/buntdb"
 	"testing"
@@ -12,7 +13,7 @@ import (
 func initStateManager(t *testing.T, notifyChan chan<- concern.Notify) *StateManager {
 	sm := NewStateManager(notifyChan)
 	assert.NotNil(t, sm)
-	sm.FreshIndex(test.G1, test.G2)
+	sm.FreshIndex(mmsg.NewGroupTarget(test.G1), mmsg.NewGroupTarget(test.G2))
 	return sm
 }
 
@@ -26,7 +27,7 @@ func TestStateManager(t *testing.T) {
 	assert.NotNil(t, sm)
 	defer sm.Stop()
 
-	assert.NotNil(t, sm.GetGroupConcernConfig(test.G1, test.UID1))
+	assert.NotNil(t, sm.GetGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1))
 
 	userInfo := UserInfo{
 		Uid:  test.UID1,
@@ -0,0 +1,179 @@
+package alias
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/tidwall/buntdb"
+)
+
+// maxAliasLength 订阅别名的最大长度
+const maxAliasLength = 32
+
+var (
+	ErrInvalidAlias  = fmt.Errorf("别名不能为空，不能包含空白字符以及 : * ?，并且不能超过%v个字", maxAliasLength)
+	ErrAliasConflict = errors.New("别名已被其他订阅使用")
+	ErrAliasNotFound = errors.New("别名不存在")
+)
+
+// CheckAlias 别名会作为key的一部分，所以不允许包含 : * ? 以及空白字符
+func CheckAlias(alias string) error {
+	if len(alias) == 0 || utf8.RuneCountInString(alias) > maxAliasLength {
+		return ErrInvalidAlias
+	}
+	if strings.ContainsAny(alias, ":*?") || strings.IndexFunc(alias, unicode.IsSpace) >= 0 {
+		return ErrInvalidAlias
+	}
+	return nil
+}
+
+// Normalize 把订阅的名字转换为可以使用的别名，去掉不允许的字符并截断过长的部分，
+// 转换后为空时返回空字符串
+func Normalize(name string) string {
+	var sb strings.Builder
+	var count int
+	for _, r := range name {
+		if unicode.IsSpace(r) || strings.ContainsRune(":*?", r) {
+			continue
+		}
+		if count >= maxAliasLength {
+			break
+		}
+		sb.WriteRune(r)
+		count++
+	}
+	return sb.String()
+}
+
+func concernId(id interface{}) string {
+	return fmt.Sprint(id)
+}
+
+func set(tx *buntdb.Tx, groupCode int64, site string, id string, alias string) error {
+	aliasKey := localdb.GroupConcernAliasKey(groupCode, site, alias)
+	prevId, err := tx.Get(aliasKey)
+	if err == nil && prevId != id {
+		return ErrAliasConflict
+	} else if err != nil && err != buntdb.ErrNotFound {
+		return err
+	}
+	if err = remove(tx, groupCode, site, id); err != nil {
+		return err
+	}
+	if _, _, err = tx.Set(aliasKey, id, nil); err != nil {
+		return err
+	}
+	_, _, err = tx.Set(localdb.GroupConcernAliasIdKey(groupCode, site, id), alias, nil)
+	return err
+}
+
+func remove(tx *buntdb.Tx, groupCode int64, site string, id string) error {
+	alias, err := tx.Delete(localdb.GroupConcernAliasIdKey(groupCode, site, id))
+	if err == buntdb.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if _, err = tx.Delete(localdb.GroupConcernAliasKey(groupCode, site, alias)); err != nil && err != buntdb.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// Set 设置一个订阅在群内的别名，会替换这个订阅原来的别名，
+// 同一个网站下别名已经被其他订阅使用时返回 ErrAliasConflict
+func Set(groupCode int64, site string, id interface{}, alias string) error {
+	if err := CheckAlias(alias); err != nil {
+		return err
+	}
+	return localdb.RWCoverTx(func(tx *buntdb.Tx) error {
+		return set(tx, groupCode, site, concernId(id), alias)
+	})
+}
+
+// SetDefault 订阅还没有别名时，使用订阅的名字作为别名，返回订阅当前的别名，
+// 名字无法作为别名、与其他订阅的别名冲突或者 reserved 返回true（例如与其他订阅的id相同）时不设置
+func SetDefault(groupCode int64, site string, id interface{}, name string, reserved func(alias string) bool) (string, error) {
+	var result string
+	sid := concernId(id)
+	defaultAlias := Normalize(name)
+	if len(defaultAlias) > 0 && reserved != nil && reserved(defaultAlias) {
+		defaultAlias = ""
+	}
+	err := localdb.RWCoverTx(func(tx *buntdb.Tx) error {
+		alias, err := tx.Get(localdb.GroupConcernAliasIdKey(groupCode, site, sid))
+		if err == nil {
+			result = alias
+			return nil
+		} else if err != buntdb.ErrNotFound {
+			return err
+		}
+		alias = defaultAlias
+		if len(alias) == 0 {
+			return nil
+		}
+		if err = set(tx, groupCode, site, sid, alias); err != nil {
+			return err
+		}
+		result = alias
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// Resolve 通过别名查询订阅的id
+func Resolve(groupCode int64, site string, alias string) (string, error) {
+	id, err := localdb.Get(localdb.GroupConcernAliasKey(groupCode, site, alias))
+	if err == buntdb.ErrNotFound {
+		return "", ErrAliasNotFound
+	}
+	return id, err
+}
+
+// Get 查询订阅在群内的别名
+func Get(groupCode int64, site string, id interface{}) (string, error) {
+	alias, err := localdb.Get(localdb.GroupConcernAliasIdKey(groupCode, site, concernId(id)))
+	if err == buntdb.ErrNotFound {
+		return "", ErrAliasNotFound
+	}
+	return alias, err
+}
+
+// Remove 删除订阅在群内的别名，没有别名时什么也不做
+func Remove(groupCode int64, site string, id interface{}) error {
+	return localdb.RWCoverTx(func(tx *buntdb.Tx) error {
+		return remove(tx, groupCode, site, concernId(id))
+	})
+}
+
+// RemoveAllByGroupCode 删除一个群内的所有别名
+func RemoveAllByGroupCode(groupCode int64) error {
+	return localdb.RWCoverTx(func(tx *buntdb.Tx) error {
+		var keys []string
+		for _, pattern := range []string{
+			localdb.GroupConcernAliasKey(groupCode, "*"),
+			localdb.GroupConcernAliasIdKey(groupCode, "*"),
+		} {
+			err := tx.AscendKeys(pattern, func(key, value string) bool {
+				keys = append(keys, key)
+				return true
+			})
+			if err != nil {
+				return err
+			}
+		}
+		for _, key := range keys {
+			if _, err := tx.Delete(key); err != nil && err != buntdb.ErrNotFound {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,104 @@
+package alias
+
+import (
+	"testing"
+
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckAlias(t *testing.T) {
+	assert.Nil(t, CheckAlias("小明"))
+	assert.Nil(t, CheckAlias("xiao-ming_1"))
+	for _, alias := range []string{"", "a b", "a:b", "a*", "a?", "一二三四五六七八九十一二三四五六七八九十一二三四五六七八九十一二三"} {
+		assert.Equal(t, ErrInvalidAlias, CheckAlias(alias), alias)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "小明", Normalize("小明"))
+	assert.Equal(t, "小明Official", Normalize(" 小明 Official "))
+	assert.Equal(t, "ab", Normalize("a:*?b"))
+	assert.Equal(t, "", Normalize(" \t"))
+	assert.Equal(t, "一二三四五六七八九十一二三四五六七八九十一二三四五六七八九十一二",
+		Normalize("一二三四五六七八九十一二三四五六七八九十一二三四五六七八九十一二三"))
+}
+
+func TestSet(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	assert.Equal(t, ErrInvalidAlias, Set(test.G1, test.Site1, test.UID1, "a b"))
+
+	assert.Nil(t, Set(test.G1, test.Site1, test.UID1, "alice"))
+	// 重复设置相同的别名
+	assert.Nil(t, Set(test.G1, test.Site1, test.UID1, "alice"))
+	assert.Equal(t, ErrAliasConflict, Set(test.G1, test.Site1, test.UID2, "alice"))
+	// 不同网站和不同群的别名互不影响
+	assert.Nil(t, Set(test.G1, test.Site2, test.NAME1, "alice"))
+	assert.Nil(t, Set(test.G2, test.Site1, test.UID2, "alice"))
+
+	id, err := Resolve(test.G1, test.Site1, "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, "777", id)
+	id, err = Resolve(test.G1, test.Site2, "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, test.NAME1, id)
+	_, err = Resolve(test.G1, test.Site1, "bob")
+	assert.Equal(t, ErrAliasNotFound, err)
+
+	// 修改别名后旧的别名不再可用
+	assert.Nil(t, Set(test.G1, test.Site1, test.UID1, "bob"))
+	_, err = Resolve(test.G1, test.Site1, "alice")
+	assert.Equal(t, ErrAliasNotFound, err)
+	a, err := Get(test.G1, test.Site1, test.UID1)
+	assert.Nil(t, err)
+	assert.Equal(t, "bob", a)
+
+	assert.Nil(t, Remove(test.G1, test.Site1, test.UID1))
+	assert.Nil(t, Remove(test.G1, test.Site1, test.UID1))
+	_, err = Get(test.G1, test.Site1, test.UID1)
+	assert.Equal(t, ErrAliasNotFound, err)
+	_, err = Resolve(test.G1, test.Site1, "bob")
+	assert.Equal(t, ErrAliasNotFound, err)
+
+	assert.Nil(t, RemoveAllByGroupCode(test.G1))
+	_, err = Resolve(test.G1, test.Site2, "alice")
+	assert.Equal(t, ErrAliasNotFound, err)
+	_, err = Resolve(test.G2, test.Site1, "alice")
+	assert.Nil(t, err)
+}
+
+func TestSetDefault(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	a, err := SetDefault(test.G1, test.Site1, test.UID1, "小 明", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "小明", a)
+
+	// 已经有别名时不会覆盖
+	assert.Nil(t, Set(test.G1, test.Site1, test.UID1, "alice"))
+	a, err = SetDefault(test.G1, test.Site1, test.UID1, "小明", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", a)
+
+	a, err = SetDefault(test.G1, test.Site1, test.UID2, "alice", nil)
+	assert.Equal(t, ErrAliasConflict, err)
+	assert.Empty(t, a)
+
+	a, err = SetDefault(test.G1, test.Site1, test.UID2, " ", nil)
+	assert.Nil(t, err)
+	assert.Empty(t, a)
+	_, err = Get(test.G1, test.Site1, test.UID2)
+	assert.Equal(t, ErrAliasNotFound, err)
+
+	// 与其他订阅的id相同时不设置
+	a, err = SetDefault(test.G1, test.Site1, test.UID2, "12345", func(alias string) bool {
+		return alias == "12345"
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, a)
+	_, err = Resolve(test.G1, test.Site1, "12345")
+	assert.Equal(t, ErrAliasNotFound, err)
+}
@@ -3,6 +3,7 @@ package bilibili
 import (
 	"io"
 	"net/http/cookiejar"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -29,9 +30,11 @@ type XSpaceAccInfoRequest struct {
 
 var cj atomic.Pointer[cookiejar.Jar]
 
+var bilibiliHomeUrl, _ = url.Parse("https://www.bilibili.com/")
+
 func refreshCookieJar() {
 	j, _ := cookiejar.New(nil)
-	err := requests.Get("https://www.bilibili.com/", nil, io.Discard,
+	err := requests.Get(bilibiliHomeUrl.String(), nil, io.Discard,
 		requests.WithCookieJar(j),
 		AddUAOption(),
 		requests.RequestAutoHostOption(),
@@ -81,7 +81,6 @@ func XWebInterfaceNav(login bool) (*WebInterfaceNavResponse, error) {
 		ed := time.Now()
 		logger.WithField("FuncName", utils.FuncName()).Tracef("cost %v", ed.Sub(st))
 	}()
-	path := BPath(PathXWebInterfaceNav)
 	var opts = []requests.Option{
 		requests.ProxyOption(proxy_pool.PreferNone),
 		requests.TimeoutOption(time.Second * 15),
@@ -91,6 +90,29 @@ func XWebInterfaceNav(login bool) (*WebInterfaceNavResponse, error) {
 	if login && getVerify() != nil {
 		opts = append(opts, getVerify().VerifyOpts...)
 	}
+	return xWebInterfaceNav(opts)
+}
+
+// XWebInterfaceNav 使用该账号查询登录信息
+func (a *Account) XWebInterfaceNav() (*WebInterfaceNavResponse, error) {
+	if a.IsMain() {
+		return XWebInterfaceNav(true)
+	}
+	if !a.IsVerifyGiven() {
+		return nil, ErrVerifyRequired
+	}
+	var opts = []requests.Option{
+		requests.ProxyOption(proxy_pool.PreferNone),
+		requests.TimeoutOption(time.Second * 15),
+		AddUAOption(),
+		delete412ProxyOption,
+	}
+	opts = append(opts, a.VerifyOption()...)
+	return xWebInterfaceNav(opts)
+}
+
+func xWebInterfaceNav(opts []requests.Option) (*WebInterfaceNavResponse, error) {
+	path := BPath(PathXWebInterfaceNav)
 	xwin := new(WebInterfaceNavResponse)
 	err := requests.Get(path, nil, xwin, opts...)
 	if err != nil {
@@ -0,0 +1,253 @@
+package bilibili
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/cfg"
+	"github.com/cnxysoft/DDBOT-WSa/requests"
+	"go.uber.org/atomic"
+)
+
+// MainAccountName 主账号，即 bilibili.SESSDATA / bilibili.account 配置的账号
+const MainAccountName = "main"
+
+// accountLimitCooldown 账号被风控或达到关注上限后暂停使用的时间
+const accountLimitCooldown = time.Minute * 30
+
+var (
+	ErrAccountExists   = errors.New("账号已存在")
+	ErrAccountNotFound = errors.New("账号不存在")
+)
+
+// Account 一个b站账号，配置了多个账号时，订阅会分摊到每个账号上，
+// 每个账号只关注并刷新自己负责的那一部分订阅
+type Account struct {
+	Name string
+
+	verify       *atomic.Pointer[VerifyInfo]
+	uid          *atomic.Int64
+	cookieStatus *atomic.Int32
+	limitedUntil atomic.Int64
+}
+
+var (
+	mainAccount = &Account{
+		Name:         MainAccountName,
+		verify:       &atomicVerifyInfo,
+		uid:          &accountUid,
+		cookieStatus: &lastCookieStatus,
+	}
+
+	accountMux   sync.RWMutex
+	extraAccount = make(map[string]*Account)
+
+	// accountChanged 账号增删或者被风控时通知 SyncSub 重新分配订阅
+	accountChanged = make(chan struct{}, 1)
+)
+
+func newAccount(name string, SESSDATA string, biliJct string) *Account {
+	a := &Account{
+		Name:         name,
+		verify:       new(atomic.Pointer[VerifyInfo]),
+		uid:          new(atomic.Int64),
+		cookieStatus: new(atomic.Int32),
+	}
+	a.verify.Store(&VerifyInfo{
+		SESSDATA:   SESSDATA,
+		BiliJct:    biliJct,
+		VerifyOpts: []requests.Option{requests.CookieOption("SESSDATA", SESSDATA), requests.CookieOption("bili_jct", biliJct)},
+	})
+	return a
+}
+
+func (a *Account) IsMain() bool {
+	return a == mainAccount
+}
+
+func (a *Account) VerifyOption() []requests.Option {
+	if a.IsMain() {
+		return GetVerifyOption()
+	}
+	info := a.verify.Load()
+	if info == nil {
+		return nil
+	}
+	return info.VerifyOpts
+}
+
+func (a *Account) BiliJct() string {
+	if a.IsMain() {
+		return GetVerifyBiliJct()
+	}
+	info := a.verify.Load()
+	if info == nil {
+		return ""
+	}
+	return info.BiliJct
+}
+
+func (a *Account) IsVerifyGiven() bool {
+	if a.IsMain() {
+		return IsVerifyGiven()
+	}
+	info := a.verify.Load()
+	return info != nil && len(info.VerifyOpts) > 0
+}
+
+func (a *Account) Uid() int64 {
+	return a.uid.Load()
+}
+
+// IsLimited 账号是否处于风控冷却中
+func (a *Account) IsLimited() bool {
+	return time.Now().Unix() < a.limitedUntil.Load()
+}
+
+// Available 账号是否可以用来关注和刷新
+func (a *Account) Available() bool {
+	return a.IsVerifyGiven() && !a.IsLimited()
+}
+
+// MarkLimited 标记账号被风控，冷却期间该账号负责的订阅会被分配给其他账号
+func (a *Account) MarkLimited() {
+	if a.IsLimited() {
+		return
+	}
+	a.limitedUntil.Store(time.Now().Add(accountLimitCooldown).Unix())
+	logger.WithField("account", a.Name).Warnf("b站账号被限制，将在%v内暂停使用该账号", accountLimitCooldown)
+	notifyAccountChanged()
+}
+
+// invalidate cookie失效时清除账号信息，只用于额外配置的账号
+func (a *Account) invalidate() {
+	if a.IsMain() {
+		return
+	}
+	a.verify.Store(new(VerifyInfo))
+	a.uid.Store(0)
+	logger.WithField("account", a.Name).Errorf("b站账号cookie已失效，请更新配置")
+	notifyAccountChanged()
+}
+
+func (a *Account) FreshSelfInfo() {
+	if a.IsMain() {
+		FreshSelfInfo()
+		return
+	}
+	navResp, err := a.XWebInterfaceNav()
+	if err != nil {
+		logger.WithField("account", a.Name).Errorf("获取个人信息失败 - %v", err)
+	} else if navResp.GetCode() != 0 || !navResp.GetData().GetIsLogin() {
+		logger.WithField("account", a.Name).Errorf("获取个人信息失败 - %v %v", navResp.GetCode(), navResp.GetMessage())
+	} else {
+		logger.WithField("account", a.Name).Infof("B站账号启动成功：UID:%v %v", navResp.GetData().GetMid(), navResp.GetData().GetUname())
+		a.uid.Store(navResp.GetData().GetMid())
+		return
+	}
+	a.uid.Store(0)
+}
+
+// isLimitCode 返回的code是否表示账号被风控或者达到关注上限
+func isLimitCode(code int32) bool {
+	switch code {
+	case -412, 412, -352, -509, 22009:
+		return true
+	}
+	return false
+}
+
+func notifyAccountChanged() {
+	select {
+	case accountChanged <- struct{}{}:
+	default:
+	}
+}
+
+// Accounts 返回所有账号，主账号总是第一个
+func Accounts() []*Account {
+	accountMux.RLock()
+	defer accountMux.RUnlock()
+	var result = []*Account{mainAccount}
+	for _, a := range extraAccount {
+		result = append(result, a)
+	}
+	sort.Slice(result[1:], func(i, j int) bool {
+		return result[i+1].Name < result[j+1].Name
+	})
+	return result
+}
+
+// GetAccount 按名字查找账号，找不到时返回nil
+func GetAccount(name string) *Account {
+	if name == MainAccountName {
+		return mainAccount
+	}
+	accountMux.RLock()
+	defer accountMux.RUnlock()
+	return extraAccount[name]
+}
+
+// AddAccount 添加一个额外的账号
+func AddAccount(name string, SESSDATA string, biliJct string) (*Account, error) {
+	if len(name) == 0 || len(SESSDATA) == 0 || len(biliJct) == 0 {
+		return nil, errors.New("账号信息不完整")
+	}
+	if name == MainAccountName {
+		return nil, ErrAccountExists
+	}
+	accountMux.Lock()
+	if _, found := extraAccount[name]; found {
+		accountMux.Unlock()
+		return nil, ErrAccountExists
+	}
+	a := newAccount(name, SESSDATA, biliJct)
+	extraAccount[name] = a
+	accountMux.Unlock()
+	a.FreshSelfInfo()
+	notifyAccountChanged()
+	return a, nil
+}
+
+// RemoveAccount 移除一个额外的账号，该账号负责的订阅会在下次 SyncSub 时分配给其他账号
+func RemoveAccount(name string) error {
+	accountMux.Lock()
+	defer accountMux.Unlock()
+	if _, found := extraAccount[name]; !found {
+		return ErrAccountNotFound
+	}
+	delete(extraAccount, name)
+	notifyAccountChanged()
+	return nil
+}
+
+// ReloadAccounts 按配置重新加载额外的账号，cookie有变化的账号会被替换
+func ReloadAccounts() {
+	var configured = make(map[string]*cfg.BilibiliAccount)
+	for _, acc := range cfg.GetBilibiliAccounts() {
+		if acc == nil || len(acc.Name) == 0 || acc.Name == MainAccountName {
+			continue
+		}
+		configured[acc.Name] = acc
+	}
+	for _, a := range Accounts() {
+		if a.IsMain() {
+			continue
+		}
+		acc, found := configured[a.Name]
+		if found {
+			if info := a.verify.Load(); info != nil && info.SESSDATA == acc.SESSDATA && info.BiliJct == acc.BiliJct {
+				delete(configured, a.Name)
+				continue
+			}
+		}
+		RemoveAccount(a.Name)
+	}
+	for _, acc := range configured {
+		if _, err := AddAccount(acc.Name, acc.SESSDATA, acc.BiliJct); err != nil {
+			logger.WithField("account", acc.Name).Errorf("添加b站账号失败 - %v", err)
+		}
+	}
+}
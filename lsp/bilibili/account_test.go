@@ -0,0 +1,137 @@
+package bilibili
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func countShard(plan map[int64]string) map[string]int {
+	var result = make(map[string]int)
+	for _, name := range plan {
+		result[name]++
+	}
+	return result
+}
+
+func TestPlanAccountShard(t *testing.T) {
+	var mids []int64
+	for i := int64(1); i <= 100; i++ {
+		mids = append(mids, i)
+	}
+
+	// 没有记录的订阅属于主账号
+	plan := planAccountShard(mids, nil, []string{MainAccountName}, 10)
+	assert.Len(t, plan, 100)
+	assert.Equal(t, 100, countShard(plan)[MainAccountName])
+
+	// 新增账号后重新平衡
+	plan = planAccountShard(mids, nil, []string{MainAccountName, "a"}, 10)
+	cnt := countShard(plan)
+	assert.LessOrEqual(t, cnt[MainAccountName]-cnt["a"], 10)
+	assert.Equal(t, 100, cnt[MainAccountName]+cnt["a"])
+
+	// 差距没有超过阈值时不移动
+	var current = make(map[int64]string)
+	for _, mid := range mids {
+		if mid <= 55 {
+			current[mid] = MainAccountName
+		} else {
+			current[mid] = "a"
+		}
+	}
+	plan = planAccountShard(mids, current, []string{MainAccountName, "a"}, 10)
+	assert.EqualValues(t, current, plan)
+
+	// 账号不可用时，负责的订阅分配给其他账号
+	plan = planAccountShard(mids, current, []string{MainAccountName, "b"}, 10)
+	cnt = countShard(plan)
+	assert.Zero(t, cnt["a"])
+	assert.LessOrEqual(t, cnt[MainAccountName]-cnt["b"], 10)
+	for mid := int64(1); mid <= 55; mid++ {
+		// 主账号负责的订阅不需要移动
+		assert.Equal(t, MainAccountName, plan[mid])
+	}
+
+	assert.Empty(t, planAccountShard(mids, current, nil, 10))
+}
+
+func TestAccount(t *testing.T) {
+	_, err := AddAccount(MainAccountName, "a", "b")
+	assert.Equal(t, ErrAccountExists, err)
+	_, err = AddAccount("test", "", "")
+	assert.NotNil(t, err)
+
+	acc := newAccount("test", "SESSDATA", "bili_jct")
+	assert.False(t, acc.IsMain())
+	assert.True(t, acc.IsVerifyGiven())
+	assert.Equal(t, "bili_jct", acc.BiliJct())
+	assert.Len(t, acc.VerifyOption(), 2)
+	assert.True(t, acc.Available())
+
+	acc.limitedUntil.Store(time.Now().Add(time.Minute).Unix())
+	assert.True(t, acc.IsLimited())
+	assert.False(t, acc.Available())
+
+	acc.invalidate()
+	assert.False(t, acc.IsVerifyGiven())
+
+	assert.True(t, mainAccount.IsMain())
+	assert.Equal(t, mainAccount, GetAccount(MainAccountName))
+	assert.Nil(t, GetAccount("not_exist"))
+	assert.Equal(t, ErrAccountNotFound, RemoveAccount("not_exist"))
+	assert.Equal(t, mainAccount, Accounts()[0])
+
+	assert.True(t, isLimitCode(-412))
+	assert.False(t, isLimitCode(0))
+}
+
+func TestStateManager_AccountShard(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	c := initConcern(t)
+
+	assert.Equal(t, MainAccountName, c.GetAccountShard(test.UID1))
+	assert.Nil(t, c.SetAccountShard(test.UID1, "a"))
+	assert.Nil(t, c.SetAccountShard(test.UID2, "b"))
+	assert.Equal(t, "a", c.GetAccountShard(test.UID1))
+
+	shards, err := c.ListAccountShard()
+	assert.Nil(t, err)
+	assert.EqualValues(t, map[int64]string{test.UID1: "a", test.UID2: "b"}, shards)
+
+	// 账号不存在时使用主账号
+	assert.Equal(t, mainAccount, c.accountOf(test.UID1))
+
+	assert.Nil(t, c.DeleteAccountShard(test.UID1))
+	assert.Equal(t, MainAccountName, c.GetAccountShard(test.UID1))
+}
+
+func TestConcern_PickAccount(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	c := initConcern(t)
+
+	// 主账号没有配置cookie时不可用
+	assert.False(t, mainAccount.Available())
+	_, err := c.pickAccount()
+	assert.Equal(t, ErrVerifyRequired, err)
+
+	extra := newAccount("extra", "SESSDATA", "bili_jct")
+	accountMux.Lock()
+	extraAccount[extra.Name] = extra
+	accountMux.Unlock()
+	defer func() {
+		accountMux.Lock()
+		delete(extraAccount, extra.Name)
+		accountMux.Unlock()
+	}()
+
+	acc, err := c.pickAccount()
+	assert.Nil(t, err)
+	assert.Equal(t, extra, acc)
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/requests"
 	jsoniter "github.com/json-iterator/go"
 	"go.uber.org/atomic"
-	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -48,6 +47,13 @@ var BasePath = map[string]string{
 	PathGetPlayTogetherUserAnchorInfoV2: BaseLiveHost,
 	PathRoomInfo:                        BaseLiveHost,
 	PathWebAreaList:                     BaseLiveHost,
+	PathGetDanmuInfo:                    BaseLiveHost,
+	PathPgcViewWebSeason:                BaseHost,
+	PathPgcReviewUser:                   BaseHost,
+	PathFavSeasonList:                   BaseHost,
+	PathSeasonsArchivesList:             BaseHost,
+	PathSeriesSeries:                    BaseHost,
+	PathSeriesArchives:                  BaseHost,
 }
 
 type VerifyInfo struct {
@@ -91,7 +97,7 @@ func Init() {
 	var (
 		SESSDATA   = config.GlobalConfig.GetString("bilibili.SESSDATA")
 		biliJct    = config.GlobalConfig.GetString("bilibili.bili_jct")
-		useQRLogin = config.GlobalConfig.GetBool("bilibili.QRLogin")
+		useQRLogin = IsQRLoginEnabled()
 	)
 	if len(SESSDATA) != 0 && len(biliJct) != 0 {
 		SetVerify(SESSDATA, biliJct)
@@ -122,23 +128,23 @@ func Init() {
 						logger.Errorf("解析Cookies失败 - %v", err)
 						continue
 					}
-					config.GlobalConfig.Set("bilibili.SESSDATA", cookies.SESSDATA)
-					config.GlobalConfig.Set("bilibili.bili_jct", cookies.BILI_JCT)
-					if err := config.GlobalConfig.WriteConfig(); err != nil {
-						logger.Errorf("保存配置文件失败 - %v", err)
-						continue
+					if err := ApplyCookies(cookies); err != nil {
+						logger.Errorf("B站扫码登陆失败 - %v", err)
+					} else {
+						logger.Info("B站扫码登陆成功")
 					}
-					SetVerify(cookies.SESSDATA, cookies.BILI_JCT)
-					FreshSelfInfo()
-					logger.Info("B站扫码登陆成功，请重启DDBOT-WSa")
-					logger.Info("正在退出...")
-					time.Sleep(time.Second * 5)
-					os.Exit(0)
+					return
 				}
 			}
 		}()
 	}
 	SetAccount(config.GlobalConfig.GetString("bilibili.account"), config.GlobalConfig.GetString("bilibili.password"))
+	ReloadAccounts()
+}
+
+// IsQRLoginEnabled 是否开启了启动时扫码登陆
+func IsQRLoginEnabled() bool {
+	return config.GlobalConfig.GetBool("bilibili.QRLogin")
 }
 
 func BPath(path string) string {
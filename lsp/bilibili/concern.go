@@ -26,19 +26,23 @@ var logger = utils.GetModuleLogger("bilibili-concern")
 const (
 	Live concern_type.Type = "live"
 	News concern_type.Type = "news"
+	// Danmaku 直播间的醒目留言、大航海和礼物，只在直播时连接弹幕服务器
+	Danmaku concern_type.Type = "danmaku"
 )
 
 var online bool
 
 type Concern struct {
 	*StateManager
-	attentionListExpirable *expirable.Expirable
-	unsafeStart            atomic.Bool
-	notify                 chan<- concern.Notify
-	stop                   chan interface{}
-	wg                     sync.WaitGroup
-	cacheStartTs           int64
-	AreaData               *AreaData
+	attentionList map[string]*expirable.Expirable
+	attentionMux  sync.Mutex
+	unsafeStart   atomic.Bool
+	notify        chan<- concern.Notify
+	stop          chan interface{}
+	wg            sync.WaitGroup
+	cacheStartTs  int64
+	AreaData      *AreaData
+	danmaku       *danmakuManager
 }
 
 func (c *Concern) Site() string {
@@ -46,7 +50,7 @@ func (c *Concern) Site() string {
 }
 
 func (c *Concern) Types() []concern_type.Type {
-	return []concern_type.Type{Live, News}
+	return []concern_type.Type{Live, News, Danmaku}
 }
 
 func (c *Concern) ParseId(s string) (interface{}, error) {
@@ -59,26 +63,12 @@ func (c *Concern) GetStateManager() concern.IStateManager {
 
 func NewConcern(notify chan<- concern.Notify) *Concern {
 	c := &Concern{
-		notify:       notify,
-		stop:         make(chan interface{}),
-		cacheStartTs: time.Now().Unix(),
-		attentionListExpirable: expirable.NewExpirable(time.Second*20, func() interface{} {
-			var m = make(map[int64]interface{})
-			resp, err := GetAttentionList()
-			if err != nil {
-				logger.Errorf("GetAttentionList error %v", err)
-				return m
-			}
-			if resp.GetCode() != 0 {
-				logger.Errorf("GetAttentionList error %v - %v", resp.GetCode(), resp.GetMessage())
-				return m
-			}
-			for _, id := range resp.GetData().GetList() {
-				m[id] = struct{}{}
-			}
-			return m
-		}),
+		notify:        notify,
+		stop:          make(chan interface{}),
+		cacheStartTs:  time.Now().Unix(),
+		attentionList: make(map[string]*expirable.Expirable),
 	}
+	c.danmaku = newDanmakuManager(c.onDanmakuEvent)
 	c.AreaData = RefreshAreaList()
 	c.StateManager = NewStateManager(c)
 	return c
@@ -93,6 +83,7 @@ func (c *Concern) Stop() {
 	c.StateManager.Stop()
 	logger.Trace("bilibili StateManager已停止")
 	c.wg.Wait()
+	c.danmaku.stop()
 	logger.Trace("bilibili concern已停止")
 }
 
@@ -107,7 +98,7 @@ func (c *Concern) Start() error {
 		})
 	}
 	c.UseNotifyGeneratorFunc(c.notifyGenerator())
-	if !IsVerifyGiven() {
+	if !IsVerifyGiven() && !IsQRLoginEnabled() && len(Accounts()) == 1 {
 		logger.Warnf("未设置B站账户，将使用慢速模式，推荐订阅数量不超过5个，否则推送将出现较长延迟，如需更多订阅，推荐您配置使用B站账号，最高可支持2000订阅。")
 		c.UseEmitQueue()
 		c.UseFreshFunc(c.emitQueueFresher())
@@ -116,12 +107,26 @@ func (c *Concern) Start() error {
 		go func() {
 			c.wg.Add(1)
 			defer c.wg.Done()
+			loginChan := eventbus.BusObj.Subscribe(TopicLogin)
+			reloadChan := eventbus.BusObj.Subscribe("config_reload")
 			c.SyncSub()
+			c.checkCookie()
 			tick := time.Tick(time.Hour)
+			cookieTick := time.Tick(time.Minute * 30)
 			for {
 				select {
 				case <-tick:
 					c.SyncSub()
+				case <-cookieTick:
+					c.checkCookie()
+				case <-loginChan:
+					logger.Info("B站账号已重新登陆，开始同步订阅")
+					c.SyncSub()
+				case <-reloadChan:
+					ReloadAccounts()
+				case <-accountChanged:
+					logger.Info("B站账号发生变化，开始重新分配订阅")
+					c.SyncSub()
 				case <-c.stop:
 					return
 				}
@@ -140,18 +145,20 @@ func (c *Concern) Start() error {
 			logger.Debugf("模块 BILIBILI 收到：bot_online: %v", msg)
 		}
 	}()
+	c.wg.Add(1)
+	go c.danmakuLoop()
 	return c.StateManager.Start()
 }
 
 func (c *Concern) Add(ctx mmsg.IMsgCtx,
-	groupCode int64, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+	target mmsg.Target, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	mid := _id.(int64)
 	selfUid := accountUid.Load()
 	var watchSelf = selfUid != 0 && selfUid == mid
 	var err error
-	log := logger.WithFields(localutils.GroupLogFields(groupCode)).WithField("mid", mid)
+	log := logger.WithFields(mmsg.TargetLogFields(target)).WithField("mid", mid)
 
-	err = c.StateManager.CheckGroupConcern(groupCode, mid, ctype)
+	err = c.StateManager.CheckGroupConcern(target, mid, ctype)
 	if err != nil {
 		return nil, err
 	}
@@ -203,8 +210,9 @@ func (c *Concern) Add(ctx mmsg.IMsgCtx,
 			if err != nil {
 				log.Errorf("GetConcern error %v", err)
 			} else if oldCtype.Empty() {
-				if c.checkRelation(mid) {
-					log.Infof("当前B站账户已关注该用户，跳过关注")
+				if acc := c.followedAccount(mid); acc != nil {
+					log.WithField("account", acc.Name).Infof("当前B站账户已关注该用户，跳过关注")
+					c.SetAccountShard(mid, acc.Name)
 				} else {
 					if cfg.GetBilibiliDisableSub() {
 						return nil, fmt.Errorf("关注用户失败 - 该用户未在关注列表内，请联系管理员")
@@ -213,7 +221,13 @@ func (c *Concern) Add(ctx mmsg.IMsgCtx,
 					if cfg.GetBilibiliHiddenSub() {
 						actType = ActHiddenSub
 					}
-					resp, err := c.ModifyUserRelation(mid, actType)
+					acc, err := c.pickAccount()
+					if err != nil {
+						log.Errorf("pickAccount error %v", err)
+						return nil, fmt.Errorf("关注用户失败 - 未配置B站")
+					}
+					log = log.WithField("account", acc.Name)
+					resp, err := c.modifyUserRelation(acc, mid, actType)
 					if err != nil {
 						if err == ErrVerifyRequired {
 							log.Errorf("ModifyUserRelation error %v", err)
@@ -234,6 +248,7 @@ func (c *Concern) Add(ctx mmsg.IMsgCtx,
 							return nil, fmt.Errorf("关注用户失败 - %v", resp.GetMessage())
 						}
 					}
+					c.SetAccountShard(mid, acc.Name)
 				}
 			}
 		} else if selfUid != 0 {
@@ -241,7 +256,7 @@ func (c *Concern) Add(ctx mmsg.IMsgCtx,
 		}
 	}
 
-	_, err = c.StateManager.AddGroupConcern(groupCode, mid, ctype)
+	_, err = c.StateManager.AddGroupConcern(target, mid, ctype)
 	if err != nil {
 		log.Errorf("AddGroupConcern error %v", err)
 		return nil, fmt.Errorf("关注用户失败 - 内部错误")
@@ -252,12 +267,11 @@ func (c *Concern) Add(ctx mmsg.IMsgCtx,
 	}
 	_ = c.StateManager.AddUserInfo(userInfo)
 	if ctype.ContainAny(Live) {
-		// 其他群关注了同一uid，并且推送过Living，那么给新watch的群也推一份
+		// 其他群关注了同一uid，并且推送过Living，那么给新watch的群或者私聊也推一份
 		if liveInfo != nil && liveInfo.Living() {
-			if ctx.GetTarget().TargetType().IsGroup() {
-				defer c.GroupWatchNotify(groupCode, mid)
-			}
-			if ctx.GetTarget().TargetType().IsPrivate() {
+			if mmsg.TargetEqual(ctx.GetTarget(), target) {
+				defer c.GroupWatchNotify(target, mid)
+			} else {
 				defer ctx.Send(mmsg.NewText("检测到该用户正在直播，但由于您目前处于私聊模式，" +
 					"因此不会在群内推送本次直播，将在该用户下次直播时推送"))
 			}
@@ -275,14 +289,14 @@ func (c *Concern) Add(ctx mmsg.IMsgCtx,
 }
 
 func (c *Concern) Remove(ctx mmsg.IMsgCtx,
-	groupCode int64, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+	target mmsg.Target, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	mid := id.(int64)
 	var identityInfo concern.IdentityInfo
 	var allCtype concern_type.Type
 	err := c.StateManager.RWCoverTx(func(tx *buntdb.Tx) error {
 		var err error
 		identityInfo, _ = c.Get(mid)
-		_, err = c.StateManager.RemoveGroupConcern(groupCode, mid, ctype)
+		_, err = c.StateManager.RemoveGroupConcern(target, mid, ctype)
 		if err != nil {
 			return err
 		}
@@ -317,25 +331,27 @@ func (c *Concern) Get(id interface{}) (concern.IdentityInfo, error) {
 }
 
 func (c *Concern) notifyGenerator() concern.NotifyGeneratorFunc {
-	return func(groupCode int64, ievent concern.Event) (result []concern.Notify) {
+	return func(target mmsg.Target, ievent concern.Event) (result []concern.Notify) {
 		log := ievent.Logger()
 		switch event := ievent.(type) {
 		case *LiveInfo:
 			if event.Status == LiveStatus_Living {
-				log.WithFields(localutils.GroupLogFields(groupCode)).Trace("living notify")
+				log.WithFields(mmsg.TargetLogFields(target)).Trace("living notify")
 			} else if event.Status == LiveStatus_NoLiving {
-				log.WithFields(localutils.GroupLogFields(groupCode)).Trace("noliving notify")
+				log.WithFields(mmsg.TargetLogFields(target)).Trace("noliving notify")
 			} else {
-				log.WithFields(localutils.GroupLogFields(groupCode)).Error("unknown live status")
+				log.WithFields(mmsg.TargetLogFields(target)).Error("unknown live status")
 			}
-			result = append(result, NewConcernLiveNotify(groupCode, event))
+			result = append(result, NewConcernLiveNotify(target, event))
 		case *NewsInfo:
-			notifies := NewConcernNewsNotify(groupCode, event, c)
-			log.WithFields(localutils.GroupLogFields(groupCode)).
+			notifies := NewConcernNewsNotify(target, event, c)
+			log.WithFields(mmsg.TargetLogFields(target)).
 				WithField("Size", len(notifies)).Trace("news notify")
 			for _, notify := range notifies {
 				result = append(result, notify)
 			}
+		case *DanmakuInfo:
+			result = append(result, NewConcernDanmakuNotify(target, event))
 		}
 		return
 	}
@@ -343,37 +359,10 @@ func (c *Concern) notifyGenerator() concern.NotifyGeneratorFunc {
 
 func (c *Concern) FindUser(mid int64, load bool) (*UserInfo, error) {
 	if load {
-		resp, err := XSpaceAccInfo(mid)
+		newLiveInfo, err := loadLiveInfo(mid)
 		if err != nil {
 			return nil, err
 		}
-		if resp.Code != 0 {
-			return nil, fmt.Errorf("code:%v %v", resp.Code, resp.Message)
-		}
-		newUserInfo := NewUserInfo(mid,
-			resp.GetData().GetLiveRoom().GetRoomid(),
-			resp.GetData().GetName(),
-			resp.GetData().GetLiveRoom().GetUrl(),
-		)
-		var liveTime int64
-		if resp.GetData().GetLiveRoom().GetLiveStatus() == LiveStatus_Living {
-			var liveTimeStr string
-			respRoom, err := GetRoomInfo(resp.GetData().GetLiveRoom().GetRoomid())
-			if err != nil {
-				logger.Warnf("GetRoomInfo error %v", err)
-			} else {
-				liveTimeStr = respRoom.GetData().GetLiveTime()
-			}
-			if liveTimeStr != "" {
-				liveTime = ParseLiveTime(liveTimeStr)
-			}
-		}
-		newLiveInfo := NewLiveInfo(newUserInfo,
-			resp.GetData().GetLiveRoom().GetTitle(),
-			resp.GetData().GetLiveRoom().GetCover(),
-			resp.GetData().GetLiveRoom().GetLiveStatus(),
-			liveTime,
-		)
 		// AddLiveInfo 会顺便添加UserInfo
 		err = c.StateManager.AddLiveInfo(newLiveInfo)
 		if err != nil {
@@ -383,6 +372,42 @@ func (c *Concern) FindUser(mid int64, load bool) (*UserInfo, error) {
 	return c.StateManager.GetUserInfo(mid)
 }
 
+// loadLiveInfo 从接口查询用户当前的直播间信息，不会保存
+func loadLiveInfo(mid int64) (*LiveInfo, error) {
+	resp, err := XSpaceAccInfo(mid)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("code:%v %v", resp.Code, resp.Message)
+	}
+	newUserInfo := NewUserInfo(mid,
+		resp.GetData().GetLiveRoom().GetRoomid(),
+		resp.GetData().GetName(),
+		resp.GetData().GetLiveRoom().GetUrl(),
+	)
+	var liveTime int64
+	if resp.GetData().GetLiveRoom().GetLiveStatus() == LiveStatus_Living {
+		var liveTimeStr string
+		respRoom, err := GetRoomInfo(resp.GetData().GetLiveRoom().GetRoomid())
+		if err != nil {
+			logger.Warnf("GetRoomInfo error %v", err)
+		} else {
+			liveTimeStr = respRoom.GetData().GetLiveTime()
+		}
+		if liveTimeStr != "" {
+			liveTime = ParseLiveTime(liveTimeStr)
+		}
+	}
+	newLiveInfo := NewLiveInfo(newUserInfo,
+		resp.GetData().GetLiveRoom().GetTitle(),
+		resp.GetData().GetLiveRoom().GetCover(),
+		resp.GetData().GetLiveRoom().GetLiveStatus(),
+		liveTime,
+	)
+	return newLiveInfo, nil
+}
+
 func (c *Concern) StatUserWithCache(mid int64, expire time.Duration) (*UserStat, error) {
 	userStat, _ := c.StateManager.GetUserStat(mid)
 	if userStat != nil {
@@ -403,21 +428,30 @@ func (c *Concern) StatUserWithCache(mid int64, expire time.Duration) (*UserStat,
 	return userStat, nil
 }
 
+// ModifyUserRelation 使用负责该用户的账号修改关注关系
 func (c *Concern) ModifyUserRelation(mid int64, act int) (*RelationModifyResponse, error) {
+	return c.modifyUserRelation(c.accountOf(mid), mid, act)
+}
+
+func (c *Concern) modifyUserRelation(acc *Account, mid int64, act int) (*RelationModifyResponse, error) {
 	var resp *RelationModifyResponse
 	var err error
 	// b站好像有新灰度，-111代表 csrf校验失败
 	// 只有shjd这个idc会返回这个错误
 	// 当返回-111的时候重试一下
 	localutils.Retry(3, time.Millisecond*300, func() bool {
-		resp, err = RelationModify(mid, act)
+		resp, err = acc.RelationModify(mid, act)
 		return err != nil || resp.GetCode() != -111
 	})
 	if err != nil {
 		return nil, err
 	}
 	if resp.GetCode() != 0 {
+		if isLimitCode(resp.GetCode()) {
+			acc.MarkLimited()
+		}
 		logger.WithField("code", resp.GetCode()).
+			WithField("account", acc.Name).
 			WithField("message", resp.GetMessage()).
 			WithField("act", act).
 			WithField("mid", mid).
@@ -428,30 +462,100 @@ func (c *Concern) ModifyUserRelation(mid int64, act int) (*RelationModifyRespons
 	return resp, nil
 }
 
+// SyncSub 同步订阅和账号关注列表，并按账号重新分配订阅：
+// 负责账号已移除或者被风控的订阅会分配给其他可用账号，每个账号只关注自己负责的订阅
 func (c *Concern) SyncSub() {
 	defer logger.Debug("SyncSub done")
-	resp, err := GetAttentionList()
-	if err != nil {
-		logger.Errorf("SyncSub error %v", err)
-		return
+	var available []*Account
+	var availableNames []string
+	for _, acc := range Accounts() {
+		if acc.Available() {
+			available = append(available, acc)
+			availableNames = append(availableNames, acc.Name)
+		}
 	}
-	if resp.GetCode() != 0 {
-		logger.WithField("code", resp.GetCode()).
-			WithField("msg", resp.GetMessage()).
-			Errorf("SyncSub GetAttentionList error")
+	if len(available) == 0 {
+		logger.Debug("SyncSub skipped - 没有可用的B站账户")
 		return
 	}
 	var midSet = make(map[int64]bool)
-	var attentionMidSet = make(map[int64]bool)
-	_, _, _, err = c.StateManager.ListConcernState(func(groupCode int64, id interface{}, p concern_type.Type) bool {
+	_, _, _, err := c.StateManager.ListConcernState(func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
 		midSet[id.(int64)] = true
 		return true
 	})
-
 	if err != nil {
 		logger.Errorf("SyncSub ListConcernState all error %v", err)
 		return
 	}
+	current, err := c.ListAccountShard()
+	if err != nil {
+		logger.Errorf("SyncSub ListAccountShard error %v", err)
+		return
+	}
+	var mids []int64
+	for mid := range midSet {
+		mids = append(mids, mid)
+	}
+	plan := planAccountShard(mids, current, availableNames, shardRebalanceThreshold)
+
+	for _, acc := range available {
+		var shard []int64
+		for mid, name := range plan {
+			if name == acc.Name {
+				shard = append(shard, mid)
+			}
+		}
+		if !c.syncAccountSub(acc, shard) {
+			return
+		}
+	}
+
+	for mid, name := range plan {
+		oldName, found := current[mid]
+		if !found {
+			oldName = MainAccountName
+		}
+		if found && oldName == name {
+			continue
+		}
+		if err := c.SetAccountShard(mid, name); err != nil {
+			logger.WithField("mid", mid).Errorf("SetAccountShard error %v", err)
+			continue
+		}
+		if oldName == name {
+			continue
+		}
+		logger.WithField("mid", mid).WithField("from", oldName).WithField("to", name).
+			Info("订阅已分配给新的B站账号")
+		if old := GetAccount(oldName); old != nil && old.Available() && cfg.GetBilibiliUnsub() {
+			resp, err := c.modifyUserRelation(old, mid, ActUnsub)
+			if err != nil {
+				logger.WithField("mid", mid).Errorf("取消关注失败 - %v", err)
+			} else if resp.GetCode() != 0 {
+				logger.WithField("mid", mid).Errorf("取消关注失败 - %v - %v", resp.GetCode(), resp.GetMessage())
+			}
+		}
+	}
+}
+
+// syncAccountSub 让账号关注自己负责的订阅，返回false表示concern已经停止
+func (c *Concern) syncAccountSub(acc *Account, shard []int64) bool {
+	log := logger.WithField("account", acc.Name)
+	resp, err := acc.GetAttentionList()
+	if err != nil {
+		log.Errorf("SyncSub error %v", err)
+		return true
+	}
+	if resp.GetCode() != 0 {
+		log.WithField("code", resp.GetCode()).
+			WithField("msg", resp.GetMessage()).
+			Errorf("SyncSub GetAttentionList error")
+		if isLimitCode(resp.GetCode()) {
+			acc.MarkLimited()
+		}
+		return true
+	}
+	var attentionMidSet = make(map[int64]bool)
 	for _, attentionMid := range resp.GetData().GetList() {
 		attentionMidSet[attentionMid] = true
 	}
@@ -465,39 +569,44 @@ func (c *Concern) SyncSub() {
 		actType = ActHiddenSub
 	}
 
-	for mid := range midSet {
-		if mid == accountUid.Load() {
+	for _, mid := range shard {
+		if mid == acc.Uid() || mid == accountUid.Load() {
 			continue
 		}
 		if _, found := attentionMidSet[mid]; !found {
 			if disableSub {
-				logger.Warnf("检测到存在未关注的订阅目标 UID:%v，同时禁用了b站自动关注，将无法推送该用户", mid)
+				log.Warnf("检测到存在未关注的订阅目标 UID:%v，同时禁用了b站自动关注，将无法推送该用户", mid)
 				continue
 			}
-			resp, err := c.ModifyUserRelation(mid, actType)
+			resp, err := c.modifyUserRelation(acc, mid, actType)
 			if err == nil {
 				switch resp.Code {
 				case 22002, 22003, 22013:
 					// 22002 可能是被拉黑了
 					// 22003 主动拉黑对方
 					// 22013 帐号注销
-					logger.WithField("ModifyUserRelation Code", resp.Code).
+					log.WithField("ModifyUserRelation Code", resp.Code).
 						WithField("ModifyUserRelation Message", resp.Message).
 						WithField("mid", mid).
 						Errorf("ModifyUserRelation failed, remove concern")
 					c.RemoveAllById(mid)
 				}
+				if acc.IsLimited() {
+					// 剩下的订阅等下次同步时分配给其他账号
+					return true
+				}
 			} else {
-				logger.Errorf("ModifyUserRelation error %v", err)
+				log.Errorf("ModifyUserRelation error %v", err)
 			}
 			time.Sleep(time.Second * 3)
 			select {
 			case <-c.stop:
-				return
+				return false
 			default:
 			}
 		}
 	}
+	return true
 }
 
 func (c *Concern) FindOrLoadUser(mid int64) (*UserInfo, error) {
@@ -542,11 +651,11 @@ func (c *Concern) FindUserNews(mid int64, load bool) (*NewsInfo, error) {
 	return c.StateManager.GetNewsInfo(mid)
 }
 
-func (c *Concern) GroupWatchNotify(groupCode, mid int64) {
+func (c *Concern) GroupWatchNotify(target mmsg.Target, mid int64) {
 	liveInfo, _ := c.GetLiveInfo(mid)
 	if liveInfo.Living() {
 		liveInfo.liveStatusChanged = true
-		c.notify <- NewConcernLiveNotify(groupCode, liveInfo)
+		c.notify <- NewConcernLiveNotify(target, liveInfo)
 	}
 }
 
@@ -598,20 +707,13 @@ func (c *Concern) unsubUser(mid int64) {
 		logger.Errorf("取消关注失败 - %v - %v", resp.GetCode(), resp.GetMessage())
 	} else {
 		logger.WithField("mid", mid).Info("取消关注成功")
+		c.DeleteAccountShard(mid)
 	}
 }
 
+// checkRelation 是否有账号已经关注了该用户
 func (c *Concern) checkRelation(mid int64) bool {
-	var atr = c.attentionListExpirable.Do()
-	if atr == nil {
-		return false
-	}
-	var matr = atr.(map[int64]interface{})
-	if _, found := matr[mid]; found {
-		return true
-	} else {
-		return false
-	}
+	return c.followedAccount(mid) != nil
 }
 
 func (c *Concern) filterCard(card *Card) bool {
@@ -0,0 +1,189 @@
+package bilibili
+
+import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"sort"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/utils/expirable"
+)
+
+// shardRebalanceThreshold 账号之间负责的订阅数量差距超过这个值时才会重新分配，避免频繁关注/取关
+const shardRebalanceThreshold = 20
+
+// attentionListOf 返回账号的关注列表缓存
+func (c *Concern) attentionListOf(acc *Account) *expirable.Expirable {
+	c.attentionMux.Lock()
+	defer c.attentionMux.Unlock()
+	if e, found := c.attentionList[acc.Name]; found {
+		return e
+	}
+	e := expirable.NewExpirable(time.Second*20, func() interface{} {
+		var m = make(map[int64]interface{})
+		resp, err := acc.GetAttentionList()
+		if err != nil {
+			logger.WithField("account", acc.Name).Errorf("GetAttentionList error %v", err)
+			return m
+		}
+		if resp.GetCode() != 0 {
+			logger.WithField("account", acc.Name).Errorf("GetAttentionList error %v - %v", resp.GetCode(), resp.GetMessage())
+			return m
+		}
+		for _, id := range resp.GetData().GetList() {
+			m[id] = struct{}{}
+		}
+		return m
+	})
+	c.attentionList[acc.Name] = e
+	return e
+}
+
+func (c *Concern) checkAccountRelation(acc *Account, mid int64) bool {
+	var atr = c.attentionListOf(acc).Do()
+	if atr == nil {
+		return false
+	}
+	_, found := atr.(map[int64]interface{})[mid]
+	return found
+}
+
+// followedAccount 返回已经关注了该用户的可用账号，没有时返回nil
+func (c *Concern) followedAccount(mid int64) *Account {
+	for _, acc := range Accounts() {
+		if !acc.Available() {
+			continue
+		}
+		if c.checkAccountRelation(acc, mid) {
+			return acc
+		}
+	}
+	return nil
+}
+
+// accountOf 返回负责该用户的账号，账号已经不存在时返回主账号
+func (c *Concern) accountOf(mid int64) *Account {
+	if acc := GetAccount(c.GetAccountShard(mid)); acc != nil {
+		return acc
+	}
+	return mainAccount
+}
+
+// pickAccount 为新的订阅选择负责的账号，选择负责订阅最少的可用账号，没有可用的账号时返回 ErrVerifyRequired
+func (c *Concern) pickAccount() (*Account, error) {
+	var available []*Account
+	for _, acc := range Accounts() {
+		if acc.Available() {
+			available = append(available, acc)
+		}
+	}
+	if len(available) == 0 {
+		return nil, ErrVerifyRequired
+	}
+	if len(available) == 1 {
+		return available[0], nil
+	}
+	shards, err := c.ListAccountShard()
+	if err != nil {
+		logger.Errorf("ListAccountShard error %v", err)
+		return available[0], nil
+	}
+	var load = make(map[string]int)
+	_, ids, _, err := c.ListConcernState(func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
+		return true
+	})
+	if err != nil {
+		logger.Errorf("ListConcernState error %v", err)
+		return available[0], nil
+	}
+	var counted = make(map[int64]bool)
+	for _, id := range ids {
+		mid := id.(int64)
+		if counted[mid] {
+			continue
+		}
+		counted[mid] = true
+		if name, found := shards[mid]; found {
+			load[name]++
+		} else {
+			load[MainAccountName]++
+		}
+	}
+	var result = available[0]
+	for _, acc := range available[1:] {
+		if load[acc.Name] < load[result.Name] {
+			result = acc
+		}
+	}
+	return result, nil
+}
+
+// planAccountShard 计算每个订阅应该由哪个账号负责。
+// 没有记录或者负责账号不可用的订阅会分配给负责订阅最少的账号，
+// 账号之间负责的订阅数量差距超过 threshold 时，会从最多的账号移动到最少的账号。
+func planAccountShard(mids []int64, current map[int64]string, available []string, threshold int) map[int64]string {
+	var result = make(map[int64]string, len(mids))
+	if len(available) == 0 {
+		return result
+	}
+	mids = append([]int64(nil), mids...)
+	sort.Slice(mids, func(i, j int) bool {
+		return mids[i] < mids[j]
+	})
+	var load = make(map[string][]int64)
+	for _, name := range available {
+		load[name] = nil
+	}
+	var pending []int64
+	for _, mid := range mids {
+		name, found := current[mid]
+		if !found {
+			name = MainAccountName
+		}
+		if _, ok := load[name]; ok {
+			load[name] = append(load[name], mid)
+		} else {
+			pending = append(pending, mid)
+		}
+	}
+	minAccount := func() string {
+		var result = available[0]
+		for _, name := range available[1:] {
+			if len(load[name]) < len(load[result]) {
+				result = name
+			}
+		}
+		return result
+	}
+	maxAccount := func() string {
+		var result = available[0]
+		for _, name := range available[1:] {
+			if len(load[name]) > len(load[result]) {
+				result = name
+			}
+		}
+		return result
+	}
+	for _, mid := range pending {
+		name := minAccount()
+		load[name] = append(load[name], mid)
+	}
+	if threshold < 1 {
+		threshold = 1
+	}
+	for {
+		maxName, minName := maxAccount(), minAccount()
+		if len(load[maxName])-len(load[minName]) <= threshold {
+			break
+		}
+		last := len(load[maxName]) - 1
+		load[minName] = append(load[minName], load[maxName][last])
+		load[maxName] = load[maxName][:last]
+	}
+	for name, shard := range load {
+		for _, mid := range shard {
+			result[mid] = name
+		}
+	}
+	return result
+}
@@ -0,0 +1,180 @@
+package bilibili
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/cfg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
+	"github.com/sirupsen/logrus"
+)
+
+// 检查订阅了 Danmaku 的直播间是否在直播的间隔
+const danmakuCheckInterval = time.Minute
+
+// DanmakuInfo 直播间里的一个醒目留言、大航海或者礼物事件
+type DanmakuInfo struct {
+	UserInfo
+	Event *DanmakuEvent `json:"event"`
+
+	msgCache template.MsgCache
+}
+
+func (d *DanmakuInfo) Site() string {
+	return Site
+}
+
+func (d *DanmakuInfo) Type() concern_type.Type {
+	return Danmaku
+}
+
+func (d *DanmakuInfo) Logger() *logrus.Entry {
+	return logger.WithFields(logrus.Fields{
+		"Site":   Site,
+		"Mid":    d.Mid,
+		"Name":   d.Name,
+		"RoomId": d.RoomId,
+		"Type":   d.Type().String(),
+		"Kind":   d.Event.Kind,
+	})
+}
+
+func (d *DanmakuInfo) GetMSG(locale string) *mmsg.MSG {
+	return d.msgCache.Get(locale, func() *mmsg.MSG {
+		roomUrl := d.RoomUrl
+		if pos := strings.Index(roomUrl, "?"); pos > 0 {
+			roomUrl = roomUrl[:pos]
+		}
+		var data = map[string]interface{}{
+			"uid":       d.Mid,
+			"name":      d.Name,
+			"url":       roomUrl,
+			"user":      d.Event.UserName,
+			"user_id":   d.Event.Uid,
+			"message":   d.Event.Message,
+			"gift":      d.Event.GiftName,
+			"num":       d.Event.Num,
+			"price":     d.Event.PriceString(),
+			"guard":     GuardName(d.Event.GuardLevel),
+			"guard_lvl": d.Event.GuardLevel,
+		}
+		msg, err := template.ExecNotify(locale, "notify.group.bilibili."+string(d.Event.Kind)+".tmpl", data)
+		if err != nil {
+			logger.Errorf("bilibili: DanmakuInfo LoadAndExec error %v", err)
+		}
+		return msg
+	})
+}
+
+type ConcernDanmakuNotify struct {
+	Target mmsg.Target `json:"target"`
+	*DanmakuInfo
+}
+
+func (notify *ConcernDanmakuNotify) ToMessage() *mmsg.MSG {
+	return notify.DanmakuInfo.GetMSG(template.NotifyLocale(notify.Target))
+}
+
+func (notify *ConcernDanmakuNotify) Logger() *logrus.Entry {
+	if notify == nil {
+		return logger
+	}
+	return notify.DanmakuInfo.Logger().WithFields(mmsg.TargetLogFields(notify.Target))
+}
+
+func (notify *ConcernDanmakuNotify) GetTarget() mmsg.Target {
+	return notify.Target
+}
+
+func NewConcernDanmakuNotify(target mmsg.Target, info *DanmakuInfo) *ConcernDanmakuNotify {
+	return &ConcernDanmakuNotify{
+		Target:      target,
+		DanmakuInfo: info,
+	}
+}
+
+// danmakuPass 检查事件是否达到配置的推送阈值
+func danmakuPass(event *DanmakuEvent) bool {
+	switch event.Kind {
+	case DanmakuSuperChat:
+		return event.Price >= cfg.GetBilibiliDanmakuSuperChatPrice()
+	case DanmakuGuard:
+		// 等级数字越小越高
+		return event.GuardLevel > 0 && event.GuardLevel <= cfg.GetBilibiliDanmakuGuardLevel()
+	case DanmakuGift:
+		return event.Price >= cfg.GetBilibiliDanmakuGiftPrice()
+	}
+	return false
+}
+
+func (c *Concern) onDanmakuEvent(mid int64, event *DanmakuEvent) {
+	if !danmakuPass(event) {
+		return
+	}
+	userInfo, err := c.GetUserInfo(mid)
+	if err != nil {
+		logger.WithField("mid", mid).Errorf("danmaku GetUserInfo error %v", err)
+		return
+	}
+	info := &DanmakuInfo{UserInfo: *userInfo, Event: event}
+	info.Logger().Debug("danmaku event")
+	c.EmitEvent(info)
+}
+
+func (c *Concern) danmakuLoop() {
+	defer c.wg.Done()
+	ticker := time.NewTicker(danmakuCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.syncDanmaku()
+		}
+	}
+}
+
+// syncDanmaku 连接订阅了 Danmaku 并且正在直播的直播间
+// 同时订阅了 Live 时直接使用直播状态，否则查询直播间信息
+func (c *Concern) syncDanmaku() {
+	_, ids, types, err := c.StateManager.ListConcernState(func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
+		return p.ContainAny(Danmaku)
+	})
+	if err != nil {
+		logger.Errorf("syncDanmaku ListConcernState error %v", err)
+		return
+	}
+	ids, _, err = c.StateManager.GroupTypeById(ids, types)
+	if err != nil {
+		logger.Errorf("syncDanmaku GroupTypeById error %v", err)
+		return
+	}
+	var living = make(map[int64]int64)
+	for _, id := range ids {
+		mid := id.(int64)
+		userInfo, err := c.FindOrLoadUser(mid)
+		if err != nil || userInfo.RoomId == 0 {
+			continue
+		}
+		if ctype, _ := c.GetConcern(mid); ctype.ContainAny(Live) {
+			if liveInfo, err := c.GetLiveInfo(mid); err == nil {
+				if liveInfo.Living() {
+					living[mid] = userInfo.RoomId
+				}
+				continue
+			}
+		}
+		roomInfo, err := GetRoomInfo(userInfo.RoomId)
+		if err != nil {
+			logger.WithField("mid", mid).Errorf("syncDanmaku GetRoomInfo error %v", err)
+			continue
+		}
+		if roomInfo.GetLiveStatus() == LiveStatus_Living {
+			living[mid] = userInfo.RoomId
+		}
+	}
+	c.danmaku.sync(living)
+}
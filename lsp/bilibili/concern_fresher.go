@@ -7,272 +7,349 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/lsp/cfg"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/sirupsen/logrus"
 	"github.com/tidwall/buntdb"
 	"go.uber.org/atomic"
 	"golang.org/x/sync/errgroup"
+	"runtime/debug"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // fresh 这个fresh不能启动多个
+// 每个账号都有自己的刷新循环，只刷新自己负责的订阅，账号增删时会启动或停止对应的循环
 func (c *Concern) fresh() concern.FreshFunc {
 	return func(ctx context.Context, eventChan chan<- concern.Event) {
-		t := time.NewTimer(time.Second * 3)
-		var interval time.Duration
-		if config.GlobalConfig != nil {
-			interval = config.GlobalConfig.GetDuration("bilibili.interval")
-		}
-		if interval == 0 {
-			interval = time.Second * 20
-		}
-		var freshCount atomic.Int32
-		if !cfg.GetBilibiliOnlyOnlineNotify() {
-			freshCount.Store(1000)
+		var wg sync.WaitGroup
+		var running = make(map[*Account]context.CancelFunc)
+		var done = make(chan *Account, 1)
+		check := func() {
+			var exist = make(map[*Account]bool)
+			for _, acc := range Accounts() {
+				exist[acc] = true
+				if _, found := running[acc]; found {
+					continue
+				}
+				accCtx, cancel := context.WithCancel(ctx)
+				running[acc] = cancel
+				wg.Add(1)
+				go func(acc *Account) {
+					defer func() {
+						if e := recover(); e != nil {
+							logger.WithField("account", acc.Name).WithField("stack", string(debug.Stack())).
+								Errorf("fresh account panic recovered %v", e)
+						}
+						wg.Done()
+						select {
+						case done <- acc:
+						case <-ctx.Done():
+						}
+					}()
+					c.freshAccount(accCtx, acc, eventChan)
+				}(acc)
+			}
+			for acc, cancel := range running {
+				if !exist[acc] {
+					logger.WithField("account", acc.Name).Debug("stop fresh account")
+					cancel()
+				}
+			}
 		}
+		check()
+		ticker := time.NewTicker(time.Second * 30)
+		defer ticker.Stop()
 		for {
 			select {
-			case <-t.C:
+			case <-ticker.C:
+				check()
+			case acc := <-done:
+				if cancel, found := running[acc]; found {
+					cancel()
+					delete(running, acc)
+				}
 			case <-ctx.Done():
+				wg.Wait()
 				return
 			}
-			start := time.Now()
-			var errGroup errgroup.Group
+		}
+	}
+}
 
-			errGroup.Go(func() error {
-				defer func() {
-					logger.WithField("cost", time.Now().Sub(start)).
-						Tracef("watchCore dynamic fresh done")
-				}()
-				newsList, err := c.freshDynamicNew()
-				if err != nil {
-					logger.Errorf("freshDynamicNew failed %v", err)
-					return err
-				} else {
-					for _, news := range newsList {
-						eventChan <- news
-					}
-				}
-				return nil
-			})
+// freshAccount 使用账号刷新它负责的订阅
+func (c *Concern) freshAccount(ctx context.Context, acc *Account, eventChan chan<- concern.Event) {
+	t := time.NewTimer(time.Second * 3)
+	var interval time.Duration
+	if config.GlobalConfig != nil {
+		interval = config.GlobalConfig.GetDuration("bilibili.interval")
+	}
+	if interval == 0 {
+		interval = time.Second * 20
+	}
+	var freshCount atomic.Int32
+	if !cfg.GetBilibiliOnlyOnlineNotify() {
+		freshCount.Store(1000)
+	}
+	for {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+		if !acc.Available() {
+			// 等待扫码登陆或者风控冷却
+			t.Reset(interval)
+			continue
+		}
+		start := time.Now()
+		var errGroup errgroup.Group
 
-			errGroup.Go(func() error {
-				defer func() {
-					logger.WithField("cost", time.Now().Sub(start)).
-						Tracef("watchCore live fresh done")
-				}()
-				liveInfo, err := c.freshLive()
-				if err != nil {
-					logger.Errorf("freshLive error %v", err)
-					return err
-				}
-				// liveInfoMap内是所有正在直播的列表，没有直播的不应该放进去
-				var liveInfoMap = make(map[int64]*LiveInfo)
-				for _, info := range liveInfo {
-					liveInfoMap[info.Mid] = info
+		errGroup.Go(func() error {
+			defer func() {
+				logger.WithField("cost", time.Now().Sub(start)).
+					Tracef("watchCore dynamic fresh done")
+			}()
+			newsList, err := c.freshDynamicNew(acc)
+			if err != nil {
+				logger.Errorf("freshDynamicNew failed %v", err)
+				return err
+			} else {
+				for _, news := range newsList {
+					eventChan <- news
 				}
+			}
+			return nil
+		})
 
-				_, ids, types, err := c.StateManager.ListConcernState(
-					func(groupCode int64, id interface{}, p concern_type.Type) bool {
-						return p.ContainAny(Live)
-					})
-				if err != nil {
-					logger.Errorf("ListConcernState error %v", err)
-					return err
+		errGroup.Go(func() error {
+			defer func() {
+				logger.WithField("cost", time.Now().Sub(start)).
+					Tracef("watchCore live fresh done")
+			}()
+			liveInfo, err := c.freshLive(acc)
+			if err != nil {
+				logger.Errorf("freshLive error %v", err)
+				return err
+			}
+			// liveInfoMap内是所有正在直播的列表，没有直播的不应该放进去
+			var liveInfoMap = make(map[int64]*LiveInfo)
+			for _, info := range liveInfo {
+				liveInfoMap[info.Mid] = info
+			}
+
+			shards, err := c.ListAccountShard()
+			if err != nil {
+				logger.Errorf("ListAccountShard error %v", err)
+				return err
+			}
+			_, ids, types, err := c.StateManager.ListConcernState(
+				func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
+					name, found := shards[id.(int64)]
+					if !found {
+						name = MainAccountName
+					}
+					return p.ContainAny(Live) && name == acc.Name
+				})
+			if err != nil {
+				logger.Errorf("ListConcernState error %v", err)
+				return err
+			}
+			ids, types, err = c.GroupTypeById(ids, types)
+			if err != nil {
+				logger.Errorf("GroupTypeById error %v", err)
+				return err
+			}
+
+			sendLiveInfo := func(info *LiveInfo) {
+				if info.Status == LiveStatus_Living {
+					resp, err := GetRoomInfo(info.RoomId)
+					if err != nil {
+						logger.WithField("mid", info.Mid).Errorf("GetRoomInfo error %v", err)
+					}
+					info.LiveTime = ParseLiveTime(resp.GetData().GetLiveTime())
+				}
+				addLiveInfoErr := c.AddLiveInfo(info)
+				if addLiveInfoErr != nil {
+					// 如果因为系统原因add失败，会造成重复推送
+					// 按照ddbot的原则，选择不推送，而非重复推送
+					logger.WithField("mid", info.Mid).Errorf("add live info error %v", err)
+					return
 				}
-				ids, types, err = c.GroupTypeById(ids, types)
-				if err != nil {
-					logger.Errorf("GroupTypeById error %v", err)
-					return err
+				if (info.Living() && freshCount.Load() < 1) || (!info.Living() && freshCount.Load() < 3) {
+					return
 				}
+				eventChan <- info
+			}
 
-				sendLiveInfo := func(info *LiveInfo) {
-					if info.Status == LiveStatus_Living {
-						resp, err := GetRoomInfo(info.RoomId)
-						if err != nil {
-							logger.WithField("mid", info.Mid).Errorf("GetRoomInfo error %v", err)
-						}
-						info.LiveTime = ParseLiveTime(resp.GetData().GetLiveTime())
+			var selfUid int64
+			if acc.IsMain() {
+				selfUid = accountUid.Load()
+			}
+			for _, id := range ids {
+				mid := id.(int64)
+				if selfUid != 0 && selfUid == mid {
+					// 特殊处理下关注自己
+					selfInfo, err := c.GetUserInfo(selfUid)
+					if err != nil {
+						logger.WithField("uid", mid).WithField("name", selfInfo.Name).
+							WithField("room_id", selfInfo.RoomId).Errorf("GetRoomInfo error %v", err)
+						continue
 					}
-					addLiveInfoErr := c.AddLiveInfo(info)
-					if addLiveInfoErr != nil {
-						// 如果因为系统原因add失败，会造成重复推送
-						// 按照ddbot的原则，选择不推送，而非重复推送
-						logger.WithField("mid", info.Mid).Errorf("add live info error %v", err)
-						return
+					roomId := selfInfo.RoomId
+					resp, err := GetRoomInfo(roomId)
+					if err != nil {
+						logger.WithField("uid", mid).WithField("name", selfInfo.Name).
+							WithField("room_id", roomId).Errorf("GetRoomInfo error %v", err)
+						continue
+					}
+					resp2, err := GetPlayTogetherUserAnchorInfoV2(mid)
+					if err != nil {
+						logger.WithField("uid", mid).WithField("name", selfInfo.Name).
+							WithField("room_id", roomId).Errorf("GetPlayTogetherUserAnchorInfoV2 error %v", err)
+						continue
 					}
-					if (info.Living() && freshCount.Load() < 1) || (!info.Living() && freshCount.Load() < 3) {
-						return
+					selfLiveInfo := NewLiveInfo(
+						NewUserInfo(mid, resp.Data.RoomId, resp2.GetName(), resp.GetUrl()),
+						resp.GetTitle(),
+						resp.GetCover(),
+						resp.GetLiveStatus(),
+						ParseLiveTime(resp.GetData().GetLiveTime()),
+					)
+					selfLiveInfo.SetAreaData(
+						resp.Data.GetAreaId(),
+						resp.Data.GetAreaName(),
+						resp.Data.GetParentAreaId(),
+						resp.Data.GetParentAreaName(),
+					)
+					//accResp, err := XSpaceAccInfo(selfUid)
+					//if err != nil {
+					//	logger.Errorf("freshLive self-fresh %v error %v", selfUid, err)
+					//	return err
+					//}
+					//liveRoom := accResp.GetData().GetLiveRoom()
+					//selfLiveInfo := NewLiveInfo(
+					//	NewUserInfo(selfUid, liveRoom.GetRoomid(), accResp.GetData().GetName(), liveRoom.GetUrl()),
+					//	liveRoom.GetTitle(),
+					//	liveRoom.GetCover(),
+					//	liveRoom.GetLiveStatus(),
+					//)
+					if selfLiveInfo.Living() {
+						liveInfoMap[selfUid] = selfLiveInfo
 					}
-					eventChan <- info
 				}
-
-				selfUid := accountUid.Load()
-				for _, id := range ids {
-					mid := id.(int64)
-					if selfUid != 0 && selfUid == mid {
-						// 特殊处理下关注自己
-						selfInfo, err := c.GetUserInfo(selfUid)
-						if err != nil {
-							logger.WithField("uid", mid).WithField("name", selfInfo.Name).
-								WithField("room_id", selfInfo.RoomId).Errorf("GetRoomInfo error %v", err)
+				oldInfo, _ := c.GetLiveInfo(mid)
+				if oldInfo == nil {
+					// first live info
+					if newInfo, found := liveInfoMap[mid]; found {
+						newInfo.liveStatusChanged = true
+						sendLiveInfo(newInfo)
+					}
+					continue
+				}
+				if oldInfo.Status == LiveStatus_NoLiving {
+					if newInfo, found := liveInfoMap[mid]; found {
+						// notliving -> living
+						newInfo.liveStatusChanged = true
+						sendLiveInfo(newInfo)
+					}
+				} else if oldInfo.Status == LiveStatus_Living {
+					if newInfo, found := liveInfoMap[mid]; !found {
+						// living -> notliving
+						if count := c.IncNotLiveCount(mid); count < 3 {
+							logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
+								WithField("notlive_count", count).
+								Trace("notlive counting")
 							continue
+						} else {
+							logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
+								Debug("notlive count done, notlive confirmed")
+						}
+						if err := c.ClearNotLiveCount(mid); err != nil {
+							logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
+								Errorf("clear notlive count error %v", err)
 						}
-						roomId := selfInfo.RoomId
+						//resp, err := XSpaceAccInfo(mid)
+						//if err != nil {
+						//	logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
+						//		Errorf("XSpaceAccInfo error %v", err)
+						//	continue
+						//}
+						//if resp.GetData().GetLiveRoom().GetLiveStatus() == LiveStatus_Living {
+						//	continue
+						//} else {
+						//	logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
+						//		Debug("XSpaceAccInfo notlive confirmed")
+						//}
+						//newInfo = NewLiveInfo(&oldInfo.UserInfo, resp.GetData().GetLiveRoom().GetTitle(),
+						//	resp.GetData().GetLiveRoom().GetCover(), LiveStatus_NoLiving)
+						//newInfo.Name = resp.GetData().GetName()
+						roomId := oldInfo.RoomId
 						resp, err := GetRoomInfo(roomId)
-						if err != nil {
-							logger.WithField("uid", mid).WithField("name", selfInfo.Name).
+						if err != nil || resp.Data.Uid != mid {
+							logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
 								WithField("room_id", roomId).Errorf("GetRoomInfo error %v", err)
 							continue
 						}
+						if resp.GetLiveStatus() == LiveStatus_Living {
+							continue
+						} else {
+							logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
+								Debug("XSpaceAccInfo notlive confirmed")
+						}
 						resp2, err := GetPlayTogetherUserAnchorInfoV2(mid)
 						if err != nil {
-							logger.WithField("uid", mid).WithField("name", selfInfo.Name).
+							logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
 								WithField("room_id", roomId).Errorf("GetPlayTogetherUserAnchorInfoV2 error %v", err)
 							continue
 						}
-						selfLiveInfo := NewLiveInfo(
-							NewUserInfo(mid, resp.Data.RoomId, resp2.GetName(), resp.GetUrl()),
-							resp.GetTitle(),
-							resp.GetCover(),
-							resp.GetLiveStatus(),
-							ParseLiveTime(resp.GetData().GetLiveTime()),
-						)
-						selfLiveInfo.SetAreaData(
+						newInfo = NewLiveInfo(&oldInfo.UserInfo, resp.GetTitle(),
+							resp.GetCover(), LiveStatus_NoLiving, oldInfo.LiveTime)
+						newInfo.SetAreaData(
 							resp.Data.GetAreaId(),
 							resp.Data.GetAreaName(),
 							resp.Data.GetParentAreaId(),
 							resp.Data.GetParentAreaName(),
 						)
-						//accResp, err := XSpaceAccInfo(selfUid)
-						//if err != nil {
-						//	logger.Errorf("freshLive self-fresh %v error %v", selfUid, err)
-						//	return err
-						//}
-						//liveRoom := accResp.GetData().GetLiveRoom()
-						//selfLiveInfo := NewLiveInfo(
-						//	NewUserInfo(selfUid, liveRoom.GetRoomid(), accResp.GetData().GetName(), liveRoom.GetUrl()),
-						//	liveRoom.GetTitle(),
-						//	liveRoom.GetCover(),
-						//	liveRoom.GetLiveStatus(),
-						//)
-						if selfLiveInfo.Living() {
-							liveInfoMap[selfUid] = selfLiveInfo
+						newInfo.Name = resp2.GetName()
+						newInfo.liveStatusChanged = true
+						sendLiveInfo(newInfo)
+					} else {
+						if newInfo.LiveTitle == "bilibili主播的直播间" {
+							newInfo.LiveTitle = oldInfo.LiveTitle
 						}
-					}
-					oldInfo, _ := c.GetLiveInfo(mid)
-					if oldInfo == nil {
-						// first live info
-						if newInfo, found := liveInfoMap[mid]; found {
-							newInfo.liveStatusChanged = true
-							sendLiveInfo(newInfo)
+						if err := c.ClearNotLiveCount(mid); err != nil {
+							logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
+								Errorf("clear notlive count error %v", err)
 						}
-						continue
-					}
-					if oldInfo.Status == LiveStatus_NoLiving {
-						if newInfo, found := liveInfoMap[mid]; found {
-							// notliving -> living
-							newInfo.liveStatusChanged = true
+						if newInfo.LiveTitle != oldInfo.LiveTitle {
+							// live title change
+							newInfo.liveTitleChanged = true
 							sendLiveInfo(newInfo)
 						}
-					} else if oldInfo.Status == LiveStatus_Living {
-						if newInfo, found := liveInfoMap[mid]; !found {
-							// living -> notliving
-							if count := c.IncNotLiveCount(mid); count < 3 {
-								logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
-									WithField("notlive_count", count).
-									Trace("notlive counting")
-								continue
-							} else {
-								logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
-									Debug("notlive count done, notlive confirmed")
-							}
-							if err := c.ClearNotLiveCount(mid); err != nil {
-								logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
-									Errorf("clear notlive count error %v", err)
-							}
-							//resp, err := XSpaceAccInfo(mid)
-							//if err != nil {
-							//	logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
-							//		Errorf("XSpaceAccInfo error %v", err)
-							//	continue
-							//}
-							//if resp.GetData().GetLiveRoom().GetLiveStatus() == LiveStatus_Living {
-							//	continue
-							//} else {
-							//	logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
-							//		Debug("XSpaceAccInfo notlive confirmed")
-							//}
-							//newInfo = NewLiveInfo(&oldInfo.UserInfo, resp.GetData().GetLiveRoom().GetTitle(),
-							//	resp.GetData().GetLiveRoom().GetCover(), LiveStatus_NoLiving)
-							//newInfo.Name = resp.GetData().GetName()
-							roomId := oldInfo.RoomId
-							resp, err := GetRoomInfo(roomId)
-							if err != nil || resp.Data.Uid != mid {
-								logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
-									WithField("room_id", roomId).Errorf("GetRoomInfo error %v", err)
-								continue
-							}
-							if resp.GetLiveStatus() == LiveStatus_Living {
-								continue
-							} else {
-								logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
-									Debug("XSpaceAccInfo notlive confirmed")
-							}
-							resp2, err := GetPlayTogetherUserAnchorInfoV2(mid)
-							if err != nil {
-								logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
-									WithField("room_id", roomId).Errorf("GetPlayTogetherUserAnchorInfoV2 error %v", err)
-								continue
-							}
-							newInfo = NewLiveInfo(&oldInfo.UserInfo, resp.GetTitle(),
-								resp.GetCover(), LiveStatus_NoLiving, oldInfo.LiveTime)
-							newInfo.SetAreaData(
-								resp.Data.GetAreaId(),
-								resp.Data.GetAreaName(),
-								resp.Data.GetParentAreaId(),
-								resp.Data.GetParentAreaName(),
-							)
-							newInfo.Name = resp2.GetName()
-							newInfo.liveStatusChanged = true
-							sendLiveInfo(newInfo)
-						} else {
-							if newInfo.LiveTitle == "bilibili主播的直播间" {
-								newInfo.LiveTitle = oldInfo.LiveTitle
-							}
-							if err := c.ClearNotLiveCount(mid); err != nil {
-								logger.WithField("uid", mid).WithField("name", oldInfo.UserInfo.Name).
-									Errorf("clear notlive count error %v", err)
-							}
-							if newInfo.LiveTitle != oldInfo.LiveTitle {
-								// live title change
-								newInfo.liveTitleChanged = true
-								sendLiveInfo(newInfo)
-							}
-						}
 					}
 				}
-				return nil
-			})
-			err := errGroup.Wait()
-			freshCount.Inc()
-			end := time.Now()
-			if err == nil {
-				logger.WithField("cost", end.Sub(start)).Tracef("watchCore loop done")
-				c.SetLastFreshTime(time.Now().Unix())
-			} else {
-				logger.WithField("cost", end.Sub(start)).Errorf("watchCore error %v", err)
 			}
-			t.Reset(interval)
+			return nil
+		})
+		err := errGroup.Wait()
+		freshCount.Inc()
+		end := time.Now()
+		if err == nil {
+			logger.WithField("cost", end.Sub(start)).Tracef("watchCore loop done")
+			c.SetLastFreshTime(time.Now().Unix())
+		} else {
+			logger.WithField("cost", end.Sub(start)).Errorf("watchCore error %v", err)
 		}
+		t.Reset(interval)
 	}
 }
 
-func (c *Concern) freshDynamicNew() ([]*NewsInfo, error) {
+func (c *Concern) freshDynamicNew(acc *Account) ([]*NewsInfo, error) {
 	var start = time.Now()
-	resp, err := DynamicSvrDynamicNew()
+	resp, err := acc.DynamicSvrDynamicNew()
 	if err != nil {
 		logger.Errorf("DynamicSvrDynamicNew error %v", err)
 		return nil, err
@@ -281,7 +358,11 @@ func (c *Concern) freshDynamicNew() ([]*NewsInfo, error) {
 	if resp.GetCode() != 0 {
 		logger.WithField("RespCode", resp.GetCode()).
 			WithField("RespMsg", resp.GetMessage()).
+			WithField("account", acc.Name).
 			Errorf("DynamicSvrDynamicNew failed")
+		if isLimitCode(resp.GetCode()) {
+			acc.MarkLimited()
+		}
 		return nil, fmt.Errorf("DynamicSvrDynamicNew failed %v - %v", resp.GetCode(), resp.GetMessage())
 	}
 	var cards []*Card
@@ -294,7 +375,7 @@ func (c *Concern) freshDynamicNew() ([]*NewsInfo, error) {
 			if len(lastDynamicId) == 0 {
 				break
 			}
-			historyResp, err = DynamicSvrDynamicHistory(lastDynamicId)
+			historyResp, err = acc.DynamicSvrDynamicHistory(lastDynamicId)
 			if err != nil {
 				logger.WithField("lastDynamicId", lastDynamicId).
 					Errorf("DynamicSvrDynamicHistory error %v", err)
@@ -317,6 +398,8 @@ func (c *Concern) freshDynamicNew() ([]*NewsInfo, error) {
 		}
 	}
 
+	c.checkDeletedNews(acc, cards)
+
 	logger.WithField("cost", time.Now().Sub(start)).Trace("freshDynamicNew cost 1")
 	for _, card := range cards {
 		uid := card.GetDesc().GetUid()
@@ -354,8 +437,25 @@ func (c *Concern) freshDynamicNew() ([]*NewsInfo, error) {
 	return result, nil
 }
 
+// checkDeletedNews 检测已推送的动态是否被删除，cards 为这次刷新得到的全部动态
+// 动态列表中只有当前账号关注的用户，所以只检测由当前账号负责刷新的用户
+func (c *Concern) checkDeletedNews(acc *Account, cards []*Card) {
+	var since int64
+	var ids []string
+	for _, card := range cards {
+		ids = append(ids, card.GetDesc().GetDynamicIdStr())
+		if ts := card.GetDesc().GetTimestamp(); since == 0 || ts < since {
+			since = ts
+		}
+	}
+	concern.CheckDeletedPost(Site, time.Unix(since, 0), ids, func(id string) bool {
+		mid, err := strconv.ParseInt(id, 10, 64)
+		return err == nil && c.GetAccountShard(mid) == acc.Name
+	})
+}
+
 // return all LiveInfo in LiveStatus_Living
-func (c *Concern) freshLive() ([]*LiveInfo, error) {
+func (c *Concern) freshLive(acc *Account) ([]*LiveInfo, error) {
 	var start = time.Now()
 	var liveInfo []*LiveInfo
 	var infoSet = make(map[int64]bool)
@@ -363,15 +463,24 @@ func (c *Concern) freshLive() ([]*LiveInfo, error) {
 	var maxPage int32 = 1
 	var zeroCount = 0
 	for {
-		resp, err := FeedList(FeedPageOpt(page))
+		resp, err := acc.FeedList(FeedPageOpt(page))
 		if err != nil {
 			logger.Errorf("freshLive FeedList error %v", err)
 			return nil, err
 		} else if resp.GetCode() != 0 {
 			if resp.GetCode() == -101 && strings.Contains(resp.GetMessage(), "未登录") {
-				logger.Errorf("刷新直播列表失败，可能是cookie失效，将尝试重新获取cookie")
-				ClearCookieInfo(username)
-				atomicVerifyInfo.Store(new(VerifyInfo))
+				if acc.IsMain() {
+					logger.Errorf("刷新直播列表失败，可能是cookie失效，将尝试重新获取cookie")
+					ClearCookieInfo(username)
+					atomicVerifyInfo.Store(new(VerifyInfo))
+					reportCookieStatus(CookieExpired, time.Time{})
+				} else {
+					acc.reportCookieStatus(CookieExpired, time.Time{})
+					acc.invalidate()
+				}
+			} else if isLimitCode(resp.GetCode()) {
+				logger.WithField("account", acc.Name).Errorf("刷新直播列表失败，账号可能被风控 %v - %v", resp.GetCode(), resp.GetMessage())
+				acc.MarkLimited()
 			} else if resp.GetCode() == -400 {
 				logger.Errorf("刷新直播列表失败，可能是自动登陆失败，请查看文档尝试手动设置b站cookie")
 			} else {
@@ -420,6 +529,7 @@ func (c *Concern) freshLive() ([]*LiveInfo, error) {
 			if info.Cover == "" {
 				info.Cover = l.GetFace()
 			}
+			info.Online = l.GetOnline()
 			liveInfo = append(liveInfo, info)
 		}
 		if dataSize != 0 {
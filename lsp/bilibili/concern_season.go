@@ -0,0 +1,389 @@
+package bilibili
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
+	"github.com/sirupsen/logrus"
+)
+
+// SeasonSite 番剧、合集和系列不属于某个b站用户，使用 bangumi 单独订阅
+const SeasonSite = "bangumi"
+
+const (
+	// Season 番剧，使用 ss 开头的 season id 或者 md 开头的 media id
+	Season concern_type.Type = "season"
+	// Collection 合集，使用合集的 season id
+	Collection concern_type.Type = "collection"
+	// Series 系列，使用系列的 series id
+	Series concern_type.Type = "series"
+)
+
+// seasonKnownSize 每个订阅记住最近多少个剧集或视频，需要大于每次查看的数量
+const seasonKnownSize = 100
+
+// SeasonInfo 订阅的番剧、合集或者系列，同一个id的不同类型分开保存
+type SeasonInfo struct {
+	Id     string            `json:"id"`
+	Ctype  concern_type.Type `json:"type"`
+	Title  string            `json:"title"`
+	Cover  string            `json:"cover"`
+	Mid    int64             `json:"mid"`
+	UpName string            `json:"up_name"`
+	// SeasonId 番剧使用 md id 订阅时对应的 season id
+	SeasonId int64 `json:"season_id"`
+	// Known 最近已经见过的剧集或视频
+	Known []int64 `json:"known"`
+}
+
+func (s *SeasonInfo) Site() string {
+	return SeasonSite
+}
+
+func (s *SeasonInfo) Type() concern_type.Type {
+	return s.Ctype
+}
+
+func (s *SeasonInfo) GetUid() interface{} {
+	return s.Id
+}
+
+func (s *SeasonInfo) GetName() string {
+	if s == nil {
+		return ""
+	}
+	return s.Title
+}
+
+func (s *SeasonInfo) Logger() *logrus.Entry {
+	return logger.WithFields(logrus.Fields{
+		"Site":  SeasonSite,
+		"Id":    s.Id,
+		"Type":  s.Ctype.String(),
+		"Title": s.Title,
+	})
+}
+
+// remember 记住这些剧集，只保留最近的 seasonKnownSize 个
+func (s *SeasonInfo) remember(episodes []*SeasonEpisode) {
+	var known = make(map[int64]bool)
+	for _, id := range s.Known {
+		known[id] = true
+	}
+	for _, ep := range episodes {
+		if !known[ep.Id] {
+			known[ep.Id] = true
+			s.Known = append(s.Known, ep.Id)
+		}
+	}
+	if len(s.Known) > seasonKnownSize {
+		s.Known = s.Known[len(s.Known)-seasonKnownSize:]
+	}
+}
+
+func (s *SeasonInfo) isKnown(episodeId int64) bool {
+	for _, id := range s.Known {
+		if id == episodeId {
+			return true
+		}
+	}
+	return false
+}
+
+// SeasonNewsInfo 一次刷新中发现的新剧集或视频
+type SeasonNewsInfo struct {
+	*SeasonInfo
+	Episodes []*SeasonEpisode `json:"episodes"`
+}
+
+func (s *SeasonNewsInfo) Logger() *logrus.Entry {
+	return s.SeasonInfo.Logger().WithField("EpisodeSize", len(s.Episodes))
+}
+
+type ConcernSeasonNotify struct {
+	Target mmsg.Target `json:"target"`
+	*SeasonInfo
+	Episode *SeasonEpisode `json:"episode"`
+
+	once     sync.Once
+	msgCache *mmsg.MSG
+}
+
+func (notify *ConcernSeasonNotify) GetTarget() mmsg.Target {
+	return notify.Target
+}
+
+func (notify *ConcernSeasonNotify) Logger() *logrus.Entry {
+	if notify == nil {
+		return logger
+	}
+	return notify.SeasonInfo.Logger().WithFields(mmsg.TargetLogFields(notify.Target)).
+		WithField("EpisodeId", notify.Episode.Id)
+}
+
+func (notify *ConcernSeasonNotify) ToMessage() *mmsg.MSG {
+	notify.once.Do(func() {
+		cover := notify.Episode.Cover
+		if len(cover) == 0 {
+			cover = notify.Cover
+		}
+		var data = map[string]interface{}{
+			"id":    notify.Id,
+			"name":  notify.Title,
+			"up":    notify.UpName,
+			"title": notify.Episode.Title,
+			"cover": cover,
+			"url":   notify.Episode.Url,
+		}
+		var err error
+		notify.msgCache, err = template.ExecNotify(template.NotifyLocale(notify.Target),
+			"notify.group."+SeasonSite+"."+notify.Type().String()+".tmpl", data)
+		if err != nil {
+			logger.Errorf("bilibili: ConcernSeasonNotify LoadAndExec error %v", err)
+		}
+	})
+	return notify.msgCache
+}
+
+func NewConcernSeasonNotify(target mmsg.Target, info *SeasonNewsInfo) []*ConcernSeasonNotify {
+	var result []*ConcernSeasonNotify
+	for _, ep := range info.Episodes {
+		result = append(result, &ConcernSeasonNotify{
+			Target:     target,
+			SeasonInfo: info.SeasonInfo,
+			Episode:    ep,
+		})
+	}
+	return result
+}
+
+type SeasonConcern struct {
+	*SeasonStateManager
+}
+
+func (c *SeasonConcern) Site() string {
+	return SeasonSite
+}
+
+func (c *SeasonConcern) Types() []concern_type.Type {
+	return []concern_type.Type{Season, Collection, Series}
+}
+
+func (c *SeasonConcern) ParseId(s string) (interface{}, error) {
+	return ParseSeasonId(s)
+}
+
+func (c *SeasonConcern) GetStateManager() concern.IStateManager {
+	return c.SeasonStateManager
+}
+
+func (c *SeasonConcern) Start() error {
+	c.UseEmitQueue()
+	c.StateManager.UseFreshFunc(c.EmitQueueFresher(func(p concern_type.Type, id interface{}) ([]concern.Event, error) {
+		var result []concern.Event
+		for _, ctype := range c.Types() {
+			if !p.ContainAny(ctype) {
+				continue
+			}
+			newsInfo, err := c.freshSeason(ctype, id.(string))
+			if err != nil {
+				return nil, err
+			}
+			if len(newsInfo.Episodes) > 0 {
+				result = append(result, newsInfo)
+			}
+		}
+		return result, nil
+	}))
+	c.StateManager.UseNotifyGeneratorFunc(c.notifyGenerator())
+	return c.StateManager.Start()
+}
+
+func (c *SeasonConcern) Stop() {
+	logger.Tracef("正在停止%v concern", SeasonSite)
+	c.StateManager.Stop()
+	logger.Tracef("%v concern已停止", SeasonSite)
+}
+
+func (c *SeasonConcern) Add(ctx mmsg.IMsgCtx, target mmsg.Target, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+	id := _id.(string)
+	log := logger.WithFields(mmsg.TargetLogFields(target)).WithField("id", id).WithField("Type", ctype.String())
+
+	if prefix, _, _ := seasonNumericId(id); len(prefix) > 0 && ctype != Season {
+		return nil, errors.New("合集和系列的id只能是数字")
+	}
+	err := c.StateManager.CheckGroupConcern(target, id, ctype)
+	if err != nil {
+		return nil, err
+	}
+	info, err := c.GetSeasonInfo(ctype, id)
+	if err != nil {
+		// 记住订阅时已有的剧集或视频，之后只推送新出现的
+		info = &SeasonInfo{Id: id, Ctype: ctype}
+		episodes, err := c.fetchSeason(info)
+		if err == ErrSeasonNotExist {
+			return nil, fmt.Errorf("添加订阅失败 - %v %v 不存在", ctype.String(), id)
+		} else if err != nil {
+			log.Errorf("fetchSeason error %v", err)
+			return nil, fmt.Errorf("添加订阅失败 - 查询信息失败")
+		}
+		info.remember(episodes)
+		if err = c.AddSeasonInfo(info); err != nil {
+			log.Errorf("AddSeasonInfo error %v", err)
+			return nil, fmt.Errorf("添加订阅失败 - 内部错误")
+		}
+	}
+	_, err = c.StateManager.AddGroupConcern(target, id, ctype)
+	if err != nil {
+		return nil, err
+	}
+	return concern.NewIdentity(id, info.Title), nil
+}
+
+func (c *SeasonConcern) Remove(ctx mmsg.IMsgCtx, target mmsg.Target, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+	id := _id.(string)
+	var identity concern.IdentityInfo = concern.NewIdentity(id, "unknown")
+	if info, err := c.GetSeasonInfo(ctype, id); err == nil {
+		identity = concern.NewIdentity(id, info.Title)
+	}
+	_, err := c.StateManager.RemoveGroupConcern(target, id, ctype)
+	if err != nil {
+		return identity, err
+	}
+	if r, _ := c.GetConcern(id); !r.ContainAny(ctype) {
+		if err := c.RemoveSeasonInfo(ctype, id); err != nil {
+			logger.Errorf("RemoveSeasonInfo error %v", err)
+		}
+	}
+	return identity, nil
+}
+
+func (c *SeasonConcern) Get(id interface{}) (concern.IdentityInfo, error) {
+	var err error
+	for _, ctype := range c.Types() {
+		var info *SeasonInfo
+		info, err = c.GetSeasonInfo(ctype, id.(string))
+		if err == nil {
+			return concern.NewIdentity(info.Id, info.Title), nil
+		}
+	}
+	return nil, err
+}
+
+// fetchSeason 查询最近的剧集或视频，从旧到新排列，同时更新 info 中的标题等信息
+func (c *SeasonConcern) fetchSeason(info *SeasonInfo) ([]*SeasonEpisode, error) {
+	prefix, n, err := seasonNumericId(info.Id)
+	if err != nil {
+		return nil, err
+	}
+	var meta *SeasonMeta
+	var episodes []*SeasonEpisode
+	switch info.Ctype {
+	case Season:
+		if info.SeasonId == 0 {
+			info.SeasonId = n
+			if prefix == "md" {
+				info.SeasonId, err = PgcMediaSeasonId(n)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		meta, episodes, err = PgcSeason(info.SeasonId)
+		// 番剧会返回所有剧集，只看最近的部分
+		if len(episodes) > seasonArchivesPageSize {
+			episodes = episodes[len(episodes)-seasonArchivesPageSize:]
+		}
+	case Collection:
+		if info.Mid == 0 {
+			if meta, err = CollectionMeta(n); err != nil {
+				return nil, err
+			}
+			info.Mid = meta.Mid
+		}
+		episodes, err = CollectionArchives(info.Mid, n)
+	case Series:
+		if info.Mid == 0 {
+			if meta, err = SeriesMeta(n); err != nil {
+				return nil, err
+			}
+			info.Mid = meta.Mid
+		}
+		episodes, err = SeriesArchives(info.Mid, n)
+	default:
+		return nil, fmt.Errorf("unknown type %v", info.Ctype)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if meta != nil {
+		info.Title = meta.Title
+		info.Cover = meta.Cover
+		if len(meta.UpName) > 0 {
+			info.UpName = meta.UpName
+		}
+	}
+	return episodes, nil
+}
+
+func (c *SeasonConcern) freshSeason(ctype concern_type.Type, id string) (*SeasonNewsInfo, error) {
+	log := logger.WithField("id", id).WithField("Type", ctype.String())
+	var first bool
+	info, err := c.GetSeasonInfo(ctype, id)
+	if err != nil {
+		info = &SeasonInfo{Id: id, Ctype: ctype}
+		first = true
+	}
+	episodes, err := c.fetchSeason(info)
+	if err != nil {
+		log.Errorf("fetchSeason error %v", err)
+		return nil, err
+	}
+	var newsInfo = &SeasonNewsInfo{SeasonInfo: info}
+	if !first {
+		// 合集和系列可以加入以前发布的视频，所以不按发布时间筛选，只看是否见过
+		newsInfo.Episodes, _ = concern.FilterNews(episodes, 0, nil, func(ep *SeasonEpisode) bool {
+			if info.isKnown(ep.Id) {
+				return false
+			}
+			replaced, err := c.MarkEpisode(ctype, id, ep.Id)
+			if err != nil {
+				log.WithField("EpisodeId", ep.Id).Errorf("MarkEpisode error %v", err)
+				return false
+			}
+			return !replaced
+		})
+	}
+	info.remember(episodes)
+	if err = c.AddSeasonInfo(info); err != nil {
+		log.Errorf("AddSeasonInfo error %v", err)
+		return nil, err
+	}
+	return newsInfo, nil
+}
+
+func (c *SeasonConcern) notifyGenerator() concern.NotifyGeneratorFunc {
+	return func(target mmsg.Target, ievent concern.Event) []concern.Notify {
+		var result []concern.Notify
+		switch news := ievent.(type) {
+		case *SeasonNewsInfo:
+			for _, n := range NewConcernSeasonNotify(target, news) {
+				result = append(result, n)
+			}
+		default:
+			logger.Errorf("unknown EventType %+v", ievent)
+		}
+		return result
+	}
+}
+
+func NewSeasonConcern(notify chan<- concern.Notify) *SeasonConcern {
+	return &SeasonConcern{
+		SeasonStateManager: NewSeasonStateManager(notify),
+	}
+}
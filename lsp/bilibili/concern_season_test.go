@@ -0,0 +1,133 @@
+package bilibili
+
+import (
+	"context"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"testing"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeasonInfo_remember(t *testing.T) {
+	info := &SeasonInfo{Known: []int64{1, 2}}
+	info.remember([]*SeasonEpisode{{Id: 2}, {Id: 3}})
+	assert.Equal(t, []int64{1, 2, 3}, info.Known)
+	assert.True(t, info.isKnown(3))
+	assert.False(t, info.isKnown(4))
+
+	var episodes []*SeasonEpisode
+	for i := 0; i < seasonKnownSize; i++ {
+		episodes = append(episodes, &SeasonEpisode{Id: int64(100 + i)})
+	}
+	info.remember(episodes)
+	assert.Len(t, info.Known, seasonKnownSize)
+	assert.EqualValues(t, 100, info.Known[0])
+}
+
+type seasonTypeName struct {
+	Type concern_type.Type
+	Name string
+}
+
+func TestConcernSeasonNotify(t *testing.T) {
+	for _, ctype := range []seasonTypeName{
+		{Season, "番剧"},
+		{Collection, "合集"},
+		{Series, "系列"},
+	} {
+		info := &SeasonNewsInfo{
+			SeasonInfo: &SeasonInfo{Id: "123", Ctype: ctype.Type, Title: test.NAME1, UpName: test.NAME2},
+			Episodes:   []*SeasonEpisode{{Id: 1, Title: "第1话", Url: BVIDUrl("BV1a")}},
+		}
+		assert.Equal(t, SeasonSite, info.Site())
+		assert.Equal(t, ctype.Type, info.Type())
+		assert.NotNil(t, info.Logger())
+		notifies := NewConcernSeasonNotify(mmsg.NewGroupTarget(test.G1), info)
+		require.Len(t, notifies, 1)
+		notify := notifies[0]
+		assert.Equal(t, mmsg.NewGroupTarget(test.G1), notify.GetTarget())
+		assert.Equal(t, "123", notify.GetUid())
+		assert.NotNil(t, notify.Logger())
+		s := msgstringer.MsgToString(notify.ToMessage().Elements())
+		assert.Contains(t, s, ctype.Name)
+		assert.Contains(t, s, test.NAME1)
+		assert.Contains(t, s, "第1话")
+		assert.Contains(t, s, BVIDUrl("BV1a"))
+	}
+}
+
+func TestSeasonConcern(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	testEventChan := make(chan concern.Event, 16)
+	testNotifyChan := make(chan concern.Notify)
+
+	c := NewSeasonConcern(testNotifyChan)
+	assert.Equal(t, SeasonSite, c.Site())
+	assert.NotNil(t, c.GetStateManager())
+
+	id, err := c.ParseId("md28339913")
+	assert.Nil(t, err)
+	assert.Equal(t, "md28339913", id)
+
+	c.StateManager.UseNotifyGeneratorFunc(c.notifyGenerator())
+	c.StateManager.UseFreshFunc(func(ctx context.Context, eventChan chan<- concern.Event) {
+		for {
+			select {
+			case e := <-testEventChan:
+				if e != nil {
+					eventChan <- e
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+	assert.Nil(t, c.StateManager.Start())
+	defer c.Stop()
+	defer close(testEventChan)
+
+	_, err = c.Add(nil, mmsg.NewGroupTarget(test.G1), "md28339913", Collection)
+	assert.NotNil(t, err)
+
+	// 已经保存过信息时不需要查询
+	info := &SeasonInfo{Id: "123", Ctype: Collection, Title: test.NAME1, Mid: test.UID1, Known: []int64{1}}
+	assert.Nil(t, c.AddSeasonInfo(info))
+	identity, err := c.Add(nil, mmsg.NewGroupTarget(test.G1), "123", Collection)
+	assert.Nil(t, err)
+	assert.Equal(t, test.NAME1, identity.GetName())
+
+	identity, err = c.Get("123")
+	assert.Nil(t, err)
+	assert.Equal(t, test.NAME1, identity.GetName())
+
+	replaced, err := c.MarkEpisode(Collection, "123", 2)
+	assert.Nil(t, err)
+	assert.False(t, replaced)
+	replaced, err = c.MarkEpisode(Collection, "123", 2)
+	assert.Nil(t, err)
+	assert.True(t, replaced)
+
+	testEventChan <- &SeasonNewsInfo{SeasonInfo: info, Episodes: []*SeasonEpisode{{Id: 2, Title: "new"}}}
+	select {
+	case notify := <-testNotifyChan:
+		assert.Equal(t, mmsg.NewGroupTarget(test.G1), notify.GetTarget())
+		assert.Equal(t, "123", notify.GetUid())
+		assert.Equal(t, Collection, notify.Type())
+	case <-time.After(time.Second):
+		assert.Fail(t, "no notify received")
+	}
+
+	identity, err = c.Remove(nil, mmsg.NewGroupTarget(test.G1), "123", Collection)
+	assert.Nil(t, err)
+	assert.Equal(t, test.NAME1, identity.GetName())
+	_, err = c.GetSeasonInfo(Collection, "123")
+	assert.NotNil(t, err)
+}
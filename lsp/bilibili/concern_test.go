@@ -2,6 +2,7 @@ package bilibili
 
 import (
 	"context"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"testing"
 	"time"
 
@@ -14,7 +15,7 @@ import (
 func initConcern(t *testing.T) *Concern {
 	c := NewConcern(nil)
 	assert.NotNil(t, c)
-	c.StateManager.FreshIndex(test.G1, test.G2)
+	c.StateManager.FreshIndex(mmsg.NewGroupTarget(test.G1), mmsg.NewGroupTarget(test.G2))
 	return c
 }
 
@@ -43,10 +44,10 @@ func TestConcern_Remove(t *testing.T) {
 
 	origUserInfo := NewUserInfo(test.UID1, test.ROOMID1, test.NAME1, "")
 	assert.NotNil(t, origUserInfo)
-	_, err := c.AddGroupConcern(test.G1, test.UID1, test.BibiliLive)
+	_, err := c.AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.BibiliLive)
 	assert.Nil(t, err)
 
-	_, err = c.Remove(nil, test.G1, test.UID1, test.BibiliLive)
+	_, err = c.Remove(nil, mmsg.NewGroupTarget(test.G1), test.UID1, test.BibiliLive)
 	assert.Nil(t, err)
 }
 
@@ -170,9 +171,9 @@ func TestConcernNotify(t *testing.T) {
 	defer c.Stop()
 	defer close(testEventChan)
 
-	_, err := c.StateManager.AddGroupConcern(test.G1, test.UID1, Live.Add(News))
+	_, err := c.StateManager.AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, Live.Add(News))
 	assert.Nil(t, err)
-	_, err = c.StateManager.AddGroupConcern(test.G2, test.UID1, News)
+	_, err = c.StateManager.AddGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID1, News)
 	assert.Nil(t, err)
 
 	origUserInfo := NewUserInfo(test.UID1, test.ROOMID1, test.NAME1, "")
@@ -189,7 +190,7 @@ func TestConcernNotify(t *testing.T) {
 	case notify := <-testNotifyChan:
 		assert.NotNil(t, notify)
 		assert.EqualValues(t, test.UID1, notify.GetUid())
-		assert.EqualValues(t, test.G1, notify.GetGroupCode())
+		assert.Equal(t, mmsg.NewGroupTarget(test.G1), notify.GetTarget())
 		assert.Contains(t, msgstringer.MsgToString(notify.ToMessage().Elements()), "mytitle")
 	case <-time.After(time.Second):
 		assert.Fail(t, "no item received")
@@ -207,7 +208,7 @@ func TestConcernNotify(t *testing.T) {
 		case notify := <-testNotifyChan:
 			assert.NotNil(t, notify)
 			assert.EqualValues(t, test.UID1, notify.GetUid())
-			assert.True(t, notify.GetGroupCode() == test.G1 || notify.GetGroupCode() == test.G2)
+			assert.True(t, notify.GetTarget().TargetCode() == test.G1 || notify.GetTarget().TargetCode() == test.G2)
 		case <-time.After(time.Second):
 			assert.Fail(t, "no item received")
 		}
@@ -239,7 +240,7 @@ func TestConcern_GroupWatchNotify(t *testing.T) {
 	defer c.Stop()
 	defer close(testEventChan)
 
-	_, err := c.StateManager.AddGroupConcern(test.G1, test.UID1, Live.Add(News))
+	_, err := c.StateManager.AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, Live.Add(News))
 	assert.Nil(t, err)
 
 	origUserInfo := NewUserInfo(test.UID1, test.ROOMID1, test.NAME1, "")
@@ -248,12 +249,12 @@ func TestConcern_GroupWatchNotify(t *testing.T) {
 
 	assert.Nil(t, c.AddLiveInfo(origLiveInfo))
 
-	go c.GroupWatchNotify(test.G2, test.UID1)
+	go c.GroupWatchNotify(mmsg.NewGroupTarget(test.G2), test.UID1)
 	select {
 	case notify := <-testNotifyChan:
 		assert.NotNil(t, notify)
 		assert.EqualValues(t, test.UID1, notify.GetUid())
-		assert.EqualValues(t, test.G2, notify.GetGroupCode())
+		assert.Equal(t, mmsg.NewGroupTarget(test.G2), notify.GetTarget())
 	case <-time.After(time.Second):
 		assert.Fail(t, "no item received")
 	}
@@ -41,21 +41,21 @@ func (g *GroupConcernConfig) NotifyBeforeCallback(inotify concern.Notify) {
 	case DynamicDescType_WithVideo:
 		// 解决联合投稿的时候刷屏
 		notify.compactKey = notify.Card.GetDesc().GetBvid()
-		err := g.concern.SetGroupCompactMarkIfNotExist(notify.GetGroupCode(), notify.compactKey)
+		err := g.concern.SetGroupCompactMarkIfNotExist(notify.GetTarget(), notify.compactKey)
 		if localdb.IsRollback(err) {
 			notify.shouldCompact = true
 		}
 	case DynamicDescType_WithOrigin:
 		// 解决一起转发的时候刷屏
 		notify.compactKey = notify.Card.GetDesc().GetOrigDyIdStr()
-		err := g.concern.SetGroupCompactMarkIfNotExist(notify.GetGroupCode(), notify.compactKey)
+		err := g.concern.SetGroupCompactMarkIfNotExist(notify.GetTarget(), notify.compactKey)
 		if localdb.IsRollback(err) {
 			notify.shouldCompact = true
 		}
 	default:
 		// 其他动态也设置一下
 		notify.compactKey = notify.Card.GetDesc().GetDynamicIdStr()
-		err := g.concern.SetGroupCompactMarkIfNotExist(notify.GetGroupCode(), notify.Card.GetDesc().GetDynamicIdStr())
+		err := g.concern.SetGroupCompactMarkIfNotExist(notify.GetTarget(), notify.Card.GetDesc().GetDynamicIdStr())
 		if err != nil && !localdb.IsRollback(err) {
 			logger.Errorf("SetGroupOriginMarkIfNotExist error %v", err)
 		}
@@ -88,7 +88,7 @@ func (g *GroupConcernConfig) AtBeforeHook(notify concern.Notify) (hook *concern.
 func (g *GroupConcernConfig) FilterHook(notify concern.Notify) (hook *concern.HookResult) {
 	hook = new(concern.HookResult)
 	switch n := notify.(type) {
-	case *ConcernLiveNotify:
+	case *ConcernLiveNotify, *ConcernDanmakuNotify:
 		hook.Pass = true
 		return
 	case *ConcernNewsNotify:
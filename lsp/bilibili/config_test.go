@@ -6,6 +6,7 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/internal/test"
 	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/stretchr/testify/assert"
 	"strconv"
 	"testing"
@@ -34,6 +35,7 @@ func newNewsInfo(uid int64, cardTypes ...DynamicDescType) []*ConcernNewsNotify {
 	var result []*ConcernNewsNotify
 	for _, t := range cardTypes {
 		notify := &ConcernNewsNotify{
+			Target: mmsg.NewGroupTarget(test.G1),
 			UserInfo: &UserInfo{
 				Mid: uid,
 			},
@@ -55,7 +57,7 @@ func TestNewGroupConcernConfig(t *testing.T) {
 
 	c := initConcern(t)
 
-	g := c.GetGroupConcernConfig(test.G1, test.UID1)
+	g := c.GetGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1)
 
 	assert.NotNil(t, g)
 	assert.Nil(t, g.Validate())
@@ -70,16 +72,16 @@ func TestNewGroupConcernConfig(t *testing.T) {
 	g.GetGroupConcernFilter().Type = ""
 	assert.Nil(t, g.Validate())
 
-	g = c.GetGroupConcernConfig(test.G1, test.UID1)
-	err := c.OperateGroupConcernConfig(test.G1, test.UID1, g, func(concernConfig concern.IConfig) bool {
+	g = c.GetGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1)
+	err := c.OperateGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1, g, func(concernConfig concern.IConfig) bool {
 		concernConfig.GetGroupConcernFilter().Type = concern.FilterTypeNotType
 		concernConfig.GetGroupConcernFilter().Config = (&concern.GroupConcernFilterConfigByType{Type: []string{"wrong"}}).ToString()
 		return true
 	})
 	assert.NotNil(t, err)
 
-	g = c.GetGroupConcernConfig(test.G1, test.UID1)
-	err = c.OperateGroupConcernConfig(test.G1, test.UID1, g, func(concernConfig concern.IConfig) bool {
+	g = c.GetGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1)
+	err = c.OperateGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1, g, func(concernConfig concern.IConfig) bool {
 		concernConfig.GetGroupConcernFilter().Type = concern.FilterTypeNotType
 		concernConfig.GetGroupConcernFilter().Config = (&concern.GroupConcernFilterConfigByType{Type: []string{Tougao}}).ToString()
 		return true
@@ -312,7 +314,7 @@ func TestGroupConcernConfig_NotifyBeforeCallback(t *testing.T) {
 
 	c := initConcern(t)
 
-	_, err := c.GetNotifyMsg(test.G1, test.BVID1)
+	_, err := c.GetNotifyMsg(mmsg.NewGroupTarget(test.G1), test.BVID1)
 	assert.True(t, localdb.IsNotFound(err))
 
 	var notify = newNewsInfo(test.UID1, DynamicDescType_WithOrigin)[0]
@@ -351,7 +353,7 @@ func TestGroupConcernConfig_NotifyAfterCallback(t *testing.T) {
 
 	c := initConcern(t)
 
-	_, err := c.GetNotifyMsg(test.G1, test.BVID1)
+	_, err := c.GetNotifyMsg(mmsg.NewGroupTarget(test.G1), test.BVID1)
 	assert.True(t, localdb.IsNotFound(err))
 
 	var notify = newNewsInfo(test.UID1, DynamicDescType_WithOrigin)[0]
@@ -368,7 +370,7 @@ func TestGroupConcernConfig_NotifyAfterCallback(t *testing.T) {
 
 	g.NotifyAfterCallback(notify, msg)
 
-	msg2, err := c.GetNotifyMsg(test.G1, test.BVID1)
+	msg2, err := c.GetNotifyMsg(mmsg.NewGroupTarget(test.G1), test.BVID1)
 	assert.Nil(t, err)
 	assert.EqualValues(t, msg, msg2)
 
@@ -0,0 +1,199 @@
+package bilibili
+
+import (
+	"context"
+	"github.com/Sora233/MiraiGo-Template/config"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/eventbus"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/interfaces"
+	"go.uber.org/atomic"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TopicLogin 扫码登录成功后通过eventbus发布，消息为当前账号的UID
+const TopicLogin = "bilibili_login"
+
+// defaultCookieExpireAhead cookie在过期前多久开始提醒
+const defaultCookieExpireAhead = time.Hour * 72
+
+type CookieStatus int32
+
+const (
+	CookieNotGiven CookieStatus = iota
+	CookieValid
+	CookieExpiring
+	CookieExpired
+)
+
+func (s CookieStatus) String() string {
+	switch s {
+	case CookieValid:
+		return "有效"
+	case CookieExpiring:
+		return "即将过期"
+	case CookieExpired:
+		return "已过期"
+	default:
+		return "未设置"
+	}
+}
+
+var lastCookieStatus atomic.Int32
+
+// ParseSESSDATAExpire 解析SESSDATA中携带的过期时间
+// SESSDATA的格式为 xxx%2C<过期时间戳>%2Cxxx
+func ParseSESSDATAExpire(sessdata string) (time.Time, bool) {
+	s, err := url.QueryUnescape(sessdata)
+	if err != nil {
+		s = sessdata
+	}
+	parts := strings.Split(s, ",")
+	if len(parts) < 2 {
+		return time.Time{}, false
+	}
+	ts, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || ts <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(ts, 0), true
+}
+
+func cookieStatusAt(expire time.Time, now time.Time, ahead time.Duration) CookieStatus {
+	if expire.IsZero() {
+		return CookieValid
+	}
+	if !now.Before(expire) {
+		return CookieExpired
+	}
+	if expire.Sub(now) <= ahead {
+		return CookieExpiring
+	}
+	return CookieValid
+}
+
+func getCookieExpireAhead() time.Duration {
+	var ahead time.Duration
+	if config.GlobalConfig != nil {
+		ahead = config.GlobalConfig.GetDuration("bilibili.cookieExpireNotify")
+	}
+	if ahead <= 0 {
+		ahead = defaultCookieExpireAhead
+	}
+	return ahead
+}
+
+// CheckCookieStatus 检查当前cookie的状态，
+// 优先使用SESSDATA中的过期时间判断，未过期时再通过nav接口确认是否仍然处于登录状态
+func CheckCookieStatus() (CookieStatus, time.Time) {
+	info := getVerify()
+	if info == nil || len(info.SESSDATA) == 0 {
+		return CookieNotGiven, time.Time{}
+	}
+	expire, _ := ParseSESSDATAExpire(info.SESSDATA)
+	status := cookieStatusAt(expire, time.Now(), getCookieExpireAhead())
+	if status == CookieExpired {
+		return status, expire
+	}
+	navResp, err := XWebInterfaceNav(true)
+	return checkNavStatus(mainAccount, navResp, err, status, expire)
+}
+
+// CheckCookieStatus 检查账号cookie的状态，检查时会同时刷新账号的UID
+func (a *Account) CheckCookieStatus() (CookieStatus, time.Time) {
+	if a.IsMain() {
+		return CheckCookieStatus()
+	}
+	info := a.verify.Load()
+	if info == nil || len(info.SESSDATA) == 0 {
+		return CookieNotGiven, time.Time{}
+	}
+	expire, _ := ParseSESSDATAExpire(info.SESSDATA)
+	status := cookieStatusAt(expire, time.Now(), getCookieExpireAhead())
+	if status == CookieExpired {
+		return status, expire
+	}
+	navResp, err := a.XWebInterfaceNav()
+	return checkNavStatus(a, navResp, err, status, expire)
+}
+
+// checkNavStatus nav接口返回未登录时cookie已失效，仍然处于登录状态时更新账号的UID
+func checkNavStatus(a *Account, navResp *WebInterfaceNavResponse, err error, status CookieStatus, expire time.Time) (CookieStatus, time.Time) {
+	if err != nil {
+		return status, expire
+	}
+	if navResp.GetCode() == -101 {
+		return CookieExpired, expire
+	}
+	if navResp.GetCode() == 0 && navResp.GetData().GetIsLogin() {
+		a.uid.Store(navResp.GetData().GetMid())
+	}
+	return status, expire
+}
+
+// reportCookieStatus 主账号cookie变为即将过期或已过期时通过eventbus通知
+func reportCookieStatus(status CookieStatus, expire time.Time) {
+	mainAccount.reportCookieStatus(status, expire)
+}
+
+// reportCookieStatus cookie变为即将过期或已过期时通过eventbus通知，同一个状态只通知一次
+func (a *Account) reportCookieStatus(status CookieStatus, expire time.Time) {
+	if status == CookieNotGiven {
+		return
+	}
+	if CookieStatus(a.cookieStatus.Swap(int32(status))) == status {
+		return
+	}
+	if status != CookieExpiring && status != CookieExpired {
+		return
+	}
+	log := logger.WithField("account", a.Name).WithField("expire", expire)
+	if a.IsMain() {
+		log.Warnf("B站cookie%v，请使用扫码登录更新cookie", status)
+	} else {
+		log.Warnf("B站cookie%v，请更新配置中该账号的cookie", status)
+	}
+	eventbus.BusObj.Publish(interfaces.TopicLoginStatus, a.loginStatus(status, expire))
+}
+
+func (a *Account) loginStatus(status CookieStatus, expire time.Time) *interfaces.LoginStatus {
+	result := &interfaces.LoginStatus{
+		Site:    Site,
+		Status:  status.String(),
+		Expired: status == CookieExpired,
+		Expire:  expire,
+		Uid:     a.Uid(),
+	}
+	if !a.IsMain() {
+		result.Account = a.Name
+	}
+	return result
+}
+
+// checkCookie 检查所有账号的cookie，额外配置的账号过期后不再使用
+func (c *Concern) checkCookie() {
+	for _, a := range Accounts() {
+		if a.IsMain() && !IsCookieGiven() {
+			continue
+		}
+		status, expire := a.CheckCookieStatus()
+		a.reportCookieStatus(status, expire)
+		if status == CookieExpired {
+			a.invalidate()
+		}
+	}
+}
+
+// QRLogin 实现 interfaces.QRLoginProvider
+func (c *Concern) QRLogin(ctx context.Context, onResult func(err error)) ([]byte, error) {
+	return QRLogin(ctx, onResult)
+}
+
+// LoginStatus 实现 interfaces.QRLoginProvider
+func (c *Concern) LoginStatus() *interfaces.LoginStatus {
+	status := mainAccount.loginStatus(CheckCookieStatus())
+	// 慢速模式启动时没有同步订阅，需要重启后才会使用账号刷新
+	status.RestartRequired = c.EmitQueueEnabled()
+	return status
+}
@@ -0,0 +1,129 @@
+package bilibili
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/eventbus"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSESSDATAExpire(t *testing.T) {
+	expire, ok := ParseSESSDATAExpire("3a1b2c4d%2C1767225600%2Cab12c%2A41")
+	assert.True(t, ok)
+	assert.EqualValues(t, 1767225600, expire.Unix())
+
+	expire, ok = ParseSESSDATAExpire("3a1b2c4d,1767225600,ab12c*41")
+	assert.True(t, ok)
+	assert.EqualValues(t, 1767225600, expire.Unix())
+
+	_, ok = ParseSESSDATAExpire("wrong")
+	assert.False(t, ok)
+
+	_, ok = ParseSESSDATAExpire("a%2Cb%2Cc")
+	assert.False(t, ok)
+}
+
+func TestCookieStatusAt(t *testing.T) {
+	var now = time.Now()
+	var ahead = time.Hour * 24
+	assert.Equal(t, CookieValid, cookieStatusAt(time.Time{}, now, ahead))
+	assert.Equal(t, CookieValid, cookieStatusAt(now.Add(ahead*2), now, ahead))
+	assert.Equal(t, CookieExpiring, cookieStatusAt(now.Add(time.Hour), now, ahead))
+	assert.Equal(t, CookieExpired, cookieStatusAt(now, now, ahead))
+	assert.Equal(t, CookieExpired, cookieStatusAt(now.Add(-time.Hour), now, ahead))
+}
+
+func TestReportCookieStatus(t *testing.T) {
+	defer lastCookieStatus.Store(0)
+	ch := eventbus.BusObj.Subscribe(interfaces.TopicLoginStatus)
+
+	reportCookieStatus(CookieValid, time.Time{})
+	select {
+	case <-ch:
+		assert.Fail(t, "valid cookie should not notify")
+	default:
+	}
+
+	expire := time.Now().Add(time.Hour)
+	reportCookieStatus(CookieExpiring, expire)
+	select {
+	case msg := <-ch:
+		status, ok := msg.(*interfaces.LoginStatus)
+		assert.True(t, ok)
+		assert.Equal(t, Site, status.Site)
+		assert.False(t, status.Expired)
+		assert.Equal(t, expire, status.Expire)
+	default:
+		assert.Fail(t, "expiring cookie should notify")
+	}
+
+	// 同一个状态只通知一次
+	reportCookieStatus(CookieExpiring, expire)
+	select {
+	case <-ch:
+		assert.Fail(t, "duplicate status should not notify")
+	default:
+	}
+
+	reportCookieStatus(CookieExpired, time.Time{})
+	select {
+	case msg := <-ch:
+		assert.True(t, msg.(*interfaces.LoginStatus).Expired)
+	default:
+		assert.Fail(t, "expired cookie should notify")
+	}
+}
+
+func TestAccountReportCookieStatus(t *testing.T) {
+	defer lastCookieStatus.Store(0)
+	ch := eventbus.BusObj.Subscribe(interfaces.TopicLoginStatus)
+
+	a := newAccount("a", "SESSDATA", "bili_jct")
+	a.uid.Store(1)
+	a.reportCookieStatus(CookieExpired, time.Time{})
+	select {
+	case msg := <-ch:
+		status := msg.(*interfaces.LoginStatus)
+		assert.Equal(t, "a", status.Account)
+		assert.True(t, status.Expired)
+		assert.EqualValues(t, 1, status.Uid)
+	default:
+		assert.Fail(t, "expired cookie should notify")
+	}
+
+	// 每个账号单独记录状态
+	reportCookieStatus(CookieExpired, time.Time{})
+	select {
+	case msg := <-ch:
+		assert.Empty(t, msg.(*interfaces.LoginStatus).Account)
+	default:
+		assert.Fail(t, "main account should notify")
+	}
+}
+
+func TestCheckNavStatus(t *testing.T) {
+	a := newAccount("a", "SESSDATA", "bili_jct")
+
+	status, _ := checkNavStatus(a, &WebInterfaceNavResponse{Code: -101}, nil, CookieValid, time.Time{})
+	assert.Equal(t, CookieExpired, status)
+
+	// 检查时刷新UID
+	status, _ = checkNavStatus(a, &WebInterfaceNavResponse{
+		Data: &WebInterfaceNavResponse_Data{IsLogin: true, Mid: 2},
+	}, nil, CookieValid, time.Time{})
+	assert.Equal(t, CookieValid, status)
+	assert.EqualValues(t, 2, a.Uid())
+}
+
+func TestApplyCookies(t *testing.T) {
+	assert.NotNil(t, ApplyCookies(nil))
+	assert.NotNil(t, ApplyCookies(&BiliCookies{SESSDATA: "a"}))
+}
+
+func TestQRCodePNG(t *testing.T) {
+	png, err := QRCodePNG("https://passport.bilibili.com/h5-app/passport/login/scan?qrcode_key=test")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, png)
+}
@@ -0,0 +1,482 @@
+package bilibili
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
+	"github.com/cnxysoft/DDBOT-WSa/requests"
+	"github.com/gorilla/websocket"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// b站直播弹幕 websocket 协议，每个包有16字节的包头，body可能是 zlib 或 brotli 压缩的多个包
+const (
+	PathGetDanmuInfo = "/xlive/web-room/v1/index/getDanmuInfo"
+
+	DefaultDanmakuServer = "wss://broadcastlv.chat.bilibili.com/sub"
+
+	danmakuHeaderLen = 16
+
+	danmakuProtoJson   = 0
+	danmakuProtoInt    = 1
+	danmakuProtoZlib   = 2
+	danmakuProtoBrotli = 3
+
+	danmakuOpHeartbeat      = 2
+	danmakuOpHeartbeatReply = 3
+	danmakuOpMessage        = 5
+	danmakuOpAuth           = 7
+	danmakuOpAuthReply      = 8
+
+	danmakuHeartbeatInterval = time.Second * 30
+	// 断线重连的等待时间从 danmakuRetryBase 开始翻倍，最多 danmakuRetryMax
+	danmakuRetryBase = time.Second * 5
+	danmakuRetryMax  = time.Minute * 5
+)
+
+type danmakuPacket struct {
+	Proto int
+	Op    int
+	Body  []byte
+}
+
+func encodeDanmakuPacket(proto, op int, body []byte) []byte {
+	buf := make([]byte, danmakuHeaderLen+len(body))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(buf)))
+	binary.BigEndian.PutUint16(buf[4:6], danmakuHeaderLen)
+	binary.BigEndian.PutUint16(buf[6:8], uint16(proto))
+	binary.BigEndian.PutUint32(buf[8:12], uint32(op))
+	binary.BigEndian.PutUint32(buf[12:16], 1)
+	copy(buf[danmakuHeaderLen:], body)
+	return buf
+}
+
+// decodeDanmakuPacket 拆分一条 websocket 消息中的所有包，压缩的包会解压后继续拆分
+func decodeDanmakuPacket(data []byte) ([]*danmakuPacket, error) {
+	var result []*danmakuPacket
+	for len(data) > 0 {
+		if len(data) < danmakuHeaderLen {
+			return nil, errors.New("danmaku packet too short")
+		}
+		packetLen := int(binary.BigEndian.Uint32(data[0:4]))
+		headerLen := int(binary.BigEndian.Uint16(data[4:6]))
+		if packetLen < headerLen || headerLen < danmakuHeaderLen || packetLen > len(data) {
+			return nil, fmt.Errorf("invalid danmaku packet length %v/%v", packetLen, headerLen)
+		}
+		p := &danmakuPacket{
+			Proto: int(binary.BigEndian.Uint16(data[6:8])),
+			Op:    int(binary.BigEndian.Uint32(data[8:12])),
+			Body:  data[headerLen:packetLen],
+		}
+		data = data[packetLen:]
+
+		var reader io.Reader
+		switch p.Proto {
+		case danmakuProtoZlib:
+			zr, err := zlib.NewReader(bytes.NewReader(p.Body))
+			if err != nil {
+				return nil, err
+			}
+			reader = zr
+		case danmakuProtoBrotli:
+			reader = brotli.NewReader(bytes.NewReader(p.Body))
+		default:
+			result = append(result, p)
+			continue
+		}
+		b, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		sub, err := decodeDanmakuPacket(b)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, sub...)
+	}
+	return result, nil
+}
+
+type DanmakuEventKind string
+
+const (
+	DanmakuSuperChat DanmakuEventKind = "superchat"
+	DanmakuGuard     DanmakuEventKind = "guard"
+	DanmakuGift      DanmakuEventKind = "gift"
+)
+
+// GuardName 大航海等级名称，1为总督，2为提督，3为舰长
+func GuardName(level int) string {
+	switch level {
+	case 1:
+		return "总督"
+	case 2:
+		return "提督"
+	case 3:
+		return "舰长"
+	default:
+		return "大航海"
+	}
+}
+
+// DanmakuEvent 直播间里需要推送的事件
+type DanmakuEvent struct {
+	Kind     DanmakuEventKind `json:"kind"`
+	Uid      int64            `json:"uid"`
+	UserName string           `json:"user_name"`
+	// Message 醒目留言的内容
+	Message  string `json:"message"`
+	GiftName string `json:"gift_name"`
+	Num      int64  `json:"num"`
+	// Price 总价值，单位为元
+	Price      float64 `json:"price"`
+	GuardLevel int     `json:"guard_level"`
+}
+
+// PriceString 去掉多余的0，例如 30 或者 0.1
+func (e *DanmakuEvent) PriceString() string {
+	return strconv.FormatFloat(e.Price, 'f', -1, 64)
+}
+
+type danmakuCmd struct {
+	Cmd  string              `json:"cmd"`
+	Data jsoniter.RawMessage `json:"data"`
+}
+
+// parseDanmakuEvent 解析 op 为5的消息，不关心的消息返回 nil
+func parseDanmakuEvent(body []byte) (*DanmakuEvent, error) {
+	var cmd danmakuCmd
+	if err := json.Unmarshal(body, &cmd); err != nil {
+		return nil, err
+	}
+	// 部分cmd会带上后缀，例如 DANMU_MSG:4:0:2:2:2:0
+	switch strings.SplitN(cmd.Cmd, ":", 2)[0] {
+	case "SUPER_CHAT_MESSAGE":
+		var data struct {
+			Uid      int64   `json:"uid"`
+			Price    float64 `json:"price"`
+			Message  string  `json:"message"`
+			UserInfo struct {
+				Uname string `json:"uname"`
+			} `json:"user_info"`
+		}
+		if err := json.Unmarshal(cmd.Data, &data); err != nil {
+			return nil, err
+		}
+		return &DanmakuEvent{
+			Kind:     DanmakuSuperChat,
+			Uid:      data.Uid,
+			UserName: data.UserInfo.Uname,
+			Message:  data.Message,
+			Num:      1,
+			Price:    data.Price,
+		}, nil
+	case "GUARD_BUY":
+		var data struct {
+			Uid        int64  `json:"uid"`
+			Username   string `json:"username"`
+			GuardLevel int    `json:"guard_level"`
+			Num        int64  `json:"num"`
+			Price      int64  `json:"price"`
+			GiftName   string `json:"gift_name"`
+		}
+		if err := json.Unmarshal(cmd.Data, &data); err != nil {
+			return nil, err
+		}
+		// price 的单位是金瓜子，1000金瓜子为1元
+		return &DanmakuEvent{
+			Kind:       DanmakuGuard,
+			Uid:        data.Uid,
+			UserName:   data.Username,
+			GiftName:   data.GiftName,
+			Num:        data.Num,
+			Price:      float64(data.Price*data.Num) / 1000,
+			GuardLevel: data.GuardLevel,
+		}, nil
+	case "SEND_GIFT":
+		var data struct {
+			Uid       int64  `json:"uid"`
+			Uname     string `json:"uname"`
+			GiftName  string `json:"giftName"`
+			Num       int64  `json:"num"`
+			Price     int64  `json:"price"`
+			CoinType  string `json:"coin_type"`
+			TotalCoin int64  `json:"total_coin"`
+		}
+		if err := json.Unmarshal(cmd.Data, &data); err != nil {
+			return nil, err
+		}
+		// 银瓜子礼物是免费的
+		if data.CoinType != "gold" {
+			return nil, nil
+		}
+		total := data.TotalCoin
+		if total == 0 {
+			total = data.Price * data.Num
+		}
+		return &DanmakuEvent{
+			Kind:     DanmakuGift,
+			Uid:      data.Uid,
+			UserName: data.Uname,
+			GiftName: data.GiftName,
+			Num:      data.Num,
+			Price:    float64(total) / 1000,
+		}, nil
+	}
+	return nil, nil
+}
+
+// DanmakuServer 连接弹幕服务器需要的地址和token
+type DanmakuServer struct {
+	Url   string
+	Token string
+	// Uid 和 Buvid 为获取token时使用的账号，认证时需要保持一致，未登录时Uid为0
+	Uid   int64
+	Buvid string
+}
+
+type GetDanmuInfoResponse struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		Token    string `json:"token"`
+		HostList []struct {
+			Host    string `json:"host"`
+			WssPort int    `json:"wss_port"`
+		} `json:"host_list"`
+	} `json:"data"`
+}
+
+// GetDanmakuServer 获取直播间的弹幕服务器，失败时使用默认服务器和空token
+func GetDanmakuServer(roomId int64) (*DanmakuServer, error) {
+	opts := []requests.Option{
+		AddUAOption(),
+		AddReferOption("https://live.bilibili.com/"),
+		requests.TimeoutOption(time.Second * 15),
+		requests.ProxyOption(proxy_pool.PreferNone),
+		requests.WithCookieJar(cj.Load()),
+	}
+	opts = append(opts, GetVerifyOption()...)
+	var resp = new(GetDanmuInfoResponse)
+	err := requests.Get(BPath(PathGetDanmuInfo), map[string]interface{}{"id": roomId, "type": 0}, resp, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("getDanmuInfo code %v - %v", resp.Code, resp.Message)
+	}
+	var server = &DanmakuServer{
+		Url:   DefaultDanmakuServer,
+		Token: resp.Data.Token,
+		Uid:   accountUid.Load(),
+		Buvid: getBuvid3(),
+	}
+	for _, host := range resp.Data.HostList {
+		if host.Host != "" && host.WssPort != 0 {
+			server.Url = fmt.Sprintf("wss://%v:%v/sub", host.Host, host.WssPort)
+			break
+		}
+	}
+	return server, nil
+}
+
+// getBuvid3 从访问b站首页得到的cookie中读取buvid3，没有时返回空
+func getBuvid3() string {
+	j := cj.Load()
+	if j == nil {
+		return ""
+	}
+	for _, c := range j.Cookies(bilibiliHomeUrl) {
+		if c.Name == "buvid3" {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+// danmakuManager 管理所有直播间的弹幕连接，只连接正在直播的直播间
+type danmakuManager struct {
+	mu    sync.Mutex
+	conns map[int64]*danmakuConn
+	wg    sync.WaitGroup
+
+	// getServer 获取弹幕服务器，测试时替换成本地服务器
+	getServer func(roomId int64) (*DanmakuServer, error)
+	onEvent   func(mid int64, event *DanmakuEvent)
+}
+
+type danmakuConn struct {
+	roomId int64
+	cancel context.CancelFunc
+}
+
+func newDanmakuManager(onEvent func(mid int64, event *DanmakuEvent)) *danmakuManager {
+	return &danmakuManager{
+		conns:     make(map[int64]*danmakuConn),
+		getServer: GetDanmakuServer,
+		onEvent:   onEvent,
+	}
+}
+
+// sync 连接 living 中的直播间（mid -> roomId），断开其他直播间
+func (m *danmakuManager) sync(living map[int64]int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for mid, conn := range m.conns {
+		if roomId, found := living[mid]; !found || roomId != conn.roomId {
+			logger.WithField("mid", mid).WithField("roomId", conn.roomId).Debug("danmaku disconnect")
+			conn.cancel()
+			delete(m.conns, mid)
+		}
+	}
+	for mid, roomId := range living {
+		if _, found := m.conns[mid]; found || roomId == 0 {
+			continue
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		m.conns[mid] = &danmakuConn{roomId: roomId, cancel: cancel}
+		m.wg.Add(1)
+		go func(mid, roomId int64) {
+			defer m.wg.Done()
+			m.keepConnect(ctx, mid, roomId)
+		}(mid, roomId)
+	}
+}
+
+func (m *danmakuManager) connected() []int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []int64
+	for mid := range m.conns {
+		result = append(result, mid)
+	}
+	return result
+}
+
+func (m *danmakuManager) stop() {
+	m.sync(nil)
+	m.wg.Wait()
+}
+
+// keepConnect 断线后重连，直到 ctx 被取消
+func (m *danmakuManager) keepConnect(ctx context.Context, mid, roomId int64) {
+	log := logger.WithField("mid", mid).WithField("roomId", roomId)
+	var retry = danmakuRetryBase
+	for {
+		start := time.Now()
+		err := m.session(ctx, mid, roomId)
+		if ctx.Err() != nil {
+			return
+		}
+		// 连接了一段时间才断开的，重新从最短的等待时间开始
+		if time.Since(start) > danmakuRetryMax {
+			retry = danmakuRetryBase
+		}
+		log.Debugf("danmaku connection closed %v, retry after %v", err, retry)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+		retry *= 2
+		if retry > danmakuRetryMax {
+			retry = danmakuRetryMax
+		}
+	}
+}
+
+func (m *danmakuManager) session(ctx context.Context, mid, roomId int64) error {
+	server, err := m.getServer(roomId)
+	if err != nil {
+		logger.WithField("roomId", roomId).Debugf("GetDanmakuServer error %v, use default server", err)
+		server = &DanmakuServer{Url: DefaultDanmakuServer}
+	}
+	header := http.Header{}
+	header.Set("Origin", "https://live.bilibili.com")
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, server.Url, header)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	params := map[string]interface{}{
+		"uid":      server.Uid,
+		"roomid":   roomId,
+		"protover": danmakuProtoBrotli,
+		"platform": "web",
+		"type":     2,
+		"key":      server.Token,
+	}
+	if server.Buvid != "" {
+		params["buvid"] = server.Buvid
+	}
+	auth, _ := json.Marshal(params)
+	if err = conn.WriteMessage(websocket.BinaryMessage, encodeDanmakuPacket(danmakuProtoInt, danmakuOpAuth, auth)); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(danmakuHeartbeatInterval)
+		defer ticker.Stop()
+		heartbeat := encodeDanmakuPacket(danmakuProtoInt, danmakuOpHeartbeat, nil)
+		for {
+			if err := conn.WriteMessage(websocket.BinaryMessage, heartbeat); err != nil {
+				conn.Close()
+				return
+			}
+			select {
+			case <-ctx.Done():
+				conn.Close()
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		packets, err := decodeDanmakuPacket(data)
+		if err != nil {
+			return err
+		}
+		for _, p := range packets {
+			switch p.Op {
+			case danmakuOpAuthReply:
+				var reply struct {
+					Code int `json:"code"`
+				}
+				if err := json.Unmarshal(p.Body, &reply); err != nil || reply.Code != 0 {
+					return fmt.Errorf("danmaku auth failed %v", string(p.Body))
+				}
+			case danmakuOpMessage:
+				event, err := parseDanmakuEvent(p.Body)
+				if err != nil {
+					logger.WithField("roomId", roomId).Debugf("parseDanmakuEvent error %v", err)
+					continue
+				}
+				if event != nil {
+					m.onEvent(mid, event)
+				}
+			}
+		}
+	}
+}
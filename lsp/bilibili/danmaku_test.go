@@ -0,0 +1,246 @@
+package bilibili
+
+import (
+	"bytes"
+	"compress/zlib"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sora233/MiraiGo-Template/config"
+	"github.com/andybalholm/brotli"
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSuperChat = `{"cmd":"SUPER_CHAT_MESSAGE","data":{"uid":1,"price":30,"message":"加油","user_info":{"uname":"fan"}}}`
+	testGuardBuy  = `{"cmd":"GUARD_BUY","data":{"uid":2,"username":"captain","guard_level":3,"num":2,"price":198000,"gift_name":"舰长"}}`
+	testSendGift  = `{"cmd":"SEND_GIFT","data":{"uid":3,"uname":"rich","giftName":"小电视飞船","num":1,"price":1245000,"coin_type":"gold","total_coin":1245000}}`
+	testSilver    = `{"cmd":"SEND_GIFT","data":{"uid":4,"uname":"free","giftName":"辣条","num":10,"price":100,"coin_type":"silver","total_coin":1000}}`
+	testDanmu     = `{"cmd":"DANMU_MSG:4:0:2:2:2:0","info":[]}`
+)
+
+func zlibCompress(t *testing.T, b []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	_, err := w.Write(b)
+	require.Nil(t, err)
+	require.Nil(t, w.Close())
+	return buf.Bytes()
+}
+
+func brotliCompress(t *testing.T, b []byte) []byte {
+	var buf bytes.Buffer
+	w := brotli.NewWriter(&buf)
+	_, err := w.Write(b)
+	require.Nil(t, err)
+	require.Nil(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestDecodeDanmakuPacket(t *testing.T) {
+	inner := append(
+		encodeDanmakuPacket(danmakuProtoJson, danmakuOpMessage, []byte(testSuperChat)),
+		encodeDanmakuPacket(danmakuProtoJson, danmakuOpMessage, []byte(testGuardBuy))...,
+	)
+
+	for _, data := range [][]byte{
+		encodeDanmakuPacket(danmakuProtoZlib, danmakuOpMessage, zlibCompress(t, inner)),
+		encodeDanmakuPacket(danmakuProtoBrotli, danmakuOpMessage, brotliCompress(t, inner)),
+		inner,
+	} {
+		packets, err := decodeDanmakuPacket(data)
+		require.Nil(t, err)
+		require.Len(t, packets, 2)
+		assert.Equal(t, danmakuOpMessage, packets[0].Op)
+		assert.Equal(t, testSuperChat, string(packets[0].Body))
+		assert.Equal(t, testGuardBuy, string(packets[1].Body))
+	}
+
+	packets, err := decodeDanmakuPacket(encodeDanmakuPacket(danmakuProtoInt, danmakuOpHeartbeatReply, []byte{0, 0, 0, 1}))
+	require.Nil(t, err)
+	require.Len(t, packets, 1)
+	assert.Equal(t, danmakuOpHeartbeatReply, packets[0].Op)
+
+	_, err = decodeDanmakuPacket([]byte{0, 0, 0})
+	assert.NotNil(t, err)
+	bad := encodeDanmakuPacket(danmakuProtoJson, danmakuOpMessage, []byte("{}"))
+	_, err = decodeDanmakuPacket(bad[:len(bad)-1])
+	assert.NotNil(t, err)
+}
+
+func TestParseDanmakuEvent(t *testing.T) {
+	e, err := parseDanmakuEvent([]byte(testSuperChat))
+	require.Nil(t, err)
+	require.NotNil(t, e)
+	assert.Equal(t, DanmakuSuperChat, e.Kind)
+	assert.Equal(t, "fan", e.UserName)
+	assert.Equal(t, "加油", e.Message)
+	assert.Equal(t, "30", e.PriceString())
+
+	e, err = parseDanmakuEvent([]byte(testGuardBuy))
+	require.Nil(t, err)
+	require.NotNil(t, e)
+	assert.Equal(t, DanmakuGuard, e.Kind)
+	assert.Equal(t, 3, e.GuardLevel)
+	assert.EqualValues(t, 2, e.Num)
+	assert.Equal(t, "396", e.PriceString())
+	assert.Equal(t, "舰长", GuardName(e.GuardLevel))
+
+	e, err = parseDanmakuEvent([]byte(testSendGift))
+	require.Nil(t, err)
+	require.NotNil(t, e)
+	assert.Equal(t, DanmakuGift, e.Kind)
+	assert.Equal(t, "小电视飞船", e.GiftName)
+	assert.Equal(t, "1245", e.PriceString())
+
+	e, err = parseDanmakuEvent([]byte(testSilver))
+	assert.Nil(t, err)
+	assert.Nil(t, e)
+	e, err = parseDanmakuEvent([]byte(testDanmu))
+	assert.Nil(t, err)
+	assert.Nil(t, e)
+	_, err = parseDanmakuEvent([]byte("not json"))
+	assert.NotNil(t, err)
+}
+
+func TestDanmakuPass(t *testing.T) {
+	config.GlobalConfig.Set("bilibili.danmaku.superChatPrice", 50)
+	config.GlobalConfig.Set("bilibili.danmaku.guardLevel", 2)
+	config.GlobalConfig.Set("bilibili.danmaku.giftPrice", 1000)
+	defer func() {
+		config.GlobalConfig.Set("bilibili.danmaku.superChatPrice", nil)
+		config.GlobalConfig.Set("bilibili.danmaku.guardLevel", nil)
+		config.GlobalConfig.Set("bilibili.danmaku.giftPrice", nil)
+	}()
+
+	assert.False(t, danmakuPass(&DanmakuEvent{Kind: DanmakuSuperChat, Price: 30}))
+	assert.True(t, danmakuPass(&DanmakuEvent{Kind: DanmakuSuperChat, Price: 50}))
+	assert.False(t, danmakuPass(&DanmakuEvent{Kind: DanmakuGuard, GuardLevel: 3}))
+	assert.True(t, danmakuPass(&DanmakuEvent{Kind: DanmakuGuard, GuardLevel: 1}))
+	assert.False(t, danmakuPass(&DanmakuEvent{Kind: DanmakuGift, Price: 999}))
+	assert.True(t, danmakuPass(&DanmakuEvent{Kind: DanmakuGift, Price: 1245}))
+}
+
+func TestDanmakuInfo(t *testing.T) {
+	for _, data := range []string{testSuperChat, testGuardBuy, testSendGift} {
+		e, err := parseDanmakuEvent([]byte(data))
+		require.Nil(t, err)
+		info := &DanmakuInfo{
+			UserInfo: *NewUserInfo(test.UID1, test.ROOMID1, test.NAME1, "https://live.bilibili.com/1?broadcast_type=0"),
+			Event:    e,
+		}
+		assert.Equal(t, Danmaku, info.Type())
+		assert.Equal(t, Site, info.Site())
+		assert.NotNil(t, info.Logger())
+
+		notify := NewConcernDanmakuNotify(mmsg.NewGroupTarget(test.G1), info)
+		assert.Equal(t, mmsg.NewGroupTarget(test.G1), notify.GetTarget())
+		assert.NotNil(t, notify.Logger())
+		s := msgstringer.MsgToString(notify.ToMessage().Elements())
+		assert.Contains(t, s, e.UserName)
+		assert.Contains(t, s, "https://live.bilibili.com/1")
+		assert.NotContains(t, s, "broadcast_type")
+	}
+}
+
+// fakeDanmakuServer 本地的弹幕服务器，验证认证包后发送 brotli 压缩的事件
+func fakeDanmakuServer(t *testing.T, roomId int64, messages ...string) *httptest.Server {
+	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		packets, err := decodeDanmakuPacket(data)
+		if err != nil || len(packets) != 1 || packets[0].Op != danmakuOpAuth {
+			return
+		}
+		var auth struct {
+			Uid    int64  `json:"uid"`
+			RoomId int64  `json:"roomid"`
+			Key    string `json:"key"`
+			Buvid  string `json:"buvid"`
+		}
+		if json.Unmarshal(packets[0].Body, &auth) != nil || auth.RoomId != roomId || auth.Key != "token" ||
+			auth.Uid != test.UID2 || auth.Buvid != "buvid" {
+			return
+		}
+		conn.WriteMessage(websocket.BinaryMessage, encodeDanmakuPacket(danmakuProtoJson, danmakuOpAuthReply, []byte(`{"code":0}`)))
+		var inner []byte
+		for _, m := range messages {
+			inner = append(inner, encodeDanmakuPacket(danmakuProtoJson, danmakuOpMessage, []byte(m))...)
+		}
+		conn.WriteMessage(websocket.BinaryMessage, encodeDanmakuPacket(danmakuProtoBrotli, danmakuOpMessage, brotliCompress(t, inner)))
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+func TestGetBuvid3(t *testing.T) {
+	old := cj.Load()
+	defer cj.Store(old)
+
+	cj.Store(nil)
+	assert.Empty(t, getBuvid3())
+
+	j, _ := cookiejar.New(nil)
+	j.SetCookies(bilibiliHomeUrl, []*http.Cookie{{Name: "buvid3", Value: "buvid", Domain: ".bilibili.com"}})
+	cj.Store(j)
+	assert.Equal(t, "buvid", getBuvid3())
+}
+
+func TestDanmakuManager(t *testing.T) {
+	server := fakeDanmakuServer(t, test.ROOMID1, testSuperChat, testDanmu, testGuardBuy)
+	defer server.Close()
+
+	var mu sync.Mutex
+	var events []*DanmakuEvent
+	var mids []int64
+	m := newDanmakuManager(func(mid int64, event *DanmakuEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		mids = append(mids, mid)
+		events = append(events, event)
+	})
+	m.getServer = func(roomId int64) (*DanmakuServer, error) {
+		return &DanmakuServer{Url: "ws" + strings.TrimPrefix(server.URL, "http"), Token: "token", Uid: test.UID2, Buvid: "buvid"}, nil
+	}
+
+	m.sync(map[int64]int64{test.UID1: test.ROOMID1})
+	assert.Equal(t, []int64{test.UID1}, m.connected())
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) == 2
+	}, time.Second*5, time.Millisecond*50)
+	mu.Lock()
+	require.Len(t, events, 2)
+	assert.Equal(t, DanmakuSuperChat, events[0].Kind)
+	assert.Equal(t, DanmakuGuard, events[1].Kind)
+	assert.Equal(t, []int64{test.UID1, test.UID1}, mids)
+	mu.Unlock()
+
+	// 下播后断开连接
+	m.sync(map[int64]int64{})
+	assert.Empty(t, m.connected())
+	m.stop()
+}
@@ -18,7 +18,11 @@ type DynamicSrvDynamicHistoryRequest struct {
 }
 
 func DynamicSvrDynamicHistory(offsetDynamicId string) (*DynamicSvrDynamicHistoryResponse, error) {
-	if !IsVerifyGiven() {
+	return mainAccount.DynamicSvrDynamicHistory(offsetDynamicId)
+}
+
+func (a *Account) DynamicSvrDynamicHistory(offsetDynamicId string) (*DynamicSvrDynamicHistoryResponse, error) {
+	if !a.IsVerifyGiven() {
 		return nil, ErrVerifyRequired
 	}
 	st := time.Now()
@@ -43,7 +47,7 @@ func DynamicSvrDynamicHistory(offsetDynamicId string) (*DynamicSvrDynamicHistory
 		requests.TimeoutOption(time.Second*10),
 		delete412ProxyOption,
 	)
-	opts = append(opts, GetVerifyOption()...)
+	opts = append(opts, a.VerifyOption()...)
 	dynamicHistoryResp := new(DynamicSvrDynamicHistoryResponse)
 	err = requests.Get(url, params, dynamicHistoryResp, opts...)
 	if err != nil {
@@ -19,7 +19,11 @@ type DynamicSrvDynamicNewRequest struct {
 }
 
 func DynamicSvrDynamicNew() (*DynamicSvrDynamicNewResponse, error) {
-	if !IsVerifyGiven() {
+	return mainAccount.DynamicSvrDynamicNew()
+}
+
+func (a *Account) DynamicSvrDynamicNew() (*DynamicSvrDynamicNewResponse, error) {
+	if !a.IsVerifyGiven() {
 		return nil, ErrVerifyRequired
 	}
 	st := time.Now()
@@ -45,7 +49,7 @@ func DynamicSvrDynamicNew() (*DynamicSvrDynamicNewResponse, error) {
 		requests.TimeoutOption(time.Second*10),
 		delete412ProxyOption,
 	)
-	opts = append(opts, GetVerifyOption()...)
+	opts = append(opts, a.VerifyOption()...)
 	dynamicNewResp := new(DynamicSvrDynamicNewResponse)
 	err = requests.Get(url, params, dynamicNewResp, opts...)
 	if err != nil {
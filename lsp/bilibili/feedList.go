@@ -31,7 +31,11 @@ func FeedPageSizeOpt(pageSize int) FeedOpt {
 }
 
 func FeedList(opt ...FeedOpt) (*FeedListResponse, error) {
-	if !IsVerifyGiven() {
+	return mainAccount.FeedList(opt...)
+}
+
+func (a *Account) FeedList(opt ...FeedOpt) (*FeedListResponse, error) {
+	if !a.IsVerifyGiven() {
 		return nil, ErrVerifyRequired
 	}
 	st := time.Now()
@@ -62,7 +66,7 @@ func FeedList(opt ...FeedOpt) (*FeedListResponse, error) {
 		AddUAOption(),
 		requests.TimeoutOption(time.Second*10),
 	)
-	opts = append(opts, GetVerifyOption()...)
+	opts = append(opts, a.VerifyOption()...)
 	flr := new(FeedListResponse)
 	err = requests.Get(url, params, flr, opts...)
 	if err != nil {
@@ -12,7 +12,11 @@ const (
 )
 
 func GetAttentionList() (*GetAttentionListResponse, error) {
-	if !IsVerifyGiven() {
+	return mainAccount.GetAttentionList()
+}
+
+func (a *Account) GetAttentionList() (*GetAttentionListResponse, error) {
+	if !a.IsVerifyGiven() {
 		return nil, ErrVerifyRequired
 	}
 	st := time.Now()
@@ -28,10 +32,10 @@ func GetAttentionList() (*GetAttentionListResponse, error) {
 		requests.TimeoutOption(time.Second*10),
 		delete412ProxyOption,
 	)
-	opts = append(opts, GetVerifyOption()...)
+	opts = append(opts, a.VerifyOption()...)
 	getAttentionListResp := new(GetAttentionListResponse)
 	err := requests.Get(url, map[string]interface{}{
-		"uid": accountUid.String(),
+		"uid": a.uid.String(),
 	}, getAttentionListResp, opts...)
 	if err != nil {
 		return nil, err
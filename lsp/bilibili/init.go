@@ -7,6 +7,7 @@ import (
 
 func init() {
 	concern.RegisterConcern(NewConcern(concern.GetNotifyChan()))
+	concern.RegisterConcern(NewSeasonConcern(concern.GetNotifyChan()))
 	refreshCookieJar()
 	refreshNavWbi()
 	go func() {
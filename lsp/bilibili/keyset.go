@@ -1,6 +1,10 @@
 package bilibili
 
-import "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+)
 
 type keySet struct {
 }
@@ -21,8 +25,8 @@ func (k *keySet) FreshKey(keys ...interface{}) string {
 	return buntdb.BilibliFreshKey(keys...)
 }
 
-func (k *keySet) ParseGroupConcernStateKey(key string) (int64, interface{}, error) {
-	return buntdb.ParseConcernStateKeyWithInt64(key)
+func (k *keySet) ParseGroupConcernStateKey(key string) (mmsg.Target, interface{}, error) {
+	return concern.ParseConcernStateKeyWithInt64(key)
 }
 
 type extraKey struct {
@@ -70,6 +74,18 @@ func (k *extraKey) ActiveTimestampKey(keys ...interface{}) string {
 	return buntdb.BilibiliActiveTimestampKey(keys...)
 }
 
+func (k *extraKey) AccountShardKey(keys ...interface{}) string {
+	return buntdb.BilibiliAccountShardKey(keys...)
+}
+
+func (k *extraKey) SeasonInfoKey(keys ...interface{}) string {
+	return buntdb.BilibiliSeasonInfoKey(keys...)
+}
+
+func (k *extraKey) MarkEpisodeKey(keys ...interface{}) string {
+	return buntdb.BilibiliMarkEpisodeKey(keys...)
+}
+
 func NewKeySet() *keySet {
 	return &keySet{}
 }
@@ -3,12 +3,15 @@ package bilibili
 import (
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Mrs4s/MiraiGo/message"
 	"github.com/Sora233/MiraiGo-Template/config"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
+	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
 	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/cnxysoft/DDBOT-WSa/utils/blockCache"
 	"github.com/sirupsen/logrus"
@@ -40,7 +43,7 @@ func (n *NewsInfo) Logger() *logrus.Entry {
 }
 
 type ConcernNewsNotify struct {
-	GroupCode int64 `json:"group_code"`
+	Target mmsg.Target `json:"target"`
 	*UserInfo
 	Card *CacheCard
 
@@ -59,7 +62,7 @@ func (notify *ConcernNewsNotify) Living() bool {
 }
 
 type ConcernLiveNotify struct {
-	GroupCode int64 `json:"group_code"`
+	Target mmsg.Target `json:"target"`
 	*LiveInfo
 }
 
@@ -101,14 +104,15 @@ type LiveInfo struct {
 	ParentAreaId   int32      `json:"parent_area_id"`
 	ParentAreaName string     `json:"parent_area_name"`
 	LiveTime       int64      `json:"live_time"`
+	// Online 直播间人气，只有正在直播时有值
+	Online int64 `json:"online"`
 
-	once              sync.Once
-	msgCache          *mmsg.MSG
+	msgCache          template.MsgCache
 	liveStatusChanged bool
 	liveTitleChanged  bool
 }
 
-func (l *LiveInfo) GetMSG() *mmsg.MSG {
+func (l *LiveInfo) GetMSG(locale string) *mmsg.MSG {
 	if l == nil {
 		return nil
 	}
@@ -119,7 +123,7 @@ func (l *LiveInfo) GetMSG() *mmsg.MSG {
 		}
 		return url
 	}
-	l.once.Do(func() {
+	return l.msgCache.Get(locale, func() *mmsg.MSG {
 		var data = map[string]interface{}{
 			"uid":              l.Mid,
 			"title":            l.LiveTitle,
@@ -132,14 +136,12 @@ func (l *LiveInfo) GetMSG() *mmsg.MSG {
 			"live_time":        l.LiveTime,
 			"title_changed":    l.liveTitleChanged,
 		}
-		var err error
-		l.msgCache, err = template.LoadAndExec("notify.group.bilibili.live.tmpl", data)
+		msg, err := template.ExecNotify(locale, "notify.group.bilibili.live.tmpl", data)
 		if err != nil {
 			logger.Errorf("bilibili: LiveInfo LoadAndExec error %v", err)
 		}
-		return
+		return msg
 	})
-	return l.msgCache
 }
 
 func (l *LiveInfo) TitleChanged() bool {
@@ -150,6 +152,18 @@ func (l *LiveInfo) LiveStatusChanged() bool {
 	return l.liveStatusChanged
 }
 
+func (l *LiveInfo) AnchorName() string {
+	return l.Name
+}
+
+func (l *LiveInfo) RoomTitle() string {
+	return l.LiveTitle
+}
+
+func (l *LiveInfo) Popularity() int64 {
+	return l.Online
+}
+
 func (l *LiveInfo) IsLive() bool {
 	return true
 }
@@ -244,29 +258,29 @@ func NewNewsInfoWithDetail(userInfo *UserInfo, cards []*Card) *NewsInfo {
 	}
 }
 
-func NewConcernNewsNotify(groupCode int64, newsInfo *NewsInfo, c *Concern) []*ConcernNewsNotify {
+func NewConcernNewsNotify(target mmsg.Target, newsInfo *NewsInfo, c *Concern) []*ConcernNewsNotify {
 	if newsInfo == nil {
 		return nil
 	}
 	var result []*ConcernNewsNotify
 	for _, card := range newsInfo.Cards {
 		result = append(result, &ConcernNewsNotify{
-			GroupCode: groupCode,
-			UserInfo:  &newsInfo.UserInfo,
-			Card:      NewCacheCard(card),
-			concern:   c,
+			Target:   target,
+			UserInfo: &newsInfo.UserInfo,
+			Card:     NewCacheCard(card),
+			concern:  c,
 		})
 	}
 	return result
 }
 
-func NewConcernLiveNotify(groupCode int64, liveInfo *LiveInfo) *ConcernLiveNotify {
+func NewConcernLiveNotify(target mmsg.Target, liveInfo *LiveInfo) *ConcernLiveNotify {
 	if liveInfo == nil {
 		return nil
 	}
 	return &ConcernLiveNotify{
-		GroupCode: groupCode,
-		LiveInfo:  liveInfo,
+		Target:   target,
+		LiveInfo: liveInfo,
 	}
 }
 
@@ -281,7 +295,7 @@ func (notify *ConcernNewsNotify) ToMessage() (m *mmsg.MSG) {
 	if notify.shouldCompact {
 		// 通过回复之前消息的方式简化推送
 		m = mmsg.NewMSG()
-		msg, _ := notify.concern.GetNotifyMsg(notify.GroupCode, notify.compactKey)
+		msg, _ := notify.concern.GetNotifyMsg(notify.Target, notify.compactKey)
 		if msg != nil {
 			card.orgMsg = msg
 			//m.Append(message.NewReply(msg))
@@ -308,10 +322,72 @@ func (notify *ConcernNewsNotify) ToMessage() (m *mmsg.MSG) {
 		//	return
 		//}
 	}
-	m = notify.Card.GetMSG()
+	m = notify.Card.GetMSG(template.NotifyLocale(notify.Target))
 	return
 }
 
+// NotifyMedias 存档投稿视频的封面、视频、音频和视频链接，其他动态不存档
+func (notify *ConcernNewsNotify) NotifyMedias() []*concern.NotifyMedia {
+	if notify == nil || notify.Card == nil || notify.Card.GetDesc().GetType() != DynamicDescType_WithVideo {
+		return nil
+	}
+	videoCard, err := notify.Card.GetCardWithVideo()
+	if err != nil {
+		notify.Logger().Errorf("GetCardWithVideo error %v", err)
+		return nil
+	}
+	var t = time.Unix(notify.Card.GetDesc().GetTimestamp(), 0)
+	var medias []*concern.NotifyMedia
+	if videoCard.GetPic() != "" {
+		medias = append(medias, &concern.NotifyMedia{
+			Kind:  concern.MediaCover,
+			Url:   videoCard.GetPic(),
+			Name:  concern.MediaFileName(t, videoCard.GetTitle(), ".jpg"),
+			Proxy: proxy_pool.PreferNone,
+		})
+	}
+	if bvid := notify.Card.GetDesc().GetBvid(); bvid != "" {
+		// 只有开启了视频存档时才会调用 Loader，视频和音频共用一次cid查询
+		cid := sync.OnceValues(func() (int64, error) {
+			return XPlayerPagelistCid(bvid)
+		})
+		playurl := func(audio bool) func() (string, error) {
+			return func() (string, error) {
+				c, err := cid()
+				if err != nil {
+					return "", err
+				}
+				return XPlayerPlayurl(bvid, c, audio)
+			}
+		}
+		medias = append(medias, &concern.NotifyMedia{
+			Kind:    concern.MediaVideo,
+			Name:    concern.MediaFileName(t, videoCard.GetTitle(), ".mp4"),
+			Headers: PlayurlHeaders,
+			Loader:  playurl(false),
+		}, &concern.NotifyMedia{
+			Kind:    concern.MediaAudio,
+			Name:    concern.MediaFileName(t, videoCard.GetTitle(), ".m4a"),
+			Headers: PlayurlHeaders,
+			Loader:  playurl(true),
+		}, &concern.NotifyMedia{
+			Kind: concern.MediaLink,
+			Url:  BVIDUrl(bvid),
+			Name: concern.MediaFileName(t, videoCard.GetTitle(), ".txt"),
+		})
+	}
+	return medias
+}
+
+// PostId 返回动态id，用于检测动态是否被删除
+func (notify *ConcernNewsNotify) PostId() string {
+	return notify.Card.GetDesc().GetDynamicIdStr()
+}
+
+func (notify *ConcernNewsNotify) PostTime() time.Time {
+	return time.Unix(notify.Card.GetDesc().GetTimestamp(), 0)
+}
+
 func (notify *ConcernNewsNotify) Type() concern_type.Type {
 	return News
 }
@@ -320,8 +396,8 @@ func (notify *ConcernNewsNotify) Site() string {
 	return Site
 }
 
-func (notify *ConcernNewsNotify) GetGroupCode() int64 {
-	return notify.GroupCode
+func (notify *ConcernNewsNotify) GetTarget() mmsg.Target {
+	return notify.Target
 }
 func (notify *ConcernNewsNotify) GetUid() interface{} {
 	return notify.Mid
@@ -331,7 +407,7 @@ func (notify *ConcernNewsNotify) Logger() *logrus.Entry {
 	if notify == nil {
 		return logger
 	}
-	return logger.WithFields(localutils.GroupLogFields(notify.GroupCode)).
+	return logger.WithFields(mmsg.TargetLogFields(notify.Target)).
 		WithFields(logrus.Fields{
 			"Site":      Site,
 			"Mid":       notify.Mid,
@@ -343,7 +419,7 @@ func (notify *ConcernNewsNotify) Logger() *logrus.Entry {
 }
 
 func (notify *ConcernLiveNotify) ToMessage() (m *mmsg.MSG) {
-	return notify.LiveInfo.GetMSG()
+	return notify.LiveInfo.GetMSG(template.NotifyLocale(notify.Target))
 }
 
 func (notify *ConcernLiveNotify) Logger() *logrus.Entry {
@@ -351,11 +427,11 @@ func (notify *ConcernLiveNotify) Logger() *logrus.Entry {
 		return logger
 	}
 	return notify.LiveInfo.Logger().
-		WithFields(localutils.GroupLogFields(notify.GroupCode))
+		WithFields(mmsg.TargetLogFields(notify.Target))
 }
 
-func (notify *ConcernLiveNotify) GetGroupCode() int64 {
-	return notify.GroupCode
+func (notify *ConcernLiveNotify) GetTarget() mmsg.Target {
+	return notify.Target
 }
 
 // combineImageCache 是给combineImage用的cache，其他地方禁止使用
@@ -442,7 +518,7 @@ func urlsMergeImage(urls []string) (result []byte, err error) {
 type CacheCard struct {
 	*Card
 	once     sync.Once
-	msgCache *mmsg.MSG
+	msgCache template.MsgCache
 	dynamic  DynamicInfo
 	orgMsg   *message.GroupMessage
 }
@@ -977,20 +1053,18 @@ func (c *CacheCard) prepare() {
 	c.dynamic.DynamicUrl = dynamicUrl
 }
 
-func (c *CacheCard) GetMSG() *mmsg.MSG {
-	c.once.Do(func() {
-		c.prepare()
+func (c *CacheCard) GetMSG(locale string) *mmsg.MSG {
+	c.once.Do(c.prepare)
+	return c.msgCache.Get(locale, func() *mmsg.MSG {
 		var data = map[string]interface{}{
 			"dynamic":   c.dynamic,
 			"msg":       c.orgMsg,
 			"parsePost": config.GlobalConfig.GetBool("bilibili.autoParsePosts"),
 		}
-		var err error
-		c.msgCache, err = template.LoadAndExec("notify.group.bilibili.news.tmpl", data)
+		msg, err := template.ExecNotify(locale, "notify.group.bilibili.news.tmpl", data)
 		if err != nil {
 			logger.Errorf("bilibili: NewsInfo LoadAndExec error %v", err)
 		}
-		return
+		return msg
 	})
-	return c.msgCache
 }
@@ -2,6 +2,7 @@ package bilibili
 
 import (
 	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -10,14 +11,14 @@ func TestModelNotify(t *testing.T) {
 	var live *LiveInfo
 	assert.False(t, live.Living())
 	liveNotify := newLiveInfo(test.UID1, true, false, false)
-	liveNotify.GroupCode = test.G1
+	liveNotify.Target = mmsg.NewGroupTarget(test.G1)
 	m := liveNotify.ToMessage()
 	assert.NotNil(t, m)
 
 	assert.Equal(t, Site, liveNotify.Site())
 	assert.NotNil(t, liveNotify.Logger())
 	assert.NotNil(t, Live, liveNotify.Type())
-	assert.Equal(t, test.G1, liveNotify.GetGroupCode())
+	assert.Equal(t, mmsg.NewGroupTarget(test.G1), liveNotify.GetTarget())
 	assert.Equal(t, test.UID1, liveNotify.GetUid())
 
 	liveNotify.Status = LiveStatus_NoLiving
@@ -25,13 +26,13 @@ func TestModelNotify(t *testing.T) {
 	assert.NotNil(t, m)
 
 	newsNotify := newNewsInfo(test.UID1, DynamicDescType_TextOnly)[0]
-	newsNotify.GroupCode = test.G2
+	newsNotify.Target = mmsg.NewGroupTarget(test.G2)
 	assert.NotNil(t, newsNotify)
 	assert.NotNil(t, newsNotify.Logger())
 	assert.Equal(t, Site, newsNotify.Site())
 	assert.Equal(t, News, newsNotify.Type())
 	assert.Equal(t, test.UID1, newsNotify.GetUid())
-	assert.Equal(t, test.G2, newsNotify.GetGroupCode())
+	assert.Equal(t, mmsg.NewGroupTarget(test.G2), newsNotify.GetTarget())
 	m = newsNotify.ToMessage()
 	assert.NotNil(t, m)
 	newsNotify.shouldCompact = true
@@ -43,7 +44,7 @@ func TestModelNotify(t *testing.T) {
 		DynamicDescType_WithPost, DynamicDescType_WithMusic, DynamicDescType_WithSketch, DynamicDescType_WithLive,
 		DynamicDescType_WithLiveV2, DynamicDescType_WithMiss)
 	for _, notify := range notifies {
-		notify.GroupCode = test.G2
+		notify.Target = mmsg.NewGroupTarget(test.G2)
 		m = notify.ToMessage()
 		assert.NotNil(t, m)
 		notify.Card.Card.Card = "{}"
@@ -56,20 +57,20 @@ func TestModelNotify(t *testing.T) {
 }
 
 func TestNewConcernLiveNotify(t *testing.T) {
-	notify := NewConcernLiveNotify(test.G1, nil)
+	notify := NewConcernLiveNotify(mmsg.NewGroupTarget(test.G1), nil)
 	assert.Nil(t, notify)
 	origUserInfo := NewUserInfo(test.UID1, test.ROOMID1, test.NAME1, "")
 	origLiveInfo := NewLiveInfo(origUserInfo, "", "", LiveStatus_Living, 0)
-	notify = NewConcernLiveNotify(test.G1, origLiveInfo)
+	notify = NewConcernLiveNotify(mmsg.NewGroupTarget(test.G1), origLiveInfo)
 	assert.NotNil(t, notify)
 }
 
 func TestNewConcernNewsNotify(t *testing.T) {
-	notify := NewConcernNewsNotify(test.G1, nil, nil)
+	notify := NewConcernNewsNotify(mmsg.NewGroupTarget(test.G1), nil, nil)
 	assert.Nil(t, notify)
 	origUserInfo := NewUserInfo(test.UID1, test.ROOMID1, test.NAME1, "")
 	origNewsInfo := NewNewsInfo(origUserInfo, test.DynamicID1, test.TIMESTAMP1)
 	origNewsInfo.Cards = []*Card{{}}
-	notify = NewConcernNewsNotify(test.G1, origNewsInfo, nil)
+	notify = NewConcernNewsNotify(mmsg.NewGroupTarget(test.G1), origNewsInfo, nil)
 	assert.NotNil(t, notify)
 }
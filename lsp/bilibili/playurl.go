@@ -0,0 +1,143 @@
+package bilibili
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
+	"github.com/cnxysoft/DDBOT-WSa/requests"
+	"github.com/cnxysoft/DDBOT-WSa/utils"
+)
+
+const (
+	PathXPlayerPagelist = "/x/player/pagelist"
+	PathXPlayerPlayurl  = "/x/player/playurl"
+
+	// 存档使用的视频清晰度，360P的mp4文件，体积较小并且包含音频
+	playurlQn = 16
+	// fnval 1 为mp4格式，16 为dash格式，音频只在dash格式中单独提供
+	playurlFnvalMp4  = 1
+	playurlFnvalDash = 16
+)
+
+// PlayurlHeaders 下载视频和音频时需要带上的请求头，没有Referer时b站会返回403
+// 格式为协议端 download_file 使用的 Key=Value
+var PlayurlHeaders = []string{"Referer=https://www.bilibili.com/"}
+
+var ErrNoPlayurl = errors.New("没有可以下载的视频地址")
+
+type XPlayerPagelistResponse struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+	Data    []struct {
+		Cid  int64  `json:"cid"`
+		Page int32  `json:"page"`
+		Part string `json:"part"`
+	} `json:"data"`
+}
+
+func (r *XPlayerPagelistResponse) GetCode() int32 {
+	return r.Code
+}
+
+type XPlayerPlayurlResponse struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+	Data    *struct {
+		Durl []struct {
+			Url string `json:"url"`
+		} `json:"durl"`
+		Dash *struct {
+			Audio []struct {
+				Id      int64  `json:"id"`
+				BaseUrl string `json:"base_url"`
+			} `json:"audio"`
+		} `json:"dash"`
+	} `json:"data"`
+}
+
+func (r *XPlayerPlayurlResponse) GetCode() int32 {
+	return r.Code
+}
+
+func playurlGet(path string, params map[string]interface{}, out interface{}) error {
+	st := time.Now()
+	defer func() {
+		ed := time.Now()
+		logger.WithField("FuncName", utils.FuncName()).WithField("Path", path).Tracef("cost %v", ed.Sub(st))
+	}()
+	var opts = []requests.Option{
+		requests.ProxyOption(proxy_pool.PreferNone),
+		requests.TimeoutOption(time.Second * 15),
+		AddUAOption(),
+		AddReferOption(),
+		delete412ProxyOption,
+	}
+	opts = append(opts, GetVerifyOption()...)
+	return requests.Get(BPath(path), params, out, opts...)
+}
+
+// XPlayerPagelistCid 查询视频第一P的cid，下载视频时需要
+func XPlayerPagelistCid(bvid string) (int64, error) {
+	resp := new(XPlayerPagelistResponse)
+	if err := playurlGet(PathXPlayerPagelist, map[string]interface{}{"bvid": bvid}, resp); err != nil {
+		return 0, err
+	}
+	if resp.Code != 0 {
+		return 0, fmt.Errorf("XPlayerPagelist code %v - %v", resp.Code, resp.Message)
+	}
+	if len(resp.Data) == 0 {
+		return 0, ErrNoPlayurl
+	}
+	return resp.Data[0].Cid, nil
+}
+
+// XPlayerPlayurl 查询视频的下载地址，audio 为true时返回音频的下载地址
+func XPlayerPlayurl(bvid string, cid int64, audio bool) (string, error) {
+	var fnval = playurlFnvalMp4
+	if audio {
+		fnval = playurlFnvalDash
+	}
+	resp := new(XPlayerPlayurlResponse)
+	err := playurlGet(PathXPlayerPlayurl, map[string]interface{}{
+		"bvid":  bvid,
+		"cid":   cid,
+		"qn":    playurlQn,
+		"fnval": fnval,
+	}, resp)
+	if err != nil {
+		return "", err
+	}
+	return ParsePlayurl(resp, audio)
+}
+
+// ParsePlayurl 视频使用第一段mp4，音频使用码率最高的一个
+func ParsePlayurl(resp *XPlayerPlayurlResponse, audio bool) (string, error) {
+	if resp.Code != 0 {
+		return "", fmt.Errorf("XPlayerPlayurl code %v - %v", resp.Code, resp.Message)
+	}
+	if resp.Data == nil {
+		return "", ErrNoPlayurl
+	}
+	if !audio {
+		if len(resp.Data.Durl) == 0 || resp.Data.Durl[0].Url == "" {
+			return "", ErrNoPlayurl
+		}
+		return resp.Data.Durl[0].Url, nil
+	}
+	if resp.Data.Dash == nil {
+		return "", ErrNoPlayurl
+	}
+	var url string
+	var best int64
+	for _, a := range resp.Data.Dash.Audio {
+		if a.BaseUrl != "" && a.Id > best {
+			url, best = a.BaseUrl, a.Id
+		}
+	}
+	if url == "" {
+		return "", ErrNoPlayurl
+	}
+	return url, nil
+}
@@ -0,0 +1,33 @@
+package bilibili
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlayurl(t *testing.T) {
+	var resp = new(XPlayerPlayurlResponse)
+	require.Nil(t, json.Unmarshal([]byte(`{
+		"code": 0,
+		"data": {
+			"durl": [{"url": "https://example.com/video.mp4"}],
+			"dash": {"audio": [
+				{"id": 30216, "base_url": "https://example.com/64k.m4s"},
+				{"id": 30280, "base_url": "https://example.com/192k.m4s"}
+			]}
+		}
+	}`), resp))
+	url, err := ParsePlayurl(resp, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/video.mp4", url)
+	url, err = ParsePlayurl(resp, true)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/192k.m4s", url)
+
+	_, err = ParsePlayurl(&XPlayerPlayurlResponse{Code: -404, Message: "啥都木有"}, false)
+	assert.NotNil(t, err)
+	_, err = ParsePlayurl(&XPlayerPlayurlResponse{}, true)
+	assert.Equal(t, ErrNoPlayurl, err)
+}
@@ -0,0 +1,79 @@
+package bilibili
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+)
+
+// Preview 动态使用空间动态接口获取最新的动态，直播使用当前的直播间状态，并当作刚刚发生变化处理
+func (c *Concern) Preview(target mmsg.Target, id interface{}, ctype concern_type.Type, limit int) ([]concern.Notify, error) {
+	mid := id.(int64)
+	var result []concern.Notify
+	switch ctype {
+	case News:
+		userInfo, err := c.GetUserInfo(mid)
+		if err != nil {
+			liveInfo, err := loadLiveInfo(mid)
+			if err != nil {
+				return nil, err
+			}
+			userInfo = &liveInfo.UserInfo
+		}
+		history, err := DynamicSrvSpaceHistory(mid)
+		if err != nil {
+			return nil, err
+		}
+		if history.Code != 0 {
+			return nil, fmt.Errorf("code:%v %v", history.Code, history.Message)
+		}
+		var cards []*Card
+		for _, card := range history.GetData().GetCards() {
+			if len(cards) >= limit {
+				break
+			}
+			// 与 filterCard 相同，系统推荐的直播间不算做动态
+			if card.GetDesc().GetType() == DynamicDescType_WithLiveV2 {
+				continue
+			}
+			cards = append(cards, card)
+		}
+		for _, notify := range NewConcernNewsNotify(target, NewNewsInfoWithDetail(userInfo, cards), c) {
+			result = append(result, notify)
+		}
+	case Live:
+		// 不能使用 FindUserLiving，它会保存直播状态，导致刷新时错过真正的开播推送
+		liveInfo, err := loadLiveInfo(mid)
+		if err != nil {
+			return nil, err
+		}
+		liveInfo.liveStatusChanged = true
+		result = append(result, NewConcernLiveNotify(target, liveInfo))
+	default:
+		return nil, errors.New("该类型不支持预览")
+	}
+	return result, nil
+}
+
+// Preview 获取最近的剧集或视频，不会记录为已经见过
+func (c *SeasonConcern) Preview(target mmsg.Target, id interface{}, ctype concern_type.Type, limit int) ([]concern.Notify, error) {
+	info, err := c.GetSeasonInfo(ctype, id.(string))
+	if err != nil {
+		info = &SeasonInfo{Id: id.(string), Ctype: ctype}
+	}
+	episodes, err := c.fetchSeason(info)
+	if err != nil {
+		return nil, err
+	}
+	if len(episodes) > limit {
+		episodes = episodes[len(episodes)-limit:]
+	}
+	var result []concern.Notify
+	for _, notify := range NewConcernSeasonNotify(target, &SeasonNewsInfo{SeasonInfo: info, Episodes: episodes}) {
+		result = append(result, notify)
+	}
+	return result, nil
+}
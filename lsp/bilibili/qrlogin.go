@@ -1,11 +1,15 @@
 package bilibili
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/Sora233/MiraiGo-Template/config"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/eventbus"
 	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
 	"github.com/cnxysoft/DDBOT-WSa/requests"
 	"github.com/skip2/go-qrcode"
+	"go.uber.org/atomic"
 	"net/url"
 	"time"
 )
@@ -15,7 +19,26 @@ const (
 	PathQRLoginGenerateQR  = "/x/passport-login/web/qrcode/generate"
 )
 
-func GetQRCode() (*GetQRCodeResponse, error) {
+const (
+	// QRCodeExpired 二维码已失效
+	QRCodeExpired int32 = 86038
+	// QRCodeScanned 二维码已扫码，但还未确认
+	QRCodeScanned int32 = 86090
+	// QRCodeNotScanned 二维码还未扫码
+	QRCodeNotScanned int32 = 86101
+)
+
+var (
+	ErrQRLoginRunning = errors.New("已经有一个正在进行的扫码登录")
+	ErrQRCodeExpired  = errors.New("二维码已失效")
+	ErrQRLoginTimeout = errors.New("等待扫码超时")
+
+	qrLoginRunning      atomic.Bool
+	qrLoginPollInterval = time.Second * 3
+)
+
+// NewQRCode 申请一个登录二维码
+func NewQRCode() (*GetQRCodeResponse, error) {
 	var opts []requests.Option
 	opts = append(opts,
 		requests.ProxyOption(proxy_pool.PreferNone),
@@ -32,6 +55,15 @@ func GetQRCode() (*GetQRCodeResponse, error) {
 	if GetQRCodeResp.Code != 0 {
 		return nil, errors.New(GetQRCodeResp.Message)
 	}
+	return GetQRCodeResp, nil
+}
+
+// GetQRCode 申请一个登录二维码，并输出到控制台和qrcode.png
+func GetQRCode() (*GetQRCodeResponse, error) {
+	GetQRCodeResp, err := NewQRCode()
+	if err != nil {
+		return nil, err
+	}
 	err = qrcode.WriteFile(GetQRCodeResp.Data.Url, qrcode.Low, 256, "qrcode.png")
 	if err != nil {
 		return nil, err
@@ -46,6 +78,12 @@ func GetQRCode() (*GetQRCodeResponse, error) {
 	return GetQRCodeResp, nil
 }
 
+// QRCodePNG 把二维码链接编码为png图片
+func QRCodePNG(content string) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, 256)
+}
+
+// QRLoginCheck 查询扫码结果，未登录成功时会同时返回结果和error，可以通过 Data.Code 判断二维码状态
 func QRLoginCheck(token string) (*QRLoginResponse, error) {
 	if token == "" {
 		return nil, errors.New("查询的Token为空")
@@ -66,11 +104,63 @@ func QRLoginCheck(token string) (*QRLoginResponse, error) {
 		return nil, err
 	}
 	if QRLoginResp.Data.Code != 0 {
-		return nil, errors.New(QRLoginResp.Data.Message)
+		return QRLoginResp, errors.New(QRLoginResp.Data.Message)
 	}
 	return QRLoginResp, nil
 }
 
+// QRLogin 申请一个登录二维码并返回png图片，然后在后台轮询扫码结果，
+// 登录成功后cookie会立即生效并保存到配置文件，不需要重启。
+// 同一时间只允许一个扫码登录，ctx 用于控制等待扫码的时间。
+func QRLogin(ctx context.Context, onResult func(err error)) ([]byte, error) {
+	if !qrLoginRunning.CompareAndSwap(false, true) {
+		return nil, ErrQRLoginRunning
+	}
+	resp, err := NewQRCode()
+	if err != nil {
+		qrLoginRunning.Store(false)
+		return nil, err
+	}
+	png, err := QRCodePNG(resp.GetData().GetUrl())
+	if err != nil {
+		qrLoginRunning.Store(false)
+		return nil, err
+	}
+	go func() {
+		defer qrLoginRunning.Store(false)
+		err := pollQRLogin(ctx, resp.GetData().GetQrcodeKey())
+		if onResult != nil {
+			onResult(err)
+		}
+	}()
+	return png, nil
+}
+
+func pollQRLogin(ctx context.Context, key string) error {
+	ticker := time.NewTicker(qrLoginPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ErrQRLoginTimeout
+		}
+		resp, err := QRLoginCheck(key)
+		if err != nil {
+			if resp.GetData().GetCode() == QRCodeExpired {
+				return ErrQRCodeExpired
+			}
+			logger.Tracef("QRLoginCheck %v", err)
+			continue
+		}
+		cookies, err := GetCookies(resp.GetData().GetUrl())
+		if err != nil {
+			return fmt.Errorf("解析Cookies失败 - %v", err)
+		}
+		return ApplyCookies(cookies)
+	}
+}
+
 type BiliCookies struct {
 	SESSDATA string
 	BILI_JCT string
@@ -93,3 +183,27 @@ func GetCookies(Url string) (*BiliCookies, error) {
 	}
 	return &cookies, nil
 }
+
+// ApplyCookies 使用新的cookie并立即生效，同时写入配置文件，
+// 生效后会通过eventbus发布 TopicLogin，让订阅同步等功能使用新的账号
+func ApplyCookies(cookies *BiliCookies) error {
+	if cookies == nil || len(cookies.SESSDATA) == 0 || len(cookies.BILI_JCT) == 0 {
+		return errors.New("cookie信息不完整")
+	}
+	SetVerify(cookies.SESSDATA, cookies.BILI_JCT)
+	FreshSelfInfo()
+	lastCookieStatus.Store(int32(CookieValid))
+	logger.WithField("uid", accountUid.Load()).Info("B站cookie已更新")
+	eventbus.BusObj.Publish(TopicLogin, accountUid.Load())
+
+	if config.GlobalConfig == nil {
+		return nil
+	}
+	config.GlobalConfig.Set("bilibili.SESSDATA", cookies.SESSDATA)
+	config.GlobalConfig.Set("bilibili.bili_jct", cookies.BILI_JCT)
+	if err := config.GlobalConfig.WriteConfig(); err != nil {
+		logger.Errorf("保存配置文件失败 - %v", err)
+		return fmt.Errorf("cookie已生效，但保存配置文件失败，重启后需要重新登录 - %v", err)
+	}
+	return nil
+}
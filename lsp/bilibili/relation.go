@@ -28,7 +28,11 @@ type RelationModifyRequest struct {
 }
 
 func RelationModify(fid int64, act int) (*RelationModifyResponse, error) {
-	if !IsVerifyGiven() {
+	return mainAccount.RelationModify(fid, act)
+}
+
+func (a *Account) RelationModify(fid int64, act int) (*RelationModifyResponse, error) {
+	if !a.IsVerifyGiven() {
 		return nil, ErrVerifyRequired
 	}
 	st := time.Now()
@@ -41,7 +45,7 @@ func RelationModify(fid int64, act int) (*RelationModifyResponse, error) {
 	formRequest := &RelationModifyRequest{
 		Fid:  fid,
 		Act:  act,
-		Csrf: GetVerifyBiliJct(),
+		Csrf: a.BiliJct(),
 	}
 	form, err := utils.ToParams(formRequest)
 	if err != nil {
@@ -54,7 +58,7 @@ func RelationModify(fid int64, act int) (*RelationModifyResponse, error) {
 		AddUAOption(),
 		delete412ProxyOption,
 	)
-	opts = append(opts, GetVerifyOption()...)
+	opts = append(opts, a.VerifyOption()...)
 	rmr := new(RelationModifyResponse)
 	err = requests.PostWWWForm(url, form, rmr, opts...)
 	if err != nil {
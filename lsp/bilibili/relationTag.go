@@ -0,0 +1,148 @@
+package bilibili
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
+	"github.com/cnxysoft/DDBOT-WSa/requests"
+	"github.com/cnxysoft/DDBOT-WSa/utils"
+)
+
+const (
+	PathRelationTags = "/x/relation/tags"
+	PathRelationTag  = "/x/relation/tag"
+
+	// relationTagPageSize 查询关注分组成员时每页的数量
+	relationTagPageSize = 50
+)
+
+var ErrRelationTagNotFound = errors.New("关注分组不存在")
+
+type RelationTagInfo struct {
+	TagId int64  `json:"tagid"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type RelationTagsResponse struct {
+	Code    int32              `json:"code"`
+	Message string             `json:"message"`
+	Data    []*RelationTagInfo `json:"data"`
+}
+
+type RelationTagResponse struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+	Data    []*struct {
+		Mid   int64  `json:"mid"`
+		Uname string `json:"uname"`
+	} `json:"data"`
+}
+
+func (a *Account) relationGet(path string, params map[string]interface{}, out interface{}) error {
+	if !a.IsVerifyGiven() {
+		return ErrVerifyRequired
+	}
+	st := time.Now()
+	defer func() {
+		ed := time.Now()
+		logger.WithField("FuncName", utils.FuncName()).Tracef("cost %v", ed.Sub(st))
+	}()
+	var opts []requests.Option
+	opts = append(opts,
+		requests.ProxyOption(proxy_pool.PreferNone),
+		AddUAOption(),
+		requests.TimeoutOption(time.Second*10),
+		delete412ProxyOption,
+	)
+	opts = append(opts, a.VerifyOption()...)
+	return requests.Get(BPath(path), params, out, opts...)
+}
+
+// RelationTags 查询账号的所有关注分组
+func (a *Account) RelationTags() (*RelationTagsResponse, error) {
+	resp := new(RelationTagsResponse)
+	if err := a.relationGet(PathRelationTags, nil, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RelationTag 分页查询关注分组内的用户
+func (a *Account) RelationTag(tagId int64, pn int, ps int) (*RelationTagResponse, error) {
+	resp := new(RelationTagResponse)
+	err := a.relationGet(PathRelationTag, map[string]interface{}{
+		"tagid": tagId,
+		"pn":    pn,
+		"ps":    ps,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// FindRelationTag 按名称查找关注分组，名称不存在时也可以直接使用分组id
+func FindRelationTag(tags []*RelationTagInfo, name string) (*RelationTagInfo, error) {
+	for _, tag := range tags {
+		if tag.Name == name {
+			return tag, nil
+		}
+	}
+	if tagId, err := strconv.ParseInt(name, 10, 64); err == nil {
+		for _, tag := range tags {
+			if tag.TagId == tagId {
+				return tag, nil
+			}
+		}
+	}
+	return nil, ErrRelationTagNotFound
+}
+
+// FollowGroupMids 查询主账号名称为 name 的关注分组内的所有用户
+func FollowGroupMids(name string) ([]int64, error) {
+	tagsResp, err := mainAccount.RelationTags()
+	if err != nil {
+		return nil, err
+	}
+	if tagsResp.Code != 0 {
+		return nil, fmt.Errorf("RelationTags code %v - %v", tagsResp.Code, tagsResp.Message)
+	}
+	tag, err := FindRelationTag(tagsResp.Data, name)
+	if err != nil {
+		return nil, err
+	}
+	var result []int64
+	for pn := 1; ; pn++ {
+		resp, err := mainAccount.RelationTag(tag.TagId, pn, relationTagPageSize)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Code != 0 {
+			return nil, fmt.Errorf("RelationTag code %v - %v", resp.Code, resp.Message)
+		}
+		for _, user := range resp.Data {
+			result = append(result, user.Mid)
+		}
+		if len(resp.Data) < relationTagPageSize {
+			break
+		}
+	}
+	return result, nil
+}
+
+// FollowGroupIds 实现 concern.FollowGroupExt
+func (c *Concern) FollowGroupIds(name string) ([]string, error) {
+	mids, err := FollowGroupMids(name)
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, mid := range mids {
+		result = append(result, strconv.FormatInt(mid, 10))
+	}
+	return result, nil
+}
@@ -0,0 +1,35 @@
+package bilibili
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindRelationTag(t *testing.T) {
+	tags := []*RelationTagInfo{
+		{TagId: 0, Name: "默认分组", Count: 10},
+		{TagId: 123, Name: "虚拟主播", Count: 3},
+		{TagId: 456, Name: "123", Count: 1},
+	}
+	tag, err := FindRelationTag(tags, "虚拟主播")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 123, tag.TagId)
+
+	// 名称优先于分组id
+	tag, err = FindRelationTag(tags, "123")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 456, tag.TagId)
+
+	tag, err = FindRelationTag(tags, "456")
+	assert.Nil(t, err)
+	assert.Equal(t, "123", tag.Name)
+
+	_, err = FindRelationTag(tags, "不存在")
+	assert.Equal(t, ErrRelationTagNotFound, err)
+}
+
+func TestFollowGroupMids(t *testing.T) {
+	_, err := FollowGroupMids("默认分组")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,342 @@
+package bilibili
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
+	"github.com/cnxysoft/DDBOT-WSa/requests"
+	"github.com/cnxysoft/DDBOT-WSa/utils"
+)
+
+const (
+	PathPgcViewWebSeason    = "/pgc/view/web/season"
+	PathPgcReviewUser       = "/pgc/review/user"
+	PathFavSeasonList       = "/x/space/fav/season/list"
+	PathSeasonsArchivesList = "/x/polymer/web-space/seasons_archives_list"
+	PathSeriesSeries        = "/x/series/series"
+	PathSeriesArchives      = "/x/series/archives"
+
+	BangumiPlayView = "https://www.bilibili.com/bangumi/play"
+
+	// 合集和系列每次刷新查看最新的多少个视频
+	seasonArchivesPageSize = 30
+)
+
+var (
+	ErrSeasonNotExist = errors.New("合集或番剧不存在")
+
+	seasonIdRegex = regexp.MustCompile(`^(ss|md)?(\d+)$`)
+)
+
+// ParseSeasonId 番剧支持 ss 开头的 season id 和 md 开头的 media id，合集和系列只支持数字id
+// 也可以直接填链接，会使用链接的最后一段
+func ParseSeasonId(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if pos := strings.IndexAny(s, "?#"); pos >= 0 {
+		s = s[:pos]
+	}
+	s = strings.TrimRight(s, "/")
+	if pos := strings.LastIndex(s, "/"); pos >= 0 {
+		s = s[pos+1:]
+	}
+	if !seasonIdRegex.MatchString(s) {
+		return "", fmt.Errorf("无效的id %v", s)
+	}
+	return s, nil
+}
+
+// SeasonEpisode 番剧的一集或者合集、系列中的一个视频
+type SeasonEpisode struct {
+	// Id 番剧是 ep id，合集和系列是 aid
+	Id    int64  `json:"id"`
+	Bvid  string `json:"bvid"`
+	Title string `json:"title"`
+	Cover string `json:"cover"`
+	PubTs int64  `json:"pub_ts"`
+	Url   string `json:"url"`
+}
+
+// SeasonMeta 订阅的番剧、合集或者系列的信息
+type SeasonMeta struct {
+	Title string
+	Cover string
+	// Mid 合集和系列的up主，查询视频列表时需要
+	Mid    int64
+	UpName string
+	// SeasonId 番剧的 season id
+	SeasonId int64
+}
+
+type PgcSeasonResponse struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+	Result  *struct {
+		SeasonId int64  `json:"season_id"`
+		Title    string `json:"title"`
+		Cover    string `json:"cover"`
+		Episodes []struct {
+			Id        int64  `json:"id"`
+			Bvid      string `json:"bvid"`
+			Cover     string `json:"cover"`
+			Title     string `json:"title"`
+			LongTitle string `json:"long_title"`
+			ShowTitle string `json:"show_title"`
+			PubTime   int64  `json:"pub_time"`
+			Link      string `json:"link"`
+		} `json:"episodes"`
+	} `json:"result"`
+}
+
+type PgcReviewUserResponse struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+	Result  *struct {
+		Media struct {
+			MediaId  int64  `json:"media_id"`
+			SeasonId int64  `json:"season_id"`
+			Title    string `json:"title"`
+			Cover    string `json:"cover"`
+		} `json:"media"`
+	} `json:"result"`
+}
+
+type FavSeasonListResponse struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+	Data    *struct {
+		Info struct {
+			Id    int64  `json:"id"`
+			Title string `json:"title"`
+			Cover string `json:"cover"`
+			Upper struct {
+				Mid  int64  `json:"mid"`
+				Name string `json:"name"`
+			} `json:"upper"`
+		} `json:"info"`
+	} `json:"data"`
+}
+
+type SeriesSeriesResponse struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+	Data    *struct {
+		Meta struct {
+			SeriesId int64  `json:"series_id"`
+			Mid      int64  `json:"mid"`
+			Name     string `json:"name"`
+			Cover    string `json:"cover"`
+		} `json:"meta"`
+	} `json:"data"`
+}
+
+// ArchivesResponse 合集和系列的视频列表
+type ArchivesResponse struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+	Data    *struct {
+		Archives []struct {
+			Aid     int64  `json:"aid"`
+			Bvid    string `json:"bvid"`
+			Title   string `json:"title"`
+			Pic     string `json:"pic"`
+			Pubdate int64  `json:"pubdate"`
+		} `json:"archives"`
+	} `json:"data"`
+}
+
+func seasonGet(path string, params map[string]interface{}, out interface{}) error {
+	st := time.Now()
+	defer func() {
+		ed := time.Now()
+		logger.WithField("FuncName", utils.FuncName()).WithField("Path", path).Tracef("cost %v", ed.Sub(st))
+	}()
+	var opts = []requests.Option{
+		requests.ProxyOption(proxy_pool.PreferNone),
+		requests.TimeoutOption(time.Second * 15),
+		AddUAOption(),
+		AddReferOption("https://www.bilibili.com/"),
+		delete412ProxyOption,
+	}
+	opts = append(opts, GetVerifyOption()...)
+	return requests.Get(BPath(path), params, out, opts...)
+}
+
+func codeError(name string, code int32, message string) error {
+	// -404 以及番剧接口的 -10403 都表示内容不存在或不可见
+	if code == -404 || code == -10403 {
+		return ErrSeasonNotExist
+	}
+	return fmt.Errorf("%v code %v - %v", name, code, message)
+}
+
+// PgcSeason 查询番剧的信息和所有剧集
+func PgcSeason(ssid int64) (*SeasonMeta, []*SeasonEpisode, error) {
+	resp := new(PgcSeasonResponse)
+	err := seasonGet(PathPgcViewWebSeason, map[string]interface{}{"season_id": ssid}, resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ParsePgcSeason(resp)
+}
+
+func ParsePgcSeason(resp *PgcSeasonResponse) (*SeasonMeta, []*SeasonEpisode, error) {
+	if resp.Code != 0 {
+		return nil, nil, codeError("PgcSeason", resp.Code, resp.Message)
+	}
+	if resp.Result == nil {
+		return nil, nil, ErrSeasonNotExist
+	}
+	meta := &SeasonMeta{
+		Title:    resp.Result.Title,
+		Cover:    resp.Result.Cover,
+		SeasonId: resp.Result.SeasonId,
+	}
+	var episodes []*SeasonEpisode
+	for _, ep := range resp.Result.Episodes {
+		title := ep.ShowTitle
+		if len(title) == 0 {
+			title = strings.TrimSpace(ep.Title + " " + ep.LongTitle)
+		}
+		url := ep.Link
+		if len(url) == 0 {
+			url = fmt.Sprintf("%v/ep%v", BangumiPlayView, ep.Id)
+		}
+		episodes = append(episodes, &SeasonEpisode{
+			Id:    ep.Id,
+			Bvid:  ep.Bvid,
+			Title: title,
+			Cover: ep.Cover,
+			PubTs: ep.PubTime,
+			Url:   url,
+		})
+	}
+	return meta, episodes, nil
+}
+
+// PgcMediaSeasonId 通过 media id 查询番剧的 season id
+func PgcMediaSeasonId(mdid int64) (int64, error) {
+	resp := new(PgcReviewUserResponse)
+	err := seasonGet(PathPgcReviewUser, map[string]interface{}{"media_id": mdid}, resp)
+	if err != nil {
+		return 0, err
+	}
+	if resp.Code != 0 {
+		return 0, codeError("PgcReviewUser", resp.Code, resp.Message)
+	}
+	if resp.Result == nil || resp.Result.Media.SeasonId == 0 {
+		return 0, ErrSeasonNotExist
+	}
+	return resp.Result.Media.SeasonId, nil
+}
+
+// CollectionMeta 查询合集的标题和up主
+func CollectionMeta(seasonId int64) (*SeasonMeta, error) {
+	resp := new(FavSeasonListResponse)
+	err := seasonGet(PathFavSeasonList, map[string]interface{}{"season_id": seasonId, "pn": 1, "ps": 1}, resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != 0 {
+		return nil, codeError("FavSeasonList", resp.Code, resp.Message)
+	}
+	if resp.Data == nil || resp.Data.Info.Upper.Mid == 0 {
+		return nil, ErrSeasonNotExist
+	}
+	return &SeasonMeta{
+		Title:  resp.Data.Info.Title,
+		Cover:  resp.Data.Info.Cover,
+		Mid:    resp.Data.Info.Upper.Mid,
+		UpName: resp.Data.Info.Upper.Name,
+	}, nil
+}
+
+// CollectionArchives 查询合集中最后加入的视频
+func CollectionArchives(mid int64, seasonId int64) ([]*SeasonEpisode, error) {
+	resp := new(ArchivesResponse)
+	err := seasonGet(PathSeasonsArchivesList, map[string]interface{}{
+		"mid":          mid,
+		"season_id":    seasonId,
+		"sort_reverse": true,
+		"page_num":     1,
+		"page_size":    seasonArchivesPageSize,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+	return ParseArchives("SeasonsArchivesList", resp)
+}
+
+// SeriesMeta 查询系列的标题和up主
+func SeriesMeta(seriesId int64) (*SeasonMeta, error) {
+	resp := new(SeriesSeriesResponse)
+	err := seasonGet(PathSeriesSeries, map[string]interface{}{"series_id": seriesId}, resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != 0 {
+		return nil, codeError("SeriesSeries", resp.Code, resp.Message)
+	}
+	if resp.Data == nil || resp.Data.Meta.Mid == 0 {
+		return nil, ErrSeasonNotExist
+	}
+	return &SeasonMeta{
+		Title: resp.Data.Meta.Name,
+		Cover: resp.Data.Meta.Cover,
+		Mid:   resp.Data.Meta.Mid,
+	}, nil
+}
+
+// SeriesArchives 查询系列中最新发布的视频
+func SeriesArchives(mid int64, seriesId int64) ([]*SeasonEpisode, error) {
+	resp := new(ArchivesResponse)
+	err := seasonGet(PathSeriesArchives, map[string]interface{}{
+		"mid":         mid,
+		"series_id":   seriesId,
+		"only_normal": true,
+		"sort":        "desc",
+		"pn":          1,
+		"ps":          seasonArchivesPageSize,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+	return ParseArchives("SeriesArchives", resp)
+}
+
+// ParseArchives 接口返回的视频是从新到旧，这里会反转成从旧到新
+func ParseArchives(name string, resp *ArchivesResponse) ([]*SeasonEpisode, error) {
+	if resp.Code != 0 {
+		return nil, codeError(name, resp.Code, resp.Message)
+	}
+	if resp.Data == nil {
+		return nil, ErrSeasonNotExist
+	}
+	var episodes []*SeasonEpisode
+	for i := len(resp.Data.Archives) - 1; i >= 0; i-- {
+		archive := resp.Data.Archives[i]
+		episodes = append(episodes, &SeasonEpisode{
+			Id:    archive.Aid,
+			Bvid:  archive.Bvid,
+			Title: archive.Title,
+			Cover: archive.Pic,
+			PubTs: archive.Pubdate,
+			Url:   BVIDUrl(archive.Bvid),
+		})
+	}
+	return episodes, nil
+}
+
+// seasonNumericId 去掉 ss / md 前缀
+func seasonNumericId(id string) (prefix string, n int64, err error) {
+	m := seasonIdRegex.FindStringSubmatch(id)
+	if m == nil {
+		return "", 0, fmt.Errorf("无效的id %v", id)
+	}
+	n, err = strconv.ParseInt(m[2], 10, 64)
+	return m[1], n, err
+}
@@ -0,0 +1,90 @@
+package bilibili
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPgcSeason = `{
+	"code": 0,
+	"message": "success",
+	"result": {
+		"season_id": 39462,
+		"title": "测试番剧",
+		"cover": "https://i0.hdslb.com/bfs/bangumi/cover.jpg",
+		"episodes": [
+			{"id": 692287, "bvid": "BV1a", "cover": "https://i0.hdslb.com/ep1.jpg", "title": "1", "long_title": "第一话", "show_title": "第1话 第一话", "pub_time": 1667232000, "link": "https://www.bilibili.com/bangumi/play/ep692287"},
+			{"id": 692288, "bvid": "BV1b", "cover": "https://i0.hdslb.com/ep2.jpg", "title": "2", "long_title": "第二话", "pub_time": 1667836800}
+		]
+	}
+}`
+
+const testArchives = `{
+	"code": 0,
+	"message": "0",
+	"data": {
+		"archives": [
+			{"aid": 3, "bvid": "BV1c", "title": "新视频", "pic": "https://i0.hdslb.com/3.jpg", "pubdate": 1700000300},
+			{"aid": 2, "bvid": "BV1b", "title": "旧视频", "pic": "https://i0.hdslb.com/2.jpg", "pubdate": 1700000200}
+		]
+	}
+}`
+
+func TestParseSeasonId(t *testing.T) {
+	for s, expected := range map[string]string{
+		"ss39462":    "ss39462",
+		"MD28339913": "md28339913",
+		"123":        "123",
+		"https://www.bilibili.com/bangumi/media/md28339913/?spm=1": "md28339913",
+		"https://www.bilibili.com/bangumi/play/ss39462":            "ss39462",
+	} {
+		id, err := ParseSeasonId(s)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, id)
+	}
+	for _, s := range []string{"", "ep123", "abc", "ss"} {
+		_, err := ParseSeasonId(s)
+		assert.NotNil(t, err)
+	}
+
+	prefix, n, err := seasonNumericId("md28339913")
+	assert.Nil(t, err)
+	assert.Equal(t, "md", prefix)
+	assert.EqualValues(t, 28339913, n)
+}
+
+func TestParsePgcSeason(t *testing.T) {
+	resp := new(PgcSeasonResponse)
+	require.Nil(t, json.Unmarshal([]byte(testPgcSeason), resp))
+	meta, episodes, err := ParsePgcSeason(resp)
+	require.Nil(t, err)
+	assert.Equal(t, "测试番剧", meta.Title)
+	assert.EqualValues(t, 39462, meta.SeasonId)
+	require.Len(t, episodes, 2)
+	assert.Equal(t, "第1话 第一话", episodes[0].Title)
+	assert.Equal(t, "2 第二话", episodes[1].Title)
+	assert.Equal(t, "https://www.bilibili.com/bangumi/play/ep692288", episodes[1].Url)
+
+	_, _, err = ParsePgcSeason(&PgcSeasonResponse{Code: -404, Message: "啥都木有"})
+	assert.Equal(t, ErrSeasonNotExist, err)
+	_, _, err = ParsePgcSeason(&PgcSeasonResponse{Code: -412, Message: "请求被拦截"})
+	assert.NotNil(t, err)
+	assert.NotEqual(t, ErrSeasonNotExist, err)
+}
+
+func TestParseArchives(t *testing.T) {
+	resp := new(ArchivesResponse)
+	require.Nil(t, json.Unmarshal([]byte(testArchives), resp))
+	episodes, err := ParseArchives("test", resp)
+	require.Nil(t, err)
+	require.Len(t, episodes, 2)
+	// 从旧到新
+	assert.EqualValues(t, 2, episodes[0].Id)
+	assert.EqualValues(t, 3, episodes[1].Id)
+	assert.Equal(t, BVIDUrl("BV1c"), episodes[1].Url)
+
+	_, err = ParseArchives("test", &ArchivesResponse{Code: -400, Message: "请求错误"})
+	assert.NotNil(t, err)
+}
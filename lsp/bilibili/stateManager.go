@@ -5,8 +5,12 @@ import (
 	"github.com/Mrs4s/MiraiGo/message"
 	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/tidwall/buntdb"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,8 +20,8 @@ type StateManager struct {
 	concern *Concern
 }
 
-func (c *StateManager) GetGroupConcernConfig(groupCode int64, id interface{}) (concernConfig concern.IConfig) {
-	return NewGroupConcernConfig(c.StateManager.GetGroupConcernConfig(groupCode, id), c.concern)
+func (c *StateManager) GetGroupConcernConfig(target mmsg.Target, id interface{}) (concernConfig concern.IConfig) {
+	return NewGroupConcernConfig(c.StateManager.GetGroupConcernConfig(target, id), c.concern)
 }
 
 func (c *StateManager) AddUserInfo(userInfo *UserInfo) error {
@@ -187,8 +191,8 @@ func (c *StateManager) GetUidFirstTimestamp(uid int64) (timestamp int64, err err
 	return c.GetInt64(c.UidFirstTimestamp(uid))
 }
 
-func (c *StateManager) SetGroupCompactMarkIfNotExist(groupCode int64, compactKey string) error {
-	return c.Set(c.CompactMarkKey(groupCode, compactKey), "",
+func (c *StateManager) SetGroupCompactMarkIfNotExist(target mmsg.Target, compactKey string) error {
+	return c.Set(c.CompactMarkKey(target, compactKey), "",
 		localdb.SetExpireOpt(CompactExpireTime), localdb.SetNoOverWriteOpt())
 }
 func (c *StateManager) SetLastFreshTime(ts int64) error {
@@ -231,18 +235,56 @@ func (c *StateManager) SetNotifyMsg(notifyKey string, msg *message.GroupMessage)
 	if err != nil {
 		return err
 	}
-	return c.Set(c.NotifyMsgKey(tmp.GroupCode, notifyKey), value,
+	return c.Set(c.NotifyMsgKey(mmsg.NewGroupTarget(tmp.GroupCode), notifyKey), value,
 		localdb.SetExpireOpt(CompactExpireTime), localdb.SetNoOverWriteOpt())
 }
 
-func (c *StateManager) GetNotifyMsg(groupCode int64, notifyKey string) (*message.GroupMessage, error) {
-	value, err := c.Get(c.NotifyMsgKey(groupCode, notifyKey))
+func (c *StateManager) GetNotifyMsg(target mmsg.Target, notifyKey string) (*message.GroupMessage, error) {
+	value, err := c.Get(c.NotifyMsgKey(target, notifyKey))
 	if err != nil {
 		return nil, err
 	}
 	return localutils.DeserializationGroupMsg(value)
 }
 
+// SetAccountShard 记录负责刷新该用户的账号
+func (c *StateManager) SetAccountShard(mid int64, account string) error {
+	return c.Set(c.AccountShardKey(mid), account)
+}
+
+// GetAccountShard 返回负责刷新该用户的账号，没有记录时返回 MainAccountName
+func (c *StateManager) GetAccountShard(mid int64) string {
+	account, err := c.Get(c.AccountShardKey(mid), localdb.IgnoreNotFoundOpt())
+	if err != nil || len(account) == 0 {
+		return MainAccountName
+	}
+	return account
+}
+
+func (c *StateManager) DeleteAccountShard(mid int64) error {
+	_, err := c.Delete(c.AccountShardKey(mid), localdb.IgnoreNotFoundOpt())
+	return err
+}
+
+// ListAccountShard 返回所有记录过的 mid -> 账号
+func (c *StateManager) ListAccountShard() (map[int64]string, error) {
+	var result = make(map[int64]string)
+	prefix := c.AccountShardKey() + ":"
+	err := c.RCoverTx(func(tx *buntdb.Tx) error {
+		return tx.AscendKeys(prefix+"*", func(key, value string) bool {
+			mid, err := strconv.ParseInt(strings.TrimPrefix(key, prefix), 10, 64)
+			if err == nil {
+				result[mid] = value
+			}
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func SetCookieInfo(username string, cookieInfo *LoginResponse_Data_CookieInfo) error {
 	if cookieInfo == nil {
 		return errors.New("<nil> cookieInfo")
@@ -286,3 +328,44 @@ func NewStateManager(c *Concern) *StateManager {
 	sm.StateManager = concern.NewStateManagerWithCustomKey(Site, NewKeySet(), c.notify)
 	return sm
 }
+
+// SeasonStateManager 番剧、合集和系列使用单独的site和string类型的id
+type SeasonStateManager struct {
+	*concern.StateManager
+	*extraKey
+}
+
+func (c *SeasonStateManager) AddSeasonInfo(info *SeasonInfo) error {
+	if info == nil {
+		return errors.New("nil SeasonInfo")
+	}
+	return c.SetJson(c.SeasonInfoKey(info.Ctype.String(), info.Id), info)
+}
+
+func (c *SeasonStateManager) GetSeasonInfo(ctype concern_type.Type, id string) (*SeasonInfo, error) {
+	var info = &SeasonInfo{}
+	err := c.GetJson(c.SeasonInfoKey(ctype.String(), id), info)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (c *SeasonStateManager) RemoveSeasonInfo(ctype concern_type.Type, id string) error {
+	_, err := c.Delete(c.SeasonInfoKey(ctype.String(), id), localdb.IgnoreNotFoundOpt())
+	return err
+}
+
+// MarkEpisode 标记已经推送过的剧集或视频，返回是否已经标记过
+func (c *SeasonStateManager) MarkEpisode(ctype concern_type.Type, id string, episodeId int64) (replaced bool, err error) {
+	err = c.Set(c.MarkEpisodeKey(ctype.String(), id, episodeId), "",
+		localdb.SetExpireOpt(time.Hour*120), localdb.SetGetIsOverwriteOpt(&replaced))
+	return
+}
+
+func NewSeasonStateManager(notify chan<- concern.Notify) *SeasonStateManager {
+	return &SeasonStateManager{
+		StateManager: concern.NewStateManagerWithStringID(SeasonSite, notify),
+		extraKey:     NewExtraKey(),
+	}
+}
@@ -133,6 +133,22 @@ func TestRWTxCover(t *testing.T) {
 	assert.Greater(t, ttl, time.Hour*47)
 }
 
+func TestInTx(t *testing.T) {
+	assert.Nil(t, InitBuntDB(MEMORYDB))
+	defer Close()
+
+	assert.False(t, InTx())
+	assert.Nil(t, RWCover(func() error {
+		assert.True(t, InTx())
+		return nil
+	}))
+	assert.Nil(t, RCover(func() error {
+		assert.True(t, InTx())
+		return nil
+	}))
+	assert.False(t, InTx())
+}
+
 func TestNestedCover(t *testing.T) {
 	var err error
 	err = InitBuntDB(MEMORYDB)
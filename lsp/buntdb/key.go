@@ -30,6 +30,11 @@ func Key(keys ...interface{}) string {
 		case reflect.Bool:
 			_keys = append(_keys, strconv.FormatBool(rk.Bool()))
 		default:
+			// 例如 mmsg.Target，使用 String 作为key
+			if s, ok := ikey.(fmt.Stringer); ok {
+				_keys = append(_keys, s.String())
+				continue
+			}
 			panic("unsupported key type " + reflect.ValueOf(ikey).Type().Name())
 		}
 	}
@@ -92,6 +97,15 @@ func BilibiliActiveTimestampKey(keys ...interface{}) string {
 func BilibiliLastFreshKey(keys ...interface{}) string {
 	return NamedKey("BilibiliLastFresh", keys)
 }
+func BilibiliAccountShardKey(keys ...interface{}) string {
+	return NamedKey("BilibiliAccountShard", keys)
+}
+func BilibiliSeasonInfoKey(keys ...interface{}) string {
+	return NamedKey("BilibiliSeasonInfo", keys)
+}
+func BilibiliMarkEpisodeKey(keys ...interface{}) string {
+	return NamedKey("BilibiliMarkEpisode", keys)
+}
 func DouyuGroupConcernStateKey(keys ...interface{}) string {
 	return NamedKey("DouyuConcernState", keys)
 }
@@ -125,6 +139,12 @@ func YoutubeInfoKey(keys ...interface{}) string {
 func YoutubeVideoKey(keys ...interface{}) string {
 	return NamedKey("YoutubeVideo", keys)
 }
+func YoutubePostKey(keys ...interface{}) string {
+	return NamedKey("YoutubePost", keys)
+}
+func YoutubeReminderKey(keys ...interface{}) string {
+	return NamedKey("YoutubeReminder", keys)
+}
 func YoutubeGroupAtAllMarkKey(keys ...interface{}) string {
 	return NamedKey("YoutubeGroupAtAll", keys)
 }
@@ -164,6 +184,18 @@ func WeiboNewsInfoKey(keys ...interface{}) string {
 func WeiboMarkMblogIdKey(keys ...interface{}) string {
 	return NamedKey("WeiboMarkMblogId", keys)
 }
+func WeiboReplyInfoKey(keys ...interface{}) string {
+	return NamedKey("WeiboReplyInfo", keys)
+}
+func WeiboMarkCommentIdKey(keys ...interface{}) string {
+	return NamedKey("WeiboMarkCommentId", keys)
+}
+func WeiboTopicInfoKey(keys ...interface{}) string {
+	return NamedKey("WeiboTopicInfo", keys)
+}
+func WeiboMarkTopicMblogIdKey(keys ...interface{}) string {
+	return NamedKey("WeiboMarkTopicMblogId", keys)
+}
 func TwitterUserInfoKey(keys ...interface{}) string {
 	return NamedKey("TwitterUserInfo", keys)
 }
@@ -189,6 +221,42 @@ func DouyinFreshKey(keys ...interface{}) string {
 func DouyinCurrentLiveKey(keys ...interface{}) string {
 	return NamedKey("DouyinCurrentLive", keys)
 }
+func DouyinNewsTimeKey(keys ...interface{}) string {
+	return NamedKey("DouyinNewsTime", keys)
+}
+func DouyinMarkAwemeKey(keys ...interface{}) string {
+	return NamedKey("DouyinMarkAweme", keys)
+}
+func TwitchGroupConcernStateKey(keys ...interface{}) string {
+	return NamedKey("TwitchConcernState", keys)
+}
+func TwitchGroupConcernConfigKey(keys ...interface{}) string {
+	return NamedKey("TwitchConcernConfig", keys)
+}
+func TwitchFreshKey(keys ...interface{}) string {
+	return NamedKey("TwitchFresh", keys)
+}
+func TwitchCurrentLiveKey(keys ...interface{}) string {
+	return NamedKey("TwitchCurrentLive", keys)
+}
+func TwitchGroupAtAllMarkKey(keys ...interface{}) string {
+	return NamedKey("TwitchGroupAtAll", keys)
+}
+func KickGroupConcernStateKey(keys ...interface{}) string {
+	return NamedKey("KickConcernState", keys)
+}
+func KickGroupConcernConfigKey(keys ...interface{}) string {
+	return NamedKey("KickConcernConfig", keys)
+}
+func KickFreshKey(keys ...interface{}) string {
+	return NamedKey("KickFresh", keys)
+}
+func KickCurrentLiveKey(keys ...interface{}) string {
+	return NamedKey("KickCurrentLive", keys)
+}
+func KickGroupAtAllMarkKey(keys ...interface{}) string {
+	return NamedKey("KickGroupAtAll", keys)
+}
 
 func PermissionKey(keys ...interface{}) string {
 	return NamedKey("Permission", keys)
@@ -208,12 +276,39 @@ func GlobalEnabledKey(keys ...interface{}) string {
 func GroupMessageImageKey(keys ...interface{}) string {
 	return NamedKey("GroupMessageImage", keys)
 }
+func GroupMessageFileKey(keys ...interface{}) string {
+	return NamedKey("GroupMessageFile", keys)
+}
+func GroupMessageArchiveKey(keys ...interface{}) string {
+	return NamedKey("GroupMessageArchive", keys)
+}
+func GroupPersonKey(keys ...interface{}) string {
+	return NamedKey("GroupPerson", keys)
+}
+func GroupPersonMemberKey(keys ...interface{}) string {
+	return NamedKey("GroupPersonMember", keys)
+}
+func GroupPersonLivingKey(keys ...interface{}) string {
+	return NamedKey("GroupPersonLiving", keys)
+}
+func GroupPersonNotifyKey(keys ...interface{}) string {
+	return NamedKey("GroupPersonNotify", keys)
+}
 func GroupSilenceKey(keys ...interface{}) string {
 	return NamedKey("GroupSilence", keys)
 }
 func GlobalSilenceKey(keys ...interface{}) string {
 	return NamedKey("GlobalSilence", keys)
 }
+func GroupLocaleKey(keys ...interface{}) string {
+	return NamedKey("GroupLocale", keys)
+}
+func GroupConcernAliasKey(keys ...interface{}) string {
+	return NamedKey("GroupConcernAlias", keys)
+}
+func GroupConcernAliasIdKey(keys ...interface{}) string {
+	return NamedKey("GroupConcernAliasId", keys)
+}
 func GroupMuteKey(keys ...interface{}) string {
 	return NamedKey("GroupMute", keys)
 }
@@ -251,6 +346,24 @@ func DDBotNoUpdateKey(keys ...interface{}) string {
 	return NamedKey("DDBotNoUpdateKey", keys)
 }
 
+func OutboxKey(keys ...interface{}) string {
+	return NamedKey("Outbox", keys)
+}
+func OutboxDeadLetterKey(keys ...interface{}) string {
+	return NamedKey("OutboxDeadLetter", keys)
+}
+func OutboxSeqKey() string {
+	return NamedKey("OutboxSeq", nil)
+}
+
+func PostedNotifyKey(keys ...interface{}) string {
+	return NamedKey("PostedNotify", keys)
+}
+
+func LiveSessionKey(keys ...interface{}) string {
+	return NamedKey("LiveSession", keys)
+}
+
 func ParseConcernStateKeyWithInt64(key string) (groupCode int64, id int64, err error) {
 	keys := strings.Split(key, ":")
 	if len(keys) != 3 {
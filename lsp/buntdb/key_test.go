@@ -50,6 +50,8 @@ func TestParseConcernStateKeyWithString(t *testing.T) {
 	var testCase = []string{
 		YoutubeGroupConcernStateKey(GroupCode1, Sid),
 		HuyaGroupConcernStateKey(GroupCode1, Sid),
+		TwitchGroupConcernStateKey(GroupCode1, Sid),
+		KickGroupConcernStateKey(GroupCode1, Sid),
 	}
 	var expected = [][]interface{}{
 		{
@@ -58,6 +60,12 @@ func TestParseConcernStateKeyWithString(t *testing.T) {
 		{
 			GroupCode1, Sid,
 		},
+		{
+			GroupCode1, Sid,
+		},
+		{
+			GroupCode1, Sid,
+		},
 	}
 	assert.Equal(t, len(expected), len(testCase))
 	for index := range testCase {
@@ -106,6 +114,8 @@ func TestKeys(t *testing.T) {
 	YoutubeInfoKey()
 	YoutubeVideoKey()
 	YoutubeGroupAtAllMarkKey()
+	YoutubePostKey()
+	YoutubeReminderKey()
 	HuyaGroupConcernStateKey()
 	HuyaGroupConcernConfigKey()
 	HuyaFreshKey()
@@ -117,8 +127,16 @@ func TestKeys(t *testing.T) {
 	GroupEnabledKey()
 	GlobalEnabledKey()
 	GroupMessageImageKey()
+	GroupMessageFileKey()
+	GroupPersonKey()
+	GroupPersonMemberKey()
+	GroupPersonLivingKey()
+	GroupPersonNotifyKey()
 	GroupSilenceKey()
 	GlobalSilenceKey()
+	GroupLocaleKey()
+	GroupConcernAliasKey()
+	GroupConcernAliasIdKey()
 	GroupMuteKey()
 	GroupInvitorKey()
 	LoliconPoolStoreKey()
@@ -135,6 +153,24 @@ func TestKeys(t *testing.T) {
 	WeiboMarkMblogIdKey()
 	WeiboNewsInfoKey()
 	WeiboUserInfoKey()
+	WeiboReplyInfoKey()
+	WeiboMarkCommentIdKey()
+	WeiboTopicInfoKey()
+	WeiboMarkTopicMblogIdKey()
+	DouyinNewsTimeKey()
+	DouyinMarkAwemeKey()
+	BilibiliSeasonInfoKey()
+	BilibiliMarkEpisodeKey()
+	TwitchGroupConcernStateKey()
+	TwitchGroupConcernConfigKey()
+	TwitchFreshKey()
+	TwitchCurrentLiveKey()
+	TwitchGroupAtAllMarkKey()
+	KickGroupConcernStateKey()
+	KickGroupConcernConfigKey()
+	KickFreshKey()
+	KickCurrentLiveKey()
+	KickGroupAtAllMarkKey()
 	assert.Panics(t, func() {
 		BilibiliGroupConcernStateKey(&struct{}{})
 	})
@@ -142,3 +178,15 @@ func TestKeys(t *testing.T) {
 		Key(nil)
 	})
 }
+
+type testStringer struct {
+	s string
+}
+
+func (t *testStringer) String() string {
+	return t.s
+}
+
+func TestKeyStringer(t *testing.T) {
+	assert.Equal(t, "ConcernState:g123456:777", BilibiliGroupConcernStateKey(&testStringer{"g123456"}, Uid))
+}
@@ -354,6 +354,11 @@ func RCover(f func() error) error {
 	return shortCut.RCover(f)
 }
 
+// InTx 返回当前Goroutine是否处于事务中，处于事务中时的修改在事务结束前都有可能回滚
+func InTx() bool {
+	return gls.Get(txKey) != nil
+}
+
 // SeqNext 将key上的int64值加上1并保存，返回保存后的值。
 // 如果key不存在，则会默认其为0，返回值为1
 // 等价于 IncInt64(key, 1)
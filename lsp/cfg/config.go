@@ -2,6 +2,7 @@ package cfg
 
 import (
 	"errors"
+	"fmt"
 	"github.com/Sora233/MiraiGo-Template/config"
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cast"
@@ -147,6 +148,127 @@ func GetNotifyParallel() int {
 	return parallel
 }
 
+// GetNotifyGlobalLimit 每分钟最多发送的推送数量，默认为30，设置为0时不限制
+func GetNotifyGlobalLimit() int64 {
+	if !config.GlobalConfig.IsSet("notify.globalLimit") {
+		return 30
+	}
+	return config.GlobalConfig.GetInt64("notify.globalLimit")
+}
+
+// GetNotifyGroupLimit 每个群每分钟最多发送的推送数量，默认为0不限制
+func GetNotifyGroupLimit() int64 {
+	return config.GlobalConfig.GetInt64("notify.groupLimit")
+}
+
 func GetBilibiliOnlyOnlineNotify() bool {
 	return config.GlobalConfig.GetBool("bilibili.onlyOnlineNotify")
 }
+
+// GetBilibiliDanmakuSuperChatPrice 推送醒目留言的最低金额，单位为元，默认推送所有醒目留言
+func GetBilibiliDanmakuSuperChatPrice() float64 {
+	return config.GlobalConfig.GetFloat64("bilibili.danmaku.superChatPrice")
+}
+
+// GetBilibiliDanmakuGuardLevel 推送的最低大航海等级，3为舰长，2为提督，1为总督，默认为3
+func GetBilibiliDanmakuGuardLevel() int {
+	var level = config.GlobalConfig.GetInt("bilibili.danmaku.guardLevel")
+	if level <= 0 || level > 3 {
+		level = 3
+	}
+	return level
+}
+
+// GetBilibiliDanmakuGiftPrice 推送礼物的最低总价值，单位为元，默认为100
+func GetBilibiliDanmakuGiftPrice() float64 {
+	if !config.GlobalConfig.IsSet("bilibili.danmaku.giftPrice") {
+		return 100
+	}
+	return config.GlobalConfig.GetFloat64("bilibili.danmaku.giftPrice")
+}
+
+type BilibiliAccount struct {
+	Name     string `yaml:"name" mapstructure:"name"`
+	SESSDATA string `yaml:"SESSDATA" mapstructure:"SESSDATA"`
+	BiliJct  string `yaml:"bili_jct" mapstructure:"bili_jct"`
+}
+
+// GetBilibiliAccounts 返回额外配置的b站账号，订阅会分摊到这些账号上
+func GetBilibiliAccounts() []*BilibiliAccount {
+	var result []*BilibiliAccount
+	if err := config.GlobalConfig.UnmarshalKey("bilibili.accounts", &result); err != nil {
+		logger.Errorf("GetBilibiliAccounts UnmarshalKey <bilibili.accounts> error %v", err)
+		return nil
+	}
+	return result
+}
+
+func GetMessageArchiveEnable() bool {
+	return config.GlobalConfig.GetBool("messageArchive.enable")
+}
+
+// GetMessageArchiveRetention 返回群消息存档的保留时间，
+// 优先使用 messageArchive.groups.<群号> 的配置，为0时表示该群不存档
+func GetMessageArchiveRetention(groupCode int64) time.Duration {
+	groupKey := fmt.Sprintf("messageArchive.groups.%v", groupCode)
+	if config.GlobalConfig.IsSet(groupKey) {
+		return config.GlobalConfig.GetDuration(groupKey)
+	}
+	var retention = config.GlobalConfig.GetDuration("messageArchive.retention")
+	if retention <= 0 {
+		retention = time.Hour * 72
+	}
+	return retention
+}
+
+// GetYoutubeRemindBefore 返回youtube直播和首播开始前多久发送提醒，例如 [30m, 5m]
+func GetYoutubeRemindBefore() []time.Duration {
+	var result []time.Duration
+	for _, s := range config.GlobalConfig.GetStringSlice("youtube.remindBefore") {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			logger.Errorf("GetYoutubeRemindBefore: invalid duration <%v>", s)
+			continue
+		}
+		result = append(result, d)
+	}
+	return result
+}
+
+// GetPersonLiveWindow 返回同一个人物在多个平台开播时合并推送的时间窗口，默认为10分钟，设置为0时不合并
+func GetPersonLiveWindow() time.Duration {
+	if !config.GlobalConfig.IsSet("person.liveWindow") {
+		return time.Minute * 10
+	}
+	return config.GlobalConfig.GetDuration("person.liveWindow")
+}
+
+// GetOutboxEnable 返回是否把发送失败的推送保存到发件箱中重试，默认开启
+func GetOutboxEnable() bool {
+	if !config.GlobalConfig.IsSet("outbox.enable") {
+		return true
+	}
+	return config.GlobalConfig.GetBool("outbox.enable")
+}
+
+// OutboxPolicy 发件箱的重试策略，第n次重试的间隔为 Backoff * 2^n，最长为 MaxBackoff
+type OutboxPolicy struct {
+	MaxRetry   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// GetOutboxPolicy 使用 outbox.policy.<reason> 中的配置覆盖默认的重试策略
+func GetOutboxPolicy(reason string, def OutboxPolicy) OutboxPolicy {
+	prefix := fmt.Sprintf("outbox.policy.%v.", reason)
+	if config.GlobalConfig.IsSet(prefix + "maxRetry") {
+		def.MaxRetry = config.GlobalConfig.GetInt(prefix + "maxRetry")
+	}
+	if d := config.GlobalConfig.GetDuration(prefix + "backoff"); d > 0 {
+		def.Backoff = d
+	}
+	if d := config.GlobalConfig.GetDuration(prefix + "maxBackoff"); d > 0 {
+		def.MaxBackoff = d
+	}
+	return def
+}
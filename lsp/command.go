@@ -37,6 +37,13 @@ var CommandMaps = map[string]string{
 	"NoUpdateCommand":      NoUpdateCommand,
 	"AbnormalConcernCheck": AbnormalConcernCheck,
 	"CleanConcern":         CleanConcern,
+	"LoginCommand":         LoginCommand,
+	"SearchCommand":        SearchCommand,
+	"PreviewCommand":       PreviewCommand,
+	"MirrorCommand":        MirrorCommand,
+	"OutboxCommand":        OutboxCommand,
+	"LocaleCommand":        LocaleCommand,
+	"CopyCommand":          CopyCommand,
 }
 
 const (
@@ -55,6 +62,10 @@ const (
 	ReverseCommand = "倒放"
 	HelpCommand    = "help"
 	ConfigCommand  = "config"
+	SearchCommand  = "search"
+	PreviewCommand = "preview"
+	LocaleCommand  = "locale"
+	CopyCommand    = "copy"
 )
 
 // private command
@@ -73,6 +84,9 @@ const (
 	NoUpdateCommand      = "退订更新"
 	AbnormalConcernCheck = "检测异常订阅"
 	CleanConcern         = "清除订阅"
+	LoginCommand         = "login"
+	MirrorCommand        = "mirror"
+	OutboxCommand        = "outbox"
 )
 
 var allGroupCommand = [...]string{
@@ -83,6 +97,8 @@ var allGroupCommand = [...]string{
 	ReverseCommand, ConfigCommand,
 	HelpCommand, ScoreCommand, AdminCommand,
 	SilenceCommand, NoUpdateCommand, CleanConcern,
+	SearchCommand, PreviewCommand, LocaleCommand,
+	CopyCommand,
 }
 
 var allPrivateOperate = [...]string{
@@ -93,7 +109,9 @@ var allPrivateOperate = [...]string{
 	WhosyourdaddyCommand, QuitCommand, ModeCommand,
 	GroupRequestCommand, FriendRequestCommand, AdminCommand,
 	SilenceCommand, NoUpdateCommand, AbnormalConcernCheck,
-	CleanConcern,
+	CleanConcern, LoginCommand, SearchCommand,
+	MirrorCommand, PreviewCommand, OutboxCommand,
+	LocaleCommand, CopyCommand,
 }
 
 var nonOprateable = [...]string{
@@ -102,7 +120,8 @@ var nonOprateable = [...]string{
 	WhosyourdaddyCommand, QuitCommand, ModeCommand,
 	GroupRequestCommand, FriendRequestCommand, AdminCommand,
 	SilenceCommand, NoUpdateCommand, AbnormalConcernCheck,
-	CleanConcern,
+	CleanConcern, LoginCommand, MirrorCommand,
+	OutboxCommand, LocaleCommand, CopyCommand,
 }
 
 func CheckValidCommand(command string) bool {
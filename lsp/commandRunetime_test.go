@@ -1,11 +1,14 @@
 package lsp
 
 import (
+	"github.com/alecthomas/kong"
 	"github.com/cnxysoft/DDBOT-WSa/internal/test"
 	tc "github.com/cnxysoft/DDBOT-WSa/internal/test_concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -75,3 +78,33 @@ func TestNewRuntime(t *testing.T) {
 	assert.Empty(t, output)
 	assert.True(t, r.exit)
 }
+
+func TestRuntime_Locale(t *testing.T) {
+	var testCmd struct {
+		Site string `optional:"" short:"s" help:"网站参数"`
+		Id   string `arg:"" help:"配置的主播id"`
+	}
+
+	r := NewRuntime(Instance)
+	r.Command = "test"
+	r.Args = []string{"-h"}
+	_, output := r.parseCommandSyntax(&testCmd, "test", kong.Description("设置沉默模式"))
+	assert.Contains(t, output, "设置沉默模式")
+	assert.Contains(t, output, "网站参数")
+
+	r = NewRuntime(Instance)
+	r.locale = i18n.English
+	r.Command = "test"
+	r.Args = []string{"-h"}
+	_, output = r.parseCommandSyntax(&testCmd, "test", kong.Description("设置沉默模式"))
+	assert.Contains(t, output, "Set silence mode")
+	assert.Contains(t, output, "id of the streamer to configure")
+	assert.NotContains(t, output, "网站参数")
+
+	r = NewRuntime(Instance)
+	r.locale = i18n.English
+	r.Command = "test"
+	r.Args = []string{"--unknown"}
+	_, output = r.parseCommandSyntax(&testCmd, "test")
+	assert.True(t, strings.HasPrefix(output, "Failed to parse arguments - "))
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/alecthomas/kong"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/parser"
 	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"io"
@@ -19,6 +20,8 @@ type Runtime struct {
 	debug   bool
 	exit    bool
 	silence bool
+	// locale 回复使用的语言
+	locale string
 }
 
 func (r *Runtime) Exit(int) {
@@ -33,6 +36,9 @@ func (r *Runtime) parseCommandSyntax(ast interface{}, name string, options ...ko
 	cmdOut := &strings.Builder{}
 	// kong 错误信息不太友好
 	options = append(options, kong.Name(name), kong.UsageOnError(), kong.Exit(r.Exit))
+	if !i18n.IsDefault(r.locale) {
+		options = append(options, localizeHelp(r.locale))
+	}
 	k, err := kong.New(ast, options...)
 	if err != nil {
 		logger.Errorf("kong new failed %v", err)
@@ -54,13 +60,38 @@ func (r *Runtime) parseCommandSyntax(ast interface{}, name string, options ...ko
 		r.Exit(0)
 		var out string
 		if !r.silence {
-			out = fmt.Sprintf("参数解析失败 - %v", err)
+			out = i18n.Sprintf(r.locale, "参数解析失败 - %v", err)
 		}
 		return nil, out
 	}
 	return ctx, ""
 }
 
+// localizeHelp 把命令的帮助信息翻译成 locale 对应的语言，需要在 kong.Description 之后生效
+func localizeHelp(locale string) kong.Option {
+	var walk func(node *kong.Node)
+	walk = func(node *kong.Node) {
+		node.Help = i18n.Tr(locale, node.Help)
+		node.Detail = i18n.Tr(locale, node.Detail)
+		if node.Argument != nil {
+			node.Argument.Help = i18n.Tr(locale, node.Argument.Help)
+		}
+		for _, flag := range node.Flags {
+			flag.Help = i18n.Tr(locale, flag.Help)
+		}
+		for _, positional := range node.Positional {
+			positional.Help = i18n.Tr(locale, positional.Help)
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	return kong.PostBuild(func(k *kong.Kong) error {
+		walk(k.Model.Node)
+		return nil
+	})
+}
+
 func (r *Runtime) ParseRawSiteAndType(rawSite string, rawType string) (string, concern_type.Type, error) {
 	site, ctype, err := concern.ParseRawSiteAndType(rawSite, rawType)
 	if err == concern.ErrSiteNotSupported {
@@ -85,6 +116,7 @@ func NewRuntime(l *Lsp, silence ...bool) *Runtime {
 		bot:    localutils.GetBot(),
 		l:      l,
 		Parser: parser.NewParser(),
+		locale: i18n.DefaultLocale,
 	}
 	if len(silence) > 0 {
 		r.silence = silence[0]
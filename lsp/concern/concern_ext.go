@@ -1,5 +1,11 @@
 package concern
 
+import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
+)
+
 // NotifyLiveExt 是一个针对直播推送过滤的扩展接口， Notify 可以选择性实现这个接口，如果实现了，则会自动使用默认的推送过滤逻辑
 // 默认情况下，如果 IsLive 为 true，则根据以下规则推送：
 // Living 为 true 且 LiveStatusChanged 为true（说明是开播了）进行推送
@@ -18,3 +24,73 @@ type NotifyLiveExt interface {
 	// 如果没有变化也可以发送给DDBOT，DDBOT会自动进行过滤
 	LiveStatusChanged() bool
 }
+
+// LiveStatusObserver 在推送过滤之前观察 NotifyLiveExt 的上播与下播，即使这条推送最终被过滤掉也会调用
+type LiveStatusObserver func(notify Notify, living bool)
+
+var liveStatusObservers []LiveStatusObserver
+
+// RegisterLiveStatusObserver 注册一个 LiveStatusObserver，需要在 StartAll 之前调用
+func RegisterLiveStatusObserver(observer LiveStatusObserver) {
+	liveStatusObservers = append(liveStatusObservers, observer)
+}
+
+func observeLiveStatus(notify Notify) {
+	liveExt, ok := notify.(NotifyLiveExt)
+	if !ok || !liveExt.IsLive() || !liveExt.LiveStatusChanged() {
+		return
+	}
+	for _, observer := range liveStatusObservers {
+		observer(notify, liveExt.Living())
+	}
+}
+
+// MediaKind 推送附带的媒体种类
+type MediaKind int
+
+const (
+	// MediaCover 封面图片
+	MediaCover MediaKind = iota
+	// MediaVideo 视频文件
+	MediaVideo
+	// MediaAudio 音频文件
+	MediaAudio
+	// MediaLink 视频链接，存档时会保存为文本文件
+	MediaLink
+)
+
+// NotifyMedia 推送附带的一个媒体
+// Url 为媒体的下载地址，Kind 为 MediaLink 时为需要保存的链接
+// Name 为存档时使用的文件名，需要带上扩展名
+// 视频和音频文件较大，由协议端直接下载，Headers 为协议端下载时使用的请求头，格式为 Key=Value
+// 需要额外请求才能拿到下载地址时可以设置 Loader，只有确定要存档时才会调用
+type NotifyMedia struct {
+	Kind    MediaKind
+	Url     string
+	Name    string
+	Proxy   proxy_pool.Prefer
+	Headers []string
+	Loader  func() (string, error)
+}
+
+// NotifyMediaExt 是一个扩展接口，用于支持将推送附带的媒体存档到群文件
+// 如果 Notify 没有实现这个接口，则不会存档
+type NotifyMediaExt interface {
+	// NotifyMedias 返回推送附带的媒体，只有开启了存档配置时才会调用，所以可以在这里进行耗时的操作
+	NotifyMedias() []*NotifyMedia
+}
+
+// PreviewExt 是一个扩展接口，用于支持 preview 命令预览订阅会推送的内容
+// 如果 Concern 没有实现这个接口，则不支持预览
+type PreviewExt interface {
+	// Preview 使用刷新时相同的接口获取 id 最新的最多 limit 条内容，并为 target 生成 Notify
+	// 预览不能修改刷新的状态，例如不能把内容标记为已推送
+	Preview(target mmsg.Target, id interface{}, ctype concern_type.Type, limit int) ([]Notify, error)
+}
+
+// FollowGroupExt 是一个扩展接口，用于支持 watch --from-list 从账号的关注分组批量读取订阅的id
+// 如果 Concern 没有实现这个接口，则只能从文本文件读取
+type FollowGroupExt interface {
+	// FollowGroupIds 返回名称为 name 的关注分组内的所有用户id
+	FollowGroupIds(name string) ([]string, error)
+}
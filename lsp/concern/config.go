@@ -12,6 +12,8 @@ type IConfig interface {
 	GetGroupConcernAt() *GroupConcernAtConfig
 	GetGroupConcernNotify() *GroupConcernNotifyConfig
 	GetGroupConcernFilter() *GroupConcernFilterConfig
+	GetGroupConcernMedia() *GroupConcernMediaConfig
+	GetGroupConcernDeleted() *GroupConcernDeletedConfig
 	ICallback
 	Hook
 }
@@ -20,9 +22,11 @@ type IConfig interface {
 // 如果 Notify 有实现 NotifyLiveExt，则会使用默认逻辑
 type GroupConcernConfig struct {
 	DefaultCallback
-	GroupConcernAt     GroupConcernAtConfig     `json:"group_concern_at"`
-	GroupConcernNotify GroupConcernNotifyConfig `json:"group_concern_notify"`
-	GroupConcernFilter GroupConcernFilterConfig `json:"group_concern_filter"`
+	GroupConcernAt      GroupConcernAtConfig      `json:"group_concern_at"`
+	GroupConcernNotify  GroupConcernNotifyConfig  `json:"group_concern_notify"`
+	GroupConcernFilter  GroupConcernFilterConfig  `json:"group_concern_filter"`
+	GroupConcernMedia   GroupConcernMediaConfig   `json:"group_concern_media"`
+	GroupConcernDeleted GroupConcernDeletedConfig `json:"group_concern_deleted"`
 }
 
 // Validate 可以在此自定义config校验，每次对config修改后会在同一个事务中调用，如果返回non-nil，则改动会回滚，此次操作失败
@@ -132,6 +136,36 @@ func (g *GroupConcernConfig) GetGroupConcernFilter() *GroupConcernFilterConfig {
 	return &g.GroupConcernFilter
 }
 
+// GetGroupConcernMedia 返回 GroupConcernMediaConfig，总是返回 non-nil
+func (g *GroupConcernConfig) GetGroupConcernMedia() *GroupConcernMediaConfig {
+	return &g.GroupConcernMedia
+}
+
+// GetGroupConcernDeleted 返回 GroupConcernDeletedConfig，总是返回 non-nil
+func (g *GroupConcernConfig) GetGroupConcernDeleted() *GroupConcernDeletedConfig {
+	return &g.GroupConcernDeleted
+}
+
+// clone 返回 GroupConcernConfig 的深拷贝，缓存的配置不能被调用者修改
+func (g *GroupConcernConfig) clone() *GroupConcernConfig {
+	var n = *g
+	if g.GroupConcernAt.AtSomeone != nil {
+		n.GroupConcernAt.AtSomeone = make([]*AtSomeone, 0, len(g.GroupConcernAt.AtSomeone))
+		for _, at := range g.GroupConcernAt.AtSomeone {
+			if at == nil {
+				n.GroupConcernAt.AtSomeone = append(n.GroupConcernAt.AtSomeone, nil)
+				continue
+			}
+			var nat = *at
+			if at.AtList != nil {
+				nat.AtList = append(make([]int64, 0, len(at.AtList)), at.AtList...)
+			}
+			n.GroupConcernAt.AtSomeone = append(n.GroupConcernAt.AtSomeone, &nat)
+		}
+	}
+	return &n
+}
+
 // ToString 将 GroupConcernConfig 通过json序列化成string
 func (g *GroupConcernConfig) ToString() string {
 	b, e := json.Marshal(g)
@@ -0,0 +1,30 @@
+package concern
+
+// DeletedAction 推送的原内容被作者删除后，对已经发送的推送消息的处理方式
+type DeletedAction string
+
+const (
+	// DeletedActionNone 不检测原内容是否被删除
+	DeletedActionNone DeletedAction = ""
+	// DeletedActionRecall 撤回推送消息，撤回失败时改为回复标注
+	DeletedActionRecall DeletedAction = "recall"
+	// DeletedActionReply 回复推送消息，标注原内容已删除
+	DeletedActionReply DeletedAction = "reply"
+)
+
+// GroupConcernDeletedConfig 原内容删除检测配置，默认关闭
+type GroupConcernDeletedConfig struct {
+	Action DeletedAction `json:"action"`
+}
+
+// GetAction 返回配置的处理方式，未配置时返回 DeletedActionNone
+func (g *GroupConcernDeletedConfig) GetAction() DeletedAction {
+	if g == nil {
+		return DeletedActionNone
+	}
+	switch g.Action {
+	case DeletedActionRecall, DeletedActionReply:
+		return g.Action
+	}
+	return DeletedActionNone
+}
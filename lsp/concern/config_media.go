@@ -0,0 +1,66 @@
+package concern
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+)
+
+// DefaultMediaFolder 默认的存档群文件夹名称
+const DefaultMediaFolder = "推送存档"
+
+// GroupConcernMediaConfig 推送媒体存档配置，开启后会把推送附带的封面等媒体上传到群文件
+type GroupConcernMediaConfig struct {
+	Archive concern_type.Type `json:"archive"`
+	Folder  string            `json:"folder"`
+	// Video 是否同时存档视频和音频文件，文件较大，默认关闭
+	Video bool `json:"video"`
+	// Link 是否同时存档视频链接
+	Link bool `json:"link"`
+}
+
+func (g *GroupConcernMediaConfig) CheckArchive(ctype concern_type.Type) bool {
+	if g == nil {
+		return false
+	}
+	return g.Archive.ContainAll(ctype)
+}
+
+// GetFolder 返回存档使用的群文件夹名称，未配置时返回 DefaultMediaFolder
+func (g *GroupConcernMediaConfig) GetFolder() string {
+	if g == nil || g.Folder == "" {
+		return DefaultMediaFolder
+	}
+	return g.Folder
+}
+
+// CheckMedia 检查该种类的媒体是否需要存档
+func (g *GroupConcernMediaConfig) CheckMedia(kind MediaKind) bool {
+	if g == nil {
+		return false
+	}
+	switch kind {
+	case MediaCover:
+		return true
+	case MediaVideo, MediaAudio:
+		return g.Video
+	case MediaLink:
+		return g.Link
+	}
+	return false
+}
+
+var mediaNameReplacer = strings.NewReplacer(
+	"/", "_", "\\", "_", ":", "_", "*", "_", "?", "_",
+	"\"", "_", "<", "_", ">", "_", "|", "_", "\n", " ", "\r", "",
+)
+
+// MediaFileName 生成存档使用的文件名，格式为 日期 标题.扩展名，会替换掉文件名中不允许出现的字符
+func MediaFileName(t time.Time, title string, ext string) string {
+	name := []rune(mediaNameReplacer.Replace(strings.TrimSpace(title)))
+	if len(name) > 80 {
+		name = name[:80]
+	}
+	return t.Format("2006-01-02") + " " + string(name) + ext
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGroupConcernAtConfig_CheckAtAll(t *testing.T) {
@@ -74,6 +76,12 @@ func TestGroupConcernConfig_ToString(t *testing.T) {
 			},
 			"group_concern_filter": {
 				"type": "", "config":""
+			},
+			"group_concern_media": {
+				"archive": "", "folder": "", "video": false, "link": false
+			},
+			"group_concern_deleted": {
+				"action": ""
 			}
 		}`,
 	}
@@ -83,6 +91,47 @@ func TestGroupConcernConfig_ToString(t *testing.T) {
 	}
 }
 
+func TestGroupConcernMediaConfig(t *testing.T) {
+	var g *GroupConcernMediaConfig
+	assert.False(t, g.CheckArchive(test.BilibiliNews))
+	assert.False(t, g.CheckMedia(MediaCover))
+	assert.Equal(t, DefaultMediaFolder, g.GetFolder())
+
+	g = &GroupConcernMediaConfig{
+		Archive: test.BilibiliNews,
+	}
+	assert.True(t, g.CheckArchive(test.BilibiliNews))
+	assert.False(t, g.CheckArchive(test.BibiliLive))
+	assert.True(t, g.CheckMedia(MediaCover))
+	assert.False(t, g.CheckMedia(MediaVideo))
+	assert.False(t, g.CheckMedia(MediaAudio))
+	assert.False(t, g.CheckMedia(MediaLink))
+	assert.Equal(t, DefaultMediaFolder, g.GetFolder())
+
+	g.Folder = "test"
+	g.Video = true
+	g.Link = true
+	assert.True(t, g.CheckMedia(MediaVideo))
+	assert.True(t, g.CheckMedia(MediaAudio))
+	assert.True(t, g.CheckMedia(MediaLink))
+	assert.Equal(t, "test", g.GetFolder())
+
+	cfg, err := NewGroupConcernConfigFromString(`{"group_concern_media":{"archive":"bilibiliNews","folder":"test","link":true}}`)
+	assert.Nil(t, err)
+	assert.True(t, cfg.GetGroupConcernMedia().CheckArchive(test.BilibiliNews))
+	assert.Equal(t, "test", cfg.GetGroupConcernMedia().GetFolder())
+	assert.True(t, cfg.GetGroupConcernMedia().Link)
+	assert.False(t, cfg.GetGroupConcernMedia().Video)
+}
+
+func TestMediaFileName(t *testing.T) {
+	var tm = time.Date(2021, 6, 1, 12, 0, 0, 0, time.Local)
+	assert.Equal(t, "2021-06-01 title.jpg", MediaFileName(tm, " title ", ".jpg"))
+	assert.Equal(t, "2021-06-01 a_b_c_d e.txt", MediaFileName(tm, "a/b:c?d\ne", ".txt"))
+	name := MediaFileName(tm, strings.Repeat("长", 100), ".jpg")
+	assert.Equal(t, "2021-06-01 "+strings.Repeat("长", 80)+".jpg", name)
+}
+
 func TestGroupConcernAtConfig_GetAtSomeoneList(t *testing.T) {
 	var testCase = []*GroupConcernConfig{
 		{
@@ -264,7 +313,7 @@ type testInfo struct {
 	titleChanged  bool
 	statusChanged bool
 	uid           int64
-	groupCode     int64
+	target        mmsg.Target
 	t             concern_type.Type
 }
 
@@ -287,8 +336,8 @@ func (t *testInfo) Logger() *logrus.Entry {
 	return logrus.WithField("Site", t.Site())
 }
 
-func (t *testInfo) GetGroupCode() int64 {
-	return t.groupCode
+func (t *testInfo) GetTarget() mmsg.Target {
+	return t.target
 }
 
 func (t *testInfo) ToMessage() *mmsg.MSG {
@@ -0,0 +1,136 @@
+package concern
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/tidwall/buntdb"
+)
+
+// PostedNotifyExpire 推送后检测原内容是否被删除的时间范围，超过之后不再检测
+const PostedNotifyExpire = time.Hour * 24
+
+// NotifyPostExt 是一个扩展接口，用于支持原内容被删除时撤回或者标注推送消息
+// 如果 Notify 没有实现这个接口，则不会检测
+type NotifyPostExt interface {
+	// PostId 返回推送的内容在网站上的id，例如动态id、微博id
+	PostId() string
+	// PostTime 返回内容的发布时间，需要与刷新时 CheckDeletedPost 使用的时间一致
+	PostTime() time.Time
+}
+
+// PostedNotify 一条开启了删除检测的推送
+// 没有复用b站和推特的 SetNotifyMsg 记录：那些记录只用于合并推送，以合并用的key（例如bvid）保存，
+// 只保存第一条消息并且合并后的推送不会保存，也没有发布时间，无法按网站遍历检测
+type PostedNotify struct {
+	Site   string  `json:"site"`
+	Id     string  `json:"id"`
+	PostId string  `json:"post_id"`
+	Target string  `json:"target"`
+	MsgIds []int32 `json:"msg_ids"`
+	// PostTime 内容的发布时间
+	PostTime int64 `json:"post_time"`
+}
+
+// DeletedPostHandler 处理原内容已经被删除的推送
+type DeletedPostHandler func(p *PostedNotify)
+
+var (
+	deletedLock    sync.RWMutex
+	deletedHandler DeletedPostHandler
+)
+
+// SetDeletedPostHandler 设置原内容被删除时的处理函数，没有设置时不会进行检测
+func SetDeletedPostHandler(handler DeletedPostHandler) {
+	deletedLock.Lock()
+	defer deletedLock.Unlock()
+	deletedHandler = handler
+}
+
+func getDeletedPostHandler() DeletedPostHandler {
+	deletedLock.RLock()
+	defer deletedLock.RUnlock()
+	return deletedHandler
+}
+
+// TrackPostedNotify 记录发送成功的推送，只有推送目标是群并且开启了删除检测配置时才会记录
+func TrackPostedNotify(cfg IConfig, notify Notify, msgIds []int32) error {
+	if cfg.GetGroupConcernDeleted().GetAction() == DeletedActionNone || !notify.GetTarget().TargetType().IsGroup() {
+		return nil
+	}
+	ext, ok := notify.(NotifyPostExt)
+	if !ok || len(ext.PostId()) == 0 {
+		return nil
+	}
+	var ids []int32
+	for _, id := range msgIds {
+		if id != -1 {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	p := &PostedNotify{
+		Site:     notify.Site(),
+		Id:       fmt.Sprint(notify.GetUid()),
+		PostId:   ext.PostId(),
+		Target:   notify.GetTarget().String(),
+		MsgIds:   ids,
+		PostTime: ext.PostTime().Unix(),
+	}
+	return localdb.SetJson(localdb.PostedNotifyKey(p.Site, p.Id, p.PostId, p.Target), p,
+		localdb.SetExpireOpt(PostedNotifyExpire))
+}
+
+// CheckDeletedPost 使用一次成功刷新得到的内容列表检测已推送的内容是否被删除
+// postIds 为刷新得到的全部内容id，since 为其中最早的发布时间（不包括置顶等不按时间排序的内容）
+// covers 返回这次刷新是否包含该id的全部内容，例如b站只包含由当前账号负责刷新的用户
+// 发布时间晚于 since 但是没有出现在列表中的内容视为已被删除，每条推送只会处理一次
+func CheckDeletedPost(site string, since time.Time, postIds []string, covers func(id string) bool) {
+	handler := getDeletedPostHandler()
+	if handler == nil || len(postIds) == 0 {
+		return
+	}
+	var alive = make(map[string]bool)
+	for _, postId := range postIds {
+		alive[postId] = true
+	}
+	var deleted []*PostedNotify
+	err := localdb.RWCoverTx(func(tx *buntdb.Tx) error {
+		var keys []string
+		err := tx.AscendKeys(localdb.PostedNotifyKey(site, "*"), func(key, value string) bool {
+			var p = new(PostedNotify)
+			if err := json.Unmarshal([]byte(value), p); err != nil {
+				logger.WithField("key", key).Errorf("unmarshal posted notify error %v", err)
+				return true
+			}
+			if p.PostTime <= since.Unix() || alive[p.PostId] || !covers(p.Id) {
+				return true
+			}
+			keys = append(keys, key)
+			deleted = append(deleted, p)
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if _, err := tx.Delete(key); err != nil && !localdb.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithField("site", site).Errorf("CheckDeletedPost error %v", err)
+		return
+	}
+	for _, p := range deleted {
+		logger.WithField("site", p.Site).WithField("id", p.Id).WithField("PostId", p.PostId).
+			WithField("Target", p.Target).Info("posted notify deleted by author")
+		handler(p)
+	}
+}
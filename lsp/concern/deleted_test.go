@@ -0,0 +1,94 @@
+package concern
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPostNotify struct {
+	testNotify
+	uid    string
+	postId string
+	t      time.Time
+}
+
+func (t *testPostNotify) GetUid() interface{} {
+	return t.uid
+}
+
+func (t *testPostNotify) PostId() string {
+	return t.postId
+}
+
+func (t *testPostNotify) PostTime() time.Time {
+	return t.t
+}
+
+func TestGroupConcernDeletedConfig(t *testing.T) {
+	var g *GroupConcernDeletedConfig
+	assert.Equal(t, DeletedActionNone, g.GetAction())
+	g = &GroupConcernDeletedConfig{Action: "unknown"}
+	assert.Equal(t, DeletedActionNone, g.GetAction())
+	g.Action = DeletedActionReply
+	assert.Equal(t, DeletedActionReply, g.GetAction())
+}
+
+func TestCheckDeletedPost(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	var now = time.Now()
+	var cfg = new(GroupConcernConfig)
+	var notify = func(uid, postId string, t time.Time) *testPostNotify {
+		return &testPostNotify{uid: uid, postId: postId, t: t}
+	}
+	var covers = func(id string) bool {
+		return id == "1"
+	}
+
+	// 没有开启配置时不记录
+	assert.Nil(t, TrackPostedNotify(cfg, notify("1", "a", now), []int32{1}))
+
+	cfg.GetGroupConcernDeleted().Action = DeletedActionRecall
+	assert.Nil(t, TrackPostedNotify(cfg, new(testNotify), []int32{1}))
+	assert.Nil(t, TrackPostedNotify(cfg, notify("1", "a", now), []int32{1, -1, 2}))
+	assert.Nil(t, TrackPostedNotify(cfg, notify("1", "b", now.Add(-time.Hour)), []int32{3}))
+	assert.Nil(t, TrackPostedNotify(cfg, notify("1", "c", now), []int32{-1}))
+	assert.Nil(t, TrackPostedNotify(cfg, notify("2", "d", now), []int32{4}))
+
+	var deleted []*PostedNotify
+	// 没有设置处理函数时不检测
+	CheckDeletedPost("test", now.Add(-time.Minute), []string{"x"}, covers)
+
+	SetDeletedPostHandler(func(p *PostedNotify) {
+		deleted = append(deleted, p)
+	})
+	defer SetDeletedPostHandler(nil)
+
+	// 刷新结果为空时不检测
+	CheckDeletedPost("test", now.Add(-time.Minute), nil, covers)
+	assert.Empty(t, deleted)
+
+	// 仍然在列表中
+	CheckDeletedPost("test", now.Add(-time.Minute), []string{"a"}, covers)
+	assert.Empty(t, deleted)
+
+	// b 早于检测范围，d 不是由这次刷新负责
+	CheckDeletedPost("test", now.Add(-time.Minute), []string{"x"}, covers)
+	assert.Len(t, deleted, 1)
+	assert.Equal(t, "a", deleted[0].PostId)
+	assert.Equal(t, "1", deleted[0].Id)
+	assert.Equal(t, "g123456", deleted[0].Target)
+	assert.Equal(t, []int32{1, 2}, deleted[0].MsgIds)
+
+	// 每条推送只处理一次
+	CheckDeletedPost("test", now.Add(-time.Minute), []string{"x"}, covers)
+	assert.Len(t, deleted, 1)
+
+	CheckDeletedPost("test", now.Add(-time.Hour*2), []string{"x"}, covers)
+	assert.Len(t, deleted, 2)
+	assert.Equal(t, "b", deleted[1].PostId)
+}
@@ -38,3 +38,15 @@ var defaultHookResult = &HookResult{
 var HookResultPass = &HookResult{
 	Pass: true,
 }
+
+// RunSendHooks 按推送时的顺序执行 ShouldSendHook 和 FilterHook
+// 如果有 Hook 没有通过，则返回这个 Hook 的名字和结果，全部通过时返回空字符串和 HookResultPass
+func RunSendHooks(hook Hook, notify Notify) (string, *HookResult) {
+	if result := hook.ShouldSendHook(notify); !result.Pass {
+		return "ShouldSendHook", result
+	}
+	if result := hook.FilterHook(notify); !result.Pass {
+		return "FilterHook", result
+	}
+	return "", HookResultPass
+}
@@ -15,3 +15,21 @@ func TestHook(t *testing.T) {
 	assert.False(t, c.Pass)
 	assert.EqualValues(t, test.NAME1, c.Reason)
 }
+
+func TestRunSendHooks(t *testing.T) {
+	var g GroupConcernConfig
+	hookName, result := RunSendHooks(&g, &testInfo{isLive: true, living: true, statusChanged: true})
+	assert.True(t, result.Pass)
+	assert.Empty(t, hookName)
+
+	hookName, result = RunSendHooks(&g, &testInfo{isLive: true, living: false, statusChanged: true})
+	assert.False(t, result.Pass)
+	assert.Equal(t, "ShouldSendHook", hookName)
+
+	g.GetGroupConcernFilter().Type = FilterTypeText
+	g.GetGroupConcernFilter().Config = (&GroupConcernFilterConfigByText{Text: []string{"nothing"}}).ToString()
+	hookName, result = RunSendHooks(&g, &testInfo{isLive: true, living: true, statusChanged: true})
+	assert.False(t, result.Pass)
+	assert.Equal(t, "FilterHook", hookName)
+	assert.NotEmpty(t, result.Reason)
+}
@@ -0,0 +1,167 @@
+package concern
+
+import (
+	"sync"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+)
+
+// indexEntry 是内存索引中的一条订阅
+type indexEntry struct {
+	target mmsg.Target
+	ctype  concern_type.Type
+}
+
+// concernIndex 是 StateManager 的内存索引，维护 id -> target -> concern_type.Type，
+// 以及订阅配置的缓存，避免每个 Event 都遍历数据库中所有的订阅。
+//
+// 索引总是以数据库为准：订阅发生变化时增量更新，无法确定最终状态时（例如在外层事务中修改，外层事务可能回滚）直接失效，
+// 下次使用时从数据库重建。gen 在每次变化时增加，用来丢弃重建过程中发生了变化的结果。
+type concernIndex struct {
+	lock   sync.RWMutex
+	gen    uint64
+	ready  bool
+	state  map[interface{}]map[string]indexEntry
+	config map[string]*GroupConcernConfig
+}
+
+func newConcernIndex() *concernIndex {
+	return &concernIndex{
+		config: make(map[string]*GroupConcernConfig),
+	}
+}
+
+// generation 返回当前的版本，需要在读取数据库之前获取
+func (i *concernIndex) generation() uint64 {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return i.gen
+}
+
+// get 返回订阅了id的所有target，索引未就绪时ok为false
+func (i *concernIndex) get(id interface{}) (entries []indexEntry, ok bool) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	if !i.ready {
+		return nil, false
+	}
+	for _, entry := range i.state[id] {
+		entries = append(entries, entry)
+	}
+	return entries, true
+}
+
+// rebuild 使用从数据库中读取的全部订阅重建索引，如果读取期间索引发生了变化，则放弃这次结果
+func (i *concernIndex) rebuild(gen uint64, targets []mmsg.Target, ids []interface{}, ctypes []concern_type.Type) bool {
+	if len(targets) != len(ids) || len(ids) != len(ctypes) {
+		return false
+	}
+	var state = make(map[interface{}]map[string]indexEntry)
+	for index := range ids {
+		m, found := state[ids[index]]
+		if !found {
+			m = make(map[string]indexEntry)
+			state[ids[index]] = m
+		}
+		m[targets[index].String()] = indexEntry{target: targets[index], ctype: ctypes[index]}
+	}
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if i.gen != gen {
+		return false
+	}
+	i.state = state
+	i.ready = true
+	return true
+}
+
+// update 设置id在target内的订阅，ctype为空表示删除
+func (i *concernIndex) update(target mmsg.Target, id interface{}, ctype concern_type.Type) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.gen++
+	if !i.ready {
+		return
+	}
+	m := i.state[id]
+	if ctype.Empty() {
+		delete(m, target.String())
+		if len(m) == 0 {
+			delete(i.state, id)
+		}
+		return
+	}
+	if m == nil {
+		m = make(map[string]indexEntry)
+		i.state[id] = m
+	}
+	m[target.String()] = indexEntry{target: target, ctype: ctype}
+}
+
+// removeTarget 删除target内的所有订阅以及配置缓存
+func (i *concernIndex) removeTarget(target mmsg.Target) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.gen++
+	i.config = make(map[string]*GroupConcernConfig)
+	if !i.ready {
+		return
+	}
+	key := target.String()
+	for id, m := range i.state {
+		delete(m, key)
+		if len(m) == 0 {
+			delete(i.state, id)
+		}
+	}
+}
+
+// removeId 删除所有target内id的订阅
+func (i *concernIndex) removeId(id interface{}) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.gen++
+	if i.ready {
+		delete(i.state, id)
+	}
+}
+
+// invalidate 使索引和配置缓存全部失效，下次使用时从数据库重建
+func (i *concernIndex) invalidate() {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.gen++
+	i.ready = false
+	i.state = nil
+	i.config = make(map[string]*GroupConcernConfig)
+}
+
+// getConfig 返回缓存的配置的拷贝
+func (i *concernIndex) getConfig(key string) (*GroupConcernConfig, bool) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	cfg, found := i.config[key]
+	if !found {
+		return nil, false
+	}
+	return cfg.clone(), true
+}
+
+// setConfig 缓存从数据库中读取的配置，如果读取期间发生了变化，则不缓存
+func (i *concernIndex) setConfig(gen uint64, key string, cfg *GroupConcernConfig) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if i.gen != gen {
+		return
+	}
+	i.config[key] = cfg.clone()
+}
+
+// deleteConfig 删除配置缓存
+func (i *concernIndex) deleteConfig(key string) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.gen++
+	delete(i.config, key)
+}
@@ -0,0 +1,236 @@
+package concern
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcernIndex(t *testing.T) {
+	var g1 = mmsg.NewGroupTarget(test.G1)
+	var g2 = mmsg.NewGroupTarget(test.G2)
+	var p1 = mmsg.NewPrivateTarget(test.UID1)
+
+	idx := newConcernIndex()
+	_, ok := idx.get(test.UID1)
+	assert.False(t, ok)
+
+	// 未就绪时的修改只增加版本
+	gen := idx.generation()
+	idx.update(g1, test.UID1, testType)
+	assert.False(t, idx.rebuild(gen, nil, nil, nil))
+	_, ok = idx.get(test.UID1)
+	assert.False(t, ok)
+
+	gen = idx.generation()
+	assert.True(t, idx.rebuild(gen,
+		[]mmsg.Target{g1, g2},
+		[]interface{}{test.UID1, test.UID1},
+		[]concern_type.Type{testType, testType}))
+	entries, ok := idx.get(test.UID1)
+	assert.True(t, ok)
+	assert.Len(t, entries, 2)
+
+	idx.update(p1, test.UID1, testType)
+	idx.update(g1, test.UID2, testType)
+	entries, _ = idx.get(test.UID1)
+	assert.Len(t, entries, 3)
+
+	idx.update(g2, test.UID1, concern_type.Empty)
+	entries, _ = idx.get(test.UID1)
+	assert.Len(t, entries, 2)
+
+	idx.removeTarget(g1)
+	entries, _ = idx.get(test.UID1)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, p1, entries[0].target)
+	entries, ok = idx.get(test.UID2)
+	assert.True(t, ok)
+	assert.Empty(t, entries)
+
+	idx.removeId(test.UID1)
+	entries, _ = idx.get(test.UID1)
+	assert.Empty(t, entries)
+
+	idx.invalidate()
+	_, ok = idx.get(test.UID1)
+	assert.False(t, ok)
+
+	// 配置缓存返回的是拷贝
+	var cfg = new(GroupConcernConfig)
+	cfg.GroupConcernAt.MergeAtSomeoneList(testType, []int64{1, 2})
+	gen = idx.generation()
+	idx.setConfig(gen, "key", cfg)
+	cached, found := idx.getConfig("key")
+	assert.True(t, found)
+	assert.EqualValues(t, cfg, cached)
+	cached.GroupConcernAt.AtSomeone[0].AtList[0] = 3
+	cached, _ = idx.getConfig("key")
+	assert.EqualValues(t, []int64{1, 2}, cached.GroupConcernAt.AtSomeone[0].AtList)
+
+	idx.deleteConfig("key")
+	_, found = idx.getConfig("key")
+	assert.False(t, found)
+
+	// 读取期间发生了变化，不缓存
+	gen = idx.generation()
+	idx.deleteConfig("other")
+	idx.setConfig(gen, "key", cfg)
+	_, found = idx.getConfig("key")
+	assert.False(t, found)
+}
+
+func TestStateManager_Index(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	var g1 = mmsg.NewGroupTarget(test.G1)
+	var g2 = mmsg.NewGroupTarget(test.G2)
+
+	sm := newStateManager(t)
+
+	_, err := sm.AddGroupConcern(g1, test.UID1, testType)
+	assert.Nil(t, err)
+	_, _, _, err = sm.rebuildIndex()
+	assert.Nil(t, err)
+
+	_, err = sm.AddGroupConcern(g2, test.UID1, testType)
+	assert.Nil(t, err)
+	_, err = sm.AddGroupConcern(g2, test.UID2, testType)
+	assert.Nil(t, err)
+
+	entries, ok := sm.index.get(test.UID1)
+	assert.True(t, ok)
+	assert.Len(t, entries, 2)
+
+	_, err = sm.RemoveGroupConcern(g1, test.UID1, testType)
+	assert.Nil(t, err)
+	entries, _ = sm.index.get(test.UID1)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, g2, entries[0].target)
+
+	// 外层事务回滚后索引仍然与数据库一致
+	err = localdb.RWCover(func() error {
+		_, err := sm.RemoveGroupConcern(g2, test.UID1, testType)
+		assert.Nil(t, err)
+		ctype, err := sm.GetConcern(test.UID1)
+		assert.Nil(t, err)
+		assert.True(t, ctype.Empty())
+		return errors.New("rollback")
+	})
+	assert.NotNil(t, err)
+	ctype, err := sm.GetConcern(test.UID1)
+	assert.Nil(t, err)
+	assert.Equal(t, testType, ctype)
+	entries, ok = sm.index.get(test.UID1)
+	assert.True(t, ok)
+	assert.Len(t, entries, 1)
+
+	_, err = sm.RemoveAllByTarget(g2)
+	assert.Nil(t, err)
+	entries, _ = sm.index.get(test.UID1)
+	assert.Empty(t, entries)
+	entries, _ = sm.index.get(test.UID2)
+	assert.Empty(t, entries)
+
+	_, err = sm.AddGroupConcern(g1, test.UID2, testType)
+	assert.Nil(t, err)
+	assert.Nil(t, sm.RemoveAllById(test.UID2))
+	entries, _ = sm.index.get(test.UID2)
+	assert.Empty(t, entries)
+
+	// 配置缓存在修改后失效
+	_, err = sm.AddGroupConcern(g1, test.UID1, testType)
+	assert.Nil(t, err)
+	cfg := sm.GetGroupConcernConfig(g1, test.UID1)
+	assert.False(t, cfg.GetGroupConcernAt().CheckAtAll(testType))
+	assert.Nil(t, sm.OperateGroupConcernConfig(g1, test.UID1, cfg, func(concernConfig IConfig) bool {
+		concernConfig.GetGroupConcernAt().AtAll = testType
+		return true
+	}))
+	assert.True(t, sm.GetGroupConcernConfig(g1, test.UID1).GetGroupConcernAt().CheckAtAll(testType))
+}
+
+// prepareBenchmarkStateManager 准备groups个群，每个群订阅perGroup个id
+func prepareBenchmarkStateManager(b *testing.B, groups int, perGroup int) *StateManager {
+	if err := localdb.InitBuntDB(localdb.MEMORYDB); err != nil {
+		b.Fatal(err)
+	}
+	sm := NewStateManagerWithCustomKey("test", &testKeySet{}, nil)
+	sm.FreshIndex()
+	err := localdb.RWCover(func() error {
+		for g := 0; g < groups; g++ {
+			for id := 0; id < perGroup; id++ {
+				if err := sm.Set(sm.GroupConcernStateKey(mmsg.NewGroupTarget(int64(g+1)), int64(id)), testType.String()); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	sm.FreshIndex()
+	return sm
+}
+
+func BenchmarkDispatchLookup_Scan(b *testing.B) {
+	sm := prepareBenchmarkStateManager(b, 300, 30)
+	defer localdb.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		id := int64(i % 30)
+		_, _, _, err := sm.ListConcernState(func(target mmsg.Target, _id interface{}, p concern_type.Type) bool {
+			return _id == id && p.ContainAll(testType)
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDispatchLookup_Index(b *testing.B) {
+	sm := prepareBenchmarkStateManager(b, 300, 30)
+	defer localdb.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := sm.lookupConcernState(int64(i % 30)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetGroupConcernConfig_NoCache(b *testing.B) {
+	sm := prepareBenchmarkStateManager(b, 1, 1)
+	defer localdb.Close()
+	target := mmsg.NewGroupTarget(1)
+	assert.Nil(b, sm.OperateGroupConcernConfig(target, int64(0), sm.GetGroupConcernConfig(target, int64(0)), func(concernConfig IConfig) bool {
+		concernConfig.GetGroupConcernAt().MergeAtSomeoneList(testType, []int64{1, 2, 3})
+		return true
+	}))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sm.index.deleteConfig(sm.GroupConcernConfigKey(target, int64(0)))
+		sm.GetGroupConcernConfig(target, int64(0))
+	}
+}
+
+func BenchmarkGetGroupConcernConfig_Cache(b *testing.B) {
+	sm := prepareBenchmarkStateManager(b, 1, 1)
+	defer localdb.Close()
+	target := mmsg.NewGroupTarget(1)
+	assert.Nil(b, sm.OperateGroupConcernConfig(target, int64(0), sm.GetGroupConcernConfig(target, int64(0)), func(concernConfig IConfig) bool {
+		concernConfig.GetGroupConcernAt().MergeAtSomeoneList(testType, []int64{1, 2, 3})
+		return true
+	}))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sm.GetGroupConcernConfig(target, int64(0))
+	}
+}
@@ -1,18 +1,24 @@
 package concern
 
 import (
+	"errors"
+	"strconv"
+	"strings"
+
 	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 )
 
 // KeySet 是不同 StateManager 之间用来彼此隔离的一个接口。
 // 通常 StateManager 会创建多个，用于不同的 Concern 模块，所以创建 StateManager 的时候需要指定 KeySet。
 // 大多数情况下可以方便的使用内置实现 PrefixKeySet。
+// 订阅状态与配置的key格式为 <name>:<target>:<id>，其中 target 为 mmsg.Target.String 的结果。
 type KeySet interface {
 	GroupConcernStateKey(keys ...interface{}) string
 	GroupConcernConfigKey(keys ...interface{}) string
 	FreshKey(keys ...interface{}) string
 	GroupAtAllMarkKey(keys ...interface{}) string
-	ParseGroupConcernStateKey(key string) (groupCode int64, id interface{}, err error)
+	ParseGroupConcernStateKey(key string) (target mmsg.Target, id interface{}, err error)
 }
 
 // PrefixKeySet 是 KeySet 的一个默认实现，它使用一个唯一的前缀彼此区分。
@@ -22,7 +28,7 @@ type PrefixKeySet struct {
 	groupConcernConfigKey string
 	freshKey              string
 	groupAtAllMarkKey     string
-	parser                func(key string) (groupCode int64, id interface{}, err error)
+	parser                func(key string) (target mmsg.Target, id interface{}, err error)
 }
 
 func (p *PrefixKeySet) GroupConcernStateKey(keys ...interface{}) string {
@@ -41,11 +47,11 @@ func (p *PrefixKeySet) GroupAtAllMarkKey(keys ...interface{}) string {
 	return localdb.NamedKey(p.groupAtAllMarkKey, keys)
 }
 
-func (p *PrefixKeySet) ParseGroupConcernStateKey(key string) (groupCode int64, id interface{}, err error) {
+func (p *PrefixKeySet) ParseGroupConcernStateKey(key string) (target mmsg.Target, id interface{}, err error) {
 	return p.parser(key)
 }
 
-func newPrefixKeySet(prefix string, parser func(key string) (groupCode int64, id interface{}, err error)) *PrefixKeySet {
+func newPrefixKeySet(prefix string, parser func(key string) (target mmsg.Target, id interface{}, err error)) *PrefixKeySet {
 	p := &PrefixKeySet{
 		prefix: prefix,
 		parser: parser,
@@ -61,8 +67,8 @@ func newPrefixKeySet(prefix string, parser func(key string) (groupCode int64, id
 // id的格式需要与 Concern.ParseId 返回的格式一致
 // prefix 可以简单地使用 Concern.Site
 func NewPrefixKeySetWithStringID(prefix string) *PrefixKeySet {
-	return newPrefixKeySet(prefix, func(key string) (groupCode int64, id interface{}, err error) {
-		return localdb.ParseConcernStateKeyWithString(key)
+	return newPrefixKeySet(prefix, func(key string) (target mmsg.Target, id interface{}, err error) {
+		return ParseConcernStateKeyWithString(key)
 	})
 }
 
@@ -70,7 +76,37 @@ func NewPrefixKeySetWithStringID(prefix string) *PrefixKeySet {
 // id的格式需要与 Concern.ParseId 返回的格式一致
 // prefix 可以简单地使用 Concern.Site
 func NewPrefixKeySetWithInt64ID(prefix string) *PrefixKeySet {
-	return newPrefixKeySet(prefix, func(key string) (groupCode int64, id interface{}, err error) {
-		return localdb.ParseConcernStateKeyWithInt64(key)
+	return newPrefixKeySet(prefix, func(key string) (target mmsg.Target, id interface{}, err error) {
+		return ParseConcernStateKeyWithInt64(key)
 	})
 }
+
+func splitConcernStateKey(key string) (target mmsg.Target, id string, err error) {
+	keys := strings.Split(key, ":")
+	if len(keys) != 3 {
+		return nil, "", errors.New("invalid key")
+	}
+	target, err = mmsg.ParseTarget(keys[1])
+	if err != nil {
+		return nil, "", err
+	}
+	return target, keys[2], nil
+}
+
+// ParseConcernStateKeyWithInt64 解析使用 int64 格式的id的订阅key
+func ParseConcernStateKeyWithInt64(key string) (target mmsg.Target, id int64, err error) {
+	target, sid, err := splitConcernStateKey(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	id, err = strconv.ParseInt(sid, 10, 64)
+	if err != nil {
+		return nil, 0, err
+	}
+	return target, id, nil
+}
+
+// ParseConcernStateKeyWithString 解析使用 string 格式的id的订阅key
+func ParseConcernStateKeyWithString(key string) (target mmsg.Target, id string, err error) {
+	return splitConcernStateKey(key)
+}
@@ -1,9 +1,11 @@
 package concern
 
 import (
+	"testing"
+
 	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/stretchr/testify/assert"
-	"testing"
 )
 
 func TestNewPrefixKeySetWithInt64ID(t *testing.T) {
@@ -12,10 +14,24 @@ func TestNewPrefixKeySetWithInt64ID(t *testing.T) {
 	pks.FreshKey()
 	pks.GroupAtAllMarkKey()
 	pks.GroupConcernConfigKey()
-	g, id, err := pks.ParseGroupConcernStateKey(pks.GroupConcernStateKey(test.G1, test.UID1))
+	target, id, err := pks.ParseGroupConcernStateKey(pks.GroupConcernStateKey(mmsg.NewGroupTarget(test.G1), test.UID1))
 	assert.Nil(t, err)
-	assert.EqualValues(t, test.G1, g)
+	assert.True(t, mmsg.TargetEqual(mmsg.NewGroupTarget(test.G1), target))
 	assert.EqualValues(t, test.UID1, id)
+
+	target, id, err = pks.ParseGroupConcernStateKey(pks.GroupConcernStateKey(mmsg.NewPrivateTarget(test.UID2), test.UID1))
+	assert.Nil(t, err)
+	assert.True(t, mmsg.TargetEqual(mmsg.NewPrivateTarget(test.UID2), target))
+	assert.EqualValues(t, test.UID1, id)
+
+	for _, key := range []string{
+		"wrong_key",
+		pks.GroupConcernStateKey(test.G1, test.UID1),
+		pks.GroupConcernStateKey(mmsg.NewGroupTarget(test.G1), test.NAME1),
+	} {
+		_, _, err = pks.ParseGroupConcernStateKey(key)
+		assert.NotNil(t, err, key)
+	}
 }
 
 func TestNewPrefixKeySetWithStringID(t *testing.T) {
@@ -24,8 +40,8 @@ func TestNewPrefixKeySetWithStringID(t *testing.T) {
 	pks.FreshKey()
 	pks.GroupAtAllMarkKey()
 	pks.GroupConcernConfigKey()
-	g, id, err := pks.ParseGroupConcernStateKey(pks.GroupConcernStateKey(test.G1, test.NAME1))
+	target, id, err := pks.ParseGroupConcernStateKey(pks.GroupConcernStateKey(mmsg.NewGroupTarget(test.G1), test.NAME1))
 	assert.Nil(t, err)
-	assert.EqualValues(t, test.G1, g)
+	assert.True(t, mmsg.TargetEqual(mmsg.NewGroupTarget(test.G1), target))
 	assert.EqualValues(t, test.NAME1, id)
 }
@@ -0,0 +1,171 @@
+package concern
+
+import (
+	"fmt"
+	"time"
+
+	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+)
+
+// liveSessionExpire 直播记录的过期时间，防止错过下播时一直保留
+const liveSessionExpire = time.Hour * 72
+
+// NotifyLiveSummaryExt 是一个针对直播推送的扩展接口，Notify 可以选择性实现这个接口
+// 如果实现了，DDBOT会记录群内每场直播的开播推送，开启下播推送时会改为回复开播推送并附带本场直播的总结
+type NotifyLiveSummaryExt interface {
+	NotifyLiveExt
+	// AnchorName 返回主播的名字
+	AnchorName() string
+	// RoomTitle 返回当前的直播标题
+	RoomTitle() string
+	// Popularity 返回当前的人气，网站没有提供时返回0
+	Popularity() int64
+}
+
+// LiveTitleChange 直播过程中的一次标题修改
+type LiveTitleChange struct {
+	Time  int64  `json:"time"`
+	Title string `json:"title"`
+}
+
+// LiveSession 一个群内的一场直播
+type LiveSession struct {
+	Site   string `json:"site"`
+	Id     string `json:"id"`
+	Target string `json:"target"`
+	// MsgId 开播推送的消息id，没有推送成功时为0
+	MsgId int32  `json:"msg_id"`
+	Name  string `json:"name"`
+	// Title 最后的直播标题
+	Title     string             `json:"title"`
+	StartTime int64              `json:"start_time"`
+	EndTime   int64              `json:"end_time"`
+	Titles    []*LiveTitleChange `json:"titles"`
+	// Popularity 直播过程中的峰值人气
+	Popularity int64 `json:"popularity"`
+}
+
+// Ended 是否已经下播
+func (s *LiveSession) Ended() bool {
+	return s.EndTime > 0
+}
+
+// Duration 返回直播时长，还没有下播时返回到现在的时长
+func (s *LiveSession) Duration() time.Duration {
+	end := s.EndTime
+	if !s.Ended() {
+		end = time.Now().Unix()
+	}
+	return time.Duration(end-s.StartTime) * time.Second
+}
+
+func liveSessionKey(notify Notify) string {
+	return localdb.LiveSessionKey(notify.Site(), fmt.Sprint(notify.GetUid()), notify.GetTarget().String())
+}
+
+// observeLiveSession 在推送过滤之前记录群内每场直播的开播时间、标题修改以及峰值人气
+func observeLiveSession(notify Notify) {
+	ext, ok := notify.(NotifyLiveSummaryExt)
+	if !ok || !ext.IsLive() || notify.GetTarget() == nil || !notify.GetTarget().TargetType().IsGroup() {
+		return
+	}
+	key := liveSessionKey(notify)
+	now := time.Now().Unix()
+	err := localdb.RWCover(func() error {
+		var s = new(LiveSession)
+		err := localdb.GetJson(key, s)
+		if err != nil && !localdb.IsNotFound(err) {
+			return err
+		}
+		found := err == nil
+		switch {
+		case ext.Living() && ext.LiveStatusChanged():
+			s = &LiveSession{
+				Site:       notify.Site(),
+				Id:         fmt.Sprint(notify.GetUid()),
+				Target:     notify.GetTarget().String(),
+				Name:       ext.AnchorName(),
+				Title:      ext.RoomTitle(),
+				StartTime:  now,
+				Popularity: ext.Popularity(),
+			}
+		case ext.Living():
+			// 没有记录到开播，例如BOT启动前就已经开播了
+			if !found || s.Ended() {
+				return nil
+			}
+			var changed bool
+			if title := ext.RoomTitle(); len(title) > 0 && title != s.Title {
+				s.Titles = append(s.Titles, &LiveTitleChange{Time: now, Title: title})
+				s.Title = title
+				changed = true
+			}
+			if popularity := ext.Popularity(); popularity > s.Popularity {
+				s.Popularity = popularity
+				changed = true
+			}
+			if !changed {
+				return nil
+			}
+		case ext.LiveStatusChanged():
+			if !found || s.Ended() {
+				return nil
+			}
+			s.EndTime = now
+			if name := ext.AnchorName(); len(name) > 0 {
+				s.Name = name
+			}
+		default:
+			return nil
+		}
+		return localdb.SetJson(key, s, localdb.SetExpireOpt(liveSessionExpire))
+	})
+	if err != nil {
+		notify.Logger().Errorf("observeLiveSession error %v", err)
+	}
+}
+
+// SetLiveSessionMsg 记录开播推送的消息id，用于下播时回复
+func SetLiveSessionMsg(notify Notify, msgId int32) error {
+	key := liveSessionKey(notify)
+	return localdb.RWCover(func() error {
+		var s = new(LiveSession)
+		err := localdb.GetJson(key, s)
+		if localdb.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if s.Ended() {
+			return nil
+		}
+		s.MsgId = msgId
+		return localdb.SetJson(key, s, localdb.SetExpireOpt(liveSessionExpire))
+	})
+}
+
+// PopLiveSession 返回并删除已经下播的直播记录，没有记录或者还没有下播时返回nil
+func PopLiveSession(notify Notify) (*LiveSession, error) {
+	key := liveSessionKey(notify)
+	var result *LiveSession
+	err := localdb.RWCover(func() error {
+		var s = new(LiveSession)
+		err := localdb.GetJson(key, s)
+		if localdb.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !s.Ended() {
+			return nil
+		}
+		if _, err = localdb.Delete(key, localdb.IgnoreNotFoundOpt()); err != nil {
+			return err
+		}
+		result = s
+		return nil
+	})
+	return result, err
+}
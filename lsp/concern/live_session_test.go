@@ -0,0 +1,96 @@
+package concern
+
+import (
+	"testing"
+
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/stretchr/testify/assert"
+)
+
+type testLiveSummaryNotify struct {
+	testNotify
+	living     bool
+	changed    bool
+	title      string
+	popularity int64
+}
+
+func (t *testLiveSummaryNotify) GetUid() interface{} {
+	return "1"
+}
+
+func (t *testLiveSummaryNotify) IsLive() bool {
+	return true
+}
+
+func (t *testLiveSummaryNotify) Living() bool {
+	return t.living
+}
+
+func (t *testLiveSummaryNotify) TitleChanged() bool {
+	return false
+}
+
+func (t *testLiveSummaryNotify) LiveStatusChanged() bool {
+	return t.changed
+}
+
+func (t *testLiveSummaryNotify) AnchorName() string {
+	return "name"
+}
+
+func (t *testLiveSummaryNotify) RoomTitle() string {
+	return t.title
+}
+
+func (t *testLiveSummaryNotify) Popularity() int64 {
+	return t.popularity
+}
+
+func TestObserveLiveSession(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	var notify = func(living, changed bool, title string, popularity int64) *testLiveSummaryNotify {
+		return &testLiveSummaryNotify{living: living, changed: changed, title: title, popularity: popularity}
+	}
+
+	// 没有记录到开播时忽略
+	observeLiveSession(notify(true, false, "t0", 10))
+	observeLiveSession(notify(false, true, "t0", 0))
+	s, err := PopLiveSession(notify(false, true, "", 0))
+	assert.Nil(t, err)
+	assert.Nil(t, s)
+
+	observeLiveSession(notify(true, true, "t1", 10))
+	assert.Nil(t, SetLiveSessionMsg(notify(true, true, "t1", 10), 100))
+
+	// 还没有下播
+	s, err = PopLiveSession(notify(true, false, "t1", 10))
+	assert.Nil(t, err)
+	assert.Nil(t, s)
+
+	observeLiveSession(notify(true, false, "t1", 30))
+	observeLiveSession(notify(true, false, "", 20))
+	observeLiveSession(notify(true, false, "t2", 5))
+	observeLiveSession(notify(false, true, "", 0))
+
+	s, err = PopLiveSession(notify(false, true, "", 0))
+	assert.Nil(t, err)
+	assert.NotNil(t, s)
+	assert.True(t, s.Ended())
+	assert.Equal(t, "test", s.Site)
+	assert.Equal(t, "1", s.Id)
+	assert.Equal(t, "g123456", s.Target)
+	assert.EqualValues(t, 100, s.MsgId)
+	assert.Equal(t, "name", s.Name)
+	assert.Equal(t, "t2", s.Title)
+	assert.EqualValues(t, 30, s.Popularity)
+	assert.Len(t, s.Titles, 1)
+	assert.Equal(t, "t2", s.Titles[0].Title)
+
+	// 只会返回一次
+	s, err = PopLiveSession(notify(false, true, "", 0))
+	assert.Nil(t, err)
+	assert.Nil(t, s)
+}
@@ -0,0 +1,27 @@
+package concern
+
+// FilterNews 从一次刷新得到的内容中筛选出需要推送的部分，保持原来的顺序
+// postTime 返回内容的发布时间，不晚于 lastTs 或者无法获取时间（返回false）的内容会跳过，为nil时不按时间筛选
+// mark 在推送前记录内容，已经记录过时返回false，避免置顶或者重新排序的内容被重复推送
+// 返回的时间为 lastTs 和推送内容中最晚的发布时间
+func FilterNews[T any](items []T, lastTs int64, postTime func(item T) (int64, bool), mark func(item T) bool) ([]T, int64) {
+	var result []T
+	var latestTs = lastTs
+	for _, item := range items {
+		var ts int64
+		if postTime != nil {
+			var ok bool
+			if ts, ok = postTime(item); !ok || ts <= lastTs {
+				continue
+			}
+		}
+		if !mark(item) {
+			continue
+		}
+		result = append(result, item)
+		if ts > latestTs {
+			latestTs = ts
+		}
+	}
+	return result, latestTs
+}
@@ -0,0 +1,46 @@
+package concern
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterNews(t *testing.T) {
+	type post struct {
+		id string
+		ts int64
+	}
+	posts := []*post{{"1", 100}, {"2", 300}, {"", 400}, {"3", 200}, {"4", 50}}
+	postTime := func(p *post) (int64, bool) {
+		return p.ts, p.id != ""
+	}
+	var marked = make(map[string]bool)
+	mark := func(p *post) bool {
+		if marked[p.id] {
+			return false
+		}
+		marked[p.id] = true
+		return true
+	}
+
+	result, latestTs := FilterNews(posts, 100, postTime, mark)
+	assert.Equal(t, []*post{posts[1], posts[3]}, result)
+	assert.EqualValues(t, 300, latestTs)
+
+	// 已经推送过的内容不会重复推送
+	result, latestTs = FilterNews(posts, 0, postTime, mark)
+	assert.Equal(t, []*post{posts[0], posts[4]}, result)
+	assert.EqualValues(t, 100, latestTs)
+
+	result, latestTs = FilterNews(posts, 500, postTime, mark)
+	assert.Empty(t, result)
+	assert.EqualValues(t, 500, latestTs)
+
+	// 不按时间筛选时只看 mark
+	result, latestTs = FilterNews(posts, 0, nil, func(p *post) bool {
+		return p.id == ""
+	})
+	assert.Equal(t, []*post{posts[2]}, result)
+	assert.EqualValues(t, 0, latestTs)
+}
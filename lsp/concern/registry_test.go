@@ -25,11 +25,11 @@ func (t *testConcern) ParseId(s string) (interface{}, error) {
 	return s, nil
 }
 
-func (t *testConcern) Add(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctype concern_type.Type) (IdentityInfo, error) {
+func (t *testConcern) Add(ctx mmsg.IMsgCtx, target mmsg.Target, id interface{}, ctype concern_type.Type) (IdentityInfo, error) {
 	return nil, nil
 }
 
-func (t *testConcern) Remove(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctype concern_type.Type) (IdentityInfo, error) {
+func (t *testConcern) Remove(ctx mmsg.IMsgCtx, target mmsg.Target, id interface{}, ctype concern_type.Type) (IdentityInfo, error) {
 	return nil, nil
 }
 
@@ -41,7 +41,7 @@ func (t *testConcern) GetStateManager() IStateManager {
 	return nil
 }
 
-func (t *testConcern) FreshIndex(groupCode ...int64) {
+func (t *testConcern) FreshIndex(targets ...mmsg.Target) {
 }
 
 func (t *testConcern) Site() string {
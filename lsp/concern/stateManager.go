@@ -13,10 +13,10 @@ import (
 	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/cfg"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/sirupsen/logrus"
 	"github.com/tidwall/buntdb"
-	"go.uber.org/atomic"
 )
 
 var logger = utils.GetModuleLogger("concern")
@@ -24,11 +24,11 @@ var ErrEmitQueueNotInit = errors.New("emit queue not init")
 var ErrMaxGroupConcernExceed = errors.New("本群已达到订阅上限")
 
 // NotifyGeneratorFunc 是 IStateManager.NotifyGenerator 函数的具体逻辑
-// 它针对一组 groupCode 把 Event 转变成一组 Notify
+// 它针对一个推送目标 target 把 Event 转变成一组 Notify，target 可能是群，也可能是私聊
 //
 // 使用 StateManager 时，在 StateManager.Start 之前，
 // 必须使用 StateManager.UseNotifyGeneratorFunc 来指定一个 NotifyGeneratorFunc, 否则会发生 panic
-type NotifyGeneratorFunc func(groupCode int64, event Event) []Notify
+type NotifyGeneratorFunc func(target mmsg.Target, event Event) []Notify
 
 // DispatchFunc 是 IStateManager.Dispatch 函数的具体逻辑
 // 它从event channel中获取 Event，把 Event 转变成（可能多个） Notify 并发送到notify channel
@@ -44,26 +44,27 @@ type DispatchFunc func(event <-chan Event, notify chan<- Notify)
 type FreshFunc func(ctx context.Context, eventChan chan<- Event)
 
 type IStateManager interface {
-	GetGroupConcernConfig(groupCode int64, id interface{}) (concernConfig IConfig)
-	OperateGroupConcernConfig(groupCode int64, id interface{}, cfg IConfig, f func(concernConfig IConfig) bool) error
+	GetGroupConcernConfig(target mmsg.Target, id interface{}) (concernConfig IConfig)
+	OperateGroupConcernConfig(target mmsg.Target, id interface{}, cfg IConfig, f func(concernConfig IConfig) bool) error
 
-	GetGroupConcern(groupCode int64, id interface{}) (result concern_type.Type, err error)
+	GetGroupConcern(target mmsg.Target, id interface{}) (result concern_type.Type, err error)
 	GetConcern(id interface{}) (result concern_type.Type, err error)
 
-	CheckAndSetAtAllMark(groupCode int64, id interface{}) (result bool)
-	CheckGroupConcern(groupCode int64, id interface{}, ctype concern_type.Type) error
+	CheckAndSetAtAllMark(target mmsg.Target, id interface{}) (result bool)
+	CheckGroupConcern(target mmsg.Target, id interface{}, ctype concern_type.Type) error
 	CheckConcern(id interface{}, ctype concern_type.Type) error
 
-	AddGroupConcern(groupCode int64, id interface{}, ctype concern_type.Type) (newCtype concern_type.Type, err error)
-	RemoveGroupConcern(groupCode int64, id interface{}, ctype concern_type.Type) (newCtype concern_type.Type, err error)
+	AddGroupConcern(target mmsg.Target, id interface{}, ctype concern_type.Type) (newCtype concern_type.Type, err error)
+	RemoveGroupConcern(target mmsg.Target, id interface{}, ctype concern_type.Type) (newCtype concern_type.Type, err error)
 	RemoveAllByGroupCode(groupCode int64) (keys []string, err error)
+	RemoveAllByTarget(target mmsg.Target) (keys []string, err error)
 
-	ListConcernState(filter func(groupCode int64, id interface{}, p concern_type.Type) bool) (idGroups []int64,
+	ListConcernState(filter func(target mmsg.Target, id interface{}, p concern_type.Type) bool) (targets []mmsg.Target,
 		ids []interface{}, idTypes []concern_type.Type, err error)
 	GroupTypeById(ids []interface{}, types []concern_type.Type) ([]interface{}, []concern_type.Type, error)
 
 	// NotifyGenerator 从 Event 产生多个 Notify
-	NotifyGenerator(groupCode int64, event Event) []Notify
+	NotifyGenerator(target mmsg.Target, event Event) []Notify
 	// Fresh 是一个长生命周期的函数，它产生 Event
 	Fresh(wg *sync.WaitGroup, eventChan chan<- Event)
 	// Dispatch 是一个长生命周期的函数，它从event channel中获取 Event， 并产生 Notify 发送到notify channel
@@ -97,20 +98,31 @@ type StateManager struct {
 	notifyGeneratorFunc NotifyGeneratorFunc
 	logger              *logrus.Entry
 	maxGroupConcern     int
-	largeNotifyCount    atomic.Int32
+	index               *concernIndex
 }
 
-func (c *StateManager) getGroupConcernConfig(groupCode int64, id interface{}) (concernConfig *GroupConcernConfig) {
-	val, err := c.Get(c.GroupConcernConfigKey(groupCode, id), localdb.IgnoreNotFoundOpt())
+func (c *StateManager) getGroupConcernConfig(target mmsg.Target, id interface{}) (concernConfig *GroupConcernConfig) {
+	key := c.GroupConcernConfigKey(target, id)
+	if cached, found := c.index.getConfig(key); found {
+		return cached
+	}
+	gen := c.index.generation()
+	val, err := c.Get(key, localdb.IgnoreNotFoundOpt())
 	if err != nil {
-		c.Logger().WithFields(localutils.GroupLogFields(groupCode)).
+		c.Logger().WithFields(mmsg.TargetLogFields(target)).
 			WithField("id", id).
 			Errorf("GetGroupConcernConfig error %v", err)
 	}
+	defer func() {
+		// 事务中读到的可能是尚未提交的配置，不能缓存
+		if err == nil && !localdb.InTx() {
+			c.index.setConfig(gen, key, concernConfig)
+		}
+	}()
 	if len(val) > 0 {
 		concernConfig, err = NewGroupConcernConfigFromString(val)
 		if err != nil {
-			c.Logger().WithFields(localutils.GroupLogFields(groupCode)).
+			c.Logger().WithFields(mmsg.TargetLogFields(target)).
 				WithFields(logrus.Fields{"id": id, "val": val}).Errorf("NewGroupConcernConfigFromString error %v", err)
 		}
 	}
@@ -121,12 +133,12 @@ func (c *StateManager) getGroupConcernConfig(groupCode int64, id interface{}) (c
 }
 
 // GetGroupConcernConfig 总是返回non-nil
-func (c *StateManager) GetGroupConcernConfig(groupCode int64, id interface{}) IConfig {
-	return c.getGroupConcernConfig(groupCode, id)
+func (c *StateManager) GetGroupConcernConfig(target mmsg.Target, id interface{}) IConfig {
+	return c.getGroupConcernConfig(target, id)
 }
 
 // OperateGroupConcernConfig 在一个rw事务中获取GroupConcernConfig并交给函数，如果返回true，就保存GroupConcernConfig，否则就回滚。
-func (c *StateManager) OperateGroupConcernConfig(groupCode int64, id interface{}, cfg IConfig, f func(concernConfig IConfig) bool) error {
+func (c *StateManager) OperateGroupConcernConfig(target mmsg.Target, id interface{}, cfg IConfig, f func(concernConfig IConfig) bool) error {
 	err := c.RWCover(func() error {
 		if !f(cfg) {
 			return localdb.ErrRollback
@@ -134,33 +146,37 @@ func (c *StateManager) OperateGroupConcernConfig(groupCode int64, id interface{}
 		if err := cfg.Validate(); err != nil {
 			return err
 		}
-		ccfg := c.getGroupConcernConfig(groupCode, id)
+		ccfg := c.getGroupConcernConfig(target, id)
 		ccfg.GroupConcernNotify = *cfg.GetGroupConcernNotify()
 		ccfg.GroupConcernAt = *cfg.GetGroupConcernAt()
 		ccfg.GroupConcernFilter = *cfg.GetGroupConcernFilter()
-		return c.SetJson(c.GroupConcernConfigKey(groupCode, id), ccfg)
+		ccfg.GroupConcernMedia = *cfg.GetGroupConcernMedia()
+		ccfg.GroupConcernDeleted = *cfg.GetGroupConcernDeleted()
+		return c.SetJson(c.GroupConcernConfigKey(target, id), ccfg)
 	})
+	// 无论是否成功都删除缓存，下次读取时从数据库加载
+	c.index.deleteConfig(c.GroupConcernConfigKey(target, id))
 	return err
 }
 
 // CheckAndSetAtAllMark 检查@全体标记是否过期，未设置过或已过期返回true，并重置标记，否则返回false。
 // 因为@全体有次数限制，并且较为恼人，故设置标记，两次@全体之间必须有间隔。
-func (c *StateManager) CheckAndSetAtAllMark(groupCode int64, id interface{}) (result bool) {
-	err := c.Set(c.GroupAtAllMarkKey(groupCode, id), "",
+func (c *StateManager) CheckAndSetAtAllMark(target mmsg.Target, id interface{}) (result bool) {
+	err := c.Set(c.GroupAtAllMarkKey(target, id), "",
 		localdb.SetExpireOpt(time.Hour*2), localdb.SetNoOverWriteOpt())
 	return err == nil
 }
 
-// CheckGroupConcern 检查group是否已经添加过id的ctype订阅，如果添加过，返回 ErrAlreadyExists
-func (c *StateManager) CheckGroupConcern(groupCode int64, id interface{}, ctype concern_type.Type) error {
-	state, _ := c.GetGroupConcern(groupCode, id)
+// CheckGroupConcern 检查target是否已经添加过id的ctype订阅，如果添加过，返回 ErrAlreadyExists
+func (c *StateManager) CheckGroupConcern(target mmsg.Target, id interface{}, ctype concern_type.Type) error {
+	state, _ := c.GetGroupConcern(target, id)
 	if state.ContainAll(ctype) {
 		return ErrAlreadyExists
 	}
 	return nil
 }
 
-// CheckConcern 检查是否有任意一个群添加过id的ctype订阅，如果添加过，返回 ErrAlreadyExists
+// CheckConcern 检查是否有任意一个群或者私聊添加过id的ctype订阅，如果添加过，返回 ErrAlreadyExists
 func (c *StateManager) CheckConcern(id interface{}, ctype concern_type.Type) error {
 	state, err := c.GetConcern(id)
 	if err != nil {
@@ -172,18 +188,19 @@ func (c *StateManager) CheckConcern(id interface{}, ctype concern_type.Type) err
 	return nil
 }
 
-// AddGroupConcern 在group内添加id的ctype订阅，多次添加同样的订阅会返回 ErrAlreadyExists，如果超过订阅上限，则会返回 ErrMaxGroupConcernExceed。
+// AddGroupConcern 在target内添加id的ctype订阅，多次添加同样的订阅会返回 ErrAlreadyExists，如果超过订阅上限，则会返回 ErrMaxGroupConcernExceed。
 // 订阅上限可以使用 SetMaxGroupConcern 设置。
-func (c *StateManager) AddGroupConcern(groupCode int64, id interface{}, ctype concern_type.Type) (newCtype concern_type.Type, err error) {
+func (c *StateManager) AddGroupConcern(target mmsg.Target, id interface{}, ctype concern_type.Type) (newCtype concern_type.Type, err error) {
+	nested := localdb.InTx()
 	err = c.RWCover(func() error {
 		var err error
-		if c.CheckGroupConcern(groupCode, id, ctype) == ErrAlreadyExists {
+		if c.CheckGroupConcern(target, id, ctype) == ErrAlreadyExists {
 			return ErrAlreadyExists
 		}
 
 		if c.maxGroupConcern > 0 {
-			_, ids, ctypes, err := c.ListConcernState(func(_groupCode int64, id interface{}, p concern_type.Type) bool {
-				return _groupCode == groupCode
+			_, ids, ctypes, err := c.ListConcernState(func(_target mmsg.Target, id interface{}, p concern_type.Type) bool {
+				return mmsg.TargetEqual(_target, target)
 			})
 			if err != nil {
 				return err
@@ -194,7 +211,7 @@ func (c *StateManager) AddGroupConcern(groupCode int64, id interface{}, ctype co
 			}
 		}
 
-		groupStateKey := c.GroupConcernStateKey(groupCode, id)
+		groupStateKey := c.GroupConcernStateKey(target, id)
 		newCtype, err = c.upsertConcernType(groupStateKey, ctype)
 		if err != nil {
 			return err
@@ -204,6 +221,9 @@ func (c *StateManager) AddGroupConcern(groupCode int64, id interface{}, ctype co
 	if err != nil {
 		return
 	}
+	c.syncIndex(nested, func() {
+		c.index.update(target, id, newCtype)
+	})
 	if c.useEmit {
 		allCtype, err := c.GetConcern(id)
 		if err != nil {
@@ -215,20 +235,24 @@ func (c *StateManager) AddGroupConcern(groupCode int64, id interface{}, ctype co
 	return
 }
 
-// RemoveGroupConcern 在group内删除id的ctype订阅，并返回删除后当前id的在群内的ctype，删除不存在的订阅会返回 buntdb.ErrNotFound
-func (c *StateManager) RemoveGroupConcern(groupCode int64, id interface{}, ctype concern_type.Type) (newCtype concern_type.Type, err error) {
+// RemoveGroupConcern 在target内删除id的ctype订阅，并返回删除后当前id的在target内的ctype，删除不存在的订阅会返回 buntdb.ErrNotFound
+func (c *StateManager) RemoveGroupConcern(target mmsg.Target, id interface{}, ctype concern_type.Type) (newCtype concern_type.Type, err error) {
+	nested := localdb.InTx()
 	err = c.RWCoverTx(func(tx *buntdb.Tx) error {
 		var err error
-		if c.CheckGroupConcern(groupCode, id, ctype) != ErrAlreadyExists {
+		if c.CheckGroupConcern(target, id, ctype) != ErrAlreadyExists {
 			return buntdb.ErrNotFound
 		}
-		groupStateKey := c.GroupConcernStateKey(groupCode, id)
+		groupStateKey := c.GroupConcernStateKey(target, id)
 		newCtype, err = c.removeConcernType(groupStateKey, ctype)
 		return err
 	})
 	if err != nil {
 		return
 	}
+	c.syncIndex(nested, func() {
+		c.index.update(target, id, newCtype)
+	})
 	if c.useEmit {
 		allCtype, err := c.GetConcern(id)
 		if err != nil {
@@ -246,18 +270,43 @@ func (c *StateManager) RemoveGroupConcern(groupCode int64, id interface{}, ctype
 
 // RemoveAllByGroupCode 删除一个group内所有订阅
 func (c *StateManager) RemoveAllByGroupCode(groupCode int64) (keys []string, err error) {
+	return c.RemoveAllByTarget(mmsg.NewGroupTarget(groupCode))
+}
+
+// RemoveAllByTarget 删除一个target内所有订阅
+func (c *StateManager) RemoveAllByTarget(target mmsg.Target) (keys []string, err error) {
 	var indexKey = []string{
 		c.GroupConcernStateKey(),
 		c.GroupConcernConfigKey(),
 	}
 	var prefixKey = []string{
-		c.GroupConcernStateKey(groupCode),
-		c.GroupConcernConfigKey(groupCode),
+		c.GroupConcernStateKey(target),
+		c.GroupConcernConfigKey(target),
 	}
-	return localdb.RemoveByPrefixAndIndex(prefixKey, indexKey)
+	nested := localdb.InTx()
+	keys, err = localdb.RemoveByPrefixAndIndex(prefixKey, indexKey)
+	if err != nil {
+		c.index.invalidate()
+		return
+	}
+	c.syncIndex(nested, func() {
+		c.index.removeTarget(target)
+	})
+	return
 }
 
+// RemoveAllById 删除所有target内id的订阅
 func (c *StateManager) RemoveAllById(_id interface{}) (err error) {
+	nested := localdb.InTx()
+	defer func() {
+		if err != nil {
+			c.index.invalidate()
+			return
+		}
+		c.syncIndex(nested, func() {
+			c.index.removeId(_id)
+		})
+	}()
 	return c.RWCoverTx(func(tx *buntdb.Tx) error {
 		var removeKey []string
 		var iterErr error
@@ -282,9 +331,9 @@ func (c *StateManager) RemoveAllById(_id interface{}) (err error) {
 	})
 }
 
-// GetGroupConcern 返回一个id在群内的所有 concern_type.Type
-func (c *StateManager) GetGroupConcern(groupCode int64, id interface{}) (result concern_type.Type, err error) {
-	val, err := c.Get(c.GroupConcernStateKey(groupCode, id))
+// GetGroupConcern 返回一个id在target内的所有 concern_type.Type
+func (c *StateManager) GetGroupConcern(target mmsg.Target, id interface{}) (result concern_type.Type, err error) {
+	val, err := c.Get(c.GroupConcernStateKey(target, id))
 	if err != nil {
 		return
 	}
@@ -292,24 +341,67 @@ func (c *StateManager) GetGroupConcern(groupCode int64, id interface{}) (result
 	return
 }
 
-// GetConcern 查询一个id在所有group内的 concern_type.Type
+// GetConcern 查询一个id在所有target内的 concern_type.Type
 func (c *StateManager) GetConcern(id interface{}) (result concern_type.Type, err error) {
-	var ctypes []concern_type.Type
-	_, _, ctypes, err = c.ListConcernState(func(groupCode int64, _id interface{}, p concern_type.Type) bool {
-		return id == _id
+	entries, err := c.lookupConcernState(id)
+	for _, entry := range entries {
+		result = result.Add(entry.ctype)
+	}
+	return
+}
+
+// lookupConcernState 查询订阅了id的所有target，优先使用内存索引，索引未就绪时从数据库重建
+func (c *StateManager) lookupConcernState(id interface{}) ([]indexEntry, error) {
+	if entries, ok := c.index.get(id); ok {
+		return entries, nil
+	}
+	targets, ids, ctypes, err := c.rebuildIndex()
+	if err != nil {
+		return nil, err
+	}
+	var entries []indexEntry
+	for index := range ids {
+		if ids[index] == id {
+			entries = append(entries, indexEntry{target: targets[index], ctype: ctypes[index]})
+		}
+	}
+	return entries, nil
+}
+
+// rebuildIndex 从数据库读取所有订阅并重建内存索引，返回读取到的所有订阅
+func (c *StateManager) rebuildIndex() (targets []mmsg.Target, ids []interface{}, ctypes []concern_type.Type, err error) {
+	gen := c.index.generation()
+	targets, ids, ctypes, err = c.ListConcernState(func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
+		return true
 	})
-	result = concern_type.Empty.Add(ctypes...)
+	if err != nil {
+		return
+	}
+	// 事务中可以读到尚未提交的修改，不能用来重建索引
+	if !localdb.InTx() {
+		c.index.rebuild(gen, targets, ids, ctypes)
+	}
 	return
 }
 
+// syncIndex 在订阅修改成功后同步内存索引
+// 如果修改发生在外层事务中，外层事务仍然可能回滚，此时只能让索引失效，下次使用时重建
+func (c *StateManager) syncIndex(nested bool, f func()) {
+	if nested {
+		c.index.invalidate()
+		return
+	}
+	f()
+}
+
 // ListConcernState 遍历所有订阅，并根据 filter 返回需要的订阅
-func (c *StateManager) ListConcernState(filter func(groupCode int64, id interface{}, p concern_type.Type) bool) (groupCodes []int64, ids []interface{}, idTypes []concern_type.Type, err error) {
+func (c *StateManager) ListConcernState(filter func(target mmsg.Target, id interface{}, p concern_type.Type) bool) (targets []mmsg.Target, ids []interface{}, idTypes []concern_type.Type, err error) {
 	err = c.RCoverTx(func(tx *buntdb.Tx) error {
 		var iterErr error
 		err := tx.Ascend(c.GroupConcernStateKey(), func(key, value string) bool {
-			var groupCode int64
+			var target mmsg.Target
 			var id interface{}
-			groupCode, id, iterErr = c.ParseGroupConcernStateKey(key)
+			target, id, iterErr = c.ParseGroupConcernStateKey(key)
 			if iterErr != nil {
 				return false
 			}
@@ -317,8 +409,8 @@ func (c *StateManager) ListConcernState(filter func(groupCode int64, id interfac
 			if ctype.Empty() {
 				return true
 			}
-			if filter(groupCode, id, ctype) == true {
-				groupCodes = append(groupCodes, groupCode)
+			if filter(target, id, ctype) == true {
+				targets = append(targets, target)
 				ids = append(ids, id)
 				idTypes = append(idTypes, ctype)
 			}
@@ -387,29 +479,29 @@ func (c *StateManager) SetMaxGroupConcern(maxGroupConcern int) {
 	c.maxGroupConcern = maxGroupConcern
 }
 
-// FreshIndex 刷新 group 的 index，通常不需要用户主动调用
+// FreshIndex 刷新 target 的 index，通常不需要用户主动调用
 // 在单元测试中有时候需要主动刷新 index，否则遍历时会返回 buntdb.ErrNotFound
-func (c *StateManager) FreshIndex(groups ...int64) {
+func (c *StateManager) FreshIndex(targets ...mmsg.Target) {
 	for _, pattern := range []localdb.KeyPatternFunc{
 		c.GroupConcernStateKey, c.GroupConcernConfigKey,
 	} {
 		c.CreatePatternIndex(pattern, nil)
 	}
-	var groupSet = make(map[int64]interface{})
-	if len(groups) == 0 {
+	var targetSet = make(map[string]interface{})
+	if len(targets) == 0 {
 		for _, groupInfo := range localutils.GetBot().GetGroupList() {
-			groupSet[groupInfo.Code] = struct{}{}
+			targetSet[mmsg.NewGroupTarget(groupInfo.Code).String()] = struct{}{}
 		}
 	} else {
-		for _, g := range groups {
-			groupSet[g] = struct{}{}
+		for _, target := range targets {
+			targetSet[target.String()] = struct{}{}
 		}
 	}
-	c.ListConcernState(func(groupCode int64, id interface{}, p concern_type.Type) bool {
-		groupSet[groupCode] = struct{}{}
+	c.ListConcernState(func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
+		targetSet[target.String()] = struct{}{}
 		return true
 	})
-	for g := range groupSet {
+	for g := range targetSet {
 		for _, pattern := range []localdb.KeyPatternFunc{
 			c.GroupConcernStateKey, c.GroupConcernConfigKey,
 		} {
@@ -480,6 +572,9 @@ func (c *StateManager) Start() error {
 		c.UseDispatchFunc(c.DefaultDispatch())
 	}
 	c.FreshIndex()
+	if _, _, _, err := c.rebuildIndex(); err != nil {
+		return err
+	}
 	if runtime.NumCPU() >= 3 {
 		for i := 0; i < 3; i++ {
 			go c.Dispatch(&c.wg, c.eventChan, c.notifyChan)
@@ -489,7 +584,7 @@ func (c *StateManager) Start() error {
 	}
 	if c.useEmit {
 		c.emitQueue.Start()
-		_, ids, ctypes, err := c.ListConcernState(func(groupCode int64, id interface{}, p concern_type.Type) bool {
+		_, ids, ctypes, err := c.ListConcernState(func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
 			return true
 		})
 		if err != nil {
@@ -547,6 +642,21 @@ func (c *StateManager) EmitQueueFresher(doFresh func(p concern_type.Type, id int
 	}
 }
 
+// EmitEvent 在 FreshFunc 之外产生一个 Event，例如定时提醒，StateManager 停止后返回 false
+func (c *StateManager) EmitEvent(event Event) bool {
+	c.freshWg.Add(1)
+	defer c.freshWg.Done()
+	if c.ctx.Err() != nil {
+		return false
+	}
+	select {
+	case c.eventChan <- event:
+		return true
+	case <-c.ctx.Done():
+		return false
+	}
+}
+
 func (c *StateManager) Fresh(wg *sync.WaitGroup, eventChan chan<- Event) {
 	defer func() {
 		if e := recover(); e != nil {
@@ -573,8 +683,8 @@ func (c *StateManager) Dispatch(wg *sync.WaitGroup, eventChan <-chan Event, noti
 	c.dispatchFunc(eventChan, notifyChan)
 }
 
-func (c *StateManager) NotifyGenerator(groupCode int64, event Event) []Notify {
-	return c.notifyGeneratorFunc(groupCode, event)
+func (c *StateManager) NotifyGenerator(target mmsg.Target, event Event) []Notify {
+	return c.notifyGeneratorFunc(target, event)
 }
 
 func (c *StateManager) filterNotify(inotify Notify) bool {
@@ -587,69 +697,58 @@ func (c *StateManager) filterNotify(inotify Notify) bool {
 		nLogger.Errorf("filterNotify: GetConcernBySiteAndType error %v", err)
 		return true
 	}
-	concernConfig := concern.GetStateManager().GetGroupConcernConfig(inotify.GetGroupCode(), inotify.GetUid())
+	concernConfig := concern.GetStateManager().GetGroupConcernConfig(inotify.GetTarget(), inotify.GetUid())
 
-	sendHookResult := concernConfig.ShouldSendHook(inotify)
-	if !sendHookResult.Pass {
-		nLogger.WithField("Reason", sendHookResult.Reason).Trace("notify filtered by hook ShouldSendHook")
-		return false
-	}
+	observeLiveStatus(inotify)
+	observeLiveSession(inotify)
 
-	newsFilterHook := concernConfig.FilterHook(inotify)
-	if !newsFilterHook.Pass {
-		nLogger.WithField("Reason", newsFilterHook.Reason).Trace("notify filtered by hook FilterHook")
+	if hookName, result := RunSendHooks(concernConfig, inotify); !result.Pass {
+		nLogger.WithField("Reason", result.Reason).Tracef("notify filtered by hook %v", hookName)
 		return false
 	}
 	return true
 }
 
 // DefaultDispatch 是 DispatchFunc 的默认实现。
-// 它查询所有订阅过此 Event.GetUid 与 Event.Type 的群，并为每个群生成 Notify 发送给框架
+// 它通过内存索引查询所有订阅过此 Event.GetUid 与 Event.Type 的群与私聊，并为每个 target 生成 Notify 发送给框架
 func (c *StateManager) DefaultDispatch() DispatchFunc {
 	return func(eventChan <-chan Event, notifyChan chan<- Notify) {
 		for event := range eventChan {
 			log := event.Logger()
-			groups, _, _, err := c.ListConcernState(func(groupCode int64, id interface{}, p concern_type.Type) bool {
-				return event.GetUid() == id && p.ContainAll(event.Type())
-			})
+			entries, err := c.lookupConcernState(event.GetUid())
 			if err != nil {
-				log.Errorf("StateManager %v: ListConcernState error %v", c.name, err)
+				log.Errorf("StateManager %v: lookupConcernState error %v", c.name, err)
 				continue
 			}
 			var notifies []Notify
-			var filteredGroups = make(map[int64]interface{})
-			for _, groupCode := range groups {
-				for _, n := range c.NotifyGenerator(groupCode, event) {
+			var filteredTargets = make(map[string]interface{})
+			for _, entry := range entries {
+				if !entry.ctype.ContainAll(event.Type()) {
+					continue
+				}
+				for _, n := range c.NotifyGenerator(entry.target, event) {
 					if c.filterNotify(n) {
 						notifies = append(notifies, n)
-						filteredGroups[n.GetGroupCode()] = true
+						filteredTargets[n.GetTarget().String()] = true
 					}
 				}
 			}
 			if len(notifies) == 0 {
 				continue
 			}
-			log.Infof("new event - %v %v - %v notify for %v groups", event.Site(), event.Type().String(), len(notifies), len(filteredGroups))
-			largeNotifyLimit := cfg.GetLargeNotifyLimit()
-
-			if len(notifies) >= largeNotifyLimit {
-				log.Warnf("警告：当前事件将推送至%v条消息到%v个群（超过%v），为保证帐号稳定，将增加此事件的推送间隔，防止短时间内发送大量消息",
-					len(notifies), len(filteredGroups), largeNotifyLimit)
-				go func(notifies []Notify) {
-					cnt := c.largeNotifyCount.Inc()
-					ticker := time.NewTicker(time.Second*1 + time.Second*time.Duration(2*cnt))
-					for _, n := range notifies {
-						<-ticker.C
-						notifyChan <- n
-					}
-					ticker.Stop()
-					c.largeNotifyCount.Dec()
-				}(notifies)
-			} else {
-				for _, n := range notifies {
-					notifyChan <- n
+			log.Infof("new event - %v %v - %v notify for %v targets", event.Site(), event.Type().String(), len(notifies), len(filteredTargets))
+			if largeNotifyLimit := cfg.GetLargeNotifyLimit(); len(notifies) >= largeNotifyLimit {
+				if cfg.GetNotifyGlobalLimit() > 0 {
+					log.Warnf("警告：当前事件将推送至%v条消息到%v个群（超过%v），推送将按照notify配置的限流依次发送",
+						len(notifies), len(filteredTargets), largeNotifyLimit)
+				} else {
+					log.Warnf("警告：当前事件将推送至%v条消息到%v个群（超过%v），notify.globalLimit为0不限流，短时间内发送大量消息可能导致账号被风控",
+						len(notifies), len(filteredTargets), largeNotifyLimit)
 				}
 			}
+			for _, n := range notifies {
+				notifyChan <- n
+			}
 		}
 	}
 }
@@ -704,6 +803,7 @@ func NewStateManagerWithCustomKey(name string, keySet KeySet, notifyChan chan<-
 		ctx:        ctx,
 		cancelCtx:  cancel,
 		logger:     logger.WithFields(logrus.Fields{"Name": name}),
+		index:      newConcernIndex(),
 	}
 	return sm
 }
@@ -39,17 +39,17 @@ func (t *testKeySet) GroupAtAllMarkKey(keys ...interface{}) string {
 	return localdb.NamedKey("test4", keys)
 }
 
-func (t *testKeySet) ParseGroupConcernStateKey(key string) (groupCode int64, id interface{}, err error) {
-	return localdb.ParseConcernStateKeyWithInt64(key)
+func (t *testKeySet) ParseGroupConcernStateKey(key string) (target mmsg.Target, id interface{}, err error) {
+	return ParseConcernStateKeyWithInt64(key)
 }
 
 type testEvent struct {
-	id        int64
-	groupCode int64
+	id     int64
+	target mmsg.Target
 }
 
-func (t *testEvent) GetGroupCode() int64 {
-	return t.groupCode
+func (t *testEvent) GetTarget() mmsg.Target {
+	return t.target
 }
 
 func (t *testEvent) ToMessage() *mmsg.MSG {
@@ -75,7 +75,7 @@ func (t *testEvent) Logger() *logrus.Entry {
 func newStateManager(t *testing.T) *StateManager {
 	sm := NewStateManagerWithCustomKey("test", &testKeySet{}, nil)
 	assert.NotNil(t, sm)
-	sm.FreshIndex(test.G1, test.G2)
+	sm.FreshIndex(mmsg.NewGroupTarget(test.G1), mmsg.NewGroupTarget(test.G2))
 	return sm
 }
 
@@ -115,12 +115,12 @@ func TestNewStateManager(t *testing.T) {
 	assert.Panics(t, func() {
 		sm.Start()
 	})
-	sm.UseNotifyGeneratorFunc(func(groupCode int64, event Event) []Notify {
+	sm.UseNotifyGeneratorFunc(func(target mmsg.Target, event Event) []Notify {
 		return nil
 	})
 	sm.UseEmitQueue()
 
-	_, err := sm.AddGroupConcern(test.G1, test.UID1, "test")
+	_, err := sm.AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, "test")
 	assert.Nil(t, err)
 	sm.Start()
 	defer sm.Stop()
@@ -169,7 +169,7 @@ func TestNewStateManager2(t *testing.T) {
 			panic("error")
 		}
 	})
-	sm.UseNotifyGeneratorFunc(func(groupCode int64, event Event) []Notify {
+	sm.UseNotifyGeneratorFunc(func(target mmsg.Target, event Event) []Notify {
 		return nil
 	})
 	assert.Nil(t, sm.Start())
@@ -186,8 +186,8 @@ func TestStateManagerNotify(t *testing.T) {
 	testEventChan := make(chan Event, 16)
 	testNotifyChan := make(chan Notify, 16)
 	sm.notifyChan = testNotifyChan
-	sm.UseNotifyGeneratorFunc(func(groupCode int64, event Event) []Notify {
-		event.(*testEvent).groupCode = groupCode
+	sm.UseNotifyGeneratorFunc(func(target mmsg.Target, event Event) []Notify {
+		event.(*testEvent).target = target
 		return []Notify{
 			event.(*testEvent),
 		}
@@ -206,9 +206,9 @@ func TestStateManagerNotify(t *testing.T) {
 	})
 	sm.Start()
 
-	_, err = sm.AddGroupConcern(test.G1, test.UID1, testType)
+	_, err = sm.AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, testType)
 	assert.Nil(t, err)
-	_, err = sm.AddGroupConcern(test.G2, test.UID1, testType)
+	_, err = sm.AddGroupConcern(mmsg.NewPrivateTarget(test.UID2), test.UID1, testType)
 	assert.Nil(t, err)
 	testEventChan <- &testEvent{
 		id: test.UID2,
@@ -229,7 +229,7 @@ func TestStateManagerNotify(t *testing.T) {
 		case notify := <-testNotifyChan:
 			assert.NotNil(t, notify)
 			assert.EqualValues(t, test.UID1, notify.GetUid())
-			assert.True(t, notify.GetGroupCode() == test.G1 || notify.GetGroupCode() == test.G2)
+			assert.Contains(t, []int64{test.G1, test.UID2}, notify.GetTarget().TargetCode())
 		case <-time.After(time.Second):
 			assert.Fail(t, "no item received")
 		}
@@ -240,7 +240,7 @@ func TestStateManagerNotify(t *testing.T) {
 func TestStateManager_GroupConcernConfig(t *testing.T) {
 	sm := newStateManager(t)
 
-	c := sm.GetGroupConcernConfig(test.G1, test.UID1)
+	c := sm.GetGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1)
 	assert.NotNil(t, c)
 
 	test.InitBuntdb(t)
@@ -248,14 +248,14 @@ func TestStateManager_GroupConcernConfig(t *testing.T) {
 
 	sm = newStateManager(t)
 
-	c = sm.GetGroupConcernConfig(test.G1, test.UID1)
+	c = sm.GetGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1)
 	assert.NotNil(t, c)
 
 	assert.Nil(t, c.GetGroupConcernAt().AtSomeone)
 	assert.EqualValues(t, c, new(GroupConcernConfig))
 
-	cfg := sm.GetGroupConcernConfig(test.G1, test.UID1)
-	err := sm.OperateGroupConcernConfig(test.G1, test.UID1, cfg, func(concernConfig IConfig) bool {
+	cfg := sm.GetGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1)
+	err := sm.OperateGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1, cfg, func(concernConfig IConfig) bool {
 		concernConfig.GetGroupConcernNotify().TitleChangeNotify = test.BibiliLive
 		concernConfig.GetGroupConcernAt().AtSomeone = []*AtSomeone{
 			{
@@ -264,15 +264,21 @@ func TestStateManager_GroupConcernConfig(t *testing.T) {
 			},
 		}
 		concernConfig.GetGroupConcernAt().AtAll = test.YoutubeLive
+		concernConfig.GetGroupConcernMedia().Archive = test.BilibiliNews
+		concernConfig.GetGroupConcernMedia().Folder = "test"
+		concernConfig.GetGroupConcernDeleted().Action = DeletedActionRecall
 		return true
 	})
 	assert.Nil(t, err)
 
-	c = sm.GetGroupConcernConfig(test.G1, test.UID1)
+	c = sm.GetGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1)
 	assert.NotNil(t, c)
 	assert.NotNil(t, c.GetGroupConcernFilter())
 	assert.EqualValues(t, c.GetGroupConcernNotify().TitleChangeNotify, test.BibiliLive)
 	assert.EqualValues(t, c.GetGroupConcernAt().AtAll, test.YoutubeLive)
+	assert.True(t, c.GetGroupConcernMedia().CheckArchive(test.BilibiliNews))
+	assert.Equal(t, "test", c.GetGroupConcernMedia().GetFolder())
+	assert.Equal(t, DeletedActionRecall, c.GetGroupConcernDeleted().GetAction())
 	assert.EqualValues(t, c.GetGroupConcernAt().AtSomeone, []*AtSomeone{
 		{
 			Ctype:  test.DouyuLive,
@@ -280,19 +286,19 @@ func TestStateManager_GroupConcernConfig(t *testing.T) {
 		},
 	})
 
-	cfg = sm.GetGroupConcernConfig(test.G1, test.UID1)
-	err = sm.OperateGroupConcernConfig(test.G1, test.UID1, cfg, func(concernConfig IConfig) bool {
+	cfg = sm.GetGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1)
+	err = sm.OperateGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1, cfg, func(concernConfig IConfig) bool {
 		concernConfig.GetGroupConcernNotify().TitleChangeNotify = concern_type.Empty
 		return false
 	})
 	assert.EqualValues(t, localdb.ErrRollback, err)
 
-	c = sm.GetGroupConcernConfig(test.G1, test.UID1)
+	c = sm.GetGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1)
 	assert.NotNil(t, c)
 	assert.EqualValues(t, c.GetGroupConcernNotify().TitleChangeNotify, test.BibiliLive)
 
-	cfg = sm.GetGroupConcernConfig(test.G1, test.UID1)
-	err = sm.OperateGroupConcernConfig(test.G1, test.UID1, cfg, func(concernConfig IConfig) bool {
+	cfg = sm.GetGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1)
+	err = sm.OperateGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1, cfg, func(concernConfig IConfig) bool {
 		concernConfig.GetGroupConcernFilter().Type = FilterTypeType
 		concernConfig.GetGroupConcernFilter().Config = (&GroupConcernFilterConfigByType{Type: []string{"q", "w", "e"}}).ToString()
 		return true
@@ -306,8 +312,8 @@ func TestStateManager_CheckAndSetAtAllMark(t *testing.T) {
 
 	sm := newStateManager(t)
 
-	assert.True(t, sm.CheckAndSetAtAllMark(test.G1, test.UID1))
-	assert.False(t, sm.CheckAndSetAtAllMark(test.G1, test.UID1))
+	assert.True(t, sm.CheckAndSetAtAllMark(mmsg.NewGroupTarget(test.G1), test.UID1))
+	assert.False(t, sm.CheckAndSetAtAllMark(mmsg.NewGroupTarget(test.G1), test.UID1))
 }
 
 func TestStateManager_FreshCheck(t *testing.T) {
@@ -334,31 +340,31 @@ func TestStateManager_GroupConcern(t *testing.T) {
 	sm := newStateManager(t)
 	sm.UseEmitQueue()
 
-	assert.Nil(t, sm.CheckGroupConcern(test.G1, test.UID1, test.BibiliLive))
+	assert.Nil(t, sm.CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.BibiliLive))
 
-	_, err = sm.AddGroupConcern(test.G1, test.UID2, test.HuyaLive)
+	_, err = sm.AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID2, test.HuyaLive)
 	assert.Nil(t, err)
-	_, err = sm.RemoveGroupConcern(test.G1, test.UID2, test.HuyaLive)
+	_, err = sm.RemoveGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID2, test.HuyaLive)
 	assert.Nil(t, err)
 
-	_, err = sm.AddGroupConcern(test.G1, test.UID1, test.BibiliLive.Add(test.YoutubeLive))
+	_, err = sm.AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.BibiliLive.Add(test.YoutubeLive))
 	assert.Nil(t, err)
 
-	_, err = sm.RemoveGroupConcern(test.G1, test.UID1, test.BibiliLive)
+	_, err = sm.RemoveGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.BibiliLive)
 	assert.Nil(t, err)
-	_, err = sm.RemoveGroupConcern(test.G1, test.UID1, test.BibiliLive)
+	_, err = sm.RemoveGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.BibiliLive)
 	assert.EqualValues(t, buntdb.ErrNotFound, err)
-	_, err = sm.AddGroupConcern(test.G1, test.UID1, test.BibiliLive.Add(test.YoutubeLive))
+	_, err = sm.AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.BibiliLive.Add(test.YoutubeLive))
 	assert.Nil(t, err)
 
-	_, err = sm.AddGroupConcern(test.G2, test.UID1, test.HuyaLive)
+	_, err = sm.AddGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID1, test.HuyaLive)
 	assert.Nil(t, err)
-	_, err = sm.AddGroupConcern(test.G2, test.UID1, test.HuyaLive)
+	_, err = sm.AddGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID1, test.HuyaLive)
 	assert.EqualValues(t, ErrAlreadyExists, err)
 
-	_, err = sm.AddGroupConcern(test.G1, test.UID2, test.DouyuLive)
+	_, err = sm.AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID2, test.DouyuLive)
 	assert.Nil(t, err)
-	_, err = sm.AddGroupConcern(test.G2, test.UID2, test.DouyuLive)
+	_, err = sm.AddGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID2, test.DouyuLive)
 	assert.Nil(t, err)
 
 	ctype, err := sm.GetConcern(test.UID1)
@@ -369,26 +375,26 @@ func TestStateManager_GroupConcern(t *testing.T) {
 	// G2 UID1: hlive       , UID2  dlive
 
 	// 检查UID在G1中有 blive和ylive
-	assert.EqualValues(t, ErrAlreadyExists, sm.CheckGroupConcern(test.G1, test.UID1, test.BibiliLive))
-	assert.EqualValues(t, ErrAlreadyExists, sm.CheckGroupConcern(test.G1, test.UID1, test.YoutubeLive))
+	assert.EqualValues(t, ErrAlreadyExists, sm.CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.BibiliLive))
+	assert.EqualValues(t, ErrAlreadyExists, sm.CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.YoutubeLive))
 
 	// 检查UID在G1没有 hlive和dlive
-	assert.EqualValues(t, nil, sm.CheckGroupConcern(test.G1, test.UID1, test.DouyuLive))
-	assert.EqualValues(t, nil, sm.CheckGroupConcern(test.G1, test.UID1, test.HuyaLive))
+	assert.EqualValues(t, nil, sm.CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.DouyuLive))
+	assert.EqualValues(t, nil, sm.CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.HuyaLive))
 
 	// 检查UID在G2中有hlive
-	assert.EqualValues(t, ErrAlreadyExists, sm.CheckGroupConcern(test.G2, test.UID1, test.HuyaLive))
+	assert.EqualValues(t, ErrAlreadyExists, sm.CheckGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID1, test.HuyaLive))
 
 	// 检查UID2 在G1和G2中有dlive
-	assert.EqualValues(t, ErrAlreadyExists, sm.CheckGroupConcern(test.G1, test.UID2, test.DouyuLive))
-	assert.EqualValues(t, ErrAlreadyExists, sm.CheckGroupConcern(test.G2, test.UID2, test.DouyuLive))
+	assert.EqualValues(t, ErrAlreadyExists, sm.CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID2, test.DouyuLive))
+	assert.EqualValues(t, ErrAlreadyExists, sm.CheckGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID2, test.DouyuLive))
 
 	// 检查UID2 在G1中没有blive和ylive
-	assert.EqualValues(t, nil, sm.CheckGroupConcern(test.G1, test.UID2, test.BibiliLive))
-	assert.EqualValues(t, nil, sm.CheckGroupConcern(test.G1, test.UID2, test.YoutubeLive))
+	assert.EqualValues(t, nil, sm.CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID2, test.BibiliLive))
+	assert.EqualValues(t, nil, sm.CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID2, test.YoutubeLive))
 
 	// 添加已有的状态会报错
-	_, err = sm.AddGroupConcern(test.G1, test.UID1, test.BibiliLive)
+	_, err = sm.AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.BibiliLive)
 	assert.EqualValues(t, ErrAlreadyExists, err)
 
 	// 检查UID在所有G中有blive和ylive和hlive
@@ -397,7 +403,7 @@ func TestStateManager_GroupConcern(t *testing.T) {
 	assert.Nil(t, sm.CheckConcern(test.UID1, test.DouyuLive))
 
 	// 删除UID在G1中的ylive
-	_, err = sm.RemoveGroupConcern(test.G1, test.UID1, test.YoutubeLive)
+	_, err = sm.RemoveGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.YoutubeLive)
 	assert.Nil(t, err)
 
 	ctype, err = sm.GetConcern(test.UID1)
@@ -409,29 +415,29 @@ func TestStateManager_GroupConcern(t *testing.T) {
 	// 检查UID在所有G中有blive和hlive
 	assert.EqualValues(t, ErrAlreadyExists, sm.CheckConcern(test.UID1, test.BibiliLive.Add(test.HuyaLive)))
 	// 检查UID在G1中有blive
-	assert.EqualValues(t, ErrAlreadyExists, sm.CheckGroupConcern(test.G1, test.UID1, test.BibiliLive))
+	assert.EqualValues(t, ErrAlreadyExists, sm.CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.BibiliLive))
 	// 检查UID在G2中有hlive
-	assert.EqualValues(t, ErrAlreadyExists, sm.CheckGroupConcern(test.G2, test.UID1, test.HuyaLive))
+	assert.EqualValues(t, ErrAlreadyExists, sm.CheckGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID1, test.HuyaLive))
 
 	// 列出所有有hlive的记录，应该只有UID G2
-	groups, ids, ctypes, err := sm.ListConcernState(func(groupCode int64, id interface{}, p concern_type.Type) bool {
+	targets, ids, ctypes, err := sm.ListConcernState(func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
 		return p.ContainAny(test.HuyaLive)
 	})
 	assert.Nil(t, err)
-	assert.Equal(t, 1, len(groups))
+	assert.Equal(t, 1, len(targets))
 	assert.Equal(t, 1, len(ids))
 	assert.Equal(t, 1, len(ctypes))
-	assert.Equal(t, test.G2, groups[0])
+	assert.True(t, mmsg.TargetEqual(mmsg.NewGroupTarget(test.G2), targets[0]))
 	assert.Equal(t, test.UID1, ids[0])
 	assert.Equal(t, test.HuyaLive, ctypes[0])
 
-	ctype, err = sm.GetGroupConcern(test.G2, test.UID2)
+	ctype, err = sm.GetGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID2)
 	assert.Nil(t, err)
 	assert.EqualValues(t, test.DouyuLive, ctype)
 
 	// G1中有 UID1:blive UID2:dlive
-	_, ids, ctypes, err = sm.ListConcernState(func(g int64, id interface{}, p concern_type.Type) bool {
-		return g == test.G1
+	_, ids, ctypes, err = sm.ListConcernState(func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
+		return mmsg.TargetEqual(target, mmsg.NewGroupTarget(test.G1))
 	})
 	assert.Nil(t, err)
 	assert.EqualValues(t, 2, len(ids))
@@ -464,10 +470,10 @@ func TestStateManager_GroupConcern(t *testing.T) {
 
 	_, err = sm.RemoveAllByGroupCode(test.G2)
 	assert.Nil(t, err)
-	ctype, err = sm.GetGroupConcern(test.G1, test.UID2)
+	ctype, err = sm.GetGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID2)
 	assert.Nil(t, err)
 	assert.EqualValues(t, test.DouyuLive, ctype)
-	ctype, err = sm.GetGroupConcern(test.G2, test.UID2)
+	ctype, err = sm.GetGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID2)
 	assert.EqualValues(t, buntdb.ErrNotFound, err)
 }
 
@@ -478,26 +484,66 @@ func TestStateManager_GroupConcern2(t *testing.T) {
 	var err error
 	sm := newStateManager(t)
 	sm.UseEmitQueue()
-	_, err = sm.AddGroupConcern(test.G1, test.UID1, test.BilibiliNews)
+	_, err = sm.AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.BilibiliNews)
 	assert.Nil(t, err)
 
-	_, err = sm.AddGroupConcern(test.G1, test.UID1, test.BibiliLive)
+	_, err = sm.AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.BibiliLive)
 	assert.Nil(t, err)
 
-	_, err = sm.AddGroupConcern(test.G1, test.UID1, test.DouyuLive)
+	_, err = sm.AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.DouyuLive)
 	assert.Nil(t, err)
 
-	_, err = sm.AddGroupConcern(test.G1, test.UID1, test.HuyaLive)
+	_, err = sm.AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.HuyaLive)
 	assert.Nil(t, err)
 
-	ctype, err := sm.GetGroupConcern(test.G1, test.UID1)
+	ctype, err := sm.GetGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1)
 	assert.Nil(t, err)
 	assert.EqualValues(t, test.BilibiliNews.Add(test.BibiliLive, test.DouyuLive, test.HuyaLive), ctype)
 }
 
+func TestStateManager_PrivateConcern(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	var err error
+	sm := newStateManager(t)
+	friend := mmsg.NewPrivateTarget(test.G1)
+	group := mmsg.NewGroupTarget(test.G1)
+	sm.FreshIndex(friend)
+
+	_, err = sm.AddGroupConcern(friend, test.UID1, test.BibiliLive)
+	assert.Nil(t, err)
+	_, err = sm.AddGroupConcern(group, test.UID1, test.BilibiliNews)
+	assert.Nil(t, err)
+
+	// 同样号码的群与私聊互不影响
+	ctype, err := sm.GetGroupConcern(friend, test.UID1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, test.BibiliLive, ctype)
+	ctype, err = sm.GetGroupConcern(group, test.UID1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, test.BilibiliNews, ctype)
+
+	targets, _, _, err := sm.ListConcernState(func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
+		return target.TargetType().IsPrivate()
+	})
+	assert.Nil(t, err)
+	if assert.Len(t, targets, 1) {
+		assert.True(t, mmsg.TargetEqual(friend, targets[0]))
+	}
+
+	_, err = sm.RemoveAllByTarget(friend)
+	assert.Nil(t, err)
+	_, err = sm.GetGroupConcern(friend, test.UID1)
+	assert.EqualValues(t, buntdb.ErrNotFound, err)
+	ctype, err = sm.GetGroupConcern(group, test.UID1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, test.BilibiliNews, ctype)
+}
+
 func listIds(sm *StateManager) ([]interface{}, error) {
 	var m = make(map[interface{}]interface{})
-	_, _, _, err := sm.ListConcernState(func(groupCode int64, id interface{}, p concern_type.Type) bool {
+	_, _, _, err := sm.ListConcernState(func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
 		m[id] = struct{}{}
 		return true
 	})
@@ -5,9 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"github.com/Sora233/MiraiGo-Template/config"
-	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"math/rand"
 	"net/http/cookiejar"
+	"sort"
 	"time"
 
 	"github.com/Sora233/MiraiGo-Template/utils"
@@ -27,6 +27,8 @@ const (
 	Site = "douyin"
 	// 这个插件支持的订阅类型可以像这样自定义，然后在 Types 中返回
 	Live concern_type.Type = "live"
+	// News 新发布的视频和图文
+	News concern_type.Type = "news"
 	// 当像这样定义的时候，支持 /watch -s mysite -t type1 id
 	// 当实现的时候，请修改上面的定义
 	// API Base URL
@@ -41,6 +43,7 @@ var (
 	BasePath = map[string]string{
 		PathGetUserInfo:         BaseHost,
 		PathCheckUserLiveStatus: BaseLiveHost,
+		PathGetUserPost:         BaseHost,
 	}
 )
 
@@ -50,8 +53,8 @@ type StateManager struct {
 }
 
 // GetGroupConcernConfig 重写 concern.StateManager 的GetGroupConcernConfig方法，让我们自己定义的 GroupConcernConfig 生效
-func (d *StateManager) GetGroupConcernConfig(groupCode int64, id interface{}) concern.IConfig {
-	return NewGroupConcernConfig(d.StateManager.GetGroupConcernConfig(groupCode, id))
+func (d *StateManager) GetGroupConcernConfig(target mmsg.Target, id interface{}) concern.IConfig {
+	return NewGroupConcernConfig(d.StateManager.GetGroupConcernConfig(target, id))
 }
 
 type Concern struct {
@@ -64,7 +67,7 @@ func (d *Concern) Site() string {
 }
 
 func (d *Concern) Types() []concern_type.Type {
-	return []concern_type.Type{Live}
+	return []concern_type.Type{Live, News}
 }
 
 func (d *Concern) ParseId(s string) (interface{}, error) {
@@ -115,14 +118,14 @@ func (d *Concern) AddUserInfo(info *UserInfo) error {
 	return d.SetJson(d.UserInfoKey(info.SecUid), info)
 }
 
-func (d *Concern) Add(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+func (d *Concern) Add(ctx mmsg.IMsgCtx, target mmsg.Target, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	var err error
 	var uid = id.(string)
-	log := logger.WithFields(localutils.GroupLogFields(groupCode)).WithField("id", id)
+	log := logger.WithFields(mmsg.TargetLogFields(target)).WithField("id", id)
 	// 这里是添加订阅的函数
-	// 可以使 c.StateManager.AddGroupConcern(groupCode, id, ctype) 来添加这个订阅
+	// 可以使 c.StateManager.AddGroupConcern(target, id, ctype) 来添加这个订阅
 	// 通常在添加订阅前还需要通过id访问网站上的个人信息页面，来确定id是否存在，是否可以正常订阅
-	err = d.StateManager.CheckGroupConcern(groupCode, id, ctype)
+	err = d.StateManager.CheckGroupConcern(target, id, ctype)
 	if err != nil {
 		return nil, err
 	}
@@ -133,17 +136,16 @@ func (d *Concern) Add(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctype c
 		log.Errorf("FindOrLoadUserInfo error %v", err)
 		return nil, fmt.Errorf("查询用户信息失败 %v - %v", id, err)
 	}
-	_, err = d.GetStateManager().AddGroupConcern(groupCode, id, ctype)
+	_, err = d.GetStateManager().AddGroupConcern(target, id, ctype)
 	if err != nil {
 		return nil, err
 	}
 	if ctype.ContainAny(Live) {
-		// 其他群关注了同一uid，并且推送过Living，那么给新watch的群也推一份
+		// 其他群关注了同一uid，并且推送过Living，那么给新watch的群或者私聊也推一份
 		if liveInfo != nil && liveInfo.WebRoomId != "" {
-			if ctx.GetTarget().TargetType().IsGroup() {
-				defer d.GroupWatchNotify(groupCode, uid)
-			}
-			if ctx.GetTarget().TargetType().IsPrivate() {
+			if mmsg.TargetEqual(ctx.GetTarget(), target) {
+				defer d.GroupWatchNotify(target, uid)
+			} else {
 				defer ctx.Send(mmsg.NewText("检测到该用户正在直播，但由于您目前处于私聊模式，" +
 					"因此不会在群内推送本次直播，将在该用户下次直播时推送"))
 			}
@@ -152,13 +154,13 @@ func (d *Concern) Add(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctype c
 	return info, nil
 }
 
-func (d *Concern) GroupWatchNotify(groupCode int64, mid string) {
+func (d *Concern) GroupWatchNotify(target mmsg.Target, mid string) {
 	userInfo, _ := d.GetUserInfo(mid)
 	if userInfo.WebRoomId != "" {
 		var liveInfo *LiveInfo
 		liveInfo.IsLiving = true
 		liveInfo.UserInfo = *userInfo
-		d.notify <- NewConcernLiveNotify(groupCode, liveInfo)
+		d.notify <- NewConcernLiveNotify(target, liveInfo)
 	}
 }
 
@@ -172,16 +174,21 @@ func (d *Concern) removeCurrentLive(id string) error {
 	return err
 }
 
+func (d *Concern) removeNewsTime(id string) error {
+	_, err := d.Delete(d.NewsTimeKey(id), buntdb.IgnoreNotFoundOpt())
+	return err
+}
+
 func (d *Concern) removeFresh(id string) error {
 	_, err := d.Delete(d.FreshKey(id), buntdb.IgnoreNotFoundOpt())
 	return err
 }
 
-func (d *Concern) Remove(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+func (d *Concern) Remove(ctx mmsg.IMsgCtx, target mmsg.Target, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	// 大部分时候简单的删除即可
 	// 如果还有更复杂的逻辑可以自由实现
 	identity, _ := d.Get(id)
-	_, err := d.GetStateManager().RemoveGroupConcern(groupCode, id.(string), ctype)
+	_, err := d.GetStateManager().RemoveGroupConcern(target, id.(string), ctype)
 	if err != nil {
 		return nil, err
 	}
@@ -210,6 +217,12 @@ func (d *Concern) Remove(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctyp
 		}
 	}
 
+	if ctype.ContainAny(News) {
+		if err = d.removeNewsTime(id.(string)); err != nil {
+			logger.WithError(err).Errorf("remove NewsTime error")
+		}
+	}
+
 	if identity == nil {
 		identity = concern.NewIdentity(id, "unknown")
 	}
@@ -227,16 +240,21 @@ func (d *Concern) Get(id interface{}) (concern.IdentityInfo, error) {
 }
 
 func (d *Concern) notifyGenerator() concern.NotifyGeneratorFunc {
-	return func(groupCode int64, ievent concern.Event) (result []concern.Notify) {
+	return func(target mmsg.Target, ievent concern.Event) (result []concern.Notify) {
 		log := ievent.Logger()
 		switch event := ievent.(type) {
+		case *NewsInfo:
+			for _, notify := range NewConcernNewsNotify(target, event) {
+				result = append(result, notify)
+			}
+			log.WithFields(mmsg.TargetLogFields(target)).Trace("news notify")
 		case *LiveInfo:
-			notify := NewConcernLiveNotify(groupCode, event)
+			notify := NewConcernLiveNotify(target, event)
 			result = append(result, notify)
 			if event.Living() {
-				log.WithFields(localutils.GroupLogFields(groupCode)).Trace("living notify")
+				log.WithFields(mmsg.TargetLogFields(target)).Trace("living notify")
 			} else {
-				log.WithFields(localutils.GroupLogFields(groupCode)).Trace("noliving notify")
+				log.WithFields(mmsg.TargetLogFields(target)).Trace("noliving notify")
 			}
 		default:
 			logger.Errorf("unknown EventType %+v", event.Type().String())
@@ -271,14 +289,33 @@ func (d *Concern) fresh() concern.FreshFunc {
 			var start = time.Now()
 			err := func() error {
 				defer func() { logger.WithField("cost", time.Now().Sub(start)).Tracef("watchCore live fresh done") }()
-				_, ids, _, _ := d.StateManager.ListConcernState(func(g int64, id interface{}, p concern_type.Type) bool { return p.ContainAll(Live) })
-				for _, userId := range ids {
-					events, err := d.freshLiveInfo(Live, userId)
-					if err != nil {
-						continue
+				_, ids, types, err := d.StateManager.ListConcernState(func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
+					return p.ContainAny(Live.Add(News))
+				})
+				if err != nil {
+					return err
+				}
+				ids, types, err = d.StateManager.GroupTypeById(ids, types)
+				if err != nil {
+					return err
+				}
+				for index, userId := range ids {
+					if types[index].ContainAny(Live) {
+						events, err := d.freshLiveInfo(Live, userId)
+						if err == nil {
+							for _, e := range events {
+								eventChan <- e
+							}
+						}
 					}
-					for _, e := range events {
-						eventChan <- e
+					if types[index].ContainAny(News) {
+						events, err := d.freshNews(userId.(string))
+						if err != nil {
+							logger.WithField("userId", userId).Errorf("刷新作品失败：%v", err)
+						}
+						for _, e := range events {
+							eventChan <- e
+						}
 					}
 					time.Sleep(time.Duration(rand.Intn(10)) * time.Second)
 				}
@@ -353,6 +390,61 @@ func (d *Concern) freshLiveInfo(ctype concern_type.Type, id interface{}) ([]conc
 	return result, nil
 }
 
+// freshNews 检查用户新发布的作品，第一次刷新时只记录时间，不推送已有的作品
+func (d *Concern) freshNews(userId string) ([]concern.Event, error) {
+	usrInfo, err := d.FindOrLoadUserInfo(userId)
+	if err != nil {
+		return nil, err
+	}
+	awemes, err := GetUserPost(userId)
+	if err != nil {
+		return nil, err
+	}
+	lastTs, err := d.GetNewsTime(userId)
+	if err != nil {
+		if err.Error() != ErrNotFound {
+			return nil, err
+		}
+		return nil, d.SetNewsTime(userId, time.Now().Unix())
+	}
+	newAwemes, latestTs := concern.FilterNews(awemes, lastTs, func(aweme *Aweme) (int64, bool) {
+		return aweme.CreateTime, aweme.AwemeId != ""
+	}, func(aweme *Aweme) bool {
+		replaced, err := d.MarkAweme(aweme.AwemeId)
+		if err != nil {
+			logger.WithField("awemeId", aweme.AwemeId).Errorf("MarkAweme error %v", err)
+			return false
+		}
+		return !replaced
+	})
+	// 置顶的作品排在前面，按发布时间从旧到新推送
+	sort.SliceStable(newAwemes, func(i, j int) bool {
+		return newAwemes[i].CreateTime < newAwemes[j].CreateTime
+	})
+	if latestTs != lastTs {
+		if err = d.SetNewsTime(userId, latestTs); err != nil {
+			logger.Errorf("内部错误 - 作品时间更新失败：%v", err)
+			return nil, err
+		}
+	}
+	if len(newAwemes) == 0 {
+		return nil, nil
+	}
+	return []concern.Event{&NewsInfo{UserInfo: *usrInfo, Awemes: newAwemes}}, nil
+}
+
+func (d *Concern) SetNewsTime(id string, ts int64) error {
+	return d.SetInt64(d.NewsTimeKey(id), ts)
+}
+func (d *Concern) GetNewsTime(id string) (int64, error) {
+	return d.GetInt64(d.NewsTimeKey(id))
+}
+func (d *Concern) MarkAweme(awemeId string) (replaced bool, err error) {
+	err = d.Set(d.MarkAwemeKey(awemeId), "",
+		buntdb.SetExpireOpt(time.Hour*120), buntdb.SetGetIsOverwriteOpt(&replaced))
+	return
+}
+
 func (d *Concern) SetFreshTime(id string, ts time.Time) error {
 	return d.SetInt64(d.FreshKey(id), ts.Unix())
 }
@@ -16,3 +16,9 @@ func (e *extraKey) CurrentLiveKey(keys ...interface{}) string {
 func (e *extraKey) FreshKey(keys ...interface{}) string {
 	return buntdb.DouyinFreshKey(keys...)
 }
+func (e *extraKey) NewsTimeKey(keys ...interface{}) string {
+	return buntdb.DouyinNewsTimeKey(keys...)
+}
+func (e *extraKey) MarkAwemeKey(keys ...interface{}) string {
+	return buntdb.DouyinMarkAwemeKey(keys...)
+}
@@ -0,0 +1,113 @@
+package douyin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Sora233/MiraiGo-Template/utils"
+	"github.com/cnxysoft/DDBOT-WSa/requests"
+)
+
+const (
+	PathGetUserPost = "/aweme/v1/web/aweme/post/"
+	// AwemeTypeNote 图文作品
+	AwemeTypeNote = 68
+)
+
+type Aweme struct {
+	AwemeId    string `json:"aweme_id"`
+	Desc       string `json:"desc"`
+	CreateTime int64  `json:"create_time"`
+	AwemeType  int    `json:"aweme_type"`
+	// IsTop 置顶作品，可能是很久以前发布的
+	IsTop int `json:"is_top"`
+	Video struct {
+		Cover struct {
+			UrlList []string `json:"url_list"`
+		} `json:"cover"`
+	} `json:"video"`
+	Images []struct {
+		UrlList []string `json:"url_list"`
+	} `json:"images"`
+	ShareInfo struct {
+		ShareUrl string `json:"share_url"`
+	} `json:"share_info"`
+}
+
+func (a *Aweme) IsNote() bool {
+	return a.AwemeType == AwemeTypeNote || len(a.Images) > 0
+}
+
+// CoverUrl 视频返回封面，图文返回第一张图片
+func (a *Aweme) CoverUrl() string {
+	if len(a.Video.Cover.UrlList) > 0 {
+		return a.Video.Cover.UrlList[0]
+	}
+	if len(a.Images) > 0 && len(a.Images[0].UrlList) > 0 {
+		return a.Images[0].UrlList[0]
+	}
+	return ""
+}
+
+// ShareUrl 优先使用接口返回的分享链接
+func (a *Aweme) ShareUrl() string {
+	if a.ShareInfo.ShareUrl != "" {
+		return a.ShareInfo.ShareUrl
+	}
+	if a.IsNote() {
+		return BaseHost + "/note/" + a.AwemeId
+	}
+	return BaseHost + "/video/" + a.AwemeId
+}
+
+type UserPostResp struct {
+	StatusCode int      `json:"status_code"`
+	StatusMsg  string   `json:"status_msg"`
+	AwemeList  []*Aweme `json:"aweme_list"`
+	HasMore    int      `json:"has_more"`
+	MaxCursor  int64    `json:"max_cursor"`
+}
+
+// GetUserPost 获取用户主页的作品列表，secUid 即订阅时使用的id
+func GetUserPost(secUid string) ([]*Aweme, error) {
+	Url := DPath(PathGetUserPost)
+	param := map[string]string{
+		"device_platform": "webapp",
+		"aid":             "6383",
+		"channel":         "channel_pc_web",
+		"sec_user_id":     secUid,
+		"max_cursor":      "0",
+		"count":           "18",
+	}
+	opts := SetRequestOptions()
+	opts = append(opts, requests.HeaderOption("Referer", BaseHost+PathGetUserInfo+secUid))
+	var resp bytes.Buffer
+	var respHeaders requests.RespHeader
+	if err := requests.GetWithHeader(Url, param, &resp, &respHeaders, opts...); err != nil {
+		logger.WithField("userId", secUid).Errorf("获取作品列表失败：%v", err)
+		return nil, err
+	}
+
+	body, err := utils.HtmlDecoder(respHeaders.ContentEncoding, resp)
+	if err != nil {
+		logger.WithField("userId", secUid).Errorf("解压缩HTML失败：%v", err)
+		return nil, err
+	}
+	return ParseUserPostResp(body)
+}
+
+func ParseUserPostResp(body []byte) ([]*Aweme, error) {
+	// 风控时会返回空内容
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, errors.New("作品列表为空，可能触发了风控")
+	}
+	var postResp UserPostResp
+	if err := json.Unmarshal(body, &postResp); err != nil {
+		return nil, err
+	}
+	if postResp.StatusCode != 0 {
+		return nil, fmt.Errorf("获取作品列表失败：%v", postResp.StatusMsg)
+	}
+	return postResp.AwemeList, nil
+}
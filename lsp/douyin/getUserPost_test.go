@@ -0,0 +1,74 @@
+package douyin
+
+import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const testUserPost = `{
+	"status_code": 0,
+	"aweme_list": [
+		{
+			"aweme_id": "100",
+			"desc": "置顶视频",
+			"create_time": 1600000000,
+			"aweme_type": 0,
+			"is_top": 1,
+			"video": {"cover": {"url_list": ["https://example.com/cover100.jpeg"]}}
+		},
+		{
+			"aweme_id": "300",
+			"desc": "新图文",
+			"create_time": 1700000300,
+			"aweme_type": 68,
+			"images": [{"url_list": ["https://example.com/image300.jpeg"]}]
+		},
+		{
+			"aweme_id": "200",
+			"desc": "新视频",
+			"create_time": 1700000200,
+			"aweme_type": 0,
+			"video": {"cover": {"url_list": ["https://example.com/cover200.jpeg"]}},
+			"share_info": {"share_url": "https://www.iesdouyin.com/share/video/200/"}
+		}
+	],
+	"has_more": 1,
+	"max_cursor": 1700000200000
+}`
+
+func TestParseUserPostResp(t *testing.T) {
+	awemes, err := ParseUserPostResp([]byte(testUserPost))
+	require.Nil(t, err)
+	require.Len(t, awemes, 3)
+
+	assert.False(t, awemes[0].IsNote())
+	assert.Equal(t, "https://example.com/cover100.jpeg", awemes[0].CoverUrl())
+	assert.Equal(t, BaseHost+"/video/100", awemes[0].ShareUrl())
+
+	assert.True(t, awemes[1].IsNote())
+	assert.Equal(t, "https://example.com/image300.jpeg", awemes[1].CoverUrl())
+	assert.Equal(t, BaseHost+"/note/300", awemes[1].ShareUrl())
+
+	assert.Equal(t, "https://www.iesdouyin.com/share/video/200/", awemes[2].ShareUrl())
+
+	_, err = ParseUserPostResp([]byte(""))
+	assert.NotNil(t, err)
+	_, err = ParseUserPostResp([]byte(`{"status_code": 8, "status_msg": "error"}`))
+	assert.NotNil(t, err)
+}
+
+func TestNewConcernNewsNotify(t *testing.T) {
+	awemes, err := ParseUserPostResp([]byte(testUserPost))
+	require.Nil(t, err)
+
+	notifies := NewConcernNewsNotify(mmsg.NewGroupTarget(1), &NewsInfo{
+		UserInfo: UserInfo{SecUid: "sec", NikeName: "name"},
+		Awemes:   awemes[:1],
+	})
+	require.Len(t, notifies, 1)
+	assert.Equal(t, mmsg.NewGroupTarget(1), notifies[0].GetTarget())
+	assert.Equal(t, News, notifies[0].Type())
+	assert.NotNil(t, notifies[0].Logger())
+}
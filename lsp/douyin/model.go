@@ -4,9 +4,9 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
-	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/sirupsen/logrus"
 	"sync"
+	"time"
 )
 
 type UserInfo struct {
@@ -50,8 +50,7 @@ type LiveInfo struct {
 	UserInfo
 	IsLiving bool `json:"living"`
 
-	once              sync.Once
-	msgCache          *mmsg.MSG
+	msgCache          template.MsgCache
 	liveTitleChanged  bool
 	liveStatusChanged bool
 }
@@ -72,6 +71,19 @@ func (l *LiveInfo) LiveStatusChanged() bool {
 	return l.liveStatusChanged
 }
 
+func (l *LiveInfo) AnchorName() string {
+	return l.NikeName
+}
+
+func (l *LiveInfo) RoomTitle() string {
+	return ""
+}
+
+// Popularity 抖音目前没有获取人气，总是返回0
+func (l *LiveInfo) Popularity() int64 {
+	return 0
+}
+
 func (l *LiveInfo) Site() string {
 	return Site
 }
@@ -90,8 +102,8 @@ func (l *LiveInfo) Logger() *logrus.Entry {
 	})
 }
 
-func (l *LiveInfo) GetMSG() *mmsg.MSG {
-	l.once.Do(func() {
+func (l *LiveInfo) GetMSG(locale string) *mmsg.MSG {
+	return l.msgCache.Get(locale, func() *mmsg.MSG {
 		var data = map[string]interface{}{
 			"uid":    l.Uid,
 			"name":   l.NikeName,
@@ -99,39 +111,127 @@ func (l *LiveInfo) GetMSG() *mmsg.MSG {
 			"living": l.Living(),
 			"url":    BaseLiveHost + "/" + l.WebRoomId,
 		}
-		var err error
-		l.msgCache, err = template.LoadAndExec("notify.group.douyin.live.tmpl", data)
+		msg, err := template.ExecNotify(locale, "notify.group.douyin.live.tmpl", data)
 		if err != nil {
 			logger.Errorf("acfun: LiveInfo LoadAndExec error %v", err)
 		}
-		return
+		return msg
 	})
-	return l.msgCache
 }
 
 type ConcernLiveNotify struct {
-	GroupCode int64
+	Target mmsg.Target
 	*LiveInfo
 }
 
-func (notify *ConcernLiveNotify) GetGroupCode() int64 {
-	return notify.GroupCode
+func (notify *ConcernLiveNotify) GetTarget() mmsg.Target {
+	return notify.Target
 }
 
 func (notify *ConcernLiveNotify) ToMessage() (m *mmsg.MSG) {
-	return notify.LiveInfo.GetMSG()
+	return notify.LiveInfo.GetMSG(template.NotifyLocale(notify.Target))
 }
 
 func (notify *ConcernLiveNotify) Logger() *logrus.Entry {
 	if notify == nil {
 		return logger
 	}
-	return notify.LiveInfo.Logger().WithFields(localutils.GroupLogFields(notify.GroupCode))
+	return notify.LiveInfo.Logger().WithFields(mmsg.TargetLogFields(notify.Target))
 }
 
-func NewConcernLiveNotify(groupCode int64, info *LiveInfo) *ConcernLiveNotify {
+func NewConcernLiveNotify(target mmsg.Target, info *LiveInfo) *ConcernLiveNotify {
 	return &ConcernLiveNotify{
-		GroupCode: groupCode,
-		LiveInfo:  info,
+		Target:   target,
+		LiveInfo: info,
+	}
+}
+
+type NewsInfo struct {
+	UserInfo
+	Awemes []*Aweme `json:"-"`
+}
+
+func (n *NewsInfo) Site() string {
+	return Site
+}
+
+func (n *NewsInfo) Type() concern_type.Type {
+	return News
+}
+
+func (n *NewsInfo) Logger() *logrus.Entry {
+	return logger.WithFields(logrus.Fields{
+		"Site":      Site,
+		"Uid":       n.Uid,
+		"Name":      n.NikeName,
+		"Type":      n.Type().String(),
+		"AwemeSize": len(n.Awemes),
+	})
+}
+
+type ConcernNewsNotify struct {
+	Target mmsg.Target
+	UserInfo
+	Aweme *Aweme
+
+	once     sync.Once
+	msgCache *mmsg.MSG
+}
+
+func (notify *ConcernNewsNotify) Site() string {
+	return Site
+}
+
+func (notify *ConcernNewsNotify) Type() concern_type.Type {
+	return News
+}
+
+func (notify *ConcernNewsNotify) GetTarget() mmsg.Target {
+	return notify.Target
+}
+
+func (notify *ConcernNewsNotify) ToMessage() (m *mmsg.MSG) {
+	notify.once.Do(func() {
+		var data = map[string]interface{}{
+			"uid":     notify.Uid,
+			"name":    notify.NikeName,
+			"id":      notify.Aweme.AwemeId,
+			"desc":    notify.Aweme.Desc,
+			"cover":   notify.Aweme.CoverUrl(),
+			"is_note": notify.Aweme.IsNote(),
+			"date":    time.Unix(notify.Aweme.CreateTime, 0).Format("2006-01-02 15:04:05"),
+			"url":     notify.Aweme.ShareUrl(),
+		}
+		var err error
+		notify.msgCache, err = template.ExecNotify(template.NotifyLocale(notify.Target), "notify.group.douyin.news.tmpl", data)
+		if err != nil {
+			logger.Errorf("douyin: ConcernNewsNotify LoadAndExec error %v", err)
+		}
+	})
+	return notify.msgCache
+}
+
+func (notify *ConcernNewsNotify) Logger() *logrus.Entry {
+	if notify == nil {
+		return logger
+	}
+	return logger.WithFields(logrus.Fields{
+		"Site":    Site,
+		"Uid":     notify.Uid,
+		"Name":    notify.NikeName,
+		"Type":    notify.Type().String(),
+		"AwemeId": notify.Aweme.AwemeId,
+	}).WithFields(mmsg.TargetLogFields(notify.Target))
+}
+
+func NewConcernNewsNotify(target mmsg.Target, info *NewsInfo) []*ConcernNewsNotify {
+	var result []*ConcernNewsNotify
+	for _, aweme := range info.Awemes {
+		result = append(result, &ConcernNewsNotify{
+			Target:   target,
+			UserInfo: info.UserInfo,
+			Aweme:    aweme,
+		})
 	}
+	return result
 }
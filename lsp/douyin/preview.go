@@ -0,0 +1,59 @@
+package douyin
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+)
+
+// Preview 作品使用作品列表接口获取最新的作品，直播使用当前的直播状态，并当作刚刚发生变化处理
+func (d *Concern) Preview(target mmsg.Target, id interface{}, ctype concern_type.Type, limit int) ([]concern.Notify, error) {
+	userId := id.(string)
+	// 不能使用 FindOrLoadUserInfo，预览没有订阅的用户时不应该保存用户信息
+	usrInfo, err := d.GetUserInfo(userId)
+	if err != nil {
+		usrInfo, err = GetUserInfo(userId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var result []concern.Notify
+	switch ctype {
+	case News:
+		awemes, err := GetUserPost(userId)
+		if err != nil {
+			return nil, err
+		}
+		// 置顶的作品排在前面，与推送相同按发布时间从旧到新排列，只取最新的部分
+		sort.SliceStable(awemes, func(i, j int) bool {
+			return awemes[i].CreateTime < awemes[j].CreateTime
+		})
+		if len(awemes) > limit {
+			awemes = awemes[len(awemes)-limit:]
+		}
+		for _, notify := range NewConcernNewsNotify(target, &NewsInfo{UserInfo: *usrInfo, Awemes: awemes}) {
+			result = append(result, notify)
+		}
+	case Live:
+		isLive, err := FreshLiveStatus(usrInfo.Uid)
+		if err != nil {
+			return nil, err
+		}
+		if isLive && usrInfo.GetRoomId() == "" {
+			if newUserInfo, err := GetUserInfo(userId); err == nil && newUserInfo.GetRoomId() != "" {
+				usrInfo = newUserInfo
+			}
+		}
+		result = append(result, NewConcernLiveNotify(target, &LiveInfo{
+			UserInfo:          *usrInfo,
+			IsLiving:          isLive,
+			liveStatusChanged: true,
+		}))
+	default:
+		return nil, errors.New("该类型不支持预览")
+	}
+	return result, nil
+}
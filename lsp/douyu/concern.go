@@ -6,7 +6,6 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
-	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/sirupsen/logrus"
 	"github.com/tidwall/buntdb"
 )
@@ -52,12 +51,12 @@ func (c *Concern) Start() error {
 	return c.StateManager.Start()
 }
 
-func (c *Concern) Add(ctx mmsg.IMsgCtx, groupCode int64, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+func (c *Concern) Add(ctx mmsg.IMsgCtx, target mmsg.Target, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	id := _id.(int64)
 	var err error
-	log := logger.WithFields(localutils.GroupLogFields(groupCode)).WithField("id", id)
+	log := logger.WithFields(mmsg.TargetLogFields(target)).WithField("id", id)
 
-	err = c.StateManager.CheckGroupConcern(groupCode, id, ctype)
+	err = c.StateManager.CheckGroupConcern(target, id, ctype)
 	if err != nil {
 		return nil, err
 	}
@@ -76,17 +75,17 @@ func (c *Concern) Add(ctx mmsg.IMsgCtx, groupCode int64, _id interface{}, ctype
 		VideoLoop:  betardResp.GetRoom().GetVideoLoop(),
 		Avatar:     betardResp.GetRoom().GetAvatar(),
 	}
-	_, err = c.StateManager.AddGroupConcern(groupCode, id, ctype)
+	_, err = c.StateManager.AddGroupConcern(target, id, ctype)
 	if err != nil {
 		return nil, err
 	}
 	return liveInfo, nil
 }
 
-func (c *Concern) Remove(ctx mmsg.IMsgCtx, groupCode int64, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+func (c *Concern) Remove(ctx mmsg.IMsgCtx, target mmsg.Target, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	id := _id.(int64)
 	identity, _ := c.Get(id)
-	_, err := c.StateManager.RemoveGroupConcern(groupCode, id, ctype)
+	_, err := c.StateManager.RemoveGroupConcern(target, id, ctype)
 	_ = c.RWCoverTx(func(tx *buntdb.Tx) error {
 		allCtype, err := c.GetConcern(id)
 		if err != nil {
@@ -109,15 +108,15 @@ func (c *Concern) Get(id interface{}) (concern.IdentityInfo, error) {
 }
 
 func (c *Concern) notifyGenerator() concern.NotifyGeneratorFunc {
-	return func(groupCode int64, event concern.Event) []concern.Notify {
+	return func(target mmsg.Target, event concern.Event) []concern.Notify {
 		switch info := event.(type) {
 		case *LiveInfo:
 			if info.Living() {
-				info.Logger().WithFields(localutils.GroupLogFields(groupCode)).Trace("living notify")
+				info.Logger().WithFields(mmsg.TargetLogFields(target)).Trace("living notify")
 			} else {
-				info.Logger().WithFields(localutils.GroupLogFields(groupCode)).Trace("noliving notify")
+				info.Logger().WithFields(mmsg.TargetLogFields(target)).Trace("noliving notify")
 			}
-			return []concern.Notify{NewConcernLiveNotify(groupCode, info)}
+			return []concern.Notify{NewConcernLiveNotify(target, info)}
 		default:
 			logger.Errorf("unknown EventType %+v", event)
 			return nil
@@ -162,19 +161,11 @@ func (c *Concern) fresh() concern.FreshFunc {
 func (c *Concern) FindRoom(id int64, load bool) (*LiveInfo, error) {
 	var liveInfo *LiveInfo
 	if load {
-		betardResp, err := Betard(id)
+		var err error
+		liveInfo, err = loadRoom(id)
 		if err != nil {
 			return nil, err
 		}
-		liveInfo = &LiveInfo{
-			Nickname:   betardResp.GetRoom().GetNickname(),
-			RoomId:     betardResp.GetRoom().GetRoomId(),
-			RoomName:   betardResp.GetRoom().GetRoomName(),
-			RoomUrl:    betardResp.GetRoom().GetRoomUrl(),
-			ShowStatus: betardResp.GetRoom().GetShowStatus(),
-			VideoLoop:  betardResp.GetRoom().GetVideoLoop(),
-			Avatar:     betardResp.GetRoom().GetAvatar(),
-		}
 		_ = c.StateManager.AddLiveInfo(liveInfo)
 	}
 	if liveInfo != nil {
@@ -183,6 +174,23 @@ func (c *Concern) FindRoom(id int64, load bool) (*LiveInfo, error) {
 	return c.StateManager.GetLiveInfo(id)
 }
 
+// loadRoom 从接口获取直播间信息，不会保存
+func loadRoom(id int64) (*LiveInfo, error) {
+	betardResp, err := Betard(id)
+	if err != nil {
+		return nil, err
+	}
+	return &LiveInfo{
+		Nickname:   betardResp.GetRoom().GetNickname(),
+		RoomId:     betardResp.GetRoom().GetRoomId(),
+		RoomName:   betardResp.GetRoom().GetRoomName(),
+		RoomUrl:    betardResp.GetRoom().GetRoomUrl(),
+		ShowStatus: betardResp.GetRoom().GetShowStatus(),
+		VideoLoop:  betardResp.GetRoom().GetVideoLoop(),
+		Avatar:     betardResp.GetRoom().GetAvatar(),
+	}, nil
+}
+
 func (c *Concern) FindOrLoadRoom(roomId int64) (*LiveInfo, error) {
 	info, _ := c.FindRoom(roomId, false)
 	if info == nil {
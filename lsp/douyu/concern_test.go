@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/cnxysoft/DDBOT-WSa/internal/test"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -46,7 +47,7 @@ func TestConcern(t *testing.T) {
 	defer c.Stop()
 	defer close(testEventChan)
 
-	_, err = c.Add(nil, test.G1, testRoom, Live)
+	_, err = c.Add(nil, mmsg.NewGroupTarget(test.G1), testRoom, Live)
 	assert.Nil(t, err)
 
 	liveInfo, err := c.FindOrLoadRoom(testRoom)
@@ -68,15 +69,15 @@ func TestConcern(t *testing.T) {
 
 	select {
 	case notify := <-testNotifyChan:
-		assert.Equal(t, test.G1, notify.GetGroupCode())
+		assert.Equal(t, mmsg.NewGroupTarget(test.G1), notify.GetTarget())
 	case <-time.After(time.Second):
 		assert.Fail(t, "no notify received")
 	}
 
-	identityInfo, err = c.Remove(nil, test.G1, testRoom, Live)
+	identityInfo, err = c.Remove(nil, mmsg.NewGroupTarget(test.G1), testRoom, Live)
 	assert.Nil(t, err)
 	assert.EqualValues(t, testRoom, identityInfo.GetUid())
 
-	identityInfo, err = c.Remove(nil, test.G1, testRoom, Live)
+	identityInfo, err = c.Remove(nil, mmsg.NewGroupTarget(test.G1), testRoom, Live)
 	assert.NotNil(t, err)
 }
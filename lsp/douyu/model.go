@@ -4,9 +4,7 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
-	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/sirupsen/logrus"
-	"sync"
 )
 
 type LiveInfo struct {
@@ -18,8 +16,7 @@ type LiveInfo struct {
 	VideoLoop  VideoLoopStatus `json:"videoLoop"`
 	Avatar     *Avatar         `json:"avatar"`
 
-	once              sync.Once
-	msgCache          *mmsg.MSG
+	msgCache          template.MsgCache
 	liveStatusChanged bool
 	liveTitleChanged  bool
 }
@@ -36,6 +33,19 @@ func (m *LiveInfo) LiveStatusChanged() bool {
 	return m.liveStatusChanged
 }
 
+func (m *LiveInfo) AnchorName() string {
+	return m.Nickname
+}
+
+func (m *LiveInfo) RoomTitle() string {
+	return m.RoomName
+}
+
+// Popularity 斗鱼目前没有获取人气，总是返回0
+func (m *LiveInfo) Popularity() int64 {
+	return 0
+}
+
 func (m *LiveInfo) IsLive() bool {
 	return true
 }
@@ -67,8 +77,8 @@ func (m *LiveInfo) Type() concern_type.Type {
 	return Live
 }
 
-func (m *LiveInfo) GetMSG() *mmsg.MSG {
-	m.once.Do(func() {
+func (m *LiveInfo) GetMSG(locale string) *mmsg.MSG {
+	return m.msgCache.Get(locale, func() *mmsg.MSG {
 		var data = map[string]interface{}{
 			"title":  m.RoomName,
 			"name":   m.Nickname,
@@ -76,14 +86,12 @@ func (m *LiveInfo) GetMSG() *mmsg.MSG {
 			"cover":  m.GetAvatar().GetBig(),
 			"living": m.Living(),
 		}
-		var err error
-		m.msgCache, err = template.LoadAndExec("notify.group.douyu.live.tmpl", data)
+		msg, err := template.ExecNotify(locale, "notify.group.douyu.live.tmpl", data)
 		if err != nil {
 			logger.Errorf("douyu: LiveInfo LoadAndExec error %v", err)
 		}
-		return
+		return msg
 	})
-	return m.msgCache
 }
 
 func (m *LiveInfo) GetNickname() string {
@@ -154,30 +162,30 @@ func (m *LiveInfo) Logger() *logrus.Entry {
 
 type ConcernLiveNotify struct {
 	*LiveInfo
-	GroupCode int64 `json:"group_code"`
+	Target mmsg.Target `json:"target"`
 }
 
-func (notify *ConcernLiveNotify) GetGroupCode() int64 {
-	return notify.GroupCode
+func (notify *ConcernLiveNotify) GetTarget() mmsg.Target {
+	return notify.Target
 }
 
 func (notify *ConcernLiveNotify) ToMessage() (m *mmsg.MSG) {
-	return notify.LiveInfo.GetMSG()
+	return notify.LiveInfo.GetMSG(template.NotifyLocale(notify.Target))
 }
 
 func (notify *ConcernLiveNotify) Logger() *logrus.Entry {
 	if notify == nil {
 		return logger
 	}
-	return notify.LiveInfo.Logger().WithFields(localutils.GroupLogFields(notify.GroupCode))
+	return notify.LiveInfo.Logger().WithFields(mmsg.TargetLogFields(notify.Target))
 }
 
-func NewConcernLiveNotify(groupCode int64, l *LiveInfo) *ConcernLiveNotify {
+func NewConcernLiveNotify(target mmsg.Target, l *LiveInfo) *ConcernLiveNotify {
 	if l == nil {
 		return nil
 	}
 	return &ConcernLiveNotify{
-		LiveInfo:  l,
-		GroupCode: groupCode,
+		LiveInfo: l,
+		Target:   target,
 	}
 }
@@ -2,6 +2,7 @@ package douyu
 
 import (
 	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -48,11 +49,11 @@ func TestLiveInfo(t *testing.T) {
 	assert.Equal(t, VideoLoopStatus_Off, l.GetVideoLoop())
 	assert.False(t, l.GetLiveStatusChanged())
 
-	notify := NewConcernLiveNotify(test.G1, l)
+	notify := NewConcernLiveNotify(mmsg.NewGroupTarget(test.G1), l)
 	assert.NotNil(t, notify)
 	assert.Equal(t, Live, notify.Type())
 	assert.NotNil(t, notify.Logger())
-	assert.Equal(t, test.G1, notify.GetGroupCode())
+	assert.Equal(t, mmsg.NewGroupTarget(test.G1), notify.GetTarget())
 	assert.Equal(t, test.UID1, notify.GetUid())
 	assert.EqualValues(t, Site, notify.Site())
 
@@ -4,6 +4,7 @@ import (
 	"errors"
 	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"time"
 )
 
@@ -33,8 +34,8 @@ func (c *StateManager) DeleteLiveInfo(id int64) error {
 	return err
 }
 
-func (c *StateManager) GetGroupConcernConfig(groupCode int64, id interface{}) (concernConfig concern.IConfig) {
-	return NewGroupConcernConfig(c.StateManager.GetGroupConcernConfig(groupCode, id))
+func (c *StateManager) GetGroupConcernConfig(target mmsg.Target, id interface{}) (concernConfig concern.IConfig) {
+	return NewGroupConcernConfig(c.StateManager.GetGroupConcernConfig(target, id))
 }
 
 func NewStateManager(notify chan<- concern.Notify) *StateManager {
@@ -2,6 +2,7 @@ package douyu
 
 import (
 	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -9,7 +10,7 @@ import (
 func initStateManager(t *testing.T) *StateManager {
 	sm := NewStateManager(nil)
 	assert.NotNil(t, sm)
-	sm.FreshIndex(test.G1, test.G2)
+	sm.FreshIndex(mmsg.NewGroupTarget(test.G1), mmsg.NewGroupTarget(test.G2))
 	return sm
 }
 func TestNewStateManager(t *testing.T) {
@@ -18,7 +19,7 @@ func TestNewStateManager(t *testing.T) {
 
 	sm := initStateManager(t)
 	assert.NotNil(t, sm)
-	assert.NotNil(t, sm.GetGroupConcernConfig(test.G1, test.UID1))
+	assert.NotNil(t, sm.GetGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.UID1))
 }
 
 func TestStateManager_GetLiveInfo(t *testing.T) {
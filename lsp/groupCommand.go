@@ -20,6 +20,7 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/image_pool/lolicon_pool"
 	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/permission"
 	"github.com/cnxysoft/DDBOT-WSa/utils"
@@ -37,6 +38,7 @@ func NewLspGroupCommand(l *Lsp, msg *message.GroupMessage) *LspGroupCommand {
 		Runtime: NewRuntime(l, l.PermissionStateManager.CheckGroupSilence(msg.GroupCode)),
 		msg:     msg,
 	}
+	c.locale = l.PermissionStateManager.GetGroupLocale(msg.GroupCode)
 	c.Parse(msg.Elements)
 	return c
 }
@@ -61,7 +63,7 @@ func (lgc *LspGroupCommand) Execute() {
 		if err := recover(); err != nil {
 			logger.WithField("stack", string(debug.Stack())).
 				Errorf("panic recovered: %v", err)
-			lgc.textReply("エラー発生：看到该信息表示BOT出了一些问题，该问题已记录")
+			lgc.templateReply("reply.panic", nil)
 		}
 	}()
 
@@ -143,6 +145,10 @@ func (lgc *LspGroupCommand) Execute() {
 		lgc.EnableCommand(true)
 	case SilenceCommand:
 		lgc.SilenceCommand()
+	case LocaleCommand:
+		lgc.LocaleCommand()
+	case CopyCommand:
+		lgc.CopyCommand()
 	case ReverseCommand:
 		if lgc.requireNotDisable(ReverseCommand) {
 			lgc.ReverseCommand()
@@ -160,6 +166,14 @@ func (lgc *LspGroupCommand) Execute() {
 				lgc.CleanConcernCommand()
 			}
 		}
+	case SearchCommand:
+		if lgc.requireNotDisable(SearchCommand) {
+			lgc.SearchCommand()
+		}
+	case PreviewCommand:
+		if lgc.requireNotDisable(PreviewCommand) {
+			lgc.PreviewCommand()
+		}
 	default:
 		if CheckCustomGroupCommand(lgc.CommandName()) {
 			if lgc.requireNotDisable(lgc.CommandName()) {
@@ -194,7 +208,7 @@ func (lgc *LspGroupCommand) LspCommand() {
 	var lspCmd struct{}
 	_, output := lgc.parseCommandSyntax(&lspCmd, lgc.CommandName())
 	if output != "" {
-		lgc.textReply(output)
+		lgc.usageReply(output)
 	}
 	if lgc.exit {
 		return
@@ -219,7 +233,7 @@ func (lgc *LspGroupCommand) SetuCommand(r18 bool) {
 
 	_, output := lgc.parseCommandSyntax(&setuCmd, lgc.CommandName())
 	if output != "" {
-		lgc.textReply(output)
+		lgc.usageReply(output)
 	}
 	if lgc.exit {
 		return
@@ -233,17 +247,22 @@ func (lgc *LspGroupCommand) SetuCommand(r18 bool) {
 
 	if !lgc.l.PermissionStateManager.RequireAny(permission.AdminRoleRequireOption(lgc.uin())) {
 		if num != 1 {
-			lgc.textReply("失败 - 数量限制为1")
+			lgc.templateReply("reply.setu.num_limit", map[string]interface{}{
+				"max": 1,
+			})
 			return
 		}
 		if setuCmd.Tag != "" {
-			lgc.textReply("失败 - tag搜索已禁用")
+			lgc.templateReply("reply.setu.tag_disabled", nil)
 			return
 		}
 	}
 
 	if num <= 0 || num > 10 {
-		lgc.textReply("失败 - 数量范围为1-10")
+		lgc.templateReply("reply.setu.num_range", map[string]interface{}{
+			"min": 1,
+			"max": 10,
+		})
 		return
 	}
 
@@ -260,18 +279,20 @@ func (lgc *LspGroupCommand) SetuCommand(r18 bool) {
 	imgs, err := lgc.l.GetImageFromPool(options...)
 	if err != nil {
 		if err == lolicon_pool.ErrNotFound {
-			lgc.textReply(err.Error())
+			lgc.templateReply("reply.error", map[string]interface{}{
+				"error": err,
+			})
 		} else if err == lolicon_pool.ErrQuotaExceed {
-			lgc.textReply("达到调用限制")
+			lgc.templateReply("reply.setu.quota_exceed", nil)
 		} else {
-			lgc.textReply("获取失败")
+			lgc.templateReply("reply.setu.failed", nil)
 		}
 		log.Errorf("get from image pool failed %v", err)
 		return
 	}
 	if len(imgs) == 0 {
 		log.Errorf("get empty image")
-		lgc.textReply("获取失败")
+		lgc.templateReply("reply.setu.failed", nil)
 		return
 	}
 	searchNum := len(imgs)
@@ -345,15 +366,20 @@ func (lgc *LspGroupCommand) SetuCommand(r18 bool) {
 						"Title":     loliconImage.Title,
 						"UploadUrl": groupImage.Url,
 					}).Debug("debug image")
-					msg.Textf("标题：%v\n", loliconImage.Title)
-					msg.Textf("作者：%v\n", loliconImage.Author)
-					msg.Textf("PID：%v P%v\n", loliconImage.Pid, loliconImage.P)
 					tagCount := len(loliconImage.Tags)
 					if tagCount >= 2 {
 						tagCount = 2
 					}
-					msg.Textf("TAG：%v\n", strings.Join(loliconImage.Tags[:tagCount], " "))
-					msg.Textf("R18：%v", loliconImage.R18)
+					if info := lgc.templateMsg("reply.setu.image_info", map[string]interface{}{
+						"title":  loliconImage.Title,
+						"author": loliconImage.Author,
+						"pid":    loliconImage.Pid,
+						"p":      loliconImage.P,
+						"tags":   loliconImage.Tags[:tagCount],
+						"r18":    loliconImage.R18,
+					}); info != nil {
+						msg.Append(info.Elements()...)
+					}
 				}
 			}
 			if len(msg.Elements()) == 0 {
@@ -369,7 +395,10 @@ func (lgc *LspGroupCommand) SetuCommand(r18 bool) {
 
 	log = log.WithField("search_num", searchNum).WithField("miss", missCount)
 	if searchNum != num || missCount.Load() != 0 {
-		lgc.textReplyF("本次共查询到%v张图片，有%v张图片被吞了哦", searchNum, missCount.Load())
+		lgc.templateReply("reply.setu.missing", map[string]interface{}{
+			"total": searchNum,
+			"miss":  missCount.Load(),
+		})
 	}
 
 	return
@@ -388,16 +417,18 @@ func (lgc *LspGroupCommand) WatchCommand(remove bool) {
 	defer func() { log.Infof("%v command end", lgc.CommandName()) }()
 
 	var watchCmd struct {
-		Site string `optional:"" short:"s" default:"bilibili" help:"网站参数"`
-		Type string `optional:"" short:"t" default:"" help:"类型参数"`
-		Id   string `arg:""`
+		Site     string   `optional:"" short:"s" default:"bilibili" help:"网站参数"`
+		Type     string   `optional:"" short:"t" default:"" help:"类型参数"`
+		Person   string   `optional:"" short:"p" help:"人物别名，把不同网站的订阅归为同一个人"`
+		FromList bool     `optional:"" name:"from-list" help:"从回复的文本文件读取id，或者把id作为账号的关注分组名称读取分组内的用户"`
+		Id       []string `arg:"" optional:"" help:"订阅的id，可以填多个，使用 网站:id 的格式可以同时订阅不同网站"`
 	}
 
 	_, output := lgc.parseCommandSyntax(&watchCmd, lgc.CommandName(), kong.Description(
-		fmt.Sprintf("当前支持的网站：%v", strings.Join(concern.ListSite(), "/"))),
+		i18n.Sprintf(lgc.locale, "当前支持的网站：%v", strings.Join(concern.ListSite(), "/"))),
 	)
 	if output != "" {
-		lgc.textReply(output)
+		lgc.usageReply(output)
 	}
 	if lgc.exit {
 		return
@@ -407,14 +438,40 @@ func (lgc *LspGroupCommand) WatchCommand(remove bool) {
 	if err != nil {
 		log = log.WithField("args", lgc.GetArgs())
 		log.Errorf("ParseRawSiteAndType failed %v", err)
-		lgc.textReply(fmt.Sprintf("参数错误 - %v", err))
+		lgc.templateReply("reply.param_error", map[string]interface{}{
+			"error": err,
+		})
 		return
 	}
 	log = log.WithField("site", site).WithField("type", watchType)
 
-	id := watchCmd.Id
+	ids := watchCmd.Id
+	if watchCmd.FromList {
+		ids, err = readWatchList(lgc.l, lgc.msg.Elements, groupCode, site, watchCmd.Id)
+		if err != nil {
+			log.Errorf("readWatchList error %v", err)
+			lgc.templateReply(errorTemplate(err))
+			return
+		}
+	}
 
-	IWatch(lgc.NewMessageContext(log), groupCode, id, site, watchType, remove)
+	if len(watchCmd.Person) > 0 {
+		log = log.WithField("person", watchCmd.Person)
+		if remove && len(ids) == 0 {
+			IUnwatchPerson(lgc.NewMessageContext(log), groupCode, watchCmd.Person)
+			return
+		}
+	}
+	if len(ids) == 0 {
+		lgc.templateReply("reply.missing_id", nil)
+		return
+	}
+	items := parseWatchItems(ids, site, watchType, watchCmd.Type)
+	if len(watchCmd.Person) > 0 && !remove {
+		IWatchPersonItems(lgc.NewMessageContext(log), groupCode, watchCmd.Person, items)
+		return
+	}
+	IWatchItems(lgc.NewMessageContext(log), mmsg.NewGroupTarget(groupCode), items, remove)
 }
 
 func (lgc *LspGroupCommand) ListCommand() {
@@ -425,15 +482,20 @@ func (lgc *LspGroupCommand) ListCommand() {
 	defer func() { log.Infof("%v command end", lgc.CommandName()) }()
 
 	var listCmd struct {
-		Site string `optional:"" short:"s" help:"网站参数"`
+		Site   string `optional:"" short:"s" help:"网站参数"`
+		Person bool   `optional:"" short:"p" help:"按人物列出订阅"`
 	}
 	_, output := lgc.parseCommandSyntax(&listCmd, lgc.CommandName())
 	if output != "" {
-		lgc.textReply(output)
+		lgc.usageReply(output)
 	}
 	if lgc.exit {
 		return
 	}
+	if listCmd.Person {
+		IListPerson(lgc.NewMessageContext(log), groupCode)
+		return
+	}
 	param := map[string]interface{}{
 		"message_context": lgc.NewMessageContext(log),
 		"group_code":      groupCode,
@@ -455,7 +517,7 @@ func (lgc *LspGroupCommand) RollCommand() {
 	}
 	_, output := lgc.parseCommandSyntax(&rollCmd, lgc.CommandName())
 	if output != "" {
-		lgc.textReply(output)
+		lgc.usageReply(output)
 	}
 	if lgc.exit {
 		return
@@ -476,38 +538,56 @@ func (lgc *LspGroupCommand) RollCommand() {
 			if strings.Contains(rollarg, "-") {
 				rolls := strings.Split(rollarg, "-")
 				if len(rolls) != 2 {
-					lgc.textReply(fmt.Sprintf("参数解析错误 - %v", rollarg))
+					lgc.templateReply("reply.roll.param_error", map[string]interface{}{
+						"arg": rollarg,
+					})
 					return
 				}
 				min, err = strconv.ParseInt(rolls[0], 10, 64)
 				if err != nil {
-					lgc.textReply(fmt.Sprintf("参数解析错误 - %v", rollarg))
+					lgc.templateReply("reply.roll.param_error", map[string]interface{}{
+						"arg": rollarg,
+					})
 					return
 				}
 				max, err = strconv.ParseInt(rolls[1], 10, 64)
 				if err != nil {
-					lgc.textReply(fmt.Sprintf("参数解析错误 - %v", rollarg))
+					lgc.templateReply("reply.roll.param_error", map[string]interface{}{
+						"arg": rollarg,
+					})
 					return
 				}
 			} else {
 				max, err = strconv.ParseInt(rollarg, 10, 64)
 				if err != nil {
-					lgc.textReply(rollarg)
+					lgc.templateReply("reply.roll.choice", map[string]interface{}{
+						"result":  rollarg,
+						"options": rollCmd.RangeArg,
+					})
 					return
 				}
 			}
 		}
 		if min > max {
-			lgc.textReply(fmt.Sprintf("参数解析错误 - %v", rollarg))
+			lgc.templateReply("reply.roll.param_error", map[string]interface{}{
+				"arg": rollarg,
+			})
 			return
 		}
 		result := rand.Int63n(max-min+1) + min
 		log = log.WithField("roll", result)
-		lgc.textReply(strconv.FormatInt(result, 10))
+		lgc.templateReply("reply.roll.result", map[string]interface{}{
+			"result": result,
+			"min":    min,
+			"max":    max,
+		})
 	} else {
 		result := rollCmd.RangeArg[rand.Intn(len(rollCmd.RangeArg))]
 		log = log.WithField("choice", result)
-		lgc.textReply(result)
+		lgc.templateReply("reply.roll.choice", map[string]interface{}{
+			"result":  result,
+			"options": rollCmd.RangeArg,
+		})
 	}
 }
 
@@ -519,7 +599,7 @@ func (lgc *LspGroupCommand) CheckinCommand() {
 	var checkinCmd struct{}
 	_, output := lgc.parseCommandSyntax(&checkinCmd, lgc.CommandName())
 	if output != "" {
-		lgc.textReply(output)
+		lgc.usageReply(output)
 	}
 	if lgc.exit {
 		return
@@ -558,7 +638,7 @@ func (lgc *LspGroupCommand) CheckinCommand() {
 		return nil
 	})
 	if err != nil {
-		lgc.textSend("失败 - 内部错误")
+		lgc.templateSend("reply.internal_error", nil)
 		log.Errorf("checkin error %v", err)
 		return
 	}
@@ -576,7 +656,7 @@ func (lgc *LspGroupCommand) ScoreCommand() {
 	var scoreCmd struct{}
 	_, output := lgc.parseCommandSyntax(&scoreCmd, lgc.CommandName())
 	if output != "" {
-		lgc.textReply(output)
+		lgc.usageReply(output)
 	}
 	if lgc.exit {
 		return
@@ -591,9 +671,11 @@ func (lgc *LspGroupCommand) ScoreCommand() {
 		return err
 	})
 	if err != nil {
-		lgc.textSend("失败 - 内部错误")
+		lgc.templateSend("reply.internal_error", nil)
 	} else {
-		lgc.textReplyF("当前积分为%v", score)
+		lgc.templateReply("reply.score", map[string]interface{}{
+			"score": score,
+		})
 	}
 }
 
@@ -608,7 +690,7 @@ func (lgc *LspGroupCommand) EnableCommand(disable bool) {
 	}
 	_, output := lgc.parseCommandSyntax(&enableCmd, lgc.CommandName())
 	if output != "" {
-		lgc.textReply(output)
+		lgc.usageReply(output)
 	}
 	if lgc.exit {
 		return
@@ -632,7 +714,7 @@ func (lgc *LspGroupCommand) GrantCommand() {
 	}
 	_, output := lgc.parseCommandSyntax(&grantCmd, lgc.CommandName())
 	if output != "" {
-		lgc.textReply(output)
+		lgc.usageReply(output)
 	}
 	if lgc.exit {
 		return
@@ -642,7 +724,7 @@ func (lgc *LspGroupCommand) GrantCommand() {
 	grantTo := grantCmd.Target
 	if grantCmd.Command == "" && grantCmd.Role == "" {
 		log.Errorf("command and role both empty")
-		lgc.textReply("参数错误 - 必须指定-c / -r")
+		lgc.templateReply("reply.grant.missing_target", nil)
 		return
 	}
 	del := grantCmd.Delete
@@ -666,7 +748,7 @@ func (lgc *LspGroupCommand) SilenceCommand() {
 
 	_, output := lgc.parseCommandSyntax(&silenceCmd, lgc.CommandName(), kong.Description("设置沉默模式"), kong.UsageOnError())
 	if output != "" {
-		lgc.textReply(output)
+		lgc.usageReply(output)
 	}
 	if lgc.exit {
 		return
@@ -675,6 +757,46 @@ func (lgc *LspGroupCommand) SilenceCommand() {
 	ISilenceCmd(lgc.NewMessageContext(log), lgc.groupCode(), silenceCmd.Delete)
 }
 
+func (lgc *LspGroupCommand) LocaleCommand() {
+	log := lgc.DefaultLoggerWithCommand(lgc.CommandName())
+	log.Infof("run %v command", lgc.CommandName())
+	defer func() { log.Infof("%v command end", lgc.CommandName()) }()
+
+	var localeCmd struct {
+		Locale string `arg:"" optional:"" help:"要设置的语言，支持 zh-CN / en，不指定时查看当前语言"`
+	}
+
+	_, output := lgc.parseCommandSyntax(&localeCmd, lgc.CommandName(), kong.Description("设置本群使用的语言"), kong.UsageOnError())
+	if output != "" {
+		lgc.usageReply(output)
+	}
+	if lgc.exit {
+		return
+	}
+
+	ILocaleCmd(lgc.NewMessageContext(log), lgc.groupCode(), localeCmd.Locale)
+}
+
+func (lgc *LspGroupCommand) CopyCommand() {
+	log := lgc.DefaultLoggerWithCommand(lgc.CommandName())
+	log.Infof("run %v command", lgc.CommandName())
+	defer func() { log.Infof("%v command end", lgc.CommandName()) }()
+
+	var copyCmd struct {
+		From int64 `arg:"" help:"复制订阅的来源QQ群号码"`
+	}
+
+	_, output := lgc.parseCommandSyntax(&copyCmd, lgc.CommandName(), kong.Description("把其他群的所有订阅及配置复制到本群，需要两个群的管理员权限"), kong.UsageOnError())
+	if output != "" {
+		lgc.usageReply(output)
+	}
+	if lgc.exit {
+		return
+	}
+
+	ICopyConcern(lgc.NewMessageContext(log), copyCmd.From, lgc.groupCode())
+}
+
 func (lgc *LspGroupCommand) ConfigCommand() {
 	log := lgc.DefaultLoggerWithCommand(lgc.CommandName())
 	log.Infof("run %v command", lgc.CommandName())
@@ -723,13 +845,32 @@ func (lgc *LspGroupCommand) ConfigCommand() {
 				Id string `arg:"" help:"配置的主播id"`
 			} `cmd:"" help:"查看当前过滤器" name:"show" group:"filter"`
 		} `cmd:"" help:"配置动态过滤器" name:"filter"`
+		Media struct {
+			Site   string `optional:"" short:"s" default:"bilibili" help:"网站参数"`
+			Type   string `optional:"" short:"t" default:"news" help:"推送类型，youtube视频为news"`
+			Id     string `arg:"" help:"配置的主播id"`
+			Switch string `arg:"" default:"on" enum:"on,off" help:"on / off"`
+			Folder string `optional:"" short:"f" help:"存档使用的群文件夹名称，默认为推送存档"`
+			Video  bool   `optional:"" help:"同时存档视频和音频文件（如果有）"`
+			Link   bool   `optional:"" help:"同时存档视频链接"`
+		} `cmd:"" help:"配置推送时把封面等媒体存档到群文件，默认关闭" name:"media"`
+		Deleted struct {
+			Site   string `optional:"" short:"s" default:"bilibili" help:"网站参数"`
+			Id     string `arg:"" help:"配置的主播id"`
+			Action string `arg:"" default:"reply" enum:"recall,reply,off" help:"recall / reply / off"`
+		} `cmd:"" help:"配置动态被作者删除后撤回推送或者回复标注，默认关闭" name:"deleted"`
+		Alias struct {
+			Site  string `optional:"" short:"s" default:"bilibili" help:"网站参数"`
+			Id    string `arg:"" help:"配置的主播id"`
+			Alias string `arg:"" optional:"" help:"新的别名，不指定时删除别名"`
+		} `cmd:"" help:"配置订阅在群内的别名，其他命令可以使用别名代替id，默认为订阅时的名字" name:"alias"`
 	}
 
 	kongCtx, output := lgc.parseCommandSyntax(&configCmd, lgc.CommandName(),
-		kong.Description("管理BOT的配置，目前支持配置@成员、@全体成员、开启下播推送、开启标题推送、推送过滤"),
+		kong.Description("管理BOT的配置，目前支持配置@成员、@全体成员、开启下播推送、开启标题推送、推送过滤、媒体存档、删除检测、订阅别名"),
 	)
 	if output != "" {
-		lgc.textReply(output)
+		lgc.usageReply(output)
 	}
 	if lgc.exit || len(kongCtx.Path) <= 1 {
 		return
@@ -740,71 +881,117 @@ func (lgc *LspGroupCommand) ConfigCommand() {
 	cmd := kongPath[0]
 	log = log.WithField("sub_command", cmd)
 
+	target := mmsg.NewGroupTarget(lgc.groupCode())
 	switch cmd {
 	case "at":
 		site, ctype, err := lgc.ParseRawSiteAndType(configCmd.At.Site, "live")
 		if err != nil {
 			log.WithField("site", configCmd.At.Site).Errorf("ParseRawSiteAndType failed %v", err)
-			lgc.textSend(fmt.Sprintf("失败 - %v", err.Error()))
+			lgc.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return
 		}
 		log = log.WithField("site", site).WithField("id", configCmd.At.Id).WithField("action", configCmd.At.Action).WithField("QQ", configCmd.At.QQ)
-		IConfigAtCmd(lgc.NewMessageContext(log), lgc.groupCode(), configCmd.At.Id, site, ctype, configCmd.At.Action, configCmd.At.QQ)
+		IConfigAtCmd(lgc.NewMessageContext(log), target, configCmd.At.Id, site, ctype, configCmd.At.Action, configCmd.At.QQ)
 	case "at_all":
 		site, ctype, err := lgc.ParseRawSiteAndType(configCmd.AtAll.Site, "live")
 		if err != nil {
 			log.WithField("site", configCmd.AtAll.Site).Errorf("ParseRawSiteAndType failed %v", err)
-			lgc.textSend(fmt.Sprintf("失败 - %v", err.Error()))
+			lgc.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return
 		}
 		var on = utils.Switch2Bool(configCmd.AtAll.Switch)
 		log = log.WithField("site", site).WithField("id", configCmd.AtAll.Id).WithField("on", on)
-		IConfigAtAllCmd(lgc.NewMessageContext(log), lgc.groupCode(), configCmd.AtAll.Id, site, ctype, on)
+		IConfigAtAllCmd(lgc.NewMessageContext(log), target, configCmd.AtAll.Id, site, ctype, on)
 	case "title_notify":
 		site, ctype, err := lgc.ParseRawSiteAndType(configCmd.TitleNotify.Site, "live")
 		if err != nil {
 			log.WithField("site", configCmd.TitleNotify.Site).Errorf("ParseRawSiteAndType failed %v", err)
-			lgc.textSend(fmt.Sprintf("失败 - %v", err.Error()))
+			lgc.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return
 		}
 		var on = utils.Switch2Bool(configCmd.TitleNotify.Switch)
 		log = log.WithField("site", site).WithField("id", configCmd.TitleNotify.Id).WithField("on", on)
-		IConfigTitleNotifyCmd(lgc.NewMessageContext(log), lgc.groupCode(), configCmd.TitleNotify.Id, site, ctype, on)
+		IConfigTitleNotifyCmd(lgc.NewMessageContext(log), target, configCmd.TitleNotify.Id, site, ctype, on)
 	case "offline_notify":
 		site, ctype, err := lgc.ParseRawSiteAndType(configCmd.OfflineNotify.Site, "live")
 		if err != nil {
 			log.WithField("site", configCmd.OfflineNotify.Site).Errorf("ParseRawSiteAndType failed %v", err)
-			lgc.textSend(fmt.Sprintf("失败 - %v", err.Error()))
+			lgc.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return
 		}
 		var on = utils.Switch2Bool(configCmd.OfflineNotify.Switch)
 		log = log.WithField("site", site).WithField("id", configCmd.OfflineNotify.Id).WithField("on", on)
-		IConfigOfflineNotifyCmd(lgc.NewMessageContext(log), lgc.groupCode(), configCmd.OfflineNotify.Id, site, ctype, on)
+		IConfigOfflineNotifyCmd(lgc.NewMessageContext(log), target, configCmd.OfflineNotify.Id, site, ctype, on)
+	case "media":
+		site, ctype, err := lgc.ParseRawSiteAndType(configCmd.Media.Site, configCmd.Media.Type)
+		if err != nil {
+			log.WithField("site", configCmd.Media.Site).Errorf("ParseRawSiteAndType failed %v", err)
+			lgc.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
+			return
+		}
+		var on = utils.Switch2Bool(configCmd.Media.Switch)
+		log = log.WithField("site", site).WithField("id", configCmd.Media.Id).WithField("on", on)
+		IConfigMediaCmd(lgc.NewMessageContext(log), target, configCmd.Media.Id, site, ctype, on,
+			configCmd.Media.Folder, configCmd.Media.Video, configCmd.Media.Link)
+	case "deleted":
+		site, ctype, err := lgc.ParseRawSiteAndType(configCmd.Deleted.Site, "news")
+		if err != nil {
+			log.WithField("site", configCmd.Deleted.Site).Errorf("ParseRawSiteAndType failed %v", err)
+			lgc.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
+			return
+		}
+		log = log.WithField("site", site).WithField("id", configCmd.Deleted.Id).WithField("action", configCmd.Deleted.Action)
+		IConfigDeletedCmd(lgc.NewMessageContext(log), target, configCmd.Deleted.Id, site, ctype, configCmd.Deleted.Action)
+	case "alias":
+		site, err := lgc.ParseRawSite(configCmd.Alias.Site)
+		if err != nil {
+			log.WithField("site", configCmd.Alias.Site).Errorf("ParseRawSite failed %v", err)
+			lgc.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
+			return
+		}
+		log = log.WithField("site", site).WithField("id", configCmd.Alias.Id).WithField("alias", configCmd.Alias.Alias)
+		IConfigAliasCmd(lgc.NewMessageContext(log), target, configCmd.Alias.Id, site, configCmd.Alias.Alias)
 	case "filter":
 		filterCmd := kongPath[1]
 		site, ctype, err := lgc.ParseRawSiteAndType(configCmd.Filter.Site, "news")
 		if err != nil {
 			log.WithField("site", configCmd.Filter.Site).Errorf("ParseRawSiteAndType failed %v", err)
-			lgc.textSend(fmt.Sprintf("失败 - %v", err.Error()))
+			lgc.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return
 		}
 		switch filterCmd {
 		case "type":
-			IConfigFilterCmdType(lgc.NewMessageContext(log), lgc.groupCode(), configCmd.Filter.Type.Id, site, ctype, configCmd.Filter.Type.Type)
+			IConfigFilterCmdType(lgc.NewMessageContext(log), target, configCmd.Filter.Type.Id, site, ctype, configCmd.Filter.Type.Type)
 		case "not_type":
-			IConfigFilterCmdNotType(lgc.NewMessageContext(log), lgc.groupCode(), configCmd.Filter.NotType.Id, site, ctype, configCmd.Filter.NotType.Type)
+			IConfigFilterCmdNotType(lgc.NewMessageContext(log), target, configCmd.Filter.NotType.Id, site, ctype, configCmd.Filter.NotType.Type)
 		case "text":
-			IConfigFilterCmdText(lgc.NewMessageContext(log), lgc.groupCode(), configCmd.Filter.Text.Id, site, ctype, configCmd.Filter.Text.Keyword)
+			IConfigFilterCmdText(lgc.NewMessageContext(log), target, configCmd.Filter.Text.Id, site, ctype, configCmd.Filter.Text.Keyword)
 		case "clear":
-			IConfigFilterCmdClear(lgc.NewMessageContext(log), lgc.groupCode(), configCmd.Filter.Clear.Id, site, ctype)
+			IConfigFilterCmdClear(lgc.NewMessageContext(log), target, configCmd.Filter.Clear.Id, site, ctype)
 		case "show":
-			IConfigFilterCmdShow(lgc.NewMessageContext(log), lgc.groupCode(), configCmd.Filter.Show.Id, site, ctype)
+			IConfigFilterCmdShow(lgc.NewMessageContext(log), target, configCmd.Filter.Show.Id, site, ctype)
 		default:
 			log.WithField("filter_cmd", filterCmd).Errorf("unknown filter command")
-			lgc.textSend("未知的filter子命令")
+			lgc.templateSend("reply.config.unknown_filter", nil)
 		}
 	default:
-		lgc.textSend("暂未支持，你可以催作者GKD")
+		lgc.templateSend("reply.not_supported", nil)
 	}
 }
 
@@ -815,7 +1002,7 @@ func (lgc *LspGroupCommand) ReverseCommand() {
 
 	_, output := lgc.parseCommandSyntax(&struct{}{}, lgc.CommandName(), kong.Description("电脑使用/倒放 [图片] 或者 回复图片消息+/倒放触发"))
 	if output != "" {
-		lgc.textReply(output)
+		lgc.usageReply(output)
 	}
 	if lgc.exit {
 		return
@@ -829,7 +1016,7 @@ func (lgc *LspGroupCommand) ReverseCommand() {
 				return
 			default:
 				log.Errorf("cast to ImageElement failed")
-				lgc.textReply("失败")
+				lgc.templateReply("reply.failed", nil)
 				return
 			}
 		} else if e.Type() == message.Reply {
@@ -841,13 +1028,13 @@ func (lgc *LspGroupCommand) ReverseCommand() {
 				}
 			} else {
 				log.Errorf("cast to ReplyElement failed")
-				lgc.textReply("失败")
+				lgc.templateReply("reply.failed", nil)
 				return
 			}
 		}
 	}
 	log.Debug("no image found")
-	lgc.textReply("参数错误 - 未找到图片")
+	lgc.templateReply("reply.reverse.no_image", nil)
 }
 
 func (lgc *LspGroupCommand) HelpCommand() {
@@ -857,7 +1044,7 @@ func (lgc *LspGroupCommand) HelpCommand() {
 
 	_, output := lgc.parseCommandSyntax(&struct{}{}, lgc.CommandName(), kong.Description("print help message"))
 	if output != "" {
-		lgc.textReply(output)
+		lgc.usageReply(output)
 	}
 	if lgc.exit {
 		return
@@ -876,7 +1063,7 @@ func (lgc *LspGroupCommand) CleanConcernCommand() {
 	}
 	_, output := lgc.parseCommandSyntax(&cleanConcernCmd, lgc.CommandName(), kong.Description("print help message"))
 	if output != "" {
-		lgc.textReply(output)
+		lgc.usageReply(output)
 	}
 	if lgc.exit {
 		return
@@ -887,6 +1074,71 @@ func (lgc *LspGroupCommand) CleanConcernCommand() {
 
 }
 
+func (lgc *LspGroupCommand) SearchCommand() {
+	log := lgc.DefaultLoggerWithCommand(lgc.CommandName())
+	log.Infof("run %v command", lgc.CommandName())
+	defer func() { log.Infof("%v command end", lgc.CommandName()) }()
+
+	var searchCmd struct {
+		Keyword string `arg:"" optional:"" help:"消息中包含的关键字"`
+		Uin     int64  `optional:"" short:"u" help:"发送者的QQ号"`
+		Since   string `optional:"" help:"开始时间，例如 12h、21:00、2006-01-02 15:04"`
+		Until   string `optional:"" help:"结束时间，格式同--since"`
+		Limit   int    `optional:"" short:"n" default:"10" help:"最多显示的条数"`
+		Fetch   int    `optional:"" help:"搜索前先从协议端拉取最近的消息条数"`
+	}
+	_, output := lgc.parseCommandSyntax(&searchCmd, lgc.CommandName(), kong.Description("搜索本群的消息存档"))
+	if output != "" {
+		lgc.usageReply(output)
+	}
+	if lgc.exit {
+		return
+	}
+
+	query, err := newSearchQuery(searchCmd.Keyword, searchCmd.Uin, searchCmd.Since, searchCmd.Until, searchCmd.Limit)
+	if err != nil {
+		lgc.templateReply("reply.param_error", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	ISearch(lgc.NewMessageContext(log), lgc.groupCode(), query, searchCmd.Fetch)
+}
+
+func (lgc *LspGroupCommand) PreviewCommand() {
+	log := lgc.DefaultLoggerWithCommand(lgc.CommandName())
+	log.Infof("run %v command", lgc.CommandName())
+	defer func() { log.Infof("%v command end", lgc.CommandName()) }()
+
+	var previewCmd struct {
+		Site   string `optional:"" short:"s" default:"bilibili" help:"网站参数"`
+		Type   string `optional:"" short:"t" default:"" help:"类型参数"`
+		Limit  int    `optional:"" short:"n" default:"5" help:"预览最新的条数"`
+		Render bool   `optional:"" short:"r" help:"把推送的内容私聊发送给自己"`
+		Id     string `arg:""`
+	}
+	_, output := lgc.parseCommandSyntax(&previewCmd, lgc.CommandName(), kong.Description("预览订阅最新的内容在本群是否会推送"))
+	if output != "" {
+		lgc.usageReply(output)
+	}
+	if lgc.exit {
+		return
+	}
+
+	site, ctype, err := lgc.ParseRawSiteAndType(previewCmd.Site, previewCmd.Type)
+	if err != nil {
+		log = log.WithField("args", lgc.GetArgs())
+		log.Errorf("ParseRawSiteAndType failed %v", err)
+		lgc.templateReply("reply.param_error", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	log = log.WithField("site", site).WithField("type", ctype)
+	target := mmsg.NewGroupTarget(lgc.groupCode())
+	IPreview(lgc.NewMessageContext(log), target, previewCmd.Id, site, ctype, previewCmd.Limit, previewCmd.Render)
+}
+
 func (lgc *LspGroupCommand) DefaultLogger() *logrus.Entry {
 	return logger.WithField("Name", lgc.displayName()).
 		WithField("Uin", lgc.uin()).
@@ -903,13 +1155,15 @@ func (lgc *LspGroupCommand) reserveGif(url string) {
 	img, err := utils.ImageGet(url)
 	if err != nil {
 		log.Errorf("get image err %v", err)
-		lgc.textReply("获取图片失败")
+		lgc.templateReply("reply.reverse.get_image_failed", nil)
 		return
 	}
 	img, err = utils.ImageReserve(img)
 	if err != nil {
 		log.Errorf("reserve image err %v", err)
-		lgc.textReply(fmt.Sprintf("失败 - %v", err))
+		lgc.templateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
 		return
 	}
 	lgc.reply(mmsg.NewMSG().Image(img, ""))
@@ -991,6 +1245,32 @@ func (lgc *LspGroupCommand) textSendF(format string, args ...interface{}) *messa
 	return lgc.send(mmsg.NewTextf(format, args...))
 }
 
+// usageReply 回复命令的帮助或者参数错误信息
+func (lgc *LspGroupCommand) usageReply(output string) *message.GroupMessage {
+	return lgc.templateReply("reply.usage", map[string]interface{}{
+		"command": lgc.CommandName(),
+		"usage":   output,
+	})
+}
+
+// templateReply 使用模板 name 生成消息并回复，模板执行失败时返回nil
+func (lgc *LspGroupCommand) templateReply(name string, data map[string]interface{}) *message.GroupMessage {
+	m := lgc.templateMsg(name, data)
+	if m == nil {
+		return nil
+	}
+	return lgc.reply(m)
+}
+
+// templateSend 使用模板 name 生成消息并发送，模板执行失败时返回nil
+func (lgc *LspGroupCommand) templateSend(name string, data map[string]interface{}) *message.GroupMessage {
+	m := lgc.templateMsg(name, data)
+	if m == nil {
+		return nil
+	}
+	return lgc.send(m)
+}
+
 func (lgc *LspGroupCommand) reply(msg *mmsg.MSG) *message.GroupMessage {
 	m := mmsg.NewMSG()
 	m.Append(message.NewReply(lgc.msg))
@@ -1011,11 +1291,11 @@ func (lgc *LspGroupCommand) sender() *message.Sender {
 }
 
 func (lgc *LspGroupCommand) noPermissionReply() *message.GroupMessage {
-	return lgc.textReply("权限不够")
+	return lgc.templateReply("reply.no_permission", nil)
 }
 
 func (lgc *LspGroupCommand) globalDisabledReply() *message.GroupMessage {
-	return lgc.textReply("无法操作该命令，该命令已被管理员禁用")
+	return lgc.templateReply("reply.global_disabled", nil)
 }
 
 func (lgc *LspGroupCommand) commonTemplateData() map[string]interface{} {
@@ -1026,6 +1306,7 @@ func (lgc *LspGroupCommand) commonTemplateData() map[string]interface{} {
 		"member_code": lgc.sender().Uin,
 		"member_name": lgc.sender().DisplayName(),
 		"command":     CommandMaps,
+		"locale":      lgc.locale,
 	}
 }
 
@@ -1034,11 +1315,12 @@ func (lgc *LspGroupCommand) templateMsg(name string, data map[string]interface{}
 	for k, v := range data {
 		commonData[k] = v
 	}
+	localizeTemplateData(lgc.locale, commonData)
 	commonData["template_name"] = name
-	m, err := template.LoadAndExec(name, commonData)
+	m, err := template.LoadAndExec(template.LocaleName(lgc.locale, name), commonData)
 	if err != nil {
 		logger.Errorf("LoadAndExec error %v", err)
-		lgc.textReply(fmt.Sprintf("错误 - %v", err))
+		lgc.textReply(i18n.Sprintf(lgc.locale, "错误 - %v", err))
 		return nil
 	}
 	return m
@@ -1047,6 +1329,7 @@ func (lgc *LspGroupCommand) templateMsg(name string, data map[string]interface{}
 func (lgc *LspGroupCommand) NewMessageContext(log *logrus.Entry) *MessageContext {
 	ctx := NewMessageContext()
 	ctx.Target = mmsg.NewGroupTarget(lgc.groupCode())
+	ctx.Locale = lgc.locale
 	ctx.Lsp = lgc.l
 	ctx.Log = log
 	ctx.SendFunc = func(m *mmsg.MSG) interface{} {
@@ -1074,5 +1357,6 @@ func (lgc *LspGroupCommand) NewMessageContext(log *logrus.Entry) *MessageContext
 		return nil
 	}
 	ctx.Sender = lgc.sender()
+	ctx.TemplateMsgFunc = lgc.templateMsg
 	return ctx
 }
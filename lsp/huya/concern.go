@@ -6,7 +6,6 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
-	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/sirupsen/logrus"
 	"github.com/tidwall/buntdb"
@@ -54,11 +53,11 @@ func (c *Concern) Start() error {
 	return c.StateManager.Start()
 }
 
-func (c *Concern) Add(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+func (c *Concern) Add(ctx mmsg.IMsgCtx, target mmsg.Target, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	var err error
-	log := logger.WithFields(localutils.GroupLogFields(groupCode)).WithField("id", id)
+	log := logger.WithFields(mmsg.TargetLogFields(target)).WithField("id", id)
 
-	err = c.StateManager.CheckGroupConcern(groupCode, id, ctype)
+	err = c.StateManager.CheckGroupConcern(target, id, ctype)
 	if err != nil {
 		return nil, err
 	}
@@ -68,17 +67,17 @@ func (c *Concern) Add(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctype c
 		log.Errorf("RoomPage error %v", err)
 		return nil, fmt.Errorf("查询房间信息失败 %v - %v", id, err)
 	}
-	_, err = c.StateManager.AddGroupConcern(groupCode, id, ctype)
+	_, err = c.StateManager.AddGroupConcern(target, id, ctype)
 	if err != nil {
 		return nil, err
 	}
 	return liveInfo, nil
 }
 
-func (c *Concern) Remove(ctx mmsg.IMsgCtx, groupCode int64, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+func (c *Concern) Remove(ctx mmsg.IMsgCtx, target mmsg.Target, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	id := _id.(string)
 	identity, _ := c.Get(id)
-	_, err := c.StateManager.RemoveGroupConcern(groupCode, id, ctype)
+	_, err := c.StateManager.RemoveGroupConcern(target, id, ctype)
 	_ = c.RWCoverTx(func(tx *buntdb.Tx) error {
 		allCtype, err := c.GetConcern(id)
 		if err != nil {
@@ -125,15 +124,15 @@ func (c *Concern) FindOrLoadRoom(roomId string) (*LiveInfo, error) {
 }
 
 func (c *Concern) notifyGenerator() concern.NotifyGeneratorFunc {
-	return func(groupCode int64, event concern.Event) []concern.Notify {
+	return func(target mmsg.Target, event concern.Event) []concern.Notify {
 		switch info := event.(type) {
 		case *LiveInfo:
 			if info.Living() {
-				info.Logger().WithFields(localutils.GroupLogFields(groupCode)).Trace("living notify")
+				info.Logger().WithFields(mmsg.TargetLogFields(target)).Trace("living notify")
 			} else {
-				info.Logger().WithFields(localutils.GroupLogFields(groupCode)).Trace("noliving notify")
+				info.Logger().WithFields(mmsg.TargetLogFields(target)).Trace("noliving notify")
 			}
-			return []concern.Notify{NewConcernLiveNotify(groupCode, info)}
+			return []concern.Notify{NewConcernLiveNotify(target, info)}
 		default:
 			logger.Errorf("unknown EventType %+v", event)
 			return nil
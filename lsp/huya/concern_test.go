@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/cnxysoft/DDBOT-WSa/internal/test"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -45,7 +46,7 @@ func TestConcern(t *testing.T) {
 	defer c.Stop()
 	defer close(testEventChan)
 
-	_, err = c.Add(nil, test.G1, testRoom, Live)
+	_, err = c.Add(nil, mmsg.NewGroupTarget(test.G1), testRoom, Live)
 	assert.Nil(t, err)
 
 	liveInfo2, err := c.FindOrLoadRoom(testRoom)
@@ -59,12 +60,12 @@ func TestConcern(t *testing.T) {
 
 	select {
 	case notify := <-testNotifyChan:
-		assert.Equal(t, test.G1, notify.GetGroupCode())
+		assert.Equal(t, mmsg.NewGroupTarget(test.G1), notify.GetTarget())
 		assert.Equal(t, testRoom, notify.GetUid())
 	case <-time.After(time.Second):
 		assert.Fail(t, "no notify received")
 	}
 
-	_, err = c.Remove(nil, test.G1, testRoom, Live)
+	_, err = c.Remove(nil, mmsg.NewGroupTarget(test.G1), testRoom, Live)
 	assert.Nil(t, err)
 }
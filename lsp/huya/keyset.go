@@ -1,6 +1,10 @@
 package huya
 
-import "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+)
 
 type keySet struct {
 }
@@ -21,8 +25,8 @@ func (l *keySet) FreshKey(keys ...interface{}) string {
 	return buntdb.HuyaFreshKey(keys...)
 }
 
-func (l *keySet) ParseGroupConcernStateKey(key string) (int64, interface{}, error) {
-	return buntdb.ParseConcernStateKeyWithString(key)
+func (l *keySet) ParseGroupConcernStateKey(key string) (mmsg.Target, interface{}, error) {
+	return concern.ParseConcernStateKeyWithString(key)
 }
 
 type extraKey struct{}
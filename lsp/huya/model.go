@@ -4,9 +4,7 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
-	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/sirupsen/logrus"
-	"sync"
 )
 
 type LiveInfo struct {
@@ -17,8 +15,7 @@ type LiveInfo struct {
 	RoomName string `json:"room_name"`
 	IsLiving bool   `json:"living"`
 
-	once              sync.Once
-	msgCache          *mmsg.MSG
+	msgCache          template.MsgCache
 	liveStatusChanged bool
 	liveTitleChanged  bool
 }
@@ -39,6 +36,19 @@ func (m *LiveInfo) LiveStatusChanged() bool {
 	return m.liveStatusChanged
 }
 
+func (m *LiveInfo) AnchorName() string {
+	return m.Name
+}
+
+func (m *LiveInfo) RoomTitle() string {
+	return m.RoomName
+}
+
+// Popularity 虎牙目前没有获取人气，总是返回0
+func (m *LiveInfo) Popularity() int64 {
+	return 0
+}
+
 func (m *LiveInfo) GetUid() interface{} {
 	return m.RoomId
 }
@@ -79,8 +89,8 @@ func (m *LiveInfo) Site() string {
 	return Site
 }
 
-func (m *LiveInfo) GetMSG() *mmsg.MSG {
-	m.once.Do(func() {
+func (m *LiveInfo) GetMSG(locale string) *mmsg.MSG {
+	return m.msgCache.Get(locale, func() *mmsg.MSG {
 		var data = map[string]interface{}{
 			"title":  m.RoomName,
 			"name":   m.Name,
@@ -88,42 +98,40 @@ func (m *LiveInfo) GetMSG() *mmsg.MSG {
 			"cover":  m.Avatar,
 			"living": m.Living(),
 		}
-		var err error
-		m.msgCache, err = template.LoadAndExec("notify.group.huya.live.tmpl", data)
+		msg, err := template.ExecNotify(locale, "notify.group.huya.live.tmpl", data)
 		if err != nil {
 			logger.Errorf("huya: LiveInfo LoadAndExec error %v", err)
 		}
-		return
+		return msg
 	})
-	return m.msgCache
 }
 
 type ConcernLiveNotify struct {
 	*LiveInfo
-	GroupCode int64 `json:"group_code"`
+	Target mmsg.Target `json:"target"`
 }
 
-func (notify *ConcernLiveNotify) GetGroupCode() int64 {
-	return notify.GroupCode
+func (notify *ConcernLiveNotify) GetTarget() mmsg.Target {
+	return notify.Target
 }
 
 func (notify *ConcernLiveNotify) ToMessage() (m *mmsg.MSG) {
-	return notify.LiveInfo.GetMSG()
+	return notify.LiveInfo.GetMSG(template.NotifyLocale(notify.Target))
 }
 
 func (notify *ConcernLiveNotify) Logger() *logrus.Entry {
 	if notify == nil {
 		return logger
 	}
-	return notify.LiveInfo.Logger().WithFields(localutils.GroupLogFields(notify.GroupCode))
+	return notify.LiveInfo.Logger().WithFields(mmsg.TargetLogFields(notify.Target))
 }
 
-func NewConcernLiveNotify(groupCode int64, l *LiveInfo) *ConcernLiveNotify {
+func NewConcernLiveNotify(target mmsg.Target, l *LiveInfo) *ConcernLiveNotify {
 	if l == nil {
 		return nil
 	}
 	return &ConcernLiveNotify{
 		l,
-		groupCode,
+		target,
 	}
 }
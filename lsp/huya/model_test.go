@@ -2,6 +2,7 @@ package huya
 
 import (
 	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -15,10 +16,10 @@ func TestLiveInfo(t *testing.T) {
 	assert.Equal(t, Site, l.Site())
 	assert.Equal(t, test.NAME2, l.GetName())
 	assert.Equal(t, Live, l.Type())
-	notify := NewConcernLiveNotify(test.G1, l)
+	notify := NewConcernLiveNotify(mmsg.NewGroupTarget(test.G1), l)
 	assert.NotNil(t, notify)
 	assert.NotNil(t, notify.Logger())
-	assert.Equal(t, test.G1, notify.GetGroupCode())
+	assert.Equal(t, mmsg.NewGroupTarget(test.G1), notify.GetTarget())
 	assert.Equal(t, test.NAME1, notify.GetUid())
 	assert.Equal(t, Live, notify.Type())
 
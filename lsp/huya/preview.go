@@ -0,0 +1,23 @@
+package huya
+
+import (
+	"errors"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+)
+
+// Preview 使用当前的直播间状态，并当作刚刚发生变化处理
+func (c *Concern) Preview(target mmsg.Target, id interface{}, ctype concern_type.Type, limit int) ([]concern.Notify, error) {
+	if ctype != Live {
+		return nil, errors.New("该类型不支持预览")
+	}
+	// 不能使用 FindRoom，它会保存直播状态，导致刷新时错过真正的开播推送
+	liveInfo, err := RoomPage(id.(string))
+	if err != nil {
+		return nil, err
+	}
+	liveInfo.liveStatusChanged = true
+	return []concern.Notify{NewConcernLiveNotify(target, liveInfo)}, nil
+}
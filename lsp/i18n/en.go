@@ -0,0 +1,250 @@
+package i18n
+
+func init() {
+	register(English, en)
+}
+
+// en 英文翻译，key为代码中的中文原文，带有%v的原文会匹配格式化后的文本
+var en = map[string]string{
+	// 命令描述
+	"显示帮助信息":    "Show help message",
+	"返回pong":    "Reply pong",
+	"设置沉默模式":    "Set silence mode",
+	"设置本群使用的语言": "Set the language used in this group",
+	"设置群使用的语言":  "Set the language used in a group",
+	"把其他群的所有订阅及配置复制到本群，需要两个群的管理员权限":   "Copy all subscriptions and configs of another group to this group, requires admin of both groups",
+	"把其他群的所有订阅及配置复制到指定的群，需要两个群的管理员权限": "Copy all subscriptions and configs of another group to the given group, requires admin of both groups",
+	"切换BOT模式":                      "Switch the BOT mode",
+	"处理好友请求":                       "Handle friend requests",
+	"处理群邀请":                        "Handle group invitations",
+	"查看当前Admin权限":                  "Show current Admin permissions",
+	"扫码登录订阅模块使用的账号":                "Log in to the account used by a subscription module with a QR code",
+	"查看订阅模块使用的镜像站状态":               "Show the status of mirrors used by a subscription module",
+	"查看发送失败的推送":                    "Show notifications that failed to send",
+	"搜索本群的消息存档":                    "Search the message archive of this group",
+	"搜索群消息存档":                      "Search the group message archive",
+	"预览订阅最新的内容在本群是否会推送":            "Preview whether the latest content of a subscription would be pushed to this group",
+	"预览订阅最新的内容在群内是否会推送":            "Preview whether the latest content of a subscription would be pushed to the group",
+	"电脑使用/倒放 [图片] 或者 回复图片消息+/倒放触发": "Use /倒放 [image] on PC, or reply to an image with /倒放",
+	"管理BOT的配置，目前支持配置@成员、@全体成员、开启下播推送、开启标题推送、推送过滤、媒体存档、删除检测、订阅别名": "Manage BOT configs: @ members, @ all members, offline notify, title notify, notify filters, media archive, deletion detection and subscription aliases",
+	"当前支持的网站：%v": "Supported sites: %v",
+
+	// 命令参数
+	"网站参数":  "site",
+	"类型参数":  "type",
+	"命令名":   "command name",
+	"目标qq号": "target QQ number",
+	"取消设置":  "unset",
+	"删除模式，执行删除权限操作":       "delete mode, revoke the permission",
+	"人物别名，把不同网站的订阅归为同一个人": "person alias, group subscriptions on different sites as the same person",
+	"按人物列出订阅":             "list subscriptions by person",
+	"从回复的文本文件读取id，或者把id作为账号的关注分组名称读取分组内的用户": "read ids from the replied text file, or treat the ids as follow group names of the account and read the users in them",
+	"订阅的id，可以填多个，使用 网站:id 的格式可以同时订阅不同网站":    "ids to watch, multiple ids are allowed, use site:id to watch different sites at once",
+	"复制订阅的来源QQ群号码":              "QQ group number to copy subscriptions from",
+	"配置的主播id":                   "id of the streamer to configure",
+	"需要@的成员QQ号码":                "QQ numbers of members to @",
+	"配置推送时的@人员列表，默认为空":          "configure the members to @ when pushing, empty by default",
+	"配置推送时@全体成员，默认关闭，需要管理员权限":   "configure @ all members when pushing, off by default, requires admin permission",
+	"配置直播间标题发生变化时是否进行推送，默认不推送":  "configure whether to push when the live room title changes, off by default",
+	"配置下播时是否进行推送，默认不推送":         "configure whether to push when the stream goes offline, off by default",
+	"指定的种类":                     "types to include",
+	"只推送指定种类的动态":                "only push posts of the given types",
+	"指定不推送的种类":                  "types to exclude",
+	"不推送指定种类的动态":                "do not push posts of the given types",
+	"指定的关键字":                    "keywords",
+	"当动态内容里出现关键字时进行推送":          "push when the post contains a keyword",
+	"清除过滤器":                     "clear the filter",
+	"查看当前过滤器":                   "show the current filter",
+	"配置动态过滤器":                   "configure the post filter",
+	"推送类型，youtube视频为news":       "notify type, use news for youtube videos",
+	"存档使用的群文件夹名称，默认为推送存档":       "group file folder used for the archive, 推送存档 by default",
+	"同时存档视频和音频文件（如果有）":          "also archive video and audio files (if any)",
+	"同时存档视频链接":                  "also archive video links",
+	"配置推送时把封面等媒体存档到群文件，默认关闭":    "configure archiving covers and other media to group files when pushing, off by default",
+	"配置动态被作者删除后撤回推送或者回复标注，默认关闭": "configure recalling or annotating the notify when the author deletes the post, off by default",
+	"新的别名，不指定时删除别名":             "the new alias, removes the alias if not given",
+	"配置订阅在群内的别名，其他命令可以使用别名代替id，默认为订阅时的名字": "configure the alias of a subscription in this group, other commands accept the alias instead of the id, defaults to the name at watch time",
+	"清除指定的网站订阅,默认为全部":                     "clean subscriptions of the given site, all by default",
+	"清除指定的订阅类型,默认为全部":                     "clean subscriptions of the given type, all by default",
+	"清除异常订阅": "clean abnormal subscriptions",
+	"清除指定群的订阅，多个可用英文逗号隔开":                "clean subscriptions of the given groups, separated by commas",
+	"消息中包含的关键字":                          "keyword contained in the message",
+	"发送者的QQ号":                            "QQ number of the sender",
+	"开始时间，例如 12h、21:00、2006-01-02 15:04": "start time, e.g. 12h, 21:00, 2006-01-02 15:04",
+	"结束时间，格式同--since":                    "end time, same format as --since",
+	"最多显示的条数":                            "maximum number of results",
+	"搜索前先从协议端拉取最近的消息条数":                  "number of recent messages to fetch from the protocol before searching",
+	"要搜索的QQ群号码":                          "QQ group number to search",
+	"要操作的QQ群号码":                          "QQ group number to operate on",
+	"要操作的QQ群号码，不指定时操作自己的私聊订阅":            "QQ group number to operate on, your private subscriptions if omitted",
+	"要操作的QQ群号码，不指定时列出自己的私聊订阅":            "QQ group number to operate on, list your private subscriptions if omitted",
+	"要操作的QQ群号码，不指定时配置自己的私聊订阅":            "QQ group number to operate on, configure your private subscriptions if omitted",
+	"预览最新的条数":                            "number of latest items to preview",
+	"把推送的内容私聊发送给自己":                      "send the rendered notifies to yourself privately",
+	"把推送的内容发送给自己":                        "send the rendered notifies to yourself",
+	"查看当前登录状态":                           "show the current login status",
+	"清除指定镜像站的冷却和统计，all为全部镜像站":            "reset cooldown and stats of the given mirror, all for every mirror",
+	"查看死信":                 "show dead letters",
+	"立即重新发送指定id的死信":        "resend the dead letter with the given id now",
+	"删除指定id的死信，多个可用英文逗号隔开": "delete dead letters with the given ids, separated by commas",
+	"删除全部死信":               "delete all dead letters",
+	"系统级操作，对所有群生效":         "global operation, applies to all groups",
+	"要block的qq号或者qq群":      "QQ number or group to block",
+	"要block的天数，默认是永久":      "days to block, forever by default",
+	"取消block":              "unblock",
+	"要查询的行数":               "number of lines to query",
+	"要查询的日期，需要日志保留才能生效":    "date to query, requires the log to be kept",
+	"要包含的关键字":              "keyword to include",
+	"要退出的群号":               "group number to quit",
+	"强制清除":                 "force clean",
+	"指定切换模式，支持<公开> <私人> <审核>，默认为公开": "mode to switch to, supports <公开> <私人> <审核>, 公开 by default",
+	"要处理的请求的RequestId":              "RequestId of the request to handle",
+	"拒绝请求":                          "reject the request",
+	"处理全部":                          "handle all",
+	"拒绝理由":                          "reason of rejection",
+	"要设置的语言，支持 zh-CN / en，不指定时查看当前语言": "language to set, supports zh-CN / en, show the current language if omitted",
+
+	// 命令运行时
+	"参数解析失败 - %v": "Failed to parse arguments - %v",
+	"错误 - %v":     "Error - %v",
+
+	// concern
+	"不支持的类型参数":      "unsupported type",
+	"不支持的网站参数":      "unsupported site",
+	"不支持的配置":        "unsupported config",
+	"不支持的类型参数 <%v>": "unsupported type <%v>",
+	"不支持的网站参数 <%v>": "unsupported site <%v>",
+	"本群已达到订阅上限":     "this group has reached the subscription limit",
+	"不支持的语言":        "unsupported language",
+	"人物不存在":         "person not found",
+	"人物别名不能为空，不能包含空白字符以及 : * ?，并且不能超过%v个字": "the person alias must not be empty, must not contain spaces or : * ?, and must be at most %v characters",
+	"别名不能为空，不能包含空白字符以及 : * ?，并且不能超过%v个字":   "the alias must not be empty, must not contain spaces or : * ?, and must be at most %v characters",
+	"别名已被其他订阅使用": "the alias is already used by another subscription",
+	"别名不存在":      "alias not found",
+	"请回复一个文本文件，或者填写关注分组的名称": "please reply to a text file, or give the names of follow groups",
+	"文件过大":                  "the file is too large",
+	"获取文件链接失败":              "failed to get the file url",
+	"文件下载失败 - %v":           "failed to download the file - %v",
+	"该网站不支持从关注分组读取":         "this site does not support reading from follow groups",
+	"关注分组【%v】读取失败 - %v":     "failed to read follow group [%v] - %v",
+	"关注分组不存在":               "follow group not found",
+	"未找到用户":                 "user not found",
+	"无法解析时间【%v】":            "cannot parse time [%v]",
+	"发件箱没有启动":               "the outbox is not started",
+	"未知模式【%v】":              "unknown mode [%v]",
+	"该类型不支持预览":              "this type does not support preview",
+	"找不到 TwitCasting 配置":    "TwitCasting is not configured",
+	"添加订阅失败 - %v":           "failed to watch - %v",
+	"关注用户失败 - %v":           "failed to follow the user - %v",
+	"查询用户信息失败 %v - %v":      "failed to query user info %v - %v",
+	"查询用户信息失败 %v - %v %v":   "failed to query user info %v - %v %v",
+	"查询房间信息失败 %v - %v":      "failed to query room info %v - %v",
+	"查询频道信息失败 %v - %v":      "failed to query channel info %v - %v",
+	"查询channel信息失败 %v - %v": "failed to query channel info %v - %v",
+	"内部错误":                  "internal error",
+	"用户不存在":                 "user does not exist",
+	"用户不存在或返回结果为空":          "user does not exist or the result is empty",
+	"房间不存在":                 "room does not exist",
+	"房间已被关闭":                "room has been closed",
+	"涉嫌违规，正在整改中":            "room is suspended for violations",
+	"频道不存在":                 "channel does not exist",
+	"频道已被封禁":                "channel has been banned",
+	"无效的kick频道名":            "invalid kick channel name",
+	"无效的twitch用户名":          "invalid twitch user name",
+	"没有可用的镜像站":              "no mirror available",
+	"通过 Anubis 验证后仍然需要验证":   "verification is still required after passing Anubis",
+	"检测到人机验证，请稍后再试或尝试手动完成验证": "captcha detected, please retry later or complete it manually",
+	"未找到有效的用户信息":             "no valid user info found",
+	"作品列表为空，可能触发了风控":         "the post list is empty, risk control may be triggered",
+	"获取作品列表失败：%v":            "failed to get the post list: %v",
+	"超话id格式错误":               "invalid super topic id",
+	"刷新超话失败":                 "failed to refresh the super topic",
+	"无法查看超话，请检查超话id":         "cannot view the super topic, please check the id",
+	"刷新用户微博失败":               "failed to refresh the user's weibo",
+	"无法查看用户微博":               "cannot view the user's weibo",
+	"接口请求失败":                 "API request failed",
+	"合集和系列的id只能是数字":          "ids of collections and series must be numbers",
+	"%v %v 不存在":              "%v %v does not exist",
+	"查询信息失败":                 "failed to query info",
+	"合集或番剧不存在":               "collection or season does not exist",
+	"无效的id %v":               "invalid id %v",
+	"未定义的类型：\n%v":            "undefined types:\n%v",
+	"订阅目标粉丝数未超过%v无法订阅，请确认您的订阅目标是否正确，注意使用UID而非直播间ID": "cannot watch a target with fewer than %v followers, please make sure the target is correct and use the UID instead of the live room id",
+	"该用户未在关注列表内，请联系管理员":                             "the user is not in the follow list, please contact the admin",
+	"未配置B站": "bilibili is not configured",
+	"已经有一个正在进行的扫码登录": "a QR code login is already in progress",
+	"二维码已失效":         "the QR code has expired",
+	"等待扫码超时":         "timed out waiting for the QR code scan",
+	"账号已存在":          "account already exists",
+	"账号不存在":          "account does not exist",
+	"账号信息缺失":         "account info is missing",
+	"未设置帐号":          "account is not set",
+	"找不到用户 %v":       "user %v not found",
+	"请求过度频繁":         "too many requests",
+	"请求内容无效":         "invalid request",
+	"API服务器错误":       "API server error",
+	"无效的 TwitCasting API Token, 你请确保你填写了正确的 Twitcasting token 资料": "invalid TwitCasting API Token, please make sure the Twitcasting token is correct",
+	"下载媒体失败": "failed to download media",
+	"BOT不在线": "BOT is offline",
+
+	// 订阅时的完整错误
+	"关注用户失败 - 未配置B站":             "failed to follow the user - bilibili is not configured",
+	"关注用户失败 - 内部错误":              "failed to follow the user - internal error",
+	"关注用户失败 - 该用户未在关注列表内，请联系管理员": "failed to follow the user - the user is not in the follow list, please contact the admin",
+	"添加订阅失败 - 内部错误":              "failed to watch - internal error",
+	"添加订阅失败 - 刷新超话失败":            "failed to watch - failed to refresh the super topic",
+	"添加订阅失败 - 无法查看超话，请检查超话id":    "failed to watch - cannot view the super topic, please check the id",
+	"添加订阅失败 - 刷新用户微博失败":          "failed to watch - failed to refresh the user's weibo",
+	"添加订阅失败 - 无法查看用户微博":          "failed to watch - cannot view the user's weibo",
+	"添加订阅失败 - %v %v 不存在":         "failed to watch - %v %v does not exist",
+	"添加订阅失败 - 查询信息失败":            "failed to watch - failed to query info",
+
+	// B站账号
+	"账号信息不完整":          "account info is incomplete",
+	"查询的Token为空":       "the token to check is empty",
+	"解析Cookies失败 - %v": "failed to parse cookies - %v",
+	"cookie信息不完整":      "cookie info is incomplete",
+	"cookie已生效，但保存配置文件失败，重启后需要重新登录 - %v": "the cookie is in effect, but saving the config file failed, you need to log in again after restarting - %v",
+
+	// HookResult
+	"default hook":                                 "no hook matched",
+	"private target":                               "@ is not available in private chat",
+	"TextFilter All pattern match failed":          "no keyword matched the text filter",
+	"Living() is false":                            "not live",
+	"Living() ok but LiveStatusChanged() is false": "live status did not change",
+	"CheckTitleChangeNotify is false":              "title notify is off",
+	"CheckOfflineNotify is false":                  "offline notify is off",
+	"bilibili unsafe start status":                 "bilibili live start status is unreliable",
+	"filtered by TypeFilter":                       "filtered by the type filter",
+	"unknown notify type":                          "unknown notify type",
+
+	// 推送
+	"weibo-%v转发了%v的微博：\n%v":          "weibo-%v reposted %v's post:\n%v",
+	"weibo-%v发布了新微博：\n%v":            "weibo-%v posted:\n%v",
+	"\n\n原微博：\n%v":                   "\n\nOriginal post:\n%v",
+	"X-%v转发了%v的推文：\n%v\n%v\n":        "X-%v reposted %v's post:\n%v\n%v\n",
+	"X-%v引用了%v的推文：\n%v\n%v\n":        "X-%v quoted %v's post:\n%v\n%v\n",
+	"X-%v转发了%v的推文：\n":                "X-%v reposted %v's post:\n",
+	"X-%v发布了新推文：\n":                  "X-%v posted:\n",
+	"\n%v引用了%v的推文：\n":                "\n%v quoted %v's post:\n",
+	"YTB-%v发布了社区帖子：\n":               "YTB-%v posted a community post:\n",
+	"YTB-%v的%v将在%v后开始：\n%v\n时间：%v\n": "YTB-%v's %v starts in %v:\n%v\nTime: %v\n",
+	"YTB-%v正在直播：\n%v\n":              "YTB-%v is live:\n%v\n",
+	"YTB-%v直播结束了：\n%v\n":             "YTB-%v's live stream has ended:\n%v\n",
+	"YTB-%v发布了直播预约：\n%v\n时间：%v\n":    "YTB-%v scheduled a live stream:\n%v\nTime: %v\n",
+	"YTB-%v发布了新视频：\n%v\n":            "YTB-%v posted a new video:\n%v\n",
+	"直播":                             "live stream",
+	"首播":                             "premiere",
+	"%v小时":                           "%v hours",
+	"%v分钟":                           "%v minutes",
+	"[封面]":                           "[cover]",
+	"[图片]":                           "[image]",
+	"%v 的 TwitCasting 直播已结束。":        "%v's TwitCasting live stream has ended.",
+	"%v 正在 TwitCasting 直播: https://twitcasting.tv/%v (直播资讯获取失败)": "%v is live on TwitCasting: https://twitcasting.tv/%v (failed to get the live info)",
+	"%v 正在 TwitCasting 直播":  "%v is live on TwitCasting",
+	"\n标题: %v":              "\nTitle: %v",
+	"%v年%v月%v日 - %v时%v分%v秒": "%v-%02v-%02v %02v:%02v:%02v",
+	"\n开播时间: %v":            "\nStarted at: %v",
+	"\n直播间: %v":             "\nLive room: %v",
+	"\n[直播封面获取失败]":          "\n[failed to get the live cover]",
+}
@@ -0,0 +1,75 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatNumber 按语言习惯格式化数字，中文使用万和亿，英文使用千分位
+func FormatNumber(locale string, n int64) string {
+	var sign string
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+	if locale == English {
+		s := strconv.FormatInt(n, 10)
+		var sb strings.Builder
+		for i, c := range s {
+			if i > 0 && (len(s)-i)%3 == 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteRune(c)
+		}
+		return sign + sb.String()
+	}
+	switch {
+	case n >= 100000000:
+		return sign + trimZero(float64(n)/100000000) + "亿"
+	case n >= 10000:
+		return sign + trimZero(float64(n)/10000) + "万"
+	default:
+		return sign + strconv.FormatInt(n, 10)
+	}
+}
+
+func trimZero(f float64) string {
+	s := strconv.FormatFloat(f, 'f', 1, 64)
+	return strings.TrimSuffix(s, ".0")
+}
+
+// FormatDuration 按语言习惯格式化时长，精确到秒
+func FormatDuration(locale string, d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	h := int64(d.Hours())
+	m := int64(d.Minutes()) % 60
+	s := int64(d.Seconds()) % 60
+	if locale == English {
+		return fmt.Sprintf("%dh %dm %ds", h, m, s)
+	}
+	return fmt.Sprintf("%d小时%d分%d秒", h, m, s)
+}
+
+// DateLayout 返回日期格式对应的layout，支持 dateonly、timeonly、stamp，其他格式返回完整的日期时间
+func DateLayout(locale string, format string) string {
+	switch format {
+	case "dateonly":
+		if locale == English {
+			return "Jan 2, 2006"
+		}
+		return time.DateOnly
+	case "timeonly":
+		return time.TimeOnly
+	case "stamp":
+		return time.Stamp
+	default:
+		if locale == English {
+			return "Jan 2, 2006 15:04:05"
+		}
+		return time.DateTime
+	}
+}
@@ -0,0 +1,150 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// Chinese 简体中文，代码里的文本都使用中文书写
+	Chinese = "zh-CN"
+	// English 英文
+	English = "en"
+)
+
+// DefaultLocale 没有设置语言时使用的语言
+const DefaultLocale = Chinese
+
+var ErrLocaleNotSupported = errors.New("不支持的语言")
+
+var localeAlias = map[string]string{
+	"zh":      Chinese,
+	"zh-cn":   Chinese,
+	"zh_cn":   Chinese,
+	"cn":      Chinese,
+	"chinese": Chinese,
+	"中文":      Chinese,
+	"en":      English,
+	"en-us":   English,
+	"en_us":   English,
+	"english": English,
+	"英文":      English,
+}
+
+// ParseLocale 把用户输入的语言转换成支持的语言代码
+func ParseLocale(s string) (string, error) {
+	if locale, found := localeAlias[strings.ToLower(strings.TrimSpace(s))]; found {
+		return locale, nil
+	}
+	return "", ErrLocaleNotSupported
+}
+
+// Locales 返回所有支持的语言
+func Locales() []string {
+	return []string{Chinese, English}
+}
+
+// IsDefault 判断是否是默认语言，默认语言不需要翻译
+func IsDefault(locale string) bool {
+	return locale == "" || locale == DefaultLocale
+}
+
+// pattern 带有%v的翻译，匹配时把%v对应的内容也进行翻译后填入
+type pattern struct {
+	source      string
+	re          *regexp.Regexp
+	translation string
+}
+
+type catalog struct {
+	exact    map[string]string
+	patterns []*pattern
+}
+
+var catalogs = make(map[string]*catalog)
+
+// register 注册一种语言的翻译，key为代码中的中文原文
+func register(locale string, m map[string]string) {
+	c := &catalog{exact: make(map[string]string)}
+	for source, translation := range m {
+		c.exact[source] = translation
+		if !strings.Contains(source, "%v") {
+			continue
+		}
+		var expr []string
+		for _, part := range strings.Split(source, "%v") {
+			expr = append(expr, regexp.QuoteMeta(part))
+		}
+		c.patterns = append(c.patterns, &pattern{
+			source:      source,
+			re:          regexp.MustCompile(`(?s)^` + strings.Join(expr, "(.*?)") + `$`),
+			translation: translation,
+		})
+	}
+	// 更长的原文更具体，优先匹配
+	sort.Slice(c.patterns, func(i, j int) bool {
+		if len(c.patterns[i].source) != len(c.patterns[j].source) {
+			return len(c.patterns[i].source) > len(c.patterns[j].source)
+		}
+		return c.patterns[i].source < c.patterns[j].source
+	})
+	catalogs[locale] = c
+}
+
+func (c *catalog) translate(s string) (string, bool) {
+	if t, found := c.exact[s]; found {
+		return t, true
+	}
+	for _, p := range c.patterns {
+		match := p.re.FindStringSubmatch(s)
+		if match == nil {
+			continue
+		}
+		var args []interface{}
+		for _, arg := range match[1:] {
+			if t, found := c.translate(arg); found {
+				arg = t
+			}
+			args = append(args, arg)
+		}
+		return fmt.Sprintf(p.translation, args...), true
+	}
+	return s, false
+}
+
+// Tr 翻译一段文本，没有对应的翻译时返回原文
+func Tr(locale string, s string) string {
+	if IsDefault(locale) {
+		return s
+	}
+	c, found := catalogs[locale]
+	if !found {
+		return s
+	}
+	t, _ := c.translate(s)
+	return t
+}
+
+// Sprintf 先翻译format再进行格式化
+func Sprintf(locale string, format string, a ...interface{}) string {
+	if IsDefault(locale) {
+		return fmt.Sprintf(format, a...)
+	}
+	if c, found := catalogs[locale]; found {
+		if t, found := c.exact[format]; found {
+			format = t
+		}
+	}
+	return fmt.Sprintf(format, a...)
+}
+
+// Error 翻译错误信息，err为nil时返回空字符串
+func Error(locale string, err error) string {
+	if err == nil {
+		return ""
+	}
+	return Tr(locale, err.Error())
+}
@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLocale(t *testing.T) {
+	var testCase = []struct {
+		input  string
+		expect string
+	}{
+		{"zh", Chinese},
+		{"zh-CN", Chinese},
+		{"中文", Chinese},
+		{"en", English},
+		{" English ", English},
+		{"英文", English},
+	}
+	for _, c := range testCase {
+		locale, err := ParseLocale(c.input)
+		assert.Nil(t, err)
+		assert.Equal(t, c.expect, locale)
+	}
+	_, err := ParseLocale("fr")
+	assert.EqualValues(t, ErrLocaleNotSupported, err)
+}
+
+func TestTr(t *testing.T) {
+	assert.Equal(t, "网站参数", Tr(Chinese, "网站参数"))
+	assert.Equal(t, "网站参数", Tr("", "网站参数"))
+	assert.Equal(t, "site", Tr(English, "网站参数"))
+	assert.Equal(t, "not in catalog", Tr(English, "not in catalog"))
+	assert.Equal(t, "not live", Tr(English, "Living() is false"))
+
+	// 带有%v的原文，匹配到的内容也会翻译
+	assert.Equal(t, "unsupported site <abc>", Tr(English, "不支持的网站参数 <abc>"))
+	assert.Equal(t, "failed to query user info 123 - user does not exist",
+		Tr(English, fmt.Sprintf("查询用户信息失败 %v - %v", 123, "用户不存在")))
+	assert.Equal(t, "failed to watch - internal error", Tr(English, "添加订阅失败 - 内部错误"))
+	assert.Equal(t, "failed to follow the user - bilibili is not configured", Tr(English, "关注用户失败 - 未配置B站"))
+	assert.Equal(t, "failed to watch - cannot view the super topic, please check the id",
+		Tr(English, "添加订阅失败 - 无法查看超话，请检查超话id"))
+	assert.Equal(t, "failed to watch - collection 123 does not exist",
+		Tr(English, fmt.Sprintf("添加订阅失败 - %v %v 不存在", "collection", 123)))
+
+	assert.Equal(t, "failed to get the post list: busy", Tr(English, fmt.Sprintf("获取作品列表失败：%v", "busy")))
+
+	assert.Equal(t, "Supported sites: a/b", Sprintf(English, "当前支持的网站：%v", "a/b"))
+	assert.Equal(t, "当前支持的网站：a/b", Sprintf(Chinese, "当前支持的网站：%v", "a/b"))
+	assert.Equal(t, "weibo-name posted:\ntext", Sprintf(English, "weibo-%v发布了新微博：\n%v", "name", "text"))
+	assert.Equal(t, "2 hours", Tr(English, "2小时"))
+
+	assert.Equal(t, "", Error(English, nil))
+	assert.Equal(t, "person not found", Error(English, errors.New("人物不存在")))
+	assert.Equal(t, "人物不存在", Error(Chinese, errors.New("人物不存在")))
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "1,234,567", FormatNumber(English, 1234567))
+	assert.Equal(t, "-1,000", FormatNumber(English, -1000))
+	assert.Equal(t, "999", FormatNumber(English, 999))
+	assert.Equal(t, "9999", FormatNumber(Chinese, 9999))
+	assert.Equal(t, "1.2万", FormatNumber(Chinese, 12345))
+	assert.Equal(t, "3万", FormatNumber(Chinese, 30000))
+	assert.Equal(t, "1.5亿", FormatNumber(Chinese, 150000000))
+
+	d := time.Hour + time.Minute*2 + time.Second*3
+	assert.Equal(t, "1小时2分3秒", FormatDuration(Chinese, d))
+	assert.Equal(t, "1h 2m 3s", FormatDuration(English, d))
+	assert.Equal(t, "0h 0m 0s", FormatDuration(English, -d))
+
+	tm := time.Date(2021, 1, 2, 15, 4, 5, 0, time.Local)
+	assert.Equal(t, "2021-01-02", tm.Format(DateLayout(Chinese, "dateonly")))
+	assert.Equal(t, "Jan 2, 2021", tm.Format(DateLayout(English, "dateonly")))
+	assert.Equal(t, "15:04:05", tm.Format(DateLayout(English, "timeonly")))
+	assert.Equal(t, "2021-01-02 15:04:05", tm.Format(DateLayout(Chinese, "")))
+	assert.Equal(t, "Jan 2, 2021 15:04:05", tm.Format(DateLayout(English, "")))
+}
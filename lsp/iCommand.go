@@ -5,15 +5,26 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
+	"unicode"
 
+	"github.com/Mrs4s/MiraiGo/message"
 	"github.com/Sora233/sliceutil"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/alias"
 	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/cfg"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/interfaces"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/msgarchive"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/permission"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/person"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
+	"github.com/cnxysoft/DDBOT-WSa/requests"
 	"github.com/cnxysoft/DDBOT-WSa/utils"
+	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
 	"github.com/sirupsen/logrus"
 	"github.com/tidwall/buntdb"
 )
@@ -39,11 +50,11 @@ func (p *listProviderImpl) QueryList(groupCode int64, site string, msgContext ..
 			ctx = mc
 		}
 	}
-	return IList(ctx, groupCode, site, true)
+	return IList(ctx, mmsg.NewGroupTarget(groupCode), site, true)
 }
 
 func (p *listProviderImpl) RunIList(msgContext any, groupCode int64, site string) []byte {
-	return IList(msgContext.(*MessageContext), groupCode, site, false)
+	return IList(msgContext.(*MessageContext), mmsg.NewGroupTarget(groupCode), site, false)
 }
 
 type ilistData struct {
@@ -63,10 +74,11 @@ type siteData struct {
 type idInfo struct {
 	Uid       any    `json:"Uid"`
 	Name      string `json:"Name"`
+	Alias     string `json:"Alias,omitempty"`
 	WatchType string `json:"WatchType"`
 }
 
-func IList(c *MessageContext, groupCode int64, site string, opts ...any) []byte {
+func IList(c *MessageContext, target mmsg.Target, site string, opts ...any) []byte {
 	var queryMode bool
 	if len(opts) > 0 {
 		if qm, ok := opts[0].(bool); ok {
@@ -75,7 +87,7 @@ func IList(c *MessageContext, groupCode int64, site string, opts ...any) []byte
 	}
 
 	if !queryMode {
-		if c.Lsp.PermissionStateManager.CheckGroupCommandDisabled(groupCode, ListCommand) {
+		if target.TargetType().IsGroup() && c.Lsp.PermissionStateManager.CheckGroupCommandDisabled(target.TargetCode(), ListCommand) {
 			c.DisabledReply()
 			return nil
 		}
@@ -91,7 +103,9 @@ func IList(c *MessageContext, groupCode int64, site string, opts ...any) []byte
 	if len(site) > 0 {
 		cm, err := concern.GetConcernByParseSite(site)
 		if err != nil {
-			c.TextReply(fmt.Sprintf("失败 - %v", err))
+			c.TemplateReply("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return nil
 		}
 		targetCM = append(targetCM, cm)
@@ -100,8 +114,8 @@ func IList(c *MessageContext, groupCode int64, site string, opts ...any) []byte
 	}
 
 	for _, cm := range targetCM { // 避免变量名冲突，将 c 改为 cm
-		_, ids, ctypes, err := cm.GetStateManager().ListConcernState(func(_groupCode int64, _ interface{}, _ concern_type.Type) bool {
-			return groupCode == _groupCode
+		_, ids, ctypes, err := cm.GetStateManager().ListConcernState(func(_target mmsg.Target, _ interface{}, _ concern_type.Type) bool {
+			return mmsg.TargetEqual(target, _target)
 		})
 		if err == nil {
 			ids, ctypes, err = cm.GetStateManager().GroupTypeById(ids, ctypes)
@@ -113,7 +127,10 @@ func IList(c *MessageContext, groupCode int64, site string, opts ...any) []byte
 				listMsg.Text("\n")
 				charCount += 1 // 增加换行符的字符数
 			}
-			errorMsg := fmt.Sprintf("%v订阅查询失败 - %v", cm.Site(), err)
+			errorMsg := c.TemplateText("reply.list.query_failed", map[string]interface{}{
+				"site":  cm.Site(),
+				"error": err,
+			})
 			listMsg.Text(errorMsg)
 			charCount += len(errorMsg)
 			if charCount > 4500 {
@@ -137,6 +154,7 @@ func IList(c *MessageContext, groupCode int64, site string, opts ...any) []byte
 						nowInfo := idInfo{
 							Uid:       info.GetUid(),
 							Name:      info.GetName(),
+							Alias:     concernAlias(target, cm.Site(), id),
 							WatchType: ctypes[index].String(),
 						}
 						tmpData.Ids = append(tmpData.Ids, nowInfo)
@@ -165,7 +183,9 @@ func IList(c *MessageContext, groupCode int64, site string, opts ...any) []byte
 						charCount += 1 // 增加换行符的字符数
 					}
 					if len(ids) > 0 {
-						partTitle := fmt.Sprintf("%v订阅：", cm.Site())
+						partTitle := c.TemplateText("reply.list.site_title", map[string]interface{}{
+							"site": cm.Site(),
+						})
 						listMsg.Text(partTitle)
 						charCount += len(partTitle)
 					}
@@ -174,10 +194,18 @@ func IList(c *MessageContext, groupCode int64, site string, opts ...any) []byte
 						if err != nil {
 							info = concern.NewIdentity(id, "unknown")
 						}
-						itemMsg := fmt.Sprintf("\n%v %v %v", info.GetName(), info.GetUid(), ctypes[index].String())
+						itemMsg := "\n" + c.TemplateText("reply.list.item", map[string]interface{}{
+							"name":  info.GetName(),
+							"uid":   info.GetUid(),
+							"type":  ctypes[index].String(),
+							"alias": concernAlias(target, cm.Site(), id),
+						})
 						if charCount+len(itemMsg) > 4500 && index < len(ids)-1 {
 							listMsg.Cut()
-							partTitle := fmt.Sprintf("%v订阅(第%d部分)：", cm.Site(), (index+1)/4500+1)
+							partTitle := c.TemplateText("reply.list.site_title", map[string]interface{}{
+								"site": cm.Site(),
+								"part": (index+1)/4500 + 1,
+							})
 							listMsg.Text(partTitle)
 							charCount = len(partTitle)
 						} else {
@@ -199,7 +227,9 @@ func IList(c *MessageContext, groupCode int64, site string, opts ...any) []byte
 	}
 
 	if len(listMsg.Elements()) == 0 {
-		noSubMsg := fmt.Sprintf("暂无订阅，可以使用%v命令订阅", c.Lsp.CommandShowName(WatchCommand))
+		noSubMsg := c.TemplateText("reply.list.empty", map[string]interface{}{
+			"command": c.Lsp.CommandShowName(WatchCommand),
+		})
 		listMsg.Text(noSubMsg)
 	}
 	if len(listMsg.Elements()) > 0 {
@@ -208,12 +238,375 @@ func IList(c *MessageContext, groupCode int64, site string, opts ...any) []byte
 	return nil
 }
 
-func IWatch(c *MessageContext, groupCode int64, id string, site string, watchType concern_type.Type, remove bool) {
+func IWatch(c *MessageContext, target mmsg.Target, id string, site string, watchType concern_type.Type, remove bool) {
+	if !checkWatchPermission(c, target) {
+		return
+	}
+	result, err := iWatch(c, target, id, site, watchType, remove)
+	if err != nil {
+		replyErr(c, err)
+		return
+	}
+	if remove {
+		c.TemplateReply("reply.unwatch.success", result)
+	} else {
+		c.TemplateReply("reply.watch.success", result)
+	}
+}
+
+// maxWatchBatch 一次批量watch或unwatch最多的数量
+const maxWatchBatch = 200
+
+// watchItem 批量watch中的一项
+type watchItem struct {
+	Site string
+	Type concern_type.Type
+	Id   string
+	// err 解析失败的原因，失败的项会直接记为失败
+	err error
+}
+
+// parseWatchItems 解析批量watch的id，可以使用 网站:id 的格式单独指定网站，这时会使用rawType解析这个网站的类型，
+// 没有指定网站时使用site和ctype
+func parseWatchItems(rawIds []string, site string, ctype concern_type.Type, rawType string) []*watchItem {
+	var result []*watchItem
+	for _, rawId := range rawIds {
+		item := &watchItem{Site: site, Type: ctype, Id: rawId}
+		if pos := strings.Index(rawId, ":"); pos > 0 {
+			if _, err := concern.ParseRawSite(rawId[:pos]); err == nil {
+				item.Id = rawId[pos+1:]
+				item.Site, item.Type, item.err = concern.ParseRawSiteAndType(rawId[:pos], rawType)
+			}
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// parseWatchList 解析批量watch的文本文件，每行可以有多个id，使用空白字符或者逗号分隔，#开头的行会被忽略
+func parseWatchList(content string) []string {
+	var result []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		result = append(result, strings.FieldsFunc(line, func(r rune) bool {
+			return unicode.IsSpace(r) || r == ',' || r == '，'
+		})...)
+	}
+	return result
+}
+
+// IWatchItems watch或unwatch解析后的id，只有一个id时和 IWatch 相同，多个id时使用 IWatchBatch
+func IWatchItems(c *MessageContext, target mmsg.Target, items []*watchItem, remove bool) {
+	if len(items) == 1 {
+		if items[0].err != nil {
+			c.TemplateReply("reply.param_error", map[string]interface{}{
+				"error": items[0].err,
+			})
+			return
+		}
+		IWatch(c, target, items[0].Id, items[0].Site, items[0].Type, remove)
+		return
+	}
+	IWatchBatch(c, target, items, remove)
+}
+
+// IWatchPersonItems 订阅多个id并把它们都归到人物 alias 下
+func IWatchPersonItems(c *MessageContext, groupCode int64, alias string, items []*watchItem) {
+	if len(items) > maxWatchBatch {
+		c.TemplateReply("reply.watch.batch_too_many", map[string]interface{}{
+			"count": len(items),
+			"max":   maxWatchBatch,
+		})
+		return
+	}
+	for _, item := range items {
+		if item.err != nil {
+			c.TemplateReply("reply.param_error", map[string]interface{}{
+				"error": item.err,
+			})
+			continue
+		}
+		IWatchPerson(c, groupCode, alias, item.Id, item.Site, item.Type)
+	}
+}
+
+// IWatchBatch 批量watch或unwatch，所有结果合并为一条回复
+func IWatchBatch(c *MessageContext, target mmsg.Target, items []*watchItem, remove bool) {
+	if !checkWatchPermission(c, target) {
+		return
+	}
+	if len(items) > maxWatchBatch {
+		c.TemplateReply("reply.watch.batch_too_many", map[string]interface{}{
+			"count": len(items),
+			"max":   maxWatchBatch,
+		})
+		return
+	}
+	var success, failed []map[string]interface{}
+	for _, item := range items {
+		err := item.err
+		var result map[string]interface{}
+		if err == nil {
+			result, err = iWatch(c, target, item.Id, item.Site, item.Type, remove)
+		}
+		if err != nil {
+			name, data := errorTemplate(err)
+			failed = append(failed, map[string]interface{}{
+				"site":   item.Site,
+				"id":     item.Id,
+				"reason": c.TemplateText(name, data),
+			})
+			continue
+		}
+		success = append(success, result)
+	}
+	c.TemplateReply("reply.watch.batch", map[string]interface{}{
+		"remove":  remove,
+		"success": success,
+		"failed":  failed,
+	})
+}
+
+// maxWatchListSize watch --from-list 读取的文件最大的大小
+const maxWatchListSize = 64 * 1024
+
+var (
+	errWatchListNotFound       = errors.New("请回复一个文本文件，或者填写关注分组的名称")
+	errWatchListTooLarge       = errors.New("文件过大")
+	errWatchListUrlNotFound    = errors.New("获取文件链接失败")
+	errFollowGroupNotSupported = errors.New("该网站不支持从关注分组读取")
+)
+
+// readWatchList 读取 watch --from-list 的id列表，
+// 命令回复了一个文件时读取文件的内容，否则把names作为site的关注分组名称读取
+func readWatchList(l *Lsp, elements []message.IMessageElement, groupCode int64, site string, names []string) ([]string, error) {
+	if url, size, found := replyFile(l, elements, groupCode); found {
+		if size > maxWatchListSize {
+			return nil, errWatchListTooLarge
+		}
+		if len(url) == 0 {
+			return nil, errWatchListUrlNotFound
+		}
+		var body []byte
+		if err := requests.Get(url, nil, &body, requests.TimeoutOption(time.Second*10)); err != nil {
+			return nil, fmt.Errorf("文件下载失败 - %v", err)
+		}
+		if len(body) > maxWatchListSize {
+			return nil, errWatchListTooLarge
+		}
+		return parseWatchList(string(body)), nil
+	}
+	if len(names) == 0 {
+		return nil, errWatchListNotFound
+	}
+	cm, err := concern.GetConcernBySite(site)
+	if err != nil {
+		return nil, err
+	}
+	ext, ok := cm.(concern.FollowGroupExt)
+	if !ok {
+		return nil, errFollowGroupNotSupported
+	}
+	var result []string
+	for _, name := range names {
+		ids, err := ext.FollowGroupIds(name)
+		if err != nil {
+			return nil, fmt.Errorf("关注分组【%v】读取失败 - %v", name, err)
+		}
+		result = append(result, ids...)
+	}
+	return result, nil
+}
+
+// replyFile 查找命令回复的消息中的文件，返回文件的下载链接和大小。
+// ReplyElement 中没有被回复的消息内容，所以先查找收到群消息时保存的文件，找不到时再向协议端查询被回复的消息
+func replyFile(l *Lsp, elements []message.IMessageElement, groupCode int64) (url string, size int64, found bool) {
+	for _, e := range elements {
+		re, ok := e.(*message.ReplyElement)
+		if !ok {
+			continue
+		}
+		var replied []message.IMessageElement
+		if groupCode != 0 {
+			if fe, err := l.LspStateManager.GetMessageFile(groupCode, re.ReplySeq); err == nil {
+				replied = append(replied, fe)
+			}
+		}
+		if len(replied) == 0 {
+			msg, _ := utils.GetBot().GetMsg(re.ReplySeq)
+			switch m := msg.(type) {
+			case *message.GroupMessage:
+				replied = m.Elements
+			case *message.PrivateMessage:
+				replied = m.Elements
+			}
+		}
+		for _, e := range replied {
+			switch fe := e.(type) {
+			case *message.GroupFileElement:
+				url = fe.Url
+				if len(url) == 0 && groupCode != 0 {
+					url = utils.GetBot().GetFileUrl(groupCode, fe.Id)
+				}
+				return url, fe.Size, true
+			case *message.FriendFileElement:
+				return fe.Url, fe.Size, true
+			}
+		}
+	}
+	return
+}
+
+// iWatch 执行watch或unwatch，成功时返回回复模板的变量，失败时返回带有回复模板的错误
+func iWatch(c *MessageContext, target mmsg.Target, id string, site string, watchType concern_type.Type, remove bool) (map[string]interface{}, error) {
 	log := c.Log
 
+	cm, err := concern.GetConcernBySiteAndType(site, watchType)
+	if err != nil {
+		log.Errorf("GetConcernManager error %v", err)
+		return nil, newReplyError("reply.failed", map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	var mid interface{}
+	if remove {
+		mid, err = parseConcernId(cm, target, id)
+	} else {
+		mid, err = cm.ParseId(id)
+	}
+	if err != nil {
+		log.Errorf("Parseid error %v", err)
+		return nil, newReplyError("reply.parse_id_failed", map[string]interface{}{
+			"site": cm.Site(),
+		})
+	}
+	log = log.WithField("mid", mid)
+	if remove {
+		// unwatch
+		userInfo, _ := cm.Get(mid)
+		if _, err := cm.Remove(c, target, mid, watchType); err != nil {
+			if err == buntdb.ErrNotFound {
+				return nil, newReplyError("reply.unwatch.not_found", nil)
+			}
+			log.Errorf("site %v remove failed %v", site, err)
+			return nil, newReplyError("reply.unwatch.failed", map[string]interface{}{
+				"error": err,
+			})
+		}
+		if userInfo == nil {
+			userInfo = concern.NewIdentity(mid, "未知")
+		}
+		log.WithField("name", userInfo.GetName()).Debugf("unwatch success")
+		if target.TargetType().IsGroup() {
+			if ctype, err := cm.GetStateManager().GetGroupConcern(target, mid); err != nil || ctype.Empty() {
+				if err := person.Unlink(target.TargetCode(), site, mid); err != nil {
+					log.Errorf("person.Unlink error %v", err)
+				}
+				if err := alias.Remove(target.TargetCode(), cm.Site(), mid); err != nil {
+					log.Errorf("alias.Remove error %v", err)
+				}
+			}
+		}
+		return map[string]interface{}{
+			"site": site,
+			"name": userInfo.GetName(),
+		}, nil
+	}
+	// watch
+	userInfo, err := cm.Add(c, target, mid, watchType)
+	if err != nil {
+		if err == concern.ErrAlreadyExists {
+			log.Errorf("user already watched")
+			return nil, newReplyError("reply.watch.already", nil)
+		}
+		log.Errorf("watch error %v", err)
+		return nil, newReplyError("reply.watch.failed", map[string]interface{}{
+			"error": err,
+		})
+	}
+	if target.TargetType().IsGroup() {
+		releaseShadowAlias(c, cm, target, id, mid)
+	}
+	var aliasName string
+	if userInfo == nil {
+		userInfo = concern.NewIdentity(mid, "未知")
+	} else if target.TargetType().IsGroup() {
+		// 默认使用订阅时的名字作为别名，与其他订阅冲突时不设置
+		aliasName, err = alias.SetDefault(target.TargetCode(), cm.Site(), mid, userInfo.GetName(), func(a string) bool {
+			return aliasShadowsId(cm, target, mid, a)
+		})
+		if err != nil && err != alias.ErrAliasConflict {
+			log.Errorf("alias.SetDefault error %v", err)
+		}
+	}
+	log.WithField("name", userInfo.GetName()).Debugf("watch success")
+	return map[string]interface{}{
+		"site":  site,
+		"name":  userInfo.GetName(),
+		"alias": aliasName,
+	}, nil
+}
+
+// parseConcernId 解析命令中的订阅id，群内可以使用订阅的别名代替id
+func parseConcernId(cm concern.Concern, target mmsg.Target, id string) (interface{}, error) {
+	return cm.ParseId(resolveAlias(target, cm.Site(), id))
+}
+
+// resolveAlias 把群内的订阅别名解析为订阅的id，不是别名时原样返回
+func resolveAlias(target mmsg.Target, site string, id string) string {
+	if !target.TargetType().IsGroup() || len(id) == 0 {
+		return id
+	}
+	if mid, err := alias.Resolve(target.TargetCode(), site, id); err == nil {
+		return mid
+	}
+	return id
+}
+
+// aliasShadowsId 别名会优先于id解析，所以不能与群内其他订阅的id相同
+func aliasShadowsId(cm concern.Concern, target mmsg.Target, mid interface{}, aliasName string) bool {
+	other, err := cm.ParseId(aliasName)
+	if err != nil || fmt.Sprint(other) == fmt.Sprint(mid) {
+		return false
+	}
+	ctype, err := cm.GetStateManager().GetGroupConcern(target, other)
+	return err == nil && !ctype.Empty()
+}
+
+// releaseShadowAlias 新订阅的id已经被群内其他订阅用作别名时，删除那个别名，否则新订阅的id会被解析为其他订阅
+func releaseShadowAlias(c *MessageContext, cm concern.Concern, target mmsg.Target, id string, mid interface{}) {
+	for _, name := range []string{id, fmt.Sprint(mid)} {
+		other, err := alias.Resolve(target.TargetCode(), cm.Site(), name)
+		if err != nil || other == fmt.Sprint(mid) {
+			continue
+		}
+		if err = alias.Remove(target.TargetCode(), cm.Site(), other); err != nil {
+			c.GetLog().Errorf("alias.Remove error %v", err)
+		}
+	}
+}
+
+// concernAlias 返回订阅在群内的别名，没有别名时返回空字符串
+func concernAlias(target mmsg.Target, site string, id interface{}) string {
+	if !target.TargetType().IsGroup() {
+		return ""
+	}
+	a, _ := alias.Get(target.TargetCode(), site, id)
+	return a
+}
+
+func checkWatchPermission(c *MessageContext, target mmsg.Target) bool {
+	if target.TargetType().IsPrivate() {
+		return checkPrivateTargetPermission(c, target)
+	}
+	groupCode := target.TargetCode()
 	if c.Lsp.PermissionStateManager.CheckGroupCommandDisabled(groupCode, WatchCommand) {
 		c.DisabledReply()
-		return
+		return false
 	}
 
 	if !c.Lsp.PermissionStateManager.RequireAny(
@@ -224,60 +617,182 @@ func IWatch(c *MessageContext, groupCode int64, id string, site string, watchTyp
 		permission.GroupCommandRequireOption(groupCode, c.Sender.Uin, UnwatchCommand),
 	) {
 		c.NoPermissionReply()
-		return
+		return false
+	}
+	return true
+}
+
+// checkPrivateTargetPermission 私聊订阅只能由好友本人或者Admin操作
+func checkPrivateTargetPermission(c *MessageContext, target mmsg.Target) bool {
+	if target.TargetCode() != c.Sender.Uin &&
+		!c.Lsp.PermissionStateManager.RequireAny(permission.AdminRoleRequireOption(c.Sender.Uin)) {
+		c.NoPermissionReply()
+		return false
 	}
+	if utils.GetBot().FindFriend(target.TargetCode()) == nil {
+		c.TemplateReply("reply.watch.private_not_friend", nil)
+		return false
+	}
+	return true
+}
+
+// IWatchPerson 订阅并把这个订阅归到人物 alias 下，已经订阅过的也可以归到人物下
+func IWatchPerson(c *MessageContext, groupCode int64, alias string, id string, site string, watchType concern_type.Type) {
+	log := c.Log.WithField("person", alias)
 
+	if err := person.CheckAlias(alias); err != nil {
+		c.TemplateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	target := mmsg.NewGroupTarget(groupCode)
+	if !checkWatchPermission(c, target) {
+		return
+	}
 	cm, err := concern.GetConcernBySiteAndType(site, watchType)
 	if err != nil {
 		log.Errorf("GetConcernManager error %v", err)
-		c.TextReply(fmt.Sprintf("失败 - %v", err))
+		c.TemplateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
 		return
 	}
-
 	mid, err := cm.ParseId(id)
 	if err != nil {
 		log.Errorf("Parseid error %v", err)
-		c.TextReply(fmt.Sprintf("失败 - 解析%v id格式错误", cm.Site()))
+		c.TemplateReply("reply.parse_id_failed", map[string]interface{}{
+			"site": cm.Site(),
+		})
 		return
 	}
-	log = log.WithField("mid", mid)
-	if remove {
-		// unwatch
-		userInfo, _ := cm.Get(mid)
-		if _, err := cm.Remove(c, groupCode, mid, watchType); err != nil {
-			if err == buntdb.ErrNotFound {
-				c.TextReply(fmt.Sprintf("unwatch失败 - 未找到该用户"))
-			} else {
-				log.Errorf("site %v remove failed %v", site, err)
-				c.TextReply(fmt.Sprintf("unwatch失败 - %v", err))
-			}
-		} else {
-			if userInfo == nil {
-				userInfo = concern.NewIdentity(mid, "未知")
-			}
-			log.WithField("name", userInfo.GetName()).Debugf("unwatch success")
-			c.TextReply(fmt.Sprintf("unwatch成功 - %v用户 %v", site, userInfo.GetName()))
+	if ctype, err := cm.GetStateManager().GetGroupConcern(target, mid); err != nil || !ctype.ContainAll(watchType) {
+		IWatch(c, target, id, site, watchType, false)
+		if ctype, err = cm.GetStateManager().GetGroupConcern(target, mid); err != nil || !ctype.ContainAll(watchType) {
+			return
 		}
+	}
+	if err = person.Link(groupCode, alias, site, mid); err != nil {
+		log.Errorf("person.Link error %v", err)
+		c.TemplateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
 		return
 	}
-	// watch
-	userInfo, err := cm.Add(c, groupCode, mid, watchType)
+	c.TemplateReply("reply.person.linked", map[string]interface{}{
+		"site":  site,
+		"id":    mid,
+		"alias": alias,
+	})
+}
+
+// IUnwatchPerson 取消人物在所有网站上的订阅，并删除这个人物
+func IUnwatchPerson(c *MessageContext, groupCode int64, alias string) {
+	log := c.Log.WithField("person", alias)
+
+	target := mmsg.NewGroupTarget(groupCode)
+	if !checkWatchPermission(c, target) {
+		return
+	}
+	p, err := person.Get(groupCode, alias)
 	if err != nil {
-		if err == concern.ErrAlreadyExists {
-			log.Errorf("user already watched")
-			c.TextReply(fmt.Sprintf("watch失败 - 已经watch过了"))
+		if err == person.ErrPersonNotFound {
+			c.TemplateReply("reply.person.not_found", map[string]interface{}{
+				"alias": alias,
+			})
 		} else {
-			log.Errorf("watch error %v", err)
-			c.TextReply(fmt.Sprintf("watch失败 - %v", err))
+			log.Errorf("person.Get error %v", err)
+			c.TemplateReply("reply.unwatch.failed", map[string]interface{}{
+				"error": err,
+			})
 		}
 		return
 	}
-	if userInfo == nil {
-		userInfo = concern.NewIdentity(mid, "未知")
+	var removed []map[string]interface{}
+	for _, member := range p.Members {
+		cm, err := concern.GetConcernBySite(member.Site)
+		if err != nil {
+			log.Errorf("GetConcernBySite error %v", err)
+			continue
+		}
+		mid, err := cm.ParseId(member.Id)
+		if err != nil {
+			log.Errorf("Parseid error %v", err)
+			continue
+		}
+		ctype, err := cm.GetStateManager().GetGroupConcern(target, mid)
+		if err != nil || ctype.Empty() {
+			continue
+		}
+		userInfo, _ := cm.Get(mid)
+		if _, err = cm.Remove(c, target, mid, ctype); err != nil {
+			log.WithField("site", member.Site).WithField("mid", mid).Errorf("remove failed %v", err)
+			continue
+		}
+		if userInfo == nil {
+			userInfo = concern.NewIdentity(mid, "未知")
+		}
+		removed = append(removed, map[string]interface{}{
+			"site": member.Site,
+			"name": userInfo.GetName(),
+		})
 	}
-	log.WithField("name", userInfo.GetName()).Debugf("watch success")
-	c.TextReply(fmt.Sprintf("watch成功 - %v用户 %v", site, userInfo.GetName()))
-	return
+	if _, err = person.Delete(groupCode, alias); err != nil {
+		log.Errorf("person.Delete error %v", err)
+		c.TemplateReply("reply.unwatch.failed", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	log.WithField("removed", removed).Debugf("unwatch person success")
+	if len(removed) == 0 {
+		c.TemplateReply("reply.person.deleted", map[string]interface{}{
+			"alias": alias,
+		})
+		return
+	}
+	c.TemplateReply("reply.person.unwatched", map[string]interface{}{
+		"alias":   alias,
+		"removed": removed,
+	})
+}
+
+// IListPerson 列出群内的人物以及每个人物包含的订阅
+func IListPerson(c *MessageContext, groupCode int64) {
+	if c.Lsp.PermissionStateManager.CheckGroupCommandDisabled(groupCode, ListCommand) {
+		c.DisabledReply()
+		return
+	}
+	persons, err := person.List(groupCode)
+	if err != nil {
+		c.Log.Errorf("person.List error %v", err)
+		c.TemplateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	if len(persons) == 0 {
+		c.TemplateReply("reply.person.empty", nil)
+		return
+	}
+	var data []map[string]interface{}
+	for _, p := range persons {
+		var members []map[string]interface{}
+		for _, member := range p.Members {
+			members = append(members, map[string]interface{}{
+				"site":   member.Site,
+				"name":   personMemberName(member),
+				"living": person.IsLiving(groupCode, member.Site, member.Id),
+			})
+		}
+		data = append(data, map[string]interface{}{
+			"alias":   p.Alias,
+			"members": members,
+		})
+	}
+	c.TemplateSend("reply.person.list", map[string]interface{}{
+		"persons": data,
+	})
 }
 
 func IEnable(c *MessageContext, groupCode int64, command string, disable bool) {
@@ -293,7 +808,7 @@ func IEnable(c *MessageContext, groupCode int64, command string, disable bool) {
 	}
 
 	if len(command) == 0 {
-		c.TextReply("失败 - 没有指定要操作的命令名")
+		c.TemplateReply("reply.missing_command", nil)
 		log.Errorf("empty command")
 		return
 	}
@@ -302,7 +817,9 @@ func IEnable(c *MessageContext, groupCode int64, command string, disable bool) {
 
 	if !CheckOperateableCommand(command) {
 		log.Errorf("non-operateable command")
-		c.TextReply(fmt.Sprintf("失败 - 【%v】无效命令", command))
+		c.TemplateReply("reply.invalid_command", map[string]interface{}{
+			"command": command,
+		})
 		return
 	}
 	if disable {
@@ -318,18 +835,20 @@ func IEnable(c *MessageContext, groupCode int64, command string, disable bool) {
 		}
 		if err == permission.ErrPermissionExist {
 			if disable {
-				c.TextReply("失败 - 该命令已经禁用过了，请不要重复禁用")
+				c.TemplateReply("reply.enable.already_disabled", nil)
 			} else {
-				c.TextReply("失败 - 该命令已经启用过了，请不要重复启用")
+				c.TemplateReply("reply.enable.already_enabled", nil)
 			}
 		} else {
-			c.TextReply(fmt.Sprintf("失败 - 内部错误"))
+			c.TemplateReply("reply.internal_error", nil)
 		}
 		return
 	}
-	c.TextReply("成功")
+	c.TemplateReply("reply.success", nil)
 }
 
+var errGrantMemberNotFound = errors.New("未找到用户")
+
 func IGrantRole(c *MessageContext, groupCode int64, grantRole permission.RoleType, grantTo int64, del bool) {
 	var err error
 	log := c.Log.WithField("role", grantRole.String()).WithFields(utils.GroupLogFields(groupCode))
@@ -350,7 +869,7 @@ func IGrantRole(c *MessageContext, groupCode int64, grantRole permission.RoleTyp
 			}
 		} else {
 			log.Errorf("can not find uin")
-			err = errors.New("未找到用户")
+			err = errGrantMemberNotFound
 		}
 	case permission.Admin:
 		if !c.Lsp.PermissionStateManager.RequireAny(
@@ -370,16 +889,20 @@ func IGrantRole(c *MessageContext, groupCode int64, grantRole permission.RoleTyp
 	if err != nil {
 		log.Errorf("grant failed %v", err)
 		if err == permission.ErrPermissionExist {
-			c.TextReply("失败 - 目标已有该权限")
+			c.TemplateReply("reply.grant.already_has", nil)
 		} else if err == permission.ErrPermissionNotExist {
-			c.TextReply("失败 - 目标未有该权限")
+			c.TemplateReply("reply.grant.not_has", nil)
+		} else if err == errGrantMemberNotFound {
+			c.TemplateReply("reply.grant.member_not_found", nil)
 		} else {
-			c.TextReply(fmt.Sprintf("失败 - %v", err))
+			c.TemplateReply("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 		}
 		return
 	}
 	log.Debug("grant success")
-	c.TextReply("成功")
+	c.TemplateReply("reply.success", nil)
 }
 
 func IGrantCmd(c *MessageContext, groupCode int64, command string, grantTo int64, del bool) {
@@ -398,7 +921,9 @@ func IGrantCmd(c *MessageContext, groupCode int64, command string, grantTo int64
 
 	if !CheckOperateableCommand(command) {
 		log.Errorf("unknown command")
-		c.TextReply(fmt.Sprintf("失败 - 【%v】无效命令", command))
+		c.TemplateReply("reply.invalid_command", map[string]interface{}{
+			"command": command,
+		})
 		return
 	}
 
@@ -410,7 +935,7 @@ func IGrantCmd(c *MessageContext, groupCode int64, command string, grantTo int64
 		}
 	} else {
 		log.Errorf("can not find uin")
-		err = errors.New("未找到用户")
+		err = errGrantMemberNotFound
 	}
 	if err != nil {
 		log.Errorf("grant failed %v", err)
@@ -419,16 +944,20 @@ func IGrantCmd(c *MessageContext, groupCode int64, command string, grantTo int64
 			return
 		}
 		if err == permission.ErrPermissionExist {
-			c.TextReply("失败 - 目标已有该权限")
+			c.TemplateReply("reply.grant.already_has", nil)
 		} else if err == permission.ErrPermissionNotExist {
-			c.TextReply("失败 - 目标未有该权限")
+			c.TemplateReply("reply.grant.not_has", nil)
+		} else if err == errGrantMemberNotFound {
+			c.TemplateReply("reply.grant.member_not_found", nil)
 		} else {
-			c.TextReply(fmt.Sprintf("失败 - %v", err))
+			c.TemplateReply("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 		}
 		return
 	}
 	log.Debug("grant success")
-	c.TextReply("成功")
+	c.TemplateReply("reply.success", nil)
 }
 
 func ISilenceCmd(c *MessageContext, groupCode int64, delete bool) {
@@ -446,9 +975,11 @@ func ISilenceCmd(c *MessageContext, groupCode int64, delete bool) {
 			err = c.Lsp.PermissionStateManager.GlobalSilence()
 		}
 		if err == nil {
-			c.TextReply("成功")
+			c.TemplateReply("reply.success", nil)
 		} else {
-			c.TextReply(fmt.Sprintf("失败 - %v", err))
+			c.TemplateReply("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 		}
 		return
 	}
@@ -462,7 +993,7 @@ func ISilenceCmd(c *MessageContext, groupCode int64, delete bool) {
 	}
 
 	if c.Lsp.PermissionStateManager.CheckGlobalSilence() {
-		c.TextReply("失败 - 管理员已开启全局设置，无法操作")
+		c.TemplateReply("reply.silence.global_locked", nil)
 		return
 	}
 
@@ -472,23 +1003,164 @@ func ISilenceCmd(c *MessageContext, groupCode int64, delete bool) {
 		err = c.Lsp.PermissionStateManager.GroupSilence(groupCode)
 	}
 	if err == nil {
-		c.TextReply("成功")
+		c.TemplateReply("reply.success", nil)
 	} else {
-		c.TextReply(fmt.Sprintf("失败 - %v", err))
+		c.TemplateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
 	}
 }
 
-func IConfigAtCmd(c *MessageContext, groupCode int64, id string, site string, ctype concern_type.Type, action string, QQ []int64) {
-	err := configCmdGroupCommonCheck(c, groupCode)
+// ILocaleCmd 设置群使用的语言，locale为空时查看当前的语言
+func ILocaleCmd(c *MessageContext, groupCode int64, locale string) {
+	if locale == "" {
+		c.TemplateReply("reply.locale.current", map[string]interface{}{
+			"locale":  c.Lsp.PermissionStateManager.GetGroupLocale(groupCode),
+			"locales": i18n.Locales(),
+		})
+		return
+	}
+
+	if !c.Lsp.PermissionStateManager.RequireAny(
+		permission.AdminRoleRequireOption(c.Sender.Uin),
+		permission.GroupAdminRoleRequireOption(groupCode, c.Sender.Uin),
+	) {
+		c.NoPermissionReply()
+		return
+	}
+
+	parsed, err := i18n.ParseLocale(locale)
+	if err != nil {
+		c.TemplateReply("reply.locale.not_supported", map[string]interface{}{
+			"locale":  locale,
+			"locales": i18n.Locales(),
+		})
+		return
+	}
+	if err = c.Lsp.PermissionStateManager.SetGroupLocale(groupCode, parsed); err != nil {
+		c.Log.Errorf("SetGroupLocale error %v", err)
+		c.TemplateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	c.TemplateReply("reply.locale.success", map[string]interface{}{
+		"locale": parsed,
+	})
+}
+
+// ICopyConcern 把fromGroup的所有订阅复制到toGroup，包括订阅的配置和别名，toGroup已经有的订阅会跳过
+func ICopyConcern(c *MessageContext, fromGroup int64, toGroup int64) {
+	if fromGroup == toGroup {
+		c.TemplateReply("reply.copy.same_group", nil)
+		return
+	}
+	if !c.Lsp.PermissionStateManager.RequireAny(
+		permission.AdminRoleRequireOption(c.Sender.Uin),
+		permission.GroupAdminRoleRequireOption(fromGroup, c.Sender.Uin),
+		permission.QQAdminRequireOption(fromGroup, c.Sender.Uin),
+	) {
+		c.NoPermissionReply()
+		return
+	}
+	var (
+		from    = mmsg.NewGroupTarget(fromGroup)
+		to      = mmsg.NewGroupTarget(toGroup)
+		copied  int
+		skipped int
+		failed  []map[string]interface{}
+	)
+	// 复制到的群与watch命令相同，禁用了watch命令的群不能接收订阅
+	if !checkWatchPermission(c, to) {
+		return
+	}
+	// 禁用了config命令的群不复制订阅的配置，使用默认配置
+	copyConfig := !c.Lsp.PermissionStateManager.CheckGroupCommandDisabled(toGroup, ConfigCommand)
+	for _, cm := range concern.ListConcern() {
+		sm := cm.GetStateManager()
+		_, ids, ctypes, err := sm.ListConcernState(func(_target mmsg.Target, _ interface{}, _ concern_type.Type) bool {
+			return mmsg.TargetEqual(from, _target)
+		})
+		if err == nil {
+			ids, ctypes, err = sm.GroupTypeById(ids, ctypes)
+		}
+		if err != nil {
+			c.Log.WithField("site", cm.Site()).Errorf("ListConcernState error %v", err)
+			failed = append(failed, map[string]interface{}{
+				"site":   cm.Site(),
+				"id":     "*",
+				"reason": c.TemplateText(errorTemplate(err)),
+			})
+			continue
+		}
+		for idx, id := range ids {
+			ctype := ctypes[idx]
+			old, _ := sm.GetGroupConcern(to, id)
+			if old.ContainAll(ctype) {
+				skipped++
+				continue
+			}
+			if _, err = sm.AddGroupConcern(to, id, ctype); err != nil {
+				c.Log.WithField("site", cm.Site()).WithField("id", id).Errorf("AddGroupConcern error %v", err)
+				failed = append(failed, map[string]interface{}{
+					"site":   cm.Site(),
+					"id":     id,
+					"reason": c.TemplateText(errorTemplate(err)),
+				})
+				continue
+			}
+			copied++
+			log := c.Log.WithField("site", cm.Site()).WithField("id", id)
+			releaseShadowAlias(c, cm, to, fmt.Sprint(id), id)
+			if !old.Empty() {
+				// 已经有部分订阅时保留原有的配置和别名
+				continue
+			}
+			if copyConfig {
+				err = sm.OperateGroupConcernConfig(to, id, sm.GetGroupConcernConfig(from, id), func(concern.IConfig) bool {
+					return true
+				})
+				if err != nil {
+					log.Errorf("OperateGroupConcernConfig error %v", err)
+				}
+			}
+			if a, err := alias.Get(fromGroup, cm.Site(), id); err == nil {
+				// 与watch相同，别名冲突或者与其他订阅的id相同时不复制别名
+				_, err = alias.SetDefault(toGroup, cm.Site(), id, a, func(a string) bool {
+					return aliasShadowsId(cm, to, id, a)
+				})
+				if err != nil && err != alias.ErrAliasConflict {
+					log.Errorf("alias.SetDefault error %v", err)
+				}
+			} else if err != alias.ErrAliasNotFound {
+				log.Errorf("alias.Get error %v", err)
+			}
+		}
+	}
+	c.TemplateReply("reply.copy.success", map[string]interface{}{
+		"from":    fromGroup,
+		"to":      toGroup,
+		"copied":  copied,
+		"skipped": skipped,
+		"failed":  failed,
+	})
+}
+
+func IConfigAtCmd(c *MessageContext, target mmsg.Target, id string, site string, ctype concern_type.Type, action string, QQ []int64) {
+	if target.TargetType().IsPrivate() {
+		c.TemplateReply("reply.config.at_private", nil)
+		return
+	}
+	err := configCmdGroupCommonCheck(c, target)
 	if err == nil {
 		if action != "show" && action != "clear" && len(QQ) == 0 {
-			c.TextReply("失败 - 没有要操作的指定QQ号")
+			c.TemplateReply("reply.config.at.missing_qq", nil)
 			return
 		}
 		if action == "add" {
-			g := utils.GetBot().FindGroup(groupCode)
+			g := utils.GetBot().FindGroup(target.TargetCode())
 			if g == nil {
-				c.TextReply("失败 - 无法找到这个群的信息，如果看到这个信息表示bot出现了一些问题")
+				c.TemplateReply("reply.config.at.group_not_found", nil)
 				// 可能没找到吗
 				return
 			}
@@ -500,68 +1172,202 @@ func IConfigAtCmd(c *MessageContext, groupCode int64, id string, site string, ct
 				}
 			}
 			if len(failed) != 0 {
-				c.TextReply(fmt.Sprintf("失败 - 没有找到QQ号：\n%v", utils.JoinInt64(failed, "\n")))
+				c.TemplateReply("reply.config.at.member_not_found", map[string]interface{}{
+					"qq": failed,
+				})
 				return
 			}
 		}
-		err = iConfigCmd(c, groupCode, id, site, ctype, operateAtConcernConfig(c, ctype, action, QQ))
+		err = iConfigCmd(c, target, id, site, ctype, operateAtConcernConfig(c, ctype, action, QQ))
 	}
 	if localdb.IsRollback(err) || permission.IsPermissionError(err) {
 		return
 	}
 	if err != nil {
-		c.TextReply(err.Error())
+		replyErr(c, err)
 	} else {
 		if action != "show" {
-			ReplyUserInfo(c, id, site, ctype)
+			ReplyUserInfo(c, target, id, site, ctype)
 		}
 	}
 }
 
-func IConfigAtAllCmd(c *MessageContext, groupCode int64, id string, site string, ctype concern_type.Type, on bool) {
-	err := iConfigCmd(c, groupCode, id, site, ctype, operateAtAllConcernConfig(c, ctype, on))
+func IConfigAtAllCmd(c *MessageContext, target mmsg.Target, id string, site string, ctype concern_type.Type, on bool) {
+	if target.TargetType().IsPrivate() {
+		c.TemplateReply("reply.config.at_private", nil)
+		return
+	}
+	err := iConfigCmd(c, target, id, site, ctype, operateAtAllConcernConfig(c, ctype, on))
+	if localdb.IsRollback(err) || permission.IsPermissionError(err) {
+		return
+	}
+	if err != nil {
+		replyErr(c, err)
+	} else {
+		ReplyUserInfo(c, target, id, site, ctype)
+	}
+}
+
+func IConfigTitleNotifyCmd(c *MessageContext, target mmsg.Target, id string, site string, ctype concern_type.Type, on bool) {
+	err := iConfigCmd(c, target, id, site, ctype, operateNotifyConcernConfig(c, ctype, on))
 	if localdb.IsRollback(err) || permission.IsPermissionError(err) {
 		return
 	}
 	if err != nil {
-		c.TextReply(err.Error())
+		replyErr(c, err)
 	} else {
-		ReplyUserInfo(c, id, site, ctype)
+		ReplyUserInfo(c, target, id, site, ctype)
 	}
 }
 
-func IConfigTitleNotifyCmd(c *MessageContext, groupCode int64, id string, site string, ctype concern_type.Type, on bool) {
-	err := iConfigCmd(c, groupCode, id, site, ctype, operateNotifyConcernConfig(c, ctype, on))
+func IConfigOfflineNotifyCmd(c *MessageContext, target mmsg.Target, id string, site string, ctype concern_type.Type, on bool) {
+	err := iConfigCmd(c, target, id, site, ctype, operateOfflineNotifyConcernConfig(c, ctype, on))
+	if localdb.IsRollback(err) || permission.IsPermissionError(err) {
+		return
+	}
+	if err != nil {
+		replyErr(c, err)
+	} else {
+		ReplyUserInfo(c, target, id, site, ctype)
+	}
+}
+
+func IConfigMediaCmd(c *MessageContext, target mmsg.Target, id string, site string, ctype concern_type.Type, on bool, folder string, video bool, link bool) {
+	err := iConfigCmd(c, target, id, site, ctype, func(config concern.IConfig) bool {
+		mediaConfig := config.GetGroupConcernMedia()
+		if !on {
+			if !mediaConfig.CheckArchive(ctype) {
+				c.TemplateReply("reply.config.not_set", nil)
+				return false
+			}
+			mediaConfig.Archive = mediaConfig.Archive.Remove(ctype)
+			return true
+		}
+		mediaConfig.Archive = mediaConfig.Archive.Add(ctype)
+		if folder != "" {
+			mediaConfig.Folder = folder
+		}
+		mediaConfig.Video = video
+		mediaConfig.Link = link
+		return true
+	})
 	if localdb.IsRollback(err) || permission.IsPermissionError(err) {
 		return
 	}
 	if err != nil {
-		c.TextReply(err.Error())
+		replyErr(c, err)
 	} else {
-		ReplyUserInfo(c, id, site, ctype)
+		ReplyUserInfo(c, target, id, site, ctype)
 	}
 }
 
-func IConfigOfflineNotifyCmd(c *MessageContext, groupCode int64, id string, site string, ctype concern_type.Type, on bool) {
-	err := iConfigCmd(c, groupCode, id, site, ctype, operateOfflineNotifyConcernConfig(c, ctype, on))
+func IConfigDeletedCmd(c *MessageContext, target mmsg.Target, id string, site string, ctype concern_type.Type, action string) {
+	err := iConfigCmd(c, target, id, site, ctype, func(config concern.IConfig) bool {
+		deletedConfig := config.GetGroupConcernDeleted()
+		if action == "off" {
+			if deletedConfig.GetAction() == concern.DeletedActionNone {
+				c.TemplateReply("reply.config.not_set", nil)
+				return false
+			}
+			deletedConfig.Action = concern.DeletedActionNone
+			return true
+		}
+		deletedConfig.Action = concern.DeletedAction(action)
+		return true
+	})
 	if localdb.IsRollback(err) || permission.IsPermissionError(err) {
 		return
 	}
 	if err != nil {
-		c.TextReply(err.Error())
+		replyErr(c, err)
 	} else {
-		ReplyUserInfo(c, id, site, ctype)
+		ReplyUserInfo(c, target, id, site, ctype)
+	}
+}
+
+// IConfigAliasCmd 配置订阅在群内的别名，aliasName为空时删除别名
+func IConfigAliasCmd(c *MessageContext, target mmsg.Target, id string, site string, aliasName string) {
+	if target.TargetType().IsPrivate() {
+		c.TemplateReply("reply.config.alias.private", nil)
+		return
+	}
+	err := configCmdGroupCommonCheck(c, target)
+	if err == nil {
+		err = iConfigAlias(c, target, id, site, aliasName)
+	}
+	if permission.IsPermissionError(err) {
+		return
 	}
+	if err != nil {
+		replyErr(c, err)
+	}
+}
+
+func iConfigAlias(c *MessageContext, target mmsg.Target, id string, site string, aliasName string) error {
+	cm, err := concern.GetConcernBySite(site)
+	if err != nil {
+		return newReplyError("reply.failed", map[string]interface{}{
+			"error": err,
+		})
+	}
+	mid, err := parseConcernId(cm, target, id)
+	if err != nil {
+		return newReplyError("reply.config.parse_id_failed", map[string]interface{}{
+			"site":  cm.Site(),
+			"error": err,
+		})
+	}
+	if ctype, err := cm.GetStateManager().GetGroupConcern(target, mid); err != nil || ctype.Empty() {
+		return newReplyError("reply.config.not_watched", nil)
+	}
+	groupCode := target.TargetCode()
+	if len(aliasName) == 0 {
+		if err = alias.Remove(groupCode, cm.Site(), mid); err != nil {
+			c.GetLog().Errorf("alias.Remove error %v", err)
+			return newReplyError("reply.failed", map[string]interface{}{
+				"error": err,
+			})
+		}
+		c.TemplateReply("reply.config.alias.removed", nil)
+		return nil
+	}
+	if aliasShadowsId(cm, target, mid, aliasName) {
+		return newReplyError("reply.config.alias.conflict", map[string]interface{}{
+			"alias": aliasName,
+		})
+	}
+	if err = alias.Set(groupCode, cm.Site(), mid, aliasName); err == alias.ErrAliasConflict {
+		return newReplyError("reply.config.alias.conflict", map[string]interface{}{
+			"alias": aliasName,
+		})
+	} else if err != nil {
+		if err != alias.ErrInvalidAlias {
+			c.GetLog().Errorf("alias.Set error %v", err)
+		}
+		return newReplyError("reply.failed", map[string]interface{}{
+			"error": err,
+		})
+	}
+	var name string
+	if info, err := cm.Get(mid); err == nil && info != nil {
+		name = info.GetName()
+	}
+	c.TemplateReply("reply.config.alias.success", map[string]interface{}{
+		"site":  cm.Site(),
+		"name":  name,
+		"alias": aliasName,
+	})
+	return nil
 }
 
-func IConfigFilterCmdType(c *MessageContext, groupCode int64, id string, site string, ctype concern_type.Type, types []string) {
-	err := configCmdGroupCommonCheck(c, groupCode)
+func IConfigFilterCmdType(c *MessageContext, target mmsg.Target, id string, site string, ctype concern_type.Type, types []string) {
+	err := configCmdGroupCommonCheck(c, target)
 	if err == nil {
 		if len(types) == 0 {
-			c.TextReply("失败 - 没有指定过滤类型")
+			c.TemplateReply("reply.config.filter.missing_type", nil)
 			return
 		}
-		err = iConfigCmd(c, groupCode, id, site, ctype, func(config concern.IConfig) bool {
+		err = iConfigCmd(c, target, id, site, ctype, func(config concern.IConfig) bool {
 			config.GetGroupConcernFilter().Type = concern.FilterTypeType
 			filterConfig := &concern.GroupConcernFilterConfigByType{Type: types}
 			config.GetGroupConcernFilter().Config = filterConfig.ToString()
@@ -572,21 +1378,21 @@ func IConfigFilterCmdType(c *MessageContext, groupCode int64, id string, site st
 		return
 	}
 	if err != nil {
-		c.TextReply(err.Error())
+		replyErr(c, err)
 	} else {
-		ReplyUserInfo(c, id, site, ctype)
+		ReplyUserInfo(c, target, id, site, ctype)
 	}
 }
 
-func IConfigFilterCmdNotType(c *MessageContext, groupCode int64, id string, site string, ctype concern_type.Type, types []string) {
-	err := configCmdGroupCommonCheck(c, groupCode)
+func IConfigFilterCmdNotType(c *MessageContext, target mmsg.Target, id string, site string, ctype concern_type.Type, types []string) {
+	err := configCmdGroupCommonCheck(c, target)
 	if err == nil {
 
 		if len(types) == 0 {
-			c.TextReply("失败 - 没有指定过滤类型")
+			c.TemplateReply("reply.config.filter.missing_type", nil)
 			return
 		}
-		err = iConfigCmd(c, groupCode, id, site, ctype, func(config concern.IConfig) bool {
+		err = iConfigCmd(c, target, id, site, ctype, func(config concern.IConfig) bool {
 			config.GetGroupConcernFilter().Type = concern.FilterTypeNotType
 			filterConfig := &concern.GroupConcernFilterConfigByType{Type: types}
 			config.GetGroupConcernFilter().Config = filterConfig.ToString()
@@ -597,20 +1403,20 @@ func IConfigFilterCmdNotType(c *MessageContext, groupCode int64, id string, site
 		return
 	}
 	if err != nil {
-		c.TextReply(err.Error())
+		replyErr(c, err)
 	} else {
-		ReplyUserInfo(c, id, site, ctype)
+		ReplyUserInfo(c, target, id, site, ctype)
 	}
 }
 
-func IConfigFilterCmdText(c *MessageContext, groupCode int64, id string, site string, ctype concern_type.Type, keywords []string) {
-	err := configCmdGroupCommonCheck(c, groupCode)
+func IConfigFilterCmdText(c *MessageContext, target mmsg.Target, id string, site string, ctype concern_type.Type, keywords []string) {
+	err := configCmdGroupCommonCheck(c, target)
 	if err == nil {
 		if len(keywords) == 0 {
-			c.TextReply("失败 - 没有指定过滤关键字")
+			c.TemplateReply("reply.config.filter.missing_keyword", nil)
 			return
 		}
-		err = iConfigCmd(c, groupCode, id, site, ctype, func(config concern.IConfig) bool {
+		err = iConfigCmd(c, target, id, site, ctype, func(config concern.IConfig) bool {
 			config.GetGroupConcernFilter().Type = concern.FilterTypeText
 			filterConfig := &concern.GroupConcernFilterConfigByText{Text: keywords}
 			config.GetGroupConcernFilter().Config = filterConfig.ToString()
@@ -621,14 +1427,14 @@ func IConfigFilterCmdText(c *MessageContext, groupCode int64, id string, site st
 		return
 	}
 	if err != nil {
-		c.TextReply(err.Error())
+		replyErr(c, err)
 	} else {
-		ReplyUserInfo(c, id, site, ctype)
+		ReplyUserInfo(c, target, id, site, ctype)
 	}
 }
 
-func IConfigFilterCmdClear(c *MessageContext, groupCode int64, id string, site string, ctype concern_type.Type) {
-	err := iConfigCmd(c, groupCode, id, site, ctype, func(config concern.IConfig) bool {
+func IConfigFilterCmdClear(c *MessageContext, target mmsg.Target, id string, site string, ctype concern_type.Type) {
+	err := iConfigCmd(c, target, id, site, ctype, func(config concern.IConfig) bool {
 		*config.GetGroupConcernFilter() = concern.GroupConcernFilterConfig{}
 		return true
 	})
@@ -636,65 +1442,54 @@ func IConfigFilterCmdClear(c *MessageContext, groupCode int64, id string, site s
 		return
 	}
 	if err != nil {
-		c.TextReply(err.Error())
+		replyErr(c, err)
 	} else {
-		ReplyUserInfo(c, id, site, ctype)
+		ReplyUserInfo(c, target, id, site, ctype)
 	}
 }
 
-func IConfigFilterCmdShow(c *MessageContext, groupCode int64, id string, site string, ctype concern_type.Type) {
-	err := iConfigCmd(c, groupCode, id, site, ctype, func(config concern.IConfig) bool {
+func IConfigFilterCmdShow(c *MessageContext, target mmsg.Target, id string, site string, ctype concern_type.Type) {
+	err := iConfigCmd(c, target, id, site, ctype, func(config concern.IConfig) bool {
 		if config.GetGroupConcernFilter().Empty() {
-			c.TextReply("当前配置为空")
+			c.TemplateReply("reply.config.empty", nil)
 			return false
 		}
-		sb := strings.Builder{}
-		sb.WriteString("当前配置：\n")
+		var data = map[string]interface{}{
+			"type": config.GetGroupConcernFilter().Type,
+		}
 		switch config.GetGroupConcernFilter().Type {
 		case concern.FilterTypeText:
-			sb.WriteString("关键字过滤模式：\n")
 			filter, err := config.GetGroupConcernFilter().GetFilterByText()
 			if err != nil {
 				logger.WithField("filter_config", config.GetGroupConcernFilter().Config).Errorf("get filter failed %v", err)
-				c.TextReply("查询失败 - 内部错误")
+				c.TemplateReply("reply.config.query_failed", nil)
 				return false
 			}
-			for _, kw := range filter.Text {
-				sb.WriteString(kw)
-				sb.WriteRune('\n')
-			}
+			data["keywords"] = filter.Text
 		case concern.FilterTypeType, concern.FilterTypeNotType:
 			filter, err := config.GetGroupConcernFilter().GetFilterByType()
 			if err != nil {
 				logger.WithField("filter_config", config.GetGroupConcernFilter().Config).Errorf("get filter failed %v", err)
-				c.TextReply("查询失败 - 内部错误")
+				c.TemplateReply("reply.config.query_failed", nil)
 				return false
 			}
-			if config.GetGroupConcernFilter().Type == concern.FilterTypeType {
-				sb.WriteString("动态类型过滤模式 - 只推送以下种类的动态：\n")
-			} else if config.GetGroupConcernFilter().Type == concern.FilterTypeNotType {
-				sb.WriteString("动态类型过滤模式 - 不推送以下种类的动态：\n")
-			}
-			for _, tp := range filter.Type {
-				sb.WriteString(tp)
-				sb.WriteRune('\n')
-			}
+			data["types"] = filter.Type
 		}
-		c.TextReply(sb.String())
+		c.TemplateReply("reply.config.filter.show", data)
 		return false
 	})
 	if localdb.IsRollback(err) || permission.IsPermissionError(err) {
 		return
 	}
 	if err != nil {
-		c.TextReply(err.Error())
+		replyErr(c, err)
 	} else {
-		ReplyUserInfo(c, id, site, ctype)
+		ReplyUserInfo(c, target, id, site, ctype)
 	}
 }
 
-func iConfigCmd(c *MessageContext, groupCode int64, id string, site string, ctype concern_type.Type, f func(config concern.IConfig) bool) (err error) {
-	if err = configCmdGroupCommonCheck(c, groupCode); err != nil {
+func iConfigCmd(c *MessageContext, target mmsg.Target, id string, site string, ctype concern_type.Type, f func(config concern.IConfig) bool) (err error) {
+	if err = configCmdGroupCommonCheck(c, target); err != nil {
 		return err
 	}
 	if !sliceutil.Contains(concern.ListSite(), site) {
@@ -703,50 +1498,119 @@ func iConfigCmd(c *MessageContext, groupCode int64, id string, site string, ctyp
 	cm, err := concern.GetConcernBySiteAndType(site, ctype)
 	if err != nil {
 		c.GetLog().Errorf("GetConcernManager error %v", err)
-		c.TextReply(fmt.Sprintf("失败 - %v", err))
+		c.TemplateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
 		return
 	}
-	mid, err := cm.ParseId(id)
+	mid, err := parseConcernId(cm, target, id)
 	if err != nil {
-		return fmt.Errorf("%v解析Id失败 - %v", cm.Site(), err)
+		return newReplyError("reply.config.parse_id_failed", map[string]interface{}{
+			"site":  cm.Site(),
+			"error": err,
+		})
 	}
-	err = cm.GetStateManager().CheckGroupConcern(groupCode, mid, ctype)
+	err = cm.GetStateManager().CheckGroupConcern(target, mid, ctype)
 	if err != concern.ErrAlreadyExists {
-		return errors.New("失败 - 该id尚未watch")
+		return newReplyError("reply.config.not_watched", nil)
 	}
-	cfg := cm.GetStateManager().GetGroupConcernConfig(groupCode, mid)
-	err = cm.GetStateManager().OperateGroupConcernConfig(groupCode, mid, cfg, f)
+	cfg := cm.GetStateManager().GetGroupConcernConfig(target, mid)
+	err = cm.GetStateManager().OperateGroupConcernConfig(target, mid, cfg, f)
 	if err != nil && !localdb.IsRollback(err) {
 		c.GetLog().Errorf("OperateGroupConcernConfig failed %v", err)
-		err = fmt.Errorf("失败 - %v", err)
+		err = newReplyError("reply.failed", map[string]interface{}{
+			"error": err,
+		})
 	}
 	return
 }
 
-func ReplyUserInfo(c *MessageContext, id string, site string, ctype concern_type.Type) {
+// replyError 带有回复模板的错误，回复时使用模板而不是错误本身的文本
+type replyError struct {
+	name string
+	data map[string]interface{}
+}
+
+func newReplyError(name string, data map[string]interface{}) error {
+	return &replyError{name: name, data: data}
+}
+
+func (e *replyError) Error() string {
+	m, err := template.LoadAndExec(e.name, e.data)
+	if err != nil {
+		return e.name
+	}
+	return msgstringer.MsgToString(m.Elements())
+}
+
+// errorTemplate 返回回复错误时使用的模板，带有回复模板的错误使用它的模板
+func errorTemplate(err error) (string, map[string]interface{}) {
+	var re *replyError
+	if errors.As(err, &re) {
+		return re.name, re.data
+	}
+	return "reply.error", map[string]interface{}{
+		"error": err,
+	}
+}
+
+// localizeTemplateData 把模板变量中的错误翻译成 locale 对应的语言，
+// 模板中的错误都是直接输出的，翻译后不影响使用
+func localizeTemplateData(locale string, data map[string]interface{}) {
+	if i18n.IsDefault(locale) {
+		return
+	}
+	for k, v := range data {
+		if err, ok := v.(error); ok {
+			data[k] = i18n.Error(locale, err)
+		}
+	}
+}
+
+func replyErr(c *MessageContext, err error) {
+	c.TemplateReply(errorTemplate(err))
+}
+
+func ReplyUserInfo(c *MessageContext, target mmsg.Target, id string, site string, ctype concern_type.Type) {
 	cm, err := concern.GetConcernBySiteAndType(site, ctype)
 	if err != nil {
 		c.GetLog().Errorf("GetConcernManager error %v", err)
-		c.TextReply(fmt.Sprintf("失败 - %v", err))
+		c.TemplateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
 		return
 	}
-	mid, err := cm.ParseId(id)
+	mid, err := parseConcernId(cm, target, id)
 	if err != nil {
 		c.Log.Errorf("ReplyUserInfo %v got wrong id %v", site, id)
-		c.TextReply(fmt.Sprintf("成功 - %v用户", site))
+		c.TemplateReply("reply.user_info", map[string]interface{}{
+			"site": site,
+		})
 		return
 	}
 	info, err := cm.Get(mid)
 	if err != nil || info == nil {
 		c.Log.Errorf("ReplyUserInfo %v Get IdentityInfo error %v", site, err)
-		c.TextReply(fmt.Sprintf("成功 - %v用户", site))
+		c.TemplateReply("reply.user_info", map[string]interface{}{
+			"site": site,
+		})
 		return
 	}
 	c.Log.WithField("name", info.GetName()).Debug("reply user info")
-	c.TextReply(fmt.Sprintf("成功 - %v用户 %v", site, info.GetName()))
+	c.TemplateReply("reply.user_info", map[string]interface{}{
+		"site": site,
+		"name": info.GetName(),
+	})
 }
 
-func configCmdGroupCommonCheck(c *MessageContext, groupCode int64) error {
+func configCmdGroupCommonCheck(c *MessageContext, target mmsg.Target) error {
+	if target.TargetType().IsPrivate() {
+		if !checkPrivateTargetPermission(c, target) {
+			return permission.ErrPermissionDenied
+		}
+		return nil
+	}
+	groupCode := target.TargetCode()
 	if c.Lsp.PermissionStateManager.CheckGroupCommandDisabled(groupCode, ConfigCommand) {
 		c.DisabledReply()
 		return permission.ErrDisabled
@@ -779,14 +1643,16 @@ func operateAtConcernConfig(c *MessageContext, ctype concern_type.Type, action s
 		case "show":
 			qqList := concernConfig.GetGroupConcernAt().GetAtSomeoneList(ctype)
 			if len(qqList) == 0 {
-				c.TextReply("当前配置为空")
+				c.TemplateReply("reply.config.empty", nil)
 				return false
 			}
-			c.TextReply(fmt.Sprintf("当前配置：\n%v", utils.JoinInt64(qqList, "\n")))
+			c.TemplateReply("reply.config.at.show", map[string]interface{}{
+				"qq": qqList,
+			})
 			return false
 		default:
 			c.Log.Errorf("unknown action")
-			c.TextReply("失败 - 未知操作")
+			c.TemplateReply("reply.config.unknown_action", nil)
 			return false
 		}
 	}
@@ -797,7 +1663,7 @@ func operateAtAllConcernConfig(c *MessageContext, ctype concern_type.Type, on bo
 		if concernConfig.GetGroupConcernAt().CheckAtAll(ctype) {
 			if on {
 				// 配置@all，但已经配置了
-				c.TextReply("失败 - 已经配置过了")
+				c.TemplateReply("reply.config.already_set", nil)
 				return false
 			} else {
 				// 取消配置@all
@@ -807,7 +1673,7 @@ func operateAtAllConcernConfig(c *MessageContext, ctype concern_type.Type, on bo
 		} else {
 			if !on {
 				// 取消配置，但并没有配置
-				c.TextReply("失败 - 该配置未设置")
+				c.TemplateReply("reply.config.not_set", nil)
 				return false
 			} else {
 				// 配置@all
@@ -823,7 +1689,7 @@ func operateNotifyConcernConfig(c *MessageContext, ctype concern_type.Type, on b
 		if concernConfig.GetGroupConcernNotify().CheckTitleChangeNotify(ctype) {
 			if on {
 				// 配置推送，但已经配置过了
-				c.TextReply("失败 - 已经配置过了")
+				c.TemplateReply("reply.config.already_set", nil)
 				return false
 			} else {
 				// 取消配置推送
@@ -833,7 +1699,7 @@ func operateNotifyConcernConfig(c *MessageContext, ctype concern_type.Type, on b
 		} else {
 			if !on {
 				// 取消配置，但并没有配置
-				c.TextReply("失败 - 该配置未设置")
+				c.TemplateReply("reply.config.not_set", nil)
 				return false
 			} else {
 				// 配置推送
@@ -849,7 +1715,7 @@ func operateOfflineNotifyConcernConfig(c *MessageContext, ctype concern_type.Typ
 		if concernConfig.GetGroupConcernNotify().CheckOfflineNotify(ctype) {
 			if on {
 				// 配置推送，但已经配置过了
-				c.TextReply("失败 - 已经配置过了")
+				c.TemplateReply("reply.config.already_set", nil)
 				return false
 			} else {
 				// 取消配置推送
@@ -859,7 +1725,7 @@ func operateOfflineNotifyConcernConfig(c *MessageContext, ctype concern_type.Typ
 		} else {
 			if !on {
 				// 取消配置，但并没有配置
-				c.TextReply("失败 - 该配置未设置")
+				c.TemplateReply("reply.config.not_set", nil)
 				return false
 			} else {
 				concernConfig.GetGroupConcernNotify().OfflineNotify = concernConfig.GetGroupConcernNotify().OfflineNotify.Add(ctype)
@@ -884,12 +1750,16 @@ func IAbnormalConcernCheck(c *MessageContext) {
 
 	var allConcernGroups = make(map[int64]int)
 	for _, cm := range concern.ListConcern() {
-		_, _, _, err := cm.GetStateManager().ListConcernState(func(groupCode int64, id interface{}, p concern_type.Type) bool {
-			allConcernGroups[groupCode] += 1
+		_, _, _, err := cm.GetStateManager().ListConcernState(func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
+			if target.TargetType().IsGroup() {
+				allConcernGroups[target.TargetCode()] += 1
+			}
 			return true
 		})
 		if err != nil {
-			c.TextReply(fmt.Sprintf("失败 - %v", err))
+			c.TemplateReply("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return
 		}
 	}
@@ -901,26 +1771,22 @@ func IAbnormalConcernCheck(c *MessageContext) {
 		}
 	}
 
-	var m = mmsg.NewMSG()
-
-	if len(utils.GetBot().GetGroupList()) > 900 {
-		m.Textf("警告：当前账号加入超过900个群，由于QQ本身的限制，可能会将正常的群显示为异常！请注意确认。")
-	}
-
-	if len(unknownGroups) == 0 {
-		m.Textf("没有查询到异常群号")
-	} else {
-		// 让结果稳定
-		sort.Slice(unknownGroups, func(i, j int) bool {
-			return unknownGroups[i][0] < unknownGroups[j][0]
+	// 让结果稳定
+	sort.Slice(unknownGroups, func(i, j int) bool {
+		return unknownGroups[i][0] < unknownGroups[j][0]
+	})
+	var groups []map[string]interface{}
+	for _, pair := range unknownGroups {
+		groups = append(groups, map[string]interface{}{
+			"code":  pair[0],
+			"count": pair[1],
 		})
-		m.Textf("共查询到%v个异常群号:\n", len(unknownGroups))
-		for _, pair := range unknownGroups {
-			m.Textf("群 %v - %v个订阅\n", pair[0], pair[1])
-		}
-		m.Textf("可以使用<%v --abnormal>命令清除异常群订阅", c.Lsp.CommandShowName(CleanConcern))
 	}
-	c.Send(m)
+	c.TemplateSend("reply.abnormal.result", map[string]interface{}{
+		"too_many_groups": len(utils.GetBot().GetGroupList()) > 900,
+		"groups":          groups,
+		"command":         c.Lsp.CommandShowName(CleanConcern),
+	})
 }
 
 func ICleanConcern(c *MessageContext, abnormal bool, groupCodes []int64, rawSite string, rawType string) {
@@ -935,12 +1801,12 @@ func ICleanConcern(c *MessageContext, abnormal bool, groupCodes []int64, rawSite
 
 	if abnormal {
 		if len(groupCodes) != 0 {
-			c.TextReply("失败 - 无法同时清除异常订阅和指定群订阅，请重新操作。")
+			c.TemplateReply("reply.clean.conflict", nil)
 			return
 		}
 	} else {
 		if len(groupCodes) == 0 {
-			c.TextReply("失败 - 请指定要清除的群号码")
+			c.TemplateReply("reply.clean.missing_group", nil)
 			return
 		}
 	}
@@ -977,7 +1843,9 @@ func ICleanConcern(c *MessageContext, abnormal bool, groupCodes []int64, rawSite
 		if len(rawSite) > 0 {
 			site, err = concern.ParseRawSite(rawSite)
 			if err != nil {
-				c.TextReply(fmt.Sprintf("失败 - %v", err))
+				c.TemplateReply("reply.failed", map[string]interface{}{
+					"error": err,
+				})
 				return
 			}
 			if site != cm.Site() {
@@ -998,11 +1866,12 @@ func ICleanConcern(c *MessageContext, abnormal bool, groupCodes []int64, rawSite
 			site: site,
 			tp:   tp,
 		})
-		_, _, _, err = cm.GetStateManager().ListConcernState(func(groupCode int64, id interface{}, p concern_type.Type) bool {
+		_, _, _, err = cm.GetStateManager().ListConcernState(func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
 			var itp = p.Intersection(tp)
-			if itp.Empty() {
+			if itp.Empty() || !target.TargetType().IsGroup() {
 				return true
 			}
+			groupCode := target.TargetCode()
 			if abnormal {
 				if _, found := allGroups[groupCode]; found {
 					return true
@@ -1020,7 +1889,9 @@ func ICleanConcern(c *MessageContext, abnormal bool, groupCodes []int64, rawSite
 			return true
 		})
 		if err != nil {
-			c.TextReply(fmt.Sprintf("失败 - %v", err))
+			c.TemplateReply("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return
 		}
 	}
@@ -1029,20 +1900,216 @@ func ICleanConcern(c *MessageContext, abnormal bool, groupCodes []int64, rawSite
 	for site, items := range itemMap {
 		cm, err := concern.GetConcernBySite(site)
 		if err != nil {
-			c.TextReply(fmt.Sprintf("失败 - %v", err))
+			c.TemplateReply("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return
 		}
 		for _, item := range items {
-			_, err = cm.Remove(c, item.groupCode, item.id, item.tp)
+			_, err = cm.Remove(c, mmsg.NewGroupTarget(item.groupCode), item.id, item.tp)
 			if err == buntdb.ErrNotFound {
 				continue
 			} else if err != nil {
-				c.TextReply(fmt.Sprintf("失败 - %v", err))
+				c.TemplateReply("reply.failed", map[string]interface{}{
+					"error": err,
+				})
 				return
 			}
 			count++
 		}
 	}
 
-	c.TextSend(fmt.Sprintf("成功 - 共清除%v个订阅", count))
+	c.TemplateSend("reply.clean.success", map[string]interface{}{
+		"count": count,
+	})
+}
+
+// ISearch 搜索群消息存档，fetch大于0时先从协议端拉取最近的fetch条消息补全存档
+func ISearch(c *MessageContext, groupCode int64, query *msgarchive.Query, fetch int) {
+	log := c.GetLog().WithFields(utils.GroupLogFields(groupCode)).
+		WithField("keyword", query.Keyword).
+		WithField("uin", query.Uin)
+
+	if !c.Lsp.PermissionStateManager.RequireAny(
+		permission.AdminRoleRequireOption(c.Sender.Uin),
+		permission.GroupAdminRoleRequireOption(groupCode, c.Sender.Uin),
+	) {
+		c.NoPermissionReply()
+		return
+	}
+
+	if !cfg.GetMessageArchiveEnable() {
+		c.TemplateReply("reply.search.disabled", nil)
+		return
+	}
+
+	query.GroupCode = groupCode
+	if fetch > 0 {
+		added, err := msgarchive.Backfill(groupCode, fetch)
+		if err != nil {
+			log.Errorf("msgarchive.Backfill error %v", err)
+			c.TemplateReply("reply.search.fetch_failed", map[string]interface{}{
+				"error": err,
+			})
+		} else {
+			log.Debugf("backfill %v messages", added)
+		}
+	}
+
+	records, err := msgarchive.Search(query)
+	if err != nil {
+		log.Errorf("msgarchive.Search error %v", err)
+		c.TemplateReply("reply.internal_error", nil)
+		return
+	}
+	if len(records) == 0 {
+		c.TemplateReply("reply.search.empty", nil)
+		return
+	}
+	var data []map[string]interface{}
+	for _, r := range records {
+		content := []rune(r.Content)
+		if len(content) > 100 {
+			content = append(content[:100], []rune("...")...)
+		}
+		data = append(data, map[string]interface{}{
+			"time":    time.Unix(r.Time, 0),
+			"name":    r.Name,
+			"uin":     r.Uin,
+			"content": string(content),
+		})
+	}
+	c.TemplateReply("reply.search.result", map[string]interface{}{
+		"records": data,
+	})
+}
+
+// newSearchQuery 解析search命令的参数
+func newSearchQuery(keyword string, uin int64, since string, until string, limit int) (*msgarchive.Query, error) {
+	var (
+		now   = time.Now()
+		query = &msgarchive.Query{
+			Keyword: keyword,
+			Uin:     uin,
+			Limit:   limit,
+		}
+		err error
+	)
+	if query.Since, err = msgarchive.ParseTime(since, now); err != nil {
+		return nil, err
+	}
+	if query.Until, err = msgarchive.ParseTime(until, now); err != nil {
+		return nil, err
+	}
+	if query.Limit > 50 {
+		query.Limit = 50
+	}
+	return query, nil
+}
+
+// maxPreviewLimit preview命令最多预览的条数
+const maxPreviewLimit = 10
+
+// IPreview 获取订阅最新的内容，按订阅目标的配置检查每一条是否会推送，不会真正推送
+// render 为true时会把每一条推送的内容私聊发送给操作者
+func IPreview(c *MessageContext, target mmsg.Target, id string, site string, ctype concern_type.Type, limit int, render bool) {
+	log := c.GetLog().WithFields(mmsg.TargetLogFields(target)).
+		WithField("site", site).
+		WithField("id", id)
+
+	if err := configCmdGroupCommonCheck(c, target); err != nil {
+		return
+	}
+	cm, err := concern.GetConcernBySiteAndType(site, ctype)
+	if err != nil {
+		log.Errorf("GetConcernManager error %v", err)
+		c.TemplateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	previewer, ok := cm.(concern.PreviewExt)
+	if !ok {
+		c.TemplateReply("reply.preview.not_supported", map[string]interface{}{
+			"site": cm.Site(),
+		})
+		return
+	}
+	mid, err := parseConcernId(cm, target, id)
+	if err != nil {
+		log.Errorf("Parseid error %v", err)
+		c.TemplateReply("reply.parse_id_failed", map[string]interface{}{
+			"site": cm.Site(),
+		})
+		return
+	}
+	if limit <= 0 || limit > maxPreviewLimit {
+		limit = maxPreviewLimit
+	}
+	notifies, err := previewer.Preview(target, mid, ctype, limit)
+	if err != nil {
+		log.Errorf("Preview error %v", err)
+		c.TemplateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	if len(notifies) == 0 {
+		c.TemplateReply("reply.preview.empty", nil)
+		return
+	}
+	config := cm.GetStateManager().GetGroupConcernConfig(target, mid)
+	var items []map[string]interface{}
+	for index, notify := range notifies {
+		m := notify.ToMessage()
+		var statusData = make(map[string]interface{})
+		if hookName, result := concern.RunSendHooks(config, notify); result.Pass {
+			statusData["pass"] = true
+			if at := config.AtBeforeHook(notify); !at.Pass {
+				statusData["at_reason"] = i18n.Tr(c.Locale, at.Reason)
+			}
+		} else {
+			statusData["hook"] = hookName
+			statusData["reason"] = i18n.Tr(c.Locale, result.Reason)
+		}
+		status := c.TemplateText("reply.preview.status", statusData)
+		items = append(items, map[string]interface{}{
+			"index":   index + 1,
+			"status":  status,
+			"summary": previewSummary(m),
+		})
+		if render {
+			rendered := mmsg.NewMSG()
+			if title := c.TemplateMsg("reply.preview.render_title", map[string]interface{}{
+				"index":  index + 1,
+				"status": status,
+			}); title != nil {
+				rendered.Append(title.Elements()...)
+			}
+			rendered.Append(m.Elements()...)
+			if c.Target.TargetType().IsPrivate() {
+				c.Send(rendered)
+			} else {
+				c.Lsp.SendMsg(rendered, mmsg.NewPrivateTarget(c.Sender.Uin))
+			}
+		}
+	}
+	c.TemplateSend("reply.preview.result", map[string]interface{}{
+		"site":  cm.Site(),
+		"id":    id,
+		"type":  ctype.String(),
+		"items": items,
+	})
+}
+
+// previewSummary 取推送文本的第一行作为摘要
+func previewSummary(m *mmsg.MSG) string {
+	s := strings.TrimSpace(msgstringer.MsgToString(m.Elements()))
+	if pos := strings.Index(s, "\n"); pos >= 0 {
+		s = s[:pos]
+	}
+	if r := []rune(s); len(r) > 40 {
+		s = string(r[:40]) + "..."
+	}
+	return s
 }
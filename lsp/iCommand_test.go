@@ -7,13 +7,17 @@ import (
 	"github.com/Mrs4s/MiraiGo/message"
 	"github.com/cnxysoft/DDBOT-WSa/internal/test"
 	tc "github.com/cnxysoft/DDBOT-WSa/internal/test_concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/alias"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/permission"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/person"
 	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
 	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 	"time"
@@ -113,20 +117,20 @@ func TestIList(t *testing.T) {
 
 	assert.Nil(t, Instance.PermissionStateManager.DisableGroupCommand(test.G2, ListCommand))
 
-	IList(ctx, test.G1, "xxx")
+	IList(ctx, mmsg.NewGroupTarget(test.G1), "xxx")
 	result := <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
 	assert.Nil(t, Instance.PermissionStateManager.DisableGroupCommand(test.G1, ListCommand))
 
-	IList(ctx, test.G1, "xxx")
+	IList(ctx, mmsg.NewGroupTarget(test.G1), "xxx")
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), disabled)
 
 	assert.Nil(t, Instance.PermissionStateManager.EnableGroupCommand(test.G1, ListCommand))
 	assert.Nil(t, Instance.PermissionStateManager.GlobalDisableGroupCommand(ListCommand))
 
-	IList(ctx, test.G1, "xxx")
+	IList(ctx, mmsg.NewGroupTarget(test.G1), "xxx")
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), disabled)
 	assert.Nil(t, Instance.PermissionStateManager.GlobalEnableGroupCommand(ListCommand))
@@ -147,33 +151,33 @@ func TestIList(t *testing.T) {
 	assert.Contains(t, concern.ListSite(), test.Site1)
 	assert.Contains(t, concern.ListSite(), test.Site2)
 
-	IList(ctx, test.G1, "")
+	IList(ctx, mmsg.NewGroupTarget(test.G1), "")
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "暂无订阅")
 
-	_, err := tc1.GetStateManager().AddGroupConcern(test.G1, test.NAME1, test.T1)
+	_, err := tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.NAME1, test.T1)
 	assert.Nil(t, err)
-	_, err = tc2.GetStateManager().AddGroupConcern(test.G1, test.NAME2, test.T2)
+	_, err = tc2.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.NAME2, test.T2)
 	assert.Nil(t, err)
 
-	IList(ctx, test.G1, "")
+	IList(ctx, mmsg.NewGroupTarget(test.G1), "")
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements),
 		fmt.Sprintf("%v %v %v", test.NAME1, test.NAME1, test.T1))
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements),
 		fmt.Sprintf("%v %v %v", test.NAME2, test.NAME2, test.T2))
 
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G2, test.NAME1, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G2), test.NAME1, test.T1)
 	assert.Nil(t, err)
 
 	assert.Nil(t, Instance.PermissionStateManager.EnableGroupCommand(test.G2, ListCommand))
-	IList(ctx, test.G2, "")
+	IList(ctx, mmsg.NewGroupTarget(test.G2), "")
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements),
 		fmt.Sprintf("%v %v %v", test.NAME1, test.NAME1, test.T1))
 	assert.NotContains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), test.NAME2)
 
-	IList(ctx, test.G1, tc1.Site())
+	IList(ctx, mmsg.NewGroupTarget(test.G1), tc1.Site())
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements),
 		fmt.Sprintf("%v %v %v", test.NAME1, test.NAME1, test.T1))
@@ -419,7 +423,7 @@ func TestIWatch(t *testing.T) {
 	target := mmsg.NewGroupTarget(test.G1)
 	ctx := NewCtx(t, msgChan, test.Sender1, target)
 
-	IWatch(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), noPermission)
 
@@ -427,13 +431,13 @@ func TestIWatch(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Nil(t, Instance.PermissionStateManager.DisableGroupCommand(test.G1, WatchCommand))
 
-	IWatch(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), disabled)
 
 	assert.Nil(t, Instance.PermissionStateManager.EnableGroupCommand(test.G1, WatchCommand))
 
-	IWatch(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
@@ -445,23 +449,23 @@ func TestIWatch(t *testing.T) {
 	concern.RegisterConcern(tc2)
 	defer tc2.Stop()
 
-	IWatch(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IWatch(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
-	IWatch(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IWatch(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
-	testEventChan1 <- tc1.NewTestEvent(test.T1, 0, test.NAME1)
+	testEventChan1 <- tc1.NewTestEvent(test.T1, nil, test.NAME1)
 
 	select {
 	case <-testNotifyChan:
@@ -469,28 +473,84 @@ func TestIWatch(t *testing.T) {
 	case <-time.After(time.Second):
 	}
 
-	IWatch(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IWatch(ctx, test.G2, test.NAME1, test.Site1, test.T1, false)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G2), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	testEventChan1 <- tc1.NewTestEvent(test.T1, 0, test.NAME1)
+	testEventChan1 <- tc1.NewTestEvent(test.T1, nil, test.NAME1)
 
 	for i := 0; i < 2; i++ {
 		select {
 		case notify := <-testNotifyChan:
 			assert.EqualValues(t, test.NAME1, notify.GetUid())
 			assert.EqualValues(t, test.Site1, notify.Site())
-			assert.Contains(t, []int64{test.G1, test.G2}, notify.GetGroupCode())
+			assert.Contains(t, []mmsg.Target{mmsg.NewGroupTarget(test.G1), mmsg.NewGroupTarget(test.G2)}, notify.GetTarget())
 		case <-time.After(time.Second):
 			assert.Fail(t, "no item received")
 		}
 	}
 }
 
+func TestIWatchPrivate(t *testing.T) {
+	initLsp(t)
+	defer closeLsp(t)
+
+	testEventChan := make(chan concern.Event, 16)
+	testNotifyChan := make(chan concern.Notify, 1)
+	defer close(testNotifyChan)
+
+	var result *mmsg.MSG
+	msgChan := make(chan *mmsg.MSG, 10)
+	target := mmsg.NewPrivateTarget(test.Sender1.Uin)
+	ctx := NewCtx(t, msgChan, test.Sender1, target)
+
+	tc1 := newTestConcern(t, testEventChan, testNotifyChan, test.Site1, []concern_type.Type{test.T1})
+	concern.RegisterConcern(tc1)
+	defer tc1.Stop()
+
+	// 不是bot的好友
+	IWatch(ctx, target, test.NAME1, test.Site1, test.T1, false)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
+
+	localutils.GetBot().TESTAddFriend(test.Sender1.Uin)
+	localutils.GetBot().TESTAddFriend(test.UID2)
+
+	// 不能操作别人的私聊订阅
+	IWatch(ctx, mmsg.NewPrivateTarget(test.UID2), test.NAME1, test.Site1, test.T1, false)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), noPermission)
+
+	IWatch(ctx, target, test.NAME1, test.Site1, test.T1, false)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
+
+	IList(ctx, target, "")
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), test.NAME1)
+
+	IConfigAtAllCmd(ctx, target, test.NAME1, test.Site1, test.T1, true)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
+
+	testEventChan <- tc1.NewTestEvent(test.T1, nil, test.NAME1)
+	select {
+	case notify := <-testNotifyChan:
+		assert.EqualValues(t, test.NAME1, notify.GetUid())
+		assert.Equal(t, target, notify.GetTarget())
+	case <-time.After(time.Second):
+		assert.Fail(t, "no item received")
+	}
+
+	IWatch(ctx, target, test.NAME1, test.Site1, test.T1, true)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
+}
+
 func TestIConfigAtCmd(t *testing.T) {
 	initLsp(t)
 	defer closeLsp(t)
@@ -514,7 +574,7 @@ func TestIConfigAtCmd(t *testing.T) {
 	concern.RegisterConcern(tc2)
 	defer tc2.Stop()
 
-	IConfigAtCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, "show", nil)
+	IConfigAtCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, "show", nil)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), noPermission)
 
@@ -522,65 +582,65 @@ func TestIConfigAtCmd(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Nil(t, Instance.PermissionStateManager.DisableGroupCommand(test.G1, ConfigCommand))
 
-	IConfigAtCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, "show", nil)
+	IConfigAtCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, "show", nil)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), disabled)
 
 	assert.Nil(t, Instance.PermissionStateManager.EnableGroupCommand(test.G1, ConfigCommand))
 
-	IWatch(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigAtCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, "show", nil)
+	IConfigAtCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, "show", nil)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "当前配置为空")
 
-	IConfigAtCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, "add", nil)
+	IConfigAtCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, "add", nil)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
-	IConfigAtCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, "add", []int64{test.UID1, test.UID2})
+	IConfigAtCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, "add", []int64{test.UID1, test.UID2})
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
 	localutils.GetBot().TESTAddGroup(test.G1)
 	localutils.GetBot().TESTAddMember(test.G1, test.UID1, client.Member)
 
-	IConfigAtCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, "add", []int64{test.UID1, test.UID2})
+	IConfigAtCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, "add", []int64{test.UID1, test.UID2})
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
 	localutils.GetBot().TESTAddMember(test.G1, test.UID2, client.Member)
 
-	IConfigAtCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, "add", []int64{test.UID1, test.UID2})
+	IConfigAtCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, "add", []int64{test.UID1, test.UID2})
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigAtCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, "show", nil)
+	IConfigAtCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, "show", nil)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), strconv.FormatInt(test.UID1, 10))
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), strconv.FormatInt(test.UID2, 10))
 
-	IConfigAtCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, "remove", []int64{test.UID1, test.UID3})
+	IConfigAtCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, "remove", []int64{test.UID1, test.UID3})
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigAtCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, "show", nil)
+	IConfigAtCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, "show", nil)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), strconv.FormatInt(test.UID2, 10))
 	assert.NotContains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), strconv.FormatInt(test.UID1, 10))
 	assert.NotContains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), strconv.FormatInt(test.UID3, 10))
 
-	IConfigAtCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, "clear", nil)
+	IConfigAtCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, "clear", nil)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigAtCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, "show", nil)
+	IConfigAtCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, "show", nil)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "当前配置为空")
 
-	IConfigAtCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, "unknown", []int64{test.UID1})
+	IConfigAtCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, "unknown", []int64{test.UID1})
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 }
@@ -607,7 +667,7 @@ func TestIConfigAtAllCmd(t *testing.T) {
 	tc2 := newTestConcern(t, testEventChan2, testNotifyChan, test.Site2, []concern_type.Type{test.T2})
 	concern.RegisterConcern(tc2)
 
-	IConfigAtAllCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IConfigAtAllCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), noPermission)
 
@@ -615,33 +675,33 @@ func TestIConfigAtAllCmd(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Nil(t, Instance.PermissionStateManager.DisableGroupCommand(test.G1, ConfigCommand))
 
-	IConfigAtAllCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IConfigAtAllCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), disabled)
 
 	assert.Nil(t, Instance.PermissionStateManager.EnableGroupCommand(test.G1, ConfigCommand))
 
-	IConfigAtAllCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IConfigAtAllCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
-	IWatch(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigAtAllCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IConfigAtAllCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigAtAllCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IConfigAtAllCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
-	IConfigAtAllCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IConfigAtAllCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigAtAllCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IConfigAtAllCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 }
@@ -668,7 +728,7 @@ func TestIConfigTitleNotifyCmd(t *testing.T) {
 	tc2 := newTestConcern(t, testEventChan2, testNotifyChan, test.Site2, []concern_type.Type{test.T2})
 	concern.RegisterConcern(tc2)
 
-	IConfigTitleNotifyCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IConfigTitleNotifyCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), noPermission)
 
@@ -676,33 +736,33 @@ func TestIConfigTitleNotifyCmd(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Nil(t, Instance.PermissionStateManager.DisableGroupCommand(test.G1, ConfigCommand))
 
-	IConfigTitleNotifyCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IConfigTitleNotifyCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), disabled)
 
 	assert.Nil(t, Instance.PermissionStateManager.EnableGroupCommand(test.G1, ConfigCommand))
 
-	IConfigTitleNotifyCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IConfigTitleNotifyCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
-	IWatch(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigTitleNotifyCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IConfigTitleNotifyCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigTitleNotifyCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IConfigTitleNotifyCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
-	IConfigTitleNotifyCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IConfigTitleNotifyCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigTitleNotifyCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IConfigTitleNotifyCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 }
@@ -729,7 +789,7 @@ func TestIConfigOfflineNotifyCmd(t *testing.T) {
 	tc2 := newTestConcern(t, testEventChan2, testNotifyChan, test.Site2, []concern_type.Type{test.T2})
 	concern.RegisterConcern(tc2)
 
-	IConfigOfflineNotifyCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IConfigOfflineNotifyCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), noPermission)
 
@@ -737,37 +797,142 @@ func TestIConfigOfflineNotifyCmd(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Nil(t, Instance.PermissionStateManager.DisableGroupCommand(test.G1, ConfigCommand))
 
-	IConfigOfflineNotifyCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IConfigOfflineNotifyCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), disabled)
 
 	assert.Nil(t, Instance.PermissionStateManager.EnableGroupCommand(test.G1, ConfigCommand))
 
-	IConfigOfflineNotifyCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IConfigOfflineNotifyCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
-	IWatch(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigOfflineNotifyCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IConfigOfflineNotifyCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigOfflineNotifyCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, true)
+	IConfigOfflineNotifyCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, true)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
-	IConfigOfflineNotifyCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IConfigOfflineNotifyCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigOfflineNotifyCmd(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IConfigOfflineNotifyCmd(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 }
 
+func TestIConfigAliasCmd(t *testing.T) {
+	initLsp(t)
+	defer closeLsp(t)
+
+	testEventChan := make(chan concern.Event, 16)
+	testNotifyChan := make(chan concern.Notify, 1)
+	defer close(testNotifyChan)
+
+	var result *mmsg.MSG
+	var err error
+	msgChan := make(chan *mmsg.MSG, 10)
+	target := mmsg.NewGroupTarget(test.G1)
+	ctx := NewCtx(t, msgChan, test.Sender1, target)
+
+	tc1 := newTestConcern(t, testEventChan, testNotifyChan, test.Site1, []concern_type.Type{test.T1})
+	concern.RegisterConcern(tc1)
+	defer tc1.Stop()
+
+	IConfigAliasCmd(ctx, mmsg.NewPrivateTarget(test.UID1), test.NAME1, test.Site1, "alice")
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
+
+	IConfigAliasCmd(ctx, target, test.NAME1, test.Site1, "alice")
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), noPermission)
+
+	assert.Nil(t, Instance.PermissionStateManager.GrantRole(test.Sender1.Uin, permission.Admin))
+
+	IConfigAliasCmd(ctx, target, test.NAME1, test.Site1, "alice")
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
+
+	// 订阅时默认使用名字作为别名
+	IWatch(ctx, target, test.NAME1, test.Site1, test.T1, false)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
+	a, err := alias.Get(test.G1, test.Site1, test.NAME1)
+	assert.Nil(t, err)
+	assert.Equal(t, test.NAME1, a)
+
+	IWatch(ctx, target, test.NAME2, test.Site1, test.T1, false)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
+
+	IConfigAliasCmd(ctx, target, test.NAME1, test.Site1, "alice")
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "alice")
+
+	assert.Equal(t, test.NAME1, resolveAlias(target, test.Site1, "alice"))
+	assert.Equal(t, "bob", resolveAlias(target, test.Site1, "bob"))
+	assert.Equal(t, "alice", resolveAlias(mmsg.NewGroupTarget(test.G2), test.Site1, "alice"))
+	assert.Equal(t, "alice", resolveAlias(mmsg.NewPrivateTarget(test.UID1), test.Site1, "alice"))
+
+	// 配置命令可以使用别名代替id
+	IConfigTitleNotifyCmd(ctx, target, "alice", test.Site1, test.T1, true)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), test.NAME1)
+	assert.True(t, tc1.GetStateManager().GetGroupConcernConfig(target, test.NAME1).GetGroupConcernNotify().CheckTitleChangeNotify(test.T1))
+
+	// 别名冲突
+	IConfigAliasCmd(ctx, target, test.NAME2, test.Site1, "alice")
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "alice")
+
+	// 不能使用其他订阅的id作为别名
+	assert.Nil(t, alias.Remove(test.G1, test.Site1, test.NAME2))
+	IConfigAliasCmd(ctx, target, test.NAME1, test.Site1, test.NAME2)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
+
+	IConfigAliasCmd(ctx, target, test.NAME1, test.Site1, "a b")
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
+
+	IList(ctx, target, "")
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements),
+		fmt.Sprintf("%v %v %v [alice]", test.NAME1, test.NAME1, test.T1))
+
+	IConfigAliasCmd(ctx, target, test.NAME1, test.Site1, "")
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
+	assert.Equal(t, "alice", resolveAlias(target, test.Site1, "alice"))
+
+	// 取消订阅时删除别名
+	assert.Nil(t, alias.Set(test.G1, test.Site1, test.NAME2, "bob"))
+	IWatch(ctx, target, test.NAME2, test.Site1, test.T1, true)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
+	_, err = alias.Resolve(test.G1, test.Site1, "bob")
+	assert.Equal(t, alias.ErrAliasNotFound, err)
+
+	// 新订阅的id被其他订阅用作别名时，删除那个别名
+	assert.Nil(t, alias.Set(test.G1, test.Site1, test.NAME1, "carol"))
+	IWatch(ctx, target, "carol", test.Site1, test.T1, false)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
+	_, err = alias.Get(test.G1, test.Site1, test.NAME1)
+	assert.Equal(t, alias.ErrAliasNotFound, err)
+	assert.Equal(t, "carol", resolveAlias(target, test.Site1, "carol"))
+}
+
 func TestIConfigFilterCmd(t *testing.T) {
 	initLsp(t)
 	defer closeLsp(t)
@@ -790,7 +955,7 @@ func TestIConfigFilterCmd(t *testing.T) {
 	tc2 := newTestConcern(t, testEventChan2, testNotifyChan, test.Site2, []concern_type.Type{test.T2})
 	concern.RegisterConcern(tc2)
 
-	IConfigFilterCmdType(ctx, test.G1, test.NAME1, test.Site1, test.T1, []string{test.Type1})
+	IConfigFilterCmdType(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, []string{test.Type1})
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), noPermission)
 
@@ -798,55 +963,55 @@ func TestIConfigFilterCmd(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Nil(t, Instance.PermissionStateManager.DisableGroupCommand(test.G1, ConfigCommand))
 
-	IConfigFilterCmdType(ctx, test.G1, test.NAME1, test.Site1, test.T1, []string{test.Type1})
+	IConfigFilterCmdType(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, []string{test.Type1})
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), disabled)
 
 	assert.Nil(t, Instance.PermissionStateManager.EnableGroupCommand(test.G1, ConfigCommand))
 
-	IConfigFilterCmdType(ctx, test.G1, test.NAME1, test.Site1, test.T1, []string{test.Type1})
+	IConfigFilterCmdType(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, []string{test.Type1})
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
-	IWatch(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigFilterCmdType(ctx, test.G1, test.NAME1, test.Site1, test.T1, []string{})
+	IConfigFilterCmdType(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, []string{})
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
-	IConfigFilterCmdType(ctx, test.G1, test.NAME1, test.Site1, test.T1, []string{test.Type1})
+	IConfigFilterCmdType(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, []string{test.Type1})
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
-	IConfigFilterCmdNotType(ctx, test.G1, test.NAME1, test.Site1, test.T1, []string{})
+	IConfigFilterCmdNotType(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, []string{})
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
-	IConfigFilterCmdNotType(ctx, test.G1, test.NAME1, test.Site1, test.T1, []string{test.Type1})
+	IConfigFilterCmdNotType(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, []string{test.Type1})
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
-	IConfigFilterCmdText(ctx, test.G1, test.NAME1, test.Site1, test.T1, []string{})
+	IConfigFilterCmdText(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, []string{})
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
 
-	IConfigFilterCmdText(ctx, test.G1, test.NAME1, test.Site1, test.T1, []string{test.NAME1, test.NAME2})
+	IConfigFilterCmdText(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, []string{test.NAME1, test.NAME2})
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigFilterCmdShow(ctx, test.G1, test.NAME1, test.Site1, test.T1)
+	IConfigFilterCmdShow(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "关键字过滤模式")
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), test.NAME1)
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), test.NAME2)
 
-	IConfigFilterCmdClear(ctx, test.G1, test.NAME1, test.Site1, test.T1)
+	IConfigFilterCmdClear(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IConfigFilterCmdShow(ctx, test.G1, test.NAME1, test.Site1, test.T1)
+	IConfigFilterCmdShow(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "当前配置为空")
 }
@@ -880,68 +1045,68 @@ func TestICleanConcern(t *testing.T) {
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "清除0个")
 
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T2)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T2)
 	assert.Nil(t, err)
 
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T1)
 	assert.Nil(t, err)
 
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G2, test.UID1, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID1, test.T1)
 	assert.Nil(t, err)
 
 	ICleanConcern(ctx, false, []int64{test.G1}, test.Site1, test.T1.String())
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "清除1个")
 
-	err = tc1.GetStateManager().CheckGroupConcern(test.G1, test.UID1, test.T1)
+	err = tc1.GetStateManager().CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T1)
 	assert.Nil(t, err)
 
-	err = tc1.GetStateManager().CheckGroupConcern(test.G1, test.UID1, test.T2)
+	err = tc1.GetStateManager().CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T2)
 	assert.NotNil(t, err)
 
-	err = tc1.GetStateManager().CheckGroupConcern(test.G2, test.UID1, test.T1)
+	err = tc1.GetStateManager().CheckGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID1, test.T1)
 	assert.NotNil(t, err)
 
 	ICleanConcern(ctx, false, []int64{test.G1}, test.Site1, test.T2.String())
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "清除1个")
 
-	err = tc1.GetStateManager().CheckGroupConcern(test.G1, test.UID1, test.T1)
+	err = tc1.GetStateManager().CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T1)
 	assert.Nil(t, err)
 
-	err = tc1.GetStateManager().CheckGroupConcern(test.G1, test.UID1, test.T2)
+	err = tc1.GetStateManager().CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T2)
 	assert.Nil(t, err)
 
-	err = tc1.GetStateManager().CheckGroupConcern(test.G2, test.UID1, test.T1)
+	err = tc1.GetStateManager().CheckGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID1, test.T1)
 	assert.NotNil(t, err)
 
 	ICleanConcern(ctx, false, []int64{test.G1}, test.Site1, test.T1.String())
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "清除0个")
 
-	err = tc1.GetStateManager().CheckGroupConcern(test.G1, test.UID1, test.T1)
+	err = tc1.GetStateManager().CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T1)
 	assert.Nil(t, err)
 
-	err = tc1.GetStateManager().CheckGroupConcern(test.G1, test.UID1, test.T2)
+	err = tc1.GetStateManager().CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T2)
 	assert.Nil(t, err)
 
-	err = tc1.GetStateManager().CheckGroupConcern(test.G2, test.UID1, test.T1)
+	err = tc1.GetStateManager().CheckGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID1, test.T1)
 	assert.NotNil(t, err)
 
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T2)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T2)
 	assert.Nil(t, err)
 
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T1)
 	assert.Nil(t, err)
 
 	ICleanConcern(ctx, false, []int64{test.G1, test.G2}, "", "")
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "清除2个")
 
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T2)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T2)
 	assert.Nil(t, err)
 
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T1)
 	assert.Nil(t, err)
 
 	ICleanConcern(ctx, false, []int64{test.G1, test.G2}, "", "")
@@ -950,15 +1115,15 @@ func TestICleanConcern(t *testing.T) {
 
 	localutils.GetBot().TESTAddGroup(test.G2)
 
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T2)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T2)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T1)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID2, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID2, test.T1)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID3, test.T2)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID3, test.T2)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G2, test.UID1, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID1, test.T1)
 	assert.Nil(t, err)
 
 	ICleanConcern(ctx, true, []int64{test.G1, test.G2}, "", "")
@@ -969,7 +1134,7 @@ func TestICleanConcern(t *testing.T) {
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "清除3个")
 
-	err = tc1.GetStateManager().CheckGroupConcern(test.G2, test.UID1, test.T1)
+	err = tc1.GetStateManager().CheckGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID1, test.T1)
 	assert.NotNil(t, err)
 
 	ICleanConcern(ctx, false, nil, "", "")
@@ -980,15 +1145,15 @@ func TestICleanConcern(t *testing.T) {
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "清除1个")
 
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T2)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T2)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T1)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID2, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID2, test.T1)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID3, test.T2)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID3, test.T2)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G2, test.UID1, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID1, test.T1)
 	assert.Nil(t, err)
 
 	ICleanConcern(ctx, false, []int64{test.G1, test.G2}, "", test.T2.String())
@@ -999,24 +1164,24 @@ func TestICleanConcern(t *testing.T) {
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "清除3个")
 
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T2)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T2)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T1)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID2, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID2, test.T1)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID3, test.T2)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID3, test.T2)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G2, test.UID1, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID1, test.T1)
 	assert.Nil(t, err)
-	_, err = tc2.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T1)
+	_, err = tc2.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T1)
 	assert.Nil(t, err)
 
 	ICleanConcern(ctx, false, []int64{test.G1, test.G2}, test.Site1, "")
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "清除4个")
 
-	err = tc2.GetStateManager().CheckGroupConcern(test.G1, test.UID1, test.T1)
+	err = tc2.GetStateManager().CheckGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T1)
 	assert.NotNil(t, err)
 
 	ICleanConcern(ctx, false, []int64{test.G1, test.G2}, "wrongasdsad", "")
@@ -1027,31 +1192,31 @@ func TestICleanConcern(t *testing.T) {
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "清除1个")
 
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T2)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T2)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T1)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID2, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID2, test.T1)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID3, test.T2)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID3, test.T2)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G2, test.UID1, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G2), test.UID1, test.T1)
 	assert.Nil(t, err)
-	_, err = tc2.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T1)
+	_, err = tc2.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T1)
 	assert.Nil(t, err)
 	ICleanConcern(ctx, true, nil, "", "")
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "清除4个")
 
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T2)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T2)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T1)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID2, test.T1)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID2, test.T1)
 	assert.Nil(t, err)
-	_, err = tc1.GetStateManager().AddGroupConcern(test.G1, test.UID3, test.T2)
+	_, err = tc1.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID3, test.T2)
 	assert.Nil(t, err)
-	_, err = tc2.GetStateManager().AddGroupConcern(test.G1, test.UID1, test.T1)
+	_, err = tc2.GetStateManager().AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.UID1, test.T1)
 	assert.Nil(t, err)
 
 	IAbnormalConcernCheck(ctx)
@@ -1074,3 +1239,372 @@ func TestICleanConcern(t *testing.T) {
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "没有查询到")
 }
+
+func TestIWatchPerson(t *testing.T) {
+	initLsp(t)
+	defer closeLsp(t)
+
+	testEventChan1 := make(chan concern.Event, 16)
+	testEventChan2 := make(chan concern.Event, 16)
+	testNotifyChan := make(chan concern.Notify, 1)
+	defer close(testNotifyChan)
+
+	var result *mmsg.MSG
+	msgChan := make(chan *mmsg.MSG, 10)
+	target := mmsg.NewGroupTarget(test.G1)
+	ctx := NewCtx(t, msgChan, test.Sender1, target)
+
+	IWatchPerson(ctx, test.G1, "alice", test.NAME1, test.Site1, test.T1)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), noPermission)
+
+	assert.Nil(t, Instance.PermissionStateManager.GrantRole(test.Sender1.Uin, permission.Admin))
+
+	IWatchPerson(ctx, test.G1, "a:b", test.NAME1, test.Site1, test.T1)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
+
+	tc1 := newTestConcern(t, testEventChan1, testNotifyChan, test.Site1, []concern_type.Type{test.T1})
+	concern.RegisterConcern(tc1)
+	defer tc1.Stop()
+
+	tc2 := newTestConcern(t, testEventChan2, testNotifyChan, test.Site2, []concern_type.Type{test.T2})
+	concern.RegisterConcern(tc2)
+	defer tc2.Stop()
+
+	IWatchPerson(ctx, test.G1, "alice", test.NAME1, test.Site1, test.T1)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "alice")
+
+	// 已经订阅过的也可以归入人物
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME2, test.Site2, test.T2, false)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
+	IWatchPerson(ctx, test.G1, "alice", test.NAME2, test.Site2, test.T2)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "alice")
+
+	p, err := person.Get(test.G1, "alice")
+	assert.Nil(t, err)
+	assert.Len(t, p.Members, 2)
+
+	IListPerson(ctx, test.G1)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "【alice】")
+
+	// 取消订阅时移出人物
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME2, test.Site2, test.T2, true)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
+	p, err = person.Get(test.G1, "alice")
+	assert.Nil(t, err)
+	assert.Len(t, p.Members, 1)
+
+	IUnwatchPerson(ctx, test.G1, "alice")
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
+	_, err = tc1.GetStateManager().GetGroupConcern(mmsg.NewGroupTarget(test.G1), test.NAME1)
+	assert.NotNil(t, err)
+	_, err = person.Get(test.G1, "alice")
+	assert.Equal(t, person.ErrPersonNotFound, err)
+
+	IUnwatchPerson(ctx, test.G1, "alice")
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
+
+	IListPerson(ctx, test.G1)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "暂无人物")
+}
+
+func TestIPreview(t *testing.T) {
+	initLsp(t)
+	defer closeLsp(t)
+
+	testEventChan := make(chan concern.Event, 16)
+	testNotifyChan := make(chan concern.Notify, 1)
+	defer close(testNotifyChan)
+
+	var result *mmsg.MSG
+	msgChan := make(chan *mmsg.MSG, 10)
+	target := mmsg.NewPrivateTarget(test.UID1)
+	ctx := NewCtx(t, msgChan, test.Sender1, target)
+
+	IPreview(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, 2, false)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), noPermission)
+
+	assert.Nil(t, Instance.PermissionStateManager.GrantRole(test.Sender1.Uin, permission.Admin))
+
+	IPreview(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, 2, false)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
+
+	tc1 := newTestConcern(t, testEventChan, testNotifyChan, test.Site1, []concern_type.Type{test.T1})
+	concern.RegisterConcern(tc1)
+	defer tc1.Stop()
+
+	IPreview(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, 2, false)
+	result = <-msgChan
+	s := msgstringer.MsgToString(result.ToCombineMessage(target).Elements)
+	assert.Contains(t, s, "1. 会推送")
+	assert.Contains(t, s, "2. 会推送")
+
+	sm := tc1.GetStateManager()
+	assert.Nil(t, sm.OperateGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.NAME1, sm.GetGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.NAME1),
+		func(concernConfig concern.IConfig) bool {
+			concernConfig.GetGroupConcernFilter().Type = concern.FilterTypeText
+			concernConfig.GetGroupConcernFilter().Config = (&concern.GroupConcernFilterConfigByText{Text: []string{"nothing"}}).ToString()
+			return true
+		}))
+
+	IPreview(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, 1, true)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "【第1条 被过滤 - FilterHook")
+	result = <-msgChan
+	s = msgstringer.MsgToString(result.ToCombineMessage(target).Elements)
+	assert.Contains(t, s, "1. 被过滤 - FilterHook")
+	assert.NotContains(t, s, "2. ")
+
+	select {
+	case <-testNotifyChan:
+		assert.Fail(t, "preview should not notify")
+	default:
+	}
+}
+
+func TestParseWatchList(t *testing.T) {
+	assert.Equal(t, []string{"1", "2", "3", "site1:4", "5"}, parseWatchList(
+		"# 注释\n1 2,3\r\n\n  site1:4，5  \n#6\n"))
+	assert.Empty(t, parseWatchList("\n# only comment\n"))
+}
+
+func TestReadWatchList(t *testing.T) {
+	initLsp(t)
+	defer closeLsp(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "# 订阅列表\n1 2\nsite2:3\n")
+	}))
+	defer ts.Close()
+
+	// 命令消息中只有被回复消息的id，ReplyElement.Elements 是空的
+	elements := []message.IMessageElement{
+		&message.ReplyElement{ReplySeq: test.MessageID1, GroupID: test.G1},
+		message.NewText("/watch --from-list"),
+	}
+
+	_, err := readWatchList(Instance, elements, test.G1, test.Site1, nil)
+	assert.Equal(t, errWatchListNotFound, err)
+
+	assert.Nil(t, Instance.LspStateManager.SaveMessageFile(test.G1, test.MessageID1, []message.IMessageElement{
+		&message.GroupFileElement{Name: "list.txt", Size: 20, Id: "/file1", Url: ts.URL},
+	}))
+	ids, err := readWatchList(Instance, elements, test.G1, test.Site1, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2", "site2:3"}, ids)
+
+	// 私聊或者没有保存的消息会向协议端查询，测试中查询失败
+	_, err = readWatchList(Instance, elements, 0, test.Site1, nil)
+	assert.Equal(t, errWatchListNotFound, err)
+
+	assert.Nil(t, Instance.LspStateManager.SaveMessageFile(test.G1, test.MessageID2, []message.IMessageElement{
+		&message.GroupFileElement{Name: "list.txt", Size: maxWatchListSize + 1, Id: "/file2", Url: ts.URL},
+	}))
+	_, err = readWatchList(Instance, []message.IMessageElement{
+		&message.ReplyElement{ReplySeq: test.MessageID2, GroupID: test.G1},
+	}, test.G1, test.Site1, nil)
+	assert.Equal(t, errWatchListTooLarge, err)
+
+	tc1 := newTestConcern(t, nil, nil, test.Site1, []concern_type.Type{test.T1})
+	concern.RegisterConcern(tc1)
+	defer tc1.Stop()
+
+	_, err = readWatchList(Instance, nil, test.G1, test.Site1, []string{"分组"})
+	assert.Equal(t, errFollowGroupNotSupported, err)
+}
+
+func TestIWatchBatch(t *testing.T) {
+	initLsp(t)
+	defer closeLsp(t)
+
+	testEventChan1 := make(chan concern.Event, 16)
+	testEventChan2 := make(chan concern.Event, 16)
+	testNotifyChan := make(chan concern.Notify, 1)
+	defer close(testNotifyChan)
+
+	var result *mmsg.MSG
+	msgChan := make(chan *mmsg.MSG, 10)
+	target := mmsg.NewGroupTarget(test.G1)
+	ctx := NewCtx(t, msgChan, test.Sender1, target)
+
+	tc1 := newTestConcern(t, testEventChan1, testNotifyChan, test.Site1, []concern_type.Type{test.T1})
+	concern.RegisterConcern(tc1)
+	defer tc1.Stop()
+
+	tc2 := newTestConcern(t, testEventChan2, testNotifyChan, test.Site2, []concern_type.Type{test.T2})
+	concern.RegisterConcern(tc2)
+	defer tc2.Stop()
+
+	items := parseWatchItems([]string{test.NAME1, test.Site2 + ":" + test.NAME2, "unknown:" + test.NAME2}, test.Site1, test.T1, "")
+	assert.Len(t, items, 3)
+	assert.EqualValues(t, &watchItem{Site: test.Site1, Type: test.T1, Id: test.NAME1}, items[0])
+	assert.EqualValues(t, &watchItem{Site: test.Site2, Type: test.T2, Id: test.NAME2}, items[1])
+	// 不是网站的前缀会作为id的一部分
+	assert.EqualValues(t, &watchItem{Site: test.Site1, Type: test.T1, Id: "unknown:" + test.NAME2}, items[2])
+
+	items = parseWatchItems([]string{test.Site2 + ":" + test.NAME2}, test.Site1, test.T1, string(test.T1))
+	assert.Len(t, items, 1)
+	assert.NotNil(t, items[0].err)
+
+	IWatchBatch(ctx, target, parseWatchItems([]string{test.NAME1}, test.Site1, test.T1, ""), false)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), noPermission)
+
+	assert.Nil(t, Instance.PermissionStateManager.GrantRole(test.Sender1.Uin, permission.Admin))
+
+	var tooMany []string
+	for i := 0; i <= maxWatchBatch; i++ {
+		tooMany = append(tooMany, strconv.Itoa(i))
+	}
+	IWatchBatch(ctx, target, parseWatchItems(tooMany, test.Site1, test.T1, ""), false)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), failed)
+	_, ids, _, err := tc1.GetStateManager().ListConcernState(func(mmsg.Target, interface{}, concern_type.Type) bool {
+		return true
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, ids)
+
+	IWatch(ctx, target, test.NAME1, test.Site1, test.T1, false)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
+
+	IWatchBatch(ctx, target, parseWatchItems([]string{test.NAME1, test.NAME2, test.Site2 + ":" + test.NAME2}, test.Site1, test.T1, ""), false)
+	result = <-msgChan
+	s := msgstringer.MsgToString(result.ToCombineMessage(target).Elements)
+	assert.Contains(t, s, "成功2个，失败1个")
+	assert.Contains(t, s, fmt.Sprintf("失败 %v %v", test.Site1, test.NAME1))
+
+	ctype, err := tc1.GetStateManager().GetGroupConcern(target, test.NAME2)
+	assert.Nil(t, err)
+	assert.EqualValues(t, test.T1, ctype)
+	ctype, err = tc2.GetStateManager().GetGroupConcern(target, test.NAME2)
+	assert.Nil(t, err)
+	assert.EqualValues(t, test.T2, ctype)
+
+	IWatchItems(ctx, target, parseWatchItems([]string{test.NAME1, test.Site2 + ":" + test.NAME2}, test.Site1, test.T1, ""), true)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "成功2个，失败0个")
+
+	// 只有一个id时和IWatch相同
+	IWatchItems(ctx, target, parseWatchItems([]string{test.NAME2}, test.Site1, test.T1, ""), true)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), "unwatch成功")
+
+	_, ids, _, err = tc1.GetStateManager().ListConcernState(func(mmsg.Target, interface{}, concern_type.Type) bool {
+		return true
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestICopyConcern(t *testing.T) {
+	initLsp(t)
+	defer closeLsp(t)
+
+	testEventChan1 := make(chan concern.Event, 16)
+	testEventChan2 := make(chan concern.Event, 16)
+	testNotifyChan := make(chan concern.Notify, 1)
+	defer close(testNotifyChan)
+
+	var result *mmsg.MSG
+	msgChan := make(chan *mmsg.MSG, 10)
+	from := mmsg.NewGroupTarget(test.G1)
+	to := mmsg.NewGroupTarget(test.G2)
+	ctx := NewCtx(t, msgChan, test.Sender1, to)
+
+	tc1 := newTestConcern(t, testEventChan1, testNotifyChan, test.Site1, []concern_type.Type{test.T1})
+	concern.RegisterConcern(tc1)
+	defer tc1.Stop()
+
+	tc2 := newTestConcern(t, testEventChan2, testNotifyChan, test.Site2, []concern_type.Type{test.T2})
+	concern.RegisterConcern(tc2)
+	defer tc2.Stop()
+
+	ICopyConcern(ctx, test.G1, test.G1)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(to).Elements), failed)
+
+	// 需要两个群的管理员权限
+	assert.Nil(t, Instance.PermissionStateManager.GrantGroupRole(test.G1, test.Sender1.Uin, permission.GroupAdmin))
+	ICopyConcern(ctx, test.G1, test.G2)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(to).Elements), noPermission)
+	assert.Nil(t, Instance.PermissionStateManager.GrantGroupRole(test.G2, test.Sender1.Uin, permission.GroupAdmin))
+
+	// 禁用了watch的群不能接收订阅
+	assert.Nil(t, Instance.PermissionStateManager.DisableGroupCommand(test.G2, WatchCommand))
+	ICopyConcern(ctx, test.G1, test.G2)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(to).Elements), disabled)
+	assert.Nil(t, Instance.PermissionStateManager.EnableGroupCommand(test.G2, WatchCommand))
+
+	_, err := tc1.GetStateManager().AddGroupConcern(from, test.NAME1, test.T1)
+	assert.Nil(t, err)
+	_, err = tc1.GetStateManager().AddGroupConcern(from, test.NAME2, test.T1)
+	assert.Nil(t, err)
+	_, err = tc2.GetStateManager().AddGroupConcern(from, test.NAME1, test.T2)
+	assert.Nil(t, err)
+	_, err = tc1.GetStateManager().AddGroupConcern(to, test.NAME2, test.T1)
+	assert.Nil(t, err)
+	assert.Nil(t, tc1.GetStateManager().OperateGroupConcernConfig(from, test.NAME1,
+		tc1.GetStateManager().GetGroupConcernConfig(from, test.NAME1), func(concernConfig concern.IConfig) bool {
+			concernConfig.GetGroupConcernAt().AtAll = test.T1
+			return true
+		}))
+	assert.Nil(t, alias.Set(test.G1, test.Site1, test.NAME1, "alice"))
+	// 复制的订阅id被目标群其他订阅用作别名时，删除那个别名
+	assert.Nil(t, alias.Set(test.G2, test.Site1, test.NAME2, test.NAME1))
+
+	ICopyConcern(ctx, test.G1, test.G2)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(to).Elements), "复制2个，跳过已有的1个")
+
+	ctype, err := tc1.GetStateManager().GetGroupConcern(to, test.NAME1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, test.T1, ctype)
+	ctype, err = tc2.GetStateManager().GetGroupConcern(to, test.NAME1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, test.T2, ctype)
+	assert.True(t, tc1.GetStateManager().GetGroupConcernConfig(to, test.NAME1).GetGroupConcernAt().CheckAtAll(test.T1))
+	a, err := alias.Get(test.G2, test.Site1, test.NAME1)
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", a)
+	_, err = alias.Get(test.G2, test.Site1, test.NAME2)
+	assert.Equal(t, alias.ErrAliasNotFound, err)
+
+	// 再次复制时全部跳过
+	ICopyConcern(ctx, test.G1, test.G2)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(to).Elements), "复制0个，跳过已有的3个")
+
+	// 禁用了config的群只复制订阅，不复制配置
+	_, err = tc2.GetStateManager().AddGroupConcern(from, test.NAME2, test.T2)
+	assert.Nil(t, err)
+	assert.Nil(t, tc2.GetStateManager().OperateGroupConcernConfig(from, test.NAME2,
+		tc2.GetStateManager().GetGroupConcernConfig(from, test.NAME2), func(concernConfig concern.IConfig) bool {
+			concernConfig.GetGroupConcernAt().AtAll = test.T2
+			return true
+		}))
+	assert.Nil(t, Instance.PermissionStateManager.DisableGroupCommand(test.G2, ConfigCommand))
+	ICopyConcern(ctx, test.G1, test.G2)
+	result = <-msgChan
+	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(to).Elements), "复制1个，跳过已有的3个")
+	ctype, err = tc2.GetStateManager().GetGroupConcern(to, test.NAME2)
+	assert.Nil(t, err)
+	assert.EqualValues(t, test.T2, ctype)
+	assert.False(t, tc2.GetStateManager().GetGroupConcernConfig(to, test.NAME2).GetGroupConcernAt().CheckAtAll(test.T2))
+}
@@ -0,0 +1,35 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// TopicLoginStatus 订阅模块的账号登录状态发生变化（即将过期或已过期）时，
+// 会通过eventbus发布到这个topic，消息类型为 *LoginStatus
+const TopicLoginStatus = "login_status"
+
+// LoginStatus 订阅模块使用的账号登录状态
+type LoginStatus struct {
+	Site string
+	// Status 状态描述，例如 有效 / 即将过期 / 已过期
+	Status  string
+	Expired bool
+	// Expire cookie的过期时间，未知时为零值
+	Expire time.Time
+	// Uid 当前登录的账号，未登录时为0
+	Uid int64
+	// Account 额外配置的账号名称，主账号为空
+	Account string
+	// RestartRequired 表示登录后部分功能需要重启才能生效
+	RestartRequired bool
+}
+
+// QRLoginProvider 支持在聊天中扫码登录的订阅模块需要实现这个接口
+type QRLoginProvider interface {
+	// QRLogin 申请一个登录二维码，返回png格式的图片，并在后台等待扫码，
+	// 扫码结束后调用 onResult，err为nil表示登录成功并已生效
+	QRLogin(ctx context.Context, onResult func(err error)) ([]byte, error)
+	// LoginStatus 返回当前的登录状态
+	LoginStatus() *LoginStatus
+}
@@ -0,0 +1,28 @@
+package interfaces
+
+import "time"
+
+// MirrorStatus 订阅模块使用的一个镜像站的状态
+type MirrorStatus struct {
+	Host string
+	// Healthy 为 false 表示该镜像站正在冷却，冷却结束时间为 CooldownUntil
+	Healthy       bool
+	CooldownUntil time.Time
+	// SuccessRate 最近请求的成功率，越近的请求权重越高
+	SuccessRate float64
+	Latency     time.Duration
+	Requests    int64
+	Failures    int64
+	// Challenges 遇到 Anubis 等人机验证的次数
+	Challenges int64
+	// AnubisSolved 最近一次通过人机验证的时间，未通过时为零值
+	AnubisSolved time.Time
+	LastError    string
+}
+
+// MirrorProvider 使用镜像站的订阅模块需要实现这个接口，用于查看和重置镜像站状态
+type MirrorProvider interface {
+	MirrorStatus() []*MirrorStatus
+	// ResetMirror 清除镜像站的冷却和统计，host为空时重置所有镜像站，返回重置的数量
+	ResetMirror(host string) int
+}
@@ -0,0 +1,110 @@
+package kick
+
+import (
+	"bytes"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
+	"github.com/cnxysoft/DDBOT-WSa/requests"
+	"github.com/cnxysoft/DDBOT-WSa/utils"
+)
+
+var slugRegex = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// ParseSlug 支持频道名或者频道链接
+func ParseSlug(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	for _, prefix := range []string{"https://", "http://", "www.", "kick.com/"} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	s = strings.ToLower(strings.Trim(s, "/"))
+	if !slugRegex.MatchString(s) {
+		return "", ErrInvalidId
+	}
+	return s, nil
+}
+
+type channelResponse struct {
+	Id       int64  `json:"id"`
+	Slug     string `json:"slug"`
+	IsBanned bool   `json:"is_banned"`
+	User     *struct {
+		Username   string `json:"username"`
+		ProfilePic string `json:"profile_pic"`
+	} `json:"user"`
+	Livestream *struct {
+		Id           int64  `json:"id"`
+		SessionTitle string `json:"session_title"`
+		IsLive       bool   `json:"is_live"`
+		ViewerCount  int64  `json:"viewer_count"`
+		Thumbnail    *struct {
+			Url string `json:"url"`
+		} `json:"thumbnail"`
+		Categories []struct {
+			Name string `json:"name"`
+		} `json:"categories"`
+	} `json:"livestream"`
+}
+
+func ChannelInfo(slug string) (*LiveInfo, error) {
+	st := time.Now()
+	defer func() {
+		ed := time.Now()
+		logger.WithField("FuncName", utils.FuncName()).Tracef("cost %v", ed.Sub(st))
+	}()
+	var code int
+	var opts = []requests.Option{
+		requests.AddUAOption(),
+		requests.HeaderOption("accept", "application/json"),
+		requests.ProxyOption(proxy_pool.PreferOversea),
+		requests.HttpCodeOption(&code),
+		requests.TimeoutOption(time.Second * 10),
+	}
+	var body = new(bytes.Buffer)
+	err := requests.Get(ChannelApiPath(slug), nil, body, opts...)
+	if code == http.StatusNotFound {
+		return nil, ErrChannelNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseChannelInfo(body.Bytes())
+}
+
+// ParseChannelInfo 解析频道接口的返回，没有在直播时 livestream 为 null
+func ParseChannelInfo(body []byte) (*LiveInfo, error) {
+	var resp = new(channelResponse)
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Slug) == 0 {
+		return nil, ErrChannelNotExist
+	}
+	if resp.IsBanned {
+		return nil, ErrChannelBanned
+	}
+	info := &LiveInfo{
+		Slug:    resp.Slug,
+		RoomUrl: KickPath(resp.Slug),
+		Name:    resp.Slug,
+	}
+	if resp.User != nil {
+		info.Name = resp.User.Username
+		info.Avatar = resp.User.ProfilePic
+	}
+	if stream := resp.Livestream; stream != nil && stream.IsLive {
+		info.IsLiving = true
+		info.Title = stream.SessionTitle
+		info.Viewers = stream.ViewerCount
+		if stream.Thumbnail != nil {
+			info.Cover = stream.Thumbnail.Url
+		}
+		if len(stream.Categories) > 0 {
+			info.Category = stream.Categories[0].Name
+		}
+	}
+	return info, nil
+}
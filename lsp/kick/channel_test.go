@@ -0,0 +1,60 @@
+package kick
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	b, err := os.ReadFile("testdata/" + name)
+	require.Nil(t, err)
+	return b
+}
+
+func TestParseSlug(t *testing.T) {
+	for _, s := range []string{"xqc", "xQc", "https://kick.com/xqc", "kick.com/xqc/", " xqc "} {
+		slug, err := ParseSlug(s)
+		assert.Nil(t, err)
+		assert.Equal(t, "xqc", slug)
+	}
+	slug, err := ParseSlug("some-one_1")
+	assert.Nil(t, err)
+	assert.Equal(t, "some-one_1", slug)
+	for _, s := range []string{"", "x qc", "https://kick.com/", "中文"} {
+		_, err := ParseSlug(s)
+		assert.Equal(t, ErrInvalidId, err)
+	}
+}
+
+func TestParseChannelInfo(t *testing.T) {
+	info, err := ParseChannelInfo(readFixture(t, "channel_live.json"))
+	require.Nil(t, err)
+	assert.True(t, info.Living())
+	assert.Equal(t, "xqc", info.Slug)
+	assert.Equal(t, "xQc", info.Name)
+	assert.Equal(t, "JUICER REACTS", info.Title)
+	assert.Equal(t, "Just Chatting", info.Category)
+	assert.Equal(t, "https://kick.com/xqc", info.RoomUrl)
+	assert.EqualValues(t, 48213, info.Viewers)
+	assert.Contains(t, info.Cover, "video_thumbnails")
+
+	info, err = ParseChannelInfo(readFixture(t, "channel_offline.json"))
+	require.Nil(t, err)
+	assert.False(t, info.Living())
+	assert.Empty(t, info.Title)
+	assert.Empty(t, info.Cover)
+	assert.Zero(t, info.Viewers)
+	assert.NotEmpty(t, info.Avatar)
+
+	_, err = ParseChannelInfo(readFixture(t, "channel_banned.json"))
+	assert.Equal(t, ErrChannelBanned, err)
+
+	_, err = ParseChannelInfo(readFixture(t, "channel_not_exist.json"))
+	assert.Equal(t, ErrChannelNotExist, err)
+
+	_, err = ParseChannelInfo([]byte("<html>"))
+	assert.NotNil(t, err)
+}
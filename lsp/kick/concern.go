@@ -0,0 +1,192 @@
+package kick
+
+import (
+	"fmt"
+
+	"github.com/Sora233/MiraiGo-Template/utils"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus"
+	"github.com/tidwall/buntdb"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+var logger = utils.GetModuleLogger("kick-concern")
+
+const (
+	Live concern_type.Type = "live"
+)
+
+type Concern struct {
+	*StateManager
+}
+
+func (c *Concern) Site() string {
+	return Site
+}
+
+func (c *Concern) Types() []concern_type.Type {
+	return []concern_type.Type{Live}
+}
+
+func (c *Concern) ParseId(s string) (interface{}, error) {
+	return ParseSlug(s)
+}
+
+func (c *Concern) GetStateManager() concern.IStateManager {
+	return c.StateManager
+}
+
+func (c *Concern) Stop() {
+	logger.Trace("正在停止kick concern")
+	logger.Trace("正在停止kick StateManager")
+	c.StateManager.Stop()
+	logger.Trace("kick StateManager已停止")
+	logger.Trace("kick concern已停止")
+}
+
+func (c *Concern) Start() error {
+	c.UseEmitQueue()
+	c.StateManager.UseNotifyGeneratorFunc(c.notifyGenerator())
+	c.StateManager.UseFreshFunc(c.fresh())
+	return c.StateManager.Start()
+}
+
+func (c *Concern) Add(ctx mmsg.IMsgCtx, target mmsg.Target, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+	var err error
+	log := logger.WithFields(mmsg.TargetLogFields(target)).WithField("id", id)
+
+	err = c.StateManager.CheckGroupConcern(target, id, ctype)
+	if err != nil {
+		return nil, err
+	}
+
+	liveInfo, err := c.FindRoom(id.(string), true)
+	if err != nil {
+		log.Errorf("ChannelInfo error %v", err)
+		return nil, fmt.Errorf("查询频道信息失败 %v - %v", id, err)
+	}
+	_, err = c.StateManager.AddGroupConcern(target, id, ctype)
+	if err != nil {
+		return nil, err
+	}
+	return liveInfo, nil
+}
+
+func (c *Concern) Remove(ctx mmsg.IMsgCtx, target mmsg.Target, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+	id := _id.(string)
+	identity, _ := c.Get(id)
+	_, err := c.StateManager.RemoveGroupConcern(target, id, ctype)
+	_ = c.RWCoverTx(func(tx *buntdb.Tx) error {
+		allCtype, err := c.GetConcern(id)
+		if err != nil {
+			return err
+		}
+		if allCtype.Empty() {
+			err = c.DeleteLiveInfo(id)
+		}
+		return err
+	})
+	return identity, err
+}
+
+func (c *Concern) Get(id interface{}) (concern.IdentityInfo, error) {
+	liveInfo, err := c.FindRoom(id.(string), false)
+	if err != nil {
+		return nil, err
+	}
+	return concern.NewIdentity(liveInfo.Slug, liveInfo.GetName()), nil
+}
+
+func (c *Concern) FindRoom(slug string, load bool) (*LiveInfo, error) {
+	var liveInfo *LiveInfo
+	if load {
+		var err error
+		liveInfo, err = ChannelInfo(slug)
+		if err != nil {
+			return nil, err
+		}
+		_ = c.StateManager.AddLiveInfo(liveInfo)
+	}
+	if liveInfo != nil {
+		return liveInfo, nil
+	}
+	return c.StateManager.GetLiveInfo(slug)
+}
+
+func (c *Concern) FindOrLoadRoom(slug string) (*LiveInfo, error) {
+	info, _ := c.FindRoom(slug, false)
+	if info == nil {
+		return c.FindRoom(slug, true)
+	}
+	return info, nil
+}
+
+func (c *Concern) notifyGenerator() concern.NotifyGeneratorFunc {
+	return func(target mmsg.Target, event concern.Event) []concern.Notify {
+		switch info := event.(type) {
+		case *LiveInfo:
+			if info.Living() {
+				info.Logger().WithFields(mmsg.TargetLogFields(target)).Trace("living notify")
+			} else {
+				info.Logger().WithFields(mmsg.TargetLogFields(target)).Trace("noliving notify")
+			}
+			return []concern.Notify{NewConcernLiveNotify(target, info)}
+		default:
+			logger.Errorf("unknown EventType %+v", event)
+			return nil
+		}
+	}
+}
+
+// markChanged 对比上一次的状态，设置开播/下播以及标题变化
+// 没有在直播时接口不返回标题，沿用上一次的标题
+func markChanged(oldInfo, liveInfo *LiveInfo) {
+	if oldInfo == nil {
+		liveInfo.liveStatusChanged = true
+		return
+	}
+	if !liveInfo.Living() && len(liveInfo.Title) == 0 {
+		liveInfo.Title = oldInfo.Title
+	}
+	if oldInfo.Living() != liveInfo.Living() {
+		liveInfo.liveStatusChanged = true
+	}
+	if oldInfo.Title != liveInfo.Title {
+		liveInfo.liveTitleChanged = true
+	}
+}
+
+func (c *Concern) fresh() concern.FreshFunc {
+	return c.EmitQueueFresher(func(ctype concern_type.Type, id interface{}) ([]concern.Event, error) {
+		var result []concern.Event
+		slug := id.(string)
+		if ctype.ContainAll(Live) {
+			oldInfo, _ := c.FindRoom(slug, false)
+			liveInfo, err := c.FindRoom(slug, true)
+			if err == ErrChannelNotExist || err == ErrChannelBanned {
+				logger.WithFields(logrus.Fields{
+					"Slug": slug,
+					"Name": oldInfo.GetName(),
+				}).Warn("频道不存在或被封禁，订阅将失效")
+				c.RemoveAllById(id)
+				return nil, err
+			}
+			if err != nil {
+				return nil, fmt.Errorf("load liveinfo failed %v", err)
+			}
+			markChanged(oldInfo, liveInfo)
+			result = append(result, liveInfo)
+		}
+		return result, nil
+	})
+}
+
+func NewConcern(notify chan<- concern.Notify) *Concern {
+	c := &Concern{
+		StateManager: NewStateManager(notify),
+	}
+	return c
+}
@@ -0,0 +1,13 @@
+package kick
+
+import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+)
+
+type GroupConcernConfig struct {
+	concern.IConfig
+}
+
+func NewGroupConcernConfig(g concern.IConfig) *GroupConcernConfig {
+	return &GroupConcernConfig{g}
+}
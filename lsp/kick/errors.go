@@ -0,0 +1,9 @@
+package kick
+
+import "errors"
+
+var (
+	ErrChannelNotExist = errors.New("频道不存在")
+	ErrChannelBanned   = errors.New("频道已被封禁")
+	ErrInvalidId       = errors.New("无效的kick频道名")
+)
@@ -0,0 +1,9 @@
+package kick
+
+import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+)
+
+func init() {
+	concern.RegisterConcern(NewConcern(concern.GetNotifyChan()))
+}
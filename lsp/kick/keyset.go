@@ -0,0 +1,44 @@
+package kick
+
+import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+)
+
+type keySet struct {
+}
+
+func (l *keySet) GroupAtAllMarkKey(keys ...interface{}) string {
+	return buntdb.KickGroupAtAllMarkKey(keys...)
+}
+
+func (l *keySet) GroupConcernConfigKey(keys ...interface{}) string {
+	return buntdb.KickGroupConcernConfigKey(keys...)
+}
+
+func (l *keySet) GroupConcernStateKey(keys ...interface{}) string {
+	return buntdb.KickGroupConcernStateKey(keys...)
+}
+
+func (l *keySet) FreshKey(keys ...interface{}) string {
+	return buntdb.KickFreshKey(keys...)
+}
+
+func (l *keySet) ParseGroupConcernStateKey(key string) (mmsg.Target, interface{}, error) {
+	return concern.ParseConcernStateKeyWithString(key)
+}
+
+type extraKey struct{}
+
+func (k extraKey) CurrentLiveKey(keys ...interface{}) string {
+	return buntdb.KickCurrentLiveKey(keys...)
+}
+
+func NewExtraKey() *extraKey {
+	return &extraKey{}
+}
+
+func NewKeySet() *keySet {
+	return &keySet{}
+}
@@ -0,0 +1,14 @@
+package kick
+
+const (
+	Site = "kick"
+	Host = "https://kick.com"
+)
+
+func KickPath(path string) string {
+	return Host + "/" + path
+}
+
+func ChannelApiPath(slug string) string {
+	return Host + "/api/v2/channels/" + slug
+}
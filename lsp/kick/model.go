@@ -0,0 +1,134 @@
+package kick
+
+import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
+	"github.com/sirupsen/logrus"
+)
+
+type LiveInfo struct {
+	Slug     string `json:"slug"`
+	RoomUrl  string `json:"room_url"`
+	Avatar   string `json:"avatar"`
+	Name     string `json:"name"`
+	Title    string `json:"title"`
+	Category string `json:"category"`
+	Cover    string `json:"cover"`
+	IsLiving bool   `json:"living"`
+	// Viewers 当前观看人数，只有正在直播时有值
+	Viewers int64 `json:"viewers"`
+
+	msgCache          template.MsgCache
+	liveStatusChanged bool
+	liveTitleChanged  bool
+}
+
+func (m *LiveInfo) TitleChanged() bool {
+	return m.liveTitleChanged
+}
+
+func (m *LiveInfo) IsLive() bool {
+	return true
+}
+
+func (m *LiveInfo) Living() bool {
+	return m.IsLiving
+}
+
+func (m *LiveInfo) LiveStatusChanged() bool {
+	return m.liveStatusChanged
+}
+
+func (m *LiveInfo) AnchorName() string {
+	return m.Name
+}
+
+func (m *LiveInfo) RoomTitle() string {
+	return m.Title
+}
+
+func (m *LiveInfo) Popularity() int64 {
+	return m.Viewers
+}
+
+func (m *LiveInfo) GetUid() interface{} {
+	return m.Slug
+}
+
+func (m *LiveInfo) GetName() string {
+	if m == nil {
+		return ""
+	}
+	return m.Name
+}
+
+func (m *LiveInfo) Type() concern_type.Type {
+	return Live
+}
+
+func (m *LiveInfo) Logger() *logrus.Entry {
+	return logger.WithFields(logrus.Fields{
+		"Site":   Site,
+		"Name":   m.Name,
+		"Slug":   m.Slug,
+		"Title":  m.Title,
+		"Living": m.IsLiving,
+	})
+}
+
+func (m *LiveInfo) Site() string {
+	return Site
+}
+
+func (m *LiveInfo) GetMSG(locale string) *mmsg.MSG {
+	return m.msgCache.Get(locale, func() *mmsg.MSG {
+		cover := m.Cover
+		if len(cover) == 0 {
+			cover = m.Avatar
+		}
+		var data = map[string]interface{}{
+			"title":    m.Title,
+			"name":     m.Name,
+			"url":      m.RoomUrl,
+			"category": m.Category,
+			"cover":    cover,
+			"living":   m.Living(),
+		}
+		msg, err := template.ExecNotify(locale, "notify.group.kick.live.tmpl", data)
+		if err != nil {
+			logger.Errorf("kick: LiveInfo LoadAndExec error %v", err)
+		}
+		return msg
+	})
+}
+
+type ConcernLiveNotify struct {
+	*LiveInfo
+	Target mmsg.Target `json:"target"`
+}
+
+func (notify *ConcernLiveNotify) GetTarget() mmsg.Target {
+	return notify.Target
+}
+
+func (notify *ConcernLiveNotify) ToMessage() (m *mmsg.MSG) {
+	return notify.LiveInfo.GetMSG(template.NotifyLocale(notify.Target))
+}
+
+func (notify *ConcernLiveNotify) Logger() *logrus.Entry {
+	if notify == nil {
+		return logger
+	}
+	return notify.LiveInfo.Logger().WithFields(mmsg.TargetLogFields(notify.Target))
+}
+
+func NewConcernLiveNotify(target mmsg.Target, l *LiveInfo) *ConcernLiveNotify {
+	if l == nil {
+		return nil
+	}
+	return &ConcernLiveNotify{
+		l,
+		target,
+	}
+}
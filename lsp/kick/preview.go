@@ -0,0 +1,27 @@
+package kick
+
+import (
+	"errors"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+)
+
+// Preview 使用当前的直播间状态，并当作刚刚发生变化处理
+func (c *Concern) Preview(target mmsg.Target, id interface{}, ctype concern_type.Type, limit int) ([]concern.Notify, error) {
+	if ctype != Live {
+		return nil, errors.New("该类型不支持预览")
+	}
+	// 不能使用 FindRoom，它会保存直播状态，导致刷新时错过真正的开播推送
+	liveInfo, err := ChannelInfo(id.(string))
+	if err != nil {
+		return nil, err
+	}
+	// 与 markChanged 相同，没有在直播时沿用上一次的标题
+	if oldInfo, _ := c.GetLiveInfo(id.(string)); oldInfo != nil && !liveInfo.Living() && len(liveInfo.Title) == 0 {
+		liveInfo.Title = oldInfo.Title
+	}
+	liveInfo.liveStatusChanged = true
+	return []concern.Notify{NewConcernLiveNotify(target, liveInfo)}, nil
+}
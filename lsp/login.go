@@ -0,0 +1,51 @@
+package lsp
+
+import (
+	"runtime/debug"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/interfaces"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
+)
+
+// LoginStatusNotify 订阅模块的账号即将过期或已过期时提醒管理员重新登录
+func (l *Lsp) LoginStatusNotify(statusChan <-chan interface{}) {
+	defer func() {
+		if err := recover(); err != nil {
+			logger.WithField("stack", string(debug.Stack())).
+				Errorf("login status notify recoverd %v", err)
+			go l.LoginStatusNotify(statusChan)
+		}
+	}()
+	for msg := range statusChan {
+		status, ok := msg.(*interfaces.LoginStatus)
+		if !ok || status == nil {
+			continue
+		}
+		m := mmsg.NewMSG()
+		if status.Account != "" {
+			m.Textf("DDBOT管理员您好，%v账号%v的cookie%v", status.Site, status.Account, status.Status)
+		} else {
+			m.Textf("DDBOT管理员您好，%v账号的cookie%v", status.Site, status.Status)
+		}
+		if !status.Expire.IsZero() {
+			m.Textf("（过期时间：%v）", status.Expire.Format(time.DateTime))
+		}
+		if status.Account != "" {
+			// 额外配置的账号不能扫码登录，需要修改配置
+			m.Text("，请更新配置文件中该账号的cookie，保存后自动生效")
+		} else {
+			m.Textf("，请私聊输入<%v -s %v>(不含括号)扫码重新登录，登录后立即生效，无需重启",
+				l.CommandShowName(LoginCommand), status.Site)
+		}
+		for _, admin := range l.PermissionStateManager.ListAdmin() {
+			if localutils.GetBot().FindFriend(admin) == nil {
+				continue
+			}
+			logger.WithField("Target", admin).WithField("Site", status.Site).
+				Infof("login status notify: %v", status.Status)
+			l.SendMsg(m, mmsg.NewPrivateTarget(admin))
+		}
+	}
+}
@@ -3,6 +3,8 @@ package lsp
 import (
 	"github.com/Mrs4s/MiraiGo/message"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
+	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
 	"github.com/sirupsen/logrus"
 )
 
@@ -16,6 +18,10 @@ type MessageContext struct {
 	Log                   *logrus.Entry
 	Target                mmsg.Target
 	Sender                *message.Sender
+	// Locale 回复使用的语言
+	Locale string
+	// TemplateMsgFunc 使用模板生成回复，会附带群号、发送者等公共变量，没有设置时直接执行模板
+	TemplateMsgFunc func(name string, data map[string]interface{}) *mmsg.MSG
 }
 
 func (c *MessageContext) TextSend(text string) interface{} {
@@ -34,6 +40,46 @@ func (c *MessageContext) Send(m *mmsg.MSG) interface{} {
 	return c.SendFunc(m)
 }
 
+// TemplateMsg 使用模板 name 生成消息，模板执行失败时返回nil
+func (c *MessageContext) TemplateMsg(name string, data map[string]interface{}) *mmsg.MSG {
+	if c.TemplateMsgFunc != nil {
+		return c.TemplateMsgFunc(name, data)
+	}
+	m, err := template.LoadAndExec(name, data)
+	if err != nil {
+		logger.WithField("template_name", name).Errorf("LoadAndExec error %v", err)
+		return nil
+	}
+	return m
+}
+
+// TemplateText 使用模板 name 生成文本，用于需要拼接或者分段发送的回复
+func (c *MessageContext) TemplateText(name string, data map[string]interface{}) string {
+	m := c.TemplateMsg(name, data)
+	if m == nil {
+		return ""
+	}
+	return msgstringer.MsgToString(m.Elements())
+}
+
+// TemplateReply 使用模板 name 生成消息并回复
+func (c *MessageContext) TemplateReply(name string, data map[string]interface{}) interface{} {
+	m := c.TemplateMsg(name, data)
+	if m == nil {
+		return nil
+	}
+	return c.ReplyFunc(m)
+}
+
+// TemplateSend 使用模板 name 生成消息并发送
+func (c *MessageContext) TemplateSend(name string, data map[string]interface{}) interface{} {
+	m := c.TemplateMsg(name, data)
+	if m == nil {
+		return nil
+	}
+	return c.SendFunc(m)
+}
+
 func (c *MessageContext) NoPermissionReply() interface{} {
 	return c.NoPermissionReplyFunc()
 }
@@ -0,0 +1,84 @@
+package lsp
+
+import (
+	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/version"
+	"github.com/tidwall/buntdb"
+	"strconv"
+	"strings"
+)
+
+// v2KeySuffix 订阅相关的key名称后缀，格式为 <name>:<groupCode>:<id>
+var v2KeySuffix = []string{"ConcernState", "ConcernConfig", "GroupAtAll", "GroupAtAllMark"}
+
+// V2 支持私聊订阅后，订阅相关的key中的群号码改为 mmsg.Target 格式，例如 123456 -> g123456
+type V2 struct {
+}
+
+func (v *V2) migrateKey(key string) (string, bool) {
+	keys := strings.Split(key, ":")
+	if len(keys) != 3 {
+		return key, false
+	}
+	var matched bool
+	for _, suffix := range v2KeySuffix {
+		if strings.HasSuffix(keys[0], suffix) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return key, false
+	}
+	groupCode, err := strconv.ParseInt(keys[1], 10, 64)
+	if err != nil {
+		return key, false
+	}
+	keys[1] = mmsg.NewGroupTarget(groupCode).String()
+	return strings.Join(keys, ":"), true
+}
+
+func (v *V2) Func() version.MigrationFunc {
+	return func() error {
+		return localdb.RWCoverTx(func(tx *buntdb.Tx) error {
+			var data [][3]string
+			err := tx.Ascend("", func(key, value string) bool {
+				if newKey, ok := v.migrateKey(key); ok {
+					data = append(data, [3]string{key, newKey, value})
+				}
+				return true
+			})
+			if err != nil {
+				return err
+			}
+			for _, kv := range data {
+				oldKey, key, value := kv[0], kv[1], kv[2]
+				expire, err := tx.TTL(oldKey)
+				if err != nil && localdb.IsNotFound(err) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				if expire > 0 {
+					_, _, err = tx.Set(key, value, localdb.ExpireOption(expire))
+				} else {
+					_, _, err = tx.Set(key, value, nil)
+				}
+				if err != nil {
+					return err
+				}
+				if _, err = tx.Delete(oldKey); err != nil && !localdb.IsNotFound(err) {
+					return err
+				}
+			}
+			logger.Infof("V2 迁移了%v个订阅相关的key", len(data))
+			return nil
+		})
+	}
+}
+
+func (v *V2) TargetVersion() int64 {
+	return 2
+}
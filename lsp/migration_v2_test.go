@@ -0,0 +1,49 @@
+package lsp
+
+import (
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/buntdb"
+	"testing"
+	"time"
+)
+
+func TestMigrationV2(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	_, err := version.SetVersion(LspVersionName, 1)
+	assert.Nil(t, err)
+
+	assert.Nil(t, localdb.Set(localdb.BilibiliGroupConcernStateKey(test.G1, test.UID1), "live"))
+	assert.Nil(t, localdb.Set(localdb.BilibiliGroupConcernConfigKey(test.G1, test.UID1), "{}"))
+	assert.Nil(t, localdb.Set("weiboGroupConcernState:123456:test", "news"))
+	assert.Nil(t, localdb.Set("weiboGroupAtAllMark:123456:test", "", localdb.SetExpireOpt(time.Hour)))
+	assert.Nil(t, localdb.Set(localdb.BilibiliGroupConcernStateKey(mmsg.NewPrivateTarget(test.UID2), test.UID1), "news"))
+	assert.Nil(t, localdb.Set(localdb.GroupPermissionKey(test.G1, test.UID1, "GroupAdmin"), ""))
+
+	assert.Nil(t, version.DoMigration(LspVersionName, lspMigrationMap))
+
+	assert.EqualValues(t, 2, version.GetCurrentVersion(LspVersionName))
+
+	val, err := localdb.Get(localdb.BilibiliGroupConcernStateKey(mmsg.NewGroupTarget(test.G1), test.UID1))
+	assert.Nil(t, err)
+	assert.Equal(t, "live", val)
+	assert.False(t, localdb.Exist(localdb.BilibiliGroupConcernStateKey(test.G1, test.UID1)))
+
+	assert.True(t, localdb.Exist(localdb.BilibiliGroupConcernConfigKey(mmsg.NewGroupTarget(test.G1), test.UID1)))
+	assert.True(t, localdb.Exist("weiboGroupConcernState:g123456:test"))
+
+	assert.Nil(t, localdb.RCoverTx(func(tx *buntdb.Tx) error {
+		ttl, err := tx.TTL("weiboGroupAtAllMark:g123456:test")
+		assert.True(t, ttl > 0)
+		return err
+	}))
+
+	// 已经是新格式的key和无关的key不受影响
+	assert.True(t, localdb.Exist(localdb.BilibiliGroupConcernStateKey(mmsg.NewPrivateTarget(test.UID2), test.UID1)))
+	assert.True(t, localdb.Exist(localdb.GroupPermissionKey(test.G1, test.UID1, "GroupAdmin")))
+}
@@ -1,5 +1,13 @@
 package mmsg
 
+import (
+	"errors"
+	"strconv"
+
+	"github.com/cnxysoft/DDBOT-WSa/utils"
+	"github.com/sirupsen/logrus"
+)
+
 type TargetType int32
 
 const (
@@ -15,9 +23,14 @@ func (t TargetType) IsPrivate() bool {
 	return t == TargetPrivate
 }
 
+var ErrInvalidTarget = errors.New("invalid target")
+
+// Target 是消息的接收方
+// String 返回的格式可以使用 ParseTarget 解析，也会作为订阅数据库key的一部分
 type Target interface {
 	TargetType() TargetType
 	TargetCode() int64
+	String() string
 }
 
 type PrivateTarget struct {
@@ -32,6 +45,10 @@ func (t *PrivateTarget) TargetCode() int64 {
 	return t.Uin
 }
 
+func (t *PrivateTarget) String() string {
+	return "p" + strconv.FormatInt(t.Uin, 10)
+}
+
 type GroupTarget struct {
 	GroupCode int64 `json:"group_code"`
 }
@@ -44,6 +61,10 @@ func (t *GroupTarget) TargetCode() int64 {
 	return t.GroupCode
 }
 
+func (t *GroupTarget) String() string {
+	return "g" + strconv.FormatInt(t.GroupCode, 10)
+}
+
 func NewGroupTarget(groupCode int64) *GroupTarget {
 	return &GroupTarget{GroupCode: groupCode}
 }
@@ -51,3 +72,41 @@ func NewGroupTarget(groupCode int64) *GroupTarget {
 func NewPrivateTarget(uin int64) *PrivateTarget {
 	return &PrivateTarget{Uin: uin}
 }
+
+// ParseTarget 解析 Target.String 的结果，群为 g<群号>，私聊为 p<QQ号>
+func ParseTarget(s string) (Target, error) {
+	if len(s) < 2 {
+		return nil, ErrInvalidTarget
+	}
+	code, err := strconv.ParseInt(s[1:], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidTarget
+	}
+	switch s[0] {
+	case 'g':
+		return NewGroupTarget(code), nil
+	case 'p':
+		return NewPrivateTarget(code), nil
+	default:
+		return nil, ErrInvalidTarget
+	}
+}
+
+// TargetEqual 判断两个 Target 是否是同一个接收方
+func TargetEqual(a, b Target) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.TargetType() == b.TargetType() && a.TargetCode() == b.TargetCode()
+}
+
+// TargetLogFields 根据 Target 的类型返回群或者好友的日志字段
+func TargetLogFields(target Target) logrus.Fields {
+	if target == nil {
+		return logrus.Fields{}
+	}
+	if target.TargetType().IsPrivate() {
+		return utils.FriendLogFields(target.TargetCode())
+	}
+	return utils.GroupLogFields(target.TargetCode())
+}
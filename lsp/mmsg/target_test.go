@@ -17,3 +17,20 @@ func TestTarget(t *testing.T) {
 	assert.False(t, gt.TargetType().IsPrivate())
 	assert.EqualValues(t, test.ID2, gt.TargetCode())
 }
+
+func TestParseTarget(t *testing.T) {
+	for _, target := range []Target{NewGroupTarget(test.G1), NewPrivateTarget(test.UID1)} {
+		parsed, err := ParseTarget(target.String())
+		assert.Nil(t, err)
+		assert.True(t, TargetEqual(target, parsed))
+	}
+	assert.Equal(t, "g123", NewGroupTarget(123).String())
+	assert.Equal(t, "p456", NewPrivateTarget(456).String())
+	assert.False(t, TargetEqual(NewGroupTarget(123), NewPrivateTarget(123)))
+	assert.False(t, TargetEqual(NewGroupTarget(123), nil))
+
+	for _, s := range []string{"", "g", "123", "x123", "gabc"} {
+		_, err := ParseTarget(s)
+		assert.Equal(t, ErrInvalidTarget, err, s)
+	}
+}
@@ -1,6 +1,7 @@
 package lsp
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
@@ -19,12 +20,19 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/image_pool"
 	"github.com/cnxysoft/DDBOT-WSa/image_pool/local_pool"
 	"github.com/cnxysoft/DDBOT-WSa/image_pool/lolicon_pool"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/alias"
 	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/cfg"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/eventbus"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/interfaces"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/msgarchive"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/outbox"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/permission"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/person"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/scheduler"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/version"
 	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
@@ -37,7 +45,6 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/tidwall/buntdb"
 	"go.uber.org/atomic"
-	"golang.org/x/sync/semaphore"
 )
 
 const ModuleName = "me.sora233.Lsp"
@@ -56,14 +63,14 @@ var Debug = false
 var online = false
 
 type Lsp struct {
-	pool          image_pool.Pool
-	concernNotify <-chan concern.Notify
-	stop          chan interface{}
-	wg            sync.WaitGroup
-	status        *Status
-	notifyWg      sync.WaitGroup
-	msgLimit      *semaphore.Weighted
-	cron          *cron.Cron
+	pool            image_pool.Pool
+	concernNotify   <-chan concern.Notify
+	stop            chan interface{}
+	wg              sync.WaitGroup
+	status          *Status
+	notifyWg        sync.WaitGroup
+	notifyScheduler *scheduler.Scheduler
+	cron            *cron.Cron
 
 	PermissionStateManager *permission.StateManager
 	LspStateManager        *StateManager
@@ -92,7 +99,7 @@ func (l *Lsp) Init() {
 		log.Infof("设置logLevel为%v", lev.String())
 	}
 
-	l.msgLimit = semaphore.NewWeighted(int64(cfg.GetNotifyParallel()))
+	l.notifyScheduler = scheduler.New(cfg.GetNotifyParallel(), cfg.GetNotifyGlobalLimit(), cfg.GetNotifyGroupLimit())
 
 	if Tags != "UNKNOWN" {
 		logger.Infof("DDBOT版本：Release版本【%v】", Tags)
@@ -198,18 +205,28 @@ func (l *Lsp) Init() {
 		mainlandProxies := config.GlobalConfig.GetStringSlice("localProxyPool.mainland")
 		var proxies []*local_proxy_pool.Proxy
 		for _, proxy := range overseaProxies {
-			proxies = append(proxies, &local_proxy_pool.Proxy{
-				Type:  proxy_pool.PreferOversea,
-				Proxy: proxy,
-			})
+			proxies = append(proxies, local_proxy_pool.ParseProxy(proxy, proxy_pool.PreferOversea))
 		}
 		for _, proxy := range mainlandProxies {
-			proxies = append(proxies, &local_proxy_pool.Proxy{
-				Type:  proxy_pool.PreferMainland,
-				Proxy: proxy,
-			})
-		}
-		pool := local_proxy_pool.NewLocalPool(proxies)
+			proxies = append(proxies, local_proxy_pool.ParseProxy(proxy, proxy_pool.PreferMainland))
+		}
+		var checkInterval = 5 * time.Minute
+		if config.GlobalConfig.IsSet("localProxyPool.checkInterval") {
+			checkInterval = config.GlobalConfig.GetDuration("localProxyPool.checkInterval")
+		}
+		pool := local_proxy_pool.NewLocalPool(proxies,
+			local_proxy_pool.WithSticky(config.GlobalConfig.GetBool("localProxyPool.sticky")),
+			local_proxy_pool.WithHealthCheck(checkInterval, map[proxy_pool.Prefer]string{
+				proxy_pool.PreferOversea:  config.GlobalConfig.GetString("localProxyPool.checkUrl.oversea"),
+				proxy_pool.PreferMainland: config.GlobalConfig.GetString("localProxyPool.checkUrl.mainland"),
+			}),
+			local_proxy_pool.WithEvict(
+				config.GlobalConfig.GetInt("localProxyPool.failThreshold"),
+				config.GlobalConfig.GetDuration("localProxyPool.evictDuration"),
+				0,
+			),
+			local_proxy_pool.WithFailWindow(config.GlobalConfig.GetDuration("localProxyPool.failWindow")),
+		)
 		proxy_pool.Init(pool)
 		log.WithField("local_proxy_num", len(proxies)).Debug("debug")
 		l.status.ProxyPoolEnable = true
@@ -226,6 +243,7 @@ func (l *Lsp) Init() {
 	config.GlobalConfig.OnConfigChange(func(in fsnotify.Event) {
 		go cfg.ReloadCustomCommandPrefix()
 		l.CronjobReload()
+		eventbus.BusObj.Publish("config_reload", in.Name)
 	})
 }
 
@@ -500,8 +518,8 @@ func (l *Lsp) Serve(bot *bot.Bot) {
 			WithField("MemberCount", event.Group.MemberCount)
 		for _, c := range concern.ListConcern() {
 			_, ids, _, err := c.GetStateManager().ListConcernState(
-				func(groupCode int64, id interface{}, p concern_type.Type) bool {
-					return groupCode == event.Group.Code
+				func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
+					return mmsg.TargetEqual(target, mmsg.NewGroupTarget(event.Group.Code))
 				})
 			if err != nil {
 				log = log.WithField(fmt.Sprintf("%v订阅", c.Site()), "查询失败")
@@ -566,6 +584,12 @@ func (l *Lsp) Serve(bot *bot.Bot) {
 		if err := l.LspStateManager.SaveMessageImageUrl(msg.GroupCode, msg.Id, msg.Elements); err != nil {
 			logger.Errorf("SaveMessageImageUrl failed %v", err)
 		}
+		if err := l.LspStateManager.SaveMessageFile(msg.GroupCode, msg.Id, msg.Elements); err != nil {
+			logger.Errorf("SaveMessageFile failed %v", err)
+		}
+		if err := msgarchive.Save(msg); err != nil {
+			logger.Errorf("msgarchive.Save failed %v", err)
+		}
 		if !l.started.Load() {
 			return
 		}
@@ -591,6 +615,12 @@ func (l *Lsp) Serve(bot *bot.Bot) {
 		if err := l.LspStateManager.SaveMessageImageUrl(msg.GroupCode, msg.Id, msg.Elements); err != nil {
 			logger.Errorf("SaveMessageImageUrl failed %v", err)
 		}
+		if err := l.LspStateManager.SaveMessageFile(msg.GroupCode, msg.Id, msg.Elements); err != nil {
+			logger.Errorf("SaveMessageFile failed %v", err)
+		}
+		if err := msgarchive.Save(msg); err != nil {
+			logger.Errorf("msgarchive.Save failed %v", err)
+		}
 	})
 
 	bot.GroupMuteEvent.Subscribe(func(qqClient *client.QQClient, event *client.GroupMuteEvent) {
@@ -782,7 +812,11 @@ func (l *Lsp) PostStart(bot *bot.Bot) {
 	}()
 	l.CronjobReload()
 	l.CronStart()
+	go l.LoginStatusNotify(eventbus.BusObj.Subscribe(interfaces.TopicLoginStatus))
+	concern.SetDeletedPostHandler(l.handleDeletedPost)
+	template.SetNotifyLocale(l.notifyLocale)
 	concern.StartAll()
+	outbox.Start(l.sendOutbox)
 	l.started.Store(true)
 
 	var newVersionChan = make(chan string, 1)
@@ -804,6 +838,7 @@ func (l *Lsp) PostStart(bot *bot.Bot) {
 }
 
 func (l *Lsp) Start(bot *bot.Bot) {
+	l.notifyScheduler.Start()
 	go l.ConcernNotify()
 }
 
@@ -814,9 +849,15 @@ func (l *Lsp) Stop(bot *bot.Bot, wg *sync.WaitGroup) {
 	}
 	l.CronStop()
 	concern.StopAll()
+	concern.SetDeletedPostHandler(nil)
+	template.SetNotifyLocale(nil)
+	outbox.Stop()
 
 	l.wg.Wait()
 	logger.Debug("等待所有推送发送完毕")
+	if dropped := l.notifyScheduler.Stop(); dropped > 0 {
+		logger.Warnf("停止时仍有%v条推送在排队，已舍弃", dropped)
+	}
 	l.notifyWg.Wait()
 	logger.Debug("推送发送完毕")
 
@@ -883,6 +924,8 @@ func (l *Lsp) RemoveAllByGroup(groupCode int64) {
 		c.GetStateManager().RemoveAllByGroupCode(groupCode)
 	}
 	l.PermissionStateManager.RemoveAllByGroupCode(groupCode)
+	person.RemoveAllByGroupCode(groupCode)
+	alias.RemoveAllByGroupCode(groupCode)
 }
 
 func (l *Lsp) GetImageFromPool(options ...image_pool.OptionFunc) ([]image_pool.Image, error) {
@@ -892,7 +935,7 @@ func (l *Lsp) GetImageFromPool(options ...image_pool.OptionFunc) ([]image_pool.I
 	return l.pool.Get(options...)
 }
 
-func (l *Lsp) send(msg *message.SendingMessage, target mmsg.Target) interface{} {
+func (l *Lsp) send(msg *message.SendingMessage, target mmsg.Target) (interface{}, error) {
 	switch target.TargetType() {
 	case mmsg.TargetGroup:
 		return l.sendGroupMessage(target.TargetCode(), msg)
@@ -904,7 +947,13 @@ func (l *Lsp) send(msg *message.SendingMessage, target mmsg.Target) interface{}
 
 // SendMsg 总是返回至少一个
 func (l *Lsp) SendMsg(m *mmsg.MSG, target mmsg.Target) (res []interface{}) {
-	msgs := m.ToMessage(target)
+	res, _, _ = l.sendMsg(m.ToMessage(target), target)
+	return res
+}
+
+// sendMsg 依次发送msgs，遇到发送失败时停止，返回没有发送成功的消息以及失败的原因，
+// err为nil时表示全部发送成功或者失败无法重试（例如空消息、协议端离线缓存）
+func (l *Lsp) sendMsg(msgs []*message.SendingMessage, target mmsg.Target) (res []interface{}, remain []*message.SendingMessage, err error) {
 	if len(msgs) == 0 {
 		switch target.TargetType() {
 		case mmsg.TargetPrivate:
@@ -915,16 +964,28 @@ func (l *Lsp) SendMsg(m *mmsg.MSG, target mmsg.Target) (res []interface{}) {
 		return
 	}
 	for idx, msg := range msgs {
-		r := l.send(msg, target)
+		r, sendErr := l.send(msg, target)
 		res = append(res, r)
 		if reflect.ValueOf(r).Elem().FieldByName("Id").Int() == -1 {
+			if sendErr != nil {
+				remain, err = msgs[idx:], sendErr
+			}
 			break
 		}
 		if idx > 1 {
 			time.Sleep(time.Millisecond * 300)
 		}
 	}
-	return res
+	return
+}
+
+// sendOutbox 重试发件箱中的消息
+func (l *Lsp) sendOutbox(target mmsg.Target, msgs []*message.SendingMessage) ([]*message.SendingMessage, error) {
+	_, remain, err := l.sendMsg(msgs, target)
+	if err == nil && len(remain) == 0 {
+		return nil, nil
+	}
+	return remain, err
 }
 
 func (l *Lsp) GM(res []interface{}) []*message.GroupMessage {
@@ -943,13 +1004,14 @@ func (l *Lsp) PM(res []interface{}) []*message.PrivateMessage {
 	return result
 }
 
-func (l *Lsp) sendPrivateMessage(uin int64, msg *message.SendingMessage) (res *message.PrivateMessage) {
+// sendPrivateMessage 发送一条私聊消息，返回值总是非nil，Id为-1表示发送失败，可以重试的失败会返回error
+func (l *Lsp) sendPrivateMessage(uin int64, msg *message.SendingMessage) (res *message.PrivateMessage, err error) {
 	if bot.Instance == nil || !bot.Instance.Online.Load() {
-		return &message.PrivateMessage{Id: -1, Elements: msg.Elements}
+		return &message.PrivateMessage{Id: -1, Elements: msg.Elements}, outbox.NewSendError(outbox.ReasonNetwork, errors.New("BOT不在线"))
 	}
 	if msg == nil {
 		logger.WithFields(localutils.FriendLogFields(uin)).Debug("send with nil private message")
-		return &message.PrivateMessage{Id: -1}
+		return &message.PrivateMessage{Id: -1}, nil
 	}
 	//logger.Debugf("发送私聊消息：%v\n", msgstringer.MsgToString(msg.Elements))
 	msg.Elements = localutils.MessageFilter(msg.Elements, func(element message.IMessageElement) bool {
@@ -957,7 +1019,7 @@ func (l *Lsp) sendPrivateMessage(uin int64, msg *message.SendingMessage) (res *m
 	})
 	if len(msg.Elements) == 0 {
 		logger.WithFields(localutils.FriendLogFields(uin)).Debug("send with empty private message")
-		return &message.PrivateMessage{Id: -1}
+		return &message.PrivateMessage{Id: -1}, nil
 	}
 	var newstring = msgstringer.MsgToString(msg.Elements)
 	res = bot.Instance.SendPrivateMessage(uin, msg, newstring)
@@ -965,16 +1027,22 @@ func (l *Lsp) sendPrivateMessage(uin int64, msg *message.SendingMessage) (res *m
 		logger.WithField("content", msgstringer.MsgToString(msg.Elements)).
 			WithFields(localutils.GroupLogFields(uin)).
 			Errorf("发送私聊消息失败")
+		// 协议端没有返回失败的原因
+		if message.EstimateLength(msg.Elements) > message.MaxMessageSize {
+			err = outbox.NewSendError(outbox.ReasonTooLong, nil)
+		} else {
+			err = outbox.NewSendError(outbox.ReasonUnknown, nil)
+		}
 	}
 	if res == nil {
 		res = &message.PrivateMessage{Id: -1, Elements: msg.Elements}
 	}
-	return res
+	return res, err
 }
 
-// sendGroupMessage 发送一条消息，返回值总是非nil，Id为-1表示发送失败
+// sendGroupMessage 发送一条消息，返回值总是非nil，Id为-1表示发送失败，可以重试的失败会返回error
 // miraigo偶尔发送消息会panic？！
-func (l *Lsp) sendGroupMessage(groupCode int64, msg *message.SendingMessage, recovered ...bool) (res *message.GroupMessage) {
+func (l *Lsp) sendGroupMessage(groupCode int64, msg *message.SendingMessage, recovered ...bool) (res *message.GroupMessage, err error) {
 	//fmt.Printf("运行到发信息了%v\n", msgstringer.MsgToString(msg.Elements))
 	defer func() {
 		if e := recover(); e != nil {
@@ -982,29 +1050,29 @@ func (l *Lsp) sendGroupMessage(groupCode int64, msg *message.SendingMessage, rec
 				logger.WithField("content", msgstringer.MsgToString(msg.Elements)).
 					WithField("stack", string(debug.Stack())).
 					Errorf("sendGroupMessage panic recovered")
-				res = l.sendGroupMessage(groupCode, msg, true)
+				res, err = l.sendGroupMessage(groupCode, msg, true)
 			} else {
 				logger.WithField("content", msgstringer.MsgToString(msg.Elements)).
 					WithField("stack", string(debug.Stack())).
 					Errorf("sendGroupMessage panic recovered but panic again %v", e)
-				res = &message.GroupMessage{Id: -1, Elements: msg.Elements}
+				res, err = &message.GroupMessage{Id: -1, Elements: msg.Elements}, fmt.Errorf("panic: %v", e)
 			}
 		}
 	}()
 	if bot.Instance == nil {
-		return &message.GroupMessage{Id: -1, Elements: msg.Elements}
+		return &message.GroupMessage{Id: -1, Elements: msg.Elements}, outbox.NewSendError(outbox.ReasonNetwork, errors.New("BOT不在线"))
 	}
 	if l.LspStateManager.IsMuted(groupCode, bot.Instance.Uin) &&
 		!l.PermissionStateManager.CheckGroupAdministrator(groupCode, bot.Instance.Uin) {
 		logger.WithField("content", msgstringer.MsgToString(msg.Elements)).
 			WithFields(localutils.GroupLogFields(groupCode)).
 			Debug("BOT被禁言无法发送群消息")
-		return &message.GroupMessage{Id: -1, Elements: msg.Elements}
+		return &message.GroupMessage{Id: -1, Elements: msg.Elements}, outbox.NewSendError(outbox.ReasonMuted, nil)
 	}
 	if msg == nil {
 		logger.Debug("消息为空，返回")
 		logger.WithFields(localutils.GroupLogFields(groupCode)).Debug("send with nil group message")
-		return &message.GroupMessage{Id: -1}
+		return &message.GroupMessage{Id: -1}, nil
 	}
 	//logger.Debugf("发送群消息：%v\n", msgstringer.MsgToString(msg.Elements))
 	msg.Elements = localutils.MessageFilter(msg.Elements, func(element message.IMessageElement) bool {
@@ -1013,12 +1081,12 @@ func (l *Lsp) sendGroupMessage(groupCode int64, msg *message.SendingMessage, rec
 	if len(msg.Elements) == 0 {
 		//logger.Debug("消息元素为空，返回")
 		logger.WithFields(localutils.GroupLogFields(groupCode)).Debug("send with empty group message")
-		return &message.GroupMessage{Id: -1}
+		return &message.GroupMessage{Id: -1}, nil
 	}
 	var newstring = msgstringer.MsgToString(msg.Elements)
 	ret := bot.Instance.SendGroupMessage(groupCode, msg, newstring)
 	res = ret.RetMSG
-	err := ret.Error
+	err = ret.Error
 	if err != nil {
 		msgStr := msgstringer.MsgToString(msg.Elements)
 		if len(msgStr) > 150 {
@@ -1031,15 +1099,18 @@ func (l *Lsp) sendGroupMessage(groupCode int64, msg *message.SendingMessage, rec
 	if res == nil {
 		logger.WithFields(localutils.GroupLogFields(groupCode)).Debug("failed to send message")
 		res = &message.GroupMessage{Id: -1, Elements: msg.Elements}
+		if err == nil {
+			err = outbox.NewSendError(outbox.ReasonUnknown, nil)
+		}
 	}
-	return res
+	return res, err
 }
 
 var Instance = &Lsp{
 	concernNotify:          concern.ReadNotifyChan(),
 	stop:                   make(chan interface{}),
 	status:                 NewStatus(),
-	msgLimit:               semaphore.NewWeighted(3),
+	notifyScheduler:        scheduler.New(3, 0, 0),
 	PermissionStateManager: permission.NewStateManager(),
 	LspStateManager:        NewStateManager(),
 	cron:                   cron.New(cron.WithLogger(cron.VerbosePrintfLogger(cronLog))),
@@ -0,0 +1,149 @@
+package msgarchive
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/Mrs4s/MiraiGo/message"
+	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/cfg"
+	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
+	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
+	"github.com/tidwall/buntdb"
+)
+
+// defaultSearchLimit 搜索时默认返回的最大条数
+const defaultSearchLimit = 10
+
+// Record 存档的一条群消息
+type Record struct {
+	GroupCode int64  `json:"group_code"`
+	MessageId int32  `json:"message_id"`
+	Uin       int64  `json:"uin"`
+	Name      string `json:"name"`
+	Time      int64  `json:"time"`
+	Content   string `json:"content"`
+}
+
+func (r *Record) key() string {
+	return localdb.GroupMessageArchiveKey(r.GroupCode, r.Time, r.MessageId)
+}
+
+// NewRecord 把群消息转换为存档记录，消息内容使用文本形式保存
+func NewRecord(msg *message.GroupMessage) *Record {
+	r := &Record{
+		GroupCode: msg.GroupCode,
+		MessageId: msg.Id,
+		Time:      int64(msg.Time),
+		Content:   msgstringer.MsgToString(msg.Elements),
+	}
+	if msg.Sender != nil {
+		r.Uin = msg.Sender.Uin
+		r.Name = msg.Sender.DisplayName()
+	}
+	if r.Time == 0 {
+		r.Time = time.Now().Unix()
+	}
+	return r
+}
+
+// Save 存档一条群消息，没有开启存档或者该群不存档时什么也不做
+func Save(msg *message.GroupMessage) error {
+	if msg == nil || !cfg.GetMessageArchiveEnable() {
+		return nil
+	}
+	_, err := save(NewRecord(msg))
+	return err
+}
+
+// save 返回是否是新存档的消息
+func save(r *Record) (bool, error) {
+	retention := cfg.GetMessageArchiveRetention(r.GroupCode)
+	if retention <= 0 || len(r.Content) == 0 {
+		return false, nil
+	}
+	ttl := time.Until(time.Unix(r.Time, 0).Add(retention))
+	if ttl <= 0 {
+		return false, nil
+	}
+	var isOverwrite bool
+	err := localdb.SetJson(r.key(), r, localdb.SetExpireOpt(ttl), localdb.SetGetIsOverwriteOpt(&isOverwrite))
+	return !isOverwrite, err
+}
+
+// Backfill 通过协议端拉取最近 count 条群消息并存档，用于补全bot没有收到的消息，返回新存档的消息数量
+func Backfill(groupCode int64, count int) (int, error) {
+	if !cfg.GetMessageArchiveEnable() {
+		return 0, nil
+	}
+	msgs, err := localutils.GetBot().GetGroupMessageHistory(groupCode, 0, count)
+	if err != nil {
+		return 0, err
+	}
+	var added int
+	for _, msg := range msgs {
+		isNew, err := save(NewRecord(msg))
+		if err != nil {
+			logger.WithField("GroupCode", groupCode).Errorf("save archive error %v", err)
+			continue
+		}
+		if isNew {
+			added++
+		}
+	}
+	return added, nil
+}
+
+// Query 搜索条件，零值表示不限制
+type Query struct {
+	GroupCode int64
+	// Keyword 消息内容中包含的关键字，不区分大小写
+	Keyword string
+	// Uin 发送者
+	Uin   int64
+	Since time.Time
+	Until time.Time
+	// Limit 最多返回的条数，默认为10
+	Limit int
+}
+
+func (q *Query) match(r *Record) bool {
+	if q.Uin != 0 && r.Uin != q.Uin {
+		return false
+	}
+	if !q.Until.IsZero() && r.Time > q.Until.Unix() {
+		return false
+	}
+	if len(q.Keyword) > 0 && !strings.Contains(strings.ToLower(r.Content), strings.ToLower(q.Keyword)) {
+		return false
+	}
+	return true
+}
+
+// Search 按条件搜索群消息存档，结果按时间从新到旧排列
+func Search(q *Query) ([]*Record, error) {
+	var result []*Record
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	err := localdb.RCoverTx(func(tx *buntdb.Tx) error {
+		return tx.DescendKeys(localdb.GroupMessageArchiveKey(q.GroupCode, "*"), func(key, value string) bool {
+			var r = new(Record)
+			if err := json.Unmarshal([]byte(value), r); err != nil {
+				logger.WithField("key", key).Errorf("unmarshal archive error %v", err)
+				return true
+			}
+			if !q.Since.IsZero() && r.Time < q.Since.Unix() {
+				// key按时间排序，之后的消息都更早
+				return false
+			}
+			if q.match(r) {
+				result = append(result, r)
+			}
+			return len(result) < limit
+		})
+	})
+	return result, err
+}
@@ -0,0 +1,118 @@
+package msgarchive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Mrs4s/MiraiGo/message"
+	"github.com/Sora233/MiraiGo-Template/config"
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func newGroupMessage(groupCode int64, id int32, uin int64, t time.Time, text string) *message.GroupMessage {
+	return &message.GroupMessage{
+		Id:        id,
+		GroupCode: groupCode,
+		Sender: &message.Sender{
+			Uin:      uin,
+			Nickname: "name",
+		},
+		Time:     int32(t.Unix()),
+		Elements: []message.IMessageElement{message.NewText(text)},
+	}
+}
+
+func TestArchive(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	var now = time.Now()
+
+	// 未开启时不存档
+	assert.Nil(t, Save(newGroupMessage(test.G1, 1, test.UID1, now, "hello")))
+	result, err := Search(&Query{GroupCode: test.G1})
+	assert.Nil(t, err)
+	assert.Empty(t, result)
+
+	config.GlobalConfig.Set("messageArchive.enable", true)
+	config.GlobalConfig.Set("messageArchive.groups", map[string]interface{}{"654321": "0s"})
+	defer config.GlobalConfig.Set("messageArchive", nil)
+
+	assert.Nil(t, Save(newGroupMessage(test.G1, 1, test.UID1, now.Add(-time.Hour*3), "开播了 https://live.bilibili.com/1")))
+	assert.Nil(t, Save(newGroupMessage(test.G1, 2, test.UID2, now.Add(-time.Hour*2), "hello")))
+	assert.Nil(t, Save(newGroupMessage(test.G1, 3, test.UID1, now.Add(-time.Hour), "Hello World")))
+	// 超过保留时间的消息不存档
+	assert.Nil(t, Save(newGroupMessage(test.G1, 4, test.UID1, now.Add(-time.Hour*100), "hello")))
+	// 保留时间为0的群不存档
+	assert.Nil(t, Save(newGroupMessage(test.G2, 5, test.UID1, now, "hello")))
+
+	result, err = Search(&Query{GroupCode: test.G1})
+	assert.Nil(t, err)
+	assert.Len(t, result, 3)
+	// 从新到旧
+	assert.EqualValues(t, 3, result[0].MessageId)
+	assert.EqualValues(t, 1, result[2].MessageId)
+
+	result, err = Search(&Query{GroupCode: test.G1, Keyword: "HELLO"})
+	assert.Nil(t, err)
+	assert.Len(t, result, 2)
+
+	result, err = Search(&Query{GroupCode: test.G1, Keyword: "live.bilibili.com"})
+	assert.Nil(t, err)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, test.UID1, result[0].Uin)
+		assert.Equal(t, "name", result[0].Name)
+	}
+
+	result, err = Search(&Query{GroupCode: test.G1, Uin: test.UID1})
+	assert.Nil(t, err)
+	assert.Len(t, result, 2)
+
+	result, err = Search(&Query{GroupCode: test.G1, Since: now.Add(-time.Minute * 150)})
+	assert.Nil(t, err)
+	assert.Len(t, result, 2)
+
+	result, err = Search(&Query{GroupCode: test.G1, Until: now.Add(-time.Minute * 150)})
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+
+	result, err = Search(&Query{GroupCode: test.G1, Limit: 1})
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+
+	result, err = Search(&Query{GroupCode: test.G2})
+	assert.Nil(t, err)
+	assert.Empty(t, result)
+}
+
+func TestParseTime(t *testing.T) {
+	var now = time.Date(2021, 6, 1, 12, 0, 0, 0, time.Local)
+
+	tm, err := ParseTime("", now)
+	assert.Nil(t, err)
+	assert.True(t, tm.IsZero())
+
+	tm, err = ParseTime("2h", now)
+	assert.Nil(t, err)
+	assert.Equal(t, now.Add(-time.Hour*2), tm)
+
+	tm, err = ParseTime("21:30", now)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2021, 6, 1, 21, 30, 0, 0, time.Local), tm)
+
+	tm, err = ParseTime("2021-05-31 18:00", now)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2021, 5, 31, 18, 0, 0, 0, time.Local), tm)
+
+	tm, err = ParseTime("05-31 18:00", now)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2021, 5, 31, 18, 0, 0, 0, time.Local), tm)
+
+	tm, err = ParseTime("2021-05-31", now)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2021, 5, 31, 0, 0, 0, 0, time.Local), tm)
+
+	_, err = ParseTime("wrong", now)
+	assert.NotNil(t, err)
+}
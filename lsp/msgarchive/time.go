@@ -0,0 +1,42 @@
+package msgarchive
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var timeLayouts = []string{
+	time.DateTime,
+	"2006-01-02 15:04",
+	time.DateOnly,
+	"01-02 15:04",
+}
+
+// ParseTime 解析搜索时使用的时间，支持：
+// 时长（如 12h、30m，表示 now 之前的这段时间）、
+// 日期时间（如 2006-01-02 15:04、2006-01-02、01-02 15:04）、
+// 当天的时间（如 21:30）
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location()), nil
+	}
+	for _, layout := range timeLayouts {
+		t, err := time.ParseInLocation(layout, s, now.Location())
+		if err != nil {
+			continue
+		}
+		if t.Year() == 0 {
+			t = t.AddDate(now.Year(), 0, 0)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("无法解析时间【%v】", s)
+}
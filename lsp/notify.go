@@ -1,16 +1,15 @@
 package lsp
 
 import (
-	"context"
 	"fmt"
 	"runtime/debug"
-	"time"
 
 	"github.com/Mrs4s/MiraiGo/message"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/outbox"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/scheduler"
 	"github.com/cnxysoft/DDBOT-WSa/utils"
-	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
 	"github.com/sirupsen/logrus"
 )
 
@@ -36,11 +35,12 @@ func (l *Lsp) ConcernNotify() {
 
 			}
 			var inotify = _inotify
-			target := mmsg.NewGroupTarget(inotify.GetGroupCode())
+			target := inotify.GetTarget()
 			nLogger := inotify.Logger()
 
-			if l.LspStateManager.IsMuted(inotify.GetGroupCode(), utils.GetBot().GetUin()) &&
-				!l.PermissionStateManager.CheckGroupAdministrator(inotify.GetGroupCode(), utils.GetBot().GetUin()) {
+			if target.TargetType().IsGroup() &&
+				l.LspStateManager.IsMuted(target.TargetCode(), utils.GetBot().GetUin()) &&
+				!l.PermissionStateManager.CheckGroupAdministrator(target.TargetCode(), utils.GetBot().GetUin()) {
 				nLogger.Info("BOT群内被禁言，跳过本次推送")
 				continue
 			}
@@ -50,24 +50,34 @@ func (l *Lsp) ConcernNotify() {
 				nLogger.Errorf("GetConcernBySiteAndType error %v", err)
 				continue
 			}
-			cfg := c.GetStateManager().GetGroupConcernConfig(inotify.GetGroupCode(), inotify.GetUid())
+			cfg := c.GetStateManager().GetGroupConcernConfig(target, inotify.GetUid())
 			cfg.NotifyBeforeCallback(inotify)
 
 			// 注意notify可能会缓存MSG
 			var m = l.NotifyMessage(inotify).Clone()
 
+			// 同一个人物多个平台开播时只推送一次
+			if m, ok = l.personLiveMessage(inotify, m); !ok {
+				continue
+			}
+			m = l.liveEndMessage(inotify, m)
+
 			// atConfig
 			var atBeforeHook = cfg.AtBeforeHook(inotify)
+			if !target.TargetType().IsGroup() {
+				// 私聊推送没有@
+				atBeforeHook = &concern.HookResult{Reason: "private target"}
+			}
 			if !atBeforeHook.Pass {
 				nLogger.WithField("Reason", atBeforeHook.Reason).Debug("notify @at filtered by hook AtBeforeHook")
 			} else {
 				// 有@全体成员 或者 @Someone
 				var qqadmin = atBeforeHook.Pass &&
-					l.PermissionStateManager.CheckGroupAdministrator(inotify.GetGroupCode(), utils.GetBot().GetUin())
+					l.PermissionStateManager.CheckGroupAdministrator(target.TargetCode(), utils.GetBot().GetUin())
 				var checkAtAll = qqadmin &&
 					cfg.GetGroupConcernAt().CheckAtAll(inotify.Type())
 				var atAllMark = checkAtAll &&
-					c.GetStateManager().CheckAndSetAtAllMark(inotify.GetGroupCode(), inotify.GetUid())
+					c.GetStateManager().CheckAndSetAtAllMark(target, inotify.GetUid())
 				nLogger.WithFields(logrus.Fields{
 					"qqAdmin":    qqadmin,
 					"checkAtAll": checkAtAll,
@@ -83,31 +93,45 @@ func (l *Lsp) ConcernNotify() {
 				}
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
-			if err := l.msgLimit.Acquire(ctx, 1); err != nil {
-				cancel()
-				nLogger.WithField("Content", msgstringer.MsgToString(m.Elements())).
-					Errorf("BOT负载过高，推送已积压超过一分钟，将舍弃本次推送。")
-				continue
-			}
-			cancel()
-			l.notifyWg.Add(1)
-			nLogger.Info("notify")
-			go func() {
-				defer l.notifyWg.Done()
+			priority := notifyPriority(inotify)
+			nLogger.WithField("Priority", priority.String()).Debug("notify queued")
+			l.notifyScheduler.Submit(target.String(), priority, func() {
 				defer func() {
-					l.msgLimit.Release(1)
 					if e := recover(); e != nil {
 						nLogger.WithField("stack", string(debug.Stack())).
 							Errorf("notify panic recovered: %v", e)
 					}
 				}()
-				msgs := l.GM(l.SendMsg(m, target))
-				if len(msgs) > 0 {
+				nLogger.Info("notify")
+				res, remain, sendErr := l.sendMsg(m.ToMessage(target), target)
+				msgs := notifyResults(res)
+				if len(msgs) > 0 && target.TargetType().IsGroup() {
 					cfg.NotifyAfterCallback(inotify, msgs[0])
 				} else {
 					cfg.NotifyAfterCallback(inotify, nil)
 				}
+				var msgIds []int32
+				for _, msg := range msgs {
+					msgIds = append(msgIds, msg.Id)
+				}
+				if err := concern.TrackPostedNotify(cfg, inotify, msgIds); err != nil {
+					nLogger.Errorf("TrackPostedNotify error %v", err)
+				}
+				recordLiveStartMessage(inotify, msgs)
+				if len(msgs) > 0 && msgs[0].Id != -1 {
+					// 上传群文件比较慢，不占用推送的并发限制
+					l.notifyWg.Add(1)
+					go func() {
+						defer l.notifyWg.Done()
+						defer func() {
+							if e := recover(); e != nil {
+								nLogger.WithField("stack", string(debug.Stack())).
+									Errorf("archive notify media panic recovered: %v", e)
+							}
+						}()
+						l.archiveNotifyMedia(cfg, inotify, nLogger)
+					}()
+				}
 				if atBeforeHook.Pass {
 					var atIdsOnce bool
 					for _, msg := range msgs {
@@ -132,9 +156,15 @@ func (l *Lsp) ConcernNotify() {
 								panic(fmt.Sprintf("INTERNAL: len(secondRes) is %v", len(secondRes)))
 							}
 							if secondRes[0].Id == -1 {
-								// 去掉@全员还是发送失败
+								// 去掉@全员还是发送失败，之后重试时也不再@全员
+								if len(remain) > 0 {
+									remain[0] = &message.SendingMessage{Elements: dropAtAll(remain[0].Elements)}
+								}
 								continue
 							}
+							if len(remain) > 0 {
+								remain = remain[1:]
+							}
 							if !atIdsOnce {
 								// 去掉@全员之后发送成功，可能是次数到了，尝试@列表
 								atIdsOnce = true
@@ -152,11 +182,53 @@ func (l *Lsp) ConcernNotify() {
 						}
 					}
 				}
-			}()
+				if sendErr != nil && len(remain) > 0 {
+					source := fmt.Sprintf("%v/%v %v", inotify.Site(), inotify.Type(), inotify.GetUid())
+					if e, err := outbox.Push(target, source, remain, sendErr); err != nil {
+						nLogger.Errorf("outbox push error %v", err)
+					} else if e != nil {
+						nLogger.WithField("OutboxId", e.Id).WithField("Reason", e.Reason).
+							Warn("notify failed, saved to outbox")
+					}
+				}
+			})
 		}
 	}
 }
 
+// notifyPriority 开播推送优先发送，直播间标题修改最后发送，其他推送例如动态和下播使用普通优先级
+func notifyPriority(inotify concern.Notify) scheduler.Priority {
+	ext, ok := inotify.(concern.NotifyLiveExt)
+	if !ok || !ext.IsLive() || !ext.Living() {
+		return scheduler.PriorityNormal
+	}
+	if ext.LiveStatusChanged() {
+		return scheduler.PriorityHigh
+	}
+	return scheduler.PriorityLow
+}
+
+// notifyResults 把SendMsg的结果统一转换为群消息，私聊推送的结果只保留Id和消息内容
+func notifyResults(res []interface{}) []*message.GroupMessage {
+	var result []*message.GroupMessage
+	for _, r := range res {
+		switch msg := r.(type) {
+		case *message.GroupMessage:
+			result = append(result, msg)
+		case *message.PrivateMessage:
+			result = append(result, &message.GroupMessage{Id: msg.Id, Elements: msg.Elements})
+		}
+	}
+	return result
+}
+
+// dropAtAll 去掉消息中的@全体成员
+func dropAtAll(elems []message.IMessageElement) []message.IMessageElement {
+	return utils.MessageFilter(elems, func(element message.IMessageElement) bool {
+		return !(element.Type() == message.At && element.(*message.AtElement).Target == 0)
+	})
+}
+
 func (l *Lsp) NotifyMessage(inotify concern.Notify) *mmsg.MSG {
 	return inotify.ToMessage()
 }
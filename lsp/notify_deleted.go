@@ -0,0 +1,62 @@
+package lsp
+
+import (
+	"strconv"
+
+	"github.com/Mrs4s/MiraiGo/message"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
+)
+
+// DeletedNotifyText 原内容被删除后回复推送消息使用的文本
+const DeletedNotifyText = "原内容已删除"
+
+// handleDeletedPost 推送的原内容被作者删除后，根据订阅当前的配置撤回或者回复推送消息
+func (l *Lsp) handleDeletedPost(p *concern.PostedNotify) {
+	log := logger.WithField("Site", p.Site).WithField("Id", p.Id).
+		WithField("PostId", p.PostId).WithField("Target", p.Target)
+	if len(p.MsgIds) == 0 {
+		return
+	}
+	target, err := mmsg.ParseTarget(p.Target)
+	if err != nil {
+		log.Errorf("ParseTarget error %v", err)
+		return
+	}
+	c, err := concern.GetConcernBySite(p.Site)
+	if err != nil {
+		log.Errorf("GetConcernBySite error %v", err)
+		return
+	}
+	id, err := c.ParseId(p.Id)
+	if err != nil {
+		log.Errorf("ParseId error %v", err)
+		return
+	}
+	var replyId = p.MsgIds[0]
+	switch c.GetStateManager().GetGroupConcernConfig(target, id).GetGroupConcernDeleted().GetAction() {
+	case concern.DeletedActionRecall:
+		var failed []int32
+		for _, msgId := range p.MsgIds {
+			if err := localutils.GetBot().RecallMsg(msgId); err != nil {
+				log.WithField("MsgId", msgId).Errorf("recall notify message failed %v", err)
+				failed = append(failed, msgId)
+			}
+		}
+		if len(failed) == 0 {
+			log.Info("notify recalled because the post is deleted")
+			return
+		}
+		// 撤回失败时（例如超过撤回时间并且BOT不是管理员）改为回复标注
+		replyId = failed[0]
+	case concern.DeletedActionReply:
+	default:
+		return
+	}
+	m := mmsg.NewMSG()
+	m.Append(&message.ReplyElement{ReplySeq: replyId, Id: strconv.Itoa(int(replyId))})
+	m.Text(DeletedNotifyText)
+	l.SendMsg(m, target)
+	log.Info("notify annotated because the post is deleted")
+}
@@ -0,0 +1,83 @@
+package lsp
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Mrs4s/MiraiGo/message"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
+)
+
+// notifyLocale 返回推送目标使用的语言，只有群可以设置语言
+func (l *Lsp) notifyLocale(target mmsg.Target) string {
+	if !target.TargetType().IsGroup() {
+		return i18n.DefaultLocale
+	}
+	return l.PermissionStateManager.GetGroupLocale(target.TargetCode())
+}
+
+// liveEndMessage 下播时如果记录了这场直播的开播推送，改为回复开播推送并附带直播总结
+// 没有记录时（例如BOT启动前就已经开播）仍然使用原本的下播推送
+func (l *Lsp) liveEndMessage(inotify concern.Notify, m *mmsg.MSG) *mmsg.MSG {
+	ext, ok := inotify.(concern.NotifyLiveSummaryExt)
+	if !ok || !ext.IsLive() || ext.Living() || !ext.LiveStatusChanged() ||
+		!inotify.GetTarget().TargetType().IsGroup() {
+		return m
+	}
+	s, err := concern.PopLiveSession(inotify)
+	if err != nil {
+		inotify.Logger().Errorf("PopLiveSession error %v", err)
+		return m
+	}
+	if s == nil || s.MsgId == 0 {
+		return m
+	}
+	var titles []map[string]interface{}
+	for _, t := range s.Titles {
+		titles = append(titles, map[string]interface{}{
+			"time":  t.Time,
+			"title": t.Title,
+		})
+	}
+	locale := template.NotifyLocale(inotify.GetTarget())
+	name := template.LocaleName(locale, fmt.Sprintf("notify.group.%v.live_end.tmpl", inotify.Site()))
+	summary, err := template.LoadAndExec(name,
+		map[string]interface{}{
+			"name":       s.Name,
+			"title":      s.Title,
+			"start_time": s.StartTime,
+			"end_time":   s.EndTime,
+			"duration":   i18n.FormatDuration(locale, s.Duration()),
+			"titles":     titles,
+			"popularity": s.Popularity,
+			"locale":     locale,
+		})
+	if err != nil {
+		inotify.Logger().Errorf("live end template error %v", err)
+		return m
+	}
+	result := mmsg.NewMSG()
+	result.Append(&message.ReplyElement{ReplySeq: s.MsgId, Id: strconv.Itoa(int(s.MsgId))})
+	return result.Append(summary.Elements()...)
+}
+
+// recordLiveStartMessage 记录开播推送的消息id，下播时回复这条消息
+func recordLiveStartMessage(inotify concern.Notify, msgs []*message.GroupMessage) {
+	ext, ok := inotify.(concern.NotifyLiveSummaryExt)
+	if !ok || !ext.IsLive() || !ext.Living() || !ext.LiveStatusChanged() ||
+		!inotify.GetTarget().TargetType().IsGroup() {
+		return
+	}
+	for _, msg := range msgs {
+		if msg.Id == -1 || msg.Id == 0 {
+			continue
+		}
+		if err := concern.SetLiveSessionMsg(inotify, msg.Id); err != nil {
+			inotify.Logger().Errorf("SetLiveSessionMsg error %v", err)
+		}
+		return
+	}
+}
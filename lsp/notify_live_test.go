@@ -0,0 +1,64 @@
+package lsp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	tc "github.com/cnxysoft/DDBOT-WSa/internal/test_concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
+	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
+	"github.com/stretchr/testify/assert"
+)
+
+type testLiveSummaryNotify struct {
+	*testLiveNotify
+}
+
+func (n *testLiveSummaryNotify) AnchorName() string {
+	return test.NAME1
+}
+
+func (n *testLiveSummaryNotify) RoomTitle() string {
+	return "title"
+}
+
+func (n *testLiveSummaryNotify) Popularity() int64 {
+	return 0
+}
+
+func TestLsp_LiveEndMessage(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	tc1 := tc.NewTestConcern(nil, test.Site1, []concern_type.Type{test.T1})
+	offline := &testLiveSummaryNotify{
+		&testLiveNotify{tc1.NewTestEvent(test.T1, mmsg.NewGroupTarget(test.G1), test.NAME1), false, true},
+	}
+
+	// 没有记录到开播推送时使用原本的下播推送
+	m := Instance.liveEndMessage(offline, offline.ToMessage())
+	assert.Equal(t, msgstringer.MsgToString(offline.ToMessage().Elements()), msgstringer.MsgToString(m.Elements()))
+}
+
+func TestLiveEndTemplate(t *testing.T) {
+	m, err := template.LoadAndExec("notify.group.bilibili.live_end.tmpl", map[string]interface{}{
+		"name":       test.NAME1,
+		"title":      "title2",
+		"duration":   i18n.FormatDuration(i18n.Chinese, time.Hour+time.Minute*2+time.Second*3),
+		"popularity": int64(0),
+		"titles": []map[string]interface{}{
+			{"time": time.Now().Unix(), "title": "title2"},
+		},
+	})
+	assert.Nil(t, err)
+	s := msgstringer.MsgToString(m.Elements())
+	assert.Contains(t, s, test.NAME1+"直播结束了")
+	assert.Contains(t, s, "直播时长：1小时2分3秒")
+	assert.Contains(t, s, "最后标题：title2")
+	assert.NotContains(t, s, "最高人气")
+	assert.Contains(t, s, "直播中修改了标题：")
+}
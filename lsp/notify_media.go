@@ -0,0 +1,95 @@
+package lsp
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Mrs4s/MiraiGo/message"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/requests"
+	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// archiveNotifyMedia 根据订阅的存档配置，把推送附带的媒体上传到群文件夹
+func (l *Lsp) archiveNotifyMedia(cfg concern.IConfig, inotify concern.Notify, log *logrus.Entry) {
+	mediaConfig := cfg.GetGroupConcernMedia()
+	if !mediaConfig.CheckArchive(inotify.Type()) {
+		return
+	}
+	ext, ok := inotify.(concern.NotifyMediaExt)
+	if !ok {
+		return
+	}
+	var medias []*concern.NotifyMedia
+	for _, media := range ext.NotifyMedias() {
+		if media != nil && (media.Url != "" || media.Loader != nil) && mediaConfig.CheckMedia(media.Kind) {
+			medias = append(medias, media)
+		}
+	}
+	if len(medias) == 0 {
+		return
+	}
+	if !inotify.GetTarget().TargetType().IsGroup() {
+		// 群文件只能上传到群
+		return
+	}
+	log = log.WithField("Folder", mediaConfig.GetFolder())
+	groupCode := inotify.GetTarget().TargetCode()
+	folderId, err := localutils.GetBot().EnsureGroupFolder(groupCode, mediaConfig.GetFolder())
+	if err != nil {
+		log.Errorf("EnsureGroupFolder error %v", err)
+		return
+	}
+	for _, media := range medias {
+		if err = uploadNotifyMedia(groupCode, folderId, media); err != nil {
+			log.WithField("Name", media.Name).Errorf("archive notify media failed %v", err)
+		} else {
+			log.WithField("Name", media.Name).Debug("archive notify media")
+		}
+	}
+}
+
+func uploadNotifyMedia(groupCode int64, folderId string, media *concern.NotifyMedia) error {
+	if media.Url == "" && media.Loader != nil {
+		url, err := media.Loader()
+		if err != nil {
+			return err
+		}
+		media.Url = url
+	}
+	if media.Kind == concern.MediaVideo || media.Kind == concern.MediaAudio {
+		// 视频和音频不经过BOT，让协议端直接下载后上传
+		file, err := localutils.GetBot().DownloadFile(media.Url, "", media.Name, media.Headers...)
+		if err != nil {
+			return err
+		}
+		_, err = localutils.GetBot().UploadGroupFile(groupCode, file, media.Name, folderId)
+		return err
+	}
+	var f *mmsg.FileElement
+	if media.Kind == concern.MediaLink {
+		title := strings.TrimSuffix(media.Name, path.Ext(media.Name))
+		f = mmsg.NewFile("", []byte(fmt.Sprintf("%v\n%v\n", title, media.Url)))
+	} else {
+		f = mmsg.NewFileByUrl(media.Url, requests.ProxyOption(media.Proxy))
+	}
+	e, ok := f.Name(media.Name).PackToElement(mmsg.NewGroupTarget(groupCode)).(*message.FileElement)
+	if !ok || e == nil {
+		return errors.New("下载媒体失败")
+	}
+	b64, ok := e.File.(string)
+	if !ok || !strings.HasPrefix(b64, "base64://") {
+		return errors.New("下载媒体失败")
+	}
+	// 先让协议端保存到本地，再上传到群文件
+	file, err := localutils.GetBot().DownloadFile("", strings.TrimPrefix(b64, "base64://"), e.Name)
+	if err != nil {
+		return err
+	}
+	_, err = localutils.GetBot().UploadGroupFile(groupCode, file, e.Name, folderId)
+	return err
+}
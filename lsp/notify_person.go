@@ -0,0 +1,67 @@
+package lsp
+
+import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/cfg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/person"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
+)
+
+// personLiveMessage 合并同一个人物在多个平台的开播推送
+// 时间窗口内这个人物已经推送过开播时返回false，否则会在推送前加上所有正在直播的平台
+func (l *Lsp) personLiveMessage(inotify concern.Notify, m *mmsg.MSG) (*mmsg.MSG, bool) {
+	liveExt, ok := inotify.(concern.NotifyLiveExt)
+	if !ok || !liveExt.IsLive() || !liveExt.Living() || !liveExt.LiveStatusChanged() {
+		return m, true
+	}
+	window := cfg.GetPersonLiveWindow()
+	if window <= 0 || !inotify.GetTarget().TargetType().IsGroup() {
+		return m, true
+	}
+	p, err := person.Find(inotify.GetTarget().TargetCode(), inotify.Site(), inotify.GetUid())
+	if err != nil {
+		if err != person.ErrPersonNotFound {
+			inotify.Logger().Errorf("person.Find error %v", err)
+		}
+		return m, true
+	}
+	log := inotify.Logger().WithField("person", p.Alias)
+	if !person.CheckAndSetNotified(p.GroupCode, p.Alias, window) {
+		log.Info("人物已在其他平台推送过开播，跳过本次推送")
+		return nil, false
+	}
+	var lives []map[string]interface{}
+	for _, member := range p.LivingMembers() {
+		lives = append(lives, map[string]interface{}{
+			"site": member.Site,
+			"id":   member.Id,
+			"name": personMemberName(member),
+		})
+	}
+	header, err := template.ExecNotify(template.NotifyLocale(inotify.GetTarget()), "notify.group.person.live.tmpl", map[string]interface{}{
+		"alias": p.Alias,
+		"lives": lives,
+	})
+	if err != nil {
+		log.Errorf("person live template error %v", err)
+		return m, true
+	}
+	return header.Append(m.Elements()...), true
+}
+
+func personMemberName(member *person.Member) string {
+	c, err := concern.GetConcernBySite(member.Site)
+	if err != nil {
+		return member.Id
+	}
+	id, err := c.ParseId(member.Id)
+	if err != nil {
+		return member.Id
+	}
+	info, err := c.Get(id)
+	if err != nil || info == nil || len(info.GetName()) == 0 {
+		return member.Id
+	}
+	return info.GetName()
+}
@@ -0,0 +1,79 @@
+package lsp
+
+import (
+	"testing"
+
+	"github.com/Sora233/MiraiGo-Template/config"
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	tc "github.com/cnxysoft/DDBOT-WSa/internal/test_concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/person"
+	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
+	"github.com/stretchr/testify/assert"
+)
+
+type testLiveNotify struct {
+	*tc.TestEvent
+	living        bool
+	statusChanged bool
+}
+
+func (n *testLiveNotify) IsLive() bool {
+	return true
+}
+
+func (n *testLiveNotify) Living() bool {
+	return n.living
+}
+
+func (n *testLiveNotify) TitleChanged() bool {
+	return false
+}
+
+func (n *testLiveNotify) LiveStatusChanged() bool {
+	return n.statusChanged
+}
+
+func TestLsp_PersonLiveMessage(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	target := mmsg.NewGroupTarget(test.G1)
+	tc1 := tc.NewTestConcern(nil, test.Site1, []concern_type.Type{test.T1})
+	tc2 := tc.NewTestConcern(nil, test.Site2, []concern_type.Type{test.T2})
+	live1 := &testLiveNotify{tc1.NewTestEvent(test.T1, mmsg.NewGroupTarget(test.G1), test.NAME1), true, true}
+	live2 := &testLiveNotify{tc2.NewTestEvent(test.T2, mmsg.NewGroupTarget(test.G1), test.NAME2), true, true}
+
+	// 不属于人物的推送不受影响
+	m, ok := Instance.personLiveMessage(live1, live1.ToMessage())
+	assert.True(t, ok)
+	assert.Equal(t, msgstringer.MsgToString(live1.ToMessage().Elements()), msgstringer.MsgToString(m.Elements()))
+
+	assert.Nil(t, person.Link(test.G1, "alice", test.Site1, test.NAME1))
+	assert.Nil(t, person.Link(test.G1, "alice", test.Site2, test.NAME2))
+	assert.Nil(t, person.SetLiving(test.G1, test.Site1, test.NAME1, true))
+	assert.Nil(t, person.SetLiving(test.G1, test.Site2, test.NAME2, true))
+
+	m, ok = Instance.personLiveMessage(live1, live1.ToMessage())
+	assert.True(t, ok)
+	s := msgstringer.MsgToString(m.ToCombineMessage(target).Elements)
+	assert.Contains(t, s, "alice正在2个平台同时直播")
+	assert.Contains(t, s, test.Site2+"-"+test.NAME2)
+	assert.Contains(t, s, msgstringer.MsgToString(live1.ToMessage().Elements()))
+
+	// 时间窗口内其他平台的开播被合并
+	_, ok = Instance.personLiveMessage(live2, live2.ToMessage())
+	assert.False(t, ok)
+
+	// 下播推送不受影响
+	offline := &testLiveNotify{tc2.NewTestEvent(test.T2, mmsg.NewGroupTarget(test.G1), test.NAME2), false, true}
+	_, ok = Instance.personLiveMessage(offline, offline.ToMessage())
+	assert.True(t, ok)
+
+	// 关闭合并
+	config.GlobalConfig.Set("person.liveWindow", "0s")
+	defer config.GlobalConfig.Set("person.liveWindow", nil)
+	_, ok = Instance.personLiveMessage(live2, live2.ToMessage())
+	assert.True(t, ok)
+}
@@ -3,10 +3,12 @@ package lsp
 import (
 	"github.com/Mrs4s/MiraiGo/message"
 	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	tc "github.com/cnxysoft/DDBOT-WSa/internal/test_concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/permission"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/scheduler"
 	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -40,15 +42,15 @@ func TestLsp_ConcernNotify(t *testing.T) {
 	concern.RegisterConcern(tc1)
 	defer tc1.Stop()
 
-	IWatch(ctx, test.G1, test.NAME1, test.Site1, test.T1, false)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G1), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	IWatch(ctx, test.G2, test.NAME1, test.Site1, test.T1, false)
+	IWatch(ctx, mmsg.NewGroupTarget(test.G2), test.NAME1, test.Site1, test.T1, false)
 	result = <-msgChan
 	assert.Contains(t, msgstringer.MsgToString(result.ToCombineMessage(target).Elements), success)
 
-	testEventChan <- tc1.NewTestEvent(test.T1, 0, test.NAME1)
+	testEventChan <- tc1.NewTestEvent(test.T1, nil, test.NAME1)
 
 	go Instance.ConcernNotify()
 	Instance.notifyWg.Wait()
@@ -74,3 +76,23 @@ func TestNewAtIdsMsg(t *testing.T) {
 	assert.NotNil(t, msg)
 	assert.Len(t, msg.Elements(), 2)
 }
+
+func TestNotifyResults(t *testing.T) {
+	msgs := notifyResults([]interface{}{
+		&message.GroupMessage{Id: 1},
+		&message.PrivateMessage{Id: -1, Elements: []message.IMessageElement{message.NewText("test")}},
+	})
+	assert.Len(t, msgs, 2)
+	assert.EqualValues(t, 1, msgs[0].Id)
+	assert.EqualValues(t, -1, msgs[1].Id)
+	assert.Len(t, msgs[1].Elements, 1)
+}
+
+func TestNotifyPriority(t *testing.T) {
+	tc1 := tc.NewTestConcern(nil, test.Site1, []concern_type.Type{test.T1})
+	event := tc1.NewTestEvent(test.T1, mmsg.NewGroupTarget(test.G1), test.NAME1)
+	assert.Equal(t, scheduler.PriorityNormal, notifyPriority(event))
+	assert.Equal(t, scheduler.PriorityHigh, notifyPriority(&testLiveNotify{event, true, true}))
+	assert.Equal(t, scheduler.PriorityLow, notifyPriority(&testLiveNotify{event, true, false}))
+	assert.Equal(t, scheduler.PriorityNormal, notifyPriority(&testLiveNotify{event, false, true}))
+}
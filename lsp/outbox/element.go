@@ -0,0 +1,84 @@
+package outbox
+
+import (
+	"encoding/json"
+
+	"github.com/Mrs4s/MiraiGo/message"
+)
+
+// Element 可以持久化的消息元素，格式与发送给协议端的OneBot消息段相同
+type Element struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// encodeElements 序列化消息元素，不支持持久化的元素会被跳过
+func encodeElements(elems []message.IMessageElement) []*Element {
+	var result []*Element
+	for _, e := range elems {
+		var t string
+		switch v := e.(type) {
+		case *message.TextElement:
+			t = "text"
+		case *message.ImageElement:
+			t = "image"
+		case *message.AtElement:
+			t = "at"
+		case *message.VideoElement:
+			t = "video"
+		case *message.RecordElement:
+			t = "record"
+		case *message.FileElement:
+			t = "file"
+		case *message.ReplyElement:
+			t = "reply"
+			// 引用的消息内容不需要保存
+			reply := *v
+			reply.Elements = nil
+			e = &reply
+		default:
+			logger.Debugf("outbox: skip unsupported element %T", e)
+			continue
+		}
+		data, err := json.Marshal(e)
+		if err != nil {
+			logger.Errorf("outbox: marshal element %T error %v", e, err)
+			continue
+		}
+		result = append(result, &Element{Type: t, Data: data})
+	}
+	return result
+}
+
+// decodeElements 反序列化消息元素，无法识别的元素会被跳过
+func decodeElements(elems []*Element) []message.IMessageElement {
+	var result []message.IMessageElement
+	for _, e := range elems {
+		var v message.IMessageElement
+		switch e.Type {
+		case "text":
+			v = new(message.TextElement)
+		case "image":
+			v = new(message.ImageElement)
+		case "at":
+			v = new(message.AtElement)
+		case "video":
+			v = new(message.VideoElement)
+		case "record":
+			v = new(message.RecordElement)
+		case "file":
+			v = new(message.FileElement)
+		case "reply":
+			v = new(message.ReplyElement)
+		default:
+			logger.Errorf("outbox: unknown element type %v", e.Type)
+			continue
+		}
+		if err := json.Unmarshal(e.Data, v); err != nil {
+			logger.Errorf("outbox: unmarshal element %v error %v", e.Type, err)
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
@@ -0,0 +1,5 @@
+package outbox
+
+import "github.com/Sora233/MiraiGo-Template/utils"
+
+var logger = utils.GetModuleLogger("Outbox")
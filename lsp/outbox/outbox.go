@@ -0,0 +1,308 @@
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Mrs4s/MiraiGo/message"
+	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/cfg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
+	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
+	"github.com/tidwall/buntdb"
+)
+
+// deadLetterExpire 死信的保留时间
+const deadLetterExpire = time.Hour * 24 * 7
+
+// checkInterval 检查到期重试的间隔
+const checkInterval = time.Second * 10
+
+var ErrNotStarted = errors.New("发件箱没有启动")
+
+// Entry 发件箱中一条发送失败的消息
+type Entry struct {
+	Id     int64  `json:"id"`
+	Target string `json:"target"`
+	// Source 消息的来源，例如推送的网站和订阅id
+	Source string `json:"source"`
+	// Messages 没有发送成功的消息，每一项是一条单独发送的消息
+	Messages  [][]*Element `json:"messages"`
+	Content   string       `json:"content"`
+	Reason    Reason       `json:"reason"`
+	Error     string       `json:"error"`
+	Retry     int          `json:"retry"`
+	CreatedAt int64        `json:"created_at"`
+	NextRetry int64        `json:"next_retry"`
+	Dead      bool         `json:"dead"`
+}
+
+func (e *Entry) key() string {
+	if e.Dead {
+		return localdb.OutboxDeadLetterKey(e.Id)
+	}
+	return localdb.OutboxKey(e.Id)
+}
+
+func (e *Entry) setMessages(msgs []*message.SendingMessage) {
+	e.Messages = nil
+	var elems []message.IMessageElement
+	for _, msg := range msgs {
+		if msg == nil {
+			continue
+		}
+		if encoded := encodeElements(msg.Elements); len(encoded) > 0 {
+			e.Messages = append(e.Messages, encoded)
+			elems = append(elems, msg.Elements...)
+		}
+	}
+	e.Content = msgstringer.MsgToString(elems)
+}
+
+func (e *Entry) sendingMessages() []*message.SendingMessage {
+	var result []*message.SendingMessage
+	for _, elems := range e.Messages {
+		result = append(result, &message.SendingMessage{Elements: decodeElements(elems)})
+	}
+	return result
+}
+
+// fail 记录一次发送失败，超过重试次数时转为死信
+func (e *Entry) fail(err error, now time.Time) {
+	e.Reason = Classify(err)
+	e.Error = err.Error()
+	policy := PolicyOf(e.Reason)
+	if e.Retry >= policy.MaxRetry {
+		e.Dead = true
+		e.NextRetry = 0
+		return
+	}
+	e.NextRetry = now.Add(backoff(policy, e.Retry+1)).Unix()
+}
+
+// SendFunc 依次发送msgs，返回没有发送成功的消息以及失败的原因，全部发送成功时err为nil
+type SendFunc func(target mmsg.Target, msgs []*message.SendingMessage) (remain []*message.SendingMessage, err error)
+
+var (
+	// lock 保证同一条消息不会被同时发送
+	lock   sync.Mutex
+	sender SendFunc
+	stop   chan interface{}
+	wg     sync.WaitGroup
+)
+
+// Push 把发送失败的消息保存到发件箱，没有开启发件箱或者没有需要保存的消息时返回nil
+func Push(target mmsg.Target, source string, msgs []*message.SendingMessage, err error) (*Entry, error) {
+	if err == nil || !cfg.GetOutboxEnable() {
+		return nil, nil
+	}
+	now := time.Now()
+	e := &Entry{
+		Target:    target.String(),
+		Source:    source,
+		CreatedAt: now.Unix(),
+	}
+	e.setMessages(msgs)
+	if len(e.Messages) == 0 {
+		return nil, nil
+	}
+	id, dbErr := localdb.SeqNext(localdb.OutboxSeqKey())
+	if dbErr != nil {
+		return nil, dbErr
+	}
+	e.Id = id
+	e.fail(err, now)
+	return e, save(e)
+}
+
+func save(e *Entry) error {
+	return localdb.RWCover(func() error {
+		if e.Dead {
+			if _, err := localdb.Delete(localdb.OutboxKey(e.Id), localdb.IgnoreNotFoundOpt()); err != nil {
+				return err
+			}
+			return localdb.SetJson(e.key(), e, localdb.SetExpireOpt(deadLetterExpire))
+		}
+		if _, err := localdb.Delete(localdb.OutboxDeadLetterKey(e.Id), localdb.IgnoreNotFoundOpt()); err != nil {
+			return err
+		}
+		return localdb.SetJson(e.key(), e)
+	})
+}
+
+func remove(e *Entry) error {
+	_, err := localdb.Delete(e.key(), localdb.IgnoreNotFoundOpt())
+	return err
+}
+
+// List 返回等待重试的消息，dead为true时返回死信，结果按id排序
+func List(dead bool) ([]*Entry, error) {
+	var pattern = localdb.OutboxKey("*")
+	if dead {
+		pattern = localdb.OutboxDeadLetterKey("*")
+	}
+	var result []*Entry
+	err := localdb.RCoverTx(func(tx *buntdb.Tx) error {
+		return tx.AscendKeys(pattern, func(key, value string) bool {
+			var e = new(Entry)
+			if err := json.Unmarshal([]byte(value), e); err != nil {
+				logger.WithField("key", key).Errorf("unmarshal outbox entry error %v", err)
+				return true
+			}
+			result = append(result, e)
+			return true
+		})
+	})
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Id < result[j].Id
+	})
+	return result, err
+}
+
+// deliver 发送一条消息，成功后从发件箱中删除，失败时更新重试信息
+func deliver(send SendFunc, e *Entry, now time.Time) error {
+	var sendErr error
+	target, err := mmsg.ParseTarget(e.Target)
+	if err != nil {
+		sendErr = err
+	} else {
+		var remain []*message.SendingMessage
+		remain, sendErr = send(target, e.sendingMessages())
+		if sendErr == nil || len(remain) == 0 {
+			return remove(e)
+		}
+		e.setMessages(remain)
+	}
+	e.Retry++
+	e.fail(sendErr, now)
+	if err := save(e); err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// retryDue 重试所有到期的消息
+func retryDue(send SendFunc, now time.Time) {
+	lock.Lock()
+	defer lock.Unlock()
+	entries, err := List(false)
+	if err != nil {
+		logger.Errorf("list outbox error %v", err)
+		return
+	}
+	for _, e := range entries {
+		if e.NextRetry > now.Unix() {
+			continue
+		}
+		log := logger.WithField("Id", e.Id).WithField("Target", e.Target).WithField("Retry", e.Retry+1)
+		if err := deliver(send, e, now); err != nil {
+			log.WithField("Dead", e.Dead).Errorf("outbox retry failed %v", err)
+		} else {
+			log.Info("outbox retry success")
+		}
+	}
+}
+
+// Resend 立即重新发送一条死信，发送失败时仍然保留在死信中
+func Resend(id int64) error {
+	lock.Lock()
+	defer lock.Unlock()
+	if sender == nil {
+		return ErrNotStarted
+	}
+	var e = new(Entry)
+	if err := localdb.GetJson(localdb.OutboxDeadLetterKey(id), e); err != nil {
+		return err
+	}
+	target, err := mmsg.ParseTarget(e.Target)
+	if err != nil {
+		return err
+	}
+	remain, sendErr := sender(target, e.sendingMessages())
+	if sendErr == nil || len(remain) == 0 {
+		return remove(e)
+	}
+	e.setMessages(remain)
+	e.Reason = Classify(sendErr)
+	e.Error = sendErr.Error()
+	if err := save(e); err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// Purge 删除指定的死信，没有指定时删除全部死信，返回删除的数量
+func Purge(ids ...int64) (int, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	var count int
+	err := localdb.RWCoverTx(func(tx *buntdb.Tx) error {
+		var keys []string
+		if len(ids) == 0 {
+			if err := tx.AscendKeys(localdb.OutboxDeadLetterKey("*"), func(key, value string) bool {
+				keys = append(keys, key)
+				return true
+			}); err != nil {
+				return err
+			}
+		} else {
+			for _, id := range ids {
+				keys = append(keys, localdb.OutboxDeadLetterKey(id))
+			}
+		}
+		for _, key := range keys {
+			_, err := tx.Delete(key)
+			if localdb.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Start 开始定期重试发件箱中的消息
+func Start(send SendFunc) {
+	lock.Lock()
+	defer lock.Unlock()
+	if stop != nil {
+		return
+	}
+	sender = send
+	stop = make(chan interface{})
+	wg.Add(1)
+	go func(stop chan interface{}) {
+		defer wg.Done()
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				if !localutils.GetBot().IsOnline() {
+					continue
+				}
+				retryDue(send, now)
+			}
+		}
+	}(stop)
+}
+
+func Stop() {
+	lock.Lock()
+	if stop != nil {
+		close(stop)
+		stop = nil
+	}
+	lock.Unlock()
+	wg.Wait()
+}
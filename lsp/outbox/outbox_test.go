@@ -0,0 +1,171 @@
+package outbox
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Mrs4s/MiraiGo/client"
+	"github.com/Mrs4s/MiraiGo/message"
+	"github.com/Sora233/MiraiGo-Template/config"
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/cfg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSendingMessage(text string) *message.SendingMessage {
+	return &message.SendingMessage{Elements: []message.IMessageElement{message.NewText(text)}}
+}
+
+func TestClassify(t *testing.T) {
+	assert.Equal(t, ReasonMuted, Classify(NewSendError(ReasonMuted, nil)))
+	assert.Equal(t, ReasonTooLong, Classify(client.ErrMessageTooLong))
+	assert.Equal(t, ReasonNetwork, Classify(client.ErrNotConnected))
+	assert.Equal(t, ReasonRiskControl, Classify(&client.ActionError{Action: "send_group_msg", Message: "消息发送过于频繁"}))
+	assert.Equal(t, ReasonUnknown, Classify(&client.ActionError{Action: "send_group_msg", Message: "unknown"}))
+	assert.Equal(t, ReasonUnknown, Classify(errors.New("unknown")))
+}
+
+func TestBackoff(t *testing.T) {
+	policy := cfg.OutboxPolicy{MaxRetry: 10, Backoff: time.Minute, MaxBackoff: time.Minute * 5}
+	assert.Equal(t, time.Minute, backoff(policy, 1))
+	assert.Equal(t, time.Minute*2, backoff(policy, 2))
+	assert.Equal(t, time.Minute*4, backoff(policy, 3))
+	assert.Equal(t, time.Minute*5, backoff(policy, 4))
+	assert.Equal(t, time.Minute*5, backoff(policy, 10))
+
+	config.GlobalConfig.Set("outbox.policy.network.maxRetry", 1)
+	defer config.GlobalConfig.Set("outbox.policy", nil)
+	assert.Equal(t, 1, PolicyOf(ReasonNetwork).MaxRetry)
+	assert.Equal(t, defaultPolicy[ReasonNetwork].Backoff, PolicyOf(ReasonNetwork).Backoff)
+}
+
+func TestElements(t *testing.T) {
+	var elems = []message.IMessageElement{
+		message.NewText("text"),
+		message.NewAt(test.UID1),
+		&message.ImageElement{File: "https://example.com/a.jpg"},
+		&message.ReplyElement{Id: "1", Elements: []message.IMessageElement{message.NewText("reply")}},
+		&message.FaceElement{Index: 1},
+	}
+	encoded := encodeElements(elems)
+	assert.Len(t, encoded, 4)
+	decoded := decodeElements(encoded)
+	assert.Len(t, decoded, 4)
+	assert.EqualValues(t, "text", decoded[0].(*message.TextElement).Content)
+	assert.EqualValues(t, test.UID1, decoded[1].(*message.AtElement).Target)
+	assert.EqualValues(t, "https://example.com/a.jpg", decoded[2].(*message.ImageElement).File)
+	assert.EqualValues(t, "1", decoded[3].(*message.ReplyElement).Id)
+	assert.Nil(t, decoded[3].(*message.ReplyElement).Elements)
+}
+
+func TestOutbox(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	var target = mmsg.NewGroupTarget(test.G1)
+	var now = time.Now()
+
+	// 没有失败不保存
+	e, err := Push(target, "test", []*message.SendingMessage{newSendingMessage("a")}, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, e)
+
+	// 消息过长直接进入死信
+	e, err = Push(target, "test", []*message.SendingMessage{newSendingMessage("long")}, client.ErrMessageTooLong)
+	assert.Nil(t, err)
+	assert.NotNil(t, e)
+	assert.True(t, e.Dead)
+	assert.Equal(t, ReasonTooLong, e.Reason)
+
+	e, err = Push(target, "test", []*message.SendingMessage{newSendingMessage("a"), newSendingMessage("b")},
+		NewSendError(ReasonNetwork, nil))
+	assert.Nil(t, err)
+	assert.NotNil(t, e)
+	assert.False(t, e.Dead)
+	assert.Len(t, e.Messages, 2)
+	assert.True(t, e.NextRetry > now.Unix())
+
+	pending, err := List(false)
+	assert.Nil(t, err)
+	assert.Len(t, pending, 1)
+	dead, err := List(true)
+	assert.Nil(t, err)
+	assert.Len(t, dead, 1)
+
+	var sent []string
+	var fail = true
+	send := func(target mmsg.Target, msgs []*message.SendingMessage) ([]*message.SendingMessage, error) {
+		assert.Equal(t, "g123456", target.String())
+		for idx, msg := range msgs {
+			if fail && idx == 1 {
+				return msgs[idx:], &client.ActionError{Message: "风控"}
+			}
+			sent = append(sent, msg.Elements[0].(*message.TextElement).Content)
+		}
+		return nil, nil
+	}
+
+	// 还没到重试时间
+	retryDue(send, now)
+	assert.Empty(t, sent)
+
+	// 第一条发送成功，第二条失败
+	retryDue(send, now.Add(time.Hour))
+	assert.Equal(t, []string{"a"}, sent)
+	pending, err = List(false)
+	assert.Nil(t, err)
+	assert.Len(t, pending, 1)
+	assert.Len(t, pending[0].Messages, 1)
+	assert.Equal(t, 1, pending[0].Retry)
+	assert.Equal(t, ReasonRiskControl, pending[0].Reason)
+
+	// 超过重试次数进入死信
+	config.GlobalConfig.Set("outbox.policy.risk_control.maxRetry", 2)
+	defer config.GlobalConfig.Set("outbox.policy", nil)
+	sent = nil
+	fail = false
+	send2 := func(target mmsg.Target, msgs []*message.SendingMessage) ([]*message.SendingMessage, error) {
+		return msgs, &client.ActionError{Message: "风控"}
+	}
+	retryDue(send2, now.Add(time.Hour*2))
+	pending, err = List(false)
+	assert.Nil(t, err)
+	assert.Empty(t, pending)
+	dead, err = List(true)
+	assert.Nil(t, err)
+	assert.Len(t, dead, 2)
+	assert.True(t, dead[1].Dead)
+	assert.Equal(t, 2, dead[1].Retry)
+
+	// 死信重新发送
+	assert.Equal(t, ErrNotStarted, Resend(dead[1].Id))
+	sender = send
+	defer func() { sender = nil }()
+	assert.Nil(t, Resend(dead[1].Id))
+	assert.Equal(t, []string{"b"}, sent)
+	assert.NotNil(t, Resend(dead[1].Id))
+
+	// 重新发送失败时保留在死信中
+	sender = send2
+	assert.NotNil(t, Resend(dead[0].Id))
+	dead, err = List(true)
+	assert.Nil(t, err)
+	assert.Len(t, dead, 1)
+	assert.Equal(t, ReasonRiskControl, dead[0].Reason)
+
+	count, err := Purge(dead[0].Id, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	count, err = Purge()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	// 关闭发件箱
+	config.GlobalConfig.Set("outbox.enable", false)
+	defer config.GlobalConfig.Set("outbox.enable", nil)
+	e, err = Push(target, "test", []*message.SendingMessage{newSendingMessage("a")}, client.ErrNotConnected)
+	assert.Nil(t, err)
+	assert.Nil(t, e)
+}
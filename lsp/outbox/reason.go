@@ -0,0 +1,120 @@
+package outbox
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/Mrs4s/MiraiGo/client"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/cfg"
+	"github.com/pkg/errors"
+)
+
+// Reason 消息发送失败的原因
+type Reason string
+
+const (
+	ReasonMuted       Reason = "muted"
+	ReasonRiskControl Reason = "risk_control"
+	ReasonTooLong     Reason = "too_long"
+	ReasonNetwork     Reason = "network"
+	ReasonUnknown     Reason = "unknown"
+)
+
+func (r Reason) Description() string {
+	switch r {
+	case ReasonMuted:
+		return "BOT被禁言"
+	case ReasonRiskControl:
+		return "风控"
+	case ReasonTooLong:
+		return "消息过长"
+	case ReasonNetwork:
+		return "网络错误"
+	default:
+		return "未知错误"
+	}
+}
+
+// defaultPolicy 各个失败原因默认的重试策略，消息过长时重试也不会成功，直接进入死信
+var defaultPolicy = map[Reason]cfg.OutboxPolicy{
+	ReasonMuted:       {MaxRetry: 3, Backoff: time.Minute * 10, MaxBackoff: time.Hour},
+	ReasonRiskControl: {MaxRetry: 5, Backoff: time.Minute * 5, MaxBackoff: time.Hour},
+	ReasonTooLong:     {MaxRetry: 0},
+	ReasonNetwork:     {MaxRetry: 10, Backoff: time.Second * 30, MaxBackoff: time.Minute * 30},
+	ReasonUnknown:     {MaxRetry: 3, Backoff: time.Minute, MaxBackoff: time.Minute * 30},
+}
+
+// PolicyOf 返回失败原因对应的重试策略
+func PolicyOf(reason Reason) cfg.OutboxPolicy {
+	def, found := defaultPolicy[reason]
+	if !found {
+		def = defaultPolicy[ReasonUnknown]
+	}
+	return cfg.GetOutboxPolicy(string(reason), def)
+}
+
+// backoff 返回第retry次失败后到下次重试的间隔
+func backoff(policy cfg.OutboxPolicy, retry int) time.Duration {
+	d := policy.Backoff
+	for i := 1; i < retry && d < policy.MaxBackoff; i++ {
+		d *= 2
+	}
+	if policy.MaxBackoff > 0 && d > policy.MaxBackoff {
+		d = policy.MaxBackoff
+	}
+	return d
+}
+
+// SendError 记录了失败原因的发送错误
+type SendError struct {
+	Reason Reason
+	Err    error
+}
+
+func (e *SendError) Error() string {
+	if e.Err == nil {
+		return e.Reason.Description()
+	}
+	return fmt.Sprintf("%v: %v", e.Reason.Description(), e.Err)
+}
+
+func (e *SendError) Unwrap() error {
+	return e.Err
+}
+
+func NewSendError(reason Reason, err error) *SendError {
+	return &SendError{Reason: reason, Err: err}
+}
+
+// riskControlKeywords 协议端返回的错误信息中出现这些关键字时视为风控
+var riskControlKeywords = []string{"风控", "risk", "频繁", "frequent"}
+
+// Classify 返回err对应的失败原因
+func Classify(err error) Reason {
+	var sendErr *SendError
+	if errors.As(err, &sendErr) {
+		return sendErr.Reason
+	}
+	if errors.Is(err, client.ErrMessageTooLong) {
+		return ReasonTooLong
+	}
+	if errors.Is(err, client.ErrNotConnected) || errors.Is(err, client.ErrActionTimeout) {
+		return ReasonNetwork
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ReasonNetwork
+	}
+	var actionErr *client.ActionError
+	if errors.As(err, &actionErr) {
+		msg := strings.ToLower(actionErr.Message + actionErr.Wording)
+		for _, keyword := range riskControlKeywords {
+			if strings.Contains(msg, keyword) {
+				return ReasonRiskControl
+			}
+		}
+	}
+	return ReasonUnknown
+}
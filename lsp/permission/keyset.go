@@ -28,6 +28,10 @@ func (k *KeySet) GlobalSilenceKey(keys ...interface{}) string {
 	return localdb.GlobalSilenceKey(keys...)
 }
 
+func (k *KeySet) GroupLocaleKey(keys ...interface{}) string {
+	return localdb.GroupLocaleKey(keys...)
+}
+
 func (k *KeySet) BlockListKey(keys ...interface{}) string {
 	return localdb.BlockListKey(keys...)
 }
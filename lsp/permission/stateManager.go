@@ -9,6 +9,7 @@ import (
 	"github.com/Mrs4s/MiraiGo/client"
 	"github.com/Sora233/MiraiGo-Template/utils"
 	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
 	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/sirupsen/logrus"
 	"github.com/tidwall/buntdb"
@@ -197,6 +198,24 @@ func (c *StateManager) UndoGroupSilence(groupCode int64) error {
 	return err
 }
 
+// GetGroupLocale 返回群设置的语言，没有设置时返回默认语言
+func (c *StateManager) GetGroupLocale(groupCode int64) string {
+	locale, err := c.Get(c.GroupLocaleKey(groupCode), localdb.IgnoreNotFoundOpt())
+	if err != nil || locale == "" {
+		return i18n.DefaultLocale
+	}
+	return locale
+}
+
+// SetGroupLocale 设置群使用的语言，设置为默认语言时删除设置
+func (c *StateManager) SetGroupLocale(groupCode int64, locale string) error {
+	if i18n.IsDefault(locale) {
+		_, err := c.Delete(c.GroupLocaleKey(groupCode), localdb.IgnoreNotFoundOpt())
+		return err
+	}
+	return c.Set(c.GroupLocaleKey(groupCode), locale)
+}
+
 func (c *StateManager) CheckGroupAdministrator(groupCode int64, caller int64) bool {
 	log := logger.WithFields(logrus.Fields{
 		"GroupCode": groupCode,
@@ -392,7 +411,17 @@ func (c *StateManager) RemoveAllByGroupCode(groupCode int64) ([]string, error) {
 		c.PermissionKey(groupCode),
 		c.GroupEnabledKey(groupCode),
 	}
-	return localdb.RemoveByPrefixAndIndex(prefixKey, indexKey)
+	deletedKey, err := localdb.RemoveByPrefixAndIndex(prefixKey, indexKey)
+	if err != nil {
+		return deletedKey, err
+	}
+	// 语言设置的key以群号结尾，使用前缀删除会误删其他群的设置
+	if c.Exist(c.GroupLocaleKey(groupCode)) {
+		if _, err = c.Delete(c.GroupLocaleKey(groupCode)); err == nil {
+			deletedKey = append(deletedKey, c.GroupLocaleKey(groupCode))
+		}
+	}
+	return deletedKey, err
 }
 
 func (c *StateManager) FreshIndex() {
@@ -2,6 +2,7 @@ package permission
 
 import (
 	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
 	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -307,3 +308,23 @@ func TestStateManager_CheckGroupSilence(t *testing.T) {
 	assert.NotNil(t, c.GroupSilence(test.G1))
 	assert.NotNil(t, c.UndoGroupSilence(test.G1))
 }
+
+func TestStateManager_GroupLocale(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+	c := initStateManager(t)
+
+	assert.Equal(t, i18n.DefaultLocale, c.GetGroupLocale(test.G1))
+
+	assert.Nil(t, c.SetGroupLocale(test.G1, i18n.English))
+	assert.Nil(t, c.SetGroupLocale(test.G2, i18n.English))
+	assert.Equal(t, i18n.English, c.GetGroupLocale(test.G1))
+
+	assert.Nil(t, c.SetGroupLocale(test.G1, i18n.Chinese))
+	assert.Equal(t, i18n.DefaultLocale, c.GetGroupLocale(test.G1))
+	assert.False(t, c.Exist(c.GroupLocaleKey(test.G1)))
+
+	_, err := c.RemoveAllByGroupCode(test.G2)
+	assert.Nil(t, err)
+	assert.Equal(t, i18n.DefaultLocale, c.GetGroupLocale(test.G2))
+}
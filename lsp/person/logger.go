@@ -0,0 +1,5 @@
+package person
+
+import "github.com/Sora233/MiraiGo-Template/utils"
+
+var logger = utils.GetModuleLogger("Person")
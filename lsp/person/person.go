@@ -0,0 +1,319 @@
+package person
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/tidwall/buntdb"
+)
+
+// maxAliasLength 人物别名的最大长度
+const maxAliasLength = 32
+
+// livingExpire 直播状态标记的过期时间，防止错过下播时一直保持直播中
+const livingExpire = time.Hour * 48
+
+var (
+	ErrInvalidAlias   = fmt.Errorf("人物别名不能为空，不能包含空白字符以及 : * ?，并且不能超过%v个字", maxAliasLength)
+	ErrPersonNotFound = errors.New("人物不存在")
+)
+
+func init() {
+	concern.RegisterLiveStatusObserver(func(notify concern.Notify, living bool) {
+		// 人物只存在于群内
+		if !notify.GetTarget().TargetType().IsGroup() {
+			return
+		}
+		groupCode := notify.GetTarget().TargetCode()
+		if !localdb.Exist(localdb.GroupPersonMemberKey(groupCode, notify.Site(), memberId(notify.GetUid()))) {
+			return
+		}
+		if err := SetLiving(groupCode, notify.Site(), notify.GetUid(), living); err != nil {
+			notify.Logger().Errorf("person SetLiving error %v", err)
+		}
+	})
+}
+
+// Member 人物在一个网站上的订阅
+type Member struct {
+	Site string `json:"site"`
+	// Id 订阅的id，使用字符串形式保存，需要时使用 concern.Concern 的 ParseId 解析
+	Id string `json:"id"`
+}
+
+// Person 把一个群内不同网站的订阅归为同一个人，推送时会合并这个人多个平台的开播
+type Person struct {
+	GroupCode int64     `json:"group_code"`
+	Alias     string    `json:"alias"`
+	Members   []*Member `json:"members"`
+}
+
+func (p *Person) key() string {
+	return localdb.GroupPersonKey(p.GroupCode, p.Alias)
+}
+
+func (p *Person) indexOf(site string, id string) int {
+	for index, m := range p.Members {
+		if m.Site == site && m.Id == id {
+			return index
+		}
+	}
+	return -1
+}
+
+// LivingMembers 返回当前正在直播的成员
+func (p *Person) LivingMembers() []*Member {
+	var result []*Member
+	for _, m := range p.Members {
+		if IsLiving(p.GroupCode, m.Site, m.Id) {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// CheckAlias 别名会作为key的一部分，所以不允许包含 : * ? 以及空白字符
+func CheckAlias(alias string) error {
+	if len(alias) == 0 || utf8.RuneCountInString(alias) > maxAliasLength {
+		return ErrInvalidAlias
+	}
+	if strings.ContainsAny(alias, ":*?") || strings.IndexFunc(alias, unicode.IsSpace) >= 0 {
+		return ErrInvalidAlias
+	}
+	return nil
+}
+
+func memberId(id interface{}) string {
+	return fmt.Sprint(id)
+}
+
+func get(tx *buntdb.Tx, groupCode int64, alias string) (*Person, error) {
+	val, err := tx.Get(localdb.GroupPersonKey(groupCode, alias))
+	if err == buntdb.ErrNotFound {
+		return nil, ErrPersonNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var p = new(Person)
+	if err = json.Unmarshal([]byte(val), p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func save(tx *buntdb.Tx, p *Person) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	_, _, err = tx.Set(p.key(), string(b), nil)
+	return err
+}
+
+// removeMember 从人物中去掉一个成员，没有成员的人物会被删除
+func removeMember(tx *buntdb.Tx, groupCode int64, alias string, site string, id string) error {
+	if _, err := tx.Delete(localdb.GroupPersonMemberKey(groupCode, site, id)); err != nil && err != buntdb.ErrNotFound {
+		return err
+	}
+	p, err := get(tx, groupCode, alias)
+	if err == ErrPersonNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if index := p.indexOf(site, id); index >= 0 {
+		p.Members = append(p.Members[:index], p.Members[index+1:]...)
+	}
+	if len(p.Members) == 0 {
+		_, err = tx.Delete(p.key())
+		return err
+	}
+	return save(tx, p)
+}
+
+// Link 把一个订阅加入人物，人物不存在时会创建，如果这个订阅已经属于其他人物，则会从其他人物中移出
+func Link(groupCode int64, alias string, site string, id interface{}) error {
+	if err := CheckAlias(alias); err != nil {
+		return err
+	}
+	sid := memberId(id)
+	return localdb.RWCoverTx(func(tx *buntdb.Tx) error {
+		memberKey := localdb.GroupPersonMemberKey(groupCode, site, sid)
+		prev, err := tx.Get(memberKey)
+		if err == nil && prev != alias {
+			if err = removeMember(tx, groupCode, prev, site, sid); err != nil {
+				return err
+			}
+		} else if err != nil && err != buntdb.ErrNotFound {
+			return err
+		}
+		p, err := get(tx, groupCode, alias)
+		if err == ErrPersonNotFound {
+			p = &Person{GroupCode: groupCode, Alias: alias}
+		} else if err != nil {
+			return err
+		}
+		if p.indexOf(site, sid) < 0 {
+			p.Members = append(p.Members, &Member{Site: site, Id: sid})
+		}
+		if err = save(tx, p); err != nil {
+			return err
+		}
+		_, _, err = tx.Set(memberKey, alias, nil)
+		return err
+	})
+}
+
+// Unlink 把一个订阅从所属的人物中移出，不属于任何人物时什么也不做
+func Unlink(groupCode int64, site string, id interface{}) error {
+	sid := memberId(id)
+	return localdb.RWCoverTx(func(tx *buntdb.Tx) error {
+		alias, err := tx.Get(localdb.GroupPersonMemberKey(groupCode, site, sid))
+		if err == buntdb.ErrNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if _, err = tx.Delete(localdb.GroupPersonLivingKey(groupCode, site, sid)); err != nil && err != buntdb.ErrNotFound {
+			return err
+		}
+		return removeMember(tx, groupCode, alias, site, sid)
+	})
+}
+
+// Delete 删除一个人物，返回被删除的人物
+func Delete(groupCode int64, alias string) (*Person, error) {
+	var p *Person
+	err := localdb.RWCoverTx(func(tx *buntdb.Tx) error {
+		var err error
+		p, err = get(tx, groupCode, alias)
+		if err != nil {
+			return err
+		}
+		for _, m := range p.Members {
+			for _, key := range []string{
+				localdb.GroupPersonMemberKey(groupCode, m.Site, m.Id),
+				localdb.GroupPersonLivingKey(groupCode, m.Site, m.Id),
+			} {
+				if _, err = tx.Delete(key); err != nil && err != buntdb.ErrNotFound {
+					return err
+				}
+			}
+		}
+		if _, err = tx.Delete(localdb.GroupPersonNotifyKey(groupCode, alias)); err != nil && err != buntdb.ErrNotFound {
+			return err
+		}
+		_, err = tx.Delete(p.key())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Get 通过别名查询人物
+func Get(groupCode int64, alias string) (*Person, error) {
+	var p *Person
+	err := localdb.RCoverTx(func(tx *buntdb.Tx) error {
+		var err error
+		p, err = get(tx, groupCode, alias)
+		return err
+	})
+	return p, err
+}
+
+// Find 查询一个订阅所属的人物
+func Find(groupCode int64, site string, id interface{}) (*Person, error) {
+	var p *Person
+	err := localdb.RCoverTx(func(tx *buntdb.Tx) error {
+		alias, err := tx.Get(localdb.GroupPersonMemberKey(groupCode, site, memberId(id)))
+		if err == buntdb.ErrNotFound {
+			return ErrPersonNotFound
+		} else if err != nil {
+			return err
+		}
+		p, err = get(tx, groupCode, alias)
+		return err
+	})
+	return p, err
+}
+
+// List 返回一个群内的所有人物，按别名排序
+func List(groupCode int64) ([]*Person, error) {
+	var result []*Person
+	err := localdb.RCoverTx(func(tx *buntdb.Tx) error {
+		return tx.AscendKeys(localdb.GroupPersonKey(groupCode, "*"), func(key, value string) bool {
+			var p = new(Person)
+			if err := json.Unmarshal([]byte(value), p); err != nil {
+				logger.WithField("key", key).Errorf("unmarshal person error %v", err)
+				return true
+			}
+			result = append(result, p)
+			return true
+		})
+	})
+	return result, err
+}
+
+// RemoveAllByGroupCode 删除一个群内的所有人物
+func RemoveAllByGroupCode(groupCode int64) error {
+	return localdb.RWCoverTx(func(tx *buntdb.Tx) error {
+		var keys []string
+		for _, pattern := range []string{
+			localdb.GroupPersonKey(groupCode, "*"),
+			localdb.GroupPersonMemberKey(groupCode, "*"),
+			localdb.GroupPersonLivingKey(groupCode, "*"),
+			localdb.GroupPersonNotifyKey(groupCode, "*"),
+		} {
+			err := tx.AscendKeys(pattern, func(key, value string) bool {
+				keys = append(keys, key)
+				return true
+			})
+			if err != nil {
+				return err
+			}
+		}
+		for _, key := range keys {
+			if _, err := tx.Delete(key); err != nil && err != buntdb.ErrNotFound {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetLiving 记录人物成员的直播状态
+func SetLiving(groupCode int64, site string, id interface{}, living bool) error {
+	key := localdb.GroupPersonLivingKey(groupCode, site, memberId(id))
+	if living {
+		return localdb.Set(key, "", localdb.SetExpireOpt(livingExpire))
+	}
+	_, err := localdb.Delete(key, localdb.IgnoreNotFoundOpt())
+	return err
+}
+
+// IsLiving 返回人物成员是否正在直播
+func IsLiving(groupCode int64, site string, id interface{}) bool {
+	return localdb.Exist(localdb.GroupPersonLivingKey(groupCode, site, memberId(id)))
+}
+
+// CheckAndSetNotified 如果 window 内这个人物还没有推送过开播，则返回true并记录本次推送
+func CheckAndSetNotified(groupCode int64, alias string, window time.Duration) bool {
+	var replaced bool
+	err := localdb.Set(localdb.GroupPersonNotifyKey(groupCode, alias), "",
+		localdb.SetExpireOpt(window), localdb.SetGetIsOverwriteOpt(&replaced))
+	if err != nil {
+		logger.WithField("GroupCode", groupCode).WithField("Alias", alias).
+			Errorf("set person notify mark error %v", err)
+		return true
+	}
+	return !replaced
+}
@@ -0,0 +1,122 @@
+package person
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckAlias(t *testing.T) {
+	assert.Nil(t, CheckAlias("小明"))
+	assert.Nil(t, CheckAlias("xiao-ming_1"))
+	for _, alias := range []string{"", "a b", "a:b", "a*", "a?", "一二三四五六七八九十一二三四五六七八九十一二三四五六七八九十一二三"} {
+		assert.Equal(t, ErrInvalidAlias, CheckAlias(alias), alias)
+	}
+}
+
+func TestLink(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	assert.Equal(t, ErrInvalidAlias, Link(test.G1, "a b", test.Site1, test.UID1))
+
+	assert.Nil(t, Link(test.G1, "alice", test.Site1, test.UID1))
+	assert.Nil(t, Link(test.G1, "alice", test.Site2, test.NAME1))
+	// 重复添加不会产生重复的成员
+	assert.Nil(t, Link(test.G1, "alice", test.Site2, test.NAME1))
+	assert.Nil(t, Link(test.G2, "alice", test.Site1, test.UID1))
+
+	p, err := Get(test.G1, "alice")
+	assert.Nil(t, err)
+	if assert.Len(t, p.Members, 2) {
+		assert.Equal(t, &Member{Site: test.Site1, Id: "777"}, p.Members[0])
+		assert.Equal(t, &Member{Site: test.Site2, Id: test.NAME1}, p.Members[1])
+	}
+
+	p, err = Find(test.G1, test.Site2, test.NAME1)
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", p.Alias)
+
+	_, err = Find(test.G1, test.Site2, test.NAME2)
+	assert.Equal(t, ErrPersonNotFound, err)
+
+	// 移动到另一个人物
+	assert.Nil(t, Link(test.G1, "bob", test.Site1, test.UID1))
+	p, err = Find(test.G1, test.Site1, test.UID1)
+	assert.Nil(t, err)
+	assert.Equal(t, "bob", p.Alias)
+
+	persons, err := List(test.G1)
+	assert.Nil(t, err)
+	if assert.Len(t, persons, 2) {
+		assert.Equal(t, "alice", persons[0].Alias)
+		assert.Len(t, persons[0].Members, 1)
+		assert.Equal(t, "bob", persons[1].Alias)
+	}
+
+	// 最后一个成员移出后人物被删除
+	assert.Nil(t, Unlink(test.G1, test.Site1, test.UID1))
+	_, err = Get(test.G1, "bob")
+	assert.Equal(t, ErrPersonNotFound, err)
+	assert.Nil(t, Unlink(test.G1, test.Site1, test.UID1))
+
+	p, err = Delete(test.G1, "alice")
+	assert.Nil(t, err)
+	assert.Len(t, p.Members, 1)
+	_, err = Find(test.G1, test.Site2, test.NAME1)
+	assert.Equal(t, ErrPersonNotFound, err)
+	_, err = Delete(test.G1, "alice")
+	assert.Equal(t, ErrPersonNotFound, err)
+
+	// 其他群不受影响
+	p, err = Get(test.G2, "alice")
+	assert.Nil(t, err)
+	assert.Len(t, p.Members, 1)
+
+	assert.Nil(t, RemoveAllByGroupCode(test.G2))
+	persons, err = List(test.G2)
+	assert.Nil(t, err)
+	assert.Empty(t, persons)
+	_, err = Find(test.G2, test.Site1, test.UID1)
+	assert.Equal(t, ErrPersonNotFound, err)
+}
+
+func TestLiving(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	assert.Nil(t, Link(test.G1, "alice", test.Site1, test.UID1))
+	assert.Nil(t, Link(test.G1, "alice", test.Site2, test.NAME1))
+
+	p, err := Get(test.G1, "alice")
+	assert.Nil(t, err)
+	assert.Empty(t, p.LivingMembers())
+
+	assert.Nil(t, SetLiving(test.G1, test.Site1, test.UID1, true))
+	assert.True(t, IsLiving(test.G1, test.Site1, test.UID1))
+	assert.False(t, IsLiving(test.G2, test.Site1, test.UID1))
+	assert.Len(t, p.LivingMembers(), 1)
+
+	assert.Nil(t, SetLiving(test.G1, test.Site2, test.NAME1, true))
+	assert.Len(t, p.LivingMembers(), 2)
+
+	assert.Nil(t, SetLiving(test.G1, test.Site1, test.UID1, false))
+	assert.Nil(t, SetLiving(test.G1, test.Site1, test.UID1, false))
+	assert.Len(t, p.LivingMembers(), 1)
+
+	assert.Nil(t, Unlink(test.G1, test.Site2, test.NAME1))
+	assert.False(t, IsLiving(test.G1, test.Site2, test.NAME1))
+}
+
+func TestCheckAndSetNotified(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	assert.True(t, CheckAndSetNotified(test.G1, "alice", time.Second))
+	assert.False(t, CheckAndSetNotified(test.G1, "alice", time.Second))
+	assert.True(t, CheckAndSetNotified(test.G2, "alice", time.Second))
+	time.Sleep(time.Second * 2)
+	assert.True(t, CheckAndSetNotified(test.G1, "alice", time.Second))
+}
@@ -3,6 +3,7 @@ package lsp
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"runtime/debug"
@@ -10,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Mrs4s/MiraiGo/client"
 	"github.com/Mrs4s/MiraiGo/message"
 	"github.com/Sora233/MiraiGo-Template/config"
 	"github.com/Sora233/sliceutil"
@@ -17,9 +19,14 @@ import (
 	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/interfaces"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/outbox"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/permission"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/scheduler"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
+	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
 	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/sirupsen/logrus"
 )
@@ -44,7 +51,7 @@ func (c *LspPrivateCommand) Execute() {
 		if err := recover(); err != nil {
 			logger.WithField("stack", string(debug.Stack())).
 				Errorf("panic recovered: %v", err)
-			c.textSend("エラー発生：看到该信息表示BOT出了一些问题，该问题已记录")
+			c.templateSend("reply.panic", nil)
 		}
 	}()
 
@@ -106,12 +113,26 @@ func (c *LspPrivateCommand) Execute() {
 		c.AdminCommand()
 	case SilenceCommand:
 		c.SilenceCommand()
+	case LocaleCommand:
+		c.LocaleCommand()
+	case CopyCommand:
+		c.CopyCommand()
 	case NoUpdateCommand:
 		c.NoUpdateCommand()
 	case AbnormalConcernCheck:
 		c.AbnormalConcernCheckCommand()
 	case CleanConcern:
 		c.CleanConcernCommand()
+	case LoginCommand:
+		c.LoginCommand()
+	case SearchCommand:
+		c.SearchCommand()
+	case PreviewCommand:
+		c.PreviewCommand()
+	case MirrorCommand:
+		c.MirrorCommand()
+	case OutboxCommand:
+		c.OutboxCommand()
 	default:
 		if CheckCustomPrivateCommand(c.CommandName()) {
 			func() {
@@ -169,7 +190,7 @@ func (c *LspPrivateCommand) NoUpdateCommand() {
 
 	_, output := c.parseCommandSyntax(&noUpdateCmd, c.CommandName())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -180,16 +201,18 @@ func (c *LspPrivateCommand) NoUpdateCommand() {
 	if noUpdateCmd.Delete {
 		_, err = localdb.Delete(key, localdb.IgnoreNotFoundOpt())
 		if err == nil {
-			c.textReply("成功 - 您将接收到更新消息")
+			c.templateReply("reply.no_update.disabled", nil)
 		}
 	} else {
 		err = localdb.Set(key, "")
 		if err == nil {
-			c.textReply("成功 - 您将不再接受更新消息")
+			c.templateReply("reply.no_update.enabled", nil)
 		}
 	}
 	if err != nil {
-		c.textReplyF("失败 - %v", err)
+		c.templateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
 	}
 }
 
@@ -200,7 +223,7 @@ func (c *LspPrivateCommand) AbnormalConcernCheckCommand() {
 
 	_, output := c.parseCommandSyntax(&struct{}{}, c.CommandName())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -230,7 +253,7 @@ func (c *LspPrivateCommand) CleanConcernCommand() {
 
 	_, output := c.parseCommandSyntax(&cleanConcernCmd, c.CommandName())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -241,6 +264,370 @@ func (c *LspPrivateCommand) CleanConcernCommand() {
 
 }
 
+func (c *LspPrivateCommand) SearchCommand() {
+	log := c.DefaultLoggerWithCommand(c.CommandName())
+	log.Infof("run %v command", c.CommandName())
+	defer func() { log.Infof("%v command end", c.CommandName()) }()
+
+	var searchCmd struct {
+		Group   int64  `optional:"" short:"g" help:"要搜索的QQ群号码"`
+		Keyword string `arg:"" optional:"" help:"消息中包含的关键字"`
+		Uin     int64  `optional:"" short:"u" help:"发送者的QQ号"`
+		Since   string `optional:"" help:"开始时间，例如 12h、21:00、2006-01-02 15:04"`
+		Until   string `optional:"" help:"结束时间，格式同--since"`
+		Limit   int    `optional:"" short:"n" default:"10" help:"最多显示的条数"`
+		Fetch   int    `optional:"" help:"搜索前先从协议端拉取最近的消息条数"`
+	}
+	_, output := c.parseCommandSyntax(&searchCmd, c.CommandName(), kong.Description("搜索群消息存档"))
+	if output != "" {
+		c.usageReply(output)
+	}
+	if c.exit {
+		return
+	}
+
+	groupCode := searchCmd.Group
+	if err := c.checkGroupCode(groupCode); err != nil {
+		c.errorReply(err)
+		return
+	}
+	query, err := newSearchQuery(searchCmd.Keyword, searchCmd.Uin, searchCmd.Since, searchCmd.Until, searchCmd.Limit)
+	if err != nil {
+		c.templateReply("reply.param_error", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	log = log.WithFields(localutils.GroupLogFields(groupCode))
+	ISearch(c.NewMessageContext(log), groupCode, query, searchCmd.Fetch)
+}
+
+func (c *LspPrivateCommand) PreviewCommand() {
+	log := c.DefaultLoggerWithCommand(c.CommandName())
+	log.Infof("run %v command", c.CommandName())
+	defer func() { log.Infof("%v command end", c.CommandName()) }()
+
+	var previewCmd struct {
+		Group  int64  `optional:"" short:"g" help:"要操作的QQ群号码，不指定时操作自己的私聊订阅"`
+		Site   string `optional:"" short:"s" default:"bilibili" help:"网站参数"`
+		Type   string `optional:"" short:"t" default:"" help:"类型参数"`
+		Limit  int    `optional:"" short:"n" default:"5" help:"预览最新的条数"`
+		Render bool   `optional:"" short:"r" help:"把推送的内容发送给自己"`
+		Id     string `arg:""`
+	}
+	_, output := c.parseCommandSyntax(&previewCmd, c.CommandName(), kong.Description("预览订阅最新的内容在群内是否会推送"))
+	if output != "" {
+		c.usageReply(output)
+	}
+	if c.exit {
+		return
+	}
+
+	site, ctype, err := c.ParseRawSiteAndType(previewCmd.Site, previewCmd.Type)
+	if err != nil {
+		log = log.WithField("args", c.GetArgs())
+		log.Errorf("parse raw concern failed %v", err)
+		c.templateReply("reply.param_error", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	target, err := c.parseTarget(previewCmd.Group)
+	if err != nil {
+		c.errorReply(err)
+		return
+	}
+	log = log.WithFields(mmsg.TargetLogFields(target)).WithField("site", site).WithField("type", ctype)
+	IPreview(c.NewMessageContext(log), target, previewCmd.Id, site, ctype, previewCmd.Limit, previewCmd.Render)
+}
+
+func (c *LspPrivateCommand) LoginCommand() {
+	log := c.DefaultLoggerWithCommand(c.CommandName())
+	log.Infof("run %v command", c.CommandName())
+	defer func() { log.Infof("%v command end", c.CommandName()) }()
+
+	if !c.l.PermissionStateManager.RequireAny(
+		permission.AdminRoleRequireOption(c.uin()),
+	) {
+		c.noPermission()
+		return
+	}
+
+	var loginCmd struct {
+		Site   string `optional:"" short:"s" default:"bilibili" help:"网站参数"`
+		Status bool   `optional:"" help:"查看当前登录状态"`
+	}
+
+	_, output := c.parseCommandSyntax(&loginCmd, c.CommandName(), kong.Description("扫码登录订阅模块使用的账号"), kong.UsageOnError())
+	if output != "" {
+		c.usageReply(output)
+	}
+	if c.exit {
+		return
+	}
+
+	site, err := concern.ParseRawSite(loginCmd.Site)
+	if err != nil {
+		c.templateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	cm, err := concern.GetConcernBySite(site)
+	if err != nil {
+		c.templateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	provider, ok := cm.(interfaces.QRLoginProvider)
+	if !ok {
+		c.templateReply("reply.login.not_supported", map[string]interface{}{
+			"site": site,
+		})
+		return
+	}
+
+	if loginCmd.Status {
+		status := provider.LoginStatus()
+		c.templateReply("reply.login.status", map[string]interface{}{
+			"site":    site,
+			"status":  status.Status,
+			"expired": status.Expired,
+			"expire":  status.Expire,
+			"uid":     status.Uid,
+		})
+		return
+	}
+
+	uin := c.uin()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*3)
+	png, err := provider.QRLogin(ctx, func(err error) {
+		defer cancel()
+		if err != nil {
+			log.Errorf("QRLogin failed %v", err)
+			if m := c.templateMsg("reply.login.failed", map[string]interface{}{
+				"site":  site,
+				"error": err,
+			}); m != nil {
+				c.l.SendMsg(m, mmsg.NewPrivateTarget(uin))
+			}
+			return
+		}
+		status := provider.LoginStatus()
+		log.WithField("uid", status.Uid).Info("QRLogin success")
+		if m := c.templateMsg("reply.login.success", map[string]interface{}{
+			"site":             site,
+			"uid":              status.Uid,
+			"restart_required": status.RestartRequired,
+		}); m != nil {
+			c.l.SendMsg(m, mmsg.NewPrivateTarget(uin))
+		}
+	})
+	if err != nil {
+		cancel()
+		log.Errorf("QRLogin error %v", err)
+		c.templateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	m := mmsg.NewMSG()
+	if tip := c.templateMsg("reply.login.qrcode", map[string]interface{}{
+		"site": site,
+	}); tip != nil {
+		m.Append(tip.Elements()...)
+	}
+	m.Image(png, "[登录二维码]")
+	c.send(m)
+}
+
+func (c *LspPrivateCommand) MirrorCommand() {
+	log := c.DefaultLoggerWithCommand(c.CommandName())
+	log.Infof("run %v command", c.CommandName())
+	defer func() { log.Infof("%v command end", c.CommandName()) }()
+
+	if !c.l.PermissionStateManager.RequireAny(
+		permission.AdminRoleRequireOption(c.uin()),
+	) {
+		c.noPermission()
+		return
+	}
+
+	var mirrorCmd struct {
+		Site  string `optional:"" short:"s" default:"twitter" help:"网站参数"`
+		Reset string `optional:"" help:"清除指定镜像站的冷却和统计，all为全部镜像站"`
+	}
+
+	_, output := c.parseCommandSyntax(&mirrorCmd, c.CommandName(), kong.Description("查看订阅模块使用的镜像站状态"), kong.UsageOnError())
+	if output != "" {
+		c.usageReply(output)
+	}
+	if c.exit {
+		return
+	}
+
+	site, err := concern.ParseRawSite(mirrorCmd.Site)
+	if err != nil {
+		c.templateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	cm, err := concern.GetConcernBySite(site)
+	if err != nil {
+		c.templateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	provider, ok := cm.(interfaces.MirrorProvider)
+	if !ok {
+		c.templateReply("reply.mirror.not_supported", map[string]interface{}{
+			"site": site,
+		})
+		return
+	}
+
+	if mirrorCmd.Reset != "" {
+		var host = mirrorCmd.Reset
+		if host == "all" {
+			host = ""
+		}
+		if provider.ResetMirror(host) == 0 {
+			c.templateReply("reply.mirror.not_found", map[string]interface{}{
+				"host": mirrorCmd.Reset,
+			})
+		} else {
+			log.WithField("host", mirrorCmd.Reset).Info("reset mirror")
+			c.templateReply("reply.success", nil)
+		}
+		return
+	}
+
+	var statusList = provider.MirrorStatus()
+	if len(statusList) == 0 {
+		c.templateReply("reply.mirror.empty", map[string]interface{}{
+			"site": site,
+		})
+		return
+	}
+	var mirrors []map[string]interface{}
+	for _, status := range statusList {
+		mirrors = append(mirrors, map[string]interface{}{
+			"host":           status.Host,
+			"healthy":        status.Healthy,
+			"cooldown_until": status.CooldownUntil,
+			"success_rate":   status.SuccessRate * 100,
+			"latency":        status.Latency.Round(time.Millisecond),
+			"requests":       status.Requests,
+			"failures":       status.Failures,
+			"challenges":     status.Challenges,
+			"anubis_solved":  status.AnubisSolved,
+			"last_error":     status.LastError,
+		})
+	}
+	c.templateSend("reply.mirror.status", map[string]interface{}{
+		"site":    site,
+		"mirrors": mirrors,
+	})
+}
+
+func (c *LspPrivateCommand) OutboxCommand() {
+	log := c.DefaultLoggerWithCommand(c.CommandName())
+	log.Infof("run %v command", c.CommandName())
+	defer func() { log.Infof("%v command end", c.CommandName()) }()
+
+	if !c.l.PermissionStateManager.RequireAny(
+		permission.AdminRoleRequireOption(c.uin()),
+	) {
+		c.noPermission()
+		return
+	}
+
+	var outboxCmd struct {
+		Dead     bool    `optional:"" short:"d" help:"查看死信"`
+		Resend   int64   `optional:"" help:"立即重新发送指定id的死信"`
+		Purge    []int64 `optional:"" help:"删除指定id的死信，多个可用英文逗号隔开"`
+		PurgeAll bool    `optional:"" help:"删除全部死信"`
+	}
+
+	_, output := c.parseCommandSyntax(&outboxCmd, c.CommandName(), kong.Description("查看发送失败的推送"), kong.UsageOnError())
+	if output != "" {
+		c.usageReply(output)
+	}
+	if c.exit {
+		return
+	}
+
+	switch {
+	case outboxCmd.Resend != 0:
+		log = log.WithField("id", outboxCmd.Resend)
+		if err := outbox.Resend(outboxCmd.Resend); err != nil {
+			log.Errorf("outbox resend failed %v", err)
+			if localdb.IsNotFound(err) {
+				c.templateReply("reply.outbox.not_found", map[string]interface{}{
+					"id": outboxCmd.Resend,
+				})
+			} else {
+				c.templateReply("reply.failed", map[string]interface{}{
+					"error": err,
+				})
+			}
+			return
+		}
+		c.templateReply("reply.success", nil)
+		return
+	case outboxCmd.PurgeAll || len(outboxCmd.Purge) > 0:
+		var ids []int64
+		if !outboxCmd.PurgeAll {
+			ids = outboxCmd.Purge
+		}
+		count, err := outbox.Purge(ids...)
+		if err != nil {
+			log.Errorf("outbox purge failed %v", err)
+			c.templateReply("reply.failed", map[string]interface{}{
+				"error": err,
+			})
+			return
+		}
+		c.templateReply("reply.outbox.purged", map[string]interface{}{
+			"count": count,
+		})
+		return
+	}
+
+	entries, err := outbox.List(outboxCmd.Dead)
+	if err != nil {
+		log.Errorf("outbox list failed %v", err)
+		c.templateReply("reply.failed", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	var data []map[string]interface{}
+	for _, e := range entries {
+		content := []rune(e.Content)
+		if len(content) > 50 {
+			content = append(content[:50], []rune("...")...)
+		}
+		data = append(data, map[string]interface{}{
+			"id":         e.Id,
+			"target":     e.Target,
+			"source":     e.Source,
+			"reason":     e.Reason.Description(),
+			"retry":      e.Retry,
+			"dead":       e.Dead,
+			"next_retry": time.Unix(e.NextRetry, 0),
+			"content":    string(content),
+		})
+	}
+	c.templateSend("reply.outbox.list", map[string]interface{}{
+		"dead":    outboxCmd.Dead,
+		"entries": data,
+	})
+}
+
 func (c *LspPrivateCommand) WhosyourdaddyCommand() {
 	log := c.DefaultLoggerWithCommand(c.CommandName())
 	log.Infof("run %v command", c.CommandName())
@@ -248,14 +635,14 @@ func (c *LspPrivateCommand) WhosyourdaddyCommand() {
 
 	_, output := c.parseCommandSyntax(&struct{}{}, c.CommandName())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
 	}
 
 	if c.l.PermissionStateManager.CheckRole(c.uin(), permission.Admin) {
-		c.textReply("您已经是管理员了，请不要重复使用此命令。")
+		c.templateReply("reply.whosyourdaddy.already_admin", nil)
 		return
 	}
 
@@ -263,14 +650,14 @@ func (c *LspPrivateCommand) WhosyourdaddyCommand() {
 		if err := c.l.PermissionStateManager.GrantRole(c.uin(), permission.Admin); err != nil {
 			log.WithField("permission", permission.Admin.String()).
 				Errorf("GrantRole error %v", err)
-			c.textReply("失败 - 内部错误")
+			c.templateReply("reply.internal_error", nil)
 		} else {
 			log.Info("已配置bot初始管理员，现在可以开始使用bot了，祝你好运")
-			c.textReply("成功 - 您已成为bot管理员")
+			c.templateReply("reply.whosyourdaddy.success", nil)
 		}
 	} else {
 		log.Debug("someone is trying WhosyourdaddyCommand")
-		c.textReply("失败 - 该bot不属于你！")
+		c.templateReply("reply.whosyourdaddy.denied", nil)
 	}
 }
 
@@ -280,24 +667,34 @@ func (c *LspPrivateCommand) ListCommand() {
 	defer func() { log.Infof("%v command end", c.CommandName()) }()
 
 	var listCmd struct {
-		Group int64  `optional:"" short:"g" help:"要操作的QQ群号码"`
-		Site  string `optional:"" short:"s" help:"网站参数"`
+		Group  int64  `optional:"" short:"g" help:"要操作的QQ群号码，不指定时列出自己的私聊订阅"`
+		Site   string `optional:"" short:"s" help:"网站参数"`
+		Person bool   `optional:"" short:"p" help:"按人物列出订阅"`
 	}
 	_, output := c.parseCommandSyntax(&listCmd, c.CommandName())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
 	}
 
 	groupCode := listCmd.Group
-	if err := c.checkGroupCode(groupCode); err != nil {
-		c.textReply(err.Error())
+	if listCmd.Person {
+		if err := c.checkGroupCode(groupCode); err != nil {
+			c.errorReply(err)
+			return
+		}
+		IListPerson(c.NewMessageContext(log.WithFields(localutils.GroupLogFields(groupCode))), groupCode)
 		return
 	}
-	log = log.WithFields(localutils.GroupLogFields(groupCode))
-	IList(c.NewMessageContext(log), groupCode, listCmd.Site)
+	target, err := c.parseTarget(groupCode)
+	if err != nil {
+		c.errorReply(err)
+		return
+	}
+	log = log.WithFields(mmsg.TargetLogFields(target))
+	IList(c.NewMessageContext(log), target, listCmd.Site)
 }
 
 func (c *LspPrivateCommand) ConfigCommand() {
@@ -348,95 +745,159 @@ func (c *LspPrivateCommand) ConfigCommand() {
 				Id string `arg:"" help:"配置的主播id"`
 			} `cmd:"" help:"查看当前过滤器" name:"show" group:"filter"`
 		} `cmd:"" help:"配置动态过滤器" name:"filter"`
-		Group int64 `optional:"" short:"g" help:"要操作的QQ群号码"`
+		Media struct {
+			Site   string `optional:"" short:"s" default:"bilibili" help:"网站参数"`
+			Type   string `optional:"" short:"t" default:"news" help:"推送类型，youtube视频为news"`
+			Id     string `arg:"" help:"配置的主播id"`
+			Switch string `arg:"" default:"on" enum:"on,off" help:"on / off"`
+			Folder string `optional:"" short:"f" help:"存档使用的群文件夹名称，默认为推送存档"`
+			Video  bool   `optional:"" help:"同时存档视频和音频文件（如果有）"`
+			Link   bool   `optional:"" help:"同时存档视频链接"`
+		} `cmd:"" help:"配置推送时把封面等媒体存档到群文件，默认关闭" name:"media"`
+		Deleted struct {
+			Site   string `optional:"" short:"s" default:"bilibili" help:"网站参数"`
+			Id     string `arg:"" help:"配置的主播id"`
+			Action string `arg:"" default:"reply" enum:"recall,reply,off" help:"recall / reply / off"`
+		} `cmd:"" help:"配置动态被作者删除后撤回推送或者回复标注，默认关闭" name:"deleted"`
+		Alias struct {
+			Site  string `optional:"" short:"s" default:"bilibili" help:"网站参数"`
+			Id    string `arg:"" help:"配置的主播id"`
+			Alias string `arg:"" optional:"" help:"新的别名，不指定时删除别名"`
+		} `cmd:"" help:"配置订阅在群内的别名，其他命令可以使用别名代替id，默认为订阅时的名字" name:"alias"`
+		Group int64 `optional:"" short:"g" help:"要操作的QQ群号码，不指定时配置自己的私聊订阅"`
 	}
 
 	kongCtx, output := c.parseCommandSyntax(&configCmd, c.CommandName(),
-		kong.Description("管理BOT的配置，目前支持配置@成员、@全体成员、开启下播推送、开启标题推送、推送过滤"),
+		kong.Description("管理BOT的配置，目前支持配置@成员、@全体成员、开启下播推送、开启标题推送、推送过滤、媒体存档、删除检测、订阅别名"),
 	)
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit || len(kongCtx.Path) <= 1 {
 		return
 	}
 
-	groupCode := configCmd.Group
-	if err := c.checkGroupCode(groupCode); err != nil {
-		c.textReply(err.Error())
+	target, err := c.parseTarget(configCmd.Group)
+	if err != nil {
+		c.errorReply(err)
 		return
 	}
 
 	kongPath := strings.Split(kongCtx.Command(), " ")
 
 	cmd := kongPath[0]
-	log = log.WithFields(localutils.GroupLogFields(groupCode)).WithField("sub_command", cmd)
+	log = log.WithFields(mmsg.TargetLogFields(target)).WithField("sub_command", cmd)
 
 	switch cmd {
 	case "at":
 		site, ctype, err := c.ParseRawSiteAndType(configCmd.At.Site, "live")
 		if err != nil {
 			log.WithField("site", configCmd.At.Site).Errorf("ParseRawSiteAndType failed %v", err)
-			c.textSend(fmt.Sprintf("失败 - %v", err.Error()))
+			c.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return
 		}
 		log = log.WithField("site", site).WithField("id", configCmd.At.Id).WithField("action", configCmd.At.Action).WithField("QQ", configCmd.At.QQ)
-		IConfigAtCmd(c.NewMessageContext(log), groupCode, configCmd.At.Id, site, ctype, configCmd.At.Action, configCmd.At.QQ)
+		IConfigAtCmd(c.NewMessageContext(log), target, configCmd.At.Id, site, ctype, configCmd.At.Action, configCmd.At.QQ)
 	case "at_all":
 		site, ctype, err := c.ParseRawSiteAndType(configCmd.AtAll.Site, "live")
 		if err != nil {
 			log.WithField("site", configCmd.AtAll.Site).Errorf("ParseRawSiteAndType failed %v", err)
-			c.textSend(fmt.Sprintf("失败 - %v", err.Error()))
+			c.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return
 		}
 		var on = localutils.Switch2Bool(configCmd.AtAll.Switch)
 		log = log.WithField("site", site).WithField("id", configCmd.AtAll.Id).WithField("on", on)
-		IConfigAtAllCmd(c.NewMessageContext(log), groupCode, configCmd.AtAll.Id, site, ctype, on)
+		IConfigAtAllCmd(c.NewMessageContext(log), target, configCmd.AtAll.Id, site, ctype, on)
 	case "title_notify":
 		site, ctype, err := c.ParseRawSiteAndType(configCmd.TitleNotify.Site, "live")
 		if err != nil {
 			log.WithField("site", configCmd.TitleNotify.Site).Errorf("ParseRawSiteAndType failed %v", err)
-			c.textSend(fmt.Sprintf("失败 - %v", err.Error()))
+			c.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return
 		}
 		var on = localutils.Switch2Bool(configCmd.TitleNotify.Switch)
 		log = log.WithField("site", site).WithField("id", configCmd.TitleNotify.Id).WithField("on", on)
-		IConfigTitleNotifyCmd(c.NewMessageContext(log), groupCode, configCmd.TitleNotify.Id, site, ctype, on)
+		IConfigTitleNotifyCmd(c.NewMessageContext(log), target, configCmd.TitleNotify.Id, site, ctype, on)
 	case "offline_notify":
 		site, ctype, err := c.ParseRawSiteAndType(configCmd.OfflineNotify.Site, "live")
 		if err != nil {
 			log.WithField("site", configCmd.OfflineNotify.Site).Errorf("ParseRawSiteAndType failed %v", err)
-			c.textSend(fmt.Sprintf("失败 - %v", err.Error()))
+			c.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return
 		}
 		var on = localutils.Switch2Bool(configCmd.OfflineNotify.Switch)
 		log = log.WithField("site", site).WithField("id", configCmd.OfflineNotify.Id).WithField("on", on)
-		IConfigOfflineNotifyCmd(c.NewMessageContext(log), groupCode, configCmd.OfflineNotify.Id, site, ctype, on)
+		IConfigOfflineNotifyCmd(c.NewMessageContext(log), target, configCmd.OfflineNotify.Id, site, ctype, on)
+	case "media":
+		site, ctype, err := c.ParseRawSiteAndType(configCmd.Media.Site, configCmd.Media.Type)
+		if err != nil {
+			log.WithField("site", configCmd.Media.Site).Errorf("ParseRawSiteAndType failed %v", err)
+			c.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
+			return
+		}
+		var on = localutils.Switch2Bool(configCmd.Media.Switch)
+		log = log.WithField("site", site).WithField("id", configCmd.Media.Id).WithField("on", on)
+		IConfigMediaCmd(c.NewMessageContext(log), target, configCmd.Media.Id, site, ctype, on,
+			configCmd.Media.Folder, configCmd.Media.Video, configCmd.Media.Link)
+	case "deleted":
+		site, ctype, err := c.ParseRawSiteAndType(configCmd.Deleted.Site, "news")
+		if err != nil {
+			log.WithField("site", configCmd.Deleted.Site).Errorf("ParseRawSiteAndType failed %v", err)
+			c.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
+			return
+		}
+		log = log.WithField("site", site).WithField("id", configCmd.Deleted.Id).WithField("action", configCmd.Deleted.Action)
+		IConfigDeletedCmd(c.NewMessageContext(log), target, configCmd.Deleted.Id, site, ctype, configCmd.Deleted.Action)
+	case "alias":
+		site, err := c.ParseRawSite(configCmd.Alias.Site)
+		if err != nil {
+			log.WithField("site", configCmd.Alias.Site).Errorf("ParseRawSite failed %v", err)
+			c.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
+			return
+		}
+		log = log.WithField("site", site).WithField("id", configCmd.Alias.Id).WithField("alias", configCmd.Alias.Alias)
+		IConfigAliasCmd(c.NewMessageContext(log), target, configCmd.Alias.Id, site, configCmd.Alias.Alias)
 	case "filter":
 		filterCmd := kongPath[1]
 		site, ctype, err := c.ParseRawSiteAndType(configCmd.Filter.Site, "news")
 		if err != nil {
 			log.WithField("site", configCmd.Filter.Site).Errorf("ParseRawSiteAndType failed %v", err)
-			c.textSend(fmt.Sprintf("失败 - %v", err.Error()))
+			c.templateSend("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return
 		}
 		switch filterCmd {
 		case "type":
-			IConfigFilterCmdType(c.NewMessageContext(log), groupCode, configCmd.Filter.Type.Id, site, ctype, configCmd.Filter.Type.Type)
+			IConfigFilterCmdType(c.NewMessageContext(log), target, configCmd.Filter.Type.Id, site, ctype, configCmd.Filter.Type.Type)
 		case "not_type":
-			IConfigFilterCmdNotType(c.NewMessageContext(log), groupCode, configCmd.Filter.NotType.Id, site, ctype, configCmd.Filter.NotType.Type)
+			IConfigFilterCmdNotType(c.NewMessageContext(log), target, configCmd.Filter.NotType.Id, site, ctype, configCmd.Filter.NotType.Type)
 		case "text":
-			IConfigFilterCmdText(c.NewMessageContext(log), groupCode, configCmd.Filter.Text.Id, site, ctype, configCmd.Filter.Text.Keyword)
+			IConfigFilterCmdText(c.NewMessageContext(log), target, configCmd.Filter.Text.Id, site, ctype, configCmd.Filter.Text.Keyword)
 		case "clear":
-			IConfigFilterCmdClear(c.NewMessageContext(log), groupCode, configCmd.Filter.Clear.Id, site, ctype)
+			IConfigFilterCmdClear(c.NewMessageContext(log), target, configCmd.Filter.Clear.Id, site, ctype)
 		case "show":
-			IConfigFilterCmdShow(c.NewMessageContext(log), groupCode, configCmd.Filter.Show.Id, site, ctype)
+			IConfigFilterCmdShow(c.NewMessageContext(log), target, configCmd.Filter.Show.Id, site, ctype)
 		default:
 			log.WithField("filter_cmd", filterCmd).Errorf("unknown filter command")
-			c.textSend("未知的filter子命令")
+			c.templateSend("reply.config.unknown_filter", nil)
 		}
 	default:
-		c.textSend("暂未支持，你可以催作者GKD")
+		c.templateSend("reply.not_supported", nil)
 	}
 
 }
@@ -453,15 +914,17 @@ func (c *LspPrivateCommand) WatchCommand(remove bool) {
 	)
 
 	var watchCmd struct {
-		Site  string `optional:"" short:"s" default:"bilibili" help:"网站参数"`
-		Type  string `optional:"" short:"t" default:"" help:"类型参数"`
-		Group int64  `optional:"" short:"g" help:"要操作的QQ群号码"`
-		Id    string `arg:""`
+		Site     string   `optional:"" short:"s" default:"bilibili" help:"网站参数"`
+		Type     string   `optional:"" short:"t" default:"" help:"类型参数"`
+		Group    int64    `optional:"" short:"g" help:"要操作的QQ群号码，不指定时操作自己的私聊订阅"`
+		Person   string   `optional:"" short:"p" help:"人物别名，把不同网站的订阅归为同一个人"`
+		FromList bool     `optional:"" name:"from-list" help:"从回复的文本文件读取id，或者把id作为账号的关注分组名称读取分组内的用户"`
+		Id       []string `arg:"" optional:"" help:"订阅的id，可以填多个，使用 网站:id 的格式可以同时订阅不同网站"`
 	}
 
 	_, output := c.parseCommandSyntax(&watchCmd, c.CommandName())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -471,22 +934,52 @@ func (c *LspPrivateCommand) WatchCommand(remove bool) {
 	if err != nil {
 		log = log.WithField("args", c.GetArgs())
 		log.Errorf("parse raw concern failed %v", err)
-		c.textReply(fmt.Sprintf("参数错误 - %v", err))
+		c.templateReply("reply.param_error", map[string]interface{}{
+			"error": err,
+		})
 		return
 	}
 	log = log.WithField("site", site).WithField("type", watchType)
 
-	id := watchCmd.Id
+	ids := watchCmd.Id
 	groupCode := watchCmd.Group
+	if watchCmd.FromList {
+		ids, err = readWatchList(c.l, c.msg.Elements, 0, site, watchCmd.Id)
+		if err != nil {
+			log.Errorf("readWatchList error %v", err)
+			c.errorReply(err)
+			return
+		}
+	}
 
-	if err := c.checkGroupCode(groupCode); err != nil {
-		c.textReply(err.Error())
+	if len(watchCmd.Person) > 0 {
+		// 人物只支持群订阅
+		if err := c.checkGroupCode(groupCode); err != nil {
+			c.errorReply(err)
+			return
+		}
+		log = log.WithFields(localutils.GroupLogFields(groupCode)).WithField("person", watchCmd.Person)
+		if remove && len(ids) == 0 {
+			IUnwatchPerson(c.NewMessageContext(log), groupCode, watchCmd.Person)
+			return
+		}
+	}
+	if len(ids) == 0 {
+		c.templateReply("reply.missing_id", nil)
 		return
 	}
-
-	log = log.WithFields(localutils.GroupLogFields(groupCode))
-
-	IWatch(c.NewMessageContext(log), groupCode, id, site, watchType, remove)
+	items := parseWatchItems(ids, site, watchType, watchCmd.Type)
+	if len(watchCmd.Person) > 0 && !remove {
+		IWatchPersonItems(c.NewMessageContext(log), groupCode, watchCmd.Person, items)
+		return
+	}
+	target, err := c.parseTarget(groupCode)
+	if err != nil {
+		c.errorReply(err)
+		return
+	}
+	log = log.WithFields(mmsg.TargetLogFields(target))
+	IWatchItems(c.NewMessageContext(log), target, items, remove)
 }
 
 func (c *LspPrivateCommand) EnableCommand(disable bool) {
@@ -502,14 +995,14 @@ func (c *LspPrivateCommand) EnableCommand(disable bool) {
 
 	_, output := c.parseCommandSyntax(&enableCmd, c.CommandName())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
 	}
 
 	if len(enableCmd.Command) == 0 {
-		c.textReply("失败 - 没有指定要操作的命令名")
+		c.templateReply("reply.missing_command", nil)
 		log.Errorf("empty command")
 		return
 	}
@@ -517,7 +1010,7 @@ func (c *LspPrivateCommand) EnableCommand(disable bool) {
 	command := CombineCommand(enableCmd.Command)
 	if !CheckOperateableCommand(command) {
 		log.Errorf("unknown command")
-		c.textReply("失败 - 命令名非法")
+		c.templateReply("reply.enable.illegal_command", nil)
 		return
 	}
 
@@ -527,7 +1020,9 @@ func (c *LspPrivateCommand) EnableCommand(disable bool) {
 			return
 		}
 		if enableCmd.Group != 0 {
-			c.textReply(fmt.Sprintf("注意：--global模式，忽略参数-g %v", enableCmd.Group))
+			c.templateReply("reply.enable.global_ignore_group", map[string]interface{}{
+				"group": enableCmd.Group,
+			})
 		}
 
 		var err error
@@ -538,12 +1033,12 @@ func (c *LspPrivateCommand) EnableCommand(disable bool) {
 			err = c.l.PermissionStateManager.GlobalEnableGroupCommand(command)
 		}
 		if err == nil {
-			c.textReply("成功")
+			c.templateReply("reply.success", nil)
 		} else if err == permission.ErrPermissionExist {
 			if disable {
-				c.textReply("失败 - 该命令已禁用")
+				c.templateReply("reply.enable.global_already_disabled", nil)
 			} else {
-				c.textReply("失败 - 该命令已启用")
+				c.templateReply("reply.enable.global_already_enabled", nil)
 			}
 		}
 	} else {
@@ -554,7 +1049,7 @@ func (c *LspPrivateCommand) EnableCommand(disable bool) {
 
 		groupCode := enableCmd.Group
 		if err := c.checkGroupCode(groupCode); err != nil {
-			c.textReply(err.Error())
+			c.errorReply(err)
 			return
 		}
 
@@ -580,7 +1075,7 @@ func (c *LspPrivateCommand) GrantCommand() {
 	}
 	_, output := c.parseCommandSyntax(&grantCmd, c.CommandName())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -592,7 +1087,7 @@ func (c *LspPrivateCommand) GrantCommand() {
 	grantTo := grantCmd.Target
 	if grantCmd.Command == "" && grantCmd.Role == "" {
 		log.Errorf("command and role both empty")
-		c.textReply("参数错误 - 必须指定-c / -r")
+		c.templateReply("reply.grant.missing_target", nil)
 		return
 	}
 
@@ -601,7 +1096,7 @@ func (c *LspPrivateCommand) GrantCommand() {
 
 	if grantCmd.Command != "" {
 		if err := c.checkGroupCode(groupCode); err != nil {
-			c.textReply(err.Error())
+			c.errorReply(err)
 			return
 		}
 		log = log.WithFields(localutils.GroupLogFields(groupCode))
@@ -610,7 +1105,7 @@ func (c *LspPrivateCommand) GrantCommand() {
 		role := permission.NewRoleFromString(grantCmd.Role)
 		if role != permission.Admin {
 			if err := c.checkGroupCode(groupCode); err != nil {
-				c.textReply(err.Error())
+				c.errorReply(err)
 				return
 			}
 		}
@@ -639,7 +1134,7 @@ func (c *LspPrivateCommand) BlockCommand() {
 
 	_, output := c.parseCommandSyntax(&blockCmd, c.CommandName())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -647,7 +1142,7 @@ func (c *LspPrivateCommand) BlockCommand() {
 
 	if blockCmd.Uin == c.uin() {
 		log.Errorf("can not block yourself")
-		c.textReply("失败 - 不能block自己")
+		c.templateReply("reply.block.self", nil)
 		return
 	}
 
@@ -667,31 +1162,31 @@ func (c *LspPrivateCommand) BlockCommand() {
 		log = log.WithField("TargetGroupName", name)
 	}
 
-	if name == "" {
-		name = "未知目标"
-	}
-
 	if !blockCmd.Delete {
 		if err := c.l.PermissionStateManager.AddBlockList(blockCmd.Uin, time.Duration(blockCmd.Days)*time.Hour*24); err == nil {
 			log.Info("blocked")
-			c.textReplyF("成功 - %v", name)
+			c.templateReply("reply.block.success", map[string]interface{}{
+				"name": name,
+			})
 		} else if err == localdb.ErrKeyExist {
 			log.Errorf("block failed - duplicate")
-			c.textReply("失败 - 已经block过了")
+			c.templateReply("reply.block.already", nil)
 		} else {
 			log.Errorf("block failed err %v", err)
-			c.textReply("失败 - 内部错误")
+			c.templateReply("reply.internal_error", nil)
 		}
 	} else {
 		if err := c.l.PermissionStateManager.DeleteBlockList(blockCmd.Uin); err == nil {
 			log.Info("unblocked")
-			c.textReplyF("成功 - %v", name)
+			c.templateReply("reply.unblock.success", map[string]interface{}{
+				"name": name,
+			})
 		} else if localdb.IsNotFound(err) {
 			log.Errorf("unblock failed - not exist")
-			c.textReply("失败 - 该目标未被block")
+			c.templateReply("reply.unblock.not_blocked", nil)
 		} else {
 			log.Errorf("unblock failed err %v", err)
-			c.textReply("失败 - 内部错误")
+			c.templateReply("reply.internal_error", nil)
 		}
 	}
 }
@@ -716,7 +1211,7 @@ func (c *LspPrivateCommand) LogCommand() {
 
 	_, output := c.parseCommandSyntax(&logCmd, c.CommandName())
 	if output != "" {
-		c.textSend(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -731,7 +1226,9 @@ func (c *LspPrivateCommand) LogCommand() {
 	logName := fmt.Sprintf("%v.log", logCmd.Date.Format("2006-01-02"))
 	b, err := os.ReadFile("logs/" + logName)
 	if err != nil {
-		c.textSend(fmt.Sprintf("失败 - %v", err))
+		c.templateSend("reply.failed", map[string]interface{}{
+			"error": err,
+		})
 		return
 	}
 	var lines []string
@@ -757,9 +1254,11 @@ func (c *LspPrivateCommand) LogCommand() {
 	lines = lines[len(lines)-logCmd.N:]
 
 	if len(lines) == 0 {
-		c.textSend("无结果")
+		c.templateSend("reply.log.empty", nil)
 	} else {
-		c.textSend(strings.Join(lines, "\n"))
+		c.templateSend("reply.log.result", map[string]interface{}{
+			"lines": lines,
+		})
 	}
 }
 
@@ -782,7 +1281,7 @@ func (c *LspPrivateCommand) QuitCommand() {
 
 	_, output := c.parseCommandSyntax(&quitCmd, c.CommandName())
 	if output != "" {
-		c.textSend(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -790,7 +1289,7 @@ func (c *LspPrivateCommand) QuitCommand() {
 
 	if quitCmd.GroupCode == 0 {
 		log.Errorf("GroupCode is 0")
-		c.textSend("没有指定群号，请输入群号")
+		c.templateSend("reply.quit.missing_group", nil)
 		return
 	}
 
@@ -806,20 +1305,24 @@ func (c *LspPrivateCommand) QuitCommand() {
 	if gi == nil {
 		if quitCmd.Force {
 			log.Debugf("没有找到该QQ群，force已启用")
-			c.textSend("没有找到该QQ群，请确认bot在群内，但由于指定了-f参数，将强制清除bot在该群的数据")
+			c.templateSend("reply.quit.force_clean", nil)
 		} else {
 			log.Errorf("没有找到该QQ群，force已禁用")
-			c.textSend("没有找到该QQ群，请确认bot在群内，如果要强制清除bot在该群内的数据，请指定-f参数")
+			c.templateSend("reply.quit.group_not_found", nil)
 			return
 		}
 	} else {
 		gi.Quit()
 		log.Debugf("已退出群【%v】", displayName)
-		c.textSend(fmt.Sprintf("已退出群【%v】", displayName))
+		c.templateSend("reply.quit.success", map[string]interface{}{
+			"name": displayName,
+		})
 	}
 	c.l.RemoveAllByGroup(quitCmd.GroupCode)
 	log.Debugf("已清除群【%v】的数据", displayName)
-	c.textSend(fmt.Sprintf("已清除群【%v】的数据", displayName))
+	c.templateSend("reply.quit.cleaned", map[string]interface{}{
+		"name": displayName,
+	})
 }
 
 func (c *LspPrivateCommand) ModeCommand() {
@@ -840,7 +1343,7 @@ func (c *LspPrivateCommand) ModeCommand() {
 
 	_, output := c.parseCommandSyntax(&modeCmd, c.CommandName(), kong.Description("切换BOT模式"), kong.UsageOnError())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -849,14 +1352,9 @@ func (c *LspPrivateCommand) ModeCommand() {
 	if modeCmd.Mode == "" {
 		mode := c.l.LspStateManager.GetCurrentMode()
 		log.Infof("当前Mode为%v", mode)
-		switch c.l.LspStateManager.GetCurrentMode() {
-		case PublicMode:
-			c.textReply("当前模式为公开")
-		case PrivateMode:
-			c.textReply("当前模式为私人")
-		case ProtectMode:
-			c.textReply("当前模式为审核")
-		}
+		c.templateReply("reply.mode.current", map[string]interface{}{
+			"mode": string(mode),
+		})
 		return
 	}
 
@@ -873,15 +1371,21 @@ func (c *LspPrivateCommand) ModeCommand() {
 		err = c.l.LspStateManager.SetMode(ProtectMode)
 	default:
 		log.Errorf("未知的模式")
-		c.textSend(fmt.Sprintf("未知的模式【%v】，仅支持<公开> <私人> <审核>，请查看命令文档", modeCmd.Mode))
+		c.templateSend("reply.mode.unknown", map[string]interface{}{
+			"mode": modeCmd.Mode,
+		})
 		return
 	}
 	if err != nil {
 		log.Errorf("切换模式失败 %v", err)
-		c.textReply(fmt.Sprintf("切换模式失败 - %v", err))
+		c.templateReply("reply.mode.failed", map[string]interface{}{
+			"error": err,
+		})
 	} else {
 		log.Infof("切换到%v模式", modeCmd.Mode)
-		c.textReply(fmt.Sprintf("成功 - 切换到%v模式", modeCmd.Mode))
+		c.templateReply("reply.mode.success", map[string]interface{}{
+			"mode": modeCmd.Mode,
+		})
 	}
 }
 
@@ -906,7 +1410,7 @@ func (c *LspPrivateCommand) GroupRequestCommand() {
 
 	_, output := c.parseCommandSyntax(&groupRequestCmd, c.CommandName(), kong.Description("处理群邀请"), kong.UsageOnError())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -925,13 +1429,15 @@ func (c *LspPrivateCommand) GroupRequestCommand() {
 		requests, err := c.l.LspStateManager.ListGroupInvitedRequest()
 		if err != nil {
 			log.Errorf("ListGroupInvitedRequest error - %v", err)
-			c.textReply(fmt.Sprintf("失败 - %v", err))
+			c.templateReply("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return
 		}
 
 		if len(requests) == 0 {
 			log.Infof("没有查询到加群邀请")
-			c.textReply("没有查询到加群邀请")
+			c.templateReply("reply.group_request.empty", nil)
 			return
 		}
 
@@ -939,7 +1445,10 @@ func (c *LspPrivateCommand) GroupRequestCommand() {
 			// 拒绝全部？
 			if !groupRequestCmd.All {
 				log.Info("拒绝全部加群邀请需要确认")
-				c.textReply(fmt.Sprintf("您似乎想要拒绝全部%v个加群邀请，如果确定想这样做，请输入：\n%v --all %v", len(requests), c.GetCmd(), strings.Join(c.GetArgs(), " ")))
+				c.templateReply("reply.group_request.confirm_reject_all", map[string]interface{}{
+					"count":   len(requests),
+					"command": c.GetCmd() + " --all " + strings.Join(c.GetArgs(), " "),
+				})
 				return
 			}
 			// 拒绝全部！
@@ -952,7 +1461,9 @@ func (c *LspPrivateCommand) GroupRequestCommand() {
 				}
 			}
 			log.Infof("已拒绝%v个加群邀请", len(requests))
-			c.textReply(fmt.Sprintf("成功 - 已拒绝全部%v个加群邀请", len(requests)))
+			c.templateReply("reply.group_request.rejected_all", map[string]interface{}{
+				"count": len(requests),
+			})
 			return
 		}
 
@@ -972,26 +1483,32 @@ func (c *LspPrivateCommand) GroupRequestCommand() {
 				}
 			}
 			log.Infof("已接受%v个加群邀请", len(requests))
-			c.textReply(fmt.Sprintf("成功 - 已接受全部%v个加群邀请", len(requests)))
+			c.templateReply("reply.group_request.accepted_all", map[string]interface{}{
+				"count": len(requests),
+			})
 			return
 		}
 
 		// 展示加群邀请
-		var sb strings.Builder
+		var data []map[string]interface{}
 		for _, req := range requests {
-			sb.WriteString(fmt.Sprintf("ID:%v %v(%v) 邀请加入群 %v(%v)\n", req.RequestId, req.InvitorNick, req.InvitorUin, req.GroupName, req.GroupCode))
+			data = append(data, groupRequestData(req))
 		}
 		log.Infof("查询到%v个加群邀请", len(requests))
-		c.textReply(sb.String())
+		c.templateReply("reply.group_request.list", map[string]interface{}{
+			"requests": data,
+		})
 	} else {
 		request, err := c.l.LspStateManager.GetGroupInvitedRequest(groupRequestCmd.RequestId)
 		if localdb.IsNotFound(err) {
 			log.Errorf("处理加群邀请失败 - 未找到该邀请")
-			c.textReply(fmt.Sprintf("失败 - 未找到该邀请【%v】", groupRequestCmd.RequestId))
+			c.templateReply("reply.group_request.not_found", map[string]interface{}{
+				"id": groupRequestCmd.RequestId,
+			})
 			return
 		} else if err != nil {
 			log.Errorf("GetGroupInvitedRequest error %v", err)
-			c.textReply(fmt.Sprintf("失败 - 内部错误"))
+			c.templateReply("reply.internal_error", nil)
 			return
 		}
 		log := log.WithFields(logrus.Fields{
@@ -1003,7 +1520,9 @@ func (c *LspPrivateCommand) GroupRequestCommand() {
 		if groupRequestCmd.Reject {
 			c.bot.SolveGroupJoinRequest(request, false, false, rmsg)
 			log.Info("拒绝加群邀请成功")
-			c.textReply(fmt.Sprintf("成功- 已拒绝 %v(%v) 邀请加群 %v(%v)", request.InvitorNick, request.InvitorUin, request.GroupName, request.GroupCode))
+			c.templateReply("reply.group_request.rejected", map[string]interface{}{
+				"request": groupRequestData(request),
+			})
 		} else {
 			c.bot.SolveGroupJoinRequest(request, true, false, "")
 			if err := c.l.PermissionStateManager.GrantGroupRole(request.GroupCode, request.InvitorUin, permission.GroupAdmin); err != nil {
@@ -1013,7 +1532,9 @@ func (c *LspPrivateCommand) GroupRequestCommand() {
 				log.Errorf("DeleteBlockList error %v", err)
 			}
 			log.Info("接受加群请求成功")
-			c.textReply(fmt.Sprintf("成功 - 已接受 %v(%v) 邀请加群 %v(%v)", request.InvitorNick, request.InvitorUin, request.GroupName, request.GroupCode))
+			c.templateReply("reply.group_request.accepted", map[string]interface{}{
+				"request": groupRequestData(request),
+			})
 		}
 		if err := c.l.LspStateManager.DeleteGroupInvitedRequest(request.RequestId); err != nil {
 			log.Errorf("DeleteGroupInvitedRequest error %v", err)
@@ -1041,7 +1562,7 @@ func (c *LspPrivateCommand) FriendRequestCommand() {
 
 	_, output := c.parseCommandSyntax(&friendRequestCmd, c.CommandName(), kong.Description("处理好友请求"), kong.UsageOnError())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -1057,20 +1578,25 @@ func (c *LspPrivateCommand) FriendRequestCommand() {
 		requests, err := c.l.LspStateManager.ListNewFriendRequest()
 		if err != nil {
 			log.Errorf("ListNewFriendRequest error - %v", err)
-			c.textReply(fmt.Sprintf("失败 - %v", err))
+			c.templateReply("reply.failed", map[string]interface{}{
+				"error": err,
+			})
 			return
 		}
 
 		if len(requests) == 0 {
 			log.Infof("没有查询到好友申请")
-			c.textReply("没有查询到好友申请")
+			c.templateReply("reply.friend_request.empty", nil)
 			return
 		}
 
 		if friendRequestCmd.Reject {
 			if !friendRequestCmd.All {
 				log.Info("拒绝全部好友申请需要确认")
-				c.textReply(fmt.Sprintf("您似乎想要拒绝全部%v个好友申请，如果确定想这样做，请输入：\n%v --all %v", len(requests), c.GetCmd(), strings.Join(c.GetArgs(), " ")))
+				c.templateReply("reply.friend_request.confirm_reject_all", map[string]interface{}{
+					"count":   len(requests),
+					"command": c.GetCmd() + " --all " + strings.Join(c.GetArgs(), " "),
+				})
 				return
 			}
 			log.Info("确认拒绝全部好友申请")
@@ -1082,7 +1608,9 @@ func (c *LspPrivateCommand) FriendRequestCommand() {
 				}
 			}
 			log.Infof("已拒绝%v个好友申请", len(requests))
-			c.textReply(fmt.Sprintf("成功 - 已拒绝全部%v个好友申请", len(requests)))
+			c.templateReply("reply.friend_request.rejected_all", map[string]interface{}{
+				"count": len(requests),
+			})
 			return
 		}
 
@@ -1096,26 +1624,32 @@ func (c *LspPrivateCommand) FriendRequestCommand() {
 				}
 			}
 			log.Infof("已接受%v个好友申请", len(requests))
-			c.textReply(fmt.Sprintf("成功 - 已接受全部%v个好友申请", len(requests)))
+			c.templateReply("reply.friend_request.accepted_all", map[string]interface{}{
+				"count": len(requests),
+			})
 			return
 		}
 
 		// 展示好友申请
-		var sb strings.Builder
+		var data []map[string]interface{}
 		for _, req := range requests {
-			sb.WriteString(fmt.Sprintf("ID:%v %v(%v)申请好友\n", req.RequestId, req.RequesterNick, req.RequesterUin))
+			data = append(data, friendRequestData(req))
 		}
 		log.Infof("查询到%v个好友申请", len(requests))
-		c.textReply(sb.String())
+		c.templateReply("reply.friend_request.list", map[string]interface{}{
+			"requests": data,
+		})
 	} else {
 		request, err := c.l.LspStateManager.GetNewFriendRequest(friendRequestCmd.RequestId)
 		if localdb.IsNotFound(err) {
 			log.Errorf("处理好友申请失败 - 未找到该好友申请")
-			c.textReply(fmt.Sprintf("失败 - 未找到该好友申请【%v】", friendRequestCmd.RequestId))
+			c.templateReply("reply.friend_request.not_found", map[string]interface{}{
+				"id": friendRequestCmd.RequestId,
+			})
 			return
 		} else if err != nil {
 			log.Errorf("GetNewFriendRequest error %v", err)
-			c.textReply(fmt.Sprintf("失败 - 内部错误"))
+			c.templateReply("reply.internal_error", nil)
 			return
 		}
 
@@ -1127,11 +1661,15 @@ func (c *LspPrivateCommand) FriendRequestCommand() {
 		if friendRequestCmd.Reject {
 			c.bot.SolveFriendRequest(request, false)
 			log.Info("拒绝好友申请")
-			c.textReply(fmt.Sprintf("成功 - 已拒绝 %v(%v) 的好友申请", request.RequesterNick, request.RequesterUin))
+			c.templateReply("reply.friend_request.rejected", map[string]interface{}{
+				"request": friendRequestData(request),
+			})
 		} else {
 			c.bot.SolveFriendRequest(request, true)
 			log.Info("接受好友申请")
-			c.textReply(fmt.Sprintf("成功 - 已接受 %v(%v) 的好友申请", request.RequesterNick, request.RequesterUin))
+			c.templateReply("reply.friend_request.accepted", map[string]interface{}{
+				"request": friendRequestData(request),
+			})
 		}
 		if err := c.l.LspStateManager.DeleteNewFriendRequest(request.RequestId); err != nil {
 			log.Errorf("DeleteNewFriendRequest error %v", err)
@@ -1150,7 +1688,7 @@ func (c *LspPrivateCommand) AdminCommand() {
 
 	_, output := c.parseCommandSyntax(&adminCmd, c.CommandName(), kong.Description("查看当前Admin权限"), kong.UsageOnError())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -1173,54 +1711,44 @@ func (c *LspPrivateCommand) AdminCommand() {
 		}
 	}
 
-	var m = mmsg.NewMSG()
+	var admins []map[string]interface{}
 
 	if adminCmd.Group == 0 {
 		ids := c.l.PermissionStateManager.ListAdmin()
-		if len(ids) == 0 {
-			c.textReply("未查询到Admin，如果bot刚刚启动，请稍后重试。")
-			return
-		}
-		m.Textf("当前Admin：")
-		var name string
 		for _, id := range ids {
-			fi := c.bot.FindFriend(id)
-			if fi != nil {
+			var name string
+			if fi := c.bot.FindFriend(id); fi != nil {
 				name = fi.Nickname
-			} else {
-				name = "未知"
 			}
-			m.Textf("\n%v %v", id, name)
+			admins = append(admins, map[string]interface{}{
+				"uin":  id,
+				"name": name,
+			})
 		}
+		c.templateReply("reply.admin.list", map[string]interface{}{
+			"admins": admins,
+		})
 	} else {
 		gi := c.bot.FindGroup(adminCmd.Group)
-		if gi == nil {
-			m.Textf("注意：没有找到这个群\n")
-		}
 		ids := c.l.PermissionStateManager.ListGroupAdmin(adminCmd.Group)
-		if len(ids) == 0 {
-			m.Text("未查询到GroupAdmin，如果bot刚刚启动，请稍后重试。")
-		} else {
-			m.Textf("当前GroupAdmin：")
-			if gi == nil {
-				for _, id := range ids {
-					m.Textf("\n%v", id)
-				}
-			} else {
-				var name string
-				for _, id := range ids {
-					fi := gi.FindMember(id)
-					if fi != nil {
-						name = fi.Nickname
-					} else {
-						name = "未知"
-					}
-					m.Textf("\n%v %v", id, name)
+		for _, id := range ids {
+			var name string
+			if gi != nil {
+				if fi := gi.FindMember(id); fi != nil {
+					name = fi.Nickname
 				}
 			}
+			admins = append(admins, map[string]interface{}{
+				"uin":  id,
+				"name": name,
+			})
 		}
+		c.templateReply("reply.admin.group_list", map[string]interface{}{
+			"group":       adminCmd.Group,
+			"group_found": gi != nil,
+			"admins":      admins,
+		})
 	}
-	c.send(m)
 }
 
 func (c *LspPrivateCommand) SilenceCommand() {
@@ -1242,7 +1770,7 @@ func (c *LspPrivateCommand) SilenceCommand() {
 
 	_, output := c.parseCommandSyntax(&silenceCmd, c.CommandName(), kong.Description("设置沉默模式"), kong.UsageOnError())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -1251,6 +1779,60 @@ func (c *LspPrivateCommand) SilenceCommand() {
 	ISilenceCmd(c.NewMessageContext(log), silenceCmd.Group, silenceCmd.Delete)
 }
 
+func (c *LspPrivateCommand) LocaleCommand() {
+	log := c.DefaultLoggerWithCommand(c.CommandName())
+	log.Infof("run %v command", c.CommandName())
+	defer func() { log.Infof("%v command end", c.CommandName()) }()
+
+	var localeCmd struct {
+		Group  int64  `optional:"" short:"g" help:"要操作的QQ群号码"`
+		Locale string `arg:"" optional:"" help:"要设置的语言，支持 zh-CN / en，不指定时查看当前语言"`
+	}
+
+	_, output := c.parseCommandSyntax(&localeCmd, c.CommandName(), kong.Description("设置群使用的语言"), kong.UsageOnError())
+	if output != "" {
+		c.usageReply(output)
+	}
+	if c.exit {
+		return
+	}
+
+	groupCode := localeCmd.Group
+	if err := c.checkGroupCode(groupCode); err != nil {
+		c.errorReply(err)
+		return
+	}
+	log = log.WithFields(localutils.GroupLogFields(groupCode))
+	ILocaleCmd(c.NewMessageContext(log), groupCode, localeCmd.Locale)
+}
+
+func (c *LspPrivateCommand) CopyCommand() {
+	log := c.DefaultLoggerWithCommand(c.CommandName())
+	log.Infof("run %v command", c.CommandName())
+	defer func() { log.Infof("%v command end", c.CommandName()) }()
+
+	var copyCmd struct {
+		Group int64 `optional:"" short:"g" help:"要操作的QQ群号码"`
+		From  int64 `arg:"" help:"复制订阅的来源QQ群号码"`
+	}
+
+	_, output := c.parseCommandSyntax(&copyCmd, c.CommandName(), kong.Description("把其他群的所有订阅及配置复制到指定的群，需要两个群的管理员权限"), kong.UsageOnError())
+	if output != "" {
+		c.usageReply(output)
+	}
+	if c.exit {
+		return
+	}
+
+	groupCode := copyCmd.Group
+	if err := c.checkGroupCode(groupCode); err != nil {
+		c.errorReply(err)
+		return
+	}
+	log = log.WithFields(localutils.GroupLogFields(groupCode)).WithField("from", copyCmd.From)
+	ICopyConcern(c.NewMessageContext(log), copyCmd.From, groupCode)
+}
+
 func (c *LspPrivateCommand) PingCommand() {
 	log := c.DefaultLoggerWithCommand(c.CommandName())
 	log.Infof("run %v command", c.CommandName())
@@ -1258,7 +1840,7 @@ func (c *LspPrivateCommand) PingCommand() {
 
 	_, output := c.parseCommandSyntax(&struct{}{}, c.CommandName(), kong.Description("返回pong"), kong.UsageOnError())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -1273,7 +1855,7 @@ func (c *LspPrivateCommand) HelpCommand() {
 
 	_, output := c.parseCommandSyntax(&struct{}{}, c.CommandName(), kong.Description("显示帮助信息"))
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -1288,7 +1870,7 @@ func (c *LspPrivateCommand) SysinfoCommand() {
 
 	_, output := c.parseCommandSyntax(&struct{}{}, c.CommandName())
 	if output != "" {
-		c.textReply(output)
+		c.usageReply(output)
 	}
 	if c.exit {
 		return
@@ -1299,25 +1881,58 @@ func (c *LspPrivateCommand) SysinfoCommand() {
 		return
 	}
 
-	m := mmsg.NewMSG()
-	m.Textf("当前好友数：%v\n", len(c.bot.GetFriendList()))
-	m.Textf("当前群组数：%v\n", len(c.bot.GetGroupList()))
-	for index, cm := range concern.ListConcern() {
+	var concerns []map[string]interface{}
+	for _, cm := range concern.ListConcern() {
 		_, ids, ctypes, err := cm.GetStateManager().ListConcernState(
-			func(groupCode int64, id interface{}, p concern_type.Type) bool {
+			func(target mmsg.Target, id interface{}, p concern_type.Type) bool {
 				return true
 			})
-		if index > 0 {
-			m.Text("\n")
+		if err == nil {
+			ids, ctypes, err = cm.GetStateManager().GroupTypeById(ids, ctypes)
 		}
-		ids, ctypes, err = cm.GetStateManager().GroupTypeById(ids, ctypes)
-		if err != nil {
-			m.Textf("当前%v订阅数：获取失败", cm.Site())
-		} else {
-			m.Textf("当前%v订阅数：%v", cm.Site(), len(ids))
+		concerns = append(concerns, map[string]interface{}{
+			"site":   cm.Site(),
+			"count":  len(ids),
+			"failed": err != nil,
+		})
+	}
+	stats := c.l.notifyScheduler.Stats()
+	var queued []map[string]interface{}
+	for p, n := range stats.Queued {
+		queued = append(queued, map[string]interface{}{
+			"priority": scheduler.Priority(p).String(),
+			"count":    n,
+		})
+	}
+	var proxies []map[string]interface{}
+	var available int
+	for _, status := range proxy_pool.Status() {
+		if status.Available {
+			available++
 		}
+		proxies = append(proxies, map[string]interface{}{
+			"proxy":         status.Proxy,
+			"prefer":        status.Prefer,
+			"available":     status.Available,
+			"evicted_until": status.EvictedUntil,
+			"weight":        status.Weight,
+			"failures":      status.Failures,
+			"checked":       !status.LastCheck.IsZero(),
+			"check_ok":      status.LastCheckOK,
+			"latency":       status.Latency.Round(time.Millisecond),
+		})
 	}
-	c.send(m)
+	c.templateSend("reply.sysinfo", map[string]interface{}{
+		"friend_count":    len(c.bot.GetFriendList()),
+		"group_count":     len(c.bot.GetGroupList()),
+		"concerns":        concerns,
+		"queue_total":     stats.Total(),
+		"queued":          queued,
+		"queue_keys":      stats.Keys,
+		"running":         stats.Running,
+		"proxies":         proxies,
+		"proxy_available": available,
+	})
 }
 
 func (c *LspPrivateCommand) DebugCheck() bool {
@@ -1341,19 +1956,19 @@ func (c *LspPrivateCommand) DefaultLoggerWithCommand(command string) *logrus.Ent
 }
 
 func (c *LspPrivateCommand) noPermission() *message.PrivateMessage {
-	return c.textReply("权限不够")
+	return c.templateReply("reply.no_permission", nil)
 }
 
 func (c *LspPrivateCommand) globalDisabledReply() *message.PrivateMessage {
-	return c.textReply("无法操作该命令，该命令已被管理员禁用")
+	return c.templateReply("reply.global_disabled", nil)
 }
 
 func (c *LspPrivateCommand) disabledReply() *message.PrivateMessage {
-	return c.textSend("该命令已被设置为disable，请设置enable后重试")
+	return c.templateSend("reply.disabled", nil)
 }
 
 func (c *LspPrivateCommand) notImplReply() *message.PrivateMessage {
-	return c.textReply("暂未实现，你可以催作者GKD")
+	return c.templateReply("reply.not_implemented", nil)
 }
 
 func (c *LspPrivateCommand) textSend(text string) *message.PrivateMessage {
@@ -1369,6 +1984,33 @@ func (c *LspPrivateCommand) textReplyF(format string, args ...interface{}) *mess
 	return c.send(mmsg.NewTextf(format, args...))
 }
 
+// usageReply 回复命令的帮助或者参数错误信息
+func (c *LspPrivateCommand) usageReply(output string) *message.PrivateMessage {
+	return c.templateReply("reply.usage", map[string]interface{}{
+		"command": c.CommandName(),
+		"usage":   output,
+	})
+}
+
+// templateReply 使用模板 name 生成消息并回复，模板执行失败时返回nil
+func (c *LspPrivateCommand) templateReply(name string, data map[string]interface{}) *message.PrivateMessage {
+	m := c.templateMsg(name, data)
+	if m == nil {
+		return nil
+	}
+	return c.send(m)
+}
+
+// templateSend 使用模板 name 生成消息并发送，模板执行失败时返回nil
+func (c *LspPrivateCommand) templateSend(name string, data map[string]interface{}) *message.PrivateMessage {
+	return c.templateReply(name, data)
+}
+
+// errorReply 回复错误，带有回复模板的错误使用它的模板
+func (c *LspPrivateCommand) errorReply(err error) *message.PrivateMessage {
+	return c.templateReply(errorTemplate(err))
+}
+
 func (c *LspPrivateCommand) send(msg *mmsg.MSG) *message.PrivateMessage {
 	return c.l.PM(c.l.SendMsg(msg, mmsg.NewPrivateTarget(c.uin())))[0]
 }
@@ -1383,6 +2025,7 @@ func (c *LspPrivateCommand) commonTemplateData() map[string]interface{} {
 		"member_code": c.sender().Uin,
 		"member_name": c.sender().DisplayName(),
 		"command":     CommandMaps,
+		"locale":      c.locale,
 	}
 }
 
@@ -1391,11 +2034,12 @@ func (c *LspPrivateCommand) templateMsg(name string, data map[string]interface{}
 	for k, v := range data {
 		commonData[k] = v
 	}
+	localizeTemplateData(c.locale, commonData)
 	commonData["template_name"] = name
-	m, err := template.LoadAndExec(name, commonData)
+	m, err := template.LoadAndExec(template.LocaleName(c.locale, name), commonData)
 	if err != nil {
 		logger.Errorf("LoadAndExec error %v", err)
-		c.textReply(fmt.Sprintf("错误 - %v", err))
+		c.textReply(i18n.Sprintf(c.locale, "错误 - %v", err))
 		return nil
 	}
 	return m
@@ -1415,6 +2059,7 @@ func (c *LspPrivateCommand) name() string {
 func (c *LspPrivateCommand) NewMessageContext(log *logrus.Entry) *MessageContext {
 	ctx := NewMessageContext()
 	ctx.Target = mmsg.NewPrivateTarget(c.uin())
+	ctx.Locale = c.locale
 	ctx.Lsp = c.l
 	ctx.Log = log
 	ctx.SendFunc = func(m *mmsg.MSG) interface{} {
@@ -1433,12 +2078,26 @@ func (c *LspPrivateCommand) NewMessageContext(log *logrus.Entry) *MessageContext
 		return c.globalDisabledReply()
 	}
 	ctx.Sender = c.sender()
+	ctx.TemplateMsgFunc = c.templateMsg
 	return ctx
 }
 
+// parseTarget 指定了群号码时操作群订阅，否则操作自己的私聊订阅
+func (c *LspPrivateCommand) parseTarget(groupCode int64) (mmsg.Target, error) {
+	if groupCode == 0 {
+		return mmsg.NewPrivateTarget(c.uin()), nil
+	}
+	if err := c.checkGroupCode(groupCode); err != nil {
+		return nil, err
+	}
+	return mmsg.NewGroupTarget(groupCode), nil
+}
+
 func (c *LspPrivateCommand) checkGroupCode(groupCode int64) error {
 	if groupCode == 0 {
-		return fmt.Errorf("没有指定QQ群号码，请使用-g参数指定QQ群，例如对QQ群123456进行操作：%v %v %v", c.GetCmd(), "-g 123456", strings.Join(c.GetArgs(), " "))
+		return newReplyError("reply.group.missing_group", map[string]interface{}{
+			"example": fmt.Sprintf("%v %v %v", c.GetCmd(), "-g 123456", strings.Join(c.GetArgs(), " ")),
+		})
 	}
 	group := c.bot.FindGroup(groupCode)
 	if !c.l.PermissionStateManager.CheckRole(c.uin(), permission.Admin) {
@@ -1446,16 +2105,41 @@ func (c *LspPrivateCommand) checkGroupCode(groupCode int64) error {
 			return fmt.Errorf("")
 		}
 		if group == nil {
-			return fmt.Errorf("没有找到QQ群<%v>，请确认bot是否在群内", groupCode)
+			return newReplyError("reply.group.not_found", map[string]interface{}{
+				"group": groupCode,
+			})
 		}
 		member := group.FindMember(c.uin())
 		if member == nil {
-			return fmt.Errorf("没有在QQ群<%v>内找到您，请确认您是否在群内", groupCode)
+			return newReplyError("reply.group.member_not_found", map[string]interface{}{
+				"group": groupCode,
+			})
 		}
 	} else {
 		if group == nil {
-			c.textReplyF("请注意未找到QQ群<%v>，如果bot刚刚启动，有可能是尚未刷新完毕，将继续查询数据", groupCode)
+			c.templateReply("reply.group.not_found_warning", map[string]interface{}{
+				"group": groupCode,
+			})
 		}
 	}
 	return nil
 }
+
+func groupRequestData(req *client.GroupInvitedRequest) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           req.RequestId,
+		"invitor_uin":  req.InvitorUin,
+		"invitor_nick": req.InvitorNick,
+		"group_code":   req.GroupCode,
+		"group_name":   req.GroupName,
+	}
+}
+
+func friendRequestData(req *client.NewFriendRequest) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             req.RequestId,
+		"requester_uin":  req.RequesterUin,
+		"requester_nick": req.RequesterNick,
+		"message":        req.Message,
+	}
+}
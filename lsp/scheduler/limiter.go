@@ -0,0 +1,62 @@
+package scheduler
+
+import "time"
+
+// rateLimiter 滑动窗口限流，算法与 client.RateLimiter 相同
+// 区别是检查和计数分开，这样可以在全局和单个目标都允许时才计数
+// 不是并发安全的，由 Scheduler 加锁使用
+type rateLimiter struct {
+	size  time.Duration
+	limit int64
+
+	currStart time.Time
+	curr      int64
+	prev      int64
+}
+
+// newRateLimiter limit不大于0时不限制，返回nil
+func newRateLimiter(size time.Duration, limit int64) *rateLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		size:  size,
+		limit: limit,
+	}
+}
+
+// allow 返回在now时是否还可以发送一条
+func (rl *rateLimiter) allow(now time.Time) bool {
+	if rl == nil {
+		return true
+	}
+	rl.advance(now)
+	elapsed := now.Sub(rl.currStart)
+	weight := float64(rl.size-elapsed) / float64(rl.size)
+	count := int64(weight*float64(rl.prev)) + rl.curr
+	return count+1 <= rl.limit
+}
+
+// add 在now时计数一次
+func (rl *rateLimiter) add(now time.Time) {
+	if rl == nil {
+		return
+	}
+	rl.advance(now)
+	rl.curr++
+}
+
+func (rl *rateLimiter) advance(now time.Time) {
+	newCurrStart := now.Truncate(rl.size)
+
+	diffSize := newCurrStart.Sub(rl.currStart) / rl.size
+	if diffSize >= 1 {
+		var newPrevCount int64
+		if diffSize == 1 {
+			newPrevCount = rl.curr
+		}
+		rl.prev = newPrevCount
+		rl.curr = 0
+		rl.currStart = newCurrStart
+	}
+}
@@ -0,0 +1,302 @@
+package scheduler
+
+import (
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// limitWindow 限流的时间窗口，限流配置为每个窗口内最多发送的数量
+const limitWindow = time.Minute
+
+// retryInterval 被限流时重新检查的间隔
+const retryInterval = time.Second
+
+// Priority 推送的优先级，数值越小越先发送
+type Priority int
+
+const (
+	// PriorityHigh 例如开播推送
+	PriorityHigh Priority = iota
+	// PriorityNormal 例如动态、下播推送
+	PriorityNormal
+	// PriorityLow 例如直播间标题修改
+	PriorityLow
+
+	priorityCount
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityHigh:
+		return "高"
+	case PriorityNormal:
+		return "普通"
+	case PriorityLow:
+		return "低"
+	default:
+		return "未知"
+	}
+}
+
+// Task 一条等待发送的推送
+type Task struct {
+	// Key 公平调度和单独限流的单位，例如推送目标
+	Key      string
+	Priority Priority
+	Run      func()
+}
+
+// fairQueue 同一优先级的队列，在各个Key之间轮流取出
+type fairQueue struct {
+	keys  []string
+	tasks map[string][]*Task
+}
+
+func newFairQueue() *fairQueue {
+	return &fairQueue{tasks: make(map[string][]*Task)}
+}
+
+func (q *fairQueue) push(task *Task) {
+	if len(q.tasks[task.Key]) == 0 {
+		q.keys = append(q.keys, task.Key)
+	}
+	q.tasks[task.Key] = append(q.tasks[task.Key], task)
+}
+
+// pop 从排在最前面的Key开始，找到第一个允许发送的Key并取出它最早的一条，之后这个Key排到最后
+// 被限流的Key保持原来的位置，限流结束后优先发送
+func (q *fairQueue) pop(allow func(key string) bool) *Task {
+	for i, key := range q.keys {
+		if !allow(key) {
+			continue
+		}
+		tasks := q.tasks[key]
+		q.keys = append(q.keys[:i], q.keys[i+1:]...)
+		if len(tasks) > 1 {
+			q.tasks[key] = tasks[1:]
+			q.keys = append(q.keys, key)
+		} else {
+			delete(q.tasks, key)
+		}
+		return tasks[0]
+	}
+	return nil
+}
+
+// Stats 调度器的当前状态
+type Stats struct {
+	// Queued 每个优先级正在排队的数量，按优先级从高到低
+	Queued []int
+	// Keys 有推送正在排队的Key数量
+	Keys int
+	// Running 正在发送的数量
+	Running int
+}
+
+// Total 正在排队的总数
+func (s *Stats) Total() int {
+	var total int
+	for _, n := range s.Queued {
+		total += n
+	}
+	return total
+}
+
+// Scheduler 推送调度器
+// 高优先级的推送总是先发送，同一优先级内在各个Key之间轮流发送，防止订阅很多的群占满发送队列
+// 同时支持全局和单个Key的限流，被限流时推送会继续排队而不是丢弃
+type Scheduler struct {
+	parallel   int
+	groupLimit int64
+
+	mu      sync.Mutex
+	queues  [priorityCount]*fairQueue
+	queued  int
+	running int
+	global  *rateLimiter
+	groups  map[string]*rateLimiter
+
+	wake   chan struct{}
+	stop   chan struct{}
+	wg     sync.WaitGroup
+	taskWg sync.WaitGroup
+	now    func() time.Time
+}
+
+// New 创建调度器，parallel为同时发送的数量，globalLimit和groupLimit为每分钟全局和单个Key最多发送的数量，不大于0时不限制
+func New(parallel int, globalLimit, groupLimit int64) *Scheduler {
+	if parallel <= 0 {
+		parallel = 1
+	}
+	s := &Scheduler{
+		parallel:   parallel,
+		groupLimit: groupLimit,
+		global:     newRateLimiter(limitWindow, globalLimit),
+		groups:     make(map[string]*rateLimiter),
+		wake:       make(chan struct{}, 1),
+		now:        time.Now,
+	}
+	for i := range s.queues {
+		s.queues[i] = newFairQueue()
+	}
+	return s
+}
+
+// Submit 添加一条推送，在 Start 之前添加的推送会在启动后发送
+func (s *Scheduler) Submit(key string, priority Priority, run func()) {
+	if priority < 0 || priority >= priorityCount {
+		priority = PriorityNormal
+	}
+	s.mu.Lock()
+	s.queues[priority].push(&Task{Key: key, Priority: priority, Run: run})
+	s.queued++
+	s.mu.Unlock()
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Stats 返回当前的排队情况
+func (s *Scheduler) Stats() *Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result = &Stats{Running: s.running}
+	var keys = make(map[string]bool)
+	for _, q := range s.queues {
+		var n int
+		for key, tasks := range q.tasks {
+			n += len(tasks)
+			keys[key] = true
+		}
+		result.Queued = append(result.Queued, n)
+	}
+	result.Keys = len(keys)
+	return result
+}
+
+func (s *Scheduler) groupLimiter(key string) *rateLimiter {
+	if s.groupLimit <= 0 {
+		return nil
+	}
+	rl, ok := s.groups[key]
+	if !ok {
+		rl = newRateLimiter(limitWindow, s.groupLimit)
+		s.groups[key] = rl
+	}
+	return rl
+}
+
+// next 取出下一条可以发送的推送，没有时返回需要等待的时间，为0表示等待新的推送
+func (s *Scheduler) next() (*Task, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.queued == 0 {
+		return nil, 0
+	}
+	now := s.now()
+	if !s.global.allow(now) {
+		return nil, retryInterval
+	}
+	for _, q := range s.queues {
+		task := q.pop(func(key string) bool {
+			return s.groupLimiter(key).allow(now)
+		})
+		if task == nil {
+			continue
+		}
+		s.global.add(now)
+		s.groupLimiter(task.Key).add(now)
+		s.queued--
+		s.running++
+		return task, 0
+	}
+	return nil, retryInterval
+}
+
+// wait 等待新的推送或者限流结束，调度器停止时返回false
+func (s *Scheduler) wait(d time.Duration, stop chan struct{}) bool {
+	var timeout <-chan time.Time
+	if d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-s.wake:
+	case <-timeout:
+	case <-stop:
+		return false
+	}
+	return true
+}
+
+func (s *Scheduler) run(task *Task, sem chan struct{}) {
+	defer s.taskWg.Done()
+	defer func() {
+		if e := recover(); e != nil {
+			logger.WithField("stack", string(debug.Stack())).
+				WithField("Key", task.Key).Errorf("task panic recovered: %v", e)
+		}
+		s.mu.Lock()
+		s.running--
+		s.mu.Unlock()
+		<-sem
+	}()
+	task.Run()
+}
+
+func (s *Scheduler) loop(stop chan struct{}) {
+	defer s.wg.Done()
+	sem := make(chan struct{}, s.parallel)
+	for {
+		select {
+		case sem <- struct{}{}:
+		case <-stop:
+			return
+		}
+		var task *Task
+		for task == nil {
+			var d time.Duration
+			if task, d = s.next(); task == nil && !s.wait(d, stop) {
+				return
+			}
+		}
+		s.taskWg.Add(1)
+		go s.run(task, sem)
+	}
+}
+
+// Start 开始发送推送
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+	go s.loop(s.stop)
+}
+
+// Stop 停止发送并等待正在发送的推送完成，返回丢弃的排队中的推送数量
+func (s *Scheduler) Stop() int {
+	s.mu.Lock()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	s.taskWg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dropped := s.queued
+	for i := range s.queues {
+		s.queues[i] = newFairQueue()
+	}
+	s.queued = 0
+	return dropped
+}
@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func drain(s *Scheduler) []string {
+	var result []string
+	for {
+		task, _ := s.next()
+		if task == nil {
+			return result
+		}
+		task.Run()
+		s.running--
+	}
+}
+
+func TestScheduler_Fair(t *testing.T) {
+	s := New(1, 0, 0)
+	var order []string
+	submit := func(key string, priority Priority, name string) {
+		s.Submit(key, priority, func() {
+			order = append(order, name)
+		})
+	}
+	for _, name := range []string{"a1", "a2", "a3"} {
+		submit("a", PriorityNormal, name)
+	}
+	submit("b", PriorityNormal, "b1")
+	submit("c", PriorityLow, "c1")
+	submit("b", PriorityHigh, "b2")
+	submit("c", PriorityNormal, "c2")
+
+	stats := s.Stats()
+	assert.Equal(t, []int{1, 5, 1}, stats.Queued)
+	assert.Equal(t, 7, stats.Total())
+	assert.Equal(t, 3, stats.Keys)
+
+	drain(s)
+	assert.Equal(t, []string{"b2", "a1", "b1", "c2", "a2", "a3", "c1"}, order)
+	assert.Zero(t, s.Stats().Total())
+}
+
+func TestScheduler_RateLimit(t *testing.T) {
+	var now = time.Date(2022, 1, 1, 0, 0, 0, 0, time.Local)
+	s := New(1, 3, 2)
+	s.now = func() time.Time {
+		return now
+	}
+	var order []string
+	for _, key := range []string{"a", "a", "a", "b", "b"} {
+		key := key
+		s.Submit(key, PriorityNormal, func() {
+			order = append(order, key)
+		})
+	}
+	drain(s)
+	// 全局限制为3，单个key限制为2
+	assert.Equal(t, []string{"a", "b", "a"}, order)
+
+	task, wait := s.next()
+	assert.Nil(t, task)
+	assert.Equal(t, retryInterval, wait)
+
+	// 两个窗口之后限流结束
+	now = now.Add(limitWindow * 2)
+	drain(s)
+	assert.Equal(t, []string{"a", "b", "a", "b", "a"}, order)
+}
+
+func TestScheduler_StartStop(t *testing.T) {
+	s := New(2, 0, 0)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var count int
+	wg.Add(11)
+	for i := 0; i < 10; i++ {
+		s.Submit("a", PriorityNormal, func() {
+			defer wg.Done()
+			mu.Lock()
+			count++
+			mu.Unlock()
+		})
+	}
+	s.Submit("b", PriorityNormal, func() {
+		defer wg.Done()
+		panic("test")
+	})
+	s.Start()
+	s.Start()
+	wg.Wait()
+	assert.Zero(t, s.Stop())
+	assert.Equal(t, 10, count)
+	assert.Zero(t, s.Stats().Running)
+
+	s.Submit("a", PriorityNormal, func() {})
+	assert.Equal(t, 1, s.Stop())
+}
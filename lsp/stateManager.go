@@ -17,6 +17,10 @@ func (KeySet) GroupMessageImageKey(keys ...interface{}) string {
 	return localdb.GroupMessageImageKey(keys...)
 }
 
+func (KeySet) GroupMessageFileKey(keys ...interface{}) string {
+	return localdb.GroupMessageFileKey(keys...)
+}
+
 func (KeySet) GroupMuteKey(keys ...interface{}) string {
 	return localdb.GroupMuteKey(keys...)
 }
@@ -70,6 +74,25 @@ func (s *StateManager) GetMessageImageUrl(groupCode int64, messageID int32) []st
 	return result
 }
 
+// SaveMessageFile 保存群消息中的文件，用于回复文件消息的命令读取文件
+func (s *StateManager) SaveMessageFile(groupCode int64, messageID int32, msgs []message.IMessageElement) error {
+	for _, e := range msgs {
+		if fe, ok := e.(*message.GroupFileElement); ok {
+			return s.SetJson(s.GroupMessageFileKey(groupCode, messageID), fe, localdb.SetExpireOpt(time.Hour*8))
+		}
+	}
+	return nil
+}
+
+// GetMessageFile 查询 SaveMessageFile 保存的文件
+func (s *StateManager) GetMessageFile(groupCode int64, messageID int32) (*message.GroupFileElement, error) {
+	var fe = new(message.GroupFileElement)
+	if err := s.GetJson(s.GroupMessageFileKey(groupCode, messageID), fe); err != nil {
+		return nil, err
+	}
+	return fe, nil
+}
+
 func (s *StateManager) Muted(groupCode int64, uin int64, t int32) error {
 	return s.RWCoverTx(func(tx *buntdb.Tx) error {
 		var err error
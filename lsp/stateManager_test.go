@@ -89,6 +89,36 @@ func TestStateManager_GetMessageImageUrl(t *testing.T) {
 	assert.Len(t, sm.GetMessageImageUrl(test.G1, test.MessageID1), 3)
 }
 
+func TestStateManager_GetMessageFile(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	sm := newStateManager(t)
+	assert.NotNil(t, sm)
+
+	assert.Nil(t, sm.SaveMessageFile(test.G1, test.MessageID1, []message.IMessageElement{
+		message.NewText("text"),
+	}))
+	_, err := sm.GetMessageFile(test.G1, test.MessageID1)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, sm.SaveMessageFile(test.G1, test.MessageID1, []message.IMessageElement{
+		&message.GroupFileElement{
+			Name: "list.txt",
+			Size: 10,
+			Id:   "/file1",
+			Url:  "url1",
+		},
+	}))
+	fe, err := sm.GetMessageFile(test.G1, test.MessageID1)
+	assert.Nil(t, err)
+	assert.Equal(t, "/file1", fe.Id)
+	assert.Equal(t, "url1", fe.Url)
+	assert.EqualValues(t, 10, fe.Size)
+	_, err = sm.GetMessageFile(test.G2, test.MessageID1)
+	assert.NotNil(t, err)
+}
+
 func TestStateManager_GetCurrentMode(t *testing.T) {
 	test.InitBuntdb(t)
 	defer test.CloseBuntdb(t)
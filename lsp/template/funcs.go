@@ -79,6 +79,8 @@ func builtins() FuncMap {
 		"file":               file,
 		"remoteDownloadFile": remoteDownloadFile,
 		"getMsg":             getMsg,
+		"searchMsg":          searchMsg,
+		"lastMsg":            lastMsg,
 		"getFileUrl":         getFileUrl,
 		"reCall":             reCall,
 
@@ -94,6 +96,7 @@ func builtins() FuncMap {
 		"getTimeStamp":  getTimeStamp,
 		"getTime":       getTime,
 		"getUnixTime":   getUnixTime,
+		"formatNumber":  formatNumber,
 		"cooldown":      cooldown,
 		"setCooldown":   setCooldown,
 		"openFile":      openFile,
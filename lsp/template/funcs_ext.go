@@ -19,10 +19,13 @@ import (
 	"github.com/Mrs4s/MiraiGo/message"
 	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/cfg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/interfaces"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/msgarchive"
 	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/shopspring/decimal"
+	"github.com/spf13/cast"
 )
 
 var funcsExt = make(FuncMap)
@@ -594,9 +597,9 @@ func fin() interface{} {
 	panic(errFin)
 }
 
-func getUnixTime(i int64, f string) string {
+func getUnixTime(i int64, f string, locale ...string) string {
 	t := time.Unix(i, 0)
-	return getTime(t, f)
+	return getTime(t, f, locale...)
 }
 
 func getTimeStamp(t string) int64 {
@@ -606,7 +609,8 @@ func getTimeStamp(t string) int64 {
 	return ret
 }
 
-func getTime(s interface{}, f string) string {
+// getTime 格式化时间，可以额外指定语言，例如 {{ getTime .time "dateonly" .locale }}
+func getTime(s interface{}, f string, locale ...string) string {
 	var t time.Time
 
 	switch v := s.(type) {
@@ -630,26 +634,29 @@ func getTime(s interface{}, f string) string {
 		panic("template: getTime with invalid s")
 	}
 
+	var l string
+	if len(locale) > 0 {
+		l = locale[0]
+	}
 	switch f {
-	case "dateonly":
-		return t.Format(time.DateOnly)
-	case "timeonly":
-		return t.Format(time.TimeOnly)
-	case "stamp":
-		return t.Format(time.Stamp)
 	case "unix":
 		return strconv.FormatInt(t.Unix(), 10)
 	case "elapsed":
-		dur := time.Since(t)
-		h := int64(dur.Hours())
-		m := int64(dur.Minutes()) % 60
-		s := int64(dur.Seconds()) % 60
-		return fmt.Sprintf("%d小时%d分%d秒", h, m, s)
+		return i18n.FormatDuration(l, time.Since(t))
 	default:
-		return t.Format(time.DateTime)
+		return t.Format(i18n.DateLayout(l, f))
 	}
 }
 
+// formatNumber 按语言格式化数字，例如 {{ formatNumber .popularity .locale }}
+func formatNumber(n interface{}, locale ...string) string {
+	var l string
+	if len(locale) > 0 {
+		l = locale[0]
+	}
+	return i18n.FormatNumber(l, toInt64(n))
+}
+
 func readLine(p string, l int64) string {
 	file, err := os.OpenFile(p, os.O_RDONLY, 0666)
 	if err != nil {
@@ -1069,6 +1076,48 @@ func getMsg(msgId int32) interface{} {
 	return ret
 }
 
+// searchMsg 搜索群消息存档，可选的参数为一个dict，支持 keyword / uin / since / until / limit
+func searchMsg(groupCode int64, opts ...map[string]interface{}) []*msgarchive.Record {
+	var query = &msgarchive.Query{GroupCode: groupCode}
+	if len(opts) > 0 && opts[0] != nil {
+		var (
+			now = time.Now()
+			opt = opts[0]
+			err error
+		)
+		query.Keyword = cast.ToString(opt["keyword"])
+		query.Uin = toInt64(opt["uin"])
+		query.Limit = toInt(opt["limit"])
+		if query.Since, err = msgarchive.ParseTime(cast.ToString(opt["since"]), now); err != nil {
+			logger.Errorf("searchMsg: %v", err)
+			return nil
+		}
+		if query.Until, err = msgarchive.ParseTime(cast.ToString(opt["until"]), now); err != nil {
+			logger.Errorf("searchMsg: %v", err)
+			return nil
+		}
+	}
+	result, err := msgarchive.Search(query)
+	if err != nil {
+		logger.Errorf("searchMsg error: %v", err)
+		return nil
+	}
+	return result
+}
+
+// lastMsg 返回存档中最近一条包含关键字的群消息，没有时返回nil
+func lastMsg(groupCode int64, keyword string) *msgarchive.Record {
+	result, err := msgarchive.Search(&msgarchive.Query{GroupCode: groupCode, Keyword: keyword, Limit: 1})
+	if err != nil {
+		logger.Errorf("lastMsg error: %v", err)
+		return nil
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result[0]
+}
+
 func loop(from, to int64) <-chan int64 {
 	ch := make(chan int64)
 	go func() {
@@ -83,6 +83,19 @@ func TestTimeFuncs(t *testing.T) {
 	ts := getTimeStamp("2021-01-01 12:00:00")
 	expected, _ := time.ParseInLocation(time.DateTime, "2021-01-01 12:00:00", time.Local)
 	assert.Equal(t, expected.Unix(), ts)
+
+	// 测试指定语言
+	s = getTime("2021-01-01 12:00:00", "dateonly", "en")
+	assert.Equal(t, "Jan 1, 2021", s)
+	s = getTime("2021-01-01 12:00:00", "", "en")
+	assert.Equal(t, "Jan 1, 2021 12:00:00", s)
+	s = getTime("2021-01-01 12:00:00", "dateonly", "zh-CN")
+	assert.Equal(t, "2021-01-01", s)
+	s = getTime(time.Now().Add(-time.Hour), "elapsed", "en")
+	assert.Regexp(t, `^1h 0m \d+s$`, s)
+
+	assert.Equal(t, "12,345", formatNumber(12345, "en"))
+	assert.Equal(t, "1.2万", formatNumber(int64(12345)))
 }
 
 func TestFileFuncs(t *testing.T) {
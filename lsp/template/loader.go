@@ -3,6 +3,7 @@ package template
 import (
 	"embed"
 	"fmt"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/fsnotify/fsnotify"
 	"os"
@@ -98,6 +99,19 @@ func LoadTemplate(name string) *Template {
 	return rootT.Lookup(name)
 }
 
+// LocaleName 返回模板 name 在语言 locale 下使用的模板名，
+// 定义了 "<locale>/<name>" 模板时使用它，否则使用原模板
+func LocaleName(locale string, name string) string {
+	if i18n.IsDefault(locale) {
+		return name
+	}
+	localeName := locale + "/" + name
+	if LoadTemplate(localeName) != nil {
+		return localeName
+	}
+	return name
+}
+
 func LoadAndExec(name string, data interface{}) (*mmsg.MSG, error) {
 	initRootT()
 	m := mmsg.NewMSG()
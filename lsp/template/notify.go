@@ -0,0 +1,58 @@
+package template
+
+import (
+	"sync"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+)
+
+var (
+	notifyLocaleLock sync.RWMutex
+	notifyLocaleFunc func(target mmsg.Target) string
+)
+
+// SetNotifyLocale 设置查询推送目标语言的函数，没有设置时推送都使用默认语言
+func SetNotifyLocale(f func(target mmsg.Target) string) {
+	notifyLocaleLock.Lock()
+	defer notifyLocaleLock.Unlock()
+	notifyLocaleFunc = f
+}
+
+// NotifyLocale 返回推送目标使用的语言
+func NotifyLocale(target mmsg.Target) string {
+	notifyLocaleLock.RLock()
+	f := notifyLocaleFunc
+	notifyLocaleLock.RUnlock()
+	if f == nil || target == nil {
+		return i18n.DefaultLocale
+	}
+	return f(target)
+}
+
+// ExecNotify 使用 locale 对应的推送模板生成消息，模板中可以通过 .locale 获取语言
+func ExecNotify(locale string, name string, data map[string]interface{}) (*mmsg.MSG, error) {
+	data["locale"] = locale
+	return LoadAndExec(LocaleName(locale, name), data)
+}
+
+// MsgCache 按语言缓存推送消息，同一条推送发送给多个目标时，每种语言只生成一次
+type MsgCache struct {
+	mu   sync.Mutex
+	msgs map[string]*mmsg.MSG
+}
+
+// Get 返回 locale 对应的消息，还没有生成时调用 gen 生成
+func (c *MsgCache) Get(locale string, gen func() *mmsg.MSG) *mmsg.MSG {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if m, found := c.msgs[locale]; found {
+		return m
+	}
+	if c.msgs == nil {
+		c.msgs = make(map[string]*mmsg.MSG)
+	}
+	m := gen()
+	c.msgs[locale] = m
+	return m
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -64,6 +65,83 @@ func TestLoadTemplate(t *testing.T) {
 	assert.Empty(t, m.ToMessage(mmsg.NewGroupTarget(test.G1)))
 }
 
+func TestCommandReplyTemplate(t *testing.T) {
+	var testCase = []struct {
+		name   string
+		data   map[string]interface{}
+		expect string
+	}{
+		{"reply.success", nil, "成功"},
+		{"reply.failed", nil, "失败"},
+		{"reply.failed", map[string]interface{}{"error": "test"}, "失败 - test"},
+		{"reply.watch.success", map[string]interface{}{"site": "bilibili", "name": "name"}, "watch成功 - bilibili用户 name"},
+		{"reply.user_info", map[string]interface{}{"site": "bilibili"}, "成功 - bilibili用户"},
+		{"reply.list.site_title", map[string]interface{}{"site": "bilibili", "part": 2}, "bilibili订阅(第2部分)："},
+		{"reply.config.at.show", map[string]interface{}{"qq": []int64{1, 2}}, "当前配置：\n1\n2"},
+		{"reply.mode.current", map[string]interface{}{"mode": "public"}, "当前模式为公开"},
+		{"reply.block.success", map[string]interface{}{"name": ""}, "成功 - 未知目标"},
+		{"reply.person.unwatched", map[string]interface{}{
+			"alias": "alias",
+			"removed": []map[string]interface{}{
+				{"site": "bilibili", "name": "name1"},
+				{"site": "douyu", "name": "name2"},
+			},
+		}, "unwatch成功 - 人物【alias】：bilibili用户 name1、douyu用户 name2"},
+	}
+	for _, tc := range testCase {
+		m, err := LoadAndExec(tc.name, tc.data)
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, tc.expect, msgstringer.MsgToString(m.Elements()), tc.name)
+	}
+}
+
+func TestLocaleTemplate(t *testing.T) {
+	assert.Equal(t, "reply.success", LocaleName("zh-CN", "reply.success"))
+	assert.Equal(t, "reply.success", LocaleName("", "reply.success"))
+	assert.Equal(t, "en/reply.success", LocaleName("en", "reply.success"))
+	assert.Equal(t, "reply.not_exist", LocaleName("en", "reply.not_exist"))
+
+	// 英文模板都要有对应的默认模板
+	for _, tmpl := range LoadTemplate("reply.success").Templates() {
+		name := tmpl.Name()
+		if !strings.HasPrefix(name, "en/") || name == "en/notify.group.live_end.summary" {
+			continue
+		}
+		assert.NotNil(t, LoadTemplate(strings.TrimPrefix(name, "en/")), name)
+	}
+
+	var testCase = []struct {
+		name   string
+		data   map[string]interface{}
+		expect string
+	}{
+		{"reply.failed", map[string]interface{}{"error": "test"}, "Failed - test"},
+		{"reply.watch.success", map[string]interface{}{"site": "bilibili", "name": "name"}, "Watched - bilibili user name"},
+		{"reply.locale.success", map[string]interface{}{"locale": "en"}, "Success - language set to en"},
+		{"reply.locale.current", map[string]interface{}{"locale": "en", "locales": []string{"zh-CN", "en"}},
+			"Current language: en\nSupported languages: zh-CN / en"},
+		{"notify.group.douyu.live_end.tmpl", map[string]interface{}{
+			"name":       "name",
+			"duration":   "1h 2m 3s",
+			"popularity": int64(12345),
+			"locale":     "en",
+		}, "Douyu-name has ended the stream\nDuration: 1h 2m 3s\nPeak popularity: 12,345"},
+		{"notify.group.bilibili.guard.tmpl", map[string]interface{}{
+			"name":      "name",
+			"user":      "user",
+			"guard_lvl": 3,
+			"num":       2,
+			"url":       "https://live.bilibili.com/1",
+		}, "user became a Captain ×2 in name's live room\nhttps://live.bilibili.com/1"},
+		{"notify.group.chaohua.news.tmpl", map[string]interface{}{"name": "name"}, "weibo super topic-name has a new post:\n"},
+	}
+	for _, tc := range testCase {
+		m, err := LoadAndExec(LocaleName("en", tc.name), tc.data)
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, tc.expect, msgstringer.MsgToString(m.Elements()), tc.name)
+	}
+}
+
 func TestTemplateOption(t *testing.T) {
 	var tmpl = New("test")
 	tmpl.Option("missingkey=zero")
@@ -4,10 +4,11 @@ import (
 	"fmt"
 	"github.com/Sora233/MiraiGo-Template/config"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
 	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
 	"github.com/cnxysoft/DDBOT-WSa/requests"
-	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/nobuf/cas"
 	"github.com/sirupsen/logrus"
 	"strings"
@@ -37,13 +38,48 @@ func (e *LiveEvent) Logger() *logrus.Entry {
 	return logger.WithField("Id", e.Id)
 }
 
+func (e *LiveEvent) IsLive() bool {
+	return true
+}
+
+func (e *LiveEvent) Living() bool {
+	return e.Live
+}
+
+func (e *LiveEvent) TitleChanged() bool {
+	return false
+}
+
+// LiveStatusChanged 只有直播状态变化时才会产生 LiveEvent
+func (e *LiveEvent) LiveStatusChanged() bool {
+	return true
+}
+
+func (e *LiveEvent) AnchorName() string {
+	return e.Name
+}
+
+func (e *LiveEvent) RoomTitle() string {
+	if e.Movie == nil {
+		return ""
+	}
+	return e.Movie.Movie.Title
+}
+
+func (e *LiveEvent) Popularity() int64 {
+	if e.Movie == nil {
+		return 0
+	}
+	return int64(e.Movie.Movie.MaxViewCount)
+}
+
 type LiveNotify struct {
-	groupCode int64
+	target mmsg.Target
 	LiveEvent
 }
 
-func (n *LiveNotify) GetGroupCode() int64 {
-	return n.groupCode
+func (n *LiveNotify) GetTarget() mmsg.Target {
+	return n.target
 }
 
 func (n *LiveNotify) ToMessage() *mmsg.MSG {
@@ -67,13 +103,14 @@ func (n *LiveNotify) ToMessage() *mmsg.MSG {
 		username = name
 	}
 
+	locale := template.NotifyLocale(n.target)
 	if !n.Live {
-		return mmsg.NewTextf("%v 的 TwitCasting 直播已结束。", username)
+		return mmsg.NewText(i18n.Sprintf(locale, "%v 的 TwitCasting 直播已结束。", username))
 	}
 
 	// 无资讯
 	if n.Movie == nil {
-		return mmsg.NewTextf("%v 正在 TwitCasting 直播: https://twitcasting.tv/%v (直播资讯获取失败)", username, user)
+		return mmsg.NewText(i18n.Sprintf(locale, "%v 正在 TwitCasting 直播: https://twitcasting.tv/%v (直播资讯获取失败)", username, user))
 	}
 
 	enabledTitle, enabledCreated, enabledImage :=
@@ -81,32 +118,32 @@ func (n *LiveNotify) ToMessage() *mmsg.MSG {
 		config.GlobalConfig.GetBool("twitcasting.broadcaster.created"),
 		config.GlobalConfig.GetBool("twitcasting.broadcaster.image")
 
-	message := mmsg.NewTextf("%v 正在 TwitCasting 直播", username)
+	message := mmsg.NewText(i18n.Sprintf(locale, "%v 正在 TwitCasting 直播", username))
 
 	if enabledTitle {
-		message.Textf("\n标题: %v", n.Movie.Movie.Title)
+		message.Text(i18n.Sprintf(locale, "\n标题: %v", n.Movie.Movie.Title))
 	}
 
 	if enabledCreated {
 		created := time.Unix(int64(n.Movie.Movie.Created), 0)
 
-		startTime := fmt.Sprintf("%v年%v月%v日 - %v时%v分%v秒",
+		startTime := i18n.Sprintf(locale, "%v年%v月%v日 - %v时%v分%v秒",
 			created.Year(), int(created.Month()), created.Day(),
 			created.Hour(), created.Minute(), created.Second(),
 		)
 
-		message.Textf("\n开播时间: %v", startTime)
+		message.Text(i18n.Sprintf(locale, "\n开播时间: %v", startTime))
 	}
 
-	message.Textf("\n直播间: %v", fmt.Sprintf("https://twitcasting.tv/%v", n.Movie.Broadcaster.ScreenID))
+	message.Text(i18n.Sprintf(locale, "\n直播间: %v", fmt.Sprintf("https://twitcasting.tv/%v", n.Movie.Broadcaster.ScreenID)))
 
 	if enabledImage && n.Movie.Movie.LargeThumbnail != "" {
-		message.ImageByUrl(n.Movie.Movie.LargeThumbnail, "\n[直播封面获取失败]", requests.ProxyOption(proxy_pool.PreferOversea))
+		message.ImageByUrl(n.Movie.Movie.LargeThumbnail, i18n.Tr(locale, "\n[直播封面获取失败]"), requests.ProxyOption(proxy_pool.PreferOversea))
 	}
 
 	return message
 }
 
 func (n *LiveNotify) Logger() *logrus.Entry {
-	return n.LiveEvent.Logger().WithFields(localutils.GroupLogFields(n.groupCode))
+	return n.LiveEvent.Logger().WithFields(mmsg.TargetLogFields(n.target))
 }
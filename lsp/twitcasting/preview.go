@@ -0,0 +1,37 @@
+package twitcasting
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+)
+
+// Preview 使用当前的直播状态，并当作刚刚发生变化处理，不会更新数据库中的直播状态
+func (tc *TwitCastConcern) Preview(target mmsg.Target, id interface{}, ctype concern_type.Type, limit int) ([]concern.Notify, error) {
+	if ctype != Live {
+		return nil, errors.New("该类型不支持预览")
+	}
+	if tc.client == nil {
+		return nil, errors.New("找不到 TwitCasting 配置")
+	}
+	userId := strings.ReplaceAll(id.(string), "%", ":")
+	liveStatus, err := tc.GetIsLive(userId)
+	if err != nil {
+		return nil, err
+	}
+	var event = LiveEvent{
+		Id:   id.(string),
+		Live: liveStatus.Living,
+		Name: userId,
+	}
+	if liveStatus.Living {
+		event.Movie = liveStatus.Movie
+		event.Name = liveStatus.Movie.Broadcaster.Name
+	} else if name, err := tc.getUserName(userId); err == nil {
+		event.Name = *name
+	}
+	return []concern.Notify{&LiveNotify{target, event}}, nil
+}
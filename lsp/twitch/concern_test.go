@@ -0,0 +1,101 @@
+package twitch
+
+import (
+	"context"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"testing"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLogin = "xqc"
+
+func TestMarkChanged(t *testing.T) {
+	live, err := ParseStreamInfo(readFixture(t, "stream_live.json"))
+	require.Nil(t, err)
+	markChanged(nil, live)
+	assert.True(t, live.LiveStatusChanged())
+
+	offline, err := ParseStreamInfo(readFixture(t, "stream_offline.json"))
+	require.Nil(t, err)
+	markChanged(live, offline)
+	assert.True(t, offline.LiveStatusChanged())
+	assert.False(t, offline.TitleChanged())
+
+	live2, err := ParseStreamInfo(readFixture(t, "stream_live.json"))
+	require.Nil(t, err)
+	live2.Title = "new title"
+	markChanged(live, live2)
+	assert.False(t, live2.LiveStatusChanged())
+	assert.True(t, live2.TitleChanged())
+}
+
+func TestConcern(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	testEventChan := make(chan concern.Event, 16)
+	testNotifyChan := make(chan concern.Notify)
+
+	c := NewConcern(testNotifyChan)
+
+	assert.NotNil(t, c.GetStateManager())
+	assert.Equal(t, Site, c.Site())
+
+	id, err := c.ParseId("https://www.twitch.tv/xQc")
+	assert.Nil(t, err)
+	assert.Equal(t, testLogin, id)
+
+	c.StateManager.UseNotifyGeneratorFunc(c.notifyGenerator())
+	c.StateManager.UseFreshFunc(func(ctx context.Context, eventChan chan<- concern.Event) {
+		for {
+			select {
+			case e := <-testEventChan:
+				if e != nil {
+					eventChan <- e
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+
+	assert.Nil(t, c.StateManager.Start())
+	defer c.Stop()
+	defer close(testEventChan)
+
+	liveInfo, err := ParseStreamInfo(readFixture(t, "stream_live.json"))
+	require.Nil(t, err)
+	assert.Nil(t, c.AddLiveInfo(liveInfo))
+	_, err = c.StateManager.AddGroupConcern(mmsg.NewGroupTarget(test.G1), testLogin, Live)
+	assert.Nil(t, err)
+
+	liveInfo2, err := c.FindOrLoadRoom(testLogin)
+	assert.Nil(t, err)
+	assert.NotNil(t, liveInfo2)
+
+	identity, err := c.Get(testLogin)
+	assert.Nil(t, err)
+	assert.Equal(t, "xQc", identity.GetName())
+
+	liveInfo2.liveStatusChanged = true
+
+	testEventChan <- liveInfo2
+
+	select {
+	case notify := <-testNotifyChan:
+		assert.Equal(t, mmsg.NewGroupTarget(test.G1), notify.GetTarget())
+		assert.Equal(t, testLogin, notify.GetUid())
+	case <-time.After(time.Second):
+		assert.Fail(t, "no notify received")
+	}
+
+	_, err = c.Remove(nil, mmsg.NewGroupTarget(test.G1), testLogin, Live)
+	assert.Nil(t, err)
+	_, err = c.GetLiveInfo(testLogin)
+	assert.NotNil(t, err)
+}
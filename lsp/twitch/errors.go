@@ -0,0 +1,8 @@
+package twitch
+
+import "errors"
+
+var (
+	ErrUserNotExist = errors.New("用户不存在")
+	ErrInvalidId    = errors.New("无效的twitch用户名")
+)
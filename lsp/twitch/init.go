@@ -0,0 +1,9 @@
+package twitch
+
+import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+)
+
+func init() {
+	concern.RegisterConcern(NewConcern(concern.GetNotifyChan()))
+}
@@ -0,0 +1,44 @@
+package twitch
+
+import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+)
+
+type keySet struct {
+}
+
+func (l *keySet) GroupAtAllMarkKey(keys ...interface{}) string {
+	return buntdb.TwitchGroupAtAllMarkKey(keys...)
+}
+
+func (l *keySet) GroupConcernConfigKey(keys ...interface{}) string {
+	return buntdb.TwitchGroupConcernConfigKey(keys...)
+}
+
+func (l *keySet) GroupConcernStateKey(keys ...interface{}) string {
+	return buntdb.TwitchGroupConcernStateKey(keys...)
+}
+
+func (l *keySet) FreshKey(keys ...interface{}) string {
+	return buntdb.TwitchFreshKey(keys...)
+}
+
+func (l *keySet) ParseGroupConcernStateKey(key string) (mmsg.Target, interface{}, error) {
+	return concern.ParseConcernStateKeyWithString(key)
+}
+
+type extraKey struct{}
+
+func (k extraKey) CurrentLiveKey(keys ...interface{}) string {
+	return buntdb.TwitchCurrentLiveKey(keys...)
+}
+
+func NewExtraKey() *extraKey {
+	return &extraKey{}
+}
+
+func NewKeySet() *keySet {
+	return &keySet{}
+}
@@ -0,0 +1,46 @@
+package twitch
+
+import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"testing"
+
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiveInfo(t *testing.T) {
+	l := &LiveInfo{
+		Login:   test.NAME1,
+		Name:    test.NAME2,
+		Title:   test.NAME2,
+		RoomUrl: TwitchPath(test.NAME1),
+		Game:    "Just Chatting",
+	}
+	assert.Equal(t, Site, l.Site())
+	assert.Equal(t, test.NAME2, l.GetName())
+	assert.Equal(t, Live, l.Type())
+	notify := NewConcernLiveNotify(mmsg.NewGroupTarget(test.G1), l)
+	assert.NotNil(t, notify)
+	assert.NotNil(t, notify.Logger())
+	assert.Equal(t, mmsg.NewGroupTarget(test.G1), notify.GetTarget())
+	assert.Equal(t, test.NAME1, notify.GetUid())
+	assert.Equal(t, Live, notify.Type())
+
+	m := notify.ToMessage()
+	assert.NotNil(t, m)
+	assert.Contains(t, msgstringer.MsgToString(m.Elements()), "直播结束")
+
+	l = &LiveInfo{
+		Login:    test.NAME1,
+		Name:     test.NAME2,
+		Title:    test.NAME2,
+		RoomUrl:  TwitchPath(test.NAME1),
+		Game:     "Just Chatting",
+		IsLiving: true,
+	}
+	s := msgstringer.MsgToString(NewConcernLiveNotify(mmsg.NewGroupTarget(test.G1), l).ToMessage().Elements())
+	assert.Contains(t, s, "正在直播")
+	assert.Contains(t, s, "Just Chatting")
+	assert.Contains(t, s, TwitchPath(test.NAME1))
+}
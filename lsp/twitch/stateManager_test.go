@@ -0,0 +1,43 @@
+package twitch
+
+import (
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func initStateManager(t *testing.T) *StateManager {
+	sm := NewStateManager(nil)
+	assert.NotNil(t, sm)
+	sm.FreshIndex(mmsg.NewGroupTarget(test.G1), mmsg.NewGroupTarget(test.G2))
+	return sm
+}
+
+func TestNewStateManager(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	sm := initStateManager(t)
+	assert.NotNil(t, sm)
+}
+
+func TestStateManager_GetLiveInfo(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	sm := initStateManager(t)
+	assert.NotNil(t, sm)
+
+	assert.NotNil(t, sm.GetGroupConcernConfig(mmsg.NewGroupTarget(test.G1), test.NAME1))
+
+	expected := &LiveInfo{
+		Login: test.NAME1,
+		Name:  test.NAME2,
+		Title: test.NAME2,
+	}
+	assert.Nil(t, sm.AddLiveInfo(expected))
+	actual, err := sm.GetLiveInfo(test.NAME1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, expected, actual)
+}
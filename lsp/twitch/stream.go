@@ -0,0 +1,134 @@
+package twitch
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
+	"github.com/cnxysoft/DDBOT-WSa/requests"
+	"github.com/cnxysoft/DDBOT-WSa/utils"
+	"github.com/guonaihong/gout"
+)
+
+// streamQuery 查询用户信息以及当前的直播，没有在直播时 stream 为 null
+const streamQuery = `query($login: String!) {
+  user(login: $login) {
+    id
+    login
+    displayName
+    profileImageURL(width: 300)
+    broadcastSettings { title }
+    stream {
+      id
+      type
+      title
+      previewImageURL(width: 640, height: 360)
+      game { displayName }
+    }
+  }
+}`
+
+var loginRegex = regexp.MustCompile(`^[a-z0-9_]{1,25}$`)
+
+// ParseLogin 支持用户名或者直播间链接
+func ParseLogin(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	for _, prefix := range []string{"https://", "http://", "www.", "m.", "twitch.tv/"} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	s = strings.ToLower(strings.Trim(s, "/"))
+	if !loginRegex.MatchString(s) {
+		return "", ErrInvalidId
+	}
+	return s, nil
+}
+
+type gqlStreamResponse struct {
+	Data struct {
+		User *struct {
+			Id                string `json:"id"`
+			Login             string `json:"login"`
+			DisplayName       string `json:"displayName"`
+			ProfileImageURL   string `json:"profileImageURL"`
+			BroadcastSettings *struct {
+				Title string `json:"title"`
+			} `json:"broadcastSettings"`
+			Stream *struct {
+				Id              string `json:"id"`
+				Type            string `json:"type"`
+				Title           string `json:"title"`
+				PreviewImageURL string `json:"previewImageURL"`
+				Game            *struct {
+					DisplayName string `json:"displayName"`
+				} `json:"game"`
+			} `json:"stream"`
+		} `json:"user"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func StreamInfo(login string) (*LiveInfo, error) {
+	st := time.Now()
+	defer func() {
+		ed := time.Now()
+		logger.WithField("FuncName", utils.FuncName()).Tracef("cost %v", ed.Sub(st))
+	}()
+	var opts = []requests.Option{
+		requests.AddUAOption(),
+		requests.HeaderOption("Client-Id", ClientId),
+		requests.ProxyOption(proxy_pool.PreferOversea),
+		requests.RetryOption(3),
+		requests.TimeoutOption(time.Second * 10),
+	}
+	var body = new(bytes.Buffer)
+	err := requests.PostJson(PathGQL, gout.H{
+		"query":     streamQuery,
+		"variables": gout.H{"login": login},
+	}, body, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStreamInfo(body.Bytes())
+}
+
+// ParseStreamInfo 解析gql接口的返回，用户不存在时返回 ErrUserNotExist
+func ParseStreamInfo(body []byte) (*LiveInfo, error) {
+	var resp = new(gqlStreamResponse)
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		return nil, errors.New(resp.Errors[0].Message)
+	}
+	user := resp.Data.User
+	if user == nil {
+		return nil, ErrUserNotExist
+	}
+	info := &LiveInfo{
+		Login:  user.Login,
+		UserId: user.Id,
+		Name:   user.DisplayName,
+		Avatar: user.ProfileImageURL,
+	}
+	info.RoomUrl = TwitchPath(info.Login)
+	if user.BroadcastSettings != nil {
+		info.Title = user.BroadcastSettings.Title
+	}
+	// 重播等类型不算开播
+	if stream := user.Stream; stream != nil && stream.Type == "live" {
+		info.IsLiving = true
+		info.Cover = stream.PreviewImageURL
+		if len(stream.Title) > 0 {
+			info.Title = stream.Title
+		}
+		if stream.Game != nil {
+			info.Game = stream.Game.DisplayName
+		}
+	}
+	return info, nil
+}
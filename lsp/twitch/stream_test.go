@@ -0,0 +1,55 @@
+package twitch
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	b, err := os.ReadFile("testdata/" + name)
+	require.Nil(t, err)
+	return b
+}
+
+func TestParseLogin(t *testing.T) {
+	for _, s := range []string{"xqc", "xQc", "https://www.twitch.tv/xqc", "twitch.tv/xqc/", " xqc "} {
+		login, err := ParseLogin(s)
+		assert.Nil(t, err)
+		assert.Equal(t, "xqc", login)
+	}
+	for _, s := range []string{"", "x qc", "https://www.twitch.tv/", "中文"} {
+		_, err := ParseLogin(s)
+		assert.Equal(t, ErrInvalidId, err)
+	}
+}
+
+func TestParseStreamInfo(t *testing.T) {
+	info, err := ParseStreamInfo(readFixture(t, "stream_live.json"))
+	require.Nil(t, err)
+	assert.True(t, info.Living())
+	assert.Equal(t, "xqc", info.Login)
+	assert.Equal(t, "xQc", info.Name)
+	assert.Equal(t, "LIVE REACTING", info.Title)
+	assert.Equal(t, "Just Chatting", info.Game)
+	assert.Equal(t, "https://www.twitch.tv/xqc", info.RoomUrl)
+	assert.Contains(t, info.Cover, "previews-ttv")
+
+	info, err = ParseStreamInfo(readFixture(t, "stream_offline.json"))
+	require.Nil(t, err)
+	assert.False(t, info.Living())
+	assert.Equal(t, "LIVE REACTING", info.Title)
+	assert.Empty(t, info.Cover)
+	assert.NotEmpty(t, info.Avatar)
+
+	_, err = ParseStreamInfo(readFixture(t, "stream_not_exist.json"))
+	assert.Equal(t, ErrUserNotExist, err)
+
+	_, err = ParseStreamInfo(readFixture(t, "stream_error.json"))
+	assert.EqualError(t, err, "failed integrity check")
+
+	_, err = ParseStreamInfo([]byte("<html>"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,14 @@
+package twitch
+
+const (
+	Site = "twitch"
+	Host = "https://www.twitch.tv"
+
+	PathGQL = "https://gql.twitch.tv/gql"
+	// ClientId twitch网页端使用的公开Client-Id，调用gql接口不需要登录
+	ClientId = "kimne78kx3ncx6brgo4mv6wki5h1ko"
+)
+
+func TwitchPath(path string) string {
+	return Host + "/" + path
+}
@@ -11,6 +11,7 @@ import (
 	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
 	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"math/rand"
+	"net/http"
 	"net/http/cookiejar"
 	"net/url"
 	"slices"
@@ -21,6 +22,7 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/interfaces"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
 	"github.com/cnxysoft/DDBOT-WSa/requests"
@@ -51,13 +53,16 @@ const (
 var (
 	logger          = utils.GetModuleLogger(ConcernName)
 	requestInterval = time.Second * 5 // 每个请求之间的间隔
-	buildProfileURL = func(screenName string) *url.URL {
-		Url, _ := url.Parse(BaseURL[rand.Intn(len(BaseURL))] + screenName)
+	buildProfileURL = func(m *Mirror, screenName string) *url.URL {
+		Url, _ := url.Parse(m.BaseURL + screenName)
 		return Url
 	}
-	Cookie *cookiejar.Jar
+	mirrors = NewMirrorManager(BaseURL)
 )
 
+// 一次请求最多尝试的镜像站数量
+const maxMirrorAttempts = 3
+
 type StateManager struct {
 	*concern.StateManager
 	*ExtraKey
@@ -65,8 +70,8 @@ type StateManager struct {
 }
 
 // GetGroupConcernConfig 重写 concern.StateManager 的GetGroupConcernConfig方法，让我们自己定义的 GroupConcernConfig 生效
-func (t *StateManager) GetGroupConcernConfig(groupCode int64, id interface{}) concern.IConfig {
-	return NewGroupConcernConfig(t.StateManager.GetGroupConcernConfig(groupCode, id), t.concern)
+func (t *StateManager) GetGroupConcernConfig(target mmsg.Target, id interface{}) concern.IConfig {
+	return NewGroupConcernConfig(t.StateManager.GetGroupConcernConfig(target, id), t.concern)
 }
 
 func (t *StateManager) SetNotifyMsg(notifyKey string, msg *message.GroupMessage) error {
@@ -83,20 +88,20 @@ func (t *StateManager) SetNotifyMsg(notifyKey string, msg *message.GroupMessage)
 	if err != nil {
 		return err
 	}
-	return t.Set(t.NotifyMsgKey(tmp.GroupCode, notifyKey), value,
+	return t.Set(t.NotifyMsgKey(mmsg.NewGroupTarget(tmp.GroupCode), notifyKey), value,
 		localdb.SetExpireOpt(CompactExpireTime), localdb.SetNoOverWriteOpt())
 }
 
-func (t *StateManager) GetNotifyMsg(groupCode int64, notifyKey string) (*message.GroupMessage, error) {
-	value, err := t.Get(t.NotifyMsgKey(groupCode, notifyKey))
+func (t *StateManager) GetNotifyMsg(target mmsg.Target, notifyKey string) (*message.GroupMessage, error) {
+	value, err := t.Get(t.NotifyMsgKey(target, notifyKey))
 	if err != nil {
 		return nil, err
 	}
 	return localutils.DeserializationGroupMsg(value)
 }
 
-func (t *StateManager) SetGroupCompactMarkIfNotExist(groupCode int64, compactKey string) error {
-	return t.Set(t.CompactMarkKey(groupCode, compactKey), "",
+func (t *StateManager) SetGroupCompactMarkIfNotExist(target mmsg.Target, compactKey string) error {
+	return t.Set(t.CompactMarkKey(target, compactKey), "",
 		localdb.SetExpireOpt(CompactExpireTime), localdb.SetNoOverWriteOpt())
 }
 
@@ -135,37 +140,14 @@ func CSTTime(t time.Time) time.Time {
 }
 
 func (t *twitterConcern) FindUserInfo(id string, refresh bool) (*UserInfo, error) {
-retry:
-	var info *UserInfo
 	if refresh {
-		Url := buildProfileURL(id)
-		opts := SetRequestOptions()
-		var resp bytes.Buffer
-		var respHeaders requests.RespHeader
-		if err := requests.GetWithHeader(Url.String(), nil, &resp, &respHeaders, opts...); err != nil {
-			logger.WithField("Mirror", Url.Hostname()).Errorf("查找用户失败：%v", err)
-			return nil, err
-		}
-
-		// 解压缩HTML
-		body, err := utils.HtmlDecoder(respHeaders.ContentEncoding, resp)
-		if err != nil {
-			logger.WithField("Mirror", Url.Hostname()).
-				WithField("User", id).Errorf("解压缩HTML失败：%v", err)
-			return nil, err
-		}
-
-		// 解析用户信息
-		profile, _, anubis, err := ParseResp(body, Url.String())
+		profile, _, err := fetchProfile(id)
 		if err != nil {
 			return nil, err
-		} else if anubis != nil {
-			FreshCookie(anubis)
-			goto retry
 		} else if profile == nil {
 			return nil, errors.New("用户不存在或返回结果为空")
 		}
-		info = &UserInfo{
+		info := &UserInfo{
 			Id:   profile.ScreenName,
 			Name: profile.Name,
 		}
@@ -177,6 +159,88 @@ retry:
 	return t.GetUserInfo(id)
 }
 
+// fetchProfile 获取并解析用户主页，镜像站请求失败时换用其他镜像站重试
+func fetchProfile(id string) (*UserProfile, []*Tweet, error) {
+	var tried []*Mirror
+	var lastErr error
+	for i := 0; i < maxMirrorAttempts; i++ {
+		m := mirrors.Pick(tried...)
+		if m == nil {
+			break
+		}
+		tried = append(tried, m)
+		profile, tweets, final, err := fetchProfileFromMirror(m, id)
+		if err == nil || final {
+			return profile, tweets, err
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = errors.New("没有可用的镜像站")
+	}
+	return nil, nil, lastErr
+}
+
+// fetchProfileFromMirror 从指定的镜像站获取用户主页，并记录镜像站的健康状态
+// final 为 true 表示错误与镜像站无关（例如账号被冻结），不需要换用其他镜像站
+func fetchProfileFromMirror(m *Mirror, id string) (profile *UserProfile, tweets []*Tweet, final bool, err error) {
+	log := logger.WithField("Mirror", m.Host).WithField("userId", id)
+	var solved bool
+	for {
+		Url := buildProfileURL(m, id)
+		var code int
+		opts := append(SetRequestOptions(m.Jar), requests.HttpCodeOption(&code))
+		var resp bytes.Buffer
+		var respHeaders requests.RespHeader
+		start := time.Now()
+		if err = requests.GetWithHeader(Url.String(), nil, &resp, &respHeaders, opts...); err != nil {
+			log.Errorf("请求镜像站失败：%v", err)
+			m.Fail(err, code == http.StatusTooManyRequests)
+			return
+		}
+		latency := time.Since(start)
+
+		// 解压缩HTML
+		var body []byte
+		body, err = utils.HtmlDecoder(respHeaders.ContentEncoding, resp)
+		if err != nil {
+			log.Errorf("解压缩HTML失败：%v", err)
+			m.Fail(err, false)
+			return
+		}
+
+		var anubis *AnubisResult
+		profile, tweets, anubis, err = ParseResp(body, Url.String())
+		if err != nil {
+			if strings.Contains(err.Error(), "suspended") {
+				m.Success(latency)
+				final = true
+				return
+			}
+			log.Errorf("解析HTML失败：%v", err)
+			m.Fail(err, false)
+			return
+		}
+		if anubis != nil {
+			if solved || !m.Challenge() {
+				err = errors.New("通过 Anubis 验证后仍然需要验证")
+				log.Error(err)
+				m.Fail(err, false)
+				return
+			}
+			if err = FreshCookie(anubis, m.Jar); err != nil {
+				m.Fail(err, false)
+				return
+			}
+			m.AnubisSolved()
+			solved = true
+			continue
+		}
+		m.Success(latency)
+		return
+	}
+}
+
 func (t *twitterConcern) FindOrLoadUserInfo(id string) (*UserInfo, error) {
 	info, _ := t.FindUserInfo(id, false)
 	if info == nil {
@@ -210,15 +274,15 @@ func (t *twitterConcern) SetLatestTweetIds(userId string, tweetIds *LatestTweetI
 	})
 }
 
-func (t *twitterConcern) Add(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+func (t *twitterConcern) Add(ctx mmsg.IMsgCtx, target mmsg.Target, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	// 这里是添加订阅的函数
-	// 可以使 c.StateManager.AddGroupConcern(groupCode, id, ctype) 来添加这个订阅
+	// 可以使 c.StateManager.AddGroupConcern(target, id, ctype) 来添加这个订阅
 	// 通常在添加订阅前还需要通过id访问网站上的个人信息页面，来确定id是否存在，是否可以正常订阅
 	info, err := t.FindOrLoadUserInfo(id.(string))
 	if err != nil {
 		return nil, fmt.Errorf("查询用户信息失败 %v - %v", id, err)
 	}
-	_, err = t.GetStateManager().AddGroupConcern(groupCode, id, ctype)
+	_, err = t.GetStateManager().AddGroupConcern(target, id, ctype)
 	if err != nil {
 		return nil, err
 	}
@@ -245,11 +309,11 @@ func (t *twitterConcern) removeFreshTime(id string) error {
 	return err
 }
 
-func (t *twitterConcern) Remove(ctx mmsg.IMsgCtx, groupCode int64, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+func (t *twitterConcern) Remove(ctx mmsg.IMsgCtx, target mmsg.Target, id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	// 大部分时候简单的删除即可
 	// 如果还有更复杂的逻辑可以自由实现
 	identity, _ := t.Get(id)
-	_, err := t.GetStateManager().RemoveGroupConcern(groupCode, id.(string), ctype)
+	_, err := t.GetStateManager().RemoveGroupConcern(target, id.(string), ctype)
 	if err != nil {
 		return nil, err
 	}
@@ -303,10 +367,10 @@ func (t *twitterConcern) Get(id interface{}) (concern.IdentityInfo, error) {
 }
 
 func (t *twitterConcern) notifyGenerator() concern.NotifyGeneratorFunc {
-	return func(groupCode int64, event concern.Event) (result []concern.Notify) {
+	return func(target mmsg.Target, event concern.Event) (result []concern.Notify) {
 		switch e := event.(type) {
 		case *NewsInfo:
-			notifies := NewConcernNewsNotify(groupCode, e, t.concern)
+			notifies := NewConcernNewsNotify(target, e, t.concern)
 			result = append(result, notifies)
 			return
 		default:
@@ -343,7 +407,7 @@ func (t *twitterConcern) processUser(ctx context.Context, eventChan chan<- conce
 
 func (t *twitterConcern) processUsers(ctx context.Context, eventChan chan<- concern.Event) {
 	// 获取最新用户列表
-	_, ids, _, _ := t.StateManager.ListConcernState(func(g int64, id interface{}, p concern_type.Type) bool { return p.ContainAll(Tweets) })
+	_, ids, _, _ := t.StateManager.ListConcernState(func(target mmsg.Target, id interface{}, p concern_type.Type) bool { return p.ContainAll(Tweets) })
 	for _, userId := range ids {
 		if ctx.Err() != nil {
 			return
@@ -392,6 +456,7 @@ func (t *twitterConcern) freshNewsInfo(ctype concern_type.Type, id interface{})
 		if err != nil {
 			return nil, err
 		}
+		checkDeletedTweets(userId, newTweets)
 		oldTweetIds, err := t.GetLatestTweetIds(userId)
 		if err != nil && err.Error() != ErrNotFound {
 			logger.WithError(err).Errorf("内部错误 - 已推送推文列表获取失败：%v", err)
@@ -459,6 +524,28 @@ func (t *twitterConcern) freshNewsInfo(ctype concern_type.Type, id interface{})
 	return result, nil
 }
 
+// checkDeletedTweets 检测已推送的推文是否被删除
+// 置顶推文和转推的时间不按时间线排序，不参与计算检测范围
+func checkDeletedTweets(userId string, tweets []*Tweet) {
+	var since time.Time
+	var ids []string
+	for _, tweet := range tweets {
+		ids = append(ids, tweet.ID)
+		if tweet.Pinned || tweet.IsRetweet || tweet.CreatedAt.IsZero() {
+			continue
+		}
+		if since.IsZero() || tweet.CreatedAt.Before(since) {
+			since = tweet.CreatedAt
+		}
+	}
+	if since.IsZero() {
+		return
+	}
+	concern.CheckDeletedPost(Site, since, ids, func(id string) bool {
+		return id == userId
+	})
+}
+
 func getTargetTweet(tweets []*Tweet, targetId string) *Tweet {
 	for _, tweet := range tweets {
 		if tweet.ID == targetId {
@@ -508,7 +595,8 @@ func (t *twitterConcern) GetTweetIdList(id string) ([]string, error) {
 	return TweetList, nil
 }
 
-func SetRequestOptions() []requests.Option {
+// SetRequestOptions 请求镜像站使用的参数，jar 为该镜像站的cookie
+func SetRequestOptions(jar *cookiejar.Jar) []requests.Option {
 	//h1 := (http.DefaultTransport).(*http.Transport).Clone()
 	//h1.MaxResponseHeaderBytes = 262144
 	return []requests.Option{
@@ -540,41 +628,17 @@ func SetRequestOptions() []requests.Option {
 		requests.HeaderOption("priority", "u=0, i"),
 		requests.RetryOption(3),
 		//requests.WithTransport(h1),
-		requests.WithCookieJar(Cookie),
+		requests.WithCookieJar(jar),
 	}
 }
 
 func (t *twitterConcern) GetTweets(id string) ([]*Tweet, error) {
-retry:
-	Url := buildProfileURL(id)
-	opts := SetRequestOptions()
-	var resp bytes.Buffer
-	var respHeaders requests.RespHeader
-	if err := requests.GetWithHeader(Url.String(), nil, &resp, &respHeaders, opts...); err != nil {
-		logger.WithField("Mirror", Url.Hostname()).WithField("userId", id).Errorf("获取推文列表失败：%v", err)
-		return nil, err
-	}
-
-	// 解压缩HTML
-	body, err := utils.HtmlDecoder(respHeaders.ContentEncoding, resp)
+	_, tweets, err := fetchProfile(id)
 	if err != nil {
-		logger.WithField("Mirror", Url.Hostname()).
-			WithField("userId", id).Errorf("解压缩HTML失败：%v", err)
+		logger.WithField("userId", id).Errorf("获取推文列表失败：%v", err)
 		return nil, err
-	}
-
-	// 解析解压后的数据
-	_, tweets, anubis, err := ParseResp(body, Url.String())
-	if err != nil {
-		logger.WithField("Mirror", Url.Hostname()).
-			WithField("userId", id).Errorf("解析HTML失败：%v", err)
-		return nil, err
-	} else if anubis != nil {
-		FreshCookie(anubis)
-		goto retry
 	} else if tweets == nil {
-		logger.WithField("Mirror", Url.Hostname()).
-			WithField("userId", id).Warn("获取推文列表失败：无法解析数据或推文列表为空")
+		logger.WithField("userId", id).Warn("获取推文列表失败：无法解析数据或推文列表为空")
 		return nil, nil
 	}
 	return tweets, nil
@@ -667,6 +731,16 @@ func (t *twitterConcern) Stop() {
 	logger.Tracef("%v concern已停止", Site)
 }
 
+// MirrorStatus 实现 interfaces.MirrorProvider
+func (t *twitterConcern) MirrorStatus() []*interfaces.MirrorStatus {
+	return mirrors.Status()
+}
+
+// ResetMirror 实现 interfaces.MirrorProvider
+func (t *twitterConcern) ResetMirror(host string) int {
+	return mirrors.Reset(host)
+}
+
 func (t *twitterConcern) GetStateManager() concern.IStateManager {
 	return t.StateManager
 }
@@ -679,6 +753,6 @@ func newConcern(notifyChan chan<- concern.Notify) *twitterConcern {
 	return con
 }
 
-func (c *twitterConcern) GetGroupConcernConfig(groupCode int64, id interface{}) (concernConfig concern.IConfig) {
-	return NewGroupConcernConfig(c.StateManager.GetGroupConcernConfig(groupCode, id), c.concern)
+func (c *twitterConcern) GetGroupConcernConfig(target mmsg.Target, id interface{}) (concernConfig concern.IConfig) {
+	return NewGroupConcernConfig(c.StateManager.GetGroupConcernConfig(target, id), c.concern)
 }
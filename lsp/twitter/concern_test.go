@@ -91,7 +91,7 @@ func TestTwitterConcern_GetUserInfo(t *testing.T) {
 
 			// 替换 buildProfileURL
 			originalBuildProfileURL := buildProfileURL
-			buildProfileURL = func(screenName string) *url.URL {
+			buildProfileURL = func(_ *Mirror, screenName string) *url.URL {
 				Url, _ := url.Parse(ts.URL)
 				return Url
 			}
@@ -104,9 +104,6 @@ func TestTwitterConcern_GetUserInfo(t *testing.T) {
 				},
 			}
 
-			Cookie, _ = test.NewJar()
-			defer test.DestroyJar(Cookie)
-
 			result, err := tc.FindUserInfo(tt.screenName, true)
 
 			if tt.expectError {
@@ -339,7 +336,7 @@ TVアニメは7月6日(日)放送開始です！
 
 	// 替换 buildProfileURL 函数（需要修改 production 代码以支持此操作）
 	originalBuildProfileURL := buildProfileURL
-	buildProfileURL = func(screenName string) *url.URL {
+	buildProfileURL = func(_ *Mirror, screenName string) *url.URL {
 		Url, _ := url.Parse(ts.URL)
 		return Url
 	}
@@ -356,10 +353,6 @@ TVアニメは7月6日(日)放送開始です！
 		},
 	}
 
-	// 创建 CookieJar
-	Cookie, _ = test.NewJar()
-	defer test.DestroyJar(Cookie)
-
 	// 执行测试
 	tweets, err := tc.GetTweets("testuser")
 
@@ -30,7 +30,7 @@ func (g *GroupConcernConfig) NotifyBeforeCallback(inotify concern.Notify) {
 	// 解决一起转发的时候刷屏
 	notify.compactKey = notify.Tweet.ID
 retry:
-	err := g.concern.SetGroupCompactMarkIfNotExist(notify.GetGroupCode(), notify.compactKey)
+	err := g.concern.SetGroupCompactMarkIfNotExist(notify.GetTarget(), notify.compactKey)
 	if localdb.IsRollback(err) {
 		notify.shouldCompact = true
 	} else if !reQuery && notify.Tweet.QuoteTweet != nil {
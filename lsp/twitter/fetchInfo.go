@@ -11,6 +11,7 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
 	"github.com/cnxysoft/DDBOT-WSa/requests"
 	"math/rand"
+	"net/http/cookiejar"
 	"net/url"
 	"strconv"
 	"strings"
@@ -468,13 +469,14 @@ func checkNibbles(hash [32]byte, difficulty int) bool {
 	return false
 }
 
-func FreshCookie(anubis *AnubisResult) {
+// FreshCookie 提交 Anubis 验证的结果，通过后验证cookie会保存到 jar 中
+func FreshCookie(anubis *AnubisResult, jar *cookiejar.Jar) error {
 	opts := []requests.Option{
 		requests.RequestAutoHostOption(),
 		requests.ProxyOption(proxy_pool.PreferOversea),
 		requests.AddUAOption(UserAgent),
 		requests.RetryOption(3),
-		requests.WithCookieJar(Cookie),
+		requests.WithCookieJar(jar),
 		requests.HeaderOption("accept-language", "zh-CN,zh;q=0.9"),
 	}
 	var addAnubisId string
@@ -488,4 +490,5 @@ func FreshCookie(anubis *AnubisResult) {
 	if err != nil {
 		logger.Errorf("twitter: fresh %s cookie error %v", anubis.Host, err)
 	}
+	return err
 }
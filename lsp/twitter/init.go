@@ -3,7 +3,6 @@ package twitter
 import (
 	"github.com/Sora233/MiraiGo-Template/config"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
-	"net/http/cookiejar"
 )
 
 var (
@@ -18,11 +17,11 @@ func init() {
 func setCookies() {
 	ua := config.GlobalConfig.GetString("twitter.userAgent")
 	url := config.GlobalConfig.GetStringSlice("twitter.BaseUrl")
-	Cookie, _ = cookiejar.New(nil)
 	if ua != "" {
 		UserAgent = ua
 	}
 	if len(url) > 0 {
 		BaseURL = url
 	}
+	mirrors = NewMirrorManager(BaseURL)
 }
@@ -0,0 +1,306 @@
+package twitter
+
+import (
+	"math/rand"
+	"net/http/cookiejar"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/interfaces"
+)
+
+const (
+	// 连续失败这么多次之后进入冷却
+	mirrorFailThreshold = 3
+	// 冷却时间从 mirrorCooldownBase 开始，每次连续失败翻倍，最多 mirrorCooldownMax
+	mirrorCooldownBase = time.Minute * 2
+	mirrorCooldownMax  = time.Minute * 30
+	// 镜像站返回 429 时直接冷却
+	mirrorRateLimitCooldown = time.Minute * 10
+	// 成功率和延迟使用指数加权平均，这是新样本的权重
+	mirrorEWMAWeight = 0.2
+	// 刚通过 Anubis 验证后如果马上又遇到验证，说明验证没有生效
+	mirrorAnubisRecheck = time.Minute
+)
+
+// Mirror 一个 nitter 镜像站，每个镜像站使用独立的cookie和 Anubis 验证状态
+type Mirror struct {
+	BaseURL string
+	Host    string
+	Jar     *cookiejar.Jar
+
+	mu              sync.Mutex
+	requests        int64
+	failures        int64
+	challenges      int64
+	successRate     float64
+	latency         time.Duration
+	consecutiveFail int
+	cooldownUntil   time.Time
+	anubisSolved    time.Time
+	lastError       string
+}
+
+func newMirror(baseURL string) *Mirror {
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	var host string
+	if u, err := url.Parse(baseURL); err == nil {
+		host = u.Hostname()
+	}
+	jar, _ := cookiejar.New(nil)
+	return &Mirror{
+		BaseURL:     baseURL,
+		Host:        host,
+		Jar:         jar,
+		successRate: 1,
+	}
+}
+
+// Success 记录一次成功的请求
+func (m *Mirror) Success(latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests++
+	m.successRate = m.successRate*(1-mirrorEWMAWeight) + mirrorEWMAWeight
+	if m.latency == 0 {
+		m.latency = latency
+	} else {
+		m.latency = time.Duration(float64(m.latency)*(1-mirrorEWMAWeight) + float64(latency)*mirrorEWMAWeight)
+	}
+	m.consecutiveFail = 0
+	m.cooldownUntil = time.Time{}
+	m.lastError = ""
+}
+
+// Fail 记录一次失败的请求，连续失败或者被限流时进入冷却
+func (m *Mirror) Fail(err error, rateLimited bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests++
+	m.failures++
+	m.successRate = m.successRate * (1 - mirrorEWMAWeight)
+	m.consecutiveFail++
+	if err != nil {
+		m.lastError = err.Error()
+	}
+	var cooldown time.Duration
+	if rateLimited {
+		cooldown = mirrorRateLimitCooldown
+	}
+	if m.consecutiveFail >= mirrorFailThreshold {
+		backoff := mirrorCooldownBase << (m.consecutiveFail - mirrorFailThreshold)
+		if backoff <= 0 || backoff > mirrorCooldownMax {
+			backoff = mirrorCooldownMax
+		}
+		if backoff > cooldown {
+			cooldown = backoff
+		}
+	}
+	if cooldown > 0 {
+		m.cooldownUntil = time.Now().Add(cooldown)
+	}
+}
+
+// Challenge 记录一次 Anubis 验证，返回 false 表示刚刚验证过又遇到了验证，不应该继续尝试
+func (m *Mirror) Challenge() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.challenges++
+	return m.anubisSolved.IsZero() || time.Since(m.anubisSolved) > mirrorAnubisRecheck
+}
+
+// AnubisSolved 记录通过了 Anubis 验证，cookie已经保存在 Jar 中
+func (m *Mirror) AnubisSolved() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.anubisSolved = time.Now()
+}
+
+// Reset 清除冷却和统计
+func (m *Mirror) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = 0
+	m.failures = 0
+	m.challenges = 0
+	m.successRate = 1
+	m.latency = 0
+	m.consecutiveFail = 0
+	m.cooldownUntil = time.Time{}
+	m.lastError = ""
+}
+
+func (m *Mirror) coolingDown(now time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return now.Before(m.cooldownUntil)
+}
+
+func (m *Mirror) cooldownEnd() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cooldownUntil
+}
+
+// score 镜像站的健康分数，成功率越高、延迟越低、验证越少分数越高
+func (m *Mirror) score() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	score := m.successRate / (1 + m.latency.Seconds()/5)
+	if m.requests > 0 {
+		challengeRate := float64(m.challenges) / float64(m.requests+m.challenges)
+		score *= 1 - challengeRate/2
+	}
+	// 保证失败的镜像站冷却结束后仍然有机会被选到
+	return score + 0.01
+}
+
+// Status 返回镜像站的当前状态
+func (m *Mirror) Status() *interfaces.MirrorStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status := &interfaces.MirrorStatus{
+		Host:         m.Host,
+		Healthy:      !time.Now().Before(m.cooldownUntil),
+		SuccessRate:  m.successRate,
+		Latency:      m.latency,
+		Requests:     m.requests,
+		Failures:     m.failures,
+		Challenges:   m.challenges,
+		AnubisSolved: m.anubisSolved,
+		LastError:    m.lastError,
+	}
+	if !status.Healthy {
+		status.CooldownUntil = m.cooldownUntil
+	}
+	return status
+}
+
+// MirrorManager 管理所有镜像站，优先选择健康的镜像站
+type MirrorManager struct {
+	mu      sync.RWMutex
+	mirrors []*Mirror
+	// fallback 不属于任何镜像站的请求使用的cookie
+	fallback *cookiejar.Jar
+}
+
+func NewMirrorManager(baseURLs []string) *MirrorManager {
+	mm := new(MirrorManager)
+	mm.fallback, _ = cookiejar.New(nil)
+	for _, baseURL := range baseURLs {
+		if baseURL = strings.TrimSpace(baseURL); baseURL == "" {
+			continue
+		}
+		mm.mirrors = append(mm.mirrors, newMirror(baseURL))
+	}
+	return mm
+}
+
+// Pick 选择一个镜像站，exclude 中的镜像站不会被选择，没有可用的镜像站时返回 nil
+// 在未冷却的镜像站中按照健康分数加权随机选择，全部冷却时选择最早结束冷却的镜像站
+func (mm *MirrorManager) Pick(exclude ...*Mirror) *Mirror {
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+	var now = time.Now()
+	var candidates []*Mirror
+	var cooling []*Mirror
+	for _, m := range mm.mirrors {
+		if containsMirror(exclude, m) {
+			continue
+		}
+		if m.coolingDown(now) {
+			cooling = append(cooling, m)
+		} else {
+			candidates = append(candidates, m)
+		}
+	}
+	if len(candidates) == 0 {
+		if len(cooling) == 0 {
+			return nil
+		}
+		sort.Slice(cooling, func(i, j int) bool {
+			return cooling[i].cooldownEnd().Before(cooling[j].cooldownEnd())
+		})
+		return cooling[0]
+	}
+	var scores = make([]float64, len(candidates))
+	var total float64
+	for i, m := range candidates {
+		scores[i] = m.score()
+		total += scores[i]
+	}
+	r := rand.Float64() * total
+	for i, m := range candidates {
+		if r < scores[i] {
+			return m
+		}
+		r -= scores[i]
+	}
+	return candidates[len(candidates)-1]
+}
+
+// Len 返回镜像站的数量
+func (mm *MirrorManager) Len() int {
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+	return len(mm.mirrors)
+}
+
+// Find 按照host查找镜像站
+func (mm *MirrorManager) Find(host string) *Mirror {
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+	for _, m := range mm.mirrors {
+		if m.Host == host {
+			return m
+		}
+	}
+	return nil
+}
+
+// CookieJar 返回该host的镜像站使用的cookie，不是镜像站时返回公共的cookie
+func (mm *MirrorManager) CookieJar(host string) *cookiejar.Jar {
+	if m := mm.Find(host); m != nil {
+		return m.Jar
+	}
+	return mm.fallback
+}
+
+// Status 返回所有镜像站的状态
+func (mm *MirrorManager) Status() []*interfaces.MirrorStatus {
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+	var result []*interfaces.MirrorStatus
+	for _, m := range mm.mirrors {
+		result = append(result, m.Status())
+	}
+	return result
+}
+
+func containsMirror(list []*Mirror, m *Mirror) bool {
+	for _, e := range list {
+		if e == m {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset 重置指定host的镜像站，host为空时重置所有镜像站，返回重置的数量
+func (mm *MirrorManager) Reset(host string) int {
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+	var count int
+	for _, m := range mm.mirrors {
+		if host == "" || m.Host == host {
+			m.Reset()
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,103 @@
+package twitter
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMirrorManager(t *testing.T) {
+	mm := NewMirrorManager([]string{"https://a.example.com/", "https://b.example.com", " "})
+	assert.Equal(t, 2, mm.Len())
+	a := mm.Find("a.example.com")
+	b := mm.Find("b.example.com")
+	assert.NotNil(t, a)
+	assert.NotNil(t, b)
+	assert.Equal(t, "https://b.example.com/", b.BaseURL)
+	assert.NotSame(t, a.Jar, b.Jar)
+	assert.Same(t, a.Jar, mm.CookieJar("a.example.com"))
+	assert.NotNil(t, mm.CookieJar("other.example.com"))
+
+	// 连续失败后进入冷却，不会再被选择
+	for i := 0; i < mirrorFailThreshold; i++ {
+		a.Fail(errors.New("fail"), false)
+	}
+	for i := 0; i < 20; i++ {
+		assert.Same(t, b, mm.Pick())
+	}
+	assert.Same(t, b, mm.Pick(a))
+	assert.Nil(t, mm.Pick(a, b))
+
+	status := a.Status()
+	assert.False(t, status.Healthy)
+	assert.False(t, status.CooldownUntil.IsZero())
+	assert.EqualValues(t, mirrorFailThreshold, status.Failures)
+	assert.Equal(t, "fail", status.LastError)
+
+	// 全部冷却时选择最早结束冷却的，限流的冷却时间比连续失败更长
+	b.Fail(errors.New("429"), true)
+	assert.False(t, b.Status().Healthy)
+	assert.Same(t, a, mm.Pick())
+
+	// 成功后结束冷却
+	b.Success(time.Millisecond * 100)
+	status = b.Status()
+	assert.True(t, status.Healthy)
+	assert.Empty(t, status.LastError)
+	assert.Equal(t, time.Millisecond*100, status.Latency)
+
+	assert.Equal(t, 1, mm.Reset("a.example.com"))
+	assert.True(t, a.Status().Healthy)
+	assert.EqualValues(t, 0, a.Status().Requests)
+	assert.Equal(t, 2, mm.Reset(""))
+	assert.Len(t, mm.Status(), 2)
+}
+
+func TestMirrorChallenge(t *testing.T) {
+	m := newMirror("https://a.example.com")
+	assert.True(t, m.Challenge())
+	m.AnubisSolved()
+	// 刚通过验证又遇到验证
+	assert.False(t, m.Challenge())
+	assert.EqualValues(t, 2, m.Status().Challenges)
+	assert.False(t, m.Status().AnubisSolved.IsZero())
+}
+
+func TestFetchProfileFailover(t *testing.T) {
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer bad.Close()
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>test</title><meta property="og:title" content="Test User (@testuser)"></head><body></body></html>`))
+	}))
+	defer good.Close()
+
+	originMirrors := mirrors
+	defer func() { mirrors = originMirrors }()
+	mirrors = NewMirrorManager([]string{bad.URL, good.URL})
+	// 先让正常的镜像站冷却，保证第一次请求的是被限流的镜像站
+	mirrors.mirrors[1].Fail(nil, true)
+
+	for i := 0; i < 3; i++ {
+		profile, _, err := fetchProfile("testuser")
+		assert.Nil(t, err)
+		if assert.NotNil(t, profile) {
+			assert.Equal(t, "testuser", profile.ScreenName)
+			assert.Equal(t, "Test User", profile.Name)
+		}
+	}
+
+	var badStatus = mirrors.Status()[0]
+	// 被限流的镜像站直接冷却，之后不会再请求
+	assert.False(t, badStatus.Healthy)
+	assert.EqualValues(t, 1, badStatus.Failures)
+	// 请求成功后结束冷却
+	assert.True(t, mirrors.Status()[1].Healthy)
+	assert.EqualValues(t, 3, mirrors.Status()[1].Requests-mirrors.Status()[1].Failures)
+}
@@ -3,7 +3,9 @@ package twitter
 import (
 	"fmt"
 	"github.com/Mrs4s/MiraiGo/message"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
 	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
 	"github.com/cnxysoft/DDBOT-WSa/requests"
 	"github.com/google/uuid"
@@ -18,7 +20,7 @@ import (
 )
 
 type ConcernNewsNotify struct {
-	GroupCode int64 `json:"group_code"`
+	Target mmsg.Target `json:"target"`
 	*NewsInfo
 
 	shouldCompact bool
@@ -26,8 +28,8 @@ type ConcernNewsNotify struct {
 	concern       *twitterConcern
 }
 
-func (n *ConcernNewsNotify) GetGroupCode() int64 {
-	return n.GroupCode
+func (n *ConcernNewsNotify) GetTarget() mmsg.Target {
+	return n.Target
 }
 
 func (n *ConcernNewsNotify) ToMessage() (m *mmsg.MSG) {
@@ -39,29 +41,29 @@ func (n *ConcernNewsNotify) ToMessage() (m *mmsg.MSG) {
 		}
 	}()
 	m = mmsg.NewMSG()
+	locale := template.NotifyLocale(n.Target)
 	var addedUrl bool
 	if n.shouldCompact {
 		// 通过回复之前消息的方式简化推送
-		msg, _ := n.concern.GetNotifyMsg(n.GroupCode, n.compactKey)
+		msg, _ := n.concern.GetNotifyMsg(n.Target, n.compactKey)
 		if msg != nil {
 			m.Append(message.NewReply(msg))
 		}
 		logger.WithField("compact_key", n.compactKey).Debug("compact notify")
-		Tips := "转发"
+		format := "X-%v转发了%v的推文：\n%v\n%v\n"
 		var OrgUserName string
 		if n.Tweet.QuoteTweet != nil {
 			OrgUserName = n.Tweet.QuoteTweet.OrgUser.Name
-			Tips = "引用"
+			format = "X-%v引用了%v的推文：\n%v\n%v\n"
 		} else {
 			OrgUserName = n.Tweet.OrgUser.Name
 		}
-		m.Textf("X-%s%s了%s的推文：\n%s\n%s\n",
+		m.Text(i18n.Sprintf(locale, format,
 			n.Name,
-			Tips,
 			OrgUserName,
 			CSTTime(time.Now().UTC()).Format(time.DateTime),
 			n.Tweet.Content,
-		)
+		))
 		addTweetUrl(m, n.Tweet.Url, &addedUrl)
 	} else {
 		// 构造消息
@@ -71,11 +73,11 @@ func (n *ConcernNewsNotify) ToMessage() (m *mmsg.MSG) {
 		var CreatedAt time.Time
 		if n.Tweet.RtType() == RETWEET {
 			CreatedAt = time.Now().UTC()
-			m.Textf("X-%s转发了%s的推文：\n",
-				n.Name, n.Tweet.OrgUser.Name)
+			m.Text(i18n.Sprintf(locale, "X-%v转发了%v的推文：\n",
+				n.Name, n.Tweet.OrgUser.Name))
 		} else {
 			CreatedAt = n.Tweet.CreatedAt
-			m.Textf("X-%s发布了新推文：\n", n.Name)
+			m.Text(i18n.Sprintf(locale, "X-%v发布了新推文：\n", n.Name))
 		}
 		m.Text(CSTTime(CreatedAt).Format(time.DateTime) + "\n")
 		// msg加入推文
@@ -91,7 +93,7 @@ func (n *ConcernNewsNotify) ToMessage() (m *mmsg.MSG) {
 		// msg加入被引用推文
 		if QuoteTweet := n.Tweet.QuoteTweet; QuoteTweet != nil {
 			var CreatedAt time.Time
-			quoteTxt := "\n%v引用了%v的推文：\n"
+			quoteTxt := i18n.Tr(locale, "\n%v引用了%v的推文：\n")
 			CreatedAt = QuoteTweet.CreatedAt
 			// 检查是否需要插入cut
 			addCut(m, &quoteTxt)
@@ -109,6 +111,15 @@ func (n *ConcernNewsNotify) ToMessage() (m *mmsg.MSG) {
 	return
 }
 
+// PostId 返回推文id，用于检测推文是否被删除
+func (n *ConcernNewsNotify) PostId() string {
+	return n.Tweet.ID
+}
+
+func (n *ConcernNewsNotify) PostTime() time.Time {
+	return n.Tweet.CreatedAt
+}
+
 func (n *ConcernNewsNotify) IsLive() bool {
 	return false
 }
@@ -117,14 +128,14 @@ func (n *ConcernNewsNotify) Living() bool {
 	return false
 }
 
-func NewConcernNewsNotify(groupCode int64, newsInfo *NewsInfo, c *twitterConcern) *ConcernNewsNotify {
+func NewConcernNewsNotify(target mmsg.Target, newsInfo *NewsInfo, c *twitterConcern) *ConcernNewsNotify {
 	if newsInfo == nil {
 		return nil
 	}
 	var result = &ConcernNewsNotify{
-		GroupCode: groupCode,
-		NewsInfo:  newsInfo,
-		concern:   c,
+		Target:   target,
+		NewsInfo: newsInfo,
+		concern:  c,
 	}
 	return result
 }
@@ -167,7 +178,7 @@ func addMedia(tweet *Tweet, message *mmsg.MSG, mainTweet bool, addedUrl *bool) {
 						mmsg.NewImageByUrl(m.Url,
 							requests.ProxyOption(proxy_pool.PreferOversea),
 							requests.AddUAOption(UserAgent),
-							requests.WithCookieJar(Cookie)))
+							requests.WithCookieJar(mirrors.CookieJar(tweet.MirrorHost))))
 				case "video":
 					if strings.Contains(unescape, "video.twimg.com") {
 						idx := strings.Index(unescape, "video.twimg.com")
@@ -188,7 +199,7 @@ func addMedia(tweet *Tweet, message *mmsg.MSG, mainTweet bool, addedUrl *bool) {
 						mmsg.NewVideoByUrl(m.Url,
 							requests.ProxyOption(proxy_pool.PreferOversea),
 							requests.AddUAOption(UserAgent),
-							requests.WithCookieJar(Cookie)))
+							requests.WithCookieJar(mirrors.CookieJar(tweet.MirrorHost))))
 				case "gif":
 					if strings.Contains(unescape, "video.twimg.com") {
 						idx := strings.Index(unescape, "video.twimg.com")
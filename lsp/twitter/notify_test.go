@@ -1,7 +1,7 @@
 package twitter
 
 import (
-	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
 	"github.com/cnxysoft/DDBOT-WSa/proxy_pool/local_proxy_pool"
 	"github.com/stretchr/testify/assert"
@@ -26,7 +26,7 @@ func TestToMessage_ErrorHandling(t *testing.T) {
 	}
 
 	notify := &ConcernNewsNotify{
-		GroupCode: 123456,
+		Target: mmsg.NewGroupTarget(123456),
 		NewsInfo: &NewsInfo{
 			UserInfo: userInfo,
 			Tweet:    &tweet,
@@ -45,9 +45,6 @@ func TestToMessage_ErrorHandling(t *testing.T) {
 	}
 	proxy_pool.Init(pool)
 
-	Cookie, _ = test.NewJar()
-	defer test.DestroyJar(Cookie)
-
 	msg := notify.ToMessage()
 	// 验证在这种情况下不会panic，并且媒体元素为空
 	assert.Len(t, msg.Elements(), 3) // 只有文本元素存在
@@ -0,0 +1,44 @@
+package twitter
+
+import (
+	"errors"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+)
+
+// Preview 与刷新相同从镜像站获取用户主页中最新的推文，不会记录为已推送
+func (t *twitterConcern) Preview(target mmsg.Target, id interface{}, ctype concern_type.Type, limit int) ([]concern.Notify, error) {
+	if ctype != Tweets {
+		return nil, errors.New("该类型不支持预览")
+	}
+	userId := id.(string)
+	profile, tweets, err := fetchProfile(userId)
+	if err != nil {
+		return nil, err
+	}
+	// 不能使用 FindOrLoadUserInfo，预览没有订阅的用户时不应该保存用户信息
+	userInfo, err := t.GetUserInfo(userId)
+	if err != nil {
+		if profile == nil {
+			return nil, errors.New("用户不存在或返回结果为空")
+		}
+		userInfo = &UserInfo{
+			Id:   profile.ScreenName,
+			Name: profile.Name,
+		}
+	}
+	if len(tweets) > limit {
+		tweets = tweets[:limit]
+	}
+	var result []concern.Notify
+	// 主页上新的推文在前面，与推送相同按发布顺序排列
+	for i := len(tweets) - 1; i >= 0; i-- {
+		result = append(result, NewConcernNewsNotify(target, &NewsInfo{
+			UserInfo: userInfo,
+			Tweet:    tweets[i],
+		}, t))
+	}
+	return result, nil
+}
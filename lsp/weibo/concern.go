@@ -7,7 +7,6 @@ import (
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
-	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/tidwall/buntdb"
 	"strconv"
 	"time"
@@ -24,7 +23,7 @@ func (c *Concern) Site() string {
 }
 
 func (c *Concern) Types() []concern_type.Type {
-	return []concern_type.Type{News}
+	return []concern_type.Type{News, Reply}
 }
 
 func (c *Concern) ParseId(s string) (interface{}, error) {
@@ -39,18 +38,26 @@ func (c *Concern) Start() error {
 	c.UseEmitQueue()
 	c.StateManager.UseFreshFunc(c.EmitQueueFresher(func(p concern_type.Type, id interface{}) ([]concern.Event, error) {
 		uid := id.(int64)
+		var result []concern.Event
 		if p.ContainAny(News) {
 			newsInfo, err := c.freshNews(uid)
 			if err != nil {
 				return nil, err
 			}
-			if len(newsInfo.Cards) == 0 {
-				return nil, nil
+			if len(newsInfo.Cards) > 0 {
+				result = append(result, newsInfo)
+			}
+		}
+		if p.ContainAny(Reply) {
+			replyInfo, err := c.freshReply(uid)
+			if err != nil {
+				return nil, err
+			}
+			if len(replyInfo.Replies) > 0 {
+				result = append(result, replyInfo)
 			}
-			return []concern.Event{newsInfo}, nil
-
 		}
-		return nil, nil
+		return result, nil
 	}))
 	c.StateManager.UseNotifyGeneratorFunc(c.notifyGenerator())
 	return c.StateManager.Start()
@@ -64,11 +71,11 @@ func (c *Concern) Stop() {
 	logger.Tracef("%v concern已停止", Site)
 }
 
-func (c *Concern) Add(ctx mmsg.IMsgCtx, groupCode int64, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+func (c *Concern) Add(ctx mmsg.IMsgCtx, target mmsg.Target, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	id := _id.(int64)
-	log := logger.WithFields(localutils.GroupLogFields(groupCode)).WithField("id", id)
+	log := logger.WithFields(mmsg.TargetLogFields(target)).WithField("id", id)
 
-	err := c.StateManager.CheckGroupConcern(groupCode, id, ctype)
+	err := c.StateManager.CheckGroupConcern(target, id, ctype)
 	if err != nil {
 		return nil, err
 	}
@@ -99,17 +106,30 @@ func (c *Concern) Add(ctx mmsg.IMsgCtx, groupCode int64, _id interface{}, ctype
 			return nil, fmt.Errorf("添加订阅失败 - 内部错误")
 		}
 	}
-	_, err = c.StateManager.AddGroupConcern(groupCode, id, ctype)
+	if ctype.ContainAny(Reply) {
+		if _, err := c.GetReplyInfo(id); err != nil {
+			// 回复按 LatestReplyTs 刷新，从订阅时开始，之前的回复不推送
+			err = c.AddReplyInfo(&ReplyInfo{
+				UserInfo:      info,
+				LatestReplyTs: time.Now().Unix(),
+			})
+			if err != nil {
+				log.Errorf("AddReplyInfo error %v", err)
+				return nil, fmt.Errorf("添加订阅失败 - 内部错误")
+			}
+		}
+	}
+	_, err = c.StateManager.AddGroupConcern(target, id, ctype)
 	if err != nil {
 		return nil, err
 	}
 	return info, nil
 }
 
-func (c *Concern) Remove(ctx mmsg.IMsgCtx, groupCode int64, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+func (c *Concern) Remove(ctx mmsg.IMsgCtx, target mmsg.Target, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	id := _id.(int64)
 	identity, _ := c.Get(id)
-	_, err := c.StateManager.RemoveGroupConcern(groupCode, id, ctype)
+	_, err := c.StateManager.RemoveGroupConcern(target, id, ctype)
 	if identity == nil {
 		identity = concern.NewIdentity(_id, "unknown")
 	}
@@ -121,6 +141,12 @@ func (c *Concern) Remove(ctx mmsg.IMsgCtx, groupCode int64, _id interface{}, cty
 	if err != nil {
 		logger.Errorf("removeNewsInfo error %v", err)
 	}
+	if ctype.ContainAny(Reply) {
+		err = c.RemoveReplyInfo(id)
+		if err != nil {
+			logger.Errorf("removeReplyInfo error %v", err)
+		}
+	}
 	return identity, err
 }
 
@@ -163,6 +189,7 @@ func (c *Concern) freshNews(uid int64) (*NewsInfo, error) {
 			Errorf("ApiContainerGetIndexCards not ok")
 		return nil, errors.New("ApiContainerGetIndexCards not success")
 	}
+	checkDeletedNews(uid, cardResp.GetData().GetCards())
 	var lastTs int64
 	var newsInfo = &NewsInfo{UserInfo: userInfo}
 	oldNewsInfo, err := c.GetNewsInfo(uid)
@@ -202,16 +229,41 @@ func (c *Concern) freshNews(uid int64) (*NewsInfo, error) {
 	return newsInfo, nil
 }
 
+// checkDeletedNews 检测已推送的微博是否被删除，置顶微博不按时间排序，不参与计算检测范围
+func checkDeletedNews(uid int64, cards []*Card) {
+	var since time.Time
+	var ids []string
+	for _, card := range cards {
+		ids = append(ids, card.GetMblog().GetId())
+		if card.GetMblog().GetIsTop() == 1 {
+			continue
+		}
+		if t := mblogTime(card.GetMblog()); !t.IsZero() && (since.IsZero() || t.Before(since)) {
+			since = t
+		}
+	}
+	if since.IsZero() {
+		return
+	}
+	concern.CheckDeletedPost(Site, since, ids, func(id string) bool {
+		return id == strconv.FormatInt(uid, 10)
+	})
+}
+
 func (c *Concern) notifyGenerator() concern.NotifyGeneratorFunc {
-	return func(groupCode int64, ievent concern.Event) []concern.Notify {
+	return func(target mmsg.Target, ievent concern.Event) []concern.Notify {
 		var result []concern.Notify
 		switch news := ievent.(type) {
 		case *NewsInfo:
 			if len(news.Cards) > 0 {
-				for _, n := range NewConcernNewsNotify(groupCode, news) {
+				for _, n := range NewConcernNewsNotify(target, news) {
 					result = append(result, n)
 				}
 			}
+		case *ReplyInfo:
+			for _, n := range NewConcernReplyNotify(target, news) {
+				result = append(result, n)
+			}
 		}
 		return result
 	}
@@ -219,23 +271,11 @@ func (c *Concern) notifyGenerator() concern.NotifyGeneratorFunc {
 
 func (c *Concern) FindUserInfo(uid int64, load bool) (*UserInfo, error) {
 	if load {
-		profileResp, err := ApiContainerGetIndexProfile(uid)
+		info, err := loadUserInfo(uid)
 		if err != nil {
-			logger.WithField("uid", uid).Errorf("ApiContainerGetIndexProfile error %v", err)
 			return nil, err
 		}
-		if profileResp.GetOk() != 1 {
-			logger.WithField("respOk", profileResp.GetOk()).
-				WithField("respMsg", profileResp.GetMsg()).
-				Errorf("ApiContainerGetIndexProfile not ok")
-			return nil, errors.New("接口请求失败")
-		}
-		err = c.AddUserInfo(&UserInfo{
-			Uid:             uid,
-			Name:            profileResp.GetData().GetUserInfo().GetScreenName(),
-			ProfileImageUrl: profileResp.GetData().GetUserInfo().GetProfileImageUrl(),
-			ProfileUrl:      profileResp.GetData().GetUserInfo().GetProfileUrl(),
-		})
+		err = c.AddUserInfo(info)
 		if err != nil {
 			logger.WithField("uid", uid).Errorf("AddUserInfo error %v", err)
 		}
@@ -243,6 +283,27 @@ func (c *Concern) FindUserInfo(uid int64, load bool) (*UserInfo, error) {
 	return c.GetUserInfo(uid)
 }
 
+// loadUserInfo 从接口获取用户信息，不会保存
+func loadUserInfo(uid int64) (*UserInfo, error) {
+	profileResp, err := ApiContainerGetIndexProfile(uid)
+	if err != nil {
+		logger.WithField("uid", uid).Errorf("ApiContainerGetIndexProfile error %v", err)
+		return nil, err
+	}
+	if profileResp.GetOk() != 1 {
+		logger.WithField("respOk", profileResp.GetOk()).
+			WithField("respMsg", profileResp.GetMsg()).
+			Errorf("ApiContainerGetIndexProfile not ok")
+		return nil, errors.New("接口请求失败")
+	}
+	return &UserInfo{
+		Uid:             uid,
+		Name:            profileResp.GetData().GetUserInfo().GetScreenName(),
+		ProfileImageUrl: profileResp.GetData().GetUserInfo().GetProfileImageUrl(),
+		ProfileUrl:      profileResp.GetData().GetUserInfo().GetProfileUrl(),
+	}, nil
+}
+
 func (c *Concern) FindOrLoadUserInfo(uid int64) (*UserInfo, error) {
 	info, _ := c.FindUserInfo(uid, false)
 	if info == nil {
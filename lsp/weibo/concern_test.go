@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/cnxysoft/DDBOT-WSa/internal/test"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -45,7 +46,7 @@ func TestConcern(t *testing.T) {
 	defer c.Stop()
 	defer close(testEventChan)
 
-	_, err = c.Add(nil, test.G1, testId, News)
+	_, err = c.Add(nil, mmsg.NewGroupTarget(test.G1), testId, News)
 	assert.Nil(t, err)
 
 	identityInfo, err := c.Get(testId)
@@ -63,12 +64,12 @@ func TestConcern(t *testing.T) {
 
 	select {
 	case notify := <-testNotifyChan:
-		assert.Equal(t, test.G1, notify.GetGroupCode())
+		assert.Equal(t, mmsg.NewGroupTarget(test.G1), notify.GetTarget())
 		assert.Equal(t, testId, notify.GetUid())
 	case <-time.After(time.Second):
 		assert.Fail(t, "no notify received")
 	}
 
-	_, err = c.Remove(nil, test.G1, testId, News)
+	_, err = c.Remove(nil, mmsg.NewGroupTarget(test.G1), testId, News)
 	assert.Nil(t, err)
 }
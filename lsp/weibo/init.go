@@ -10,6 +10,7 @@ import (
 
 func init() {
 	concern.RegisterConcern(NewConcern(concern.GetNotifyChan()))
+	concern.RegisterConcern(NewTopicConcern(concern.GetNotifyChan()))
 
 	var cookies []*http.Cookie
 	var err error
@@ -15,3 +15,19 @@ func (*extraKeySet) NewsInfoKey(keys ...interface{}) string {
 func (*extraKeySet) MarkMblogIdKey(keys ...interface{}) string {
 	return localdb.WeiboMarkMblogIdKey(keys...)
 }
+
+func (*extraKeySet) ReplyInfoKey(keys ...interface{}) string {
+	return localdb.WeiboReplyInfoKey(keys...)
+}
+
+func (*extraKeySet) MarkCommentIdKey(keys ...interface{}) string {
+	return localdb.WeiboMarkCommentIdKey(keys...)
+}
+
+func (*extraKeySet) TopicInfoKey(keys ...interface{}) string {
+	return localdb.WeiboTopicInfoKey(keys...)
+}
+
+func (*extraKeySet) MarkTopicMblogIdKey(keys ...interface{}) string {
+	return localdb.WeiboMarkTopicMblogIdKey(keys...)
+}
@@ -7,4 +7,8 @@ func TestExtraKey(t *testing.T) {
 	e.NewsInfoKey()
 	e.MarkMblogIdKey()
 	e.UserInfoKey()
+	e.ReplyInfoKey()
+	e.MarkCommentIdKey()
+	e.TopicInfoKey()
+	e.MarkTopicMblogIdKey()
 }
@@ -4,11 +4,12 @@ import (
 	"html"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
 	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/sirupsen/logrus"
 )
@@ -62,7 +63,7 @@ func (n *NewsInfo) Logger() *logrus.Entry {
 }
 
 type ConcernNewsNotify struct {
-	GroupCode int64 `json:"group_code"`
+	Target mmsg.Target `json:"target"`
 	*UserInfo
 	Card *CacheCard
 }
@@ -71,25 +72,40 @@ func (c *ConcernNewsNotify) Type() concern_type.Type {
 	return News
 }
 
-func (c *ConcernNewsNotify) GetGroupCode() int64 {
-	return c.GroupCode
+func (c *ConcernNewsNotify) GetTarget() mmsg.Target {
+	return c.Target
 }
 
 func (c *ConcernNewsNotify) Logger() *logrus.Entry {
-	return c.UserInfo.Logger().WithFields(localutils.GroupLogFields(c.GroupCode))
+	return c.UserInfo.Logger().WithFields(mmsg.TargetLogFields(c.Target))
 }
 
 func (c *ConcernNewsNotify) ToMessage() (m *mmsg.MSG) {
-	return c.Card.GetMSG()
+	return c.Card.GetMSG(template.NotifyLocale(c.Target))
 }
 
-func NewConcernNewsNotify(groupCode int64, info *NewsInfo) []*ConcernNewsNotify {
+// PostId 返回微博id，用于检测微博是否被删除
+func (c *ConcernNewsNotify) PostId() string {
+	return c.Card.GetMblog().GetId()
+}
+
+func (c *ConcernNewsNotify) PostTime() time.Time {
+	return mblogTime(c.Card.GetMblog())
+}
+
+// mblogTime 返回微博的发布时间，无法解析时返回零值
+func mblogTime(mblog *Card_Mblog) time.Time {
+	t, _ := time.Parse(time.RubyDate, mblog.GetCreatedAt())
+	return t
+}
+
+func NewConcernNewsNotify(target mmsg.Target, info *NewsInfo) []*ConcernNewsNotify {
 	var result []*ConcernNewsNotify
 	for _, card := range info.Cards {
 		result = append(result, &ConcernNewsNotify{
-			GroupCode: groupCode,
-			UserInfo:  info.UserInfo,
-			Card:      NewCacheCard(card, info.GetName()),
+			Target:   target,
+			UserInfo: info.UserInfo,
+			Card:     NewCacheCard(card, info.GetName()),
 		})
 	}
 	return result
@@ -99,15 +115,14 @@ type CacheCard struct {
 	*Card
 	Name string
 
-	once     sync.Once
-	msgCache *mmsg.MSG
+	msgCache template.MsgCache
 }
 
 func NewCacheCard(card *Card, name string) *CacheCard {
 	return &CacheCard{Card: card, Name: name}
 }
 
-func (c *CacheCard) prepare() {
+func (c *CacheCard) prepare(locale string) *mmsg.MSG {
 	m := mmsg.NewMSG()
 	var createdTime string
 	newsTime, err := time.Parse(time.RubyDate, c.Card.GetMblog().GetCreatedAt())
@@ -117,16 +132,16 @@ func (c *CacheCard) prepare() {
 		createdTime = c.Card.GetMblog().GetCreatedAt()
 	}
 	if c.Card.GetMblog().GetRetweetedStatus() != nil {
-		m.Textf("weibo-%v转发了%v的微博：\n%v",
+		m.Text(i18n.Sprintf(locale, "weibo-%v转发了%v的微博：\n%v",
 			c.Name,
 			c.Card.GetMblog().GetRetweetedStatus().GetUser().GetScreenName(),
 			createdTime,
-		)
+		))
 	} else {
-		m.Textf("weibo-%v发布了新微博：\n%v",
+		m.Text(i18n.Sprintf(locale, "weibo-%v发布了新微博：\n%v",
 			c.Name,
 			createdTime,
-		)
+		))
 	}
 	switch c.Card.GetCardType() {
 	case CardType_Normal:
@@ -161,10 +176,10 @@ func (c *CacheCard) prepare() {
 		if c.Card.GetMblog().GetRetweetedStatus() != nil {
 			if len(c.Card.GetMblog().GetRetweetedStatus().GetRawText()) > 0 {
 				rawText := parseHTML(c.Card.GetMblog().GetRetweetedStatus().GetRawText())
-				m.Textf("\n\n原微博：\n%v", localutils.RemoveHtmlTag(rawText))
+				m.Text(i18n.Sprintf(locale, "\n\n原微博：\n%v", localutils.RemoveHtmlTag(rawText)))
 			} else {
 				Text := parseHTML(c.Card.GetMblog().GetRetweetedStatus().GetText())
-				m.Textf("\n\n原微博：\n%v", localutils.RemoveHtmlTag(Text))
+				m.Text(i18n.Sprintf(locale, "\n\n原微博：\n%v", localutils.RemoveHtmlTag(Text)))
 			}
 			for _, pic := range c.Card.GetMblog().GetRetweetedStatus().GetPics() {
 				if pic.GetType() == "video" && !firstVideoPic {
@@ -191,12 +206,13 @@ func (c *CacheCard) prepare() {
 		logger.WithField("Type", c.CardType.String()).Debug("found new card_types")
 	}
 	m.Textf("\n%s", createWeiboUrl(c.Card.GetMblog().GetUser().GetId(), c.Card.GetMblog().GetBid()))
-	c.msgCache = m
+	return m
 }
 
-func (c *CacheCard) GetMSG() *mmsg.MSG {
-	c.once.Do(c.prepare)
-	return c.msgCache
+func (c *CacheCard) GetMSG(locale string) *mmsg.MSG {
+	return c.msgCache.Get(locale, func() *mmsg.MSG {
+		return c.prepare(locale)
+	})
 }
 
 func parseHTML(text string) string {
@@ -0,0 +1,81 @@
+package weibo
+
+import (
+	"errors"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+)
+
+// Preview 获取最新的微博，不会标记为已推送
+func (c *Concern) Preview(target mmsg.Target, id interface{}, ctype concern_type.Type, limit int) ([]concern.Notify, error) {
+	if ctype != News {
+		return nil, errors.New("该类型不支持预览")
+	}
+	uid := id.(int64)
+	// 不能使用 FindOrLoadUserInfo，预览没有订阅的用户时不应该保存用户信息
+	userInfo, err := c.GetUserInfo(uid)
+	if err != nil {
+		userInfo, err = loadUserInfo(uid)
+		if err != nil {
+			return nil, err
+		}
+	}
+	cardResp, err := ApiContainerGetIndexCards(uid)
+	if err != nil {
+		return nil, err
+	}
+	if cardResp.GetOk() != 1 {
+		return nil, errors.New("ApiContainerGetIndexCards not success")
+	}
+	var newsInfo = &NewsInfo{UserInfo: userInfo}
+	for _, card := range cardResp.GetData().GetCards() {
+		if len(newsInfo.Cards) >= limit {
+			break
+		}
+		if card.GetMblog() == nil {
+			continue
+		}
+		newsInfo.Cards = append(newsInfo.Cards, card)
+	}
+	var result []concern.Notify
+	for _, n := range NewConcernNewsNotify(target, newsInfo) {
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+// Preview 获取超话最新的帖子，不会标记为已推送
+func (c *TopicConcern) Preview(target mmsg.Target, id interface{}, ctype concern_type.Type, limit int) ([]concern.Notify, error) {
+	if ctype != News {
+		return nil, errors.New("该类型不支持预览")
+	}
+	containerId := id.(string)
+	resp, err := ApiContainerGetIndexTopic(containerId)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Ok != 1 {
+		return nil, errors.New("ApiContainerGetIndexTopic not success")
+	}
+	topicInfo, err := c.GetTopicInfo(containerId)
+	if err != nil {
+		topicInfo = &TopicInfo{ContainerId: containerId}
+	}
+	if title := resp.Data.PageInfo.PageTitle; title != "" {
+		topicInfo.Name = title
+	}
+	var newsInfo = &TopicNewsInfo{TopicInfo: topicInfo}
+	for _, mblog := range resp.Mblogs() {
+		if len(newsInfo.Mblogs) >= limit {
+			break
+		}
+		newsInfo.Mblogs = append(newsInfo.Mblogs, mblog)
+	}
+	var result []concern.Notify
+	for _, n := range NewConcernTopicNotify(target, newsInfo) {
+		result = append(result, n)
+	}
+	return result, nil
+}
@@ -0,0 +1,282 @@
+package weibo
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
+	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
+	"github.com/cnxysoft/DDBOT-WSa/requests"
+	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
+	"github.com/guonaihong/gout"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	Reply concern_type.Type = "reply"
+)
+
+const (
+	PathCommentsHotflow = "https://m.weibo.cn/comments/hotflow"
+	// 每次刷新检查最近几条微博下的评论
+	replyCheckMblogCount = 3
+)
+
+// flexString 微博接口中的id有时是字符串有时是数字
+type flexString string
+
+func (f *flexString) UnmarshalJSON(b []byte) error {
+	*f = flexString(bytes.Trim(b, `"`))
+	return nil
+}
+
+type Comment struct {
+	Id        flexString                                         `json:"id"`
+	CreatedAt string                                             `json:"created_at"`
+	Text      string                                             `json:"text"`
+	User      *ApiContainerGetIndexProfileResponse_Data_UserInfo `json:"user"`
+	// Comments 楼中楼回复，没有回复时接口返回 false
+	Comments jsoniter.RawMessage `json:"comments"`
+}
+
+// SubComments 返回楼中楼回复，热门评论接口只会返回其中一部分
+func (c *Comment) SubComments() []*Comment {
+	var result []*Comment
+	if len(c.Comments) == 0 || c.Comments[0] != '[' {
+		return nil
+	}
+	if err := json.Unmarshal(c.Comments, &result); err != nil {
+		logger.WithField("CommentId", c.Id).Errorf("unmarshal sub comments error %v", err)
+		return nil
+	}
+	return result
+}
+
+type ApiCommentsHotflowResponse struct {
+	Ok   int32  `json:"ok"`
+	Msg  string `json:"msg"`
+	Data struct {
+		Data  []*Comment `json:"data"`
+		MaxId int64      `json:"max_id"`
+	} `json:"data"`
+}
+
+func ApiCommentsHotflow(mid string) (*ApiCommentsHotflowResponse, error) {
+	st := time.Now()
+	defer func() {
+		ed := time.Now()
+		logger.WithField("FuncName", localutils.FuncName()).Tracef("cost %v", ed.Sub(st))
+	}()
+	params := gout.H{
+		"id":          mid,
+		"mid":         mid,
+		"max_id_type": 0,
+	}
+	var opts []requests.Option
+	opts = append(opts,
+		requests.ProxyOption(proxy_pool.PreferNone),
+		requests.AddUAOption(),
+		requests.TimeoutOption(time.Second*10),
+	)
+	opts = append(opts, CookieOption()...)
+	resp := new(ApiCommentsHotflowResponse)
+	err := requests.Get(PathCommentsHotflow, params, resp, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// AuthorReply 作者在自己微博评论区发的评论
+type AuthorReply struct {
+	Comment *Comment
+	// ReplyTo 回复的评论，直接评论微博时为 nil
+	ReplyTo *Comment
+	Mblog   *Card_Mblog
+}
+
+// findAuthorReplies 找出评论区中 uid 发的评论和楼中楼回复
+func findAuthorReplies(uid int64, mblog *Card_Mblog, comments []*Comment) []*AuthorReply {
+	var result []*AuthorReply
+	for _, comment := range comments {
+		if comment.User.GetId() == uid {
+			result = append(result, &AuthorReply{Comment: comment, Mblog: mblog})
+		}
+		for _, sub := range comment.SubComments() {
+			if sub.User.GetId() == uid {
+				result = append(result, &AuthorReply{Comment: sub, ReplyTo: comment, Mblog: mblog})
+			}
+		}
+	}
+	return result
+}
+
+type ReplyInfo struct {
+	*UserInfo
+	LatestReplyTs int64          `json:"latest_reply_time"`
+	Replies       []*AuthorReply `json:"-"`
+}
+
+func (r *ReplyInfo) Type() concern_type.Type {
+	return Reply
+}
+
+func (r *ReplyInfo) Logger() *logrus.Entry {
+	return r.UserInfo.Logger().WithFields(logrus.Fields{
+		"Type":      r.Type().String(),
+		"ReplySize": len(r.Replies),
+	})
+}
+
+type ConcernReplyNotify struct {
+	Target mmsg.Target `json:"target"`
+	*UserInfo
+	Reply *AuthorReply
+
+	once     sync.Once
+	msgCache *mmsg.MSG
+}
+
+func (c *ConcernReplyNotify) Type() concern_type.Type {
+	return Reply
+}
+
+func (c *ConcernReplyNotify) GetTarget() mmsg.Target {
+	return c.Target
+}
+
+func (c *ConcernReplyNotify) Logger() *logrus.Entry {
+	return c.UserInfo.Logger().WithFields(mmsg.TargetLogFields(c.Target)).
+		WithField("CommentId", c.Reply.Comment.Id)
+}
+
+func (c *ConcernReplyNotify) ToMessage() *mmsg.MSG {
+	c.once.Do(func() {
+		// 原微博只保留开头
+		mblogText := []rune(cleanText(c.Reply.Mblog.GetText()))
+		if len(mblogText) > 50 {
+			mblogText = append(mblogText[:50], []rune("...")...)
+		}
+		var data = map[string]interface{}{
+			"uid":   c.Uid,
+			"name":  c.GetName(),
+			"date":  formatCreatedAt(c.Reply.Comment.CreatedAt),
+			"text":  cleanText(c.Reply.Comment.Text),
+			"mblog": string(mblogText),
+			"url":   createWeiboUrl(c.Uid, c.Reply.Mblog.GetBid()),
+		}
+		if c.Reply.ReplyTo != nil {
+			data["reply_to"] = c.Reply.ReplyTo.User.GetScreenName()
+			data["reply_to_text"] = cleanText(c.Reply.ReplyTo.Text)
+		}
+		var err error
+		c.msgCache, err = template.ExecNotify(template.NotifyLocale(c.Target), "notify.group.weibo.reply.tmpl", data)
+		if err != nil {
+			logger.Errorf("weibo: ConcernReplyNotify LoadAndExec error %v", err)
+		}
+	})
+	return c.msgCache
+}
+
+func NewConcernReplyNotify(target mmsg.Target, info *ReplyInfo) []*ConcernReplyNotify {
+	var result []*ConcernReplyNotify
+	for _, reply := range info.Replies {
+		result = append(result, &ConcernReplyNotify{
+			Target:   target,
+			UserInfo: info.UserInfo,
+			Reply:    reply,
+		})
+	}
+	return result
+}
+
+// freshReply 检查最近几条微博下作者的新回复，第一次刷新时只记录时间
+func (c *Concern) freshReply(uid int64) (*ReplyInfo, error) {
+	log := logger.WithField("uid", uid).WithField("Type", Reply.String())
+	userInfo, err := c.FindOrLoadUserInfo(uid)
+	if err != nil {
+		return nil, err
+	}
+	var replyInfo = &ReplyInfo{UserInfo: userInfo}
+	oldReplyInfo, err := c.GetReplyInfo(uid)
+	if err != nil {
+		replyInfo.LatestReplyTs = time.Now().Unix()
+		return replyInfo, c.AddReplyInfo(replyInfo)
+	}
+	replyInfo.LatestReplyTs = oldReplyInfo.LatestReplyTs
+	var lastTs = oldReplyInfo.LatestReplyTs
+
+	cardResp, err := ApiContainerGetIndexCards(uid)
+	if err != nil {
+		return nil, err
+	}
+	if cardResp.GetOk() != 1 {
+		log.WithField("respOk", cardResp.GetOk()).
+			WithField("respMsg", cardResp.GetMsg()).
+			Errorf("ApiContainerGetIndexCards not ok")
+		return nil, errors.New("ApiContainerGetIndexCards not success")
+	}
+	var count int
+	for _, card := range cardResp.GetData().GetCards() {
+		mblog := card.GetMblog()
+		if mblog.GetId() == "" {
+			continue
+		}
+		if count >= replyCheckMblogCount {
+			break
+		}
+		count++
+		commentResp, err := ApiCommentsHotflow(mblog.GetId())
+		if err != nil {
+			log.WithField("mblogId", mblog.GetId()).Errorf("ApiCommentsHotflow error %v", err)
+			continue
+		}
+		// 没有评论时 ok 为 0
+		if commentResp.Ok != 1 {
+			continue
+		}
+		for _, reply := range findAuthorReplies(uid, mblog, commentResp.Data.Data) {
+			t, err := time.Parse(time.RubyDate, reply.Comment.CreatedAt)
+			if err != nil {
+				log.WithField("time_string", reply.Comment.CreatedAt).
+					Errorf("can not parse Comment.CreatedAt %v", err)
+				continue
+			}
+			if t.Unix() <= lastTs {
+				continue
+			}
+			replaced, err := c.MarkCommentId(string(reply.Comment.Id))
+			if err != nil || replaced {
+				if err != nil {
+					log.WithField("commentId", reply.Comment.Id).Errorf("MarkCommentId error %v", err)
+				}
+				continue
+			}
+			replyInfo.Replies = append(replyInfo.Replies, reply)
+			if t.Unix() > replyInfo.LatestReplyTs {
+				replyInfo.LatestReplyTs = t.Unix()
+			}
+		}
+	}
+	if err = c.AddReplyInfo(replyInfo); err != nil {
+		return nil, err
+	}
+	return replyInfo, nil
+}
+
+func formatCreatedAt(createdAt string) string {
+	t, err := time.Parse(time.RubyDate, createdAt)
+	if err != nil {
+		return createdAt
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func cleanText(text string) string {
+	return localutils.RemoveHtmlTag(parseHTML(text))
+}
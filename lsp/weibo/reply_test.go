@@ -0,0 +1,95 @@
+package weibo
+
+import (
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const testHotflow = `{
+	"ok": 1,
+	"data": {
+		"data": [
+			{
+				"id": 5001,
+				"created_at": "Mon Jan 02 15:04:05 +0800 2006",
+				"text": "第一条评论",
+				"user": {"id": 2, "screen_name": "fan"},
+				"comments": [
+					{
+						"id": "5002",
+						"created_at": "Mon Jan 02 16:04:05 +0800 2006",
+						"text": "回复<a href=\"/n/fan\">@fan</a>:谢谢",
+						"user": {"id": 1, "screen_name": "author"},
+						"comments": false
+					},
+					{
+						"id": "5003",
+						"created_at": "Mon Jan 02 16:05:05 +0800 2006",
+						"text": "路人",
+						"user": {"id": 3, "screen_name": "other"}
+					}
+				]
+			},
+			{
+				"id": "5004",
+				"created_at": "Mon Jan 02 17:04:05 +0800 2006",
+				"text": "作者自己的评论",
+				"user": {"id": 1, "screen_name": "author"},
+				"comments": false
+			}
+		],
+		"max_id": 0
+	}
+}`
+
+func TestFindAuthorReplies(t *testing.T) {
+	var resp = new(ApiCommentsHotflowResponse)
+	require.Nil(t, json.Unmarshal([]byte(testHotflow), resp))
+	assert.EqualValues(t, 1, resp.Ok)
+	require.Len(t, resp.Data.Data, 2)
+	assert.EqualValues(t, "5001", resp.Data.Data[0].Id)
+	assert.Len(t, resp.Data.Data[0].SubComments(), 2)
+	assert.Nil(t, resp.Data.Data[1].SubComments())
+
+	mblog := &Card_Mblog{Id: "100", Bid: "abc"}
+	replies := findAuthorReplies(1, mblog, resp.Data.Data)
+	require.Len(t, replies, 2)
+	assert.EqualValues(t, "5002", replies[0].Comment.Id)
+	assert.EqualValues(t, "5001", replies[0].ReplyTo.Id)
+	assert.EqualValues(t, "5004", replies[1].Comment.Id)
+	assert.Nil(t, replies[1].ReplyTo)
+	assert.Equal(t, mblog, replies[1].Mblog)
+
+	assert.Empty(t, findAuthorReplies(4, mblog, resp.Data.Data))
+}
+
+func TestConcernReplyNotify(t *testing.T) {
+	var resp = new(ApiCommentsHotflowResponse)
+	require.Nil(t, json.Unmarshal([]byte(testHotflow), resp))
+	info := &ReplyInfo{
+		UserInfo: &UserInfo{Uid: 1, Name: test.NAME1},
+		Replies:  findAuthorReplies(1, &Card_Mblog{Id: "100", Bid: "abc", Text: "原微博内容"}, resp.Data.Data),
+	}
+	assert.EqualValues(t, Reply, info.Type())
+	assert.NotNil(t, info.Logger())
+
+	notifies := NewConcernReplyNotify(mmsg.NewGroupTarget(test.G1), info)
+	require.Len(t, notifies, 2)
+	for _, notify := range notifies {
+		assert.Equal(t, mmsg.NewGroupTarget(test.G1), notify.GetTarget())
+		assert.EqualValues(t, Reply, notify.Type())
+		assert.NotNil(t, notify.Logger())
+		assert.NotNil(t, notify.ToMessage())
+	}
+	m := msgstringer.MsgToString(notifies[0].ToMessage().Elements())
+	assert.Contains(t, m, "weibo-"+test.NAME1+"回复了")
+	assert.Contains(t, m, "原评论：")
+	assert.Contains(t, m, "原微博：\n原微博内容\n"+createWeiboUrl(1, "abc"))
+	m = msgstringer.MsgToString(notifies[1].ToMessage().Elements())
+	assert.Contains(t, m, "weibo-"+test.NAME1+"评论了自己的微博：")
+	assert.NotContains(t, m, "原评论：")
+}
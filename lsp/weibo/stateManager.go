@@ -78,3 +78,64 @@ func (s *StateManager) RemoveNewsInfo(uid int64) error {
 	}
 	return nil
 }
+
+func (s *StateManager) AddReplyInfo(info *ReplyInfo) error {
+	if info == nil {
+		return errors.New("<nil replyInfo>")
+	}
+	return s.SetJson(s.ReplyInfoKey(info.Uid), info)
+}
+
+func (s *StateManager) GetReplyInfo(uid int64) (*ReplyInfo, error) {
+	var replyInfo *ReplyInfo
+	err := s.GetJson(s.ReplyInfoKey(uid), &replyInfo)
+	if err != nil {
+		return nil, err
+	}
+	return replyInfo, nil
+}
+
+func (s *StateManager) RemoveReplyInfo(uid int64) error {
+	_, err := s.Delete(s.ReplyInfoKey(uid), localdb.IgnoreNotFoundOpt())
+	return err
+}
+
+func (s *StateManager) MarkCommentId(commentId string) (replaced bool, err error) {
+	err = s.Set(s.MarkCommentIdKey(commentId), "",
+		localdb.SetExpireOpt(time.Hour*120), localdb.SetGetIsOverwriteOpt(&replaced))
+	return
+}
+
+// NewTopicStateManager 超话使用单独的site和string类型的id
+func NewTopicStateManager(notify chan<- concern.Notify) *StateManager {
+	return &StateManager{
+		StateManager: concern.NewStateManagerWithStringID(TopicSite, notify),
+	}
+}
+
+func (s *StateManager) AddTopicInfo(info *TopicInfo) error {
+	if info == nil {
+		return errors.New("<nil topicInfo>")
+	}
+	return s.SetJson(s.TopicInfoKey(info.ContainerId), info)
+}
+
+func (s *StateManager) GetTopicInfo(containerId string) (*TopicInfo, error) {
+	var topicInfo *TopicInfo
+	err := s.GetJson(s.TopicInfoKey(containerId), &topicInfo)
+	if err != nil {
+		return nil, err
+	}
+	return topicInfo, nil
+}
+
+func (s *StateManager) RemoveTopicInfo(containerId string) error {
+	_, err := s.Delete(s.TopicInfoKey(containerId), localdb.IgnoreNotFoundOpt())
+	return err
+}
+
+func (s *StateManager) MarkTopicMblogId(containerId string, mblogId string) (replaced bool, err error) {
+	err = s.Set(s.MarkTopicMblogIdKey(containerId, mblogId), "",
+		localdb.SetExpireOpt(time.Hour*120), localdb.SetGetIsOverwriteOpt(&replaced))
+	return
+}
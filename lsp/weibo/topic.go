@@ -0,0 +1,372 @@
+package weibo
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
+	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
+	"github.com/cnxysoft/DDBOT-WSa/requests"
+	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// TopicSite 超话和微博用户分开订阅，命令中使用 chaohua 或者 chao 之类的前缀
+	TopicSite = "chaohua"
+
+	PathContainerGetIndex_Topic = "https://m.weibo.cn/api/container/getIndex"
+	// 超话的containerid是 100808 加上32位的十六进制
+	topicContainerPrefix = "100808"
+)
+
+var topicIdRegex = regexp.MustCompile(`^(?:100808)?([0-9a-f]{32})$`)
+
+// ParseTopicId 解析超话id，支持带或不带 100808 前缀的形式
+func ParseTopicId(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	sub := topicIdRegex.FindStringSubmatch(s)
+	if sub == nil {
+		return "", errors.New("超话id格式错误")
+	}
+	return topicContainerPrefix + sub[1], nil
+}
+
+// TopicCard 超话页面的卡片，帖子在 card_type 为9的卡片中，也可能在 card_group 中
+type TopicCard struct {
+	CardType  int32        `json:"card_type"`
+	Mblog     *Card_Mblog  `json:"mblog"`
+	CardGroup []*TopicCard `json:"card_group"`
+}
+
+type ApiContainerGetIndexTopicResponse struct {
+	Ok   int32  `json:"ok"`
+	Msg  string `json:"msg"`
+	Data struct {
+		PageInfo struct {
+			PageTitle string `json:"page_title"`
+		} `json:"pageInfo"`
+		Cards []*TopicCard `json:"cards"`
+	} `json:"data"`
+}
+
+// Mblogs 按页面顺序返回所有帖子
+func (r *ApiContainerGetIndexTopicResponse) Mblogs() []*Card_Mblog {
+	var result []*Card_Mblog
+	var walk func(cards []*TopicCard)
+	walk = func(cards []*TopicCard) {
+		for _, card := range cards {
+			if card.CardType == int32(CardType_Normal) && card.Mblog.GetId() != "" {
+				result = append(result, card.Mblog)
+			}
+			walk(card.CardGroup)
+		}
+	}
+	walk(r.Data.Cards)
+	return result
+}
+
+func ApiContainerGetIndexTopic(containerId string) (*ApiContainerGetIndexTopicResponse, error) {
+	st := time.Now()
+	defer func() {
+		ed := time.Now()
+		logger.WithField("FuncName", localutils.FuncName()).Tracef("cost %v", ed.Sub(st))
+	}()
+	// 按发帖时间排序
+	path := PathContainerGetIndex_Topic + "?containerid=" + containerId + "_-_sort_time"
+	var opts []requests.Option
+	opts = append(opts,
+		requests.ProxyOption(proxy_pool.PreferNone),
+		requests.AddUAOption(),
+		requests.TimeoutOption(time.Second*10),
+	)
+	opts = append(opts, CookieOption()...)
+	resp := new(ApiContainerGetIndexTopicResponse)
+	err := requests.Get(path, nil, resp, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+type TopicInfo struct {
+	ContainerId  string `json:"containerid"`
+	Name         string `json:"name"`
+	LatestNewsTs int64  `json:"latest_news_time"`
+}
+
+func (t *TopicInfo) Site() string {
+	return TopicSite
+}
+
+func (t *TopicInfo) GetUid() interface{} {
+	return t.ContainerId
+}
+
+func (t *TopicInfo) GetName() string {
+	return t.Name
+}
+
+func (t *TopicInfo) Logger() *logrus.Entry {
+	return logger.WithFields(logrus.Fields{
+		"Site":        TopicSite,
+		"ContainerId": t.ContainerId,
+		"Name":        t.Name,
+	})
+}
+
+type TopicNewsInfo struct {
+	*TopicInfo
+	Mblogs []*Card_Mblog `json:"-"`
+}
+
+func (t *TopicNewsInfo) Type() concern_type.Type {
+	return News
+}
+
+func (t *TopicNewsInfo) Logger() *logrus.Entry {
+	return t.TopicInfo.Logger().WithFields(logrus.Fields{
+		"Type":      t.Type().String(),
+		"MblogSize": len(t.Mblogs),
+	})
+}
+
+type ConcernTopicNotify struct {
+	Target mmsg.Target `json:"target"`
+	*TopicInfo
+	Card *CacheCard
+
+	once     sync.Once
+	msgCache *mmsg.MSG
+}
+
+func (c *ConcernTopicNotify) Type() concern_type.Type {
+	return News
+}
+
+func (c *ConcernTopicNotify) GetTarget() mmsg.Target {
+	return c.Target
+}
+
+func (c *ConcernTopicNotify) Logger() *logrus.Entry {
+	return c.TopicInfo.Logger().WithFields(mmsg.TargetLogFields(c.Target)).
+		WithField("MblogId", c.Card.GetMblog().GetId())
+}
+
+func (c *ConcernTopicNotify) ToMessage() *mmsg.MSG {
+	c.once.Do(func() {
+		// 模板的内容会加在帖子内容的前面
+		locale := template.NotifyLocale(c.Target)
+		m, err := template.ExecNotify(locale, "notify.group.chaohua.news.tmpl", map[string]interface{}{
+			"id":   c.ContainerId,
+			"name": c.Name,
+			"user": c.Card.Name,
+		})
+		if err != nil {
+			logger.Errorf("weibo: ConcernTopicNotify LoadAndExec error %v", err)
+			m = mmsg.NewMSG()
+		}
+		c.msgCache = m.Append(c.Card.GetMSG(locale).Elements()...)
+	})
+	return c.msgCache
+}
+
+func NewConcernTopicNotify(target mmsg.Target, info *TopicNewsInfo) []*ConcernTopicNotify {
+	var result []*ConcernTopicNotify
+	for _, mblog := range info.Mblogs {
+		card := &Card{CardType: CardType_Normal, Mblog: mblog}
+		result = append(result, &ConcernTopicNotify{
+			Target:    target,
+			TopicInfo: info.TopicInfo,
+			Card:      NewCacheCard(card, mblog.GetUser().GetScreenName()),
+		})
+	}
+	return result
+}
+
+type TopicConcern struct {
+	*StateManager
+}
+
+func (c *TopicConcern) Site() string {
+	return TopicSite
+}
+
+func (c *TopicConcern) Types() []concern_type.Type {
+	return []concern_type.Type{News}
+}
+
+func (c *TopicConcern) ParseId(s string) (interface{}, error) {
+	return ParseTopicId(s)
+}
+
+func (c *TopicConcern) GetStateManager() concern.IStateManager {
+	return c.StateManager
+}
+
+func (c *TopicConcern) Start() error {
+	c.UseEmitQueue()
+	c.StateManager.UseFreshFunc(c.EmitQueueFresher(func(p concern_type.Type, id interface{}) ([]concern.Event, error) {
+		containerId := id.(string)
+		if p.ContainAny(News) {
+			newsInfo, err := c.freshTopic(containerId)
+			if err != nil {
+				return nil, err
+			}
+			if len(newsInfo.Mblogs) == 0 {
+				return nil, nil
+			}
+			return []concern.Event{newsInfo}, nil
+		}
+		return nil, nil
+	}))
+	c.StateManager.UseNotifyGeneratorFunc(c.notifyGenerator())
+	return c.StateManager.Start()
+}
+
+func (c *TopicConcern) Stop() {
+	logger.Tracef("正在停止%v concern", TopicSite)
+	c.StateManager.Stop()
+	logger.Tracef("%v concern已停止", TopicSite)
+}
+
+func (c *TopicConcern) Add(ctx mmsg.IMsgCtx, target mmsg.Target, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+	id := _id.(string)
+	log := logger.WithFields(mmsg.TargetLogFields(target)).WithField("containerid", id)
+
+	err := c.StateManager.CheckGroupConcern(target, id, ctype)
+	if err != nil {
+		return nil, err
+	}
+	info, err := c.GetTopicInfo(id)
+	if err != nil {
+		resp, err := ApiContainerGetIndexTopic(id)
+		if err != nil {
+			log.Errorf("ApiContainerGetIndexTopic error %v", err)
+			return nil, fmt.Errorf("添加订阅失败 - 刷新超话失败")
+		}
+		if resp.Ok != 1 || resp.Data.PageInfo.PageTitle == "" {
+			log.WithField("respOk", resp.Ok).
+				WithField("respMsg", resp.Msg).
+				Errorf("ApiContainerGetIndexTopic not ok")
+			return nil, fmt.Errorf("添加订阅失败 - 无法查看超话，请检查超话id")
+		}
+		// 超话里已有的帖子不推送，从订阅时开始刷新
+		info = &TopicInfo{
+			ContainerId:  id,
+			Name:         resp.Data.PageInfo.PageTitle,
+			LatestNewsTs: time.Now().Unix(),
+		}
+		if err = c.AddTopicInfo(info); err != nil {
+			log.Errorf("AddTopicInfo error %v", err)
+			return nil, fmt.Errorf("添加订阅失败 - 内部错误")
+		}
+	}
+	_, err = c.StateManager.AddGroupConcern(target, id, ctype)
+	if err != nil {
+		return nil, err
+	}
+	return concern.NewIdentity(id, info.Name), nil
+}
+
+func (c *TopicConcern) Remove(ctx mmsg.IMsgCtx, target mmsg.Target, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+	id := _id.(string)
+	identity, _ := c.Get(id)
+	if identity == nil {
+		identity = concern.NewIdentity(id, "unknown")
+	}
+	_, err := c.StateManager.RemoveGroupConcern(target, id, ctype)
+	if err != nil {
+		return identity, err
+	}
+	if r, _ := c.GetConcern(id); r.Empty() {
+		if err := c.RemoveTopicInfo(id); err != nil {
+			logger.Errorf("RemoveTopicInfo error %v", err)
+		}
+	}
+	return identity, nil
+}
+
+func (c *TopicConcern) Get(id interface{}) (concern.IdentityInfo, error) {
+	info, err := c.GetTopicInfo(id.(string))
+	if err != nil {
+		return nil, err
+	}
+	return concern.NewIdentity(info.ContainerId, info.Name), nil
+}
+
+func (c *TopicConcern) freshTopic(containerId string) (*TopicNewsInfo, error) {
+	log := logger.WithField("containerid", containerId)
+	resp, err := ApiContainerGetIndexTopic(containerId)
+	if err != nil {
+		log.Errorf("ApiContainerGetIndexTopic error %v", err)
+		return nil, err
+	}
+	if resp.Ok != 1 {
+		log.WithField("respOk", resp.Ok).
+			WithField("respMsg", resp.Msg).
+			Errorf("ApiContainerGetIndexTopic not ok")
+		return nil, errors.New("ApiContainerGetIndexTopic not success")
+	}
+	topicInfo, err := c.GetTopicInfo(containerId)
+	if err != nil {
+		topicInfo = &TopicInfo{
+			ContainerId:  containerId,
+			LatestNewsTs: time.Now().Unix(),
+		}
+	}
+	if title := resp.Data.PageInfo.PageTitle; title != "" {
+		topicInfo.Name = title
+	}
+	var newsInfo = &TopicNewsInfo{TopicInfo: topicInfo}
+	newsInfo.Mblogs, newsInfo.LatestNewsTs = concern.FilterNews(resp.Mblogs(), topicInfo.LatestNewsTs, mblogPostTime, func(mblog *Card_Mblog) bool {
+		replaced, err := c.MarkTopicMblogId(containerId, mblog.GetId())
+		if err != nil {
+			log.WithField("mblogId", mblog.GetId()).Errorf("MarkTopicMblogId error %v", err)
+			return false
+		}
+		return !replaced
+	})
+	if err = c.AddTopicInfo(topicInfo); err != nil {
+		log.Errorf("AddTopicInfo error %v", err)
+		return nil, err
+	}
+	return newsInfo, nil
+}
+
+// mblogPostTime 和 mblogTime 一样解析帖子的发布时间，但是解析失败时返回false，这样的帖子不会推送
+func mblogPostTime(mblog *Card_Mblog) (int64, bool) {
+	t, err := time.Parse(time.RubyDate, mblog.GetCreatedAt())
+	if err != nil {
+		logger.WithField("time_string", mblog.GetCreatedAt()).
+			Errorf("can not parse Mblog.CreatedAt %v", err)
+		return 0, false
+	}
+	return t.Unix(), true
+}
+
+func (c *TopicConcern) notifyGenerator() concern.NotifyGeneratorFunc {
+	return func(target mmsg.Target, ievent concern.Event) []concern.Notify {
+		var result []concern.Notify
+		switch news := ievent.(type) {
+		case *TopicNewsInfo:
+			for _, n := range NewConcernTopicNotify(target, news) {
+				result = append(result, n)
+			}
+		}
+		return result
+	}
+}
+
+func NewTopicConcern(notify chan<- concern.Notify) *TopicConcern {
+	return &TopicConcern{
+		StateManager: NewTopicStateManager(notify),
+	}
+}
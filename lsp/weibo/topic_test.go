@@ -0,0 +1,95 @@
+package weibo
+
+import (
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const testTopicId = "100808a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6"
+
+func TestParseTopicId(t *testing.T) {
+	id, err := ParseTopicId(testTopicId)
+	assert.Nil(t, err)
+	assert.Equal(t, testTopicId, id)
+
+	id, err = ParseTopicId("A1B2C3D4E5F6A7B8C9D0E1F2A3B4C5D6")
+	assert.Nil(t, err)
+	assert.Equal(t, testTopicId, id)
+
+	_, err = ParseTopicId("123")
+	assert.NotNil(t, err)
+	_, err = ParseTopicId("231522" + testTopicId[6:])
+	assert.NotNil(t, err)
+}
+
+func TestTopicMblogs(t *testing.T) {
+	const data = `{
+		"ok": 1,
+		"data": {
+			"pageInfo": {"page_title": "测试超话"},
+			"cards": [
+				{"card_type": 9, "mblog": {"id": "1", "created_at": "Mon Jan 02 15:04:05 +0800 2006"}},
+				{"card_type": 11, "card_group": [
+					{"card_type": 4},
+					{"card_type": 9, "mblog": {"id": "2", "created_at": "Mon Jan 02 16:04:05 +0800 2006"}}
+				]},
+				{"card_type": 9, "mblog": {"id": "3", "created_at": "bad time"}}
+			]
+		}
+	}`
+	var resp = new(ApiContainerGetIndexTopicResponse)
+	require.Nil(t, json.Unmarshal([]byte(data), resp))
+	assert.Equal(t, "测试超话", resp.Data.PageInfo.PageTitle)
+	mblogs := resp.Mblogs()
+	require.Len(t, mblogs, 3)
+	assert.Equal(t, "2", mblogs[1].GetId())
+
+	ts, ok := mblogPostTime(mblogs[1])
+	assert.True(t, ok)
+	assert.EqualValues(t, 1136189045, ts)
+	_, ok = mblogPostTime(mblogs[2])
+	assert.False(t, ok)
+
+	notifies := NewConcernTopicNotify(mmsg.NewGroupTarget(test.G1), &TopicNewsInfo{
+		TopicInfo: &TopicInfo{ContainerId: testTopicId, Name: "测试超话"},
+		Mblogs:    mblogs[:1],
+	})
+	require.Len(t, notifies, 1)
+	assert.Equal(t, mmsg.NewGroupTarget(test.G1), notifies[0].GetTarget())
+	assert.EqualValues(t, TopicSite, notifies[0].Site())
+	assert.NotNil(t, notifies[0].Logger())
+	assert.NotNil(t, notifies[0].ToMessage())
+	assert.True(t, strings.HasPrefix(msgstringer.MsgToString(notifies[0].ToMessage().Elements()), "weibo超话-测试超话有新帖子：\n"))
+}
+
+func TestTopicStateManager(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	c := NewTopicConcern(nil)
+	_, err := c.GetTopicInfo(testTopicId)
+	assert.NotNil(t, err)
+	assert.Nil(t, c.AddTopicInfo(&TopicInfo{ContainerId: testTopicId, Name: "测试超话"}))
+	info, err := c.GetTopicInfo(testTopicId)
+	assert.Nil(t, err)
+	assert.Equal(t, "测试超话", info.Name)
+
+	identity, err := c.Get(testTopicId)
+	assert.Nil(t, err)
+	assert.Equal(t, "测试超话", identity.GetName())
+
+	replaced, err := c.MarkTopicMblogId(testTopicId, "1")
+	assert.Nil(t, err)
+	assert.False(t, replaced)
+	replaced, err = c.MarkTopicMblogId(testTopicId, "1")
+	assert.Nil(t, err)
+	assert.True(t, replaced)
+
+	assert.Nil(t, c.RemoveTopicInfo(testTopicId))
+	assert.Nil(t, c.RemoveTopicInfo(testTopicId))
+}
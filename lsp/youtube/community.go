@@ -0,0 +1,241 @@
+package youtube
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Jeffail/gabs/v2"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
+	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
+	"github.com/cnxysoft/DDBOT-WSa/requests"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	CommunityPathOld = "https://www.youtube.com/channel/%s/community"
+	CommunityPathNew = "https://www.youtube.com/%s/community"
+	PostView         = "https://www.youtube.com/post/"
+
+	// 每个频道最多记录这么多个已经推送过的帖子
+	maxKnownPost = 100
+)
+
+const Community concern_type.Type = "community"
+
+func PostViewUrl(postId string) string {
+	return PostView + postId
+}
+
+func CommunityPath(channelID string) string {
+	if strings.HasPrefix(channelID, "@") {
+		return fmt.Sprintf(CommunityPathNew, channelID)
+	}
+	return fmt.Sprintf(CommunityPathOld, channelID)
+}
+
+// PostInfo 社区帖子
+type PostInfo struct {
+	UserInfo
+	PostId  string   `json:"post_id"`
+	Content string   `json:"content"`
+	Images  []string `json:"images"`
+	// PublishedTime youtube只提供相对时间，例如 "1天前"
+	PublishedTime string `json:"published_time"`
+
+	msgCache template.MsgCache
+}
+
+func (p *PostInfo) Site() string {
+	return Site
+}
+
+func (p *PostInfo) Type() concern_type.Type {
+	return Community
+}
+
+func (p *PostInfo) GetUid() interface{} {
+	return p.ChannelId
+}
+
+func (p *PostInfo) Logger() *logrus.Entry {
+	return logger.WithFields(logrus.Fields{
+		"Site":        Site,
+		"ChannelId":   p.ChannelId,
+		"ChannelName": p.ChannelName,
+		"PostId":      p.PostId,
+	})
+}
+
+func (p *PostInfo) GetMSG(locale string) *mmsg.MSG {
+	return p.msgCache.Get(locale, func() *mmsg.MSG {
+		m := mmsg.NewMSG()
+		m.Text(i18n.Sprintf(locale, "YTB-%v发布了社区帖子：\n", p.ChannelName))
+		if p.Content != "" {
+			m.Text(p.Content + "\n")
+		}
+		for _, image := range p.Images {
+			m.ImageByUrl(image, i18n.Tr(locale, "[图片]"), requests.ProxyOption(proxy_pool.PreferOversea))
+		}
+		m.Text(PostViewUrl(p.PostId) + "\n")
+		return m
+	})
+}
+
+type PostNotify struct {
+	*PostInfo
+	Target mmsg.Target `json:"target"`
+}
+
+func (notify *PostNotify) GetTarget() mmsg.Target {
+	return notify.Target
+}
+
+func (notify *PostNotify) ToMessage() *mmsg.MSG {
+	return notify.PostInfo.GetMSG(template.NotifyLocale(notify.Target))
+}
+
+func (notify *PostNotify) Logger() *logrus.Entry {
+	if notify == nil {
+		return logger
+	}
+	return notify.PostInfo.Logger().WithFields(mmsg.TargetLogFields(notify.Target))
+}
+
+// NotifyMedias 存档帖子图片和帖子链接
+func (notify *PostNotify) NotifyMedias() []*concern.NotifyMedia {
+	if notify == nil || notify.PostInfo == nil {
+		return nil
+	}
+	var t = time.Now()
+	// 帖子没有标题，使用内容的开头作为文件名
+	var title = []rune(strings.Join(strings.Fields(notify.Content), " "))
+	if len(title) > 20 {
+		title = title[:20]
+	}
+	var medias []*concern.NotifyMedia
+	for index, image := range notify.Images {
+		medias = append(medias, &concern.NotifyMedia{
+			Kind:  concern.MediaCover,
+			Url:   image,
+			Name:  concern.MediaFileName(t, fmt.Sprintf("%v-%v", string(title), index+1), ".jpg"),
+			Proxy: proxy_pool.PreferOversea,
+		})
+	}
+	medias = append(medias, &concern.NotifyMedia{
+		Kind: concern.MediaLink,
+		Url:  PostViewUrl(notify.PostId),
+		Name: concern.MediaFileName(t, string(title), ".txt"),
+	})
+	return medias
+}
+
+func NewPostNotify(target mmsg.Target, info *PostInfo) *PostNotify {
+	if info == nil {
+		return nil
+	}
+	return &PostNotify{
+		PostInfo: info,
+		Target:   target,
+	}
+}
+
+// XFetchPost 获取频道社区页面上的帖子，顺序与页面相同，置顶帖子可能在最前面
+func XFetchPost(channelID string) ([]*PostInfo, error) {
+	body := new(bytes.Buffer)
+	err := requests.Get(CommunityPath(channelID), nil, body,
+		requests.HeaderOption("accept-language", "zh-CN"),
+		requests.AddUAOption(),
+		requests.ProxyOption(proxy_pool.PreferOversea),
+		requests.TimeoutOption(time.Second*10),
+		requests.RetryOption(3),
+	)
+	if err != nil {
+		return nil, err
+	}
+	root, err := extractData(body.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return parsePost(channelID, root), nil
+}
+
+func parsePost(channelID string, root *gabs.Container) []*PostInfo {
+	var postSearcher = new(Searcher)
+	var infoSearcher = new(Searcher)
+	postSearcher.search("backstagePostRenderer", root)
+	infoSearcher.search("channelMetadataRenderer", root)
+
+	var channelName string
+	if len(infoSearcher.Sub) > 0 {
+		channelName, _ = infoSearcher.Sub[0].S("title").Data().(string)
+	}
+
+	var idSet = make(map[string]bool)
+	var result []*PostInfo
+	for _, postJson := range postSearcher.Sub {
+		postId, _ := postJson.S("postId").Data().(string)
+		if postId == "" || idSet[postId] {
+			continue
+		}
+		idSet[postId] = true
+		p := &PostInfo{
+			UserInfo: UserInfo{
+				ChannelId:   channelID,
+				ChannelName: channelName,
+			},
+			PostId:        postId,
+			Content:       joinRuns(postJson.Path("contentText.runs")),
+			PublishedTime: joinRuns(postJson.Path("publishedTimeText.runs")),
+		}
+		if p.ChannelName == "" {
+			p.ChannelName = joinRuns(postJson.Path("authorText.runs"))
+		}
+		attachment := postJson.S("backstageAttachment")
+		if attachment.Exists("backstageImageRenderer") {
+			if image := largestThumbnail(attachment.Path("backstageImageRenderer.image.thumbnails")); image != "" {
+				p.Images = append(p.Images, image)
+			}
+		}
+		for _, image := range attachment.Path("postMultiImageRenderer.images").Children() {
+			if u := largestThumbnail(image.Path("backstageImageRenderer.image.thumbnails")); u != "" {
+				p.Images = append(p.Images, u)
+			}
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+func joinRuns(runs *gabs.Container) string {
+	var sb strings.Builder
+	for _, run := range runs.Children() {
+		if text, ok := run.S("text").Data().(string); ok {
+			sb.WriteString(text)
+		}
+	}
+	return sb.String()
+}
+
+// largestThumbnail 返回尺寸最大的图片地址，youtube的图片地址可能省略协议
+func largestThumbnail(thumbnails *gabs.Container) string {
+	var result string
+	var size float64 = -1
+	for _, obj := range thumbnails.Children() {
+		u, _ := obj.S("url").Data().(string)
+		width, _ := obj.S("width").Data().(float64)
+		if u != "" && width > size {
+			size = width
+			result = u
+		}
+	}
+	if strings.HasPrefix(result, "//") {
+		result = "https:" + result
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package youtube
+
+import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"testing"
+
+	"github.com/Jeffail/gabs/v2"
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/stretchr/testify/assert"
+)
+
+const communityStr = `{"metadata":{"channelMetadataRenderer":{"title":"test channel"}},
+"contents":{"sectionListRenderer":{"contents":[{"itemSectionRenderer":{"contents":[
+{"backstagePostThreadRenderer":{"post":{"backstagePostRenderer":{"postId":"post2",
+"authorText":{"runs":[{"text":"test channel"}]},
+"contentText":{"runs":[{"text":"hello "},{"text":"world"}]},
+"publishedTimeText":{"runs":[{"text":"1小时前"}]},
+"backstageAttachment":{"backstageImageRenderer":{"image":{"thumbnails":[
+{"url":"//yt3.ggpht.com/small","width":288},{"url":"//yt3.ggpht.com/large","width":1080}]}}}}}}},
+{"backstagePostThreadRenderer":{"post":{"backstagePostRenderer":{"postId":"post1",
+"contentText":{"runs":[{"text":"multi image"}]},
+"backstageAttachment":{"postMultiImageRenderer":{"images":[
+{"backstageImageRenderer":{"image":{"thumbnails":[{"url":"https://example.com/1","width":100}]}}},
+{"backstageImageRenderer":{"image":{"thumbnails":[{"url":"https://example.com/2","width":100}]}}}]}}}}}}
+]}}]}}}`
+
+func TestParsePost(t *testing.T) {
+	root, err := gabs.ParseJSON([]byte(communityStr))
+	assert.Nil(t, err)
+
+	posts := parsePost(test.NAME1, root)
+	assert.Len(t, posts, 2)
+
+	assert.Equal(t, "post2", posts[0].PostId)
+	assert.Equal(t, test.NAME1, posts[0].ChannelId)
+	assert.Equal(t, "test channel", posts[0].ChannelName)
+	assert.Equal(t, "hello world", posts[0].Content)
+	assert.Equal(t, "1小时前", posts[0].PublishedTime)
+	assert.EqualValues(t, []string{"https://yt3.ggpht.com/large"}, posts[0].Images)
+
+	assert.Equal(t, "post1", posts[1].PostId)
+	assert.Equal(t, "multi image", posts[1].Content)
+	assert.EqualValues(t, []string{"https://example.com/1", "https://example.com/2"}, posts[1].Images)
+
+	assert.Equal(t, Community, posts[0].Type())
+	assert.Equal(t, test.NAME1, posts[0].GetUid())
+
+	notify := NewPostNotify(mmsg.NewGroupTarget(test.G1), posts[1])
+	assert.Equal(t, mmsg.NewGroupTarget(test.G1), notify.GetTarget())
+	assert.NotNil(t, notify.ToMessage())
+	assert.NotNil(t, notify.Logger())
+	medias := notify.NotifyMedias()
+	assert.Len(t, medias, 3)
+	assert.Equal(t, concern.MediaLink, medias[2].Kind)
+	assert.Equal(t, PostViewUrl("post1"), medias[2].Url)
+
+	assert.Nil(t, NewPostNotify(mmsg.NewGroupTarget(test.G1), nil))
+}
+
+func TestCommunityPath(t *testing.T) {
+	assert.Equal(t, "https://www.youtube.com/@test/community", CommunityPath("@test"))
+	assert.Equal(t, "https://www.youtube.com/channel/UCxxx/community", CommunityPath("UCxxx"))
+}
+
+func TestStateManager_KnownPost(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	sm := initStateManager(t)
+
+	_, err := sm.GetKnownPost(test.NAME1)
+	assert.True(t, localdb.IsNotFound(err))
+
+	var ids []string
+	for i := 0; i < maxKnownPost+10; i++ {
+		ids = append(ids, test.NAME2)
+	}
+	assert.Nil(t, sm.SetKnownPost(test.NAME1, ids))
+	known, err := sm.GetKnownPost(test.NAME1)
+	assert.Nil(t, err)
+	assert.Len(t, known, maxKnownPost)
+}
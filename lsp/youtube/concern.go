@@ -4,16 +4,17 @@ import (
 	"errors"
 	"fmt"
 	"github.com/Sora233/MiraiGo-Template/utils"
+	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
-	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 )
 
 var logger = utils.GetModuleLogger("youtube")
 
 type Concern struct {
 	*StateManager
+	reminders *reminderScheduler
 }
 
 func (c *Concern) Site() string {
@@ -21,7 +22,7 @@ func (c *Concern) Site() string {
 }
 
 func (c *Concern) Types() []concern_type.Type {
-	return []concern_type.Type{Live, Video}
+	return []concern_type.Type{Live, Video, Community}
 }
 
 func (c *Concern) ParseId(s string) (interface{}, error) {
@@ -32,11 +33,11 @@ func (c *Concern) GetStateManager() concern.IStateManager {
 	return c.StateManager
 }
 
-func (c *Concern) Add(ctx mmsg.IMsgCtx, groupCode int64, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+func (c *Concern) Add(ctx mmsg.IMsgCtx, target mmsg.Target, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	id := _id.(string)
-	log := logger.WithFields(localutils.GroupLogFields(groupCode)).WithField("id", id)
+	log := logger.WithFields(mmsg.TargetLogFields(target)).WithField("id", id)
 
-	err := c.StateManager.CheckGroupConcern(groupCode, id, ctype)
+	err := c.StateManager.CheckGroupConcern(target, id, ctype)
 	if err != nil {
 		return nil, err
 	}
@@ -47,18 +48,19 @@ func (c *Concern) Add(ctx mmsg.IMsgCtx, groupCode int64, _id interface{}, ctype
 	}
 	for _, v := range info.VideoInfo {
 		c.StateManager.AddVideo(v)
+		c.scheduleReminder(v)
 	}
-	_, err = c.StateManager.AddGroupConcern(groupCode, id, ctype)
+	_, err = c.StateManager.AddGroupConcern(target, id, ctype)
 	if err != nil {
 		return nil, err
 	}
 	return concern.NewIdentity(info.ChannelId, info.ChannelName), nil
 }
 
-func (c *Concern) Remove(ctx mmsg.IMsgCtx, groupCode int64, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
+func (c *Concern) Remove(ctx mmsg.IMsgCtx, target mmsg.Target, _id interface{}, ctype concern_type.Type) (concern.IdentityInfo, error) {
 	id := _id.(string)
 	identity, _ := c.Get(id)
-	_, err := c.StateManager.RemoveGroupConcern(groupCode, id, ctype)
+	_, err := c.StateManager.RemoveGroupConcern(target, id, ctype)
 	if identity == nil {
 		identity = concern.NewIdentity(_id, "unknown")
 	}
@@ -75,6 +77,7 @@ func (c *Concern) Get(id interface{}) (concern.IdentityInfo, error) {
 
 func (c *Concern) Stop() {
 	logger.Trace("正在停止youtube concern")
+	c.reminders.stop()
 	logger.Trace("正在停止youtube StateManager")
 	c.StateManager.Stop()
 	logger.Trace("youtube StateManager已停止")
@@ -85,21 +88,30 @@ func (c *Concern) Start() error {
 	c.UseEmitQueue()
 	c.UseFreshFunc(c.fresh())
 	c.UseNotifyGeneratorFunc(c.notifyGenerator())
-	return c.StateManager.Start()
+	if err := c.StateManager.Start(); err != nil {
+		return err
+	}
+	if err := c.restoreReminder(); err != nil {
+		logger.Errorf("restoreReminder error %v", err)
+	}
+	return nil
 }
 
 func (c *Concern) fresh() concern.FreshFunc {
 	return c.EmitQueueFresher(func(ctype concern_type.Type, id interface{}) ([]concern.Event, error) {
+		channelId, ok := id.(string)
+		if !ok {
+			return nil, errors.New("canst fresh id to string failed")
+		}
+		if !ctype.ContainAny(Live.Add(Video).Add(Community)) {
+			return nil, fmt.Errorf("unknown concern_type %v", ctype.String())
+		}
+		var result []concern.Event
 		if ctype.ContainAny(Live.Add(Video)) {
-			channelId, ok := id.(string)
-			if !ok {
-				return nil, errors.New("canst fresh id to string failed")
-			}
 			infos, err := c.freshInfo(channelId)
 			if err != nil {
 				return nil, err
 			}
-			var result []concern.Event
 			for _, event := range infos {
 				prev, getErr := c.StateManager.GetVideo(event.ChannelId, event.VideoId)
 				if err := c.StateManager.AddVideo(event); err != nil {
@@ -111,29 +123,44 @@ func (c *Concern) fresh() concern.FreshFunc {
 						continue
 					}
 				}
+				c.scheduleReminder(event)
 				result = append(result, event)
 			}
-			return result, nil
 		}
-		return nil, fmt.Errorf("unknown concern_type %v", ctype.String())
+		if ctype.ContainAny(Community) {
+			posts, err := c.freshPost(channelId)
+			if err != nil {
+				if len(result) == 0 {
+					return nil, err
+				}
+				logger.WithField("channel_id", channelId).Errorf("freshPost error %v", err)
+			}
+			for _, post := range posts {
+				result = append(result, post)
+			}
+		}
+		return result, nil
 	})
 }
 
 func (c *Concern) notifyGenerator() concern.NotifyGeneratorFunc {
-	return func(groupCode int64, ievent concern.Event) []concern.Notify {
+	return func(target mmsg.Target, ievent concern.Event) []concern.Notify {
 		switch event := ievent.(type) {
 		case *VideoInfo:
 			log := event.Logger()
 			if event.IsVideo() {
-				log.WithFields(localutils.GroupLogFields(groupCode)).Debugf("video notify")
+				log.WithFields(mmsg.TargetLogFields(target)).Debugf("video notify")
 			} else if event.IsLive() {
 				if event.IsWaiting() {
-					log.WithFields(localutils.GroupLogFields(groupCode)).Debugf("live waiting notify")
+					log.WithFields(mmsg.TargetLogFields(target)).Debugf("live waiting notify")
 				} else if event.IsLiving() {
-					log.WithFields(localutils.GroupLogFields(groupCode)).Debugf("living notify")
+					log.WithFields(mmsg.TargetLogFields(target)).Debugf("living notify")
 				}
 			}
-			return []concern.Notify{NewConcernNotify(groupCode, event)}
+			return []concern.Notify{NewConcernNotify(target, event)}
+		case *PostInfo:
+			event.Logger().WithFields(mmsg.TargetLogFields(target)).Debugf("community post notify")
+			return []concern.Notify{NewPostNotify(target, event)}
 		default:
 			logger.Errorf("unknown EventType %+v", event)
 			return nil
@@ -169,6 +196,9 @@ func (c *Concern) freshInfo(channelId string) (result []*VideoInfo, err error) {
 					if newV.IsVideo() && oldV.IsLive() {
 						// 应该是下播了吧？
 						result = append(result, newV)
+						if oldV.IsLiving() {
+							result = append(result, liveEnded(oldV))
+						}
 					}
 					if newV.IsLive() && oldV.IsLive() {
 						if newV.IsWaiting() && oldV.IsWaiting() && newV.VideoTimestamp != oldV.VideoTimestamp {
@@ -200,6 +230,54 @@ func (c *Concern) freshInfo(channelId string) (result []*VideoInfo, err error) {
 	return
 }
 
+// freshPost 返回没有推送过的社区帖子，第一次获取时只记录不推送
+func (c *Concern) freshPost(channelId string) ([]*PostInfo, error) {
+	posts, err := XFetchPost(channelId)
+	if err != nil {
+		return nil, err
+	}
+	known, err := c.StateManager.GetKnownPost(channelId)
+	if err != nil && !localdb.IsNotFound(err) {
+		return nil, err
+	}
+	var firstLoad = localdb.IsNotFound(err)
+	var knownSet = make(map[string]bool)
+	for _, id := range known {
+		knownSet[id] = true
+	}
+	var newPosts []*PostInfo
+	var ids []string
+	for _, post := range posts {
+		ids = append(ids, post.PostId)
+		if !knownSet[post.PostId] {
+			newPosts = append(newPosts, post)
+		}
+	}
+	if len(newPosts) == 0 && !firstLoad {
+		return nil, nil
+	}
+	var idSet = make(map[string]bool)
+	for _, id := range ids {
+		idSet[id] = true
+	}
+	for _, id := range known {
+		if !idSet[id] {
+			ids = append(ids, id)
+		}
+	}
+	if err = c.StateManager.SetKnownPost(channelId, ids); err != nil {
+		return nil, err
+	}
+	if firstLoad {
+		return nil, nil
+	}
+	// 页面上新的帖子在前面，按照发布顺序推送
+	for i, j := 0, len(newPosts)-1; i < j; i, j = i+1, j-1 {
+		newPosts[i], newPosts[j] = newPosts[j], newPosts[i]
+	}
+	return newPosts, nil
+}
+
 func (c *Concern) FindInfo(channelId string, load bool, addMode bool) (*Info, error) {
 	var info *Info
 	if load {
@@ -229,5 +307,6 @@ func (c *Concern) FindOrLoad(channelId string, addMode bool) (*Info, error) {
 func NewConcern(notify chan<- concern.Notify) *Concern {
 	return &Concern{
 		StateManager: NewStateManager(notify),
+		reminders:    newReminderScheduler(),
 	}
 }
@@ -1,6 +1,10 @@
 package youtube
 
-import "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+)
 
 type KeySet struct {
 }
@@ -21,8 +25,8 @@ func (k *KeySet) FreshKey(keys ...interface{}) string {
 	return buntdb.YoutubeFreshKey(keys...)
 }
 
-func (k *KeySet) ParseGroupConcernStateKey(key string) (int64, interface{}, error) {
-	return buntdb.ParseConcernStateKeyWithString(key)
+func (k *KeySet) ParseGroupConcernStateKey(key string) (mmsg.Target, interface{}, error) {
+	return concern.ParseConcernStateKeyWithString(key)
 }
 
 func NewKeySet() *KeySet {
@@ -44,6 +48,14 @@ func (e *extraKey) VideoKey(keys ...interface{}) string {
 	return buntdb.YoutubeVideoKey(keys...)
 }
 
+func (e *extraKey) PostKey(keys ...interface{}) string {
+	return buntdb.YoutubePostKey(keys...)
+}
+
+func (e *extraKey) ReminderKey(keys ...interface{}) string {
+	return buntdb.YoutubeReminderKey(keys...)
+}
+
 func NewExtraKey() *extraKey {
 	return &extraKey{}
 }
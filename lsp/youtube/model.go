@@ -1,13 +1,16 @@
 package youtube
 
 import (
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/template"
 	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
 	"github.com/cnxysoft/DDBOT-WSa/requests"
 	localutils "github.com/cnxysoft/DDBOT-WSa/utils"
 	"github.com/sirupsen/logrus"
-	"sync"
+	"time"
 )
 
 type UserInfo struct {
@@ -37,10 +40,16 @@ type VideoInfo struct {
 	VideoStatus    VideoStatus `json:"video_status"`
 	VideoTimestamp int64       `json:"video_timestamp"`
 
-	once              sync.Once
-	msgCache          *mmsg.MSG
+	msgCache          template.MsgCache
 	liveStatusChanged bool
 	liveTitleChanged  bool
+	// remindBefore 不为0时是开始前的提醒
+	remindBefore time.Duration
+}
+
+// IsReminder 是否是直播或首播开始前的提醒
+func (v *VideoInfo) IsReminder() bool {
+	return v.remindBefore > 0
 }
 
 func (v *VideoInfo) TitleChanged() bool {
@@ -55,6 +64,19 @@ func (v *VideoInfo) LiveStatusChanged() bool {
 	return v.liveStatusChanged
 }
 
+func (v *VideoInfo) AnchorName() string {
+	return v.ChannelName
+}
+
+func (v *VideoInfo) RoomTitle() string {
+	return v.VideoTitle
+}
+
+// Popularity youtube目前没有获取观看人数，总是返回0
+func (v *VideoInfo) Popularity() int64 {
+	return 0
+}
+
 func (v *VideoInfo) Site() string {
 	return Site
 }
@@ -111,24 +133,47 @@ func (v *VideoInfo) IsVideo() bool {
 	return v.VideoType == VideoType_Video
 }
 
-func (v *VideoInfo) GetMSG() *mmsg.MSG {
-	v.once.Do(func() {
+// liveEnded 根据下播前的直播信息生成一条下播事件，用于下播推送和直播总结
+func liveEnded(v *VideoInfo) *VideoInfo {
+	return &VideoInfo{
+		UserInfo:          v.UserInfo,
+		Cover:             v.Cover,
+		VideoId:           v.VideoId,
+		VideoTitle:        v.VideoTitle,
+		VideoType:         v.VideoType,
+		VideoStatus:       VideoStatus_Upload,
+		VideoTimestamp:    v.VideoTimestamp,
+		liveStatusChanged: true,
+	}
+}
+
+func (v *VideoInfo) GetMSG(locale string) *mmsg.MSG {
+	return v.msgCache.Get(locale, func() *mmsg.MSG {
 		m := mmsg.NewMSG()
-		if v.IsLive() {
+		if v.IsReminder() {
+			var kind = "直播"
+			if v.VideoType == VideoType_FirstLive {
+				kind = "首播"
+			}
+			m.Text(i18n.Sprintf(locale, "YTB-%v的%v将在%v后开始：\n%v\n时间：%v\n",
+				v.ChannelName, i18n.Tr(locale, kind), i18n.Tr(locale, remindText(v.remindBefore)),
+				v.VideoTitle, localutils.TimestampFormat(v.VideoTimestamp)))
+		} else if v.IsLive() {
 			if v.IsLiving() {
-				m.Textf("YTB-%v正在直播：\n%v\n", v.ChannelName, v.VideoTitle)
+				m.Text(i18n.Sprintf(locale, "YTB-%v正在直播：\n%v\n", v.ChannelName, v.VideoTitle))
+			} else if v.LiveStatusChanged() {
+				m.Text(i18n.Sprintf(locale, "YTB-%v直播结束了：\n%v\n", v.ChannelName, v.VideoTitle))
 			} else {
-				m.Textf("YTB-%v发布了直播预约：\n%v\n时间：%v\n",
-					v.ChannelName, v.VideoTitle, localutils.TimestampFormat(v.VideoTimestamp))
+				m.Text(i18n.Sprintf(locale, "YTB-%v发布了直播预约：\n%v\n时间：%v\n",
+					v.ChannelName, v.VideoTitle, localutils.TimestampFormat(v.VideoTimestamp)))
 			}
 		} else if v.IsVideo() {
-			m.Textf("YTB-%s发布了新视频：\n%v\n", v.ChannelName, v.VideoTitle)
+			m.Text(i18n.Sprintf(locale, "YTB-%v发布了新视频：\n%v\n", v.ChannelName, v.VideoTitle))
 		}
-		m.ImageByUrl(v.Cover, "[封面]", requests.ProxyOption(proxy_pool.PreferOversea))
+		m.ImageByUrl(v.Cover, i18n.Tr(locale, "[封面]"), requests.ProxyOption(proxy_pool.PreferOversea))
 		m.Text(VideoViewUrl(v.VideoId) + "\n")
-		v.msgCache = m
+		return m
 	})
-	return v.msgCache
 }
 
 type Info struct {
@@ -158,31 +203,57 @@ func NewInfo(vinfo []*VideoInfo, addMode bool) *Info {
 
 type ConcernNotify struct {
 	*VideoInfo
-	GroupCode int64 `json:"group_code"`
+	Target mmsg.Target `json:"target"`
 }
 
-func (notify *ConcernNotify) GetGroupCode() int64 {
-	return notify.GroupCode
+func (notify *ConcernNotify) GetTarget() mmsg.Target {
+	return notify.Target
 }
 
 func (notify *ConcernNotify) ToMessage() (m *mmsg.MSG) {
-	return notify.VideoInfo.GetMSG()
+	return notify.VideoInfo.GetMSG(template.NotifyLocale(notify.Target))
 }
 
 func (notify *ConcernNotify) Logger() *logrus.Entry {
 	if notify == nil {
 		return logger
 	}
-	return notify.VideoInfo.Logger().WithFields(localutils.GroupLogFields(notify.GroupCode))
+	return notify.VideoInfo.Logger().WithFields(mmsg.TargetLogFields(notify.Target))
+}
+
+// NotifyMedias 存档视频封面和视频链接
+func (notify *ConcernNotify) NotifyMedias() []*concern.NotifyMedia {
+	if notify == nil || notify.VideoInfo == nil {
+		return nil
+	}
+	var t = time.Now()
+	if notify.VideoTimestamp > 0 {
+		t = time.Unix(notify.VideoTimestamp, 0)
+	}
+	var medias []*concern.NotifyMedia
+	if notify.Cover != "" {
+		medias = append(medias, &concern.NotifyMedia{
+			Kind:  concern.MediaCover,
+			Url:   notify.Cover,
+			Name:  concern.MediaFileName(t, notify.VideoTitle, ".jpg"),
+			Proxy: proxy_pool.PreferOversea,
+		})
+	}
+	medias = append(medias, &concern.NotifyMedia{
+		Kind: concern.MediaLink,
+		Url:  VideoViewUrl(notify.VideoId),
+		Name: concern.MediaFileName(t, notify.VideoTitle, ".txt"),
+	})
+	return medias
 }
 
-func NewConcernNotify(groupCode int64, info *VideoInfo) *ConcernNotify {
+func NewConcernNotify(target mmsg.Target, info *VideoInfo) *ConcernNotify {
 	if info == nil {
 		return nil
 	}
 	return &ConcernNotify{
 		VideoInfo: info,
-		GroupCode: groupCode,
+		Target:    target,
 	}
 }
 
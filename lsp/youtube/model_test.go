@@ -2,7 +2,12 @@ package youtube
 
 import (
 	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/i18n"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -20,9 +25,9 @@ func TestVideoInfo(t *testing.T) {
 	info := NewInfo([]*VideoInfo{vi}, false)
 	assert.NotNil(t, info)
 
-	notify := NewConcernNotify(test.G1, vi)
+	notify := NewConcernNotify(mmsg.NewGroupTarget(test.G1), vi)
 	assert.NotNil(t, notify)
-	assert.Equal(t, test.G1, notify.GetGroupCode())
+	assert.Equal(t, mmsg.NewGroupTarget(test.G1), notify.GetTarget())
 	assert.Equal(t, test.NAME1, notify.GetUid())
 	assert.NotNil(t, notify.Logger())
 	assert.Equal(t, Video, notify.Type())
@@ -32,6 +37,19 @@ func TestVideoInfo(t *testing.T) {
 	m := notify.ToMessage()
 	assert.NotNil(t, m)
 
+	medias := notify.NotifyMedias()
+	if assert.Len(t, medias, 1) {
+		assert.Equal(t, concern.MediaLink, medias[0].Kind)
+		assert.Equal(t, VideoViewUrl(test.BVID1), medias[0].Url)
+	}
+	notify.Cover = "https://i.ytimg.com/vi/" + test.BVID1 + "/hqdefault.jpg"
+	medias = notify.NotifyMedias()
+	if assert.Len(t, medias, 2) {
+		assert.Equal(t, concern.MediaCover, medias[0].Kind)
+		assert.Equal(t, notify.Cover, medias[0].Url)
+		assert.True(t, strings.HasSuffix(medias[0].Name, ".jpg"))
+	}
+
 	notify.VideoType = VideoType_Live
 	m = notify.ToMessage()
 	assert.NotNil(t, m)
@@ -40,4 +58,17 @@ func TestVideoInfo(t *testing.T) {
 	m = notify.ToMessage()
 	assert.NotNil(t, m)
 
+	var _ concern.NotifyLiveSummaryExt = notify
+	assert.Equal(t, test.NAME2, notify.AnchorName())
+	assert.Equal(t, notify.VideoTitle, notify.RoomTitle())
+	assert.EqualValues(t, 0, notify.Popularity())
+
+	ended := liveEnded(notify.VideoInfo)
+	assert.True(t, ended.IsLive())
+	assert.False(t, ended.Living())
+	assert.True(t, ended.LiveStatusChanged())
+	assert.Equal(t, Live, ended.Type())
+	assert.Equal(t, notify.VideoId, ended.VideoId)
+	assert.Contains(t, msgstringer.MsgToString(ended.GetMSG(i18n.DefaultLocale).Elements()), "直播结束了")
+
 }
@@ -0,0 +1,49 @@
+package youtube
+
+import (
+	"errors"
+
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern_type"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+)
+
+// Preview 与刷新相同从频道页面获取最新的视频、直播或者社区帖子，不会保存
+// 正在进行的直播当作刚刚开播处理
+func (c *Concern) Preview(target mmsg.Target, id interface{}, ctype concern_type.Type, limit int) ([]concern.Notify, error) {
+	channelId := id.(string)
+	var result []concern.Notify
+	switch ctype {
+	case Live, Video:
+		infos, err := XFetchInfo(channelId)
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range infos {
+			if len(result) >= limit {
+				break
+			}
+			if (ctype == Live && !info.IsLive()) || (ctype == Video && !info.IsVideo()) {
+				continue
+			}
+			if info.IsLive() && info.IsLiving() {
+				info.liveStatusChanged = true
+			}
+			result = append(result, NewConcernNotify(target, info))
+		}
+	case Community:
+		posts, err := XFetchPost(channelId)
+		if err != nil {
+			return nil, err
+		}
+		for _, post := range posts {
+			if len(result) >= limit {
+				break
+			}
+			result = append(result, NewPostNotify(target, post))
+		}
+	default:
+		return nil, errors.New("该类型不支持预览")
+	}
+	return result, nil
+}
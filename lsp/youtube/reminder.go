@@ -0,0 +1,180 @@
+package youtube
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/cfg"
+	"github.com/tidwall/buntdb"
+)
+
+// Reminder 直播或首播开始前的提醒，保存在数据库中，重启后重新安排
+type Reminder struct {
+	ChannelId string `json:"channel_id"`
+	VideoId   string `json:"video_id"`
+	StartTime int64  `json:"start_time"`
+	// Before 提前多少秒提醒
+	Before int64 `json:"before"`
+}
+
+func (r *Reminder) RemindAt() time.Time {
+	return time.Unix(r.StartTime-r.Before, 0)
+}
+
+type reminderScheduler struct {
+	mu      sync.Mutex
+	timers  map[string]*time.Timer
+	stopped bool
+}
+
+func newReminderScheduler() *reminderScheduler {
+	return &reminderScheduler{timers: make(map[string]*time.Timer)}
+}
+
+// start 安排一个定时器，同一个key之前的定时器会被取消
+func (s *reminderScheduler) start(key string, at time.Time, f func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	if t, found := s.timers[key]; found {
+		t.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Until(at), func() {
+		s.mu.Lock()
+		if s.timers[key] != timer {
+			s.mu.Unlock()
+			return
+		}
+		delete(s.timers, key)
+		s.mu.Unlock()
+		f()
+	})
+	s.timers[key] = timer
+}
+
+func (s *reminderScheduler) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.timers)
+}
+
+func (s *reminderScheduler) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	for key, t := range s.timers {
+		t.Stop()
+		delete(s.timers, key)
+	}
+}
+
+func remindBeforeConfigured(before int64) bool {
+	for _, d := range cfg.GetYoutubeRemindBefore() {
+		if int64(d/time.Second) == before {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleReminder 为等待中的直播和首播安排开始前的提醒
+func (c *Concern) scheduleReminder(v *VideoInfo) {
+	if !v.IsWaiting() || v.VideoTimestamp == 0 {
+		return
+	}
+	var now = time.Now()
+	for _, before := range cfg.GetYoutubeRemindBefore() {
+		r := &Reminder{
+			ChannelId: v.ChannelId,
+			VideoId:   v.VideoId,
+			StartTime: v.VideoTimestamp,
+			Before:    int64(before / time.Second),
+		}
+		if !now.Before(r.RemindAt()) {
+			continue
+		}
+		key := c.ReminderKey(r.ChannelId, r.VideoId, r.Before)
+		err := c.SetJson(key, r, localdb.SetExpireOpt(time.Until(time.Unix(r.StartTime, 0))+time.Hour))
+		if err != nil {
+			v.Logger().Errorf("save reminder error %v", err)
+			continue
+		}
+		c.startReminder(key, r)
+	}
+}
+
+func (c *Concern) startReminder(key string, r *Reminder) {
+	c.reminders.start(key, r.RemindAt(), func() {
+		c.fireReminder(key, r)
+	})
+}
+
+// fireReminder 预约时间没有变化并且还没有开始时才提醒
+func (c *Concern) fireReminder(key string, r *Reminder) {
+	var current = new(Reminder)
+	if err := c.GetJson(key, current); err != nil || *current != *r {
+		return
+	}
+	c.Delete(key, localdb.IgnoreNotFoundOpt())
+	v, err := c.GetVideo(r.ChannelId, r.VideoId)
+	if err != nil {
+		logger.WithField("key", key).Errorf("reminder GetVideo error %v", err)
+		return
+	}
+	if !v.IsWaiting() || v.VideoTimestamp != r.StartTime {
+		return
+	}
+	v.remindBefore = time.Duration(r.Before) * time.Second
+	v.Logger().WithField("Before", v.remindBefore).Debug("live reminder")
+	c.EmitEvent(v)
+}
+
+// restoreReminder 重启后重新安排数据库中的提醒，错过的提醒在开始前会立即发送
+func (c *Concern) restoreReminder() error {
+	var now = time.Now()
+	var prefix = c.ReminderKey() + ":"
+	var restore = make(map[string]*Reminder)
+	var expired []string
+	err := c.RCoverTx(func(tx *buntdb.Tx) error {
+		return tx.AscendKeys(prefix+"*", func(key, value string) bool {
+			r := new(Reminder)
+			if err := json.Unmarshal([]byte(value), r); err != nil ||
+				!now.Before(time.Unix(r.StartTime, 0)) || !remindBeforeConfigured(r.Before) {
+				expired = append(expired, key)
+			} else {
+				restore[key] = r
+			}
+			return true
+		})
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range expired {
+		c.Delete(key, localdb.IgnoreNotFoundOpt())
+	}
+	for key, r := range restore {
+		c.startReminder(key, r)
+	}
+	if len(restore) > 0 {
+		logger.Debugf("restore %v reminder", len(restore))
+	}
+	return nil
+}
+
+// remindText 把提前的时间转换成 "30分钟" 这样的格式
+func remindText(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		return fmt.Sprintf("%v小时", int64(d/time.Hour))
+	}
+	if d >= time.Minute && d%time.Minute == 0 {
+		return fmt.Sprintf("%v分钟", int64(d/time.Minute))
+	}
+	return strings.TrimSuffix(d.String(), "0s")
+}
@@ -0,0 +1,117 @@
+package youtube
+
+import (
+	"context"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
+	"testing"
+	"time"
+
+	"github.com/Sora233/MiraiGo-Template/config"
+	"github.com/cnxysoft/DDBOT-WSa/internal/test"
+	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/utils/msgstringer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemindText(t *testing.T) {
+	assert.Equal(t, "30分钟", remindText(time.Minute*30))
+	assert.Equal(t, "2小时", remindText(time.Hour*2))
+	assert.Equal(t, "90分钟", remindText(time.Minute*90))
+	assert.Equal(t, "5s", remindText(time.Second*5))
+}
+
+func TestReminder(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	config.GlobalConfig.Set("youtube.remindBefore", []string{"1h", "1s"})
+	defer config.GlobalConfig.Set("youtube.remindBefore", nil)
+
+	testNotifyChan := make(chan concern.Notify, 4)
+	c := NewConcern(testNotifyChan)
+	c.StateManager.UseNotifyGeneratorFunc(c.notifyGenerator())
+	c.StateManager.UseFreshFunc(func(ctx context.Context, eventChan chan<- concern.Event) {
+		<-ctx.Done()
+	})
+	assert.Nil(t, c.StateManager.Start())
+	defer c.Stop()
+
+	_, err := c.StateManager.AddGroupConcern(mmsg.NewGroupTarget(test.G1), test.NAME1, Live)
+	assert.Nil(t, err)
+
+	var start = time.Now().Add(time.Second * 2).Unix()
+	newVideo := func(videoId string) *VideoInfo {
+		return &VideoInfo{
+			UserInfo:       UserInfo{ChannelId: test.NAME1, ChannelName: test.NAME2},
+			VideoId:        videoId,
+			VideoTitle:     videoId,
+			VideoType:      VideoType_FirstLive,
+			VideoStatus:    VideoStatus_Waiting,
+			VideoTimestamp: start,
+		}
+	}
+
+	v1 := newVideo("v1")
+	assert.Nil(t, c.AddVideo(v1))
+	c.scheduleReminder(v1)
+
+	// 预约时间修改过，之前的提醒不再发送
+	v2 := newVideo("v2")
+	assert.Nil(t, c.AddVideo(v2))
+	c.scheduleReminder(v2)
+	v2 = newVideo("v2")
+	v2.VideoTimestamp = time.Now().Add(time.Hour * 3).Unix()
+	assert.Nil(t, c.AddVideo(v2))
+
+	// 已经过了提醒时间的不会安排
+	assert.Equal(t, 2, c.reminders.len())
+
+	select {
+	case notify := <-testNotifyChan:
+		n, ok := notify.(*ConcernNotify)
+		assert.True(t, ok)
+		assert.True(t, n.IsReminder())
+		assert.Equal(t, "v1", n.VideoId)
+		assert.Equal(t, mmsg.NewGroupTarget(test.G1), n.GetTarget())
+		assert.Contains(t, msgstringer.MsgToString(n.ToMessage().Elements()), "首播将在1s后开始")
+	case <-time.After(time.Second * 3):
+		assert.Fail(t, "no reminder received")
+	}
+	select {
+	case <-testNotifyChan:
+		assert.Fail(t, "should no reminder received")
+	case <-time.After(time.Second):
+	}
+	assert.Equal(t, 0, c.reminders.len())
+	assert.False(t, c.Exist(c.ReminderKey(test.NAME1, "v1", 1)))
+}
+
+func TestRestoreReminder(t *testing.T) {
+	test.InitBuntdb(t)
+	defer test.CloseBuntdb(t)
+
+	config.GlobalConfig.Set("youtube.remindBefore", []string{"30m"})
+	defer config.GlobalConfig.Set("youtube.remindBefore", nil)
+
+	c := NewConcern(nil)
+	defer c.reminders.stop()
+
+	var start = time.Now().Add(time.Hour).Unix()
+	valid := &Reminder{ChannelId: test.NAME1, VideoId: "v1", StartTime: start, Before: 1800}
+	notConfigured := &Reminder{ChannelId: test.NAME1, VideoId: "v1", StartTime: start, Before: 300}
+	started := &Reminder{ChannelId: test.NAME1, VideoId: "v2", StartTime: time.Now().Add(-time.Minute).Unix(), Before: 1800}
+	for _, r := range []*Reminder{valid, notConfigured, started} {
+		assert.Nil(t, c.SetJson(c.ReminderKey(r.ChannelId, r.VideoId, r.Before), r))
+	}
+
+	assert.Nil(t, c.restoreReminder())
+	assert.Equal(t, 1, c.reminders.len())
+	assert.True(t, c.Exist(c.ReminderKey(test.NAME1, "v1", 1800)))
+	assert.False(t, c.Exist(c.ReminderKey(test.NAME1, "v1", 300)))
+	assert.False(t, c.Exist(c.ReminderKey(test.NAME1, "v2", 1800)))
+
+	var r = new(Reminder)
+	assert.Nil(t, c.GetJson(c.ReminderKey(test.NAME1, "v1", 1800), r, localdb.IgnoreNotFoundOpt()))
+	assert.EqualValues(t, valid, r)
+}
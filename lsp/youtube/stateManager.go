@@ -3,6 +3,7 @@ package youtube
 import (
 	localdb "github.com/cnxysoft/DDBOT-WSa/lsp/buntdb"
 	"github.com/cnxysoft/DDBOT-WSa/lsp/concern"
+	"github.com/cnxysoft/DDBOT-WSa/lsp/mmsg"
 	"time"
 )
 
@@ -37,8 +38,25 @@ func (s *StateManager) AddVideo(v *VideoInfo) error {
 	return s.SetJson(s.VideoKey(v.ChannelId, v.VideoId), v)
 }
 
-func (s *StateManager) GetGroupConcernConfig(groupCode int64, id interface{}) (concernConfig concern.IConfig) {
-	return NewGroupConcernConfig(s.StateManager.GetGroupConcernConfig(groupCode, id))
+// GetKnownPost 返回频道已经推送过的帖子id，从来没有获取过帖子时返回 buntdb.ErrNotFound
+func (s *StateManager) GetKnownPost(channelId string) ([]string, error) {
+	var ids []string
+	err := s.GetJson(s.PostKey(channelId), &ids)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (s *StateManager) SetKnownPost(channelId string, ids []string) error {
+	if len(ids) > maxKnownPost {
+		ids = ids[:maxKnownPost]
+	}
+	return s.SetJson(s.PostKey(channelId), ids)
+}
+
+func (s *StateManager) GetGroupConcernConfig(target mmsg.Target, id interface{}) (concernConfig concern.IConfig) {
+	return NewGroupConcernConfig(s.StateManager.GetGroupConcernConfig(target, id))
 }
 
 func NewStateManager(notify chan<- concern.Notify) *StateManager {
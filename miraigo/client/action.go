@@ -0,0 +1,165 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultActionTimeout 调用 OneBot 动作的默认超时时间
+const defaultActionTimeout = time.Second * 10
+
+// OneBot v11 中常见的 retcode
+const (
+	RetCodeOK           = 0
+	RetCodeAsync        = 1
+	RetCodeBadRequest   = 1400
+	RetCodeUnauthorized = 1401
+	RetCodeForbidden    = 1403
+	RetCodeUnsupported  = 1404
+)
+
+var (
+	ErrNotConnected  = errors.New("OneBot 未连接")
+	ErrActionTimeout = errors.New("action timeout")
+	// ErrMessageTooLong 消息或图片数量超过了发送的限制
+	ErrMessageTooLong = errors.New("消息或图片长度超限，取消本次发送")
+)
+
+// ActionError OneBot 动作返回了失败状态
+type ActionError struct {
+	Action  string
+	Status  string
+	RetCode int
+	Message string
+	Wording string
+}
+
+func (e *ActionError) Error() string {
+	msg := e.Wording
+	if e.Message != "" && e.Message != e.Wording {
+		if msg != "" {
+			msg += ": "
+		}
+		msg += e.Message
+	}
+	return fmt.Sprintf("%v failed: status=%v retcode=%v %v", e.Action, e.Status, e.RetCode, msg)
+}
+
+// IsUnsupported 协议端不支持该动作
+func (e *ActionError) IsUnsupported() bool {
+	return e.RetCode == RetCodeUnsupported
+}
+
+// AsActionError 从 err 中取出 *ActionError，不是动作失败时返回 nil
+func AsActionError(err error) *ActionError {
+	var actionErr *ActionError
+	if errors.As(err, &actionErr) {
+		return actionErr
+	}
+	return nil
+}
+
+type actionRequest struct {
+	Action string `json:"action"`
+	Params any    `json:"params"`
+	Echo   string `json:"echo"`
+}
+
+// callAction 发送一个 OneBot 动作并等待响应，成功时返回响应中的 data
+func (c *QQClient) callAction(action string, params any, timeout time.Duration) (json.RawMessage, error) {
+	if timeout <= 0 {
+		timeout = defaultActionTimeout
+	}
+	echo := generateEcho(action)
+	data, err := json.Marshal(&actionRequest{
+		Action: action,
+		Params: params,
+		Echo:   echo,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v 序列化请求失败", action)
+	}
+	ch := make(chan *T, 1)
+	c.responseLock.Lock()
+	if c.responseCh == nil {
+		c.responseCh = make(map[string]chan *T)
+	}
+	c.responseCh[echo] = ch
+	ws := c.ws
+	c.responseLock.Unlock()
+	defer func() {
+		c.responseLock.Lock()
+		delete(c.responseCh, echo)
+		c.responseLock.Unlock()
+	}()
+
+	if err = c.writeWebSocket(ws, data); err != nil {
+		return nil, errors.Wrapf(err, "%v 发送失败", action)
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case res := <-ch:
+		if res.Status == "ok" || res.Status == "async" {
+			return res.Data, nil
+		}
+		return nil, &ActionError{
+			Action:  action,
+			Status:  res.Status,
+			RetCode: res.RetCode,
+			Message: firstNonEmpty(res.Message, res.Msg),
+			Wording: res.Wording,
+		}
+	case <-timer.C:
+		return nil, errors.Wrap(ErrActionTimeout, action)
+	}
+}
+
+func firstNonEmpty(s ...string) string {
+	for _, v := range s {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Actions OneBot v11 动作的类型化封装，包括标准动作和常见的扩展动作
+type Actions struct {
+	c       *QQClient
+	timeout time.Duration
+}
+
+// Actions 返回使用默认超时时间的动作客户端
+func (c *QQClient) Actions() *Actions {
+	return &Actions{c: c, timeout: defaultActionTimeout}
+}
+
+// WithTimeout 返回使用指定超时时间的动作客户端，不影响原来的客户端
+func (a *Actions) WithTimeout(timeout time.Duration) *Actions {
+	return &Actions{c: a.c, timeout: timeout}
+}
+
+// call 调用不关心返回数据的动作
+func (a *Actions) call(action string, params any) error {
+	_, err := a.c.callAction(action, params, a.timeout)
+	return err
+}
+
+// callAs 调用动作并把 data 解析为 R
+func callAs[R any](a *Actions, action string, params any) (*R, error) {
+	raw, err := a.c.callAction(action, params, a.timeout)
+	if err != nil {
+		return nil, err
+	}
+	var result R
+	if len(raw) > 0 && string(raw) != "null" {
+		if err = json.Unmarshal(raw, &result); err != nil {
+			return nil, errors.Wrapf(err, "%v 解析返回数据失败", action)
+		}
+	}
+	return &result, nil
+}
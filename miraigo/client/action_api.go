@@ -0,0 +1,257 @@
+package client
+
+/* -------- 消息 -------- */
+
+func (a *Actions) SendPrivateMsg(req *SendPrivateMsgReq) (*SendMsgResp, error) {
+	return callAs[SendMsgResp](a, "send_private_msg", req)
+}
+
+func (a *Actions) SendGroupMsg(req *SendGroupMsgReq) (*SendMsgResp, error) {
+	return callAs[SendMsgResp](a, "send_group_msg", req)
+}
+
+func (a *Actions) SendMsg(req *SendMsgReq) (*SendMsgResp, error) {
+	return callAs[SendMsgResp](a, "send_msg", req)
+}
+
+func (a *Actions) DeleteMsg(req *MessageIdReq) error {
+	return a.call("delete_msg", req)
+}
+
+func (a *Actions) GetMsg(req *MessageIdReq) (*WebSocketMessage, error) {
+	return callAs[WebSocketMessage](a, "get_msg", req)
+}
+
+func (a *Actions) SendLike(req *SendLikeReq) error {
+	return a.call("send_like", req)
+}
+
+// MarkMsgAsRead 扩展动作
+func (a *Actions) MarkMsgAsRead(req *MessageIdReq) error {
+	return a.call("mark_msg_as_read", req)
+}
+
+func (a *Actions) GroupPoke(req *GroupPokeReq) error {
+	return a.call("group_poke", req)
+}
+
+func (a *Actions) FriendPoke(req *FriendPokeReq) error {
+	return a.call("friend_poke", req)
+}
+
+/* -------- 合并转发 -------- */
+
+func (a *Actions) GetForwardMsg(req *GetForwardMsgReq) (*GetForwardMsgResp, error) {
+	if req.MessageId == "" {
+		r := *req
+		r.MessageId = r.Id
+		req = &r
+	}
+	return callAs[GetForwardMsgResp](a, "get_forward_msg", req)
+}
+
+func (a *Actions) SendGroupForwardMsg(req *SendGroupForwardMsgReq) (*SendForwardMsgResp, error) {
+	return callAs[SendForwardMsgResp](a, "send_group_forward_msg", req)
+}
+
+func (a *Actions) SendPrivateForwardMsg(req *SendPrivateForwardMsgReq) (*SendForwardMsgResp, error) {
+	return callAs[SendForwardMsgResp](a, "send_private_forward_msg", req)
+}
+
+/* -------- 历史消息 -------- */
+
+func (a *Actions) GetGroupMsgHistory(req *GetGroupMsgHistoryReq) (*MsgHistoryResp, error) {
+	return callAs[MsgHistoryResp](a, "get_group_msg_history", req)
+}
+
+func (a *Actions) GetFriendMsgHistory(req *GetFriendMsgHistoryReq) (*MsgHistoryResp, error) {
+	return callAs[MsgHistoryResp](a, "get_friend_msg_history", req)
+}
+
+/* -------- 群管理 -------- */
+
+func (a *Actions) SetGroupKick(req *SetGroupKickReq) error {
+	return a.call("set_group_kick", req)
+}
+
+func (a *Actions) SetGroupBan(req *SetGroupBanReq) error {
+	return a.call("set_group_ban", req)
+}
+
+func (a *Actions) SetGroupWholeBan(req *SetGroupWholeBanReq) error {
+	return a.call("set_group_whole_ban", req)
+}
+
+func (a *Actions) SetGroupAdmin(req *SetGroupAdminReq) error {
+	return a.call("set_group_admin", req)
+}
+
+func (a *Actions) SetGroupCard(req *SetGroupCardReq) error {
+	return a.call("set_group_card", req)
+}
+
+func (a *Actions) SetGroupName(req *SetGroupNameReq) error {
+	return a.call("set_group_name", req)
+}
+
+func (a *Actions) SetGroupLeave(req *SetGroupLeaveReq) error {
+	return a.call("set_group_leave", req)
+}
+
+func (a *Actions) SetGroupSpecialTitle(req *SetGroupSpecialTitleReq) error {
+	return a.call("set_group_special_title", req)
+}
+
+func (a *Actions) SetEssenceMsg(req *MessageIdReq) error {
+	return a.call("set_essence_msg", req)
+}
+
+func (a *Actions) DeleteEssenceMsg(req *MessageIdReq) error {
+	return a.call("delete_essence_msg", req)
+}
+
+func (a *Actions) GetEssenceMsgList(req *GroupIdReq) ([]EssenceMsg, error) {
+	resp, err := callAs[[]EssenceMsg](a, "get_essence_msg_list", req)
+	if err != nil {
+		return nil, err
+	}
+	return *resp, nil
+}
+
+func (a *Actions) SendGroupNotice(req *SendGroupNoticeReq) error {
+	return a.call("_send_group_notice", req)
+}
+
+/* -------- 账号信息 -------- */
+
+func (a *Actions) GetLoginInfo() (*LoginInfo, error) {
+	return callAs[LoginInfo](a, "get_login_info", nil)
+}
+
+func (a *Actions) GetStrangerInfo(req *GetStrangerInfoReq) (*StrangerInfo, error) {
+	return callAs[StrangerInfo](a, "get_stranger_info", req)
+}
+
+func (a *Actions) GetFriendList() ([]FriendData, error) {
+	resp, err := callAs[[]FriendData](a, "get_friend_list", nil)
+	if err != nil {
+		return nil, err
+	}
+	return *resp, nil
+}
+
+func (a *Actions) GetGroupInfo(req *GetGroupInfoReq) (*GroupData, error) {
+	return callAs[GroupData](a, "get_group_info", req)
+}
+
+func (a *Actions) GetGroupList() ([]GroupData, error) {
+	resp, err := callAs[[]GroupData](a, "get_group_list", nil)
+	if err != nil {
+		return nil, err
+	}
+	return *resp, nil
+}
+
+func (a *Actions) GetGroupMemberInfo(req *GetGroupMemberInfoReq) (*MemberData, error) {
+	return callAs[MemberData](a, "get_group_member_info", req)
+}
+
+func (a *Actions) GetGroupMemberList(req *GetGroupMemberListReq) ([]MemberData, error) {
+	resp, err := callAs[[]MemberData](a, "get_group_member_list", req)
+	if err != nil {
+		return nil, err
+	}
+	return *resp, nil
+}
+
+/* -------- 请求 -------- */
+
+func (a *Actions) SetFriendAddRequest(req *SetFriendAddRequestReq) error {
+	return a.call("set_friend_add_request", req)
+}
+
+func (a *Actions) SetGroupAddRequest(req *SetGroupAddRequestReq) error {
+	if req.Type == "" {
+		r := *req
+		r.Type = r.SubType
+		req = &r
+	}
+	return a.call("set_group_add_request", req)
+}
+
+/* -------- 文件 -------- */
+
+func (a *Actions) UploadGroupFile(req *UploadGroupFileReq) (*UploadFileResp, error) {
+	return callAs[UploadFileResp](a, "upload_group_file", req)
+}
+
+func (a *Actions) UploadPrivateFile(req *UploadPrivateFileReq) (*UploadFileResp, error) {
+	return callAs[UploadFileResp](a, "upload_private_file", req)
+}
+
+func (a *Actions) GetGroupRootFiles(req *GroupIdReq) (*GroupFilesResp, error) {
+	return callAs[GroupFilesResp](a, "get_group_root_files", req)
+}
+
+func (a *Actions) GetGroupFilesByFolder(req *GetGroupFilesByFolderReq) (*GroupFilesResp, error) {
+	return callAs[GroupFilesResp](a, "get_group_files_by_folder", req)
+}
+
+func (a *Actions) GetGroupFileUrl(req *GetGroupFileUrlReq) (*FileUrlResp, error) {
+	return callAs[FileUrlResp](a, "get_group_file_url", req)
+}
+
+func (a *Actions) DeleteGroupFile(req *DeleteGroupFileReq) error {
+	return a.call("delete_group_file", req)
+}
+
+func (a *Actions) CreateGroupFileFolder(req *CreateGroupFileFolderReq) error {
+	return a.call("create_group_file_folder", req)
+}
+
+func (a *Actions) DeleteGroupFolder(req *DeleteGroupFolderReq) error {
+	return a.call("delete_group_folder", req)
+}
+
+func (a *Actions) GetGroupFileSystemInfo(req *GroupIdReq) (*GroupFileSystem, error) {
+	return callAs[GroupFileSystem](a, "get_group_file_system_info", req)
+}
+
+// DownloadFile 让协议端下载文件到本地缓存，返回协议端上的文件路径
+func (a *Actions) DownloadFile(req *DownloadFileReq) (*DownloadFileResp, error) {
+	return callAs[DownloadFileResp](a, "download_file", req)
+}
+
+func (a *Actions) GetImage(req *GetImageReq) (*MediaFileResp, error) {
+	return callAs[MediaFileResp](a, "get_image", req)
+}
+
+func (a *Actions) GetRecord(req *GetRecordReq) (*MediaFileResp, error) {
+	return callAs[MediaFileResp](a, "get_record", req)
+}
+
+/* -------- 状态 -------- */
+
+func (a *Actions) GetStatus() (*Status, error) {
+	return callAs[Status](a, "get_status", nil)
+}
+
+func (a *Actions) GetVersionInfo() (*BotVer, error) {
+	return callAs[BotVer](a, "get_version_info", nil)
+}
+
+func (a *Actions) CanSendImage() (bool, error) {
+	resp, err := callAs[YesResp](a, "can_send_image", nil)
+	if err != nil {
+		return false, err
+	}
+	return resp.Yes, nil
+}
+
+func (a *Actions) CanSendRecord() (bool, error) {
+	resp, err := callAs[YesResp](a, "can_send_record", nil)
+	if err != nil {
+		return false, err
+	}
+	return resp.Yes, nil
+}
@@ -0,0 +1,338 @@
+package client
+
+// OneBot v11 动作的请求和返回结构，字段名与协议保持一致，
+// 已有结构（GroupData、MemberData、GroupFile 等）直接复用
+
+// MessageSegment 数组格式的消息段
+type MessageSegment struct {
+	Type string         `json:"type"`
+	Data map[string]any `json:"data"`
+}
+
+// NewTextSegment 文本消息段
+func NewTextSegment(text string) MessageSegment {
+	return MessageSegment{Type: "text", Data: map[string]any{"text": text}}
+}
+
+// NewForwardNode 合并转发的自定义节点，content 可以是字符串或者消息段数组
+func NewForwardNode(uin int64, name string, content any) MessageSegment {
+	return MessageSegment{Type: "node", Data: map[string]any{
+		"user_id":  uin,
+		"nickname": name,
+		"content":  content,
+	}}
+}
+
+// NewForwardNodeById 引用已有消息的合并转发节点
+func NewForwardNodeById(messageId int32) MessageSegment {
+	return MessageSegment{Type: "node", Data: map[string]any{"id": messageId}}
+}
+
+/* -------- 消息 -------- */
+
+type SendPrivateMsgReq struct {
+	UserId     int64 `json:"user_id"`
+	Message    any   `json:"message"`
+	AutoEscape bool  `json:"auto_escape,omitempty"`
+}
+
+type SendGroupMsgReq struct {
+	GroupId    int64 `json:"group_id"`
+	Message    any   `json:"message"`
+	AutoEscape bool  `json:"auto_escape,omitempty"`
+}
+
+type SendMsgReq struct {
+	MessageType string `json:"message_type,omitempty"`
+	UserId      int64  `json:"user_id,omitempty"`
+	GroupId     int64  `json:"group_id,omitempty"`
+	Message     any    `json:"message"`
+	AutoEscape  bool   `json:"auto_escape,omitempty"`
+}
+
+type SendMsgResp struct {
+	MessageId DynamicInt64 `json:"message_id"`
+}
+
+// MessageIdReq 只需要消息id的动作，如 delete_msg、get_msg、set_essence_msg
+type MessageIdReq struct {
+	MessageId int32 `json:"message_id"`
+}
+
+type SendLikeReq struct {
+	UserId int64 `json:"user_id"`
+	Times  int   `json:"times"`
+}
+
+type GroupPokeReq struct {
+	GroupId int64 `json:"group_id"`
+	UserId  int64 `json:"user_id"`
+}
+
+type FriendPokeReq struct {
+	UserId int64 `json:"user_id"`
+}
+
+type SendForwardMsgResp struct {
+	MessageId DynamicInt64 `json:"message_id"`
+	ForwardId string       `json:"forward_id"`
+}
+
+// GetForwardMsgReq 不同实现分别使用 id 和 message_id 字段，MessageId 为空时使用 Id
+type GetForwardMsgReq struct {
+	Id        string `json:"id"`
+	MessageId string `json:"message_id"`
+}
+
+type SendGroupForwardMsgReq struct {
+	GroupId  int64            `json:"group_id"`
+	Messages []MessageSegment `json:"messages"`
+}
+
+type SendPrivateForwardMsgReq struct {
+	UserId   int64            `json:"user_id"`
+	Messages []MessageSegment `json:"messages"`
+}
+
+// ForwardMessage 合并转发中的一条消息，不同实现分别使用 content 和 message 字段
+type ForwardMessage struct {
+	Sender struct {
+		UserId   DynamicInt64 `json:"user_id"`
+		Nickname string       `json:"nickname"`
+	} `json:"sender"`
+	Time    DynamicInt64 `json:"time"`
+	Content any          `json:"content"`
+	Message any          `json:"message"`
+}
+
+type GetForwardMsgResp struct {
+	Messages []ForwardMessage `json:"messages"`
+}
+
+/* -------- 历史消息 -------- */
+
+type GetGroupMsgHistoryReq struct {
+	GroupId      int64 `json:"group_id"`
+	MessageSeq   int64 `json:"message_seq,omitempty"`
+	Count        int   `json:"count,omitempty"`
+	ReverseOrder bool  `json:"reverseOrder,omitempty"`
+}
+
+type GetFriendMsgHistoryReq struct {
+	UserId       int64 `json:"user_id"`
+	MessageSeq   int64 `json:"message_seq,omitempty"`
+	Count        int   `json:"count,omitempty"`
+	ReverseOrder bool  `json:"reverseOrder,omitempty"`
+}
+
+type MsgHistoryResp struct {
+	Messages []WebSocketMessage `json:"messages"`
+}
+
+/* -------- 群管理 -------- */
+
+// GroupIdReq 只需要群号的动作，如 get_essence_msg_list、get_group_root_files
+type GroupIdReq struct {
+	GroupId int64 `json:"group_id"`
+}
+
+type SetGroupKickReq struct {
+	GroupId          int64 `json:"group_id"`
+	UserId           int64 `json:"user_id"`
+	RejectAddRequest bool  `json:"reject_add_request"`
+}
+
+// SetGroupBanReq Duration 单位为秒，0 为解除禁言
+type SetGroupBanReq struct {
+	GroupId  int64 `json:"group_id"`
+	UserId   int64 `json:"user_id"`
+	Duration int64 `json:"duration"`
+}
+
+type SetGroupWholeBanReq struct {
+	GroupId int64 `json:"group_id"`
+	Enable  bool  `json:"enable"`
+}
+
+type SetGroupAdminReq struct {
+	GroupId int64 `json:"group_id"`
+	UserId  int64 `json:"user_id"`
+	Enable  bool  `json:"enable"`
+}
+
+type SetGroupCardReq struct {
+	GroupId int64  `json:"group_id"`
+	UserId  int64  `json:"user_id"`
+	Card    string `json:"card"`
+}
+
+type SetGroupNameReq struct {
+	GroupId   int64  `json:"group_id"`
+	GroupName string `json:"group_name"`
+}
+
+type SetGroupLeaveReq struct {
+	GroupId   int64 `json:"group_id"`
+	IsDismiss bool  `json:"is_dismiss"`
+}
+
+type SetGroupSpecialTitleReq struct {
+	GroupId      int64  `json:"group_id"`
+	UserId       int64  `json:"user_id"`
+	SpecialTitle string `json:"special_title"`
+}
+
+// SendGroupNoticeReq Image 为空时不带图片
+type SendGroupNoticeReq struct {
+	GroupId int64  `json:"group_id"`
+	Content string `json:"content"`
+	Image   string `json:"image,omitempty"`
+}
+
+type EssenceMsg struct {
+	SenderId     DynamicInt64 `json:"sender_id"`
+	SenderNick   string       `json:"sender_nick"`
+	SenderTime   int64        `json:"sender_time"`
+	OperatorId   DynamicInt64 `json:"operator_id"`
+	OperatorNick string       `json:"operator_nick"`
+	OperatorTime int64        `json:"operator_time"`
+	MessageId    DynamicInt64 `json:"message_id"`
+}
+
+/* -------- 账号信息 -------- */
+
+type LoginInfo struct {
+	UserId   int64  `json:"user_id"`
+	Nickname string `json:"nickname"`
+}
+
+type GetStrangerInfoReq struct {
+	UserId  int64 `json:"user_id"`
+	NoCache bool  `json:"no_cache"`
+}
+
+type GetGroupInfoReq struct {
+	GroupId int64 `json:"group_id"`
+	NoCache bool  `json:"no_cache"`
+}
+
+type GetGroupMemberInfoReq struct {
+	GroupId int64 `json:"group_id"`
+	UserId  int64 `json:"user_id"`
+	NoCache bool  `json:"no_cache"`
+}
+
+type GetGroupMemberListReq struct {
+	GroupId int64 `json:"group_id"`
+	NoCache bool  `json:"no_cache"`
+}
+
+/* -------- 请求 -------- */
+
+type SetFriendAddRequestReq struct {
+	Flag    string `json:"flag"`
+	Approve bool   `json:"approve"`
+	Remark  string `json:"remark"`
+}
+
+// SetGroupAddRequestReq SubType 为 add 或 invite，部分实现使用 type 字段，Type 为空时使用 SubType
+type SetGroupAddRequestReq struct {
+	Flag    string `json:"flag"`
+	SubType string `json:"sub_type"`
+	Type    string `json:"type"`
+	Approve bool   `json:"approve"`
+	Reason  string `json:"reason"`
+}
+
+/* -------- 文件 -------- */
+
+type UploadGroupFileReq struct {
+	GroupId int64  `json:"group_id"`
+	File    string `json:"file"`
+	Name    string `json:"name"`
+	Folder  string `json:"folder,omitempty"`
+}
+
+type UploadPrivateFileReq struct {
+	UserId int64  `json:"user_id"`
+	File   string `json:"file"`
+	Name   string `json:"name"`
+}
+
+type UploadFileResp struct {
+	FileId string `json:"file_id"`
+}
+
+type GroupFilesResp struct {
+	Files   []*GroupFile   `json:"files"`
+	Folders []*GroupFolder `json:"folders"`
+}
+
+type GetGroupFilesByFolderReq struct {
+	GroupId  int64  `json:"group_id"`
+	FolderId string `json:"folder_id"`
+}
+
+type GetGroupFileUrlReq struct {
+	GroupId int64  `json:"group_id"`
+	FileId  string `json:"file_id"`
+	BusId   int32  `json:"busid"`
+}
+
+type DeleteGroupFileReq struct {
+	GroupId int64  `json:"group_id"`
+	FileId  string `json:"file_id"`
+	BusId   int32  `json:"busid"`
+}
+
+// CreateGroupFileFolderReq ParentId 为空时在根目录创建
+type CreateGroupFileFolderReq struct {
+	GroupId  int64  `json:"group_id"`
+	Name     string `json:"name"`
+	ParentId string `json:"parent_id,omitempty"`
+}
+
+type DeleteGroupFolderReq struct {
+	GroupId  int64  `json:"group_id"`
+	FolderId string `json:"folder_id"`
+}
+
+type FileUrlResp struct {
+	Url string `json:"url"`
+}
+
+type DownloadFileReq struct {
+	Url         string   `json:"url,omitempty"`
+	Base64      string   `json:"base64,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	ThreadCount int      `json:"thread_count,omitempty"`
+	Headers     []string `json:"headers,omitempty"`
+}
+
+type DownloadFileResp struct {
+	File string `json:"file"`
+}
+
+type GetImageReq struct {
+	File string `json:"file"`
+}
+
+// GetRecordReq OutFormat 为转换后的格式，如 mp3、amr
+type GetRecordReq struct {
+	File      string `json:"file"`
+	OutFormat string `json:"out_format"`
+}
+
+// MediaFileResp get_image / get_record 返回的本地文件信息
+type MediaFileResp struct {
+	File     string       `json:"file"`
+	Url      string       `json:"url"`
+	FileName string       `json:"file_name"`
+	FileSize DynamicInt64 `json:"file_size"`
+}
+
+/* -------- 状态 -------- */
+
+type YesResp struct {
+	Yes bool `json:"yes"`
+}
@@ -0,0 +1,186 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActions(t *testing.T) {
+	f := newFakeOneBot(t)
+	f.Handle("get_login_info", func(params json.RawMessage) fakeReply {
+		return fakeReply{Data: map[string]any{"user_id": 10000, "nickname": "bot"}}
+	})
+	f.Handle("send_group_msg", func(params json.RawMessage) fakeReply {
+		var req SendGroupMsgReq
+		assert.Nil(t, json.Unmarshal(params, &req))
+		assert.EqualValues(t, 123, req.GroupId)
+		return fakeReply{Data: map[string]any{"message_id": "456"}}
+	})
+	f.Handle("get_group_member_list", func(params json.RawMessage) fakeReply {
+		return fakeReply{Data: []map[string]any{
+			{"group_id": 123, "user_id": 1, "role": "owner"},
+			{"group_id": 123, "user_id": 2, "role": "member"},
+		}}
+	})
+	f.Handle("get_group_msg_history", func(params json.RawMessage) fakeReply {
+		return fakeReply{Data: map[string]any{"messages": []map[string]any{
+			{"message_id": 1, "group_id": 123, "message": "a"},
+			{"message_id": 2, "group_id": 123, "message": "b"},
+		}}}
+	})
+	f.Handle("set_group_ban", func(params json.RawMessage) fakeReply {
+		return fakeReply{}
+	})
+	f.Handle("get_image", func(params json.RawMessage) fakeReply {
+		return fakeReply{Data: map[string]any{"file": "/tmp/a.jpg", "file_size": "1024"}}
+	})
+	f.Handle("can_send_image", func(params json.RawMessage) fakeReply {
+		return fakeReply{Data: map[string]any{"yes": true}}
+	})
+
+	a := f.Connect().Actions()
+
+	info, err := a.GetLoginInfo()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10000, info.UserId)
+	assert.Equal(t, "bot", info.Nickname)
+
+	sendResp, err := a.SendGroupMsg(&SendGroupMsgReq{
+		GroupId: 123,
+		Message: []MessageSegment{NewTextSegment("hello")},
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 456, sendResp.MessageId)
+
+	members, err := a.GetGroupMemberList(&GetGroupMemberListReq{GroupId: 123, NoCache: true})
+	assert.Nil(t, err)
+	assert.Len(t, members, 2)
+	assert.Equal(t, "owner", members[0].Role)
+
+	history, err := a.GetGroupMsgHistory(&GetGroupMsgHistoryReq{GroupId: 123, Count: 2})
+	assert.Nil(t, err)
+	assert.Len(t, history.Messages, 2)
+	assert.EqualValues(t, 2, history.Messages[1].MessageID)
+
+	assert.Nil(t, a.SetGroupBan(&SetGroupBanReq{GroupId: 123, UserId: 2, Duration: 60}))
+	reqs := f.Requests("set_group_ban")
+	assert.Len(t, reqs, 1)
+	var params map[string]any
+	assert.Nil(t, json.Unmarshal(reqs[0].Params, &params))
+	assert.EqualValues(t, 60, params["duration"])
+
+	// file_size 可能是字符串
+	img, err := a.GetImage(&GetImageReq{File: "a.jpg"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1024, img.FileSize)
+
+	yes, err := a.CanSendImage()
+	assert.Nil(t, err)
+	assert.True(t, yes)
+}
+
+func TestActionError(t *testing.T) {
+	f := newFakeOneBot(t)
+	f.Handle("set_group_kick", func(params json.RawMessage) fakeReply {
+		return fakeReply{Status: "failed", RetCode: 1200, Message: "no permission", Wording: "权限不足"}
+	})
+	a := f.Connect().Actions()
+
+	err := a.SetGroupKick(&SetGroupKickReq{GroupId: 123, UserId: 1})
+	actionErr := AsActionError(err)
+	if assert.NotNil(t, actionErr) {
+		assert.Equal(t, "set_group_kick", actionErr.Action)
+		assert.Equal(t, 1200, actionErr.RetCode)
+		assert.False(t, actionErr.IsUnsupported())
+	}
+
+	// 未注册的动作由服务端返回 1404
+	_, err = a.GetForwardMsg(&GetForwardMsgReq{Id: "abc"})
+	actionErr = AsActionError(err)
+	if assert.NotNil(t, actionErr) {
+		assert.True(t, actionErr.IsUnsupported())
+	}
+	// 只设置 Id 时同时发送 message_id
+	reqs := f.Requests("get_forward_msg")
+	if assert.Len(t, reqs, 1) {
+		var fwd GetForwardMsgReq
+		assert.Nil(t, json.Unmarshal(reqs[0].Params, &fwd))
+		assert.Equal(t, "abc", fwd.MessageId)
+	}
+
+	assert.Nil(t, AsActionError(errors.New("other")))
+}
+
+func TestActionTimeout(t *testing.T) {
+	f := newFakeOneBot(t)
+	f.Handle("get_status", func(params json.RawMessage) fakeReply {
+		return fakeReply{NoReply: true}
+	})
+	c := f.Connect()
+
+	_, err := c.Actions().WithTimeout(time.Millisecond * 100).GetStatus()
+	assert.True(t, errors.Is(err, ErrActionTimeout))
+
+	// 超时后不会残留等待中的响应
+	c.responseLock.Lock()
+	assert.Empty(t, c.responseCh)
+	c.responseLock.Unlock()
+}
+
+func TestActionNotConnected(t *testing.T) {
+	c := new(QQClient)
+	_, err := c.Actions().GetLoginInfo()
+	assert.True(t, errors.Is(err, ErrNotConnected))
+}
+
+func TestSendApi(t *testing.T) {
+	f := newFakeOneBot(t)
+	f.Handle("get_group_info", func(params json.RawMessage) fakeReply {
+		return fakeReply{Data: map[string]any{"group_id": 123, "group_name": "test"}}
+	})
+	c := f.Connect()
+
+	data, err := c.SendApi("get_group_info", map[string]any{"group_id": 123})
+	assert.Nil(t, err)
+	assert.Equal(t, "test", data.(map[string]any)["group_name"])
+
+	group, err := c.GetGroupInfo(123)
+	assert.Nil(t, err)
+	assert.Equal(t, "test", group.Name)
+
+	_, err = c.SendApi("not_exist", nil)
+	assert.NotNil(t, AsActionError(err))
+}
+
+func TestGetGroupMessageHistory(t *testing.T) {
+	f := newFakeOneBot(t)
+	f.Handle("get_group_msg_history", func(params json.RawMessage) fakeReply {
+		return fakeReply{Data: map[string]any{"messages": []map[string]any{
+			{
+				"message_id": 2, "group_id": 123, "user_id": 1, "time": 200, "message_type": "group",
+				"sender":  map[string]any{"user_id": 1, "nickname": "b"},
+				"message": []map[string]any{{"type": "text", "data": map[string]any{"text": "world"}}},
+			},
+			{
+				"message_id": 1, "group_id": 123, "user_id": 2, "time": 100, "message_type": "group",
+				"sender":  map[string]any{"user_id": 2, "nickname": "a"},
+				"message": []map[string]any{{"type": "text", "data": map[string]any{"text": "hello"}}},
+			},
+		}}}
+	})
+	c := f.Connect()
+
+	msgs, err := c.GetGroupMessageHistory(123, 0, 2)
+	assert.Nil(t, err)
+	if assert.Len(t, msgs, 2) {
+		// 按时间从旧到新
+		assert.EqualValues(t, 1, msgs[0].Id)
+		assert.EqualValues(t, 123, msgs[0].GroupCode)
+		assert.EqualValues(t, 2, msgs[0].Sender.Uin)
+		assert.EqualValues(t, 200, msgs[1].Time)
+	}
+}
@@ -141,6 +141,7 @@ type QQClient struct {
 	ws                 *websocket.Conn
 	wsWriteLock        sync.Mutex
 	responseCh         map[string]chan *T
+	responseLock       sync.Mutex
 	disconnectChan     chan bool
 	currentEcho        string
 	limiterMessageSend *RateLimiter
@@ -366,12 +367,12 @@ type CardMessageMeta struct {
 
 // 返回结构
 type T struct {
-	Status  string `json:"status"`
-	RetCode int    `json:"retcode"`
-	Message string `json:"message"`
-	Msg     string `json:"msg"`
-	Wording string `json:"wording"`
-	Data    any    `json:"data"`
+	Status  string          `json:"status"`
+	RetCode int             `json:"retcode"`
+	Message string          `json:"message"`
+	Msg     string          `json:"msg"`
+	Wording string          `json:"wording"`
+	Data    json.RawMessage `json:"data"`
 }
 
 // Window represents a fixed-window
@@ -738,7 +739,10 @@ func (c *QQClient) handleResponse(p []byte) {
 		return
 	}
 	if basicMsg.Echo != "" {
+		c.responseLock.Lock()
 		respCh, isResponse := c.responseCh[basicMsg.Echo]
+		delete(c.responseCh, basicMsg.Echo)
+		c.responseLock.Unlock()
 		if !isResponse {
 			logger.Warnf("No response channel for echo: %s", basicMsg.Echo)
 			return
@@ -1176,37 +1180,21 @@ func (c *QQClient) handleBotOfflineNotice(_ WebSocketMessage) (bool, error) {
 }
 
 func (c *QQClient) getBotVer() BotVer {
-	var resp BotVer
-	data, err := c.SendApi("get_version_info", nil)
+	resp, err := c.Actions().GetVersionInfo()
 	if err != nil {
 		logger.Warnf("获取BOT实现版本失败: %v", err)
+		return BotVer{}
 	}
-	t, err := json.Marshal(data)
-	if err != nil {
-		logger.Warnf("解析BOT实现版本失败: %v", err)
-	}
-	err = json.Unmarshal(t, &resp)
-	if err != nil {
-		logger.Warnf("解析BOT实现版本失败: %v", err)
-	}
-	return resp
+	return *resp
 }
 
 func (c *QQClient) getStatus() Status {
-	var stat Status
-	resp, err := c.SendApi("get_status", nil)
+	resp, err := c.Actions().GetStatus()
 	if err != nil {
 		logger.Warnf("获取BOT状态失败: %v", err)
+		return Status{}
 	}
-	t, err := json.Marshal(resp)
-	if err != nil {
-		logger.Warnf("解析BOT状态信息失败: %v", err)
-	}
-	err = json.Unmarshal(t, &stat)
-	if err != nil {
-		logger.Warnf("解析BOT状态信息失败: %v", err)
-	}
-	return stat
+	return *resp
 }
 
 func (c *QQClient) handleGroupEssence(wsmsg WebSocketMessage) (bool, error) {
@@ -1275,26 +1263,11 @@ func (c *QQClient) RecallMsg(msgId int32) error {
 }
 
 func (c *QQClient) GetFileUrl(groupCode int64, fileId string) string {
-	data, err := c.SendApi("get_group_file_url", map[string]any{
-		"group_id": groupCode,
-		"file_id":  fileId,
-	})
+	resp, err := c.Actions().GetGroupFileUrl(&GetGroupFileUrlReq{GroupId: groupCode, FileId: fileId})
 	if err != nil {
 		logger.Errorf("获取群文件链接失败: %v", err)
 		return ""
 	}
-	t, err := json.Marshal(data)
-	if err != nil {
-		logger.Errorf("解析群文件链接失败: %v", err)
-	}
-	type Resp struct {
-		Url string `json:"url"`
-	}
-	var resp Resp
-	err = json.Unmarshal(t, &resp)
-	if err != nil {
-		logger.Errorf("解析群文件链接失败: %v", err)
-	}
 	return resp.Url
 }
 
@@ -1318,7 +1291,7 @@ func (c *QQClient) handleNoticeEvent(wsmsg WebSocketMessage) {
 		logger.Infof("收到 通知事件 消息：%s: %s", wsmsg.NoticeType, wsmsg.SubType)
 		needSync, err = handler(wsmsg)
 		if err != nil {
-			logger.Warnf(err.Error())
+			logger.Warn(err.Error())
 		}
 	} else {
 		logger.Warnf("未知 通知事件 类型: %s", wsmsg.NoticeType)
@@ -1902,64 +1875,39 @@ func (c *QQClient) DownloadFile(url, base64, name string, headers []string) (str
 	if url == "" && base64 == "" {
 		return "", errors.New("url 或 base64 参数不能为空")
 	}
-	params := map[string]any{
-		"url":    url,
-		"base64": base64,
-		"name":   name,
-	}
-	if len(headers) > 0 {
-		params["headers"] = headers
-	}
-
-	rsp, err := c.SendApi("download_file", params)
+	// 下载视频等大文件时协议端需要下载完成才会响应
+	rsp, err := c.Actions().WithTimeout(groupFileUploadTimeout).DownloadFile(&DownloadFileReq{
+		Url:     url,
+		Base64:  base64,
+		Name:    name,
+		Headers: headers,
+	})
 	if err != nil {
 		return "", fmt.Errorf("API请求失败: %w", err)
 	}
-
-	if fileName, ok := rsp.(map[string]any)["file"].(string); ok {
-		return fileName, nil
+	if rsp.File == "" {
+		return "", errors.New("无效的API响应结构")
 	}
-
-	return "", errors.New("无效的API响应结构")
+	return rsp.File, nil
 }
 
 func (c *QQClient) SendApi(api string, params map[string]any, expTime ...float64) (any, error) {
 	// 设置超时时间
-	var timeout float64
+	timeout := defaultActionTimeout
 	if len(expTime) > 0 {
-		timeout = expTime[0]
-	} else {
-		timeout = 10
-	}
-	// 构建请求
-	echo := generateEcho(api)
-	req := map[string]any{
-		"action": api,
-		"params": params,
-		"echo":   echo,
-	}
-	// 发送请求
-	data, _ := json.Marshal(req)
-	ch := make(chan *T)
-	c.responseCh[echo] = ch
-	c.sendToWebSocketClient(c.ws, data)
-	// 等待返回
-	select {
-	case res := <-ch:
-		if res.Status == "ok" {
-			return res.Data, nil
-		} else {
-			errMsg := res.Wording
-			if res.Message != "" {
-				errMsg += ": " + res.Message
-			} else if res.Msg != "" {
-				errMsg += ": " + res.Msg
-			}
-			return nil, errors.New(errMsg)
+		timeout = time.Duration(expTime[0] * float64(time.Second))
+	}
+	raw, err := c.callAction(api, params, timeout)
+	if err != nil {
+		return nil, err
+	}
+	var data any
+	if len(raw) > 0 {
+		if err = json.Unmarshal(raw, &data); err != nil {
+			return nil, errors.Wrapf(err, "%v 解析返回数据失败", api)
 		}
-	case <-time.After(time.Second * time.Duration(timeout)):
-		return nil, errors.New(api + " timeout")
 	}
+	return data, nil
 }
 
 func (c *QQClient) OutputReceivingMessage(Msg interface{}) {
@@ -2131,8 +2079,10 @@ func (c *QQClient) reverseConn(mode string) {
 func (c *QQClient) wsInit(ws *websocket.Conn, mode string) {
 	logger.Info("有新的ws连接了!!")
 	//初始化变量
+	c.responseLock.Lock()
 	c.ws = ws
 	c.responseCh = make(map[string]chan *T)
+	c.responseLock.Unlock()
 	// 初始化流控
 	c.limiterMessageSend = NewRateLimiter(time.Second, 5, func() Window {
 		return NewLocalWindow()
@@ -2275,14 +2225,13 @@ func getOfflineQueueExpire() time.Duration {
 	return t
 }
 
-func (c *QQClient) sendToWebSocketClient(ws *websocket.Conn, message []byte) {
-	if ws != nil {
-		c.wsWriteLock.Lock()
-		if err := ws.WriteMessage(websocket.TextMessage, message); err != nil {
-			logger.Errorf("Failed to send message to WebSocket client: %v", err)
-		}
-		c.wsWriteLock.Unlock()
+func (c *QQClient) writeWebSocket(ws *websocket.Conn, message []byte) error {
+	if ws == nil {
+		return ErrNotConnected
 	}
+	c.wsWriteLock.Lock()
+	defer c.wsWriteLock.Unlock()
+	return ws.WriteMessage(websocket.TextMessage, message)
 }
 
 // NewClient create new qq client
@@ -2588,18 +2537,10 @@ func (c *QQClient) ReloadFriendList() error {
 }
 
 func (c *QQClient) GetFriendList() ([]*FriendInfo, error) {
-	data, err := c.SendApi("get_friend_list", nil)
-	if err != nil {
-		return nil, err
-	}
-	t, err := json.Marshal(data)
+	resp, err := c.Actions().GetFriendList()
 	if err != nil {
 		return nil, err
 	}
-	var resp []FriendData
-	if err := json.Unmarshal(t, &resp); err != nil {
-		return nil, err
-	}
 	friends := make([]*FriendInfo, len(resp))
 	c.debug("GetFriendList: %v", resp)
 	for i, friend := range resp {
@@ -2615,18 +2556,10 @@ func (c *QQClient) GetFriendList() ([]*FriendInfo, error) {
 }
 
 func (c *QQClient) GetGroupInfo(groupCode int64) (*GroupInfo, error) {
-	data, err := c.SendApi("get_group_info", map[string]any{"group_id": groupCode})
+	resp, err := c.Actions().GetGroupInfo(&GetGroupInfoReq{GroupId: groupCode})
 	if err != nil {
 		return nil, err
 	}
-	var resp GroupData
-	t, err := json.Marshal(data)
-	if err != nil {
-		return nil, err
-	}
-	if err := json.Unmarshal(t, &resp); err != nil {
-		return nil, err
-	}
 	return &GroupInfo{
 		Uin:             resp.GroupID,
 		Code:            resp.GroupID,
@@ -2667,24 +2600,18 @@ func (c *QQClient) ReloadGroupList() error {
 	return nil
 }
 
+var echoSeq atomic.Int64
+
 func generateEcho(action string) string {
 	timestamp := time.Now().UnixNano()
-	return fmt.Sprintf("%s:%d", action, timestamp)
+	return fmt.Sprintf("%s:%d:%d", action, timestamp, echoSeq.Add(1))
 }
 
 func (c *QQClient) GetGroupList() ([]*GroupInfo, error) {
-	data, err := c.SendApi("get_group_list", nil)
-	if err != nil {
-		return nil, err
-	}
-	var resp []GroupData
-	t, err := json.Marshal(data)
+	resp, err := c.Actions().GetGroupList()
 	if err != nil {
 		return nil, err
 	}
-	if err := json.Unmarshal(t, &resp); err != nil {
-		return nil, err
-	}
 	groups := make([]*GroupInfo, len(resp))
 	for i, group := range resp {
 		groups[i] = &GroupInfo{
@@ -2706,21 +2633,10 @@ func (c *QQClient) GetGroupMembers(group *GroupInfo) ([]*GroupMemberInfo, error)
 }
 
 func (c *QQClient) getGroupMembers(group *GroupInfo) ([]*GroupMemberInfo, error) {
-	data, err := c.SendApi("get_group_member_list", map[string]any{
-		"group_id": group.Uin,
-		"no_cache": true,
-	})
+	resp, err := c.Actions().GetGroupMemberList(&GetGroupMemberListReq{GroupId: group.Uin, NoCache: true})
 	if err != nil {
 		return nil, err
 	}
-	var resp []MemberData
-	t, err := json.Marshal(data)
-	if err != nil {
-		return nil, err
-	}
-	if err := json.Unmarshal(t, &resp); err != nil {
-		return nil, err
-	}
 	members := make([]*GroupMemberInfo, len(resp))
 	for i, member := range resp {
 		var permission MemberPermission
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// fakeReply 模拟服务端对一个动作的响应，NoReply 为 true 时不响应
+type fakeReply struct {
+	Status  string
+	RetCode int
+	Data    any
+	Message string
+	Wording string
+	NoReply bool
+}
+
+type fakeRequest struct {
+	Action string          `json:"action"`
+	Params json.RawMessage `json:"params"`
+	Echo   string          `json:"echo"`
+}
+
+// fakeOneBot 本地的 OneBot v11 正向 websocket 服务端，用于测试动作调用
+type fakeOneBot struct {
+	t        *testing.T
+	server   *httptest.Server
+	mu       sync.Mutex
+	handlers map[string]func(params json.RawMessage) fakeReply
+	requests []fakeRequest
+}
+
+func newFakeOneBot(t *testing.T) *fakeOneBot {
+	f := &fakeOneBot{
+		t:        t,
+		handlers: make(map[string]func(params json.RawMessage) fakeReply),
+	}
+	upgrader := websocket.Upgrader{}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade error %v", err)
+			return
+		}
+		defer ws.Close()
+		var writeLock sync.Mutex
+		for {
+			_, p, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			var req fakeRequest
+			if err = json.Unmarshal(p, &req); err != nil {
+				t.Errorf("bad request %v", string(p))
+				continue
+			}
+			go func() {
+				reply := f.reply(req)
+				if reply.NoReply {
+					return
+				}
+				b, _ := json.Marshal(map[string]any{
+					"status":  reply.Status,
+					"retcode": reply.RetCode,
+					"data":    reply.Data,
+					"message": reply.Message,
+					"wording": reply.Wording,
+					"echo":    req.Echo,
+				})
+				writeLock.Lock()
+				defer writeLock.Unlock()
+				_ = ws.WriteMessage(websocket.TextMessage, b)
+			}()
+		}
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeOneBot) reply(req fakeRequest) fakeReply {
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	handler, found := f.handlers[req.Action]
+	f.mu.Unlock()
+	if !found {
+		return fakeReply{Status: "failed", RetCode: RetCodeUnsupported, Message: "unsupported action", Wording: "不支持的动作"}
+	}
+	reply := handler(req.Params)
+	if reply.Status == "" {
+		reply.Status = "ok"
+	}
+	return reply
+}
+
+// Handle 注册动作的处理函数
+func (f *fakeOneBot) Handle(action string, handler func(params json.RawMessage) fakeReply) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[action] = handler
+}
+
+// Requests 返回收到的指定动作的请求
+func (f *fakeOneBot) Requests(action string) []fakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []fakeRequest
+	for _, req := range f.requests {
+		if req.Action == action {
+			result = append(result, req)
+		}
+	}
+	return result
+}
+
+// Connect 创建一个连接到该服务端的 QQClient
+func (f *fakeOneBot) Connect() *QQClient {
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(f.server.URL, "http"), nil)
+	if err != nil {
+		f.t.Fatalf("dial error %v", err)
+	}
+	f.t.Cleanup(func() { ws.Close() })
+	c := &QQClient{
+		ws:         ws,
+		responseCh: make(map[string]chan *T),
+	}
+	go func() {
+		for {
+			_, p, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			c.handleResponse(p)
+		}
+	}()
+	return c
+}
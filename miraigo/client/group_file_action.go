@@ -0,0 +1,100 @@
+package client
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// 通过 OneBot 动作管理群文件，GroupFileSystem 中的方法依赖旧协议，在 OneBot 下不可用
+
+// groupFileUploadTimeout 上传群文件时协议端需要先完成上传才会响应
+const groupFileUploadTimeout = time.Minute * 5
+
+// UploadGroupFile 上传群文件，file 为协议端可以访问的本地路径，folderId 为空时上传到根目录
+// 返回文件id，部分协议端不会返回文件id，此时为空
+func (c *QQClient) UploadGroupFile(groupCode int64, file, name, folderId string) (string, error) {
+	if file == "" {
+		return "", errors.New("file 参数不能为空")
+	}
+	resp, err := c.Actions().WithTimeout(groupFileUploadTimeout).UploadGroupFile(&UploadGroupFileReq{
+		GroupId: groupCode,
+		File:    file,
+		Name:    name,
+		Folder:  folderId,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.FileId, nil
+}
+
+// GetGroupRootFiles 获取群文件根目录下的文件和文件夹
+func (c *QQClient) GetGroupRootFiles(groupCode int64) ([]*GroupFile, []*GroupFolder, error) {
+	resp, err := c.Actions().GetGroupRootFiles(&GroupIdReq{GroupId: groupCode})
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Files, resp.Folders, nil
+}
+
+// GetGroupFilesByFolder 获取群文件夹下的文件和文件夹
+func (c *QQClient) GetGroupFilesByFolder(groupCode int64, folderId string) ([]*GroupFile, []*GroupFolder, error) {
+	resp, err := c.Actions().GetGroupFilesByFolder(&GetGroupFilesByFolderReq{GroupId: groupCode, FolderId: folderId})
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Files, resp.Folders, nil
+}
+
+// CreateGroupFolder 在群文件根目录下创建文件夹
+func (c *QQClient) CreateGroupFolder(groupCode int64, name string) error {
+	return c.Actions().CreateGroupFileFolder(&CreateGroupFileFolderReq{GroupId: groupCode, Name: name})
+}
+
+// DeleteGroupFolder 删除群文件夹，需要管理权限
+func (c *QQClient) DeleteGroupFolder(groupCode int64, folderId string) error {
+	return c.Actions().DeleteGroupFolder(&DeleteGroupFolderReq{GroupId: groupCode, FolderId: folderId})
+}
+
+// DeleteGroupFile 删除群文件，需要管理权限或者是自己发的文件
+func (c *QQClient) DeleteGroupFile(groupCode int64, fileId string, busId int32) error {
+	return c.Actions().DeleteGroupFile(&DeleteGroupFileReq{GroupId: groupCode, FileId: fileId, BusId: busId})
+}
+
+// FindGroupFolder 在群文件根目录下按名称查找文件夹，没有找到时返回 nil
+func (c *QQClient) FindGroupFolder(groupCode int64, name string) (*GroupFolder, error) {
+	_, folders, err := c.GetGroupRootFiles(groupCode)
+	if err != nil {
+		return nil, err
+	}
+	for _, folder := range folders {
+		if folder.FolderName == name {
+			return folder, nil
+		}
+	}
+	return nil, nil
+}
+
+// EnsureGroupFolder 返回群文件根目录下指定名称的文件夹id，不存在时创建
+func (c *QQClient) EnsureGroupFolder(groupCode int64, name string) (string, error) {
+	folder, err := c.FindGroupFolder(groupCode, name)
+	if err != nil {
+		return "", err
+	}
+	if folder != nil {
+		return folder.FolderId, nil
+	}
+	if err = c.CreateGroupFolder(groupCode, name); err != nil {
+		return "", errors.Wrapf(err, "创建群文件夹 %v 失败", name)
+	}
+	// 创建文件夹的动作不一定返回文件夹id，重新查询一次
+	folder, err = c.FindGroupFolder(groupCode, name)
+	if err != nil {
+		return "", err
+	}
+	if folder == nil {
+		return "", errors.Errorf("创建群文件夹 %v 后没有找到该文件夹", name)
+	}
+	return folder.FolderId, nil
+}
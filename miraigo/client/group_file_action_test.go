@@ -0,0 +1,70 @@
+package client
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupFileActions(t *testing.T) {
+	f := newFakeOneBot(t)
+	var mu sync.Mutex
+	var folders []map[string]any
+	f.Handle("get_group_root_files", func(params json.RawMessage) fakeReply {
+		mu.Lock()
+		defer mu.Unlock()
+		return fakeReply{Data: map[string]any{
+			"files":   []map[string]any{{"group_id": 123, "file_id": "/f1", "file_name": "a.txt", "busid": 102}},
+			"folders": folders,
+		}}
+	})
+	f.Handle("create_group_file_folder", func(params json.RawMessage) fakeReply {
+		var req map[string]any
+		assert.Nil(t, json.Unmarshal(params, &req))
+		mu.Lock()
+		defer mu.Unlock()
+		folders = append(folders, map[string]any{"group_id": 123, "folder_id": "/d1", "folder_name": req["name"]})
+		return fakeReply{}
+	})
+	f.Handle("upload_group_file", func(params json.RawMessage) fakeReply {
+		var req UploadGroupFileReq
+		assert.Nil(t, json.Unmarshal(params, &req))
+		assert.Equal(t, "/d1", req.Folder)
+		return fakeReply{Data: map[string]any{"file_id": "/f2"}}
+	})
+	c := f.Connect()
+
+	files, dirs, err := c.GetGroupRootFiles(123)
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+	assert.Empty(t, dirs)
+	assert.Equal(t, "a.txt", files[0].FileName)
+
+	folder, err := c.FindGroupFolder(123, "存档")
+	assert.Nil(t, err)
+	assert.Nil(t, folder)
+
+	// 不存在时创建
+	folderId, err := c.EnsureGroupFolder(123, "存档")
+	assert.Nil(t, err)
+	assert.Equal(t, "/d1", folderId)
+	assert.Len(t, f.Requests("create_group_file_folder"), 1)
+
+	// 已经存在时不会重复创建
+	folderId, err = c.EnsureGroupFolder(123, "存档")
+	assert.Nil(t, err)
+	assert.Equal(t, "/d1", folderId)
+	assert.Len(t, f.Requests("create_group_file_folder"), 1)
+
+	fileId, err := c.UploadGroupFile(123, "/tmp/a.jpg", "a.jpg", folderId)
+	assert.Nil(t, err)
+	assert.Equal(t, "/f2", fileId)
+
+	_, err = c.UploadGroupFile(123, "", "a.jpg", folderId)
+	assert.NotNil(t, err)
+
+	// 协议端不支持删除文件夹
+	assert.NotNil(t, AsActionError(c.DeleteGroupFolder(123, folderId)))
+}
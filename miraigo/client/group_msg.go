@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"math"
 	"math/rand"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -107,7 +108,7 @@ func (c *QQClient) RealSendMSG(groupCode int64, m *message.SendingMessage, newst
 	logger.Infof("本次发送总长: %d, 图片: %d, 视频: %d, 语音：%d, 文件：%d", msgLen, imgCount, videoCount, recordCount, fileCount)
 	//判断是否超过最大发送长度
 	if msgLen > message.MaxMessageSize || imgCount > 20 {
-		return nil, errors.New("消息或图片长度超限，取消本次发送")
+		return nil, ErrMessageTooLong
 	}
 	expTime := 120.00
 	group := c.FindGroup(groupCode)
@@ -694,3 +695,36 @@ func (c *QQClient) GetGroupEssenceMsgList(groupCode int64) ([]GroupDigest, error
 	}
 	return data.List, nil
 }
+
+// GetGroupMessageHistory 通过 get_group_msg_history 获取群历史消息，按时间从旧到新排列，
+// messageSeq 为0时从最新的消息开始向前获取
+func (c *QQClient) GetGroupMessageHistory(groupCode int64, messageSeq int64, count int) ([]*message.GroupMessage, error) {
+	resp, err := c.Actions().WithTimeout(time.Second * 30).GetGroupMsgHistory(&GetGroupMsgHistoryReq{
+		GroupId:    groupCode,
+		MessageSeq: messageSeq,
+		Count:      count,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var groupName string
+	if g := c.FindGroup(groupCode); g != nil {
+		groupName = g.Name
+	}
+	var result []*message.GroupMessage
+	for _, wsmsg := range resp.Messages {
+		if wsmsg.GroupID == 0 {
+			wsmsg.GroupID = DynamicInt64(groupCode)
+		}
+		gMsg, ok := c.ChatMsgHandler(wsmsg, c.createGroupMessage(wsmsg)).(*message.GroupMessage)
+		if !ok || gMsg == nil {
+			continue
+		}
+		gMsg.GroupName = groupName
+		result = append(result, gMsg)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time < result[j].Time
+	})
+	return result, nil
+}
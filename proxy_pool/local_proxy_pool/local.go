@@ -2,14 +2,60 @@ package local_proxy_pool
 
 import (
 	"errors"
-	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
-	"go.uber.org/atomic"
+	"fmt"
+	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sora233/MiraiGo-Template/utils"
+	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
+)
+
+var logger = utils.GetModuleLogger("local_proxy_pool")
+
+var (
+	ErrNoProxy          = errors.New("no proxy found")
+	ErrNoAvailableProxy = errors.New("no available proxy")
 )
 
+const (
+	// 连续失败这么多次之后暂时移出代理池
+	defaultFailThreshold = 3
+	// 两次失败的间隔超过这个时间时重新计数，中间的请求大概率是成功的
+	defaultFailWindow = time.Minute * 10
+	// 移出的时间从 defaultEvictBase 开始，每次移出翻倍，最多 defaultEvictMax
+	defaultEvictBase = time.Minute * 5
+	defaultEvictMax  = time.Hour
+	// 连通性检查的超时时间
+	defaultCheckTimeout = time.Second * 10
+)
+
+// DefaultCheckURL 各个类型的代理默认使用的连通性检查地址
+var DefaultCheckURL = map[proxy_pool.Prefer]string{
+	proxy_pool.PreferMainland: "https://www.baidu.com/",
+	proxy_pool.PreferOversea:  "https://www.gstatic.com/generate_204",
+}
+
 type Proxy struct {
 	Proxy string
 	Type  proxy_pool.Prefer
+	// Weight 选择的权重，小于等于0时视为1
+	Weight int
+}
+
+// ParseProxy 解析配置中的代理，可以使用 #权重 后缀指定权重，例如 http://127.0.0.1:7890#3
+func ParseProxy(s string, prefer proxy_pool.Prefer) *Proxy {
+	p := &Proxy{Proxy: strings.TrimSpace(s), Type: prefer}
+	if idx := strings.LastIndex(p.Proxy, "#"); idx >= 0 {
+		if w, err := strconv.Atoi(p.Proxy[idx+1:]); err == nil {
+			p.Weight = w
+			p.Proxy = p.Proxy[:idx]
+		}
+	}
+	return p
 }
 
 func (p *Proxy) ProxyString() string {
@@ -27,54 +73,353 @@ func (p *Proxy) Prefer() proxy_pool.Prefer {
 	return p.Type
 }
 
-type Pool struct {
-	proxies   map[proxy_pool.Prefer][]*Proxy
-	cnt       map[proxy_pool.Prefer]*atomic.Uint32
-	total     int
-	preferCnt atomic.Uint32
+func (p *Proxy) weight() int {
+	if p.Weight <= 0 {
+		return 1
+	}
+	return p.Weight
 }
 
-func (p *Pool) Get(prefer proxy_pool.Prefer) (proxy_pool.IProxy, error) {
-	if prefer == proxy_pool.PreferAny {
-		cnt := p.preferCnt.Add(1) % uint32(len(p.proxies))
-		var index uint32 = 0
-		for k := range p.proxies {
-			if index == cnt {
-				prefer = k
-			} else {
-				index++
+// entry 代理和它的健康状态，所有字段由 Pool.mu 保护
+type entry struct {
+	*Proxy
+	current      int
+	failures     int
+	lastFailure  time.Time
+	evictCount   int
+	evictedUntil time.Time
+	// evictedByCheck 是否因为连通性检查失败而移出
+	evictedByCheck bool
+	lastCheck      time.Time
+	lastCheckOK    bool
+	latency        time.Duration
+}
+
+func (e *entry) available(now time.Time) bool {
+	return !now.Before(e.evictedUntil)
+}
+
+type Option func(p *Pool)
+
+// WithSticky 开启后 GetSticky 对同一个key尽量返回同一个代理
+func WithSticky(sticky bool) Option {
+	return func(p *Pool) {
+		p.sticky = sticky
+	}
+}
+
+// WithHealthCheck 每隔 interval 通过代理访问 checkURL 检查连通性，interval 为0时不检查
+// checkURL 中没有的类型使用 DefaultCheckURL
+func WithHealthCheck(interval time.Duration, checkURL map[proxy_pool.Prefer]string) Option {
+	return func(p *Pool) {
+		p.checkInterval = interval
+		for prefer, u := range checkURL {
+			if u != "" {
+				p.checkURL[prefer] = u
 			}
 		}
 	}
+}
+
+// WithFailWindow 两次失败的间隔超过 window 时重新计算连续失败的次数
+func WithFailWindow(window time.Duration) Option {
+	return func(p *Pool) {
+		if window > 0 {
+			p.failWindow = window
+		}
+	}
+}
+
+// WithEvict 连续失败 threshold 次之后移出代理池，移出时间从 base 开始翻倍，最多 max
+func WithEvict(threshold int, base, max time.Duration) Option {
+	return func(p *Pool) {
+		if threshold > 0 {
+			p.failThreshold = threshold
+		}
+		if base > 0 {
+			p.evictBase = base
+		}
+		if max > 0 {
+			p.evictMax = max
+		}
+	}
+}
+
+type Pool struct {
+	mu      sync.Mutex
+	proxies map[proxy_pool.Prefer][]*entry
+	all     []*entry
+	// stickyMap 记录每个key上一次使用的代理
+	stickyMap map[string]*entry
+	sticky    bool
+
+	failThreshold int
+	failWindow    time.Duration
+	evictBase     time.Duration
+	evictMax      time.Duration
+	checkInterval time.Duration
+	checkURL      map[proxy_pool.Prefer]string
+	checkTimeout  time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func (p *Pool) Get(prefer proxy_pool.Prefer) (proxy_pool.IProxy, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e, err := p.pick(prefer, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return e.Proxy, nil
+}
+
+// GetSticky 同一个key在代理可用时总是返回同一个代理，没有开启 WithSticky 时和 Get 相同
+func (p *Pool) GetSticky(prefer proxy_pool.Prefer, key string) (proxy_pool.IProxy, error) {
+	if !p.sticky {
+		return p.Get(prefer)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var now = time.Now()
+	var stickyKey = fmt.Sprintf("%v/%v", prefer, key)
+	if e, found := p.stickyMap[stickyKey]; found && e.available(now) {
+		return e.Proxy, nil
+	}
+	e, err := p.pick(prefer, now)
+	if err != nil {
+		return nil, err
+	}
+	p.stickyMap[stickyKey] = e
+	return e.Proxy, nil
+}
 
-	if s, found := p.proxies[prefer]; !found {
-		return nil, errors.New("no proxy found")
+// pick 在可用的代理中使用平滑加权轮询选择，调用时需要持有锁
+func (p *Pool) pick(prefer proxy_pool.Prefer, now time.Time) (*entry, error) {
+	var candidates []*entry
+	if prefer == proxy_pool.PreferAny {
+		candidates = p.all
 	} else {
-		index := p.cnt[prefer].Add(1)
-		return s[index%uint32(len(s))], nil
+		candidates = p.proxies[prefer]
+	}
+	if len(candidates) == 0 {
+		return nil, ErrNoProxy
+	}
+	var total int
+	var best *entry
+	for _, e := range candidates {
+		if !e.available(now) {
+			continue
+		}
+		e.current += e.weight()
+		total += e.weight()
+		if best == nil || e.current > best.current {
+			best = e
+		}
+	}
+	if best == nil {
+		return nil, ErrNoAvailableProxy
 	}
+	best.current -= total
+	return best, nil
 }
 
+// Delete 报告代理请求失败，连续失败达到阈值后暂时移出代理池，返回代理是否在代理池中
 func (p *Pool) Delete(proxy string) bool {
-	return false
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var found bool
+	var now = time.Now()
+	for _, e := range p.all {
+		if e.ProxyString() != proxy {
+			continue
+		}
+		found = true
+		// 移出期间报告的失败是移出前发出的请求，不计数
+		if !e.available(now) {
+			continue
+		}
+		// 上一次失败在移出结束之前，或者已经过了计数的时间，重新计数
+		if e.lastFailure.Before(e.evictedUntil) || now.Sub(e.lastFailure) > p.failWindow {
+			e.failures = 0
+		}
+		e.failures++
+		e.lastFailure = now
+		if e.failures >= p.failThreshold {
+			p.evict(e, now, false)
+			logger.WithField("proxy", proxy).WithField("until", e.evictedUntil).
+				Info("代理连续失败，暂时移出代理池")
+		}
+	}
+	return found
+}
+
+// evict 暂时移出代理，调用时需要持有锁
+func (p *Pool) evict(e *entry, now time.Time, byCheck bool) {
+	e.evictCount++
+	d := p.evictBase << (e.evictCount - 1)
+	if d <= 0 || d > p.evictMax {
+		d = p.evictMax
+	}
+	// 被 Delete 移出期间检查失败时，仍然按照 Delete 移出处理
+	e.evictedByCheck = byCheck && (e.available(now) || e.evictedByCheck)
+	e.evictedUntil = now.Add(d)
+	e.current = 0
+	e.failures = 0
+}
+
+// Status 返回所有代理的状态
+func (p *Pool) Status() []*proxy_pool.ProxyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var now = time.Now()
+	var result []*proxy_pool.ProxyStatus
+	for _, e := range p.all {
+		status := &proxy_pool.ProxyStatus{
+			Proxy:       e.ProxyString(),
+			Prefer:      e.Type,
+			Weight:      e.weight(),
+			Available:   e.available(now),
+			Failures:    e.failures,
+			LastCheck:   e.lastCheck,
+			LastCheckOK: e.lastCheckOK,
+			Latency:     e.latency,
+		}
+		if !status.Available {
+			status.EvictedUntil = e.evictedUntil
+		}
+		result = append(result, status)
+	}
+	return result
+}
+
+// CheckNow 立即检查所有代理的连通性，包括已经移出的代理
+func (p *Pool) CheckNow() {
+	p.mu.Lock()
+	var entries = make([]*entry, len(p.all))
+	copy(entries, p.all)
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, e := range entries {
+		checkURL, found := p.checkURL[e.Type]
+		if !found {
+			continue
+		}
+		wg.Add(1)
+		go func(e *entry, checkURL string) {
+			defer wg.Done()
+			start := time.Now()
+			err := p.probe(e.Proxy, checkURL)
+			p.checkResult(e, err, time.Since(start))
+		}(e, checkURL)
+	}
+	wg.Wait()
+}
+
+func (p *Pool) probe(proxy *Proxy, checkURL string) error {
+	proxyURL, err := url.Parse(proxy.ProxyString())
+	if err != nil {
+		return err
+	}
+	client := &http.Client{
+		Timeout:   p.checkTimeout,
+		Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)},
+	}
+	defer client.CloseIdleConnections()
+	resp, err := client.Get(checkURL)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	// 5xx 通常是代理无法连接到目标网站
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("http code error %v", resp.StatusCode)
+	}
+	return nil
+}
+
+func (p *Pool) checkResult(e *entry, err error, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var now = time.Now()
+	log := logger.WithField("proxy", e.ProxyString())
+	e.lastCheck = now
+	e.lastCheckOK = err == nil
+	if err != nil {
+		// 还在移出期间时延长移出时间
+		p.evict(e, now, true)
+		// 只用于显示状态，移出结束后重新计数
+		e.failures++
+		e.lastFailure = now
+		log.WithField("until", e.evictedUntil).Debugf("代理连通性检查失败 %v", err)
+		return
+	}
+	e.latency = latency
+	// 被 Delete 移出的代理可能只是被某个网站限制，移出时间结束后才重新加入
+	if !e.available(now) && e.evictedByCheck {
+		e.evictedUntil = time.Time{}
+	}
+	if e.available(now) {
+		if e.evictCount > 0 {
+			log.Info("代理连通性检查恢复，重新加入代理池")
+		}
+		e.failures = 0
+		e.evictCount = 0
+	}
+}
+
+func (p *Pool) checkLoop() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.checkInterval)
+	defer ticker.Stop()
+	p.CheckNow()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.CheckNow()
+		}
+	}
 }
 
 func (p *Pool) Stop() error {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	p.wg.Wait()
 	return nil
 }
 
-func NewLocalPool(proxies []*Proxy) *Pool {
+func NewLocalPool(proxies []*Proxy, opts ...Option) *Pool {
 	pool := &Pool{
-		proxies: make(map[proxy_pool.Prefer][]*Proxy),
-		cnt:     make(map[proxy_pool.Prefer]*atomic.Uint32),
-		total:   len(proxies),
+		proxies:       make(map[proxy_pool.Prefer][]*entry),
+		stickyMap:     make(map[string]*entry),
+		failThreshold: defaultFailThreshold,
+		failWindow:    defaultFailWindow,
+		evictBase:     defaultEvictBase,
+		evictMax:      defaultEvictMax,
+		checkURL:      make(map[proxy_pool.Prefer]string),
+		checkTimeout:  defaultCheckTimeout,
+		stop:          make(chan struct{}),
+	}
+	for prefer, u := range DefaultCheckURL {
+		pool.checkURL[prefer] = u
+	}
+	for _, opt := range opts {
+		opt(pool)
 	}
 	for _, proxy := range proxies {
-		if _, found := pool.proxies[proxy.Type]; !found {
-			pool.proxies[proxy.Type] = make([]*Proxy, 0)
-			pool.cnt[proxy.Type] = atomic.NewUint32(0)
-		}
-		pool.proxies[proxy.Type] = append(pool.proxies[proxy.Type], proxy)
+		e := &entry{Proxy: proxy}
+		pool.proxies[proxy.Type] = append(pool.proxies[proxy.Type], e)
+		pool.all = append(pool.all, e)
+	}
+	if pool.checkInterval > 0 && len(pool.all) > 0 {
+		pool.wg.Add(1)
+		go pool.checkLoop()
 	}
 	return pool
 }
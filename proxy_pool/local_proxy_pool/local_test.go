@@ -3,7 +3,11 @@ package local_proxy_pool
 import (
 	"github.com/cnxysoft/DDBOT-WSa/proxy_pool"
 	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestProxy(t *testing.T) {
@@ -64,3 +68,164 @@ func TestProxyPool(t *testing.T) {
 	pool.Delete(proxy.ProxyString())
 	pool.Stop()
 }
+
+func TestParseProxy(t *testing.T) {
+	p := ParseProxy("127.0.0.1:9999#3", proxy_pool.PreferOversea)
+	assert.Equal(t, "http://127.0.0.1:9999", p.ProxyString())
+	assert.Equal(t, 3, p.Weight)
+	assert.Equal(t, proxy_pool.PreferOversea, p.Prefer())
+
+	p = ParseProxy(" socks5://localhost:1080 ", proxy_pool.PreferMainland)
+	assert.Equal(t, "socks5://localhost:1080", p.ProxyString())
+	assert.Equal(t, 0, p.Weight)
+}
+
+func TestProxyPoolWeight(t *testing.T) {
+	pool := NewLocalPool([]*Proxy{
+		{Proxy: "a", Type: proxy_pool.PreferOversea, Weight: 3},
+		{Proxy: "b", Type: proxy_pool.PreferOversea},
+	})
+	defer pool.Stop()
+
+	var count = make(map[string]int)
+	for i := 0; i < 8; i++ {
+		proxy, err := pool.Get(proxy_pool.PreferOversea)
+		assert.Nil(t, err)
+		count[proxy.ProxyString()]++
+	}
+	assert.Equal(t, 6, count["http://a"])
+	assert.Equal(t, 2, count["http://b"])
+}
+
+func TestProxyPoolEvict(t *testing.T) {
+	pool := NewLocalPool([]*Proxy{
+		{Proxy: "a", Type: proxy_pool.PreferOversea},
+		{Proxy: "b", Type: proxy_pool.PreferOversea},
+	}, WithEvict(2, time.Millisecond*50, time.Second))
+	defer pool.Stop()
+
+	assert.False(t, pool.Delete("http://c"))
+	assert.True(t, pool.Delete("http://a"))
+	assert.True(t, pool.Status()[0].Available)
+	assert.True(t, pool.Delete("http://a"))
+	assert.False(t, pool.Status()[0].Available)
+
+	for i := 0; i < 4; i++ {
+		proxy, err := pool.Get(proxy_pool.PreferAny)
+		assert.Nil(t, err)
+		assert.Equal(t, "http://b", proxy.ProxyString())
+	}
+
+	pool.Delete("http://b")
+	pool.Delete("http://b")
+	_, err := pool.Get(proxy_pool.PreferOversea)
+	assert.Equal(t, ErrNoAvailableProxy, err)
+
+	assert.Eventually(t, func() bool {
+		_, err := pool.Get(proxy_pool.PreferOversea)
+		return err == nil
+	}, time.Second, time.Millisecond*10)
+}
+
+func TestProxyPoolEvictReadmit(t *testing.T) {
+	pool := NewLocalPool([]*Proxy{
+		{Proxy: "a", Type: proxy_pool.PreferOversea},
+	}, WithEvict(2, time.Millisecond*50, time.Second))
+	defer pool.Stop()
+
+	pool.Delete("http://a")
+	pool.Delete("http://a")
+	assert.False(t, pool.Status()[0].Available)
+	// 移出期间报告的失败不计数
+	pool.Delete("http://a")
+	assert.Equal(t, 0, pool.Status()[0].Failures)
+
+	assert.Eventually(t, func() bool {
+		return pool.Status()[0].Available
+	}, time.Second, time.Millisecond*10)
+	// 重新加入后只失败一次不会再次移出
+	pool.Delete("http://a")
+	assert.True(t, pool.Status()[0].Available)
+	assert.Equal(t, 1, pool.Status()[0].Failures)
+	pool.Delete("http://a")
+	assert.False(t, pool.Status()[0].Available)
+}
+
+func TestProxyPoolFailWindow(t *testing.T) {
+	pool := NewLocalPool([]*Proxy{
+		{Proxy: "a", Type: proxy_pool.PreferOversea},
+	}, WithEvict(2, time.Minute, 0), WithFailWindow(time.Millisecond*50))
+	defer pool.Stop()
+
+	pool.Delete("http://a")
+	time.Sleep(time.Millisecond * 100)
+	// 间隔超过 failWindow 的失败不算连续失败
+	pool.Delete("http://a")
+	assert.True(t, pool.Status()[0].Available)
+	assert.Equal(t, 1, pool.Status()[0].Failures)
+	pool.Delete("http://a")
+	assert.False(t, pool.Status()[0].Available)
+}
+
+func TestProxyPoolSticky(t *testing.T) {
+	pool := NewLocalPool([]*Proxy{
+		{Proxy: "a", Type: proxy_pool.PreferOversea},
+		{Proxy: "b", Type: proxy_pool.PreferOversea},
+	}, WithSticky(true), WithEvict(1, time.Minute, 0))
+	defer pool.Stop()
+
+	first, err := pool.GetSticky(proxy_pool.PreferOversea, "twitter.com")
+	assert.Nil(t, err)
+	for i := 0; i < 4; i++ {
+		proxy, err := pool.GetSticky(proxy_pool.PreferOversea, "twitter.com")
+		assert.Nil(t, err)
+		assert.Equal(t, first.ProxyString(), proxy.ProxyString())
+	}
+
+	pool.Delete(first.ProxyString())
+	proxy, err := pool.GetSticky(proxy_pool.PreferOversea, "twitter.com")
+	assert.Nil(t, err)
+	assert.NotEqual(t, first.ProxyString(), proxy.ProxyString())
+}
+
+func TestProxyPoolHealthCheck(t *testing.T) {
+	var fail atomic.Bool
+	// 作为 http 代理时收到的是完整的目标地址
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxyServer.Close()
+
+	pool := NewLocalPool([]*Proxy{
+		{Proxy: proxyServer.URL, Type: proxy_pool.PreferOversea},
+	}, WithHealthCheck(0, map[proxy_pool.Prefer]string{
+		proxy_pool.PreferOversea: "http://example.com/generate_204",
+	}))
+	defer pool.Stop()
+
+	pool.CheckNow()
+	status := pool.Status()[0]
+	assert.True(t, status.Available)
+	assert.True(t, status.LastCheckOK)
+
+	fail.Store(true)
+	pool.CheckNow()
+	status = pool.Status()[0]
+	assert.False(t, status.Available)
+	assert.False(t, status.LastCheckOK)
+	_, err := pool.Get(proxy_pool.PreferOversea)
+	assert.NotNil(t, err)
+
+	// 检查恢复后立即重新加入
+	fail.Store(false)
+	pool.CheckNow()
+	status = pool.Status()[0]
+	assert.True(t, status.Available)
+	assert.Equal(t, 0, status.Failures)
+	_, err = pool.Get(proxy_pool.PreferOversea)
+	assert.Nil(t, err)
+}
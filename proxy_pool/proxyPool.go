@@ -1,6 +1,9 @@
 package proxy_pool
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var ErrNil = errors.New("<nil>")
 
@@ -13,6 +16,21 @@ const (
 	PreferNone
 )
 
+func (p Prefer) String() string {
+	switch p {
+	case PreferAny:
+		return "any"
+	case PreferMainland:
+		return "mainland"
+	case PreferOversea:
+		return "oversea"
+	case PreferNone:
+		return "none"
+	default:
+		return "unknown"
+	}
+}
+
 type IProxyPool interface {
 	Get(Prefer) (IProxy, error)
 	Delete(string) bool
@@ -23,6 +41,30 @@ type IProxy interface {
 	ProxyString() string
 }
 
+// IStickyProxyPool 支持同一个key尽量使用同一个代理的代理池
+type IStickyProxyPool interface {
+	GetSticky(prefer Prefer, key string) (IProxy, error)
+}
+
+// ProxyStatus 代理的健康状态
+type ProxyStatus struct {
+	Proxy     string
+	Prefer    Prefer
+	Weight    int
+	Available bool
+	// Failures 连续失败的次数，包括 Delete 报告的失败和连通性检查失败
+	Failures     int
+	EvictedUntil time.Time
+	LastCheck    time.Time
+	LastCheckOK  bool
+	Latency      time.Duration
+}
+
+// IStatusProxyPool 可以报告代理状态的代理池
+type IStatusProxyPool interface {
+	Status() []*ProxyStatus
+}
+
 var proxyPool IProxyPool
 
 func Init(proxy IProxyPool) {
@@ -35,6 +77,26 @@ func Get(prefer Prefer) (IProxy, error) {
 	}
 	return proxyPool.Get(prefer)
 }
+
+// GetSticky 同一个key尽量返回同一个代理，代理池不支持时和 Get 相同
+func GetSticky(prefer Prefer, key string) (IProxy, error) {
+	if proxyPool == nil {
+		return nil, ErrNil
+	}
+	if sticky, ok := proxyPool.(IStickyProxyPool); ok && key != "" {
+		return sticky.GetSticky(prefer, key)
+	}
+	return proxyPool.Get(prefer)
+}
+
+// Status 返回代理池中代理的状态，代理池不支持时返回 nil
+func Status() []*ProxyStatus {
+	if s, ok := proxyPool.(IStatusProxyPool); ok {
+		return s.Status()
+	}
+	return nil
+}
+
 func Delete(proxy string) bool {
 	if proxyPool == nil {
 		return false
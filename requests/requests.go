@@ -36,6 +36,7 @@ type option struct {
 	Cookies             []*http.Cookie
 	Header              gout.H
 	Proxy               string
+	ProxyPrefer         proxy_pool.Prefer
 	HttpCode            *int
 	Retry               int
 	ProxyCallbackOption func(out interface{}, proxy string)
@@ -119,20 +120,13 @@ func AddRandomUAOption(entry FakeUAEntry) Option {
 	return HeaderOption("user-agent", RandomUA(entry))
 }
 
+// ProxyOption 使用代理池中的代理，在发起请求时才选择代理，同一个网站会尽量使用同一个代理
 func ProxyOption(prefer proxy_pool.Prefer) Option {
 	if prefer == proxy_pool.PreferNone {
 		return empty
 	}
-	proxy, err := proxy_pool.Get(prefer)
-	if err != nil {
-		if err != proxy_pool.ErrNil {
-			logger.Errorf("get proxy failed: %v", err)
-		}
-		return empty
-	} else {
-		return func(o *option) {
-			o.Proxy = proxy.ProxyString()
-		}
+	return func(o *option) {
+		o.ProxyPrefer = prefer
 	}
 }
 
@@ -218,12 +212,23 @@ func Do(f func(*gout.Client) *dataflow.DataFlow, out interface{}, options ...Opt
 	for _, o := range options {
 		o(opt)
 	}
+	var df = f(opt.getGout())
+	if len(opt.Proxy) == 0 && opt.ProxyPrefer != 0 {
+		host, _ := df.GetHost()
+		proxy, err := proxy_pool.GetSticky(opt.ProxyPrefer, host)
+		if err != nil {
+			if err != proxy_pool.ErrNil {
+				logger.Errorf("get proxy failed: %v", err)
+			}
+		} else {
+			opt.Proxy = proxy.ProxyString()
+		}
+	}
 	if opt.ProxyCallbackOption != nil && len(opt.Proxy) > 0 {
 		defer func() {
 			opt.ProxyCallbackOption(out, opt.Proxy)
 		}()
 	}
-	var df = f(opt.getGout())
 	if opt.Debug {
 		df.Debug(true)
 	}
@@ -1,16 +1,22 @@
 package utils
 
 import (
+	"errors"
+
 	"github.com/Mrs4s/MiraiGo/client"
+	"github.com/Mrs4s/MiraiGo/message"
 	miraiBot "github.com/Sora233/MiraiGo-Template/bot"
 )
 
+var errBotNotAvailable = errors.New("bot not available")
+
 // HackedBot 拦截一些方法方便测试
 type HackedBot struct {
-	Bot        **miraiBot.Bot
-	testGroups []*client.GroupInfo
-	testUin    int64
-	testMode   bool
+	Bot         **miraiBot.Bot
+	testGroups  []*client.GroupInfo
+	testFriends []*client.FriendInfo
+	testUin     int64
+	testMode    bool
 }
 
 func (h *HackedBot) valid() bool {
@@ -23,6 +29,11 @@ func (h *HackedBot) valid() bool {
 
 func (h *HackedBot) FindFriend(uin int64) *client.FriendInfo {
 	if !h.valid() {
+		for _, fi := range h.testFriends {
+			if fi.Uin == uin {
+				return fi
+			}
+		}
 		return nil
 	}
 	return (*h.Bot).FindFriend(uin)
@@ -63,11 +74,67 @@ func (h *HackedBot) GetGroupList() []*client.GroupInfo {
 
 func (h *HackedBot) GetFriendList() []*client.FriendInfo {
 	if !h.valid() {
-		return nil
+		return h.testFriends
 	}
 	return (*h.Bot).FriendList
 }
 
+// GetGroupMessageHistory 从协议端获取群历史消息
+func (h *HackedBot) GetGroupMessageHistory(groupCode int64, messageSeq int64, count int) ([]*message.GroupMessage, error) {
+	if !h.valid() {
+		return nil, errBotNotAvailable
+	}
+	return (*h.Bot).GetGroupMessageHistory(groupCode, messageSeq, count)
+}
+
+// GetMsg 通过消息id查询消息，返回 *message.GroupMessage 或者 *message.PrivateMessage
+func (h *HackedBot) GetMsg(msgId int32) (interface{}, error) {
+	if !h.valid() {
+		return nil, errBotNotAvailable
+	}
+	return (*h.Bot).GetMsg(msgId)
+}
+
+// GetFileUrl 查询群文件的下载链接，失败时返回空字符串
+func (h *HackedBot) GetFileUrl(groupCode int64, fileId string) string {
+	if !h.valid() {
+		return ""
+	}
+	return (*h.Bot).GetFileUrl(groupCode, fileId)
+}
+
+// DownloadFile 让协议端下载文件，返回协议端上的文件路径，headers 为下载url时使用的请求头
+func (h *HackedBot) DownloadFile(url, base64, name string, headers ...string) (string, error) {
+	if !h.valid() {
+		return "", errBotNotAvailable
+	}
+	return (*h.Bot).DownloadFile(url, base64, name, headers)
+}
+
+// EnsureGroupFolder 返回群文件根目录下指定名称的文件夹id，不存在时创建
+func (h *HackedBot) EnsureGroupFolder(groupCode int64, name string) (string, error) {
+	if !h.valid() {
+		return "", errBotNotAvailable
+	}
+	return (*h.Bot).EnsureGroupFolder(groupCode, name)
+}
+
+// UploadGroupFile 上传群文件，file 为协议端上的文件路径
+func (h *HackedBot) UploadGroupFile(groupCode int64, file, name, folderId string) (string, error) {
+	if !h.valid() {
+		return "", errBotNotAvailable
+	}
+	return (*h.Bot).UploadGroupFile(groupCode, file, name, folderId)
+}
+
+// RecallMsg 撤回消息
+func (h *HackedBot) RecallMsg(msgId int32) error {
+	if !h.valid() {
+		return errBotNotAvailable
+	}
+	return (*h.Bot).RecallMsg(msgId)
+}
+
 func (h *HackedBot) IsOnline() bool {
 	return h.valid()
 }
@@ -103,6 +170,16 @@ func (h *HackedBot) TESTAddGroup(groupCode int64) {
 	})
 }
 
+// TESTAddFriend 仅可用于测试
+func (h *HackedBot) TESTAddFriend(uin int64) {
+	if h.FindFriend(uin) != nil {
+		return
+	}
+	h.testFriends = append(h.testFriends, &client.FriendInfo{
+		Uin: uin,
+	})
+}
+
 // TESTAddMember 仅可用于测试
 func (h *HackedBot) TESTAddMember(groupCode int64, uin int64, permission client.MemberPermission) {
 	h.TESTAddGroup(groupCode)
@@ -126,6 +203,7 @@ func (h *HackedBot) TESTAddMember(groupCode int64, uin int64, permission client.
 // TESTReset 仅可用于测试
 func (h *HackedBot) TESTReset() {
 	h.testGroups = nil
+	h.testFriends = nil
 	h.testUin = 0
 	h.testMode = false
 }
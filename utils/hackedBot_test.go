@@ -29,8 +29,13 @@ func TestGetBot(t *testing.T) {
 	bot.TESTAddMember(test.G1, test.UID1, client.Administrator)
 	bot.TESTAddMember(test.G2, test.UID2, client.Administrator)
 	assert.Len(t, bot.GetGroupList(), 3)
+	bot.TESTAddFriend(test.UID1)
+	bot.TESTAddFriend(test.UID1)
+	assert.Len(t, bot.GetFriendList(), 1)
+	assert.NotNil(t, bot.FindFriend(test.UID1))
 	bot.TESTReset()
 	assert.Empty(t, bot.GetGroupList())
+	assert.Empty(t, bot.GetFriendList())
 
 	test.InitMirai()
 	defer test.CloseMirai()